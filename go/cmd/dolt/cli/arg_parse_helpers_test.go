@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
 )
 
@@ -86,3 +87,19 @@ func TestParseAuthor(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessBackupArgsS3Endpoint(t *testing.T) {
+	apr, err := CreateBackupArgParser().Parse([]string{"--s3-endpoint", "http://localhost:9000", "--aws-region", "us-west-2"})
+	require.NoError(t, err)
+
+	params, err := ProcessBackupArgs(apr, dbfactory.S3Scheme, "s3://bucket/db")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{dbfactory.S3EndpointParam: "http://localhost:9000", dbfactory.AWSRegionParam: "us-west-2"}, params)
+
+	// aws remotes keep their manifest in DynamoDB, and can't use another endpoint
+	_, err = ProcessBackupArgs(apr, dbfactory.AWSScheme, "aws://[table:bucket]/db")
+	assert.ErrorContains(t, err, dbfactory.S3EndpointParam)
+
+	_, err = ProcessBackupArgs(apr, "file", "file:///tmp/db")
+	assert.Error(t, err)
+}
@@ -81,10 +81,7 @@ func CreateConflictsResolveArgParser() *argparser.ArgParser {
 }
 
 func CreateMergeArgParser() *argparser.ArgParser {
-	ap := argparser.NewArgParserWithMaxArgs("merge", 1)
-	ap.TooManyArgsErrorFunc = func(receivedArgs []string) error {
-		return errors.New("Error: Dolt does not support merging from multiple commits. You probably meant to checkout one and then merge from the other.")
-	}
+	ap := argparser.NewArgParserWithVariableArgs("merge")
 	ap.SupportsFlag(NoFFParam, "", "Create a merge commit even when the merge resolves as a fast-forward.")
 	ap.SupportsFlag(SquashParam, "", "Merge changes to the working set without updating the commit history")
 	ap.SupportsString(MessageArg, "m", "msg", "Use the given {{.LessThan}}msg{{.GreaterThan}} as the commit message.")
@@ -93,6 +90,7 @@ func CreateMergeArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(NoCommitFlag, "", "Perform the merge and stop just before creating a merge commit. Note this will not prevent a fast-forward merge; use the --no-ff arg together with the --no-commit arg to prevent both fast-forwards and merge commits.")
 	ap.SupportsFlag(NoEditFlag, "", "Use an auto-generated commit message when creating a merge commit. The default for interactive CLI sessions is to open an editor.")
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	ap.SupportsString(ColumnDefaultParam, "", "table.column=expression", "Populate the existing rows of the current branch for a column added on the merged branch (or the other way around) by evaluating {{.LessThan}}expression{{.GreaterThan}} for each row. This allows merging new NOT NULL columns that have no default value. Separate several assignments with commas.")
 
 	return ap
 }
@@ -106,6 +104,8 @@ func CreateRebaseArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(AbortParam, "", "Abort an interactive rebase and return the working set to the pre-rebase state")
 	ap.SupportsFlag(ContinueFlag, "", "Continue an interactive rebase after adjusting the rebase plan")
 	ap.SupportsFlag(InteractiveFlag, "i", "Start an interactive rebase")
+	ap.SupportsString(OntoParam, "", "newbase", "Replay the commits onto {{.LessThan}}newbase{{.GreaterThan}} instead of onto the upstream branch")
+	ap.SupportsFlag(AutosquashFlag, "", "Move commits whose message starts with \"fixup! \" or \"squash! \" after the commit they name, and fix them up or squash them into it")
 	return ap
 }
 
@@ -137,10 +137,14 @@ func CreateCloneArgParser() *argparser.ArgParser {
 	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, dbfactory.AWSCredTypes))
 	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file.")
 	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use.")
+	ap.SupportsString(dbfactory.S3EndpointParam, "", "url", "Endpoint of the S3 compatible store holding an s3 remote.")
 	ap.SupportsString(dbfactory.OSSCredsFileParam, "", "file", "OSS credentials file.")
 	ap.SupportsString(dbfactory.OSSCredsProfile, "", "profile", "OSS profile to use.")
+	ap.SupportsString(dbfactory.AzureCredsFileParam, "", "file", "Azure credentials file.")
+	ap.SupportsString(dbfactory.AzureCredsProfile, "", "profile", "Azure profile to use.")
 	ap.SupportsString(UserFlag, "u", "user", "User name to use when authenticating with the remote. Gets password from the environment variable {{.EmphasisLeft}}DOLT_REMOTE_PASSWORD{{.EmphasisRight}}.")
 	ap.SupportsFlag(SingleBranchFlag, "", "Clone only the history leading to the tip of a single branch, either specified by --branch or the remote's HEAD (default).")
+	ap.SupportsFlag(MaskedFlag, "", "Clone a single branch and replace its history with one commit in which the columns listed in {{.EmphasisLeft}}dolt_column_masks{{.EmphasisRight}} are masked. The clone has no remote and keeps none of the raw data.")
 	return ap
 }
 
@@ -172,14 +176,18 @@ func CreateCheckoutArgParser() *argparser.ArgParser {
 }
 
 func CreateCherryPickArgParser() *argparser.ArgParser {
-	ap := argparser.NewArgParserWithMaxArgs("cherrypick", 1)
+	ap := argparser.NewArgParserWithVariableArgs("cherrypick")
 	ap.SupportsFlag(AbortParam, "", "Abort the current conflict resolution process, and revert all changes from the in-process cherry-pick operation.")
+	ap.SupportsFlag(ContinueFlag, "", "Continue the cherry-pick in progress after resolving the conflicts of the commit it stopped at, "+
+		"committing the resolved changes and cherry-picking the remaining commits.")
 	ap.SupportsFlag(AllowEmptyFlag, "", "Allow empty commits to be cherry-picked. "+
 		"Note that use of this option only keeps commits that were initially empty. "+
 		"Commits which become empty, due to a previous commit, will cause cherry-pick to fail.")
-	ap.TooManyArgsErrorFunc = func(receivedArgs []string) error {
-		return errors.New("cherry-picking multiple commits is not supported yet.")
-	}
+	ap.SupportsInt(MainlineParam, "m", "parent-number", "Cherry-pick merge commits by applying their changes relative to the "+
+		"parent with the given number, starting from 1.")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"commit",
+		"The commits to cherry-pick. A range of commits may be given as {{.LessThan}}from{{.GreaterThan}}..{{.LessThan}}to{{.GreaterThan}}, " +
+			"which cherry-picks the commits reachable from {{.LessThan}}to{{.GreaterThan}} but not from {{.LessThan}}from{{.GreaterThan}}, oldest first."})
 	return ap
 }
 
@@ -194,6 +202,11 @@ func CreateFetchArgParser() *argparser.ArgParser {
 func CreateRevertArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithVariableArgs("revert")
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	ap.SupportsInt(MainlineParam, "m", "parent-number", "Revert merge commits relative to the parent with the given number, "+
+		"starting from 1. Defaults to the first parent.")
+	ap.SupportsFlag(ContinueFlag, "", "Continue the revert in progress after resolving the conflicts of the commit it stopped at.")
+	ap.SupportsFlag(SkipFlag, "", "Continue the revert in progress without reverting the commit it stopped at.")
+	ap.SupportsFlag(AbortParam, "", "Abort the revert in progress, and restore the working set from before the revert started.")
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"revision",
 		"The commit revisions. If multiple revisions are given, they're applied in the order given."})
 
@@ -247,6 +260,71 @@ func CreateTagArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(VerboseFlag, "v", "list tags along with their metadata.")
 	ap.SupportsFlag(DeleteFlag, "d", "Delete a tag.")
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	ap.SupportsFlag(SignTagFlag, "s", "Sign the tag with the key taken from 'signingkey' in the configuration, using the format in 'gpgformat'.")
+	ap.SupportsString(LocalUserParam, "u", "key-id", "Sign the tag with the given key.")
+	return ap
+}
+
+func CreateVerifyCommitArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("verify-commit")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"commit", "The commits whose signatures are verified."})
+	return ap
+}
+
+func CreateVerifyTagArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("verify-tag")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"tag", "The tags whose signatures are verified."})
+	return ap
+}
+
+func CreateMaterializedViewArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("create_materialized_view", 2)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"name", "The name of the materialized view."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"select", "The SELECT statement defining the materialized view."})
+	ap.SupportsFlag(AutoRefreshFlag, "", "Refresh the materialized view every time changes are committed.")
+	return ap
+}
+
+func CreateRefreshMaterializedViewArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("refresh_materialized_view")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"name", "The materialized views to refresh."})
+	ap.SupportsFlag(AllFlag, "a", "Refresh every materialized view.")
+	return ap
+}
+
+func CreatePolicyArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("create_policy", 2)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"name", "The name of the policy."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"table", "The table the policy applies to."})
+	ap.SupportsString(ForParam, "", "command", "The statements the policy applies to: all (the default), select, insert, update or delete.")
+	ap.SupportsString(UserFlag, "", "user", "The users the policy applies to, as a pattern. Defaults to all users.")
+	ap.SupportsString(HostFlag, "", "host", "The hosts the policy applies to, as a pattern. Defaults to all hosts.")
+	ap.SupportsString(BranchParam, "", "branch", "The branches the policy applies to, as a pattern. Defaults to all branches.")
+	ap.SupportsString(UsingParam, "", "expression", "The condition existing rows must meet to be read, updated or deleted.")
+	ap.SupportsString(CheckParam, "", "expression", "The condition inserted and updated rows must meet. Defaults to the --using condition.")
+	return ap
+}
+
+func CreateColumnMaskArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("create_column_mask", 2)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"table", "The table of the masked column."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"column", "The masked column."})
+	ap.SupportsString(MaskTypeParam, "", "mask", "How values are masked: redact (the default), hash or partial.")
+	ap.SupportsInt(VisiblePrefixParam, "", "chars", "The number of leading characters a partial mask leaves visible. Defaults to 0.")
+	ap.SupportsInt(VisibleSuffixParam, "", "chars", "The number of trailing characters a partial mask leaves visible. Defaults to 0.")
+	return ap
+}
+
+func CreateCdcArgParser(isProcedure bool) *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("cdc", 0)
+	ap.SupportsString(BranchParam, "b", "branch", "The branch to follow. Defaults to the current branch.")
+	ap.SupportsString(SinceParam, "", "commit", "Only emit changes made after this commit. Ignored if a checkpoint has been stored.")
+	if !isProcedure {
+		ap.SupportsString(CheckpointParam, "", "file", "A file recording the last commit whose changes were emitted. The stream resumes after it and updates it as it goes.")
+		ap.SupportsString(SinkParam, "", "url", "Where to write change events: '-' for stdout (the default), a file path, or a URL handled by a registered sink.")
+		ap.SupportsFlag(FollowFlag, "f", "Keep running and emit the changes of new commits as they are made.")
+		ap.SupportsString(IntervalParam, "", "duration", "How often to check for new commits with --follow. Defaults to 1s.")
+	}
 	return ap
 }
 
@@ -261,6 +339,9 @@ func CreateBackupArgParser() *argparser.ArgParser {
 	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, dbfactory.AWSCredTypes))
 	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file")
 	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use")
+	ap.SupportsString(dbfactory.S3EndpointParam, "", "url", "Endpoint of the S3 compatible store holding an s3 backup")
+	ap.SupportsString(dbfactory.AzureCredsFileParam, "", "file", "Azure credentials file")
+	ap.SupportsString(dbfactory.AzureCredsProfile, "", "profile", "Azure profile to use")
 	return ap
 }
 
@@ -291,6 +372,13 @@ func CreateLogArgParser(isTableFunction bool) *argparser.ArgParser {
 	return ap
 }
 
+func CreateMergePreviewArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("merge_preview", 2)
+	ap.SupportsFlag(DetailFlag, "", "List each row that the merge would change, and each conflict and constraint violation it would record, instead of counts per table.")
+	ap.SupportsString(ColumnDefaultParam, "", "table.column=expression", "Populate the existing rows for a column added on the other branch by evaluating {{.LessThan}}expression{{.GreaterThan}} for each row, as in dolt merge.")
+	return ap
+}
+
 func CreateGCArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs("gc", 0)
 	ap.SupportsFlag(ShallowFlag, "s", "perform a fast, but incomplete garbage collection pass")
@@ -332,6 +420,8 @@ func CreateGlobalArgParser(name string) *argparser.ArgParser {
 
 var awsParams = []string{dbfactory.AWSRegionParam, dbfactory.AWSCredsTypeParam, dbfactory.AWSCredsFileParam, dbfactory.AWSCredsProfile}
 var ossParams = []string{dbfactory.OSSCredsFileParam, dbfactory.OSSCredsProfile}
+var s3Params = append([]string{dbfactory.S3EndpointParam}, awsParams...)
+var azureParams = []string{dbfactory.AzureCredsFileParam, dbfactory.AzureCredsProfile}
 
 func ProcessBackupArgs(apr *argparser.ArgParseResults, scheme, backupUrl string) (map[string]string, error) {
 	params := map[string]string{}
@@ -342,6 +432,10 @@ func ProcessBackupArgs(apr *argparser.ArgParseResults, scheme, backupUrl string)
 		err = AddAWSParams(backupUrl, apr, params)
 	case dbfactory.OSSScheme:
 		err = AddOSSParams(backupUrl, apr, params)
+	case dbfactory.S3Scheme:
+		err = AddS3Params(backupUrl, apr, params)
+	case dbfactory.AzureScheme:
+		err = AddAzureParams(backupUrl, apr, params)
 	default:
 		err = VerifyNoAwsParams(apr)
 	}
@@ -359,6 +453,11 @@ func AddAWSParams(remoteUrl string, apr *argparser.ArgParseResults, params map[s
 		}
 	}
 
+	// aws remotes keep their manifest in DynamoDB, so they only work with AWS S3
+	if _, ok := apr.GetValue(dbfactory.S3EndpointParam); ok {
+		return fmt.Errorf("%s param is only valid for s3 cloud remotes in the format s3://s3-bucket/database", dbfactory.S3EndpointParam)
+	}
+
 	for _, p := range awsParams {
 		if val, ok := apr.GetValue(p); ok {
 			params[p] = val
@@ -368,6 +467,24 @@ func AddAWSParams(remoteUrl string, apr *argparser.ArgParseResults, params map[s
 	return nil
 }
 
+func AddS3Params(remoteUrl string, apr *argparser.ArgParseResults, params map[string]string) error {
+	isS3 := strings.HasPrefix(remoteUrl, "s3")
+
+	if !isS3 {
+		if _, ok := apr.GetValue(dbfactory.S3EndpointParam); ok {
+			return fmt.Errorf("%s param is only valid for s3 cloud remotes in the format s3://s3-bucket/database", dbfactory.S3EndpointParam)
+		}
+	}
+
+	for _, p := range s3Params {
+		if val, ok := apr.GetValue(p); ok {
+			params[p] = val
+		}
+	}
+
+	return nil
+}
+
 func AddOSSParams(remoteUrl string, apr *argparser.ArgParseResults, params map[string]string) error {
 	isOSS := strings.HasPrefix(remoteUrl, "oss")
 
@@ -388,8 +505,28 @@ func AddOSSParams(remoteUrl string, apr *argparser.ArgParseResults, params map[s
 	return nil
 }
 
+func AddAzureParams(remoteUrl string, apr *argparser.ArgParseResults, params map[string]string) error {
+	isAzure := strings.HasPrefix(remoteUrl, "azure")
+
+	if !isAzure {
+		for _, p := range azureParams {
+			if _, ok := apr.GetValue(p); ok {
+				return fmt.Errorf("%s param is only valid for azure cloud remotes in the format azure://container/database", p)
+			}
+		}
+	}
+
+	for _, p := range azureParams {
+		if val, ok := apr.GetValue(p); ok {
+			params[p] = val
+		}
+	}
+
+	return nil
+}
+
 func VerifyNoAwsParams(apr *argparser.ArgParseResults) error {
-	if awsParams := apr.GetValues(awsParams...); len(awsParams) > 0 {
+	if awsParams := apr.GetValues(s3Params...); len(awsParams) > 0 {
 		awsParamKeys := make([]string, 0, len(awsParams))
 		for k := range awsParams {
 			awsParamKeys = append(awsParamKeys, k)
@@ -22,9 +22,14 @@ const (
 	AllowEmptyFlag       = "allow-empty"
 	AmendFlag            = "amend"
 	AuthorParam          = "author"
+	AutoRefreshFlag      = "auto-refresh"
+	AutosquashFlag       = "autosquash"
 	BranchParam          = "branch"
 	CachedFlag           = "cached"
 	CheckoutCreateBranch = "b"
+	CheckParam           = "check"
+	CheckpointParam      = "checkpoint"
+	ColumnDefaultParam   = "column-default"
 	CreateResetBranch    = "B"
 	CommitFlag           = "commit"
 	ContinueFlag         = "continue"
@@ -34,15 +39,23 @@ const (
 	DeleteFlag           = "delete"
 	DeleteForceFlag      = "D"
 	DepthFlag            = "depth"
+	DetailFlag           = "detail"
 	DryRunFlag           = "dry-run"
 	EmptyParam           = "empty"
+	FollowFlag           = "follow"
+	ForParam             = "for"
 	ForceFlag            = "force"
 	FullFlag             = "full"
 	GraphFlag            = "graph"
 	HardResetParam       = "hard"
 	HostFlag             = "host"
 	InteractiveFlag      = "interactive"
+	IntervalParam        = "interval"
 	ListFlag             = "list"
+	LocalUserParam       = "local-user"
+	MainlineParam        = "mainline"
+	MaskedFlag           = "masked"
+	MaskTypeParam        = "type"
 	MergesFlag           = "merges"
 	MessageArg           = "message"
 	MinParentsFlag       = "min-parents"
@@ -56,6 +69,7 @@ const (
 	NotFlag              = "not"
 	NumberFlag           = "number"
 	OneLineFlag          = "oneline"
+	OntoParam            = "onto"
 	OursFlag             = "ours"
 	OutputOnlyFlag       = "output-only"
 	ParentsFlag          = "parents"
@@ -70,8 +84,12 @@ const (
 	ShowIgnoredFlag      = "ignored"
 	ShowSignatureFlag    = "show-signature"
 	SignFlag             = "gpg-sign"
+	SignTagFlag          = "sign"
 	SilentFlag           = "silent"
+	SinceParam           = "since"
 	SingleBranchFlag     = "single-branch"
+	SinkParam            = "sink"
+	SkipFlag             = "skip"
 	SkipEmptyFlag        = "skip-empty"
 	SoftResetParam       = "soft"
 	SquashParam          = "squash"
@@ -83,4 +101,7 @@ const (
 	TrackFlag            = "track"
 	UpperCaseAllFlag     = "ALL"
 	UserFlag             = "user"
+	UsingParam           = "using"
+	VisiblePrefixParam   = "visible-prefix"
+	VisibleSuffixParam   = "visible-suffix"
 )
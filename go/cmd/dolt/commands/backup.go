@@ -37,7 +37,7 @@ var backupDocs = cli.CommandDocumentationContent{
 
 {{.EmphasisLeft}}add{{.EmphasisRight}}
 Adds a backup named {{.LessThan}}name{{.GreaterThan}} for the database at {{.LessThan}}url{{.GreaterThan}}.
-The {{.LessThan}}url{{.GreaterThan}} parameter supports url schemes of http, https, aws, gs, and file. The url prefix defaults to https. If the {{.LessThan}}url{{.GreaterThan}} parameter is in the format {{.EmphasisLeft}}<organization>/<repository>{{.EmphasisRight}} then dolt will use the {{.EmphasisLeft}}backups.default_host{{.EmphasisRight}} from your configuration file (Which will be dolthub.com unless changed).
+The {{.LessThan}}url{{.GreaterThan}} parameter supports url schemes of http, https, aws, s3, gs, azure, and file. The url prefix defaults to https. If the {{.LessThan}}url{{.GreaterThan}} parameter is in the format {{.EmphasisLeft}}<organization>/<repository>{{.EmphasisRight}} then dolt will use the {{.EmphasisLeft}}backups.default_host{{.EmphasisRight}} from your configuration file (Which will be dolthub.com unless changed).
 The URL address must be unique to existing remotes and backups.
 
 AWS cloud backup urls should be of the form {{.EmphasisLeft}}aws://[dynamo-table:s3-bucket]/database{{.EmphasisRight}}. You may configure your aws cloud backup using the optional parameters {{.EmphasisLeft}}aws-region{{.EmphasisRight}}, {{.EmphasisLeft}}aws-creds-type{{.EmphasisRight}}, {{.EmphasisLeft}}aws-creds-file{{.EmphasisRight}}.
@@ -49,8 +49,12 @@ aws-creds-type specifies the means by which credentials should be retrieved in o
 	file: Uses the credentials file specified by the parameter aws-creds-file
 
 	
+S3 backup urls should be of the form {{.EmphasisLeft}}s3://s3-bucket/database{{.EmphasisRight}}. Unlike aws backups they do not need a DynamoDB table, and they work with S3 compatible stores such as MinIO, Ceph and R2 which support conditional writes. They accept the aws-region, aws-creds-type, aws-creds-file and aws-creds-profile parameters, and {{.EmphasisLeft}}s3-endpoint{{.EmphasisRight}} sets the url of a store other than AWS S3.
+
 GCP backup urls should be of the form gs://gcs-bucket/database and will use the credentials setup using the gcloud command line available from Google.
 
+Azure backup urls should be of the form {{.EmphasisLeft}}azure://container/database{{.EmphasisRight}}. Credentials are read from the profile {{.EmphasisLeft}}azure-creds-profile{{.EmphasisRight}} (default 'default') of the JSON file {{.EmphasisLeft}}azure-creds-file{{.EmphasisRight}} (default ~/.azure/dolt_azure_credentials), which may set endpoint, accountName, accountKey and sasToken. Missing values are read from the environment variables AZURE_STORAGE_BLOB_ENDPOINT, AZURE_STORAGE_ACCOUNT, AZURE_STORAGE_KEY and AZURE_STORAGE_SAS_TOKEN.
+
 The local filesystem can be used as a backup by providing a repository url in the format file://absolute path. See https://en.wikipedia.org/wiki/File_URI_scheme
 
 {{.EmphasisLeft}}remove{{.EmphasisRight}}, {{.EmphasisLeft}}rm{{.EmphasisRight}}
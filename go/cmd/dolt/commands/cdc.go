@@ -0,0 +1,218 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var cdcDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Stream the row changes made on a branch.`,
+	LongDesc: `Emits a change event for every row inserted, updated or deleted by the commits on a branch, oldest commit first. Each commit is compared to its first parent, so the changes brought in by a merge are emitted once, as part of the merge commit.
+
+Events are written one per line in the JSON format of a Debezium change event payload: {{.EmphasisLeft}}before{{.EmphasisRight}} and {{.EmphasisLeft}}after{{.EmphasisRight}} hold the row before and after the change, {{.EmphasisLeft}}op{{.EmphasisRight}} is {{.EmphasisLeft}}c{{.EmphasisRight}}, {{.EmphasisLeft}}u{{.EmphasisRight}} or {{.EmphasisLeft}}d{{.EmphasisRight}}, {{.EmphasisLeft}}source{{.EmphasisRight}} names the database, table, branch and commit along with a hash of the table's schema, and {{.EmphasisLeft}}transaction{{.EmphasisRight}} orders the event within its commit. A table whose primary key changed is reported as all of its old rows being deleted and all of its new rows inserted.
+
+With {{.EmphasisLeft}}--checkpoint{{.EmphasisRight}}, the last commit whose events have been written is recorded in a file after each commit, and the next run resumes after it. Without a stored checkpoint the stream starts after the {{.EmphasisLeft}}--since{{.EmphasisRight}} commit, or at the beginning of the branch's history. With {{.EmphasisLeft}}--follow{{.EmphasisRight}}, the command keeps polling for new commits; when a sql-server is running, the events are read from it, so commits made through the server are picked up as they happen.
+
+SQL clients can read the same events with {{.EmphasisLeft}}CALL DOLT_CDC('--since', {{.LessThan}}commit{{.GreaterThan}}){{.EmphasisRight}}.`,
+	Synopsis: []string{
+		`[--branch {{.LessThan}}branch{{.GreaterThan}}] [--since {{.LessThan}}commit{{.GreaterThan}}] [--checkpoint {{.LessThan}}file{{.GreaterThan}}] [--sink {{.LessThan}}url{{.GreaterThan}}] [--follow [--interval {{.LessThan}}duration{{.GreaterThan}}]]`,
+	},
+}
+
+const defaultCdcInterval = time.Second
+
+type CdcCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd CdcCmd) Name() string {
+	return "cdc"
+}
+
+// Description returns a description of the command
+func (cmd CdcCmd) Description() string {
+	return cdcDocs.ShortDesc
+}
+
+func (cmd CdcCmd) Docs() *cli.CommandDocumentation {
+	return cli.NewCommandDocumentation(cdcDocs, cmd.ArgParser())
+}
+
+func (cmd CdcCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateCdcArgParser(false)
+}
+
+// EventType returns the type of the event to log
+func (cmd CdcCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd CdcCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	apr, usage, terminate, status := ParseArgsOrPrintHelp(ap, commandStr, args, cdcDocs)
+	if terminate {
+		return status
+	}
+
+	interval := defaultCdcInterval
+	if s, ok := apr.GetValue(cli.IntervalParam); ok {
+		if !apr.Contains(cli.FollowFlag) {
+			return HandleVErrAndExitCode(errhand.BuildDError("--%s requires --%s", cli.IntervalParam, cli.FollowFlag).Build(), usage)
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return HandleVErrAndExitCode(errhand.BuildDError("invalid --%s '%s'", cli.IntervalParam, s).Build(), usage)
+		}
+		interval = d
+	}
+
+	var checkpoint cdc.Checkpoint = cdc.NewMemoryCheckpoint(hash.Hash{})
+	if path, ok := apr.GetValue(cli.CheckpointParam); ok {
+		checkpoint = cdc.NewFileCheckpoint(path)
+	}
+
+	sinkUrl, _ := apr.GetValue(cli.SinkParam)
+	sink, err := cdc.OpenSink(ctx, sinkUrl, cli.CliOut)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	defer sink.Close()
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	// A --since revision such as HEAD is resolved once, so that following the branch doesn't move the start
+	_, stored, err := checkpoint.Load(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if since, ok := apr.GetValue(cli.SinceParam); ok && !stored {
+		rows, err := InterpolateAndRunQuery(queryist, sqlCtx, "select hashof(?)", since)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		if err = checkpoint.Save(ctx, hash.Parse(fmt.Sprint(rows[0][0]))); err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+	}
+
+	branch, hasBranch := apr.GetValue(cli.BranchParam)
+	for {
+		params := []interface{}{}
+		if hasBranch {
+			params = append(params, "--"+cli.BranchParam, branch)
+		}
+		h, ok, err := checkpoint.Load(ctx)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		if ok {
+			params = append(params, "--"+cli.SinceParam, h.String())
+		}
+
+		err = streamCdcEvents(sqlCtx, queryist, params, sink, checkpoint)
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0
+			}
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		if !apr.Contains(cli.FollowFlag) {
+			return 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-time.After(interval):
+		}
+	}
+}
+
+// streamCdcEvents calls dolt_cdc with |params| and writes the events it returns to |sink|. Each time the events of a
+// commit are complete they are flushed and the commit is saved to |checkpoint|.
+func streamCdcEvents(sqlCtx *sql.Context, queryist cli.Queryist, params []interface{}, sink cdc.Sink, checkpoint cdc.Checkpoint) error {
+	query, err := dbr.InterpolateForDialect("call dolt_cdc("+strings.TrimSuffix(strings.Repeat("?, ", len(params)), ", ")+")", params, dialect.MySQL)
+	if err != nil {
+		return err
+	}
+	_, rowIter, _, err := queryist.Query(sqlCtx, query)
+	if err != nil {
+		return err
+	}
+	defer rowIter.Close(sqlCtx)
+
+	var commit string
+	endCommit := func() error {
+		if commit == "" {
+			return nil
+		}
+		if err := sink.Flush(sqlCtx); err != nil {
+			return err
+		}
+		return checkpoint.Save(sqlCtx, hash.Parse(commit))
+	}
+
+	for {
+		row, err := rowIter.Next(sqlCtx)
+		if err == io.EOF {
+			return endCommit()
+		} else if err != nil {
+			return err
+		}
+
+		rowCommit, data := fmt.Sprint(row[0]), fmt.Sprint(row[3])
+		if rowCommit != commit {
+			if err = endCommit(); err != nil {
+				return err
+			}
+			commit = rowCommit
+		}
+
+		// Decode numbers as json.Number so they are written back out exactly as the server produced them
+		var ev cdc.Event
+		dec := json.NewDecoder(strings.NewReader(data))
+		dec.UseNumber()
+		if err = dec.Decode(&ev); err != nil {
+			return err
+		}
+		if err = sink.Emit(sqlCtx, ev); err != nil {
+			return err
+		}
+	}
+}
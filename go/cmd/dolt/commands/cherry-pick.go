@@ -34,20 +34,24 @@ import (
 var cherryPickDocs = cli.CommandDocumentationContent{
 	ShortDesc: `Apply the changes introduced by an existing commit.`,
 	LongDesc: `
-Applies the changes from an existing commit and creates a new commit from the current HEAD. This requires your working tree to be clean (no modifications from the HEAD commit).
+Applies the changes from one or more existing commits and creates a new commit from the current HEAD for each of them. This requires your working tree to be clean (no modifications from the HEAD commit).
 
-Cherry-picking merge commits or commits with table drops/renames is not currently supported. 
+A range of commits can be cherry-picked with {{.LessThan}}from{{.GreaterThan}}..{{.LessThan}}to{{.GreaterThan}}, which applies the commits reachable from {{.LessThan}}to{{.GreaterThan}} but not from {{.LessThan}}from{{.GreaterThan}}, oldest first. If a commit can't be applied cleanly, cherry-picking stops at that commit, and the commits after it are recorded so that {{.EmphasisLeft}}dolt cherry-pick --continue{{.EmphasisRight}} applies them once the conflicts are resolved.
+
+Merge commits are cherry-picked by applying their changes relative to one of their parents, chosen with {{.EmphasisLeft}}--mainline{{.EmphasisRight}}. Usually this is parent 1, the branch that was merged into. Cherry-picking commits with table drops/renames is not currently supported.
 
 If any data conflicts, schema conflicts, or constraint violations are detected during cherry-picking, you can use Dolt's conflict resolution features to resolve them. For more information on resolving conflicts, see: https://docs.dolthub.com/concepts/dolt/git/conflicts.
 `,
 	Synopsis: []string{
-		`[--allow-empty] {{.LessThan}}commit{{.GreaterThan}}`,
+		`[--allow-empty] [-m {{.LessThan}}parent-number{{.GreaterThan}}] {{.LessThan}}commit{{.GreaterThan}}...`,
+		`--continue | --abort`,
 	},
 }
 
 var ErrCherryPickConflictsOrViolations = errors.NewKind("error: Unable to apply commit cleanly due to conflicts " +
 	"or constraint violations. Please resolve the conflicts and/or constraint violations, then use `dolt add` " +
-	"to add the tables to the staged set, and `dolt commit` to commit the changes and finish cherry-picking. \n" +
+	"to add the tables to the staged set, and `dolt cherry-pick --continue` to commit the changes and cherry-pick " +
+	"the remaining commits. \n" +
 	"To undo all changes from this cherry-pick operation, use `dolt cherry-pick --abort`.\n" +
 	"For more information on handling conflicts, see: https://docs.dolthub.com/concepts/dolt/git/conflicts")
 
@@ -112,12 +116,9 @@ func (cmd CherryPickCmd) Exec(ctx context.Context, commandStr string, args []str
 		}
 	}
 
-	// TODO : support single commit cherry-pick only for now
-	if apr.NArg() == 0 {
+	if apr.NArg() == 0 && !apr.Contains(cli.ContinueFlag) {
 		usage()
 		return 1
-	} else if apr.NArg() > 1 {
-		return HandleVErrAndExitCode(errhand.BuildDError("cherry-picking multiple commits is not supported yet").SetPrintUsage().Build(), usage)
 	}
 
 	err = cherryPick(queryist, sqlCtx, apr, args)
@@ -125,24 +126,28 @@ func (cmd CherryPickCmd) Exec(ctx context.Context, commandStr string, args []str
 }
 
 func cherryPick(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgParseResults, args []string) error {
-	cherryStr := apr.Arg(0)
-	if len(cherryStr) == 0 {
-		return fmt.Errorf("error: cannot cherry-pick empty string")
+	for _, cherryStr := range apr.Args {
+		if len(cherryStr) == 0 {
+			return fmt.Errorf("error: cannot cherry-pick empty string")
+		}
 	}
 
-	hasStagedChanges, hasUnstagedChanges, err := hasStagedAndUnstagedChanged(queryist, sqlCtx)
-	if err != nil {
-		return fmt.Errorf("error: failed to check for staged and unstaged changes: %w", err)
-	}
-	if hasStagedChanges {
-		return fmt.Errorf("Please commit your staged changes before using cherry-pick.")
-	}
-	if hasUnstagedChanges {
-		return fmt.Errorf(`error: your local changes would be overwritten by cherry-pick.
+	// A cherry-pick in progress is continued with the resolved changes in the working set
+	if !apr.Contains(cli.ContinueFlag) {
+		hasStagedChanges, hasUnstagedChanges, err := hasStagedAndUnstagedChanged(queryist, sqlCtx)
+		if err != nil {
+			return fmt.Errorf("error: failed to check for staged and unstaged changes: %w", err)
+		}
+		if hasStagedChanges {
+			return fmt.Errorf("Please commit your staged changes before using cherry-pick.")
+		}
+		if hasUnstagedChanges {
+			return fmt.Errorf(`error: your local changes would be overwritten by cherry-pick.
 hint: commit your changes (dolt commit -am \"<message>\") or reset them (dolt reset --hard) to proceed.`)
+		}
 	}
 
-	_, err = GetRowsForSql(queryist, sqlCtx, "set @@dolt_allow_commit_conflicts = 1")
+	_, err := GetRowsForSql(queryist, sqlCtx, "set @@dolt_allow_commit_conflicts = 1")
 	if err != nil {
 		return fmt.Errorf("error: failed to set @@dolt_allow_commit_conflicts: %w", err)
 	}
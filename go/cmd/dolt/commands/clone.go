@@ -16,6 +16,7 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path"
 	"strings"
@@ -24,6 +25,7 @@ import (
 	"github.com/dolthub/dolt/go/store/types"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
@@ -31,9 +33,13 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/colmask"
 	"github.com/dolthub/dolt/go/libraries/events"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/earl"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
 )
 
 var cloneDocs = cli.CommandDocumentationContent{
@@ -99,6 +105,11 @@ func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEn
 	remoteName := apr.GetValueOrDefault(cli.RemoteParam, "origin")
 	branch := apr.GetValueOrDefault(cli.BranchParam, "")
 	singleBranch := apr.Contains(cli.SingleBranchFlag)
+	masked := apr.Contains(cli.MaskedFlag)
+	if masked {
+		// only the masked branch is kept, so there is no point fetching the others
+		singleBranch = true
+	}
 	dir, urlStr, verr := parseArgs(apr)
 	if verr != nil {
 		return verr
@@ -150,6 +161,9 @@ func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEn
 	dEnv = nil
 
 	err = actions.CloneRemote(ctx, srcDB, remoteName, branch, singleBranch, depth, clonedEnv)
+	if err == nil && masked {
+		err = maskClone(ctx, clonedEnv, remoteName, remoteUrl)
+	}
 	if err != nil {
 		// If we're cloning into a directory that already exists do not erase it. Otherwise
 		// make best effort to delete the directory we created.
@@ -169,6 +183,11 @@ func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEn
 		}
 	}
 
+	if masked {
+		// the remote was removed along with the unmasked history, so there is no upstream to track
+		return nil
+	}
+
 	err = clonedEnv.RepoStateWriter().UpdateBranch(clonedEnv.RepoState.CWBHeadRef().GetPath(), env.BranchConfig{
 		Merge:  clonedEnv.RepoState.Head,
 		Remote: remoteName,
@@ -180,6 +199,78 @@ func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEn
 	return nil
 }
 
+// maskClone replaces the history of the branch checked out in |dEnv|, a fresh clone of |remoteUrl|, with a single
+// commit in which the columns listed in dolt_column_masks are masked. The remote, its remote-tracking branches and
+// tags are removed and the chunks that are no longer referenced are collected, so that the clone keeps none of the
+// unmasked values.
+func maskClone(ctx context.Context, dEnv *env.DoltEnv, remoteName, remoteUrl string) error {
+	name, email, err := env.GetNameAndEmail(dEnv.Config)
+	if err != nil {
+		return err
+	}
+
+	eng, dbName, err := engine.NewSqlEngineForEnv(ctx, dEnv)
+	if err != nil {
+		return err
+	}
+	defer eng.Close()
+	sqlCtx, err := eng.NewLocalContext(ctx)
+	if err != nil {
+		return err
+	}
+	sqlCtx.SetCurrentDatabase(dbName)
+
+	root, err := dEnv.WorkingRoot(ctx)
+	if err != nil {
+		return err
+	}
+	root, err = colmask.MaskRoot(sqlCtx, dbName, root)
+	if err != nil {
+		return err
+	}
+
+	ddb := dEnv.DoltDB
+	root, _, err = ddb.WriteRootValue(ctx, root)
+	if err != nil {
+		return err
+	}
+	meta, err := datas.NewCommitMeta(name, email, fmt.Sprintf("Masked clone of %s", remoteUrl))
+	if err != nil {
+		return err
+	}
+	// the commit is made on a new ref, so that it has no parents, and then moved to the checked out branch
+	tmpRef := ref.NewInternalRef("masked-clone")
+	cm, err := ddb.CommitValue(ctx, tmpRef, root.NomsValue(), datas.CommitOptions{Meta: meta})
+	if err != nil {
+		return err
+	}
+	if err = ddb.SetHeadAndWorkingSetToCommit(ctx, dEnv.RepoState.CWBHeadRef(), cm); err != nil {
+		return err
+	}
+	if err = ddb.DeleteBranch(ctx, tmpRef, nil); err != nil {
+		return err
+	}
+
+	if err = dEnv.RemoveRemote(ctx, remoteName); err != nil {
+		return err
+	}
+	tags, err := ddb.GetTags(ctx)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if err = ddb.DeleteTag(ctx, tag); err != nil {
+			return err
+		}
+	}
+
+	err = ddb.GC(ctx, types.GCModeFull, nil)
+	if err == chunks.ErrNothingToCollect {
+		return nil
+	}
+	return err
+}
+
 func parseArgs(apr *argparser.ArgParseResults) (string, string, errhand.VerboseError) {
 	if apr.NArg() < 1 || apr.NArg() > 2 {
 		return "", "", errhand.BuildDError("").SetPrintUsage().Build()
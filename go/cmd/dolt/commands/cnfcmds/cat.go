@@ -44,10 +44,11 @@ type mergeStatus struct {
 }
 
 var conflictColsToIgnore = map[string]bool{
-	"from_root_ish":    true,
-	"our_diff_type":    true,
-	"their_diff_type":  true,
-	"dolt_conflict_id": true,
+	"from_root_ish":            true,
+	"our_diff_type":            true,
+	"their_diff_type":          true,
+	"dolt_conflict_id":         true,
+	"dolt_conflict_json_paths": true,
 }
 
 var catDocs = cli.CommandDocumentationContent{
@@ -71,7 +71,7 @@ csv,json or parquet file.
 `,
 
 	Synopsis: []string{
-		"[-f] [-r {{.LessThan}}result-format{{.GreaterThan}}] [-fn {{.LessThan}}file_name{{.GreaterThan}}]  [-d {{.LessThan}}directory{{.GreaterThan}}] [--batch] [--no-batch] [--no-autocommit] [--no-create-db] ",
+		"[-f] [-r {{.LessThan}}result-format{{.GreaterThan}}] [-fn {{.LessThan}}file_name{{.GreaterThan}}]  [-d {{.LessThan}}directory{{.GreaterThan}}] [--batch] [--no-batch] [--no-autocommit] [--no-create-db] [--masked] ",
 	},
 }
 
@@ -104,6 +104,7 @@ func (cmd DumpCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(noAutocommitFlag, "na", "Turn off autocommit for each dumped table. Useful for speeding up loading of output SQL file.")
 	ap.SupportsFlag(schemaOnlyFlag, "", "Dump a table's schema, without including any data, to the output SQL file.")
 	ap.SupportsFlag(noCreateDbFlag, "", "Do not write `CREATE DATABASE` statements in SQL files.")
+	ap.SupportsFlag(cli.MaskedFlag, "", "Mask the columns listed in {{.EmphasisLeft}}dolt_column_masks{{.EmphasisRight}}, as they are for users who don't administer the database.")
 	return ap
 }
 
@@ -182,7 +183,7 @@ func (cmd DumpCmd) Exec(ctx context.Context, commandStr string, args []string, d
 		}
 
 		for _, tbl := range tblNames {
-			tblOpts := newTableArgs(tbl, dumpOpts.dest, !apr.Contains(noBatchFlag), apr.Contains(noAutocommitFlag), schemaOnly)
+			tblOpts := newTableArgs(tbl, dumpOpts.dest, !apr.Contains(noBatchFlag), apr.Contains(noAutocommitFlag), schemaOnly, apr.Contains(cli.MaskedFlag))
 			err = dumpTable(ctx, dEnv, tblOpts, fPath)
 			if err != nil {
 				return HandleVErrAndExitCode(err, usage)
@@ -194,7 +195,7 @@ func (cmd DumpCmd) Exec(ctx context.Context, commandStr string, args []string, d
 			return HandleVErrAndExitCode(err, usage)
 		}
 	case csvFileExt, jsonFileExt, parquetFileExt:
-		err = dumpNonSqlTables(ctx, root, dEnv, force, tblNames, resFormat, outputFileOrDirName, false)
+		err = dumpNonSqlTables(ctx, root, dEnv, force, tblNames, resFormat, outputFileOrDirName, false, apr.Contains(cli.MaskedFlag))
 		if err != nil {
 			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 		}
@@ -527,6 +528,7 @@ type tableOptions struct {
 	dest          mvdata.DataLocation
 	batched       bool
 	autocommitOff bool
+	masked        bool
 }
 
 func (m tableOptions) IsBatched() bool {
@@ -561,7 +563,7 @@ func (m dumpOptions) DumpDestName() string {
 
 // dumpTable dumps table in file given specific table and file location info
 func dumpTable(ctx context.Context, dEnv *env.DoltEnv, tblOpts *tableOptions, filePath string) errhand.VerboseError {
-	rd, err := mvdata.NewSqlEngineReader(ctx, dEnv, tblOpts.tableName)
+	rd, err := mvdata.NewSqlEngineReader(ctx, dEnv, tblOpts.tableName, tblOpts.masked)
 	if err != nil {
 		return errhand.BuildDError("Error creating reader for %s.", tblOpts.SrcName()).AddCause(err).Build()
 	}
@@ -723,7 +725,7 @@ func getDumpOptions(fileName string, rf string, schemaOnly bool) *dumpOptions {
 
 // newTableArgs returns tableOptions of table name and src table location and dest file location
 // corresponding to the input parameters
-func newTableArgs(tblName string, destination mvdata.DataLocation, batched, autocommitOff, schemaOnly bool) *tableOptions {
+func newTableArgs(tblName string, destination mvdata.DataLocation, batched, autocommitOff, schemaOnly, masked bool) *tableOptions {
 	if schemaOnly {
 		batched = false
 	}
@@ -733,12 +735,13 @@ func newTableArgs(tblName string, destination mvdata.DataLocation, batched, auto
 		dest:          destination,
 		batched:       batched,
 		autocommitOff: autocommitOff,
+		masked:        masked,
 	}
 }
 
 // dumpNonSqlTables returns nil if all tables is dumped successfully, and it returns err if there is one.
 // It handles only csv and json file types(rf).
-func dumpNonSqlTables(ctx context.Context, root doltdb.RootValue, dEnv *env.DoltEnv, force bool, tblNames []string, rf string, dirName string, batched bool) errhand.VerboseError {
+func dumpNonSqlTables(ctx context.Context, root doltdb.RootValue, dEnv *env.DoltEnv, force bool, tblNames []string, rf string, dirName string, batched, masked bool) errhand.VerboseError {
 	var fName string
 	if dirName == emptyStr {
 		dirName = "doltdump/"
@@ -757,7 +760,7 @@ func dumpNonSqlTables(ctx context.Context, root doltdb.RootValue, dEnv *env.Dolt
 			return err
 		}
 
-		tblOpts := newTableArgs(tbl, dumpOpts.dest, batched, false, false)
+		tblOpts := newTableArgs(tbl, dumpOpts.dest, batched, false, false, masked)
 
 		err = dumpTable(ctx, dEnv, tblOpts, fPath)
 		if err != nil {
@@ -144,6 +144,8 @@ func NewSqlEngine(
 		IsReadOnly:     config.IsReadOnly,
 		IsServerLocked: config.IsServerLocked,
 	}).WithBackgroundThreads(bThreads)
+	engine.Parser = dsqle.NewParser(engine.Parser)
+	pro.SetStatementRunner(engine)
 
 	if err := configureBinlogPrimaryController(engine); err != nil {
 		return nil, err
@@ -301,6 +303,10 @@ func (se *SqlEngine) Query(ctx *sql.Context, query string) (sql.Schema, sql.RowI
 }
 
 func (se *SqlEngine) QueryWithBindings(ctx *sql.Context, query string, parsed sqlparser.Statement, bindings map[string]sqlparser.Expr, qFlags *sql.QueryFlags) (sql.Schema, sql.RowIter, *sql.QueryFlags, error) {
+	// statements parsed by the caller haven't been through the engine's parser, which rewrites them
+	if err := dsqle.RewriteStatement(parsed); err != nil {
+		return nil, nil, nil, err
+	}
 	return se.engine.QueryWithBindings(ctx, query, parsed, bindings, qFlags)
 }
 
@@ -378,7 +378,9 @@ func rebaseSqlEngine(ctx context.Context, dEnv *env.DoltEnv, root doltdb.RootVal
 
 	sqlCtx.SetCurrentDatabase(filterDbName)
 
-	se := engine.NewRebasedSqlEngine(sqle.New(azr, &sqle.Config{IsReadOnly: false}), map[string]dsess.SqlDatabase{filterDbName: db})
+	gmsEngine := sqle.New(azr, &sqle.Config{IsReadOnly: false})
+	gmsEngine.Parser = dsqle.NewParser(gmsEngine.Parser)
+	se := engine.NewRebasedSqlEngine(gmsEngine, map[string]dsess.SqlDatabase{filterDbName: db})
 
 	return sqlCtx, se, nil
 }
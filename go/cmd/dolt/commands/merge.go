@@ -46,14 +46,22 @@ var mergeDocs = cli.CommandDocumentationContent{
 	ShortDesc: "Join two or more development histories together",
 	LongDesc: `Incorporates changes from the named commits (since the time their histories diverged from the current branch) into the current branch.
 
+When more than one branch is named, all of them are merged with a single merge commit that has the current branch and each of the named branches as parents. If merging any of the branches produces conflicts or constraint violations, the whole merge is aborted without changing the working set, and the branches must be merged one at a time instead. Merging more than one branch requires a clean working set, and can't be combined with {{.EmphasisLeft}}--squash{{.EmphasisRight}} or {{.EmphasisLeft}}--no-commit{{.EmphasisRight}}.
+
+When the merged branch adds a column that is NOT NULL and has no default value, the existing rows of the current branch can't be updated automatically. Use {{.EmphasisLeft}}--column-default table.column=expression{{.EmphasisRight}} to populate the column for those rows with the value of {{.LessThan}}expression{{.GreaterThan}}, which is evaluated for each row and may refer to its other columns. The expression is only used for the merge and doesn't change the column's definition.
+
+With {{.EmphasisLeft}}--dry-run{{.EmphasisRight}}, the branch is merged in memory only, and the number of rows that the merge would add, modify and delete in each table of the current branch is printed, along with the number of data conflicts, schema conflicts and constraint violations it would produce. Nothing is merged and the working set is left unchanged. Add {{.EmphasisLeft}}--detail{{.EmphasisRight}} to list the primary key of each of those rows instead. The same information is available in SQL from the {{.EmphasisLeft}}dolt_merge_preview(){{.EmphasisRight}} table function.
+
 The second syntax ({{.LessThan}}dolt merge --abort{{.GreaterThan}}) can only be run after the merge has resulted in conflicts. dolt merge {{.EmphasisLeft}}--abort{{.EmphasisRight}} will abort the merge process and try to reconstruct the pre-merge state. However, if there were uncommitted changes when the merge started (and especially if those changes were further modified after the merge was started), dolt merge {{.EmphasisLeft}}--abort{{.EmphasisRight}} will in some cases be unable to reconstruct the original (pre-merge) changes. Therefore: 
 
 {{.LessThan}}Warning{{.GreaterThan}}: Running dolt merge with non-trivial uncommitted changes is discouraged: while possible, it may leave you in a state that is hard to back out of in the case of a conflict.
 `,
 
 	Synopsis: []string{
-		"[--squash] {{.LessThan}}branch{{.GreaterThan}}",
+		"[--squash] [--column-default {{.LessThan}}table.column=expression{{.GreaterThan}}] {{.LessThan}}branch{{.GreaterThan}}",
 		"--no-ff [-m message] {{.LessThan}}branch{{.GreaterThan}}",
+		"[-m message] {{.LessThan}}branch{{.GreaterThan}} {{.LessThan}}branch{{.GreaterThan}}...",
+		"--dry-run [--detail] {{.LessThan}}branch{{.GreaterThan}}",
 		"--abort",
 	},
 }
@@ -94,6 +102,8 @@ func (cmd MergeCmd) RequiresRepo() bool {
 func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
 	ap := cli.CreateMergeArgParser()
 	ap.SupportsFlag(cli.NoJsonMergeFlag, "", "Do not attempt to automatically resolve multiple changes to the same JSON value, report a conflict instead.")
+	ap.SupportsFlag(cli.DryRunFlag, "", "Report the rows that the merge would change and the conflicts and constraint violations it would produce, without merging.")
+	ap.SupportsFlag(cli.DetailFlag, "", "With --dry-run, list each row that the merge would change, conflict or violate a constraint, instead of counts per table.")
 	apr, usage, terminate, status := ParseArgsOrPrintHelp(ap, commandStr, args, mergeDocs)
 	if terminate {
 		return status
@@ -114,6 +124,10 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 		}
 	}
 
+	if apr.Contains(cli.DryRunFlag) {
+		return previewMerge(sqlCtx, queryist, apr, usage)
+	}
+
 	ok := validateDoltMergeArgs(apr, usage, cliCtx)
 	if ok != 0 {
 		return 1
@@ -180,10 +194,14 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 			cli.Println("merge finished, but failed to get hash of HEAD ref")
 			cli.Println(headHashErr.Error())
 		}
-		mergeHash, mergeHashErr := getHashOf(queryist, sqlCtx, apr.Arg(0))
-		if mergeHashErr != nil {
-			cli.Println("merge finished, but failed to get hash of merge ref")
-			cli.Println(mergeHashErr.Error())
+		var mergeHash string
+		if apr.NArg() == 1 {
+			var mergeHashErr error
+			mergeHash, mergeHashErr = getHashOf(queryist, sqlCtx, apr.Arg(0))
+			if mergeHashErr != nil {
+				cli.Println("merge finished, but failed to get hash of merge ref")
+				cli.Println(mergeHashErr.Error())
+			}
 		}
 
 		fastFwd := getFastforward(mergeResultRow, dprocedures.MergeProcFFIndex)
@@ -197,6 +215,60 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 	return 0
 }
 
+// previewMerge prints the changes that merging the branch named in |apr| would make to the current branch, as
+// reported by the dolt_merge_preview() table function. The branch and its working set are not changed.
+func previewMerge(sqlCtx *sql.Context, queryist cli.Queryist, apr *argparser.ArgParseResults, usage cli.UsagePrinter) int {
+	if apr.NArg() != 1 || apr.Contains(cli.AbortParam) {
+		usage()
+		return 1
+	}
+
+	if apr.Contains(cli.NoJsonMergeFlag) {
+		_, _, _, err := queryist.Query(sqlCtx, "set @@dolt_dont_merge_json = 1")
+		if err != nil {
+			cli.Println(err.Error())
+			return 1
+		}
+	}
+
+	args := []string{"?"}
+	params := []interface{}{apr.Arg(0)}
+	if apr.Contains(cli.DetailFlag) {
+		args = append(args, "'--"+cli.DetailFlag+"'")
+	}
+	if columnDefaults, ok := apr.GetValue(cli.ColumnDefaultParam); ok {
+		args = append(args, "'--"+cli.ColumnDefaultParam+"'", "?")
+		params = append(params, columnDefaults)
+	}
+	query, err := dbr.InterpolateForDialect("select * from dolt_merge_preview("+strings.Join(args, ", ")+")", params, dialect.MySQL)
+	if err != nil {
+		cli.Println(err.Error())
+		return 1
+	}
+
+	schema, rowIter, _, err := queryist.Query(sqlCtx, query)
+	if err != nil {
+		cli.Println(err.Error())
+		return 1
+	}
+	rows, err := sql.RowIterToRows(sqlCtx, rowIter)
+	if err != nil {
+		cli.Println(err.Error())
+		return 1
+	}
+	if len(rows) == 0 {
+		cli.Println("Merge would not change any tables")
+		return 0
+	}
+
+	err = engine.PrettyPrintResults(sqlCtx, engine.FormatTabular, schema, sql.RowsToRowIter(rows...))
+	if err != nil {
+		cli.Println(err.Error())
+		return 1
+	}
+	return 0
+}
+
 // validateDoltMergeArgs checks if the arguments passed to 'dolt merge' are valid
 func validateDoltMergeArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter, cliCtx cli.CliContext) int {
 	if apr.ContainsAll(cli.SquashParam, cli.NoFFParam) {
@@ -219,7 +291,7 @@ func validateDoltMergeArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinte
 			return 1
 		}
 	} else if apr.Contains(cli.NoFFParam) {
-		if apr.NArg() == 0 || apr.NArg() > 2 {
+		if apr.NArg() == 0 {
 			usage()
 			return 1
 		}
@@ -318,9 +390,18 @@ func constructInterpolatedDoltMergeQuery(apr *argparser.ArgParseResults, cliCtx
 		params = append(params, msg)
 	}
 
-	if !apr.Contains(cli.AbortParam) && !apr.Contains(cli.SquashParam) {
+	if apr.Contains(cli.ColumnDefaultParam) {
+		writeToBuffer("--column-default", false)
 		writeToBuffer("?", true)
-		params = append(params, apr.Arg(0))
+		columnDefaults, _ := apr.GetValue(cli.ColumnDefaultParam)
+		params = append(params, columnDefaults)
+	}
+
+	if !apr.Contains(cli.AbortParam) && !apr.Contains(cli.SquashParam) {
+		for _, arg := range apr.Args {
+			writeToBuffer("?", true)
+			params = append(params, arg)
+		}
 	}
 
 	buffer.WriteString(")")
@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	goerrors "gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
@@ -47,9 +48,18 @@ the same as Git and Dolt's "two dot log" syntax, or |upstreamBranch|..|currentBr
 Rebasing is useful to clean and organize your commit history, especially before merging a feature branch back to a shared 
 branch. For example, you can drop commits that contain debugging or test changes, or squash or fixup small commits into a 
 single commit, or reorder commits so that related changes are adjacent in the new commit history.
+
+With {{.EmphasisLeft}}--onto{{.EmphasisRight}}, the commits in |upstreamBranch|..|currentBranch| are replayed on top of 
+|newbase| instead of on top of the upstream branch. With {{.EmphasisLeft}}--autosquash{{.EmphasisRight}}, commits whose 
+message starts with "fixup! " or "squash! " are moved after the commit named by the rest of their subject line and 
+fixed up or squashed into it. Merge commits are not included in the rebase plan, so the rebased history is linear.
+
+An {{.EmphasisLeft}}exec{{.EmphasisRight}} step can be added anywhere in the rebase plan to run a SQL statement after 
+the preceding steps. If the statement fails, or returns a false, zero, or NULL value, the rebase stops so the problem 
+can be fixed before continuing with {{.EmphasisLeft}}dolt rebase --continue{{.EmphasisRight}}.
 `,
 	Synopsis: []string{
-		`(-i | --interactive) [--empty=drop|keep] {{.LessThan}}upstream{{.GreaterThan}}`,
+		`(-i | --interactive) [--empty=drop|keep] [--onto {{.LessThan}}newbase{{.GreaterThan}}] [--autosquash] {{.LessThan}}upstream{{.GreaterThan}}`,
 		`(--continue | --abort)`,
 	},
 }
@@ -137,7 +147,8 @@ func (cmd RebaseCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return 0
 	}
 
-	rebasePlan, err := getRebasePlan(cliCtx, sqlCtx, queryist, apr.Arg(0), branchName)
+	ontoBranch := apr.GetValueOrDefault(cli.OntoParam, apr.Arg(0))
+	rebasePlan, err := getRebasePlan(cliCtx, sqlCtx, queryist, apr.Arg(0), ontoBranch, branchName)
 	if err != nil {
 		// attempt to abort the rebase
 		_, _, _, _ = queryist.Query(sqlCtx, "CALL DOLT_REBASE('--abort');")
@@ -171,8 +182,9 @@ func (cmd RebaseCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 	rows, err = GetRowsForSql(queryist, sqlCtx, "CALL DOLT_REBASE('--continue');")
 	if err != nil {
-		// If the error is a data conflict, don't abort the rebase, but let the caller resolve the conflicts
-		if dprocedures.ErrRebaseDataConflict.Is(err) || strings.Contains(err.Error(), dprocedures.ErrRebaseDataConflict.Message[:40]) {
+		// If the error is a data conflict or a failed exec step, don't abort the rebase, but let the caller resolve
+		// the problem and continue
+		if isResumableRebaseError(err) {
 			if checkoutErr := syncCliBranchToSqlSessionBranch(sqlCtx, dEnv); checkoutErr != nil {
 				return HandleVErrAndExitCode(errhand.VerboseErrorFromError(checkoutErr), usage)
 			}
@@ -205,8 +217,25 @@ func (cmd RebaseCmd) Exec(ctx context.Context, commandStr string, args []string,
 	return 0
 }
 
+// isResumableRebaseError returns whether |err| stopped the rebase in a state the caller can fix before continuing it,
+// rather than in a state that requires aborting it.
+func isResumableRebaseError(err error) bool {
+	for _, kind := range []*goerrors.Kind{
+		dprocedures.ErrRebaseDataConflict,
+		dprocedures.ErrRebaseExecFailed,
+		dprocedures.ErrRebaseExecUncommittedChanges,
+	} {
+		// Errors from a remote server lose their kind, so fall back to matching the fixed start of the message
+		prefix, _, _ := strings.Cut(kind.Message, "%")
+		if kind.Is(err) || strings.Contains(err.Error(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // getRebasePlan opens an editor for users to edit the rebase plan and returns the parsed rebase plan from the editor.
-func getRebasePlan(cliCtx cli.CliContext, sqlCtx *sql.Context, queryist cli.Queryist, rebaseBranch, currentBranch string) (*rebase.RebasePlan, error) {
+func getRebasePlan(cliCtx cli.CliContext, sqlCtx *sql.Context, queryist cli.Queryist, rebaseBranch, ontoBranch, currentBranch string) (*rebase.RebasePlan, error) {
 	if cli.ExecuteWithStdioRestored == nil {
 		return nil, nil
 	}
@@ -215,7 +244,7 @@ func getRebasePlan(cliCtx cli.CliContext, sqlCtx *sql.Context, queryist cli.Quer
 		return nil, nil
 	}
 
-	initialRebaseMsg, err := buildInitialRebaseMsg(sqlCtx, queryist, rebaseBranch, currentBranch)
+	initialRebaseMsg, err := buildInitialRebaseMsg(sqlCtx, queryist, rebaseBranch, ontoBranch, currentBranch)
 	if err != nil {
 		return nil, err
 	}
@@ -241,7 +270,7 @@ func getRebasePlan(cliCtx cli.CliContext, sqlCtx *sql.Context, queryist cli.Quer
 
 // buildInitialRebaseMsg builds the initial message to display to the user when they open the rebase plan editor,
 // including the formatted rebase plan.
-func buildInitialRebaseMsg(sqlCtx *sql.Context, queryist cli.Queryist, rebaseBranch, currentBranch string) (string, error) {
+func buildInitialRebaseMsg(sqlCtx *sql.Context, queryist cli.Queryist, rebaseBranch, ontoBranch, currentBranch string) (string, error) {
 	var buffer bytes.Buffer
 
 	rows, err := GetRowsForSql(queryist, sqlCtx, "SELECT action, commit_hash, commit_message FROM dolt_rebase ORDER BY rebase_order")
@@ -257,6 +286,10 @@ func buildInitialRebaseMsg(sqlCtx *sql.Context, queryist cli.Queryist, rebaseBra
 		}
 		commitHash := row[1].(string)
 		commitMessage := row[2].(string)
+		if action == rebase.RebaseActionExec {
+			buffer.WriteString(fmt.Sprintf("%s %s\n", action, commitMessage))
+			continue
+		}
 		buffer.WriteString(fmt.Sprintf("%s %s %s\n", action, commitHash, commitMessage))
 	}
 	buffer.WriteString("\n")
@@ -270,8 +303,12 @@ func buildInitialRebaseMsg(sqlCtx *sql.Context, queryist cli.Queryist, rebaseBra
 	if err != nil {
 		return "", err
 	}
+	ontoBranchHash, err := getHashOf(queryist, sqlCtx, ontoBranch)
+	if err != nil {
+		return "", err
+	}
 	numSteps := len(rows)
-	buffer.WriteString(fmt.Sprintf("# Rebase %s..%s onto %s (%d commands)\n#\n", rebaseBranchHash, currentBranchHash, rebaseBranchHash, numSteps))
+	buffer.WriteString(fmt.Sprintf("# Rebase %s..%s onto %s (%d commands)\n#\n", rebaseBranchHash, currentBranchHash, ontoBranchHash, numSteps))
 
 	buffer.WriteString("# Commands:\n")
 	buffer.WriteString("# p, pick <commit> = use commit\n")
@@ -279,6 +316,7 @@ func buildInitialRebaseMsg(sqlCtx *sql.Context, queryist cli.Queryist, rebaseBra
 	buffer.WriteString("# r, reword <commit> = use commit, but edit the commit message\n")
 	buffer.WriteString("# s, squash <commit> = use commit, but meld into previous commit\n")
 	buffer.WriteString("# f, fixup <commit> = like \"squash\", but discard this commit's message\n")
+	buffer.WriteString("# x, exec <statement> = run a SQL statement; stop if it fails or returns false\n")
 	buffer.WriteString("# These lines can be re-ordered; they are executed from top to bottom.\n")
 	buffer.WriteString("#\n")
 	buffer.WriteString("# If you remove a line here THAT COMMIT WILL BE LOST.\n")
@@ -307,6 +345,16 @@ func parseRebaseMessage(rebaseMsg string) (*rebase.RebasePlan, error) {
 	splitMsg := strings.Split(rebaseMsg, "\n")
 	for i, line := range splitMsg {
 		if !strings.HasPrefix(line, "#") && strings.TrimSpace(line) != "" {
+			if action, statement, _ := strings.Cut(line, " "); action == rebase.RebaseActionExec || action == "x" {
+				if strings.TrimSpace(statement) == "" {
+					return nil, fmt.Errorf("invalid line %d: %s", i, line)
+				}
+				plan.Steps = append(plan.Steps, rebase.RebasePlanStep{
+					Action:    rebase.RebaseActionExec,
+					CommitMsg: strings.TrimSpace(statement),
+				})
+				continue
+			}
 			rebaseStepParts := strings.SplitN(line, " ", 3)
 			if len(rebaseStepParts) != 3 {
 				return nil, fmt.Errorf("invalid line %d: %s", i, line)
@@ -331,7 +379,8 @@ func insertRebasePlanIntoDoltRebaseTable(plan *rebase.RebasePlan, sqlCtx *sql.Co
 	}
 
 	for i, step := range plan.Steps {
-		_, err := GetRowsForSql(queryist, sqlCtx, fmt.Sprintf("INSERT INTO dolt_rebase VALUES (%d, '%s', '%s', '%s')", i+1, step.Action, step.CommitHash, step.CommitMsg))
+		_, err := GetRowsForSql(queryist, sqlCtx, fmt.Sprintf("INSERT INTO dolt_rebase VALUES (%d, '%s', '%s', '%s')",
+			i+1, step.Action, step.CommitHash, escapeSqlString(step.CommitMsg)))
 		if err != nil {
 			return err
 		}
@@ -340,6 +389,12 @@ func insertRebasePlanIntoDoltRebaseTable(plan *rebase.RebasePlan, sqlCtx *sql.Co
 	return nil
 }
 
+// escapeSqlString escapes |s| so that it can be used inside a single-quoted SQL string literal.
+func escapeSqlString(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	return strings.ReplaceAll(s, "'", "''")
+}
+
 // syncCliBranchToSqlSessionBranch sets the current branch for the CLI (in repo_state.json) to the active branch
 // for the current session. This is needed during rebasing, since any conflicts need to be resolved while the
 // session is on the rebase working branch (e.g. dolt_rebase_t1) and after the rebase finishes, the session needs
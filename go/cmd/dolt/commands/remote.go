@@ -43,7 +43,7 @@ var remoteDocs = cli.CommandDocumentationContent{
 {{.EmphasisLeft}}add{{.EmphasisRight}}
 Adds a remote named {{.LessThan}}name{{.GreaterThan}} for the repository at {{.LessThan}}url{{.GreaterThan}}. The command dolt fetch {{.LessThan}}name{{.GreaterThan}} can then be used to create and update remote-tracking branches {{.EmphasisLeft}}<name>/<branch>{{.EmphasisRight}}.
 
-The {{.LessThan}}url{{.GreaterThan}} parameter supports url schemes of http, https, aws, gs, and file. The url prefix defaults to https. If the {{.LessThan}}url{{.GreaterThan}} parameter is in the format {{.EmphasisLeft}}<organization>/<repository>{{.EmphasisRight}} then dolt will use the {{.EmphasisLeft}}remotes.default_host{{.EmphasisRight}} from your configuration file (Which will be dolthub.com unless changed).
+The {{.LessThan}}url{{.GreaterThan}} parameter supports url schemes of http, https, aws, s3, gs, azure, and file. The url prefix defaults to https. If the {{.LessThan}}url{{.GreaterThan}} parameter is in the format {{.EmphasisLeft}}<organization>/<repository>{{.EmphasisRight}} then dolt will use the {{.EmphasisLeft}}remotes.default_host{{.EmphasisRight}} from your configuration file (Which will be dolthub.com unless changed).
 
 AWS cloud remote urls should be of the form {{.EmphasisLeft}}aws://[dynamo-table:s3-bucket]/database{{.EmphasisRight}}.  You may configure your aws cloud remote using the optional parameters {{.EmphasisLeft}}aws-region{{.EmphasisRight}}, {{.EmphasisLeft}}aws-creds-type{{.EmphasisRight}}, {{.EmphasisLeft}}aws-creds-file{{.EmphasisRight}}.
 
@@ -53,8 +53,12 @@ aws-creds-type specifies the means by which credentials should be retrieved in o
 	env: Looks for environment variables AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
 	file: Uses the credentials file specified by the parameter aws-creds-file
 	
+S3 remote urls should be of the form {{.EmphasisLeft}}s3://s3-bucket/database{{.EmphasisRight}}. Unlike aws remotes they do not need a DynamoDB table, and they work with S3 compatible stores such as MinIO, Ceph and R2 which support conditional writes. They accept the aws-region, aws-creds-type, aws-creds-file and aws-creds-profile parameters, and {{.EmphasisLeft}}s3-endpoint{{.EmphasisRight}} sets the url of a store other than AWS S3.
+
 GCP remote urls should be of the form gs://gcs-bucket/database and will use the credentials setup using the gcloud command line available from Google.
 
+Azure remote urls should be of the form {{.EmphasisLeft}}azure://container/database{{.EmphasisRight}}. Credentials are read from the profile {{.EmphasisLeft}}azure-creds-profile{{.EmphasisRight}} (default 'default') of the JSON file {{.EmphasisLeft}}azure-creds-file{{.EmphasisRight}} (default ~/.azure/dolt_azure_credentials), which may set endpoint, accountName, accountKey and sasToken. Missing values are read from the environment variables AZURE_STORAGE_BLOB_ENDPOINT, AZURE_STORAGE_ACCOUNT, AZURE_STORAGE_KEY and AZURE_STORAGE_SAS_TOKEN.
+
 The local filesystem can be used as a remote by providing a repository url in the format file://absolute path. See https://en.wikipedia.org/wiki/File_URI_scheme
 
 {{.EmphasisLeft}}remove{{.EmphasisRight}}, {{.EmphasisLeft}}rm{{.EmphasisRight}}
@@ -98,9 +102,13 @@ func (cmd RemoteCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "Credential type. Valid options are role, env, and file. See the help section for additional details.", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, dbfactory.AWSCredTypes))
 	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file")
 	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use")
+	ap.SupportsString(dbfactory.S3EndpointParam, "", "url", "Endpoint of the S3 compatible store holding an s3 remote")
 
 	ap.SupportsString(dbfactory.OSSCredsFileParam, "", "file", "OSS credentials file")
 	ap.SupportsString(dbfactory.OSSCredsProfile, "", "profile", "OSS profile to use")
+
+	ap.SupportsString(dbfactory.AzureCredsFileParam, "", "file", "Azure credentials file")
+	ap.SupportsString(dbfactory.AzureCredsProfile, "", "profile", "Azure profile to use")
 	return ap
 }
 
@@ -213,8 +221,12 @@ func parseRemoteArgs(apr *argparser.ArgParseResults, scheme, remoteUrl string) (
 	switch scheme {
 	case dbfactory.AWSScheme:
 		err = cli.AddAWSParams(remoteUrl, apr, params)
+	case dbfactory.S3Scheme:
+		err = cli.AddS3Params(remoteUrl, apr, params)
 	case dbfactory.OSSScheme:
 		err = cli.AddOSSParams(remoteUrl, apr, params)
+	case dbfactory.AzureScheme:
+		err = cli.AddAzureParams(remoteUrl, apr, params)
 	default:
 		err = cli.VerifyNoAwsParams(apr)
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/gocraft/dbr/v2"
 	"github.com/gocraft/dbr/v2/dialect"
+	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
@@ -37,12 +38,26 @@ var revertDocs = cli.CommandDocumentationContent{
 		"{{.EmphasisLeft}}HEAD~1..HEAD~2{{.EmphasisRight}}, giving us a patch of what to remove to effectively remove the " +
 		"influence of the specified commit. If multiple commits are specified, then this process is repeated for each " +
 		"commit in the order specified. This requires a clean working set." +
-		"\n\nAny conflicts or constraint violations caused by the merge cause the command to fail.",
+		"\n\nMerge commits are reverted relative to their first parent, or to the parent chosen with " +
+		"{{.EmphasisLeft}}--mainline{{.EmphasisRight}}." +
+		"\n\nIf reverting a commit causes conflicts or constraint violations, the revert stops at that commit and " +
+		"records them in the working set. Resolve them and use {{.EmphasisLeft}}dolt add{{.EmphasisRight}}, then " +
+		"{{.EmphasisLeft}}dolt revert --continue{{.EmphasisRight}} to revert the remaining commits and commit the " +
+		"result. Use {{.EmphasisLeft}}dolt revert --skip{{.EmphasisRight}} to leave out the commit the revert stopped " +
+		"at, or {{.EmphasisLeft}}dolt revert --abort{{.EmphasisRight}} to undo the revert.",
 	Synopsis: []string{
-		"<revision>...",
+		"[-m <parent-number>] <revision>...",
+		"--continue | --skip | --abort",
 	},
 }
 
+var ErrRevertConflictsOrViolations = errors.NewKind("error: Unable to revert commit cleanly due to conflicts " +
+	"or constraint violations. Please resolve the conflicts and/or constraint violations, then use `dolt add` " +
+	"to add the tables to the staged set, and `dolt revert --continue` to finish reverting. \n" +
+	"To leave out this commit, use `dolt revert --skip`. To undo all changes from this revert operation, " +
+	"use `dolt revert --abort`.\n" +
+	"For more information on handling conflicts, see: https://docs.dolthub.com/concepts/dolt/git/conflicts")
+
 type RevertCmd struct{}
 
 var _ cli.Command = RevertCmd{}
@@ -81,7 +96,8 @@ func (cmd RevertCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return 1
 	}
 
-	if apr.NArg() < 1 {
+	abort, resume := apr.Contains(cli.AbortParam), apr.Contains(cli.ContinueFlag) || apr.Contains(cli.SkipFlag)
+	if apr.NArg() < 1 && !abort && !resume {
 		usage()
 		return 1
 	}
@@ -107,11 +123,28 @@ func (cmd RevertCmd) Exec(ctx context.Context, commandStr string, args []string,
 		author = fmt.Sprintf("%s <%s>", name, email)
 	}
 
+	// A revert that stops with conflicts records them in the working set, so the transaction must commit anyway
+	for _, q := range []string{"set @@dolt_allow_commit_conflicts = 1", "set @@dolt_force_transaction_commit = 1"} {
+		if _, err = GetRowsForSql(queryist, sqlCtx, q); err != nil {
+			cli.Println(err.Error())
+			return 1
+		}
+	}
+
 	var params []interface{}
 	params = append(params, author)
 
 	var buffer bytes.Buffer
 	buffer.WriteString("CALL DOLT_REVERT('--author', ?")
+	if mainline, ok := apr.GetValue(cli.MainlineParam); ok {
+		buffer.WriteString(", '--mainline', ?")
+		params = append(params, mainline)
+	}
+	for _, flag := range []string{cli.ContinueFlag, cli.SkipFlag, cli.AbortParam} {
+		if apr.Contains(flag) {
+			buffer.WriteString(", '--" + flag + "'")
+		}
+	}
 	// Loop over args and add them to the query
 	for _, input := range apr.Args {
 		buffer.WriteString(", ?")
@@ -129,11 +162,25 @@ func (cmd RevertCmd) Exec(ctx context.Context, commandStr string, args []string,
 		cli.Printf("Failure to execute '%s': %s\n", query, err.Error())
 		return 1
 	}
-	_, err = sql.RowIterToRows(sqlCtx, rowIter)
+	rows, err := sql.RowIterToRows(sqlCtx, rowIter)
 	if err != nil {
 		cli.Println(err.Error())
 		return 1
 	}
+	if abort {
+		return 0
+	}
+	if len(rows) == 1 {
+		status, err := getInt64ColAsInt64(rows[0][0])
+		if err != nil {
+			cli.Printf("Unable to parse status column: %s\n", err.Error())
+			return 1
+		}
+		if status != 0 {
+			cli.PrintErrln(ErrRevertConflictsOrViolations.New().Error())
+			return 1
+		}
+	}
 
 	commit, err := getCommitInfo(queryist, sqlCtx, "HEAD")
 	if err != nil {
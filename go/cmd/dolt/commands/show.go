@@ -19,8 +19,10 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/fatih/color"
 	"github.com/pkg/errors"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
@@ -29,7 +31,9 @@ import (
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/signing"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 	"github.com/dolthub/dolt/go/store/util/outputpager"
@@ -38,17 +42,20 @@ import (
 var hashRegex = regexp.MustCompile(`^#?[0-9a-v]{32}$`)
 
 type showOpts struct {
-	showParents bool
-	pretty      bool
-	decoration  string
-	specRefs    []string
+	showParents   bool
+	showSignature bool
+	pretty        bool
+	decoration    string
+	specRefs      []string
 
 	*diffDisplaySettings
 }
 
 var showDocs = cli.CommandDocumentationContent{
 	ShortDesc: `Show information about a specific commit`,
-	LongDesc:  `Show information about a specific commit`,
+	LongDesc: `Show information about a specific commit. If the revision is a tag, the tagger and message of the tag are shown before the commit it points to.
+
+With {{.EmphasisLeft}}--show-signature{{.EmphasisRight}}, the signatures of the commit and of the tag are verified, as with {{.EmphasisLeft}}dolt verify-commit{{.EmphasisRight}} and {{.EmphasisLeft}}dolt verify-tag{{.EmphasisRight}}.`,
 	Synopsis: []string{
 		`[{{.LessThan}}revision{{.GreaterThan}}]`,
 	},
@@ -82,6 +89,7 @@ func (cmd ShowCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(cli.ParentsFlag, "", "Shows all parents of each commit in the log.")
 	ap.SupportsString(cli.DecorateFlag, "", "decorate_fmt", "Shows refs next to commits. Valid options are short, full, no, and auto")
 	ap.SupportsFlag(cli.NoPrettyFlag, "", "Show the object without making it pretty.")
+	ap.SupportsFlag(cli.ShowSignatureFlag, "", "Shows the signature of the commit, and of the tag if the revision is a tag.")
 
 	// Flags inherited from Diff
 	ap.SupportsFlag(DataFlag, "d", "Show only the data changes, do not show the schema changes (Both shown by default).")
@@ -201,6 +209,10 @@ func (cmd ShowCmd) Exec(ctx context.Context, commandStr string, args []string, d
 			continue
 		} else {
 			// Hash is a commit
+			err = printTagIfExists(queryist, sqlCtx, opts, specRef)
+			if err != nil {
+				return handleErrAndExit(err)
+			}
 			err = fetchAndPrintCommit(queryist, sqlCtx, opts, commitInfo)
 			if err != nil {
 				return handleErrAndExit(err)
@@ -286,10 +298,11 @@ func parseShowArgs(apr *argparser.ArgParseResults) (*showOpts, error) {
 	}
 
 	return &showOpts{
-		showParents: apr.Contains(cli.ParentsFlag),
-		pretty:      !apr.Contains(cli.NoPrettyFlag),
-		decoration:  decorateOption,
-		specRefs:    apr.Args,
+		showParents:   apr.Contains(cli.ParentsFlag),
+		showSignature: apr.Contains(cli.ShowSignatureFlag),
+		pretty:        !apr.Contains(cli.NoPrettyFlag),
+		decoration:    decorateOption,
+		specRefs:      apr.Args,
 	}, nil
 }
 
@@ -308,13 +321,68 @@ func getCommitSpecPretty(queryist cli.Queryist, sqlCtx *sql.Context, opts *showO
 		commitRef = strings.TrimPrefix(commitRef, "#")
 	}
 
-	commit, err = getCommitInfo(queryist, sqlCtx, commitRef)
+	commit, err = getCommitInfoWithOptions(queryist, sqlCtx, commitRef, commitInfoOptions{showSignature: opts.showSignature})
 	if err != nil {
 		return commit, fmt.Errorf("error: failed to get commit metadata for ref '%s': %v", commitRef, err)
 	}
 	return
 }
 
+// printTagIfExists prints the tag named |specRef|, if there is one, in the format used by `git show`.
+func printTagIfExists(queryist cli.Queryist, sqlCtx *sql.Context, opts *showOpts, specRef string) error {
+	rows, err := InterpolateAndRunQuery(queryist, sqlCtx, "select tag_name, tagger, email, date, message from dolt_tags where tag_name = ?", specRef)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	row := rows[0]
+	timestamp, err := getTimestampColAsUint64(row[3])
+	if err != nil {
+		return fmt.Errorf("failed to parse tag timestamp: %w", err)
+	}
+
+	var signature string
+	if opts.showSignature {
+		signature, err = getTagSignature(queryist, sqlCtx, specRef)
+		if err != nil {
+			return err
+		}
+	}
+
+	cli.ExecuteWithStdioRestored(func() {
+		pager := outputpager.Start()
+		defer pager.Stop()
+
+		pager.Writer.Write([]byte(color.YellowString("tag %s", row[0].(string))))
+		if signature != "" {
+			for _, line := range strings.Split(strings.TrimSuffix(signature, "\n"), "\n") {
+				pager.Writer.Write([]byte("\n" + color.CyanString(line)))
+			}
+		}
+		pager.Writer.Write([]byte(fmt.Sprintf("\nTagger: %s <%s>", row[1].(string), row[2].(string))))
+		timeStr := time.UnixMilli(int64(timestamp)).In(datas.CommitLoc).Format(time.RubyDate)
+		pager.Writer.Write([]byte(fmt.Sprintf("\nDate:  %s", timeStr)))
+		pager.Writer.Write([]byte("\n\n\t" + strings.Replace(row[4].(string), "\n", "\n\t", -1) + "\n\n"))
+	})
+	return nil
+}
+
+// getTagSignature returns the result of verifying the signature of the tag |tagName|. Signatures which fail to verify
+// are described in the result, and unsigned tags have an empty result.
+func getTagSignature(queryist cli.Queryist, sqlCtx *sql.Context, tagName string) (string, error) {
+	rows, err := InterpolateAndRunQuery(queryist, sqlCtx, "call dolt_verify_tag(?)", tagName)
+	if err != nil {
+		if strings.Contains(err.Error(), signing.ErrNotSigned.Error()) {
+			return "", nil
+		}
+		return err.Error(), nil
+	}
+	return rows[0][1].(string), nil
+}
+
 func fetchAndPrintCommit(queryist cli.Queryist, sqlCtx *sql.Context, opts *showOpts, commit *CommitInfo) error {
 
 	cmHash := commit.commitHash
@@ -324,7 +392,7 @@ func fetchAndPrintCommit(queryist cli.Queryist, sqlCtx *sql.Context, opts *showO
 		pager := outputpager.Start()
 		defer pager.Stop()
 
-		PrintCommitInfo(pager, 0, opts.showParents, false, opts.decoration, commit)
+		PrintCommitInfo(pager, 0, opts.showParents, opts.showSignature, opts.decoration, commit)
 	})
 
 	if len(parents) == 0 {
@@ -16,6 +16,9 @@ package commands
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
 	"fmt"
 	"log"
 	"os"
@@ -25,12 +28,16 @@ import (
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/signing"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/gpg"
 
+	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
 )
 
 const keyId = "573DA8C6366D04E35CDB1A44E09A0B208F666373"
@@ -111,11 +118,16 @@ func TestSignAndVerifyCommit(t *testing.T) {
 }
 
 func execCommand(ctx context.Context, wd string, cmd cli.Command, args []string, apr *argparser.ArgParseResults, local, global map[string]string) (output string, err error) {
+	initialWd, err := os.Getwd()
+	if err != nil {
+		return
+	}
 	err = os.Chdir(wd)
 	if err != nil {
 		err = fmt.Errorf("error changing directory to %s: %w", wd, err)
 		return
 	}
+	defer os.Chdir(initialWd)
 
 	var fs filesys.Filesys
 	fs, err = filesys.LocalFilesysWithWorkingDir(wd)
@@ -195,3 +207,90 @@ func execCommand(ctx context.Context, wd string, cmd cli.Command, args []string,
 
 	return
 }
+
+func TestSSHSignAndVerify(t *testing.T) {
+	ctx := context.Background()
+	dbDir := setupTestDB(t, ctx, filesys.LocalFS)
+	keyDir := t.TempDir()
+
+	newKey := func(name string) (string, string) {
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		block, err := ssh.MarshalPrivateKey(priv, "")
+		require.NoError(t, err)
+		keyFile := filepath.Join(keyDir, name)
+		require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(block), 0600))
+		pub, err := ssh.NewPublicKey(priv.Public())
+		require.NoError(t, err)
+		return keyFile, string(ssh.MarshalAuthorizedKey(pub))
+	}
+	keyFile, pub := newKey("id_ed25519")
+	_, otherPub := newKey("id_other")
+
+	allowedSigners := filepath.Join(keyDir, "allowed_signers")
+	require.NoError(t, os.WriteFile(allowedSigners, []byte("test@dolthub.com "+pub), 0600))
+
+	// persisted system variables are loaded when dolt starts, which execCommand skips, so set them directly
+	setGlobals := func(vals map[string]interface{}) {
+		prev := make(map[string]interface{})
+		for name := range vals {
+			_, val, ok := sql.SystemVariables.GetGlobal(name)
+			require.True(t, ok)
+			prev[name] = val
+		}
+		require.NoError(t, sql.SystemVariables.AssignValues(vals))
+		t.Cleanup(func() {
+			require.NoError(t, sql.SystemVariables.AssignValues(prev))
+		})
+	}
+	setGlobals(map[string]interface{}{
+		dsess.GPGFormat:          signing.FormatSSH,
+		dsess.SigningKey:         keyFile,
+		dsess.AllowedSignersFile: allowedSigners,
+	})
+	global := map[string]string{
+		"user.name":  "First Last",
+		"user.email": "test@dolthub.com",
+	}
+	run := func(cmd cli.Command, ap *argparser.ArgParser, args ...string) (string, error) {
+		apr, err := ap.Parse(args)
+		require.NoError(t, err)
+		return execCommand(ctx, dbDir, cmd, args, apr, map[string]string{}, global)
+	}
+	const goodSig = `Good "dolt" signature for test@dolthub.com with ED25519 key SHA256:`
+
+	_, err := run(CommitCmd{}, cli.CreateCommitArgParser(), "-S", "--allow-empty", "-m", "signed commit")
+	require.NoError(t, err)
+	_, err = run(TagCmd{}, cli.CreateTagArgParser(), "-s", "v1", "-m", "signed tag")
+	require.NoError(t, err)
+	_, err = run(TagCmd{}, cli.CreateTagArgParser(), "unsigned")
+	require.NoError(t, err)
+
+	_, err = run(VerifyCommitCmd{}, cli.CreateVerifyCommitArgParser(), "HEAD")
+	require.NoError(t, err)
+	_, err = run(VerifyTagCmd{}, cli.CreateVerifyTagArgParser(), "v1")
+	require.NoError(t, err)
+
+	_, err = run(VerifyTagCmd{}, cli.CreateVerifyTagArgParser(), "unsigned")
+	require.Error(t, err)
+	_, err = run(VerifyCommitCmd{}, cli.CreateVerifyCommitArgParser(), "HEAD~1")
+	require.Error(t, err)
+
+	out, err := run(ShowCmd{}, ShowCmd{}.ArgParser(), "--show-signature", "v1")
+	require.NoError(t, err)
+	require.Contains(t, out, "tag v1")
+	require.Contains(t, out, "signed tag")
+	require.Equal(t, 2, strings.Count(out, goodSig))
+
+	out, err = run(LogCmd{}, cli.CreateLogArgParser(false), "--show-signature", "-n", "1")
+	require.NoError(t, err)
+	require.Contains(t, out, goodSig)
+
+	// Signatures by keys which are not allowed signers do not verify
+	require.NoError(t, os.WriteFile(allowedSigners, []byte("test@dolthub.com "+otherPub), 0600))
+	_, err = run(VerifyCommitCmd{}, cli.CreateVerifyCommitArgParser(), "HEAD")
+	require.Error(t, err)
+	out, err = run(LogCmd{}, cli.CreateLogArgParser(false), "--show-signature", "-n", "1")
+	require.NoError(t, err)
+	require.Contains(t, out, "no principal matched")
+}
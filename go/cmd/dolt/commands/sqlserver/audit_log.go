@@ -0,0 +1,444 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	querypb "github.com/dolthub/vitess/go/vt/proto/query"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// auditRecord is a line of the audit log. Every record holds the hash of the record before it, and is followed by its
+// own hash, which is the SHA-256 hash of the previous hash followed by the JSON of the record up to its hash field. A
+// record that is changed, removed or inserted breaks the chain of hashes from that point on.
+type auditRecord struct {
+	Seq          uint64 `json:"seq"`
+	Time         string `json:"time"`
+	ConnectionID uint32 `json:"connection_id"`
+	User         string `json:"user"`
+	Host         string `json:"host"`
+	Database     string `json:"database"`
+	Branch       string `json:"branch"`
+	Class        string `json:"class"`
+	Statement    string `json:"statement"`
+	RowsAffected uint64 `json:"rows_affected"`
+	DurationUs   int64  `json:"duration_us"`
+	Error        string `json:"error,omitempty"`
+	PrevHash     string `json:"prev_hash"`
+}
+
+// auditRecordHash is the field that ends every line of the audit log
+type auditRecordHash struct {
+	Hash string `json:"hash"`
+}
+
+// auditLog writes the audit log of the statements run by the server to a file, which it rotates once it grows past
+// the configured size.
+type auditLog struct {
+	cfg     *servercfg.AuditLogYAMLConfig
+	users   map[string]struct{}
+	classes map[string]struct{}
+
+	mu       sync.Mutex
+	f        *os.File
+	size     int64
+	seq      uint64
+	prevHash string
+}
+
+// newAuditLog opens the audit log configured by |cfg|, appending to the existing log file if there is one, and
+// continuing its chain of hashes.
+func newAuditLog(cfg *servercfg.AuditLogYAMLConfig) (*auditLog, error) {
+	l := &auditLog{cfg: cfg}
+	if len(cfg.Users()) > 0 {
+		l.users = make(map[string]struct{})
+		for _, user := range cfg.Users() {
+			l.users[user] = struct{}{}
+		}
+	}
+	if len(cfg.StatementClasses()) > 0 {
+		l.classes = make(map[string]struct{})
+		for _, class := range cfg.StatementClasses() {
+			l.classes[strings.ToLower(class)] = struct{}{}
+		}
+	}
+
+	seq, prevHash, err := lastAuditRecord(cfg.Path())
+	if err != nil {
+		return nil, err
+	}
+	l.seq, l.prevHash = seq, prevHash
+	if err = l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// lastAuditRecord returns the sequence number and the hash of the last record of the audit log at |path|, or zero
+// values if there is no such log. When the log file is missing or empty, because the server stopped right after
+// rotating it, the chain continues from the last record of the first rotated file.
+func lastAuditRecord(path string) (uint64, string, error) {
+	seq, hash, ok, err := lastAuditRecordOfFile(path)
+	if err != nil || ok {
+		return seq, hash, err
+	}
+	seq, hash, _, err = lastAuditRecordOfFile(rotatedAuditLogPath(path, 1))
+	return seq, hash, err
+}
+
+// lastAuditRecordOfFile returns the sequence number and the hash of the last record of the audit log file at |path|,
+// and whether the file has any records.
+func lastAuditRecordOfFile(path string) (uint64, string, bool, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, "", false, nil
+	} else if err != nil {
+		return 0, "", false, err
+	}
+	defer f.Close()
+
+	var last []byte
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) > 0 {
+			last = append(last[:0], scanner.Bytes()...)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return 0, "", false, err
+	} else if last == nil {
+		return 0, "", false, nil
+	}
+
+	var rec struct {
+		auditRecord
+		auditRecordHash
+	}
+	if err = json.Unmarshal(last, &rec); err != nil || rec.Hash == "" {
+		return 0, "", false, fmt.Errorf("audit log %s does not end with a complete record, so its chain of hashes cannot be continued", path)
+	}
+	return rec.Seq, rec.Hash, true, nil
+}
+
+func (l *auditLog) open() error {
+	f, size, err := openAuditLogFile(l.cfg.Path())
+	if err != nil {
+		return err
+	}
+	l.f, l.size = f, size
+	return nil
+}
+
+// openAuditLogFile opens the audit log file at |path| for appending, and returns its size.
+func openAuditLogFile(path string) (*os.File, int64, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	return f, info.Size(), nil
+}
+
+// Close closes the audit log file.
+func (l *auditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+// shouldLog returns whether statements of |class| run by |user| are audited.
+func (l *auditLog) shouldLog(user, class string) bool {
+	if l.users != nil {
+		if _, ok := l.users[user]; !ok {
+			return false
+		}
+	}
+	if l.classes != nil {
+		if _, ok := l.classes[class]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// write adds |rec| to the audit log, filling in its sequence number and the hash of the previous record.
+func (l *auditLog) write(rec auditRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+
+	rec.Seq = l.seq + 1
+	rec.PrevHash = l.prevHash
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(append([]byte(rec.PrevHash), data...))
+	hash := hex.EncodeToString(sum[:])
+	hashField, err := json.Marshal(auditRecordHash{Hash: hash})
+	if err != nil {
+		return err
+	}
+	line := append(data[:len(data)-1], ',')
+	line = append(line, hashField[1:]...)
+	line = append(line, '\n')
+
+	// A failed rotation is reported, but the record is still written to the current file, so that auditing
+	// continues. The rotation is tried again by the next write.
+	var rotateErr error
+	if maxSize := int64(l.cfg.MaxSizeMB()) * 1024 * 1024; maxSize > 0 && l.size > 0 && l.size+int64(len(line)) > maxSize {
+		if rotateErr = l.rotate(); rotateErr != nil {
+			rotateErr = fmt.Errorf("failed to rotate the audit log: %w", rotateErr)
+		}
+	}
+	n, err := l.f.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return err
+	}
+	l.seq, l.prevHash = rec.Seq, hash
+	return rotateErr
+}
+
+// rotate moves the audit log file to the first rotated file, shifting the older rotated files along and removing the
+// oldest ones, and opens a new file. The chain of hashes continues in the new file. The current file stays open until
+// the new file is, so that a failed rotation leaves the log writable.
+func (l *auditLog) rotate() error {
+	path := l.cfg.Path()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// an earlier rotation moved the current file, but failed to open a new one
+		return l.reopen()
+	}
+
+	last := 1
+	for ; ; last++ {
+		if _, err := os.Stat(rotatedAuditLogPath(path, last)); os.IsNotExist(err) {
+			break
+		}
+	}
+	for i := last; i > 0; i-- {
+		from := path
+		if i > 1 {
+			from = rotatedAuditLogPath(path, i-1)
+		}
+		to := rotatedAuditLogPath(path, i)
+		if max := l.cfg.MaxFiles(); max > 0 && i > max {
+			if err := os.Remove(from); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+	}
+	return l.reopen()
+}
+
+// reopen opens the audit log file and closes the file that was written until now.
+func (l *auditLog) reopen() error {
+	f, size, err := openAuditLogFile(l.cfg.Path())
+	if err != nil {
+		return err
+	}
+	if err = l.f.Close(); err != nil {
+		logrus.Warnf("error closing rotated audit log: %s", err.Error())
+	}
+	l.f, l.size = f, size
+	return nil
+}
+
+func rotatedAuditLogPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+// audit writes the audit record of the statement |run| by |c| in |sess|, if statements of its class run by its user
+// are audited. Errors writing the audit log are logged, but they don't fail the statement.
+func (l *auditLog) audit(c *mysql.Conn, sess sql.Session, run statementRun) {
+	statement := run.statement
+	class := statementClass(statement)
+	if !l.shouldLog(c.User, class) {
+		return
+	}
+
+	if l.cfg.NormalizeStatements() {
+		statement = normalizeStatement(statement)
+	}
+	rec := auditRecord{
+		Time:         run.start.UTC().Format(time.RFC3339Nano),
+		ConnectionID: c.ConnectionID,
+		User:         c.User,
+		Host:         connHost(c),
+		Class:        class,
+		Statement:    statement,
+		RowsAffected: run.rowsAffected,
+		DurationUs:   run.duration.Microseconds(),
+	}
+	if run.err != nil {
+		rec.Error = run.err.Error()
+	}
+	if sess != nil {
+		rec.Database, rec.Branch = sessionDatabaseAndBranch(sess)
+	}
+
+	if err := l.write(rec); err != nil {
+		logrus.Errorf("error writing audit log: %s", err.Error())
+	}
+}
+
+// sessionDatabaseAndBranch returns the current database of |sess| and its active branch, if it has one.
+func sessionDatabaseAndBranch(sess sql.Session) (string, string) {
+	dbName := sess.GetCurrentDatabase()
+	dSess, ok := sess.(*dsess.DoltSession)
+	if dbName == "" || !ok {
+		return dbName, ""
+	}
+	ctx := sql.NewContext(context.Background(), sql.WithSession(sess))
+	head, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		// not every database has branches, and detached heads have no active branch
+		return dbName, ""
+	}
+	return dbName, head.GetPath()
+}
+
+// connHost returns the host that |c| connects from.
+func connHost(c *mysql.Conn) string {
+	addr := c.RemoteAddr()
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// statementClass returns the class of |statement|, one of the servercfg.AuditLogClass constants, according to its
+// first keyword.
+func statementClass(statement string) string {
+	rest := skipSpaceAndComments(statement)
+	keyword, rest := nextWord(rest)
+	switch strings.ToUpper(keyword) {
+	case "CREATE", "ALTER", "DROP", "RENAME", "TRUNCATE":
+		return servercfg.AuditLogClassDDL
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "REPLACE", "WITH", "LOAD", "TABLE", "VALUES":
+		return servercfg.AuditLogClassDML
+	case "CALL":
+		name, _ := nextWord(skipSpaceAndComments(rest))
+		name = strings.Trim(name, "`")
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			name = strings.Trim(name[i+1:], "`")
+		}
+		if strings.HasPrefix(strings.ToLower(name), "dolt_") {
+			return servercfg.AuditLogClassDoltProcedure
+		}
+		return servercfg.AuditLogClassDML
+	default:
+		return servercfg.AuditLogClassOther
+	}
+}
+
+// skipSpaceAndComments returns |s| without its leading whitespace, comments and opening parentheses.
+func skipSpaceAndComments(s string) string {
+	for {
+		s = strings.TrimLeft(s, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(s, "--"), strings.HasPrefix(s, "#"):
+			i := strings.IndexByte(s, '\n')
+			if i < 0 {
+				return ""
+			}
+			s = s[i+1:]
+		case strings.HasPrefix(s, "/*"):
+			i := strings.Index(s[2:], "*/")
+			if i < 0 {
+				return ""
+			}
+			s = s[i+4:]
+		default:
+			return s
+		}
+	}
+}
+
+// nextWord returns the identifier at the start of |s|, which may be quoted and qualified, and the rest of |s|.
+func nextWord(s string) (string, string) {
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		if c == '_' || c == '.' || c == '`' || c == '$' || c >= 0x80 ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			i++
+			continue
+		}
+		break
+	}
+	return s[:i], s[i:]
+}
+
+// normalizedBindVar matches the bind variables that normalizeStatement replaces literals with, and those that the
+// parser replaces the placeholders of prepared statements with. Lists of literals are replaced with a single bind
+// variable prefixed with two colons.
+var normalizedBindVar = regexp.MustCompile(`::?(dolt_normalized|v)[0-9]+`)
+
+// normalizeStatement returns |statement| with its literals replaced with question marks, and its lists of literals
+// replaced with (...), so that statements that differ only in the values they use are logged the same, and the values
+// themselves are not logged. Statements that can't be parsed are returned as they are.
+func normalizeStatement(statement string) string {
+	stmt, err := sqlparser.Parse(statement)
+	if err != nil {
+		return statement
+	}
+	sqlparser.Normalize(stmt, map[string]*querypb.BindVariable{}, "dolt_normalized")
+	return normalizedBindVar.ReplaceAllStringFunc(sqlparser.String(stmt), func(bindVar string) string {
+		if strings.HasPrefix(bindVar, "::") {
+			return "(...)"
+		}
+		return "?"
+	})
+}
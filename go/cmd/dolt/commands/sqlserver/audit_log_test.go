@@ -0,0 +1,266 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/utils/svcs"
+)
+
+func TestStatementClass(t *testing.T) {
+	tests := []struct {
+		statement string
+		class     string
+	}{
+		{"create table t (i int)", servercfg.AuditLogClassDDL},
+		{"  ALTER TABLE t ADD COLUMN j int", servercfg.AuditLogClassDDL},
+		{"drop table t", servercfg.AuditLogClassDDL},
+		{"truncate t", servercfg.AuditLogClassDDL},
+		{"select * from t", servercfg.AuditLogClassDML},
+		{"(select 1) union (select 2)", servercfg.AuditLogClassDML},
+		{"/* comment */ insert into t values (1)", servercfg.AuditLogClassDML},
+		{"-- comment\nupdate t set i = 2", servercfg.AuditLogClassDML},
+		{"with cte as (select 1) delete from t", servercfg.AuditLogClassDML},
+		{"call dolt_commit('-am', 'message')", servercfg.AuditLogClassDoltProcedure},
+		{"CALL `DOLT_MERGE`('other')", servercfg.AuditLogClassDoltProcedure},
+		{"call mydb.dolt_checkout('main')", servercfg.AuditLogClassDoltProcedure},
+		{"call my_procedure()", servercfg.AuditLogClassDML},
+		{"set @@autocommit = 0", servercfg.AuditLogClassOther},
+		{"show tables", servercfg.AuditLogClassOther},
+		{"start transaction", servercfg.AuditLogClassOther},
+		{"", servercfg.AuditLogClassOther},
+	}
+	for _, test := range tests {
+		t.Run(test.statement, func(t *testing.T) {
+			assert.Equal(t, test.class, statementClass(test.statement))
+		})
+	}
+}
+
+func TestNormalizeStatement(t *testing.T) {
+	assert.Equal(t, "select * from t where a = ? and b in (...)", normalizeStatement("select * from t where a = 1 and b in ('x', 'y')"))
+	assert.Equal(t, "insert into t values (?, ?)", normalizeStatement("INSERT INTO t VALUES (1, 'secret')"))
+	assert.Equal(t, "select * from t where a = ? limit ?", normalizeStatement("select * from t where a = ? limit 10"))
+	assert.Equal(t, "not sql 'secret'", normalizeStatement("not sql 'secret'"))
+}
+
+// readAuditLog returns the records of the audit log files |paths|, oldest first, checking that their chain of hashes
+// is intact from the first record on.
+func readAuditLog(t *testing.T, paths ...string) []auditRecord {
+	var records []auditRecord
+	var prevHash string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+			var rec auditRecord
+			var hash auditRecordHash
+			require.NoError(t, json.Unmarshal(line, &rec))
+			require.NoError(t, json.Unmarshal(line, &hash))
+			if len(records) > 0 {
+				require.Equal(t, prevHash, rec.PrevHash)
+			}
+			prevHash = rec.PrevHash
+
+			hashed := line[:bytes.LastIndex(line, []byte(`,"hash":`))]
+			sum := sha256.Sum256(append([]byte(prevHash), append(hashed, '}')...))
+			require.Equal(t, hex.EncodeToString(sum[:]), hash.Hash, "record %d has been changed", rec.Seq)
+			prevHash = hash.Hash
+			records = append(records, rec)
+		}
+	}
+	return records
+}
+
+func TestAuditLogRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	maxSize, maxFiles := 0, 2
+	cfg := &servercfg.AuditLogYAMLConfig{Path_: &path, MaxSizeMB_: &maxSize, MaxFiles_: &maxFiles}
+
+	l, err := newAuditLog(cfg)
+	require.NoError(t, err)
+	require.NoError(t, l.write(auditRecord{Statement: "select 1"}))
+	require.NoError(t, l.write(auditRecord{Statement: "select 2"}))
+	require.NoError(t, l.Close())
+
+	// the chain of hashes continues when the log is opened again
+	l, err = newAuditLog(cfg)
+	require.NoError(t, err)
+	require.NoError(t, l.write(auditRecord{Statement: "select 3"}))
+	records := readAuditLog(t, path)
+	require.Len(t, records, 3)
+	assert.Empty(t, records[0].PrevHash)
+	assert.Equal(t, uint64(3), records[2].Seq)
+
+	// rotate the log after every record
+	maxSize = 1
+	l.size = 1024 * 1024
+	require.NoError(t, l.write(auditRecord{Statement: "select 4"}))
+	l.size = 1024 * 1024
+	require.NoError(t, l.write(auditRecord{Statement: "select 5"}))
+	l.size = 1024 * 1024
+	require.NoError(t, l.write(auditRecord{Statement: "select 6"}))
+	require.NoError(t, l.Close())
+
+	_, err = os.Stat(rotatedAuditLogPath(path, 3))
+	assert.True(t, os.IsNotExist(err))
+	records = readAuditLog(t, rotatedAuditLogPath(path, 2), rotatedAuditLogPath(path, 1), path)
+	require.Len(t, records, 3)
+	for i, rec := range records {
+		assert.Equal(t, fmt.Sprintf("select %d", i+4), rec.Statement)
+	}
+
+	// the chain continues from the last rotated file when the server stopped right after rotating the log
+	l, err = newAuditLog(cfg)
+	require.NoError(t, err)
+	require.NoError(t, l.rotate())
+	require.NoError(t, l.Close())
+	l, err = newAuditLog(cfg)
+	require.NoError(t, err)
+	require.NoError(t, l.write(auditRecord{Statement: "select 7"}))
+	require.NoError(t, l.Close())
+	records = readAuditLog(t, rotatedAuditLogPath(path, 1), path)
+	require.Len(t, records, 2)
+	assert.Equal(t, uint64(7), records[1].Seq)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"seq":8,"statement":"sel`), 0600))
+	_, err = newAuditLog(cfg)
+	assert.Error(t, err)
+}
+
+func TestAuditLogFailedRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	maxSize, maxFiles := 1, 1
+	cfg := &servercfg.AuditLogYAMLConfig{Path_: &path, MaxSizeMB_: &maxSize, MaxFiles_: &maxFiles}
+
+	l, err := newAuditLog(cfg)
+	require.NoError(t, err)
+	require.NoError(t, l.write(auditRecord{Statement: "select 1"}))
+
+	// a non-empty directory in place of the oldest rotated file can't be removed, so the rotation fails
+	require.NoError(t, os.MkdirAll(filepath.Join(rotatedAuditLogPath(path, 1), "dir"), 0700))
+	l.size = 1024 * 1024
+	assert.Error(t, l.write(auditRecord{Statement: "select 2"}))
+	records := readAuditLog(t, path)
+	require.Len(t, records, 2)
+	assert.Equal(t, "select 2", records[1].Statement)
+
+	// the rotation succeeds once the directory is gone, and the chain of hashes continues
+	require.NoError(t, os.RemoveAll(rotatedAuditLogPath(path, 1)))
+	require.NoError(t, l.write(auditRecord{Statement: "select 3"}))
+	require.NoError(t, l.Close())
+	records = readAuditLog(t, rotatedAuditLogPath(path, 1), path)
+	require.Len(t, records, 3)
+	for i, rec := range records {
+		assert.Equal(t, uint64(i+1), rec.Seq)
+	}
+}
+
+func TestServerAuditLog(t *testing.T) {
+	dEnv, err := sqle.CreateEnvWithSeedData()
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, dEnv.DoltDB.Close())
+	}()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	serverConfig, err := servercfg.NewYamlConfig([]byte(fmt.Sprintf(`
+log_level: fatal
+listener:
+  host: localhost
+  port: 15304
+audit_log:
+  path: %s
+  normalize_statements: true
+  statement_classes: [ddl, dml, dolt_procedure]
+`, path)))
+	require.NoError(t, err)
+
+	sc := svcs.NewController()
+	defer sc.Stop()
+	go func() {
+		_, _ = Serve(context.Background(), "0.0.0", serverConfig, sc, dEnv)
+	}()
+	require.NoError(t, sc.WaitForStart())
+
+	conn, err := dbr.Open("mysql", servercfg.ConnectionString(serverConfig, "dolt"), nil)
+	require.NoError(t, err)
+	conn.SetMaxOpenConns(1)
+	sess := conn.NewSession(nil)
+	queries := []string{
+		"create table audited (id int primary key, secret varchar(20))",
+		"insert into audited values (1, 'hunter2'), (2, 'swordfish')",
+		"set @x = 1",
+		"call dolt_commit('-Am', 'add audited')",
+		"call dolt_checkout('-b', 'other')",
+		"delete from audited where id = 1",
+	}
+	for _, query := range queries {
+		_, err = sess.Exec(query)
+		require.NoError(t, err, query)
+	}
+	_, err = sess.Exec("insert into nope values (1)")
+	require.Error(t, err)
+	require.NoError(t, conn.Close())
+	sc.Stop()
+	require.NoError(t, sc.WaitForStop())
+
+	records := readAuditLog(t, path)
+	require.NotEmpty(t, records)
+	assert.Empty(t, records[0].PrevHash)
+	var statements []string
+	for _, rec := range records {
+		statements = append(statements, rec.Statement)
+	}
+	require.Equal(t, []string{
+		"create table audited (\n\tid int primary key,\n\tsecret varchar(20)\n)",
+		"insert into audited values (?, ?), (?, ?)",
+		"call dolt_commit(?, ?)",
+		"call dolt_checkout(?, ?)",
+		"delete from audited where id = ?",
+		"insert into nope values (?)",
+	}, statements)
+
+	insert := records[1]
+	assert.Equal(t, "root", insert.User)
+	assert.Equal(t, "127.0.0.1", insert.Host)
+	assert.Equal(t, "dolt", insert.Database)
+	assert.Equal(t, "main", insert.Branch)
+	assert.Equal(t, servercfg.AuditLogClassDML, insert.Class)
+	assert.Equal(t, uint64(2), insert.RowsAffected)
+	assert.Empty(t, insert.Error)
+	assert.NotZero(t, insert.ConnectionID)
+
+	assert.Equal(t, servercfg.AuditLogClassDDL, records[0].Class)
+	assert.Equal(t, servercfg.AuditLogClassDoltProcedure, records[2].Class)
+	assert.Equal(t, "other", records[4].Branch)
+	assert.Equal(t, uint64(1), records[4].RowsAffected)
+	assert.Contains(t, records[5].Error, "table not found")
+}
@@ -303,10 +303,30 @@ func (cfg *commandLineServerConfig) RemotesapiReadOnly() *bool {
 	return cfg.remotesapiReadOnly
 }
 
+func (cfg *commandLineServerConfig) RemotesapiPreReceive() *servercfg.PreReceiveYAMLConfig {
+	return nil
+}
+
 func (cfg *commandLineServerConfig) ClusterConfig() servercfg.ClusterConfig {
 	return nil
 }
 
+func (cfg *commandLineServerConfig) AuditLog() *servercfg.AuditLogYAMLConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) SlowQueryLog() *servercfg.SlowQueryLogYAMLConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) QueryDigests() *servercfg.QueryDigestsYAMLConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) UserResourceLimits() []servercfg.ResourceLimitsYAMLConfig {
+	return nil
+}
+
 // PrivilegeFilePath returns the path to the file which contains all needed privilege information in the form of a
 // JSON string.
 func (cfg *commandLineServerConfig) PrivilegeFilePath() string {
@@ -24,6 +24,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/clusterdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/utils/version"
 )
 
@@ -33,6 +34,7 @@ const (
 	dbLabel     = "database"
 	roleLabel   = "role"
 	remoteLabel = "remote"
+	limitLabel  = "limit"
 )
 
 var _ server.ServerEventListener = (*metricsListener)(nil)
@@ -46,6 +48,8 @@ type metricsListener struct {
 	gaugeConcurrentQueries prometheus.Gauge
 	histQueryDur           prometheus.Histogram
 	gaugeVersion           prometheus.Gauge
+	// cntLimitViolations counts the queries stopped for exceeding each resource limit
+	cntLimitViolations []prometheus.CounterFunc
 
 	// replication metrics
 	isReplicaGauges      *prometheus.GaugeVec
@@ -127,6 +131,22 @@ func newMetricsListener(labels prometheus.Labels, versionStr string, clusterStat
 	prometheus.MustRegister(ml.replicationLagGauges)
 	prometheus.MustRegister(ml.isReplicaGauges)
 
+	for _, limit := range dsess.ResourceLimitNames {
+		constLabels := prometheus.Labels{limitLabel: limit}
+		for k, v := range labels {
+			constLabels[k] = v
+		}
+		cnt := prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "dss_resource_limit_violations",
+			Help:        "Count of queries stopped for exceeding a per-user resource limit",
+			ConstLabels: constLabels,
+		}, func() float64 {
+			return float64(dsess.ResourceLimitViolations(limit))
+		})
+		prometheus.MustRegister(cnt)
+		ml.cntLimitViolations = append(ml.cntLimitViolations, cnt)
+	}
+
 	go func() {
 		for ml.updateReplMetrics() {
 			time.Sleep(clusterUpdateInterval)
@@ -207,6 +227,9 @@ func (ml *metricsListener) Close() {
 	prometheus.Unregister(ml.gaugeConcurrentConn)
 	prometheus.Unregister(ml.gaugeConcurrentQueries)
 	prometheus.Unregister(ml.histQueryDur)
+	for _, cnt := range ml.cntLimitViolations {
+		prometheus.Unregister(cnt)
+	}
 
 	ml.closeReplicationMetrics()
 }
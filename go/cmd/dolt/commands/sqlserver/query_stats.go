@@ -0,0 +1,172 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	gms "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/querydigest"
+)
+
+// queryStats keeps the slow query log and the statistics of the statements run by the server, by digest.
+type queryStats struct {
+	// slowLog configures the slow query log, or is nil if there is none
+	slowLog       *servercfg.SlowQueryLogYAMLConfig
+	longQueryTime time.Duration
+	// digests is the store of the statistics of statements, or nil if they're not kept
+	digests *querydigest.Store
+	// engine plans the slow statements whose plans are logged
+	engine *gms.Engine
+
+	mu sync.Mutex
+	// f is the slow query log file, or nil if slow statements are written to the server log
+	f *os.File
+}
+
+// newQueryStats returns the queryStats configured by |slowLog| and |digests|, either of which may be nil, recording
+// digests in |store|.
+func newQueryStats(slowLog *servercfg.SlowQueryLogYAMLConfig, digests *servercfg.QueryDigestsYAMLConfig, store *querydigest.Store, engine *gms.Engine) (*queryStats, error) {
+	q := &queryStats{
+		slowLog:       slowLog,
+		longQueryTime: time.Duration(slowLog.LongQueryTimeMillis()) * time.Millisecond,
+		engine:        engine,
+	}
+	if digests.Enabled() {
+		store.SetMaxDigests(digests.MaxDigests())
+		q.digests = store
+	}
+	if path := slowLog.Path(); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, err
+		}
+		q.f = f
+	}
+	return q, nil
+}
+
+// Close closes the slow query log file.
+func (q *queryStats) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.f == nil {
+		return nil
+	}
+	err := q.f.Close()
+	q.f = nil
+	return err
+}
+
+// observe is a statementObserver that logs |run| if it's slow, and adds it to the statistics of its digest.
+func (q *queryStats) observe(c *mysql.Conn, sess sql.Session, run statementRun) {
+	if q.slowLog != nil && run.duration >= q.longQueryTime {
+		q.logSlowStatement(c, sess, run)
+	}
+	if q.digests != nil {
+		var database string
+		if sess != nil {
+			database = sess.GetCurrentDatabase()
+		}
+		q.digests.Record(querydigest.Statement{
+			Database:     database,
+			Text:         normalizeStatement(run.statement),
+			Start:        run.start,
+			Latency:      run.duration,
+			RowsExamined: run.rowsExamined,
+			RowsSent:     run.rowsSent,
+			Failed:       run.err != nil,
+		})
+	}
+}
+
+// logSlowStatement writes |run| to the slow query log. Errors writing the log are logged, but they don't fail the
+// statement.
+func (q *queryStats) logSlowStatement(c *mysql.Conn, sess sql.Session, run statementRun) {
+	var database, plan string
+	if sess != nil {
+		database = sess.GetCurrentDatabase()
+		if q.slowLog.IncludePlan() && run.err == nil {
+			plan = q.plan(sess, run.statement)
+		}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.f == nil {
+		fields := logrus.Fields{
+			"connectionID":  c.ConnectionID,
+			"user":          c.User,
+			"query_time":    run.duration.Seconds(),
+			"rows_sent":     run.rowsSent,
+			"rows_examined": run.rowsExamined,
+		}
+		if plan != "" {
+			fields["plan"] = plan
+		}
+		logrus.WithFields(fields).Warnf("slow query: %s", run.statement)
+		return
+	}
+	if _, err := q.f.WriteString(formatSlowStatement(c.User, connHost(c), c.ConnectionID, database, plan, run)); err != nil {
+		logrus.Errorf("error writing slow query log: %s", err.Error())
+	}
+}
+
+// plan returns the plan of |statement| run by |sess|, or an empty string if it can't be planned. Statements are
+// planned again after they've run, so the plan reflects the schema and the statistics as they are afterwards.
+func (q *queryStats) plan(sess sql.Session, statement string) string {
+	if q.engine == nil {
+		return ""
+	}
+	ctx := sql.NewContext(context.Background(), sql.WithSession(sess))
+	node, err := q.engine.AnalyzeQuery(ctx, statement)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(node.String())
+}
+
+// formatSlowStatement returns the entry of |run| in the slow query log, in the format of MySQL's slow query log, with
+// its |plan| as a comment if it's not empty.
+func formatSlowStatement(user, host string, connID uint32, database, plan string, run statementRun) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Time: %s\n", run.start.UTC().Format("2006-01-02T15:04:05.000000Z"))
+	fmt.Fprintf(&sb, "# User@Host: %s[%s] @  [%s]  Id: %d\n", user, user, host, connID)
+	fmt.Fprintf(&sb, "# Query_time: %.6f  Lock_time: 0.000000 Rows_sent: %d  Rows_examined: %d\n",
+		run.duration.Seconds(), run.rowsSent, run.rowsExamined)
+	if plan != "" {
+		sb.WriteString("# Plan:\n")
+		for _, line := range strings.Split(plan, "\n") {
+			fmt.Fprintf(&sb, "#   %s\n", line)
+		}
+	}
+	if database != "" {
+		fmt.Fprintf(&sb, "use %s;\n", sql.QuoteIdentifier(database))
+	}
+	fmt.Fprintf(&sb, "SET timestamp=%d;\n", run.start.Unix())
+	sb.WriteString(strings.TrimSuffix(strings.TrimSpace(run.statement), ";"))
+	sb.WriteString(";\n")
+	return sb.String()
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/querydigest"
+	"github.com/dolthub/dolt/go/libraries/utils/svcs"
+)
+
+func TestFormatSlowStatement(t *testing.T) {
+	run := statementRun{
+		statement:    "select * from t where id > 1;",
+		start:        time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC),
+		duration:     1500 * time.Millisecond,
+		rowsSent:     2,
+		rowsExamined: 10,
+	}
+	assert.Equal(t, `# Time: 2024-05-06T07:08:09.123456Z
+# User@Host: root[root] @  [127.0.0.1]  Id: 7
+# Query_time: 1.500000  Lock_time: 0.000000 Rows_sent: 2  Rows_examined: 10
+# Plan:
+#   Filter
+#    └─ Table
+use `+"`my db`"+`;
+SET timestamp=1714979289;
+select * from t where id > 1;
+`, formatSlowStatement("root", "127.0.0.1", 7, "my db", "Filter\n └─ Table", run))
+}
+
+func TestServerQueryStats(t *testing.T) {
+	dEnv, err := sqle.CreateEnvWithSeedData()
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, dEnv.DoltDB.Close())
+	}()
+	defer querydigest.Default.Reset()
+	querydigest.Default.Reset()
+
+	path := filepath.Join(t.TempDir(), "slow.log")
+	serverConfig, err := servercfg.NewYamlConfig([]byte(fmt.Sprintf(`
+log_level: fatal
+listener:
+  host: localhost
+  port: 15305
+slow_query_log:
+  path: %s
+  long_query_time_millis: 0
+  include_plan: true
+query_digests:
+  enabled: true
+`, path)))
+	require.NoError(t, err)
+
+	sc := svcs.NewController()
+	defer sc.Stop()
+	go func() {
+		_, _ = Serve(context.Background(), "0.0.0", serverConfig, sc, dEnv)
+	}()
+	require.NoError(t, sc.WaitForStart())
+
+	conn, err := dbr.Open("mysql", servercfg.ConnectionString(serverConfig, "dolt"), nil)
+	require.NoError(t, err)
+	conn.SetMaxOpenConns(1)
+	sess := conn.NewSession(nil)
+	for _, query := range []string{
+		"create table digested (id int primary key, v int)",
+		"insert into digested values (1, 10), (2, 20), (3, 30)",
+	} {
+		_, err = sess.Exec(query)
+		require.NoError(t, err, query)
+	}
+	for i := 1; i <= 3; i++ {
+		var vs []int
+		_, err = sess.SelectBySql(fmt.Sprintf("select v from digested where v >= %d", i*10)).Load(&vs)
+		require.NoError(t, err)
+		require.Len(t, vs, 4-i)
+	}
+
+	var digests []struct {
+		Count        uint64 `db:"count"`
+		RowsExamined uint64 `db:"rows_examined"`
+		RowsReturned uint64 `db:"rows_returned"`
+		P99LatencyUs uint64 `db:"p99_latency_us"`
+		MaxLatencyUs uint64 `db:"max_latency_us"`
+	}
+	_, err = sess.SelectBySql("select count, rows_examined, rows_returned, p99_latency_us, max_latency_us from dolt_query_digests where digest_text like 'select v from digested where v >= _'").Load(&digests)
+	require.NoError(t, err)
+	require.Len(t, digests, 1)
+	assert.Equal(t, uint64(3), digests[0].Count)
+	assert.Equal(t, uint64(9), digests[0].RowsExamined)
+	assert.Equal(t, uint64(6), digests[0].RowsReturned)
+	assert.LessOrEqual(t, digests[0].P99LatencyUs, digests[0].MaxLatencyUs)
+
+	_, err = sess.Exec("call dolt_reset_query_digests()")
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, sess.SelectBySql("select count(*) from dolt_query_digests where digest_text like '%digested%'").LoadOne(&count))
+	assert.Equal(t, 0, count)
+
+	require.NoError(t, conn.Close())
+	sc.Stop()
+	require.NoError(t, sc.WaitForStop())
+
+	slowLog, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(slowLog), "Rows_sent: 1  Rows_examined: 3\n# Plan:\n")
+	assert.Contains(t, string(slowLog), "use `dolt`;\n")
+	assert.Contains(t, string(slowLog), "select v from digested where v >= 30;\n")
+	assert.Contains(t, string(slowLog), "Table\n")
+}
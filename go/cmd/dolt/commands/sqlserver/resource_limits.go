@@ -0,0 +1,80 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"net"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// resourceLimits are the resource limits of the users and roles of the server.
+type resourceLimits struct {
+	users map[string]dsess.ResourceLimits
+	roles map[string]dsess.ResourceLimits
+}
+
+// newResourceLimits returns the resourceLimits configured by |configs|.
+func newResourceLimits(configs []servercfg.ResourceLimitsYAMLConfig) *resourceLimits {
+	rl := &resourceLimits{users: make(map[string]dsess.ResourceLimits), roles: make(map[string]dsess.ResourceLimits)}
+	for _, cfg := range configs {
+		limits := dsess.ResourceLimits{
+			MaxExecutionTime:     time.Duration(cfg.MaxExecutionTimeMillis()) * time.Millisecond,
+			MaxConcurrentQueries: cfg.MaxConcurrentQueries(),
+			MaxRowsReturned:      uint64(cfg.MaxRowsReturned()),
+			MaxMemoryBytes:       uint64(cfg.MaxMemoryMB()) * 1024 * 1024,
+		}
+		if cfg.User() != "" {
+			rl.users[cfg.User()] = limits
+		} else {
+			rl.roles[cfg.Role()] = limits
+		}
+	}
+	return rl
+}
+
+// forUser returns the limits of |user| connecting from |addr|. The limits of a user apply if they're configured.
+// Otherwise, the most restrictive of the limits of the roles granted to the user apply. Roles are resolved when a
+// session is created, so changes to the roles of a user apply to their new sessions.
+func (rl *resourceLimits) forUser(db *mysql_db.MySQLDb, user, addr string) dsess.ResourceLimits {
+	if limits, ok := rl.users[user]; ok {
+		return limits
+	}
+	if len(rl.roles) == 0 || db == nil {
+		return dsess.ResourceLimits{}
+	}
+
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	rd := db.Reader()
+	defer rd.Close()
+	u := db.GetUser(rd, user, host, false)
+	if u == nil {
+		return dsess.ResourceLimits{}
+	}
+	var limits dsess.ResourceLimits
+	for _, edge := range rd.GetToUserRoleEdges(mysql_db.RoleEdgesToKey{ToHost: u.Host, ToUser: u.User}) {
+		if roleLimits, ok := rl.roles[edge.FromUser]; ok {
+			limits = limits.Restrict(roleLimits)
+		}
+	}
+	return limits
+}
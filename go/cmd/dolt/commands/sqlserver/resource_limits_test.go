@@ -0,0 +1,194 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/svcs"
+)
+
+func TestServerResourceLimits(t *testing.T) {
+	dEnv, err := sqle.CreateEnvWithSeedData()
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, dEnv.DoltDB.Close())
+	}()
+
+	serverConfig, err := servercfg.NewYamlConfig([]byte(fmt.Sprintf(`
+log_level: fatal
+cfg_dir: %s
+listener:
+  host: localhost
+  port: 15306
+user_resource_limits:
+  - role: analyst
+    max_execution_time_millis: 200
+    max_rows_returned: 5
+  - role: intern
+    max_execution_time_millis: 5000
+    max_rows_returned: 2
+  - user: bob
+    max_concurrent_queries: 1
+  - user: dave
+    max_memory_mb: 1
+`, t.TempDir())))
+	require.NoError(t, err)
+
+	sc := svcs.NewController()
+	defer sc.Stop()
+	go func() {
+		_, _ = Serve(context.Background(), "0.0.0", serverConfig, sc, dEnv)
+	}()
+	require.NoError(t, sc.WaitForStart())
+
+	rootConn, err := dbr.Open("mysql", servercfg.ConnectionString(serverConfig, "dolt"), nil)
+	require.NoError(t, err)
+	defer rootConn.Close()
+	root := rootConn.NewSession(nil)
+	for _, query := range []string{
+		"create table limited (id int primary key)",
+		"insert into limited values (1), (2), (3), (4), (5), (6), (7), (8), (9), (10)",
+		"create table big (id int primary key, v varchar(1024))",
+		"insert into big with recursive ids (n) as (select 1 union all select n + 1 from ids where n < 2048) select n, repeat('x', 1024) from ids",
+		"create role analyst, intern",
+		"create user alice@'%' identified by 'pw'",
+		"grant select on *.* to alice@'%'",
+		"grant analyst to alice@'%'",
+		"create user carol@'%' identified by 'pw'",
+		"grant select on *.* to carol@'%'",
+		"grant analyst, intern to carol@'%'",
+		"create user bob@'%' identified by 'pw'",
+		"grant select on *.* to bob@'%'",
+		"grant analyst to bob@'%'",
+		"create user dave@'%' identified by 'pw'",
+		"grant select on *.* to dave@'%'",
+	} {
+		_, err = root.Exec(query)
+		require.NoError(t, err, query)
+	}
+
+	connect := func(user string) *dbr.Session {
+		conn, err := dbr.Open("mysql", fmt.Sprintf("%s:pw@tcp(localhost:15306)/dolt", user), nil)
+		require.NoError(t, err)
+		conn.SetMaxOpenConns(1)
+		return conn.NewSession(nil)
+	}
+	requireErrorCode := func(t *testing.T, err error, code uint16) {
+		var mysqlErr *mysql.MySQLError
+		require.True(t, errors.As(err, &mysqlErr), "expected a MySQL error, got %v", err)
+		assert.Equal(t, code, mysqlErr.Number, mysqlErr.Message)
+	}
+
+	timeouts := dsess.ResourceLimitViolations(dsess.LimitMaxExecutionTime)
+	tooManyRows := dsess.ResourceLimitViolations(dsess.LimitMaxRowsReturned)
+	tooManyQueries := dsess.ResourceLimitViolations(dsess.LimitMaxConcurrentQueries)
+	tooMuchMemory := dsess.ResourceLimitViolations(dsess.LimitMaxMemory)
+
+	t.Run("limits of a role", func(t *testing.T) {
+		alice := connect("alice")
+		defer alice.Close()
+		var ids []int
+		_, err := alice.Select("id").From("limited").Where("id <= 5").Load(&ids)
+		require.NoError(t, err)
+		assert.Len(t, ids, 5)
+
+		_, err = alice.Select("id").From("limited").Load(&ids)
+		requireErrorCode(t, err, 1226)
+
+		start := time.Now()
+		_, err = alice.Exec("select sleep(2)")
+		requireErrorCode(t, err, 3024)
+		assert.Less(t, time.Since(start), time.Second)
+
+		// the session is still usable after a query is stopped
+		var one int
+		require.NoError(t, alice.SelectBySql("select 1").LoadOne(&one))
+	})
+
+	t.Run("most restrictive limits of several roles", func(t *testing.T) {
+		carol := connect("carol")
+		defer carol.Close()
+		var ids []int
+		_, err := carol.Select("id").From("limited").Where("id <= 3").Load(&ids)
+		requireErrorCode(t, err, 1226)
+		_, err = carol.Exec("select sleep(2)")
+		requireErrorCode(t, err, 3024)
+	})
+
+	t.Run("limits of a user override those of their roles", func(t *testing.T) {
+		first, second := connect("bob"), connect("bob")
+		defer first.Close()
+		defer second.Close()
+		var ids []int
+		_, err := first.Select("id").From("limited").Load(&ids)
+		require.NoError(t, err)
+		assert.Len(t, ids, 10)
+
+		done := make(chan error)
+		go func() {
+			_, err := first.Exec("select sleep(1)")
+			done <- err
+		}()
+		time.Sleep(300 * time.Millisecond)
+		_, err = second.Exec("select 1")
+		requireErrorCode(t, err, 1226)
+		require.NoError(t, <-done)
+
+		_, err = second.Exec("select 1")
+		require.NoError(t, err)
+	})
+
+	t.Run("max memory", func(t *testing.T) {
+		dave := connect("dave")
+		defer dave.Close()
+		var ids []int
+		_, err := dave.Select("id").From("limited").Load(&ids)
+		require.NoError(t, err)
+		assert.Len(t, ids, 10)
+
+		// reading 2MB of table data exceeds the 1MB limit
+		var total int
+		err = dave.SelectBySql("select sum(length(v)) from big").LoadOne(&total)
+		requireErrorCode(t, err, 1226)
+
+		require.NoError(t, dave.SelectBySql("select length(v) from big where id = 1").LoadOne(&total))
+		assert.Equal(t, 1024, total)
+	})
+
+	t.Run("users without limits", func(t *testing.T) {
+		var ids []int
+		_, err := root.Select("id").From("limited").Load(&ids)
+		require.NoError(t, err)
+		assert.Len(t, ids, 10)
+	})
+
+	assert.Equal(t, timeouts+2, dsess.ResourceLimitViolations(dsess.LimitMaxExecutionTime))
+	assert.Equal(t, tooManyRows+2, dsess.ResourceLimitViolations(dsess.LimitMaxRowsReturned))
+	assert.Equal(t, tooManyQueries+1, dsess.ResourceLimitViolations(dsess.LimitMaxConcurrentQueries))
+	assert.Equal(t, tooMuchMemory+1, dsess.ResourceLimitViolations(dsess.LimitMaxMemory))
+}
@@ -53,6 +53,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
 	_ "github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/querydigest"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqlserver"
 	"github.com/dolthub/dolt/go/libraries/events"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
@@ -525,6 +526,7 @@ func ConfigureServices(
 			authenticator := newAccessController(sqlEngine.NewDefaultContext, sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.MySQLDb)
 			args = sqle.WithUserPasswordAuth(args, authenticator)
 			args.TLSConfig = serverConf.TLSConfig
+			args.PreReceiveHooks = preReceiveHooks(sqlEngine, serverConfig.RemotesapiPreReceive())
 
 			remoteSrv.srv, err = remotesrv.NewServer(args)
 			if err != nil {
@@ -617,26 +619,85 @@ func ConfigureServices(
 	// which is responsible for it and we only do it here if it hasn't
 	// already been Closed.
 
+	var auditLog *auditLog
+	InitAuditLog := &svcs.AnonService{
+		InitF: func(context.Context) (err error) {
+			if serverConfig.AuditLog() == nil {
+				return nil
+			}
+			auditLog, err = newAuditLog(serverConfig.AuditLog())
+			return err
+		},
+		StopF: func() error {
+			if auditLog == nil {
+				return nil
+			}
+			return auditLog.Close()
+		},
+	}
+	controller.Register(InitAuditLog)
+
+	var queryStats *queryStats
+	InitQueryStats := &svcs.AnonService{
+		InitF: func(context.Context) (err error) {
+			if serverConfig.SlowQueryLog() == nil && !serverConfig.QueryDigests().Enabled() {
+				return nil
+			}
+			queryStats, err = newQueryStats(serverConfig.SlowQueryLog(), serverConfig.QueryDigests(), querydigest.Default, sqlEngine.GetUnderlyingEngine())
+			return err
+		},
+		StopF: func() error {
+			if queryStats == nil {
+				return nil
+			}
+			return queryStats.Close()
+		},
+	}
+	controller.Register(InitQueryStats)
+
 	var sqlServerClosed bool
 	var mySQLServer *server.Server
 	InitSQLServer := &svcs.AnonService{
 		InitF: func(context.Context) (err error) {
+			sessionBuilder := newSessionBuilder(sqlEngine, serverConfig)
+			var wrappers []server.HandlerWrapper
 			v, ok := serverConfig.(servercfg.ValidatingServerConfig)
 			if ok && v.GoldenMysqlConnectionString() != "" {
+				wrappers = append(wrappers, func(h mysql.Handler) (mysql.Handler, error) {
+					return golden.NewValidatingHandler(h, v.GoldenMysqlConnectionString(), logrus.StandardLogger())
+				})
+			}
+			var observers statementObservers
+			if auditLog != nil {
+				observers.add(auditLog.audit)
+			}
+			if queryStats != nil {
+				observers.add(queryStats.observe)
+			}
+			if len(serverConfig.UserResourceLimits()) > 0 {
+				// the process list tracks the queries of every session, so it enforces the limits on them
+				gmsEngine := sqlEngine.GetUnderlyingEngine()
+				gmsEngine.ProcessList = dsess.NewResourceLimitingProcessList(gmsEngine.ProcessList)
+				observers.enforceLimits = true
+			}
+			if len(observers.observers) > 0 || observers.enforceLimits {
+				sessionBuilder = observers.trackSessions(sessionBuilder)
+				wrappers = append(wrappers, observers.wrapHandler)
+			}
+
+			if len(wrappers) > 0 {
 				mySQLServer, err = server.NewServerWithHandler(
 					serverConf,
 					sqlEngine.GetUnderlyingEngine(),
-					newSessionBuilder(sqlEngine, serverConfig),
+					sessionBuilder,
 					metListener,
-					func(h mysql.Handler) (mysql.Handler, error) {
-						return golden.NewValidatingHandler(h, v.GoldenMysqlConnectionString(), logrus.StandardLogger())
-					},
+					chainHandlerWrappers(wrappers),
 				)
 			} else {
 				mySQLServer, err = server.NewServer(
 					serverConf,
 					sqlEngine.GetUnderlyingEngine(),
-					newSessionBuilder(sqlEngine, serverConfig),
+					sessionBuilder,
 					metListener,
 				)
 			}
@@ -848,6 +909,7 @@ func (r *remotesapiAuth) ApiAuthenticate(ctx context.Context) (context.Context,
 	sqlCtx.Session.SetClient(sql.Client{User: creds.Username, Address: address, Capabilities: 0})
 
 	updatedCtx := context.WithValue(ctx, ApiSqleContextKey, sqlCtx)
+	updatedCtx = remotesrv.ContextWithPrincipal(updatedCtx, creds.Username)
 
 	return updatedCtx, nil
 }
@@ -876,6 +938,28 @@ func (r *remotesapiAuth) ApiAuthorize(ctx context.Context, superUserRequired boo
 	return true, nil
 }
 
+// preReceiveHooks returns the remotesrv.PreReceiveHooks configured by |cfg|. The command hook, if any, runs before the
+// SQL assertions.
+func preReceiveHooks(se *engine.SqlEngine, cfg *servercfg.PreReceiveYAMLConfig) []remotesrv.PreReceiveHook {
+	var hooks []remotesrv.PreReceiveHook
+	if cfg.Command() != "" {
+		hooks = append(hooks, remotesrv.ExecPreReceiveHook{Path: cfg.Command()})
+	}
+	if len(cfg.SQLAssertions()) > 0 {
+		assertions := make([]sqle.PreReceiveAssertion, len(cfg.SQLAssertions()))
+		for i, a := range cfg.SQLAssertions() {
+			assertions[i] = sqle.PreReceiveAssertion{
+				Name:    a.Name(),
+				Branch:  a.Branch(),
+				Query:   a.Query(),
+				Message: a.Message(),
+			}
+		}
+		hooks = append(hooks, sqle.NewSQLPreReceiveHook(se.NewDefaultContext, se.GetUnderlyingEngine(), assertions))
+	}
+	return hooks
+}
+
 func LoadClusterTLSConfig(cfg servercfg.ClusterConfig) (*tls.Config, error) {
 	rcfg := cfg.RemotesAPIConfig()
 	if rcfg.TLSKey() == "" && rcfg.TLSCert() == "" {
@@ -902,6 +986,20 @@ func portInUse(hostPort string) bool {
 	return false
 }
 
+// chainHandlerWrappers returns a server.HandlerWrapper that wraps the handler with each of |wrappers| in turn, so that
+// the last of them is the outermost handler.
+func chainHandlerWrappers(wrappers []server.HandlerWrapper) server.HandlerWrapper {
+	return func(h mysql.Handler) (mysql.Handler, error) {
+		var err error
+		for _, wrapper := range wrappers {
+			if h, err = wrapper(h); err != nil {
+				return nil, err
+			}
+		}
+		return h, nil
+	}
+}
+
 func newSessionBuilder(se *engine.SqlEngine, config servercfg.ServerConfig) server.SessionBuilder {
 	userToSessionVars := make(map[string]map[string]string)
 	userVars := config.UserVars()
@@ -909,6 +1007,11 @@ func newSessionBuilder(se *engine.SqlEngine, config servercfg.ServerConfig) serv
 		userToSessionVars[curr.Name] = curr.Vars
 	}
 
+	var limits *resourceLimits
+	if len(config.UserResourceLimits()) > 0 {
+		limits = newResourceLimits(config.UserResourceLimits())
+	}
+
 	return func(ctx context.Context, conn *mysql.Conn, addr string) (sql.Session, error) {
 		baseSession, err := sql.BaseSessionFromConnection(ctx, conn, addr)
 		if err != nil {
@@ -920,6 +1023,11 @@ func newSessionBuilder(se *engine.SqlEngine, config servercfg.ServerConfig) serv
 			return nil, err
 		}
 
+		if limits != nil {
+			mysqlDb := se.GetUnderlyingEngine().Analyzer.Catalog.MySQLDb
+			dsess.SetResourceLimits(limits.forUser(mysqlDb, conn.User, baseSession.Client().Address))
+		}
+
 		varsForUser := userToSessionVars[conn.User]
 		if len(varsForUser) > 0 {
 			sqlCtx, err := se.NewContext(ctx, dsess)
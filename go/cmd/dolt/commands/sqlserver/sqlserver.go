@@ -124,6 +124,30 @@ SUPPORTED CONFIG FILE FIELDS:
 
 {{.EmphasisLeft}}cluster{{.EmphasisRight}}: Settings related to running this server in a replicated cluster. For information on setting these values, see https://docs.dolthub.com/sql-reference/server/replication
 
+{{.EmphasisLeft}}audit_log.path{{.EmphasisRight}}: The path of a file to write the audit log to. Every statement run by the server is written to it as a line of JSON, with its connection id, user, host, database, active branch, rows affected, duration and error. Each record includes the hash of the record before it, so that changes to the log can be detected.
+
+{{.EmphasisLeft}}audit_log.max_size_mb{{.EmphasisRight}}: The size in megabytes at which the audit log file is rotated. Defaults to 100. Zero disables rotation.
+
+{{.EmphasisLeft}}audit_log.max_files{{.EmphasisRight}}: The number of rotated audit log files to keep. Defaults to 10. Zero keeps them all.
+
+{{.EmphasisLeft}}audit_log.normalize_statements{{.EmphasisRight}}: If true, the literals in audited statements are replaced with placeholders.
+
+{{.EmphasisLeft}}audit_log.users{{.EmphasisRight}}: A list of the users whose statements are audited. Defaults to every user.
+
+{{.EmphasisLeft}}audit_log.statement_classes{{.EmphasisRight}}: A list of the classes of statement that are audited, from {{.EmphasisLeft}}ddl{{.EmphasisRight}}, {{.EmphasisLeft}}dml{{.EmphasisRight}}, {{.EmphasisLeft}}dolt_procedure{{.EmphasisRight}} and {{.EmphasisLeft}}other{{.EmphasisRight}}. Defaults to every class.
+
+{{.EmphasisLeft}}slow_query_log.path{{.EmphasisRight}}: The path of a file to write the slow query log to, in the format of MySQL's slow query log. If the {{.EmphasisLeft}}slow_query_log{{.EmphasisRight}} section is present without a path, slow statements are written to the server log.
+
+{{.EmphasisLeft}}slow_query_log.long_query_time_millis{{.EmphasisRight}}: The time in milliseconds that a statement must take to be written to the slow query log. Defaults to 10000.
+
+{{.EmphasisLeft}}slow_query_log.include_plan{{.EmphasisRight}}: If true, the plan of each slow statement is written to the slow query log along with it.
+
+{{.EmphasisLeft}}query_digests.enabled{{.EmphasisRight}}: If true, the server keeps statistics of the statements it runs, aggregated by their text with literals replaced with placeholders, which can be read from the {{.EmphasisLeft}}dolt_query_digests{{.EmphasisRight}} system table and discarded with {{.EmphasisLeft}}CALL dolt_reset_query_digests(){{.EmphasisRight}}.
+
+{{.EmphasisLeft}}query_digests.max_digests{{.EmphasisRight}}: The number of distinct statements that statistics are kept for. Defaults to 1000. Other statements are counted together in a single row.
+
+{{.EmphasisLeft}}user_resource_limits{{.EmphasisRight}}: A list of limits on the resources that the queries of a {{.EmphasisLeft}}user{{.EmphasisRight}}, or of the users granted a {{.EmphasisLeft}}role{{.EmphasisRight}}, may use: {{.EmphasisLeft}}max_execution_time_millis{{.EmphasisRight}}, {{.EmphasisLeft}}max_concurrent_queries{{.EmphasisRight}}, {{.EmphasisLeft}}max_rows_returned{{.EmphasisRight}} and {{.EmphasisLeft}}max_memory_mb{{.EmphasisRight}}, the memory a query may use, approximated by the table data that it reads. Limits of a user take precedence over those of their roles, and users granted more than one role get the most restrictive limits of them. Queries that run for too long fail with error 3024, and queries that exceed other limits fail with error 1226. Violations are counted by the {{.EmphasisLeft}}dss_resource_limit_violations{{.EmphasisRight}} metric.
+
 If a config file is not provided many of these settings may be configured on the command line.`,
 	Synopsis: []string{
 		"--config {{.LessThan}}file{{.GreaterThan}}",
@@ -0,0 +1,207 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// statementRun describes a statement run by the server.
+type statementRun struct {
+	statement    string
+	start        time.Time
+	duration     time.Duration
+	rowsAffected uint64
+	rowsSent     uint64
+	rowsExamined uint64
+	err          error
+}
+
+// statementObserver is called after every statement run by a connection, with the session of the connection if it's
+// known.
+type statementObserver func(c *mysql.Conn, sess sql.Session, run statementRun)
+
+// statementObservers wraps the server's handler so that observers are told about every statement it runs, along with
+// the session that ran it. It also enforces the limits on the rows returned by the statements of sessions with
+// resource limits.
+type statementObservers struct {
+	observers []statementObserver
+	// enforceLimits is true if sessions may have resource limits
+	enforceLimits bool
+
+	// sessions are the sessions of the open connections, by connection id
+	sessions sync.Map
+}
+
+// add adds |observer| to the observers of every statement.
+func (o *statementObservers) add(observer statementObserver) {
+	o.observers = append(o.observers, observer)
+}
+
+// trackSessions returns a server.SessionBuilder that builds sessions with |sb| and remembers them, so that they can
+// be given to the observers of the statements they run.
+func (o *statementObservers) trackSessions(sb server.SessionBuilder) server.SessionBuilder {
+	return func(ctx context.Context, conn *mysql.Conn, addr string) (sql.Session, error) {
+		sess, err := sb(ctx, conn, addr)
+		if err == nil {
+			o.sessions.Store(conn.ConnectionID, sess)
+		}
+		return sess, err
+	}
+}
+
+// wrapHandler is a server.HandlerWrapper that tells the observers about every statement run by |h|.
+func (o *statementObservers) wrapHandler(h mysql.Handler) (mysql.Handler, error) {
+	return &statementHandler{Handler: h, o: o}, nil
+}
+
+func (o *statementObservers) session(c *mysql.Conn) sql.Session {
+	if sess, ok := o.sessions.Load(c.ConnectionID); ok {
+		return sess.(sql.Session)
+	}
+	return nil
+}
+
+// run runs a statement of |c| with |f|, counting the rows that it affects, returns and examines, and tells the
+// observers about it. |f| reports the results of the statement to the callback that it's given, stopping if it returns
+// an error, and returns the statement that it ran.
+func (o *statementObservers) run(c *mysql.Conn, f func(count func(*sqltypes.Result) error) (string, error)) error {
+	sess := o.session(c)
+	run := statementRun{start: time.Now()}
+	examinedBefore := rowsExamined(sess)
+	run.statement, run.err = f(func(res *sqltypes.Result) error {
+		run.rowsAffected += res.RowsAffected
+		run.rowsSent += uint64(len(res.Rows))
+		if o.enforceLimits {
+			return dsess.AddRowsReturned(sess, len(res.Rows))
+		}
+		return nil
+	})
+	if o.enforceLimits {
+		// statements stopped for exceeding a limit fail with the error of the limit, rather than being canceled
+		if limitErr := dsess.TakeResourceLimitError(sess); limitErr != nil && run.err != nil {
+			run.err = limitErr
+		}
+	}
+	run.duration = time.Since(run.start)
+	run.rowsExamined = rowsExamined(sess) - examinedBefore
+	for _, observer := range o.observers {
+		observer(c, sess, run)
+	}
+	return run.err
+}
+
+// rowsExamined returns the number of rows read from tables by the statements run by |sess| so far.
+func rowsExamined(sess sql.Session) uint64 {
+	if sess == nil {
+		return 0
+	}
+	var n uint64
+	for _, name := range []string{"Handler_read_next", "Handler_read_rnd_next"} {
+		if v, err := sess.GetStatusVariable(nil, name); err == nil {
+			if u, ok := v.(uint64); ok {
+				n += u
+			}
+		}
+	}
+	return n
+}
+
+// statementHandler is a mysql.Handler that tells its observers about every statement run by the handler it wraps.
+type statementHandler struct {
+	mysql.Handler
+	o *statementObservers
+}
+
+var _ mysql.Handler = (*statementHandler)(nil)
+var _ mysql.BinlogReplicaHandler = (*statementHandler)(nil)
+
+func (h *statementHandler) ComQuery(ctx context.Context, c *mysql.Conn, query string, callback mysql.ResultSpoolFn) error {
+	return h.o.run(c, func(count func(*sqltypes.Result) error) (string, error) {
+		return query, h.Handler.ComQuery(ctx, c, query, func(res *sqltypes.Result, more bool) error {
+			if err := count(res); err != nil {
+				return err
+			}
+			return callback(res, more)
+		})
+	})
+}
+
+func (h *statementHandler) ComMultiQuery(ctx context.Context, c *mysql.Conn, query string, callback mysql.ResultSpoolFn) (string, error) {
+	var remainder string
+	err := h.o.run(c, func(count func(*sqltypes.Result) error) (string, error) {
+		var err error
+		remainder, err = h.Handler.ComMultiQuery(ctx, c, query, func(res *sqltypes.Result, more bool) error {
+			if err := count(res); err != nil {
+				return err
+			}
+			return callback(res, more)
+		})
+		if err != nil {
+			return query, err
+		}
+		return strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(query, remainder), ";")), nil
+	})
+	return remainder, err
+}
+
+func (h *statementHandler) ComStmtExecute(ctx context.Context, c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
+	return h.o.run(c, func(count func(*sqltypes.Result) error) (string, error) {
+		return prepare.PrepareStmt, h.Handler.ComStmtExecute(ctx, c, prepare, func(res *sqltypes.Result) error {
+			if err := count(res); err != nil {
+				return err
+			}
+			return callback(res)
+		})
+	})
+}
+
+func (h *statementHandler) ComResetConnection(c *mysql.Conn) error {
+	// the session is rebuilt, and remembered again, by the handler
+	h.o.sessions.Delete(c.ConnectionID)
+	return h.Handler.ComResetConnection(c)
+}
+
+func (h *statementHandler) ConnectionClosed(c *mysql.Conn) {
+	defer h.o.sessions.Delete(c.ConnectionID)
+	h.Handler.ConnectionClosed(c)
+}
+
+func (h *statementHandler) ComRegisterReplica(c *mysql.Conn, replicaHost string, replicaPort uint16, replicaUser string, replicaPassword string) error {
+	rh, ok := h.Handler.(mysql.BinlogReplicaHandler)
+	if !ok {
+		return fmt.Errorf("binlog replication is not supported")
+	}
+	return rh.ComRegisterReplica(c, replicaHost, replicaPort, replicaUser, replicaPassword)
+}
+
+func (h *statementHandler) ComBinlogDumpGTID(c *mysql.Conn, logFile string, logPos uint64, gtidSet mysql.GTIDSet) error {
+	rh, ok := h.Handler.(mysql.BinlogReplicaHandler)
+	if !ok {
+		return fmt.Errorf("binlog replication is not supported")
+	}
+	return rh.ComBinlogDumpGTID(c, logFile, logPos, gtidSet)
+}
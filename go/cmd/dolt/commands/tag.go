@@ -47,10 +47,12 @@ var tagDocs = cli.CommandDocumentationContent{
 
 The command's second form creates a new tag named {{.LessThan}}tagname{{.GreaterThan}} which points to the current {{.EmphasisLeft}}HEAD{{.EmphasisRight}}, or {{.LessThan}}ref{{.GreaterThan}} if given. Optionally, a tag message can be passed using the {{.EmphasisLeft}}-m{{.EmphasisRight}} option. 
 
+With {{.EmphasisLeft}}-s{{.EmphasisRight}} or {{.EmphasisLeft}}-u{{.EmphasisRight}}, the tag is signed with the configured {{.EmphasisLeft}}signingkey{{.EmphasisRight}} or the given key. Set {{.EmphasisLeft}}gpgformat{{.EmphasisRight}} to {{.EmphasisLeft}}ssh{{.EmphasisRight}} to sign with an ssh key rather than with gpg. Use {{.EmphasisLeft}}dolt verify-tag{{.EmphasisRight}} to verify the signature.
+
 With a {{.EmphasisLeft}}-d{{.EmphasisRight}}, {{.LessThan}}tagname{{.GreaterThan}} will be deleted.`,
 	Synopsis: []string{
 		`[-v]`,
-		`[-m {{.LessThan}}message{{.GreaterThan}}] {{.LessThan}}tagname{{.GreaterThan}} [{{.LessThan}}ref{{.GreaterThan}}]`,
+		`[-m {{.LessThan}}message{{.GreaterThan}}] [-s | -u {{.LessThan}}key-id{{.GreaterThan}}] {{.LessThan}}tagname{{.GreaterThan}} [{{.LessThan}}ref{{.GreaterThan}}]`,
 		`-d {{.LessThan}}tagname{{.GreaterThan}}`,
 	},
 }
@@ -124,28 +126,20 @@ func createTag(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgPar
 	if len(apr.Args) > 1 {
 		startPoint = apr.Arg(1)
 	}
-	message, _ := apr.GetValue(cli.MessageArg)
-	author, _ := apr.GetValue(cli.AuthorParam)
-
-	var query string
-	var params []interface{}
-	if len(message) == 0 {
-		if len(author) == 0 {
-			query = "call dolt_tag(?, ?)"
-			params = []interface{}{tagName, startPoint}
-		} else {
-			query = "call dolt_tag(?, ?, '--author', ?)"
-			params = []interface{}{tagName, startPoint, author}
-		}
-	} else {
-		if len(author) == 0 {
-			query = "call dolt_tag('-m', ?, ?, ?)"
-			params = []interface{}{message, tagName, startPoint}
-		} else {
-			query = "call dolt_tag('-m', ?, ?, ?, '--author', ?)"
-			params = []interface{}{message, tagName, startPoint, author}
-		}
+	params := []interface{}{tagName, startPoint}
+	if message, ok := apr.GetValue(cli.MessageArg); ok && len(message) > 0 {
+		params = append(params, "-m", message)
+	}
+	if author, ok := apr.GetValue(cli.AuthorParam); ok && len(author) > 0 {
+		params = append(params, "--author", author)
+	}
+	if apr.Contains(cli.SignTagFlag) {
+		params = append(params, "--"+cli.SignTagFlag)
+	}
+	if keyId, ok := apr.GetValue(cli.LocalUserParam); ok {
+		params = append(params, "--"+cli.LocalUserParam, keyId)
 	}
+	query := "call dolt_tag(" + strings.TrimSuffix(strings.Repeat("?, ", len(params)), ", ") + ")"
 
 	_, err := InterpolateAndRunQuery(queryist, sqlCtx, query, params...)
 	if err != nil {
@@ -159,6 +153,8 @@ func deleteTags(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgPa
 		return errors.New("delete and tag message options are incompatible")
 	} else if apr.Contains(cli.VerboseFlag) {
 		return errors.New("delete and verbose options are incompatible")
+	} else if apr.Contains(cli.SignTagFlag) || apr.Contains(cli.LocalUserParam) {
+		return errors.New("delete and sign options are incompatible")
 	} else {
 		for _, tagName := range apr.Args {
 			_, err := InterpolateAndRunQuery(queryist, sqlCtx, "call dolt_tag('-d', ?)", tagName)
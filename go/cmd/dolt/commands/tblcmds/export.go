@@ -43,13 +43,14 @@ var exportDocs = cli.CommandDocumentationContent{
 See the help for {{.EmphasisLeft}}dolt table import{{.EmphasisRight}} as the options are the same.
 `,
 	Synopsis: []string{
-		"[-f] [-pk {{.LessThan}}field{{.GreaterThan}}] [-schema {{.LessThan}}file{{.GreaterThan}}] [-map {{.LessThan}}file{{.GreaterThan}}] [-continue] [-file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
+		"[-f] [-pk {{.LessThan}}field{{.GreaterThan}}] [-schema {{.LessThan}}file{{.GreaterThan}}] [-map {{.LessThan}}file{{.GreaterThan}}] [-continue] [-file-type {{.LessThan}}type{{.GreaterThan}}] [--masked] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
 	},
 }
 
 type exportOptions struct {
 	tableName  string
 	force      bool
+	masked     bool
 	dest       mvdata.DataLocation
 	srcOptions interface{}
 }
@@ -147,6 +148,7 @@ func parseExportArgs(ap *argparser.ArgParser, commandStr string, args []string)
 	return &exportOptions{
 		tableName: tableName,
 		force:     apr.Contains(forceParam),
+		masked:    apr.Contains(cli.MaskedFlag),
 		dest:      fileLoc,
 	}, nil
 }
@@ -174,6 +176,7 @@ func (cmd ExportCmd) ArgParser() *argparser.ArgParser {
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"file", "The file being output to."})
 	ap.SupportsFlag(forceParam, "f", "If data already exists in the destination, the force flag will allow the target to be overwritten.")
 	ap.SupportsString(fileTypeParam, "", "file_type", "Explicitly define the type of the file if it can't be inferred from the file extension.")
+	ap.SupportsFlag(cli.MaskedFlag, "", "Mask the columns listed in {{.EmphasisLeft}}dolt_column_masks{{.EmphasisRight}}, as they are for users who don't administer the database.")
 	return ap
 }
 
@@ -197,7 +200,7 @@ func (cmd ExportCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return commands.HandleVErrAndExitCode(verr, usage)
 	}
 
-	rd, err := mvdata.NewSqlEngineReader(ctx, dEnv, exOpts.tableName)
+	rd, err := mvdata.NewSqlEngineReader(ctx, dEnv, exOpts.tableName, exOpts.masked)
 	if err != nil {
 		return commands.HandleVErrAndExitCode(errhand.BuildDError("Error creating reader for %s.", exOpts.SrcName()).AddCause(err).Build(), usage)
 	}
@@ -740,7 +740,7 @@ func getImportSchema(ctx context.Context, dEnv *env.DoltEnv, impOpts *importOpti
 	}
 
 	// UpdateOp || ReplaceOp
-	tblRd, err := mvdata.NewSqlEngineReader(ctx, dEnv, impOpts.destTableName)
+	tblRd, err := mvdata.NewSqlEngineReader(ctx, dEnv, impOpts.destTableName, false)
 	if err != nil {
 		return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.CreateReaderErr, Cause: err}
 	}
@@ -0,0 +1,148 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const verifySignaturesLongDesc = `Signatures made with gpg are verified with the gpg keyring. Signatures made with ssh keys are verified against the allowed signers file named by the {{.EmphasisLeft}}allowedsignersfile{{.EmphasisRight}} configuration, which has the format of the ALLOWED SIGNERS section of ssh-keygen(1), for example:
+
+	dolt config --global --add sqlserver.global.allowedsignersfile ~/.ssh/allowed_signers
+
+The command exits with a non-zero status if any signature is missing, bad, or not made by an allowed signer.`
+
+var verifyCommitDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Check the signatures of commits.`,
+	LongDesc: `Verifies the signature of each {{.LessThan}}commit{{.GreaterThan}}, which must have been signed with {{.EmphasisLeft}}dolt commit -S{{.EmphasisRight}}.
+
+` + verifySignaturesLongDesc,
+	Synopsis: []string{
+		`{{.LessThan}}commit{{.GreaterThan}}...`,
+	},
+}
+
+var verifyTagDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Check the signatures of tags.`,
+	LongDesc: `Verifies the signature of each {{.LessThan}}tag{{.GreaterThan}}, which must have been signed with {{.EmphasisLeft}}dolt tag -s{{.EmphasisRight}}.
+
+` + verifySignaturesLongDesc,
+	Synopsis: []string{
+		`{{.LessThan}}tag{{.GreaterThan}}...`,
+	},
+}
+
+type VerifyCommitCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd VerifyCommitCmd) Name() string {
+	return "verify-commit"
+}
+
+// Description returns a description of the command
+func (cmd VerifyCommitCmd) Description() string {
+	return verifyCommitDocs.ShortDesc
+}
+
+func (cmd VerifyCommitCmd) Docs() *cli.CommandDocumentation {
+	return cli.NewCommandDocumentation(verifyCommitDocs, cmd.ArgParser())
+}
+
+func (cmd VerifyCommitCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateVerifyCommitArgParser()
+}
+
+// EventType returns the type of the event to log
+func (cmd VerifyCommitCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd VerifyCommitCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	return execVerifySignatures(ctx, commandStr, args, cliCtx, cmd.ArgParser(), verifyCommitDocs, "dolt_verify_commit")
+}
+
+type VerifyTagCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd VerifyTagCmd) Name() string {
+	return "verify-tag"
+}
+
+// Description returns a description of the command
+func (cmd VerifyTagCmd) Description() string {
+	return verifyTagDocs.ShortDesc
+}
+
+func (cmd VerifyTagCmd) Docs() *cli.CommandDocumentation {
+	return cli.NewCommandDocumentation(verifyTagDocs, cmd.ArgParser())
+}
+
+func (cmd VerifyTagCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateVerifyTagArgParser()
+}
+
+// EventType returns the type of the event to log
+func (cmd VerifyTagCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd VerifyTagCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	return execVerifySignatures(ctx, commandStr, args, cliCtx, cmd.ArgParser(), verifyTagDocs, "dolt_verify_tag")
+}
+
+// execVerifySignatures calls the verification procedure |procName| with the arguments of the command, and prints the
+// verification result of each signature.
+func execVerifySignatures(ctx context.Context, commandStr string, args []string, cliCtx cli.CliContext, ap *argparser.ArgParser, docs cli.CommandDocumentationContent, procName string) int {
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, docs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() == 0 {
+		return HandleVErrAndExitCode(errhand.BuildDError("%s requires at least one argument", ap.Name).Build(), usage)
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	params := make([]interface{}, apr.NArg())
+	for i, arg := range apr.Args {
+		params[i] = arg
+	}
+	query := fmt.Sprintf("call %s(%s)", procName, strings.TrimSuffix(strings.Repeat("?, ", len(params)), ", "))
+
+	rows, err := InterpolateAndRunQuery(queryist, sqlCtx, query, params...)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	for _, row := range rows {
+		cli.Print(row[1].(string))
+	}
+
+	return 0
+}
@@ -103,6 +103,8 @@ var doltSubCommands = []cli.Command{
 	schcmds.Commands,
 	tblcmds.Commands,
 	commands.TagCmd{},
+	commands.VerifyCommitCmd{},
+	commands.VerifyTagCmd{},
 	commands.BlameCmd{},
 	cvcmds.Commands,
 	commands.SendMetricsCmd{},
@@ -127,6 +129,7 @@ var doltSubCommands = []cli.Command{
 	commands.ReflogCmd{},
 	commands.RebaseCmd{},
 	commands.ArchiveCmd{},
+	commands.CdcCmd{},
 	ci.Commands,
 }
 
@@ -667,7 +667,19 @@ func (rcv *Index) TryFulltextInfo(obj *FulltextInfo) (*FulltextInfo, error) {
 	return nil, nil
 }
 
-const IndexNumFields = 12
+func (rcv *Index) VectorKey() bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(28))
+	if o != 0 {
+		return rcv._tab.GetBool(o + rcv._tab.Pos)
+	}
+	return false
+}
+
+func (rcv *Index) MutateVectorKey(n bool) bool {
+	return rcv._tab.MutateBoolSlot(28, n)
+}
+
+const IndexNumFields = 13
 
 func IndexStart(builder *flatbuffers.Builder) {
 	builder.StartObject(IndexNumFields)
@@ -720,6 +732,9 @@ func IndexAddFulltextKey(builder *flatbuffers.Builder, fulltextKey bool) {
 func IndexAddFulltextInfo(builder *flatbuffers.Builder, fulltextInfo flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(11, flatbuffers.UOffsetT(fulltextInfo), 0)
 }
+func IndexAddVectorKey(builder *flatbuffers.Builder, vectorKey bool) {
+	builder.PrependBoolSlot(12, vectorKey, false)
+}
 func IndexEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
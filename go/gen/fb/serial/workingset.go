@@ -364,7 +364,157 @@ func (rcv *MergeState) MutateIsCherryPick(n bool) bool {
 	return rcv._tab.MutateBoolSlot(12, n)
 }
 
-const MergeStateNumFields = 5
+func (rcv *MergeState) IsRevert() bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.GetBool(o + rcv._tab.Pos)
+	}
+	return false
+}
+
+func (rcv *MergeState) MutateIsRevert(n bool) bool {
+	return rcv._tab.MutateBoolSlot(14, n)
+}
+
+func (rcv *MergeState) RevertedCommitAddrs(j int) byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(16))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.GetByte(a + flatbuffers.UOffsetT(j*1))
+	}
+	return 0
+}
+
+func (rcv *MergeState) RevertedCommitAddrsLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(16))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *MergeState) RevertedCommitAddrsBytes() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(16))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *MergeState) MutateRevertedCommitAddrs(j int, n byte) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(16))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.MutateByte(a+flatbuffers.UOffsetT(j*1), n)
+	}
+	return false
+}
+
+func (rcv *MergeState) PendingRevertCommitAddrs(j int) byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(18))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.GetByte(a + flatbuffers.UOffsetT(j*1))
+	}
+	return 0
+}
+
+func (rcv *MergeState) PendingRevertCommitAddrsLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(18))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *MergeState) PendingRevertCommitAddrsBytes() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(18))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *MergeState) MutatePendingRevertCommitAddrs(j int, n byte) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(18))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.MutateByte(a+flatbuffers.UOffsetT(j*1), n)
+	}
+	return false
+}
+
+func (rcv *MergeState) RevertMainline() uint16 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(20))
+	if o != 0 {
+		return rcv._tab.GetUint16(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *MergeState) MutateRevertMainline(n uint16) bool {
+	return rcv._tab.MutateUint16Slot(20, n)
+}
+
+func (rcv *MergeState) PendingCherryPickCommitAddrs(j int) byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(22))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.GetByte(a + flatbuffers.UOffsetT(j*1))
+	}
+	return 0
+}
+
+func (rcv *MergeState) PendingCherryPickCommitAddrsLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(22))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *MergeState) PendingCherryPickCommitAddrsBytes() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(22))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *MergeState) MutatePendingCherryPickCommitAddrs(j int, n byte) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(22))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.MutateByte(a+flatbuffers.UOffsetT(j*1), n)
+	}
+	return false
+}
+
+func (rcv *MergeState) CherryPickMainline() uint16 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(24))
+	if o != 0 {
+		return rcv._tab.GetUint16(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *MergeState) MutateCherryPickMainline(n uint16) bool {
+	return rcv._tab.MutateUint16Slot(24, n)
+}
+
+func (rcv *MergeState) CherryPickAllowEmpty() bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(26))
+	if o != 0 {
+		return rcv._tab.GetBool(o + rcv._tab.Pos)
+	}
+	return false
+}
+
+func (rcv *MergeState) MutateCherryPickAllowEmpty(n bool) bool {
+	return rcv._tab.MutateBoolSlot(26, n)
+}
+
+const MergeStateNumFields = 12
 
 func MergeStateStart(builder *flatbuffers.Builder) {
 	builder.StartObject(MergeStateNumFields)
@@ -393,6 +543,36 @@ func MergeStateStartUnmergableTablesVector(builder *flatbuffers.Builder, numElem
 func MergeStateAddIsCherryPick(builder *flatbuffers.Builder, isCherryPick bool) {
 	builder.PrependBoolSlot(4, isCherryPick, false)
 }
+func MergeStateAddIsRevert(builder *flatbuffers.Builder, isRevert bool) {
+	builder.PrependBoolSlot(5, isRevert, false)
+}
+func MergeStateAddRevertedCommitAddrs(builder *flatbuffers.Builder, revertedCommitAddrs flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(6, flatbuffers.UOffsetT(revertedCommitAddrs), 0)
+}
+func MergeStateStartRevertedCommitAddrsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(1, numElems, 1)
+}
+func MergeStateAddPendingRevertCommitAddrs(builder *flatbuffers.Builder, pendingRevertCommitAddrs flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(7, flatbuffers.UOffsetT(pendingRevertCommitAddrs), 0)
+}
+func MergeStateStartPendingRevertCommitAddrsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(1, numElems, 1)
+}
+func MergeStateAddRevertMainline(builder *flatbuffers.Builder, revertMainline uint16) {
+	builder.PrependUint16Slot(8, revertMainline, 0)
+}
+func MergeStateAddPendingCherryPickCommitAddrs(builder *flatbuffers.Builder, pendingCherryPickCommitAddrs flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(9, flatbuffers.UOffsetT(pendingCherryPickCommitAddrs), 0)
+}
+func MergeStateStartPendingCherryPickCommitAddrsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(1, numElems, 1)
+}
+func MergeStateAddCherryPickMainline(builder *flatbuffers.Builder, cherryPickMainline uint16) {
+	builder.PrependUint16Slot(10, cherryPickMainline, 0)
+}
+func MergeStateAddCherryPickAllowEmpty(builder *flatbuffers.Builder, cherryPickAllowEmpty bool) {
+	builder.PrependBoolSlot(11, cherryPickAllowEmpty, false)
+}
 func MergeStateEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
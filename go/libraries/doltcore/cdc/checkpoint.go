@@ -0,0 +1,111 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var ErrInvalidCheckpoint = errors.NewKind("invalid change data capture checkpoint in %s: %s")
+
+// Checkpoint stores the last commit whose events have all been delivered to a sink, so that a stream can resume
+// after it.
+type Checkpoint interface {
+	// Load returns the stored commit, or false if nothing has been stored yet.
+	Load(ctx context.Context) (hash.Hash, bool, error)
+	Save(ctx context.Context, h hash.Hash) error
+}
+
+// FileCheckpoint stores the checkpoint commit hash in a file.
+type FileCheckpoint struct {
+	path string
+}
+
+var _ Checkpoint = FileCheckpoint{}
+
+func NewFileCheckpoint(path string) FileCheckpoint {
+	return FileCheckpoint{path: path}
+}
+
+func (c FileCheckpoint) Load(context.Context) (hash.Hash, bool, error) {
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return hash.Hash{}, false, nil
+	} else if err != nil {
+		return hash.Hash{}, false, err
+	}
+
+	s := strings.TrimSpace(string(data))
+	if s == "" {
+		return hash.Hash{}, false, nil
+	}
+	h, ok := hash.MaybeParse(s)
+	if !ok {
+		return hash.Hash{}, false, ErrInvalidCheckpoint.New(c.path, s)
+	}
+	return h, true, nil
+}
+
+// Save writes the checkpoint to a temporary file and renames it into place, so a crash never leaves a partially
+// written checkpoint behind.
+func (c FileCheckpoint) Save(_ context.Context, h hash.Hash) error {
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.WriteString(h.String() + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// MemoryCheckpoint keeps the checkpoint in memory. It is used for one-off streams that do not need to resume.
+type MemoryCheckpoint struct {
+	h  hash.Hash
+	ok bool
+}
+
+var _ Checkpoint = (*MemoryCheckpoint)(nil)
+
+// NewMemoryCheckpoint returns a checkpoint starting after |h|, or at the beginning of history if |h| is empty.
+func NewMemoryCheckpoint(h hash.Hash) *MemoryCheckpoint {
+	return &MemoryCheckpoint{h: h, ok: !h.IsEmpty()}
+}
+
+func (c *MemoryCheckpoint) Load(context.Context) (hash.Hash, bool, error) {
+	return c.h, c.ok, nil
+}
+
+func (c *MemoryCheckpoint) Save(_ context.Context, h hash.Hash) error {
+	c.h, c.ok = h, true
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// Connector is the name reported in the source block of every event.
+const Connector = "dolt"
+
+// Op is the kind of row change an Event describes. The values match the ones used by Debezium.
+type Op string
+
+const (
+	OpCreate Op = "c"
+	OpUpdate Op = "u"
+	OpDelete Op = "d"
+)
+
+// Event is a single row-level change, serialized as the payload of a Debezium change event envelope. Before is nil
+// for inserts and After is nil for deletes.
+type Event struct {
+	Before      map[string]interface{} `json:"before"`
+	After       map[string]interface{} `json:"after"`
+	Source      Source                 `json:"source"`
+	Op          Op                     `json:"op"`
+	TsMs        int64                  `json:"ts_ms"`
+	Transaction Transaction            `json:"transaction"`
+}
+
+// Source describes where an Event came from.
+type Source struct {
+	Version   string `json:"version"`
+	Connector string `json:"connector"`
+	Name      string `json:"name"`
+	TsMs      int64  `json:"ts_ms"`
+	DB        string `json:"db"`
+	Table     string `json:"table"`
+	Branch    string `json:"branch"`
+	Commit    string `json:"commit"`
+	// SchemaVersion is the hash of the table's schema at the commit that produced the event.
+	SchemaVersion string `json:"schema_version"`
+}
+
+// Transaction places an Event within the commit that produced it. Every commit is reported as one transaction whose
+// id is the commit hash.
+type Transaction struct {
+	ID                  string `json:"id"`
+	TotalOrder          int64  `json:"total_order"`
+	DataCollectionOrder int64  `json:"data_collection_order"`
+}
+
+// rowImage converts |row|, laid out in the column order of |sch|, into the column name to value map used for the
+// before and after images of an Event.
+func rowImage(ctx *sql.Context, sch schema.Schema, row sql.Row) (map[string]interface{}, error) {
+	cols := sch.GetAllCols().GetColumns()
+	image := make(map[string]interface{}, len(cols))
+	for i, col := range cols {
+		v, err := jsonValue(ctx, col.TypeInfo.ToSqlType(), row[i])
+		if err != nil {
+			return nil, err
+		}
+		image[col.Name] = v
+	}
+	return image, nil
+}
+
+// jsonValue converts a SQL value into the representation Debezium uses for it by default: numbers are left as they
+// are, binary and spatial values are bytes (which encoding/json writes as base64), and everything else, including JSON
+// documents, is the string MySQL would return for it.
+func jsonValue(ctx *sql.Context, typ sql.Type, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if (types.IsInteger(typ) || types.IsFloat(typ) || types.IsBit(typ)) && !types.IsEnum(typ) && !types.IsSet(typ) {
+		converted, _, err := typ.Convert(v)
+		return converted, err
+	}
+
+	sqlVal, err := typ.SQL(ctx, nil, v)
+	if err != nil {
+		return nil, err
+	}
+	if (types.IsBinaryType(typ) && !types.IsJSON(typ)) || types.IsGeometry(typ) {
+		return sqlVal.ToBytes(), nil
+	}
+	return sqlVal.ToString(), nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+var ErrUnknownSink = errors.NewKind("unknown change data capture sink '%s'")
+
+// Sink receives the events produced by a Streamer. Events are delivered in commit order, and Flush is called once all
+// the events of a commit have been emitted and before the commit is recorded in the checkpoint, so a sink that has
+// returned from Flush must have made those events durable.
+type Sink interface {
+	Emit(ctx context.Context, ev Event) error
+	Flush(ctx context.Context) error
+	Close() error
+}
+
+// SinkFactory opens the sink described by |target|, which is the part of the sink URL after "<scheme>://".
+type SinkFactory func(ctx context.Context, target string) (Sink, error)
+
+var sinksMu sync.Mutex
+var sinks = map[string]SinkFactory{
+	"file": func(ctx context.Context, target string) (Sink, error) {
+		return NewFileSink(target)
+	},
+}
+
+// RegisterSink makes the sink built by |factory| available to OpenSink under URLs of the form "<scheme>://...".
+func RegisterSink(scheme string, factory SinkFactory) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks[scheme] = factory
+}
+
+// OpenSink opens the sink named by |url|. An empty url or "-" writes to |stdout|, a url with a scheme is handled by
+// the factory registered for it, and anything else is the path of a file that events are appended to.
+func OpenSink(ctx context.Context, url string, stdout io.Writer) (Sink, error) {
+	if url == "" || url == "-" {
+		return NewWriterSink(stdout), nil
+	}
+
+	scheme, target, ok := strings.Cut(url, "://")
+	if !ok {
+		return NewFileSink(url)
+	}
+
+	sinksMu.Lock()
+	factory, ok := sinks[scheme]
+	sinksMu.Unlock()
+	if !ok {
+		return nil, ErrUnknownSink.New(url)
+	}
+	return factory(ctx, target)
+}
+
+// WriterSink writes each event as a line of JSON.
+type WriterSink struct {
+	wr  *bufio.Writer
+	enc *json.Encoder
+	c   io.Closer
+}
+
+var _ Sink = (*WriterSink)(nil)
+
+// NewWriterSink returns a Sink writing newline delimited JSON events to |wr|. |wr| is not closed by the sink.
+func NewWriterSink(wr io.Writer) *WriterSink {
+	bw := bufio.NewWriter(wr)
+	return &WriterSink{wr: bw, enc: json.NewEncoder(bw)}
+}
+
+// NewFileSink returns a Sink appending newline delimited JSON events to the file at |path|, creating it if needed.
+func NewFileSink(path string) (*WriterSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	s := NewWriterSink(f)
+	s.c = f
+	return s, nil
+}
+
+func (s *WriterSink) Emit(_ context.Context, ev Event) error {
+	return s.enc.Encode(ev)
+}
+
+func (s *WriterSink) Flush(_ context.Context) error {
+	if err := s.wr.Flush(); err != nil {
+		return err
+	}
+	if f, ok := s.c.(*os.File); ok {
+		return f.Sync()
+	}
+	return nil
+}
+
+func (s *WriterSink) Close() error {
+	err := s.wr.Flush()
+	if s.c != nil {
+		if cerr := s.c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// CollectingSink keeps the events it is sent in memory.
+type CollectingSink struct {
+	Events []Event
+}
+
+var _ Sink = (*CollectingSink)(nil)
+
+func (s *CollectingSink) Emit(_ context.Context, ev Event) error {
+	s.Events = append(s.Events, ev)
+	return nil
+}
+
+func (s *CollectingSink) Flush(context.Context) error {
+	return nil
+}
+
+func (s *CollectingSink) Close() error {
+	return nil
+}
@@ -0,0 +1,418 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"context"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/doltversion"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+var ErrCheckpointNotOnBranch = errors.NewKind("checkpoint commit %s is not in the first-parent history of branch %s")
+
+// diffBatchSize is the number of differences read at a time from the row differ of tables in the old storage format.
+// Tables in the new storage format are diffed with prolly.DiffMaps, which hands each difference over as it is found.
+const diffBatchSize = 1024
+
+// Streamer emits a change event for every row changed by the commits on a branch. Commits are visited oldest first
+// along the branch's first-parent history, and each commit is diffed against its first parent, so a merge commit
+// produces the changes it brought into the branch exactly once. After the events for a commit have been flushed to
+// the sink, the commit is saved to the checkpoint and later calls resume after it.
+type Streamer struct {
+	ddb        *doltdb.DoltDB
+	dbName     string
+	branch     string
+	sink       Sink
+	checkpoint Checkpoint
+}
+
+func NewStreamer(ddb *doltdb.DoltDB, dbName, branch string, sink Sink, checkpoint Checkpoint) *Streamer {
+	return &Streamer{
+		ddb:        ddb,
+		dbName:     dbName,
+		branch:     branch,
+		sink:       sink,
+		checkpoint: checkpoint,
+	}
+}
+
+// Poll emits the events of every commit on the branch made after the checkpoint, and returns the number of commits
+// it processed.
+func (s *Streamer) Poll(ctx *sql.Context) (int, error) {
+	start, hasStart, err := s.checkpoint.Load(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	head, err := s.ddb.ResolveCommitRef(ctx, ref.NewBranchRef(s.branch))
+	if err != nil {
+		return 0, err
+	}
+
+	commits, err := s.commitsSince(ctx, head, start, hasStart)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, cm := range commits {
+		if err = s.emitCommit(ctx, cm); err != nil {
+			return 0, err
+		}
+	}
+	return len(commits), nil
+}
+
+// commitsSince walks the first-parent history of |head| back to |start|, or to the initial commit if there is no
+// start, and returns the commits after |start| oldest first.
+func (s *Streamer) commitsSince(ctx context.Context, head *doltdb.Commit, start hash.Hash, hasStart bool) ([]*doltdb.Commit, error) {
+	var commits []*doltdb.Commit
+	cm := head
+	for {
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		if hasStart && h == start {
+			break
+		}
+		commits = append(commits, cm)
+
+		if cm.NumParents() == 0 {
+			if hasStart {
+				return nil, ErrCheckpointNotOnBranch.New(start.String(), s.branch)
+			}
+			break
+		}
+		optCmt, err := cm.GetParent(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		cm, ok = optCmt.ToCommit()
+		if !ok {
+			return nil, doltdb.ErrGhostCommitEncountered
+		}
+	}
+
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
+
+// commitEvents carries the per-commit state used to build the events of a commit.
+type commitEvents struct {
+	source     Source
+	tsMs       int64
+	totalOrder int64
+}
+
+func (s *Streamer) emitCommit(ctx *sql.Context, cm *doltdb.Commit) error {
+	h, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return err
+	}
+
+	toRoot, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	var fromRoot doltdb.RootValue
+	if cm.NumParents() == 0 {
+		fromRoot, err = doltdb.EmptyRootValue(ctx, s.ddb.ValueReadWriter(), s.ddb.NodeStore())
+	} else {
+		fromRoot, err = parentRoot(ctx, cm)
+	}
+	if err != nil {
+		return err
+	}
+
+	deltas, err := diff.GetTableDeltas(ctx, fromRoot, toRoot)
+	if err != nil {
+		return err
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltaName(deltas[i]).Less(deltaName(deltas[j]))
+	})
+
+	ce := &commitEvents{
+		source: Source{
+			Version:   doltversion.Version,
+			Connector: Connector,
+			Name:      s.dbName,
+			TsMs:      meta.Time().UnixMilli(),
+			DB:        s.dbName,
+			Branch:    s.branch,
+			Commit:    h.String(),
+		},
+		tsMs: time.Now().UnixMilli(),
+	}
+	for _, td := range deltas {
+		if doltdb.IsSystemTable(deltaName(td)) {
+			continue
+		}
+		if err = s.emitTable(ctx, ce, td); err != nil {
+			return err
+		}
+	}
+
+	if err = s.sink.Flush(ctx); err != nil {
+		return err
+	}
+	return s.checkpoint.Save(ctx, h)
+}
+
+func parentRoot(ctx context.Context, cm *doltdb.Commit) (doltdb.RootValue, error) {
+	optCmt, err := cm.GetParent(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	parent, ok := optCmt.ToCommit()
+	if !ok {
+		return nil, doltdb.ErrGhostCommitEncountered
+	}
+	return parent.GetRootValue(ctx)
+}
+
+func deltaName(td diff.TableDelta) doltdb.TableName {
+	if td.ToTable != nil {
+		return td.ToName
+	}
+	return td.FromName
+}
+
+// tableEvents builds the events for the changes to a single table.
+type tableEvents struct {
+	*commitEvents
+	sink            Sink
+	fromSch, toSch  schema.Schema
+	collectionOrder int64
+}
+
+func (te *tableEvents) emit(ctx *sql.Context, op Op, before, after sql.Row) error {
+	var ev Event
+	var err error
+	if before != nil {
+		if ev.Before, err = rowImage(ctx, te.fromSch, before); err != nil {
+			return err
+		}
+	}
+	if after != nil {
+		if ev.After, err = rowImage(ctx, te.toSch, after); err != nil {
+			return err
+		}
+	}
+
+	te.totalOrder++
+	te.collectionOrder++
+	ev.Source = te.source
+	ev.Op = op
+	ev.TsMs = te.tsMs
+	ev.Transaction = Transaction{
+		ID:                  te.source.Commit,
+		TotalOrder:          te.totalOrder,
+		DataCollectionOrder: te.collectionOrder,
+	}
+	return te.sink.Emit(ctx, ev)
+}
+
+func (s *Streamer) emitTable(ctx *sql.Context, ce *commitEvents, td diff.TableDelta) error {
+	fromSch, toSch, err := td.GetSchemas(ctx)
+	if err != nil {
+		return err
+	}
+	from, to, err := td.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	if from == nil {
+		if from, err = durable.NewEmptyIndex(ctx, s.ddb.ValueReadWriter(), s.ddb.NodeStore(), toSch, false); err != nil {
+			return err
+		}
+		fromSch = toSch
+	}
+	if to == nil {
+		if to, err = durable.NewEmptyIndex(ctx, s.ddb.ValueReadWriter(), s.ddb.NodeStore(), fromSch, false); err != nil {
+			return err
+		}
+		toSch = fromSch
+	}
+
+	name := deltaName(td)
+	tbl := td.ToTable
+	if tbl == nil {
+		tbl = td.FromTable
+	}
+	schHash, err := tbl.GetSchemaHash(ctx)
+	if err != nil {
+		return err
+	}
+
+	ce.source.Table = name.Name
+	ce.source.SchemaVersion = schHash.String()
+	te := &tableEvents{commitEvents: ce, sink: s.sink, fromSch: fromSch, toSch: toSch}
+
+	if td.HasPrimaryKeySetChanged() {
+		// Rows can't be matched up across a primary key change, so the table is reported as having had all of its
+		// rows deleted and then inserted again.
+		empty, err := durable.NewEmptyIndex(ctx, s.ddb.ValueReadWriter(), s.ddb.NodeStore(), fromSch, false)
+		if err != nil {
+			return err
+		}
+		te.toSch = fromSch
+		if err = te.emitIndexDiff(ctx, from, empty); err != nil {
+			return err
+		}
+		if empty, err = durable.NewEmptyIndex(ctx, s.ddb.ValueReadWriter(), s.ddb.NodeStore(), toSch, false); err != nil {
+			return err
+		}
+		te.fromSch, te.toSch = toSch, toSch
+		return te.emitIndexDiff(ctx, empty, to)
+	}
+	return te.emitIndexDiff(ctx, from, to)
+}
+
+func (te *tableEvents) emitIndexDiff(ctx *sql.Context, from, to durable.Index) error {
+	if types.IsFormat_DOLT(from.Format()) {
+		return te.emitProllyDiff(ctx, durable.ProllyMapFromIndex(from), durable.ProllyMapFromIndex(to))
+	}
+	return te.emitNomsDiff(ctx, durable.NomsMapFromIndex(from), durable.NomsMapFromIndex(to))
+}
+
+func (te *tableEvents) emitProllyDiff(ctx *sql.Context, from, to prolly.Map) error {
+	keyless := schema.IsKeyless(te.fromSch)
+	err := prolly.DiffMaps(ctx, from, to, false, func(_ context.Context, d tree.Diff) error {
+		var before, after sql.Row
+		var err error
+		if d.Type != tree.AddedDiff {
+			if before, err = index.BuildRow(ctx, val.Tuple(d.Key), val.Tuple(d.From), te.fromSch, from.NodeStore()); err != nil {
+				return err
+			}
+		}
+		if d.Type != tree.RemovedDiff {
+			if after, err = index.BuildRow(ctx, val.Tuple(d.Key), val.Tuple(d.To), te.toSch, to.NodeStore()); err != nil {
+				return err
+			}
+		}
+
+		if !keyless {
+			switch d.Type {
+			case tree.AddedDiff:
+				return te.emit(ctx, OpCreate, nil, after)
+			case tree.RemovedDiff:
+				return te.emit(ctx, OpDelete, before, nil)
+			default:
+				return te.emit(ctx, OpUpdate, before, after)
+			}
+		}
+
+		// A keyless row is identified only by its contents, so a change in the number of copies of a row is reported
+		// as that many inserts or deletes.
+		var oldCard, newCard uint64
+		if d.Type != tree.AddedDiff {
+			oldCard = val.ReadKeylessCardinality(val.Tuple(d.From))
+		}
+		if d.Type != tree.RemovedDiff {
+			newCard = val.ReadKeylessCardinality(val.Tuple(d.To))
+		}
+		for ; newCard > oldCard; newCard-- {
+			if err = te.emit(ctx, OpCreate, nil, after); err != nil {
+				return err
+			}
+		}
+		for ; oldCard > newCard; oldCard-- {
+			if err = te.emit(ctx, OpDelete, before, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == io.EOF {
+		err = nil
+	}
+	return err
+}
+
+func (te *tableEvents) emitNomsDiff(ctx *sql.Context, from, to types.Map) error {
+	rd := diff.NewRowDiffer(ctx, from.Format(), te.fromSch, te.toSch, diffBatchSize)
+	rd.Start(ctx, from, to)
+	defer rd.Close()
+
+	for {
+		diffs, more, err := rd.GetDiffs(diffBatchSize, -1)
+		if err != nil {
+			return err
+		}
+		for _, d := range diffs {
+			var before, after sql.Row
+			if d.OldValue != nil {
+				if before, err = nomsSqlRow(te.fromSch, d.KeyValue, d.OldValue); err != nil {
+					return err
+				}
+			}
+			if d.NewValue != nil {
+				if after, err = nomsSqlRow(te.toSch, d.KeyValue, d.NewValue); err != nil {
+					return err
+				}
+			}
+
+			switch d.ChangeType {
+			case types.DiffChangeAdded:
+				err = te.emit(ctx, OpCreate, nil, after)
+			case types.DiffChangeRemoved:
+				err = te.emit(ctx, OpDelete, before, nil)
+			default:
+				err = te.emit(ctx, OpUpdate, before, after)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+func nomsSqlRow(sch schema.Schema, key, value types.Value) (sql.Row, error) {
+	r, err := row.FromNoms(sch, key.(types.Tuple), value.(types.Tuple))
+	if err != nil {
+		return nil, err
+	}
+	return sqlutil.DoltRowToSqlRow(r, sch)
+}
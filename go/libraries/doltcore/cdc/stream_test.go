@@ -0,0 +1,172 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func commitSql(t *testing.T, dEnv *env.DoltEnv, statements string) hash.Hash {
+	ctx := context.Background()
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	root, err = sqle.ExecuteSql(dEnv, root, statements)
+	require.NoError(t, err)
+
+	_, valHash, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := datas.NewCommitMeta("test", "test@test.com", statements)
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.Commit(ctx, valHash, ref.NewBranchRef(env.DefaultInitBranch), meta)
+	require.NoError(t, err)
+	h, err := cm.HashOf()
+	require.NoError(t, err)
+	return h
+}
+
+type change struct {
+	table  string
+	op     cdc.Op
+	before map[string]interface{}
+	after  map[string]interface{}
+}
+
+func changes(events []cdc.Event) []change {
+	var res []change
+	for _, ev := range events {
+		res = append(res, change{table: ev.Source.Table, op: ev.Op, before: ev.Before, after: ev.After})
+	}
+	return res
+}
+
+func TestStreamer(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+	ctx := sql.NewEmptyContext()
+	ddb := dEnv.DoltDB
+
+	first := commitSql(t, dEnv, `create table t (pk int primary key, c varchar(20), d decimal(5,2));
+insert into t values (1, 'one', 1.5), (2, 'two', null);
+create table k (c int);
+insert into k values (7), (7);`)
+
+	sink := &cdc.CollectingSink{}
+	checkpoint := cdc.NewMemoryCheckpoint(hash.Hash{})
+	s := cdc.NewStreamer(ddb, "dolt", env.DefaultInitBranch, sink, checkpoint)
+
+	n, err := s.Poll(ctx)
+	require.NoError(t, err)
+	// the initial commit made by the test environment has no changes
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []change{
+		{table: "k", op: cdc.OpCreate, after: map[string]interface{}{"c": int32(7)}},
+		{table: "k", op: cdc.OpCreate, after: map[string]interface{}{"c": int32(7)}},
+		{table: "t", op: cdc.OpCreate, after: map[string]interface{}{"pk": int32(1), "c": "one", "d": "1.50"}},
+		{table: "t", op: cdc.OpCreate, after: map[string]interface{}{"pk": int32(2), "c": "two", "d": nil}},
+	}, changes(sink.Events))
+	for i, ev := range sink.Events {
+		assert.Equal(t, first.String(), ev.Source.Commit)
+		assert.Equal(t, first.String(), ev.Transaction.ID)
+		assert.Equal(t, int64(i+1), ev.Transaction.TotalOrder)
+		assert.Equal(t, "dolt", ev.Source.Connector)
+	}
+	assert.Equal(t, int64(2), sink.Events[3].Transaction.DataCollectionOrder)
+
+	h, ok, err := checkpoint.Load(ctx)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, first, h)
+
+	n, err = s.Poll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	sink.Events = nil
+	second := commitSql(t, dEnv, `update t set c = 'uno' where pk = 1;
+delete from t where pk = 2;
+delete from k;
+insert into k values (8);`)
+	n, err = s.Poll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []change{
+		// keyless rows are ordered by the hash of their contents
+		{table: "k", op: cdc.OpCreate, after: map[string]interface{}{"c": int32(8)}},
+		{table: "k", op: cdc.OpDelete, before: map[string]interface{}{"c": int32(7)}},
+		{table: "k", op: cdc.OpDelete, before: map[string]interface{}{"c": int32(7)}},
+		{table: "t", op: cdc.OpUpdate,
+			before: map[string]interface{}{"pk": int32(1), "c": "one", "d": "1.50"},
+			after:  map[string]interface{}{"pk": int32(1), "c": "uno", "d": "1.50"}},
+		{table: "t", op: cdc.OpDelete, before: map[string]interface{}{"pk": int32(2), "c": "two", "d": nil}},
+	}, changes(sink.Events))
+	assert.Equal(t, second.String(), sink.Events[0].Source.Commit)
+
+	t.Run("checkpoint not on branch", func(t *testing.T) {
+		s := cdc.NewStreamer(ddb, "dolt", env.DefaultInitBranch, &cdc.CollectingSink{}, cdc.NewMemoryCheckpoint(hash.Of([]byte("nope"))))
+		_, err := s.Poll(ctx)
+		assert.True(t, cdc.ErrCheckpointNotOnBranch.Is(err))
+	})
+
+	t.Run("file sink and checkpoint", func(t *testing.T) {
+		dir := t.TempDir()
+		sink, err := cdc.OpenSink(ctx, filepath.Join(dir, "events.json"), nil)
+		require.NoError(t, err)
+		checkpoint := cdc.NewFileCheckpoint(filepath.Join(dir, "checkpoint"))
+		require.NoError(t, checkpoint.Save(ctx, first))
+
+		_, err = cdc.NewStreamer(ddb, "dolt", env.DefaultInitBranch, sink, checkpoint).Poll(ctx)
+		require.NoError(t, err)
+		require.NoError(t, sink.Close())
+
+		h, ok, err := checkpoint.Load(ctx)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, second, h)
+
+		f, err := os.Open(filepath.Join(dir, "events.json"))
+		require.NoError(t, err)
+		defer f.Close()
+		var ops []cdc.Op
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var ev cdc.Event
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev))
+			ops = append(ops, ev.Op)
+		}
+		assert.Equal(t, []cdc.Op{cdc.OpCreate, cdc.OpDelete, cdc.OpDelete, cdc.OpUpdate, cdc.OpDelete}, ops)
+	})
+
+	t.Run("unknown sink", func(t *testing.T) {
+		_, err := cdc.OpenSink(ctx, "kafka://localhost:9092/topic", nil)
+		assert.True(t, cdc.ErrUnknownSink.Is(err))
+	})
+}
@@ -17,14 +17,17 @@ package cherry_pick
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 // ErrCherryPickUncommittedChanges is returned when a cherry-pick is attempted without a clean working set.
@@ -52,6 +55,10 @@ type CherryPickOptions struct {
 	// and Dolt cherry-pick implementations, the default action is to fail when an empty commit is specified. In Git
 	// and Dolt rebase implementations, the default action is to keep commits that start off as empty.
 	EmptyCommitHandling doltdb.EmptyCommitHandling
+
+	// Mainline is the 1-based number of the parent that the changes of a merge commit are computed against. Zero
+	// means no parent was chosen, in which case cherry-picking a merge commit fails.
+	Mainline int
 }
 
 // NewCherryPickOptions creates a new CherryPickOptions instance, filled out with default values for cherry-pick.
@@ -78,7 +85,7 @@ func CherryPick(ctx *sql.Context, commit string, options CherryPickOptions) (str
 		return "", nil, fmt.Errorf("failed to get roots for current session")
 	}
 
-	mergeResult, commitMsg, err := cherryPick(ctx, doltSession, roots, dbName, commit, options.EmptyCommitHandling)
+	mergeResult, commitMsg, err := cherryPick(ctx, doltSession, roots, dbName, commit, options.EmptyCommitHandling, options.Mainline)
 	if err != nil {
 		return "", mergeResult, err
 	}
@@ -221,10 +228,10 @@ func AbortCherryPick(ctx *sql.Context, dbName string) error {
 	return doltSession.SetWorkingSet(ctx, dbName, newWs)
 }
 
-// cherryPick checks that the current working set is clean, verifies the cherry-pick commit is not a merge commit
-// or a commit without parent commit, performs merge and returns the new working set root value and
-// the commit message of cherry-picked commit as the commit message of the new commit created during this command.
-func cherryPick(ctx *sql.Context, dSess *dsess.DoltSession, roots doltdb.Roots, dbName, cherryStr string, emptyCommitHandling doltdb.EmptyCommitHandling) (*merge.Result, string, error) {
+// cherryPick checks that the current working set is clean, resolves the parent of the cherry-pick commit that its
+// changes are computed against, performs merge and returns the new working set root value and the commit message
+// of cherry-picked commit as the commit message of the new commit created during this command.
+func cherryPick(ctx *sql.Context, dSess *dsess.DoltSession, roots doltdb.Roots, dbName, cherryStr string, emptyCommitHandling doltdb.EmptyCommitHandling, mainline int) (*merge.Result, string, error) {
 	// check for clean working set
 	wsOnlyHasIgnoredTables, err := diff.WorkingSetContainsOnlyIgnoredTables(ctx, roots)
 	if err != nil {
@@ -271,11 +278,9 @@ func cherryPick(ctx *sql.Context, dSess *dsess.DoltSession, roots doltdb.Roots,
 		return nil, "", doltdb.ErrGhostCommitEncountered
 	}
 
-	if len(cherryCommit.DatasParents()) > 1 {
-		return nil, "", fmt.Errorf("cherry-picking a merge commit is not supported")
-	}
-	if len(cherryCommit.DatasParents()) == 0 {
-		return nil, "", fmt.Errorf("cherry-picking a commit without parents is not supported")
+	parentIdx, err := MainlineParentIndex(cherryCommit, mainline)
+	if err != nil {
+		return nil, "", err
 	}
 
 	cherryRoot, err := cherryCommit.GetRootValue(ctx)
@@ -285,7 +290,7 @@ func cherryPick(ctx *sql.Context, dSess *dsess.DoltSession, roots doltdb.Roots,
 
 	// When cherry-picking, we need to use the parent of the cherry-picked commit as the ancestor. This
 	// ensures that only the delta from the cherry-pick commit is applied.
-	optCmt, err = doltDB.ResolveParent(ctx, cherryCommit, 0)
+	optCmt, err = doltDB.ResolveParent(ctx, cherryCommit, parentIdx)
 	if err != nil {
 		return nil, "", err
 	}
@@ -375,6 +380,97 @@ func cherryPick(ctx *sql.Context, dSess *dsess.DoltSession, roots doltdb.Roots,
 	return result, cherryCommitMeta.Description, nil
 }
 
+// MainlineParentIndex returns the index of the parent of |commit| that its changes are computed against when it is
+// cherry-picked, given the 1-based |mainline| parent number chosen by the user, or zero if none was chosen.
+func MainlineParentIndex(commit *doltdb.Commit, mainline int) (int, error) {
+	numParents := len(commit.DatasParents())
+	switch {
+	case mainline < 0:
+		return 0, fmt.Errorf("invalid mainline parent number: %d", mainline)
+	case numParents == 0:
+		return 0, fmt.Errorf("cherry-picking a commit without parents is not supported")
+	case mainline == 0 && numParents > 1:
+		return 0, fmt.Errorf("cherry-picking a merge commit requires choosing a parent with --mainline")
+	case mainline > numParents:
+		return 0, fmt.Errorf("invalid mainline parent number %d: commit has %d parents", mainline, numParents)
+	case mainline == 0:
+		return 0, nil
+	default:
+		return mainline - 1, nil
+	}
+}
+
+// ExpandRevisions resolves the revisions given to cherry-pick into the list of commit hashes to cherry-pick, in
+// order. A revision of the form A..B expands to the commits reachable from B but not from A, oldest first; any
+// other revision is returned as is.
+func ExpandRevisions(ctx *sql.Context, dbName string, revisions []string) ([]string, error) {
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := doltSession.GetDoltDB(ctx, dbName)
+	if !ok {
+		return nil, fmt.Errorf("failed to get DoltDB")
+	}
+	headRef, err := doltSession.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	resolve := func(spec string) (hash.Hash, error) {
+		cs, err := doltdb.NewCommitSpec(spec)
+		if err != nil {
+			return hash.Hash{}, err
+		}
+		optCmt, err := ddb.Resolve(ctx, cs, headRef)
+		if err != nil {
+			return hash.Hash{}, err
+		}
+		cm, ok := optCmt.ToCommit()
+		if !ok {
+			return hash.Hash{}, doltdb.ErrGhostCommitEncountered
+		}
+		return cm.HashOf()
+	}
+
+	var commits []string
+	for _, revision := range revisions {
+		if !strings.Contains(revision, "..") {
+			commits = append(commits, revision)
+			continue
+		}
+
+		refs := strings.Split(revision, "..")
+		if len(refs) != 2 || len(refs[0]) == 0 || len(refs[1]) == 0 {
+			return nil, fmt.Errorf("invalid commit range: %s", revision)
+		}
+		excluded, err := resolve(refs[0])
+		if err != nil {
+			return nil, err
+		}
+		included, err := resolve(refs[1])
+		if err != nil {
+			return nil, err
+		}
+
+		optCmts, err := commitwalk.GetDotDotRevisions(ctx, ddb, []hash.Hash{included}, ddb, []hash.Hash{excluded}, -1)
+		if err != nil {
+			return nil, err
+		}
+		// revisions are returned newest first, but are applied oldest first
+		for i := len(optCmts) - 1; i >= 0; i-- {
+			cm, ok := optCmts[i].ToCommit()
+			if !ok {
+				return nil, doltdb.ErrGhostCommitEncountered
+			}
+			h, err := cm.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			commits = append(commits, h.String())
+		}
+	}
+
+	return commits, nil
+}
+
 func rootsEqual(root1, root2 doltdb.RootValue) (bool, error) {
 	root1Hash, err := root1.HashOf()
 	if err != nil {
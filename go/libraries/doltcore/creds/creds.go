@@ -211,3 +211,19 @@ func (c *RPCCredsForPass) GetRequestMetadata(ctx context.Context, uri ...string)
 func (c *RPCCredsForPass) RequireTransportSecurity() bool {
 	return c.RequireTLS
 }
+
+// RPCCredsForBearer sends a bearer token, such as a JWT issued for a self-hosted remotesapi server, with each RPC.
+type RPCCredsForBearer struct {
+	RequireTLS bool
+	Token      string
+}
+
+func (c *RPCCredsForBearer) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{
+		"authorization": "Bearer " + c.Token,
+	}, nil
+}
+
+func (c *RPCCredsForBearer) RequireTransportSecurity() bool {
+	return c.RequireTLS
+}
@@ -0,0 +1,235 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+	"github.com/dolthub/dolt/go/store/blobstore"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// AzureCredsFileParam is a creation parameter that can be used to specify a credential file to use.
+	AzureCredsFileParam = "azure-creds-file"
+
+	// AzureCredsProfile is a creation parameter that can be used to specify which Azure profile to use.
+	AzureCredsProfile = "azure-creds-profile"
+)
+
+var (
+	emptyAzureCredential = azureCredential{}
+)
+
+type azureParams map[string]interface{}
+type azureCredentials map[string]azureCredential
+
+type azureCredential struct {
+	Endpoint    string `json:"endpoint,omitempty"`
+	AccountName string `json:"accountName,omitempty"`
+	AccountKey  string `json:"accountKey,omitempty"`
+	SASToken    string `json:"sasToken,omitempty"`
+}
+
+// AzureFactory is a DBFactory implementation for creating Azure Blob Storage backed databases
+type AzureFactory struct {
+}
+
+// PrepareDB prepares an Azure backed database
+func (fact AzureFactory) PrepareDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) error {
+	// nothing to prepare
+	return nil
+}
+
+// CreateDB creates an Azure backed database
+func (fact AzureFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) (datas.Database, types.ValueReadWriter, tree.NodeStore, error) {
+	azureStore, err := fact.newChunkStore(ctx, nbf, urlObj, params)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	vrw := types.NewValueStore(azureStore)
+	ns := tree.NewNodeStore(azureStore)
+	db := datas.NewTypesDatabase(vrw, ns)
+
+	return db, vrw, ns, nil
+}
+
+func (fact AzureFactory) newChunkStore(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) (chunks.ChunkStore, error) {
+	// azure://[container]/[path]
+	containerName := urlObj.Hostname()
+	prefix := urlObj.Path
+
+	opts := azureConfigFromParams(params)
+	bs, err := newAzureBlobstore(opts, containerName, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize azure blob store: %w", err)
+	}
+
+	q := nbs.NewUnlimitedMemQuotaProvider()
+	return nbs.NewBSStore(ctx, nbf.VersionString(), bs, defaultMemTableSize, q)
+}
+
+func newAzureBlobstore(opts azureCredential, containerName, prefix string) (*blobstore.AzureBlobstore, error) {
+	accountName := opts.getAccountName()
+	endpoint, err := opts.getEndpoint(accountName)
+	if err != nil {
+		return nil, err
+	}
+	containerURL, err := url.JoinPath(endpoint, containerName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid azure blob storage endpoint '%s': %w", endpoint, err)
+	}
+
+	var client *container.Client
+	if accountKey := opts.getAccountKey(); accountKey != "" {
+		if accountName == "" {
+			return nil, fmt.Errorf("failed to find accountName from cred file or env %s", dconfig.EnvAzureStorageAccount)
+		}
+		cred, err := container.NewSharedKeyCredential(accountName, accountKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid azure storage account key: %w", err)
+		}
+		client, err = container.NewClientWithSharedKeyCredential(containerURL, cred, nil)
+		if err != nil {
+			return nil, err
+		}
+	} else if sasToken := opts.getSASToken(); sasToken != "" {
+		client, err = container.NewClientWithNoCredential(containerURL+"?"+strings.TrimPrefix(sasToken, "?"), nil)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf("failed to find accountKey or sasToken from cred file or env %s or %s", dconfig.EnvAzureStorageKey, dconfig.EnvAzureStorageSASToken)
+	}
+
+	return blobstore.NewAzureBlobstore(client, prefix)
+}
+
+func azureConfigFromParams(params map[string]interface{}) azureCredential {
+	// then we look for config from azure-creds-file
+	p := azureParams(params)
+	credFile, err := p.getCredFile()
+	if err != nil {
+		return emptyAzureCredential
+	}
+	creds, err := readAzureCredentialsFromFile(credFile)
+	if err != nil {
+		return emptyAzureCredential
+	}
+	// if there is only 1 cred in the file, use it whatever the profile is
+	if len(creds) == 1 {
+		return creds.First()
+	}
+	// otherwise, we try to get cred by profile from cred file
+	if res, ok := creds[p.getCredProfile()]; ok {
+		return res
+	}
+	return emptyAzureCredential
+}
+
+func (opt azureCredential) getAccountName() string {
+	if opt.AccountName != "" {
+		return opt.AccountName
+	}
+	return os.Getenv(dconfig.EnvAzureStorageAccount)
+}
+
+func (opt azureCredential) getAccountKey() string {
+	if opt.AccountKey != "" {
+		return opt.AccountKey
+	}
+	return os.Getenv(dconfig.EnvAzureStorageKey)
+}
+
+func (opt azureCredential) getSASToken() string {
+	if opt.SASToken != "" {
+		return opt.SASToken
+	}
+	return os.Getenv(dconfig.EnvAzureStorageSASToken)
+}
+
+// getEndpoint returns the Blob service endpoint from the cred file or env, defaulting to the public Azure endpoint of
+// |accountName|.
+func (opt azureCredential) getEndpoint(accountName string) (string, error) {
+	if opt.Endpoint != "" {
+		return opt.Endpoint, nil
+	}
+	if v := os.Getenv(dconfig.EnvAzureStorageEndpoint); v != "" {
+		return v, nil
+	}
+	if accountName != "" {
+		return fmt.Sprintf("https://%s.blob.core.windows.net", accountName), nil
+	}
+	return "", fmt.Errorf("failed to find endpoint or accountName from cred file or env %s or %s", dconfig.EnvAzureStorageEndpoint, dconfig.EnvAzureStorageAccount)
+}
+
+func readAzureCredentialsFromFile(credFile string) (azureCredentials, error) {
+	data, err := os.ReadFile(credFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read azure cred file %s, err: %s", credFile, err)
+	}
+	var res map[string]azureCredential
+	if err = json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("invalid azure credential file %s, err: %s", credFile, err)
+	}
+	if len(res) == 0 {
+		return nil, errors.New("empty credential file is not allowed")
+	}
+	return res, nil
+}
+
+func (ac azureCredentials) First() azureCredential {
+	var res azureCredential
+	for _, c := range ac {
+		res = c
+		break
+	}
+	return res
+}
+
+func (p azureParams) getCredFile() (string, error) {
+	credFile, ok := p[AzureCredsFileParam]
+	if !ok {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find azure cred file from home dir, err: %s", err)
+		}
+		credFile = filepath.Join(homeDir, ".azure", "dolt_azure_credentials")
+	}
+	return credFile.(string), nil
+}
+
+func (p azureParams) getCredProfile() string {
+	credProfile, ok := p[AzureCredsProfile]
+	if !ok {
+		credProfile = "default"
+	}
+	return credProfile.(string)
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+)
+
+func Test_azureConfigFromParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   azureCredential
+	}{
+		{
+			name:   "not exist",
+			params: map[string]interface{}{AzureCredsFileParam: "testdata/azurecred/missing"},
+			want:   emptyAzureCredential,
+		},
+		{
+			name:   "get default profile",
+			params: map[string]interface{}{AzureCredsFileParam: "testdata/azurecred/dolt_azure_credentials"},
+			want:   azureCredential{AccountName: "defaultaccount", AccountKey: "dGVzdCBrZXk="},
+		},
+		{
+			name: "get cred by profile",
+			params: map[string]interface{}{
+				AzureCredsFileParam: "testdata/azurecred/dolt_azure_credentials",
+				AzureCredsProfile:   "sas",
+			},
+			want: azureCredential{Endpoint: "https://prodaccount.blob.core.windows.net", SASToken: "sv=2021-08-06&sig=test"},
+		},
+		{
+			name: "unknown profile",
+			params: map[string]interface{}{
+				AzureCredsFileParam: "testdata/azurecred/dolt_azure_credentials",
+				AzureCredsProfile:   "staging",
+			},
+			want: emptyAzureCredential,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, azureConfigFromParams(tt.params))
+		})
+	}
+}
+
+func Test_newAzureBlobstore(t *testing.T) {
+	t.Setenv(dconfig.EnvAzureStorageAccount, "")
+	t.Setenv(dconfig.EnvAzureStorageKey, "")
+	t.Setenv(dconfig.EnvAzureStorageSASToken, "")
+	t.Setenv(dconfig.EnvAzureStorageEndpoint, "")
+
+	_, err := newAzureBlobstore(emptyAzureCredential, "container", "/db")
+	assert.Error(t, err)
+
+	bs, err := newAzureBlobstore(azureCredential{AccountName: "acct", AccountKey: "dGVzdCBrZXk="}, "container", "/db")
+	require.NoError(t, err)
+	assert.Equal(t, "container/db", bs.Path())
+
+	_, err = newAzureBlobstore(azureCredential{AccountName: "acct"}, "container", "/db")
+	assert.Error(t, err)
+
+	_, err = newAzureBlobstore(azureCredential{AccountName: "acct", AccountKey: "not base64!"}, "container", "/db")
+	assert.Error(t, err)
+
+	_, err = newAzureBlobstore(azureCredential{AccountKey: "dGVzdCBrZXk="}, "container", "/db")
+	assert.Error(t, err)
+
+	t.Setenv(dconfig.EnvAzureStorageEndpoint, "http://127.0.0.1:10000/devstoreaccount1")
+	t.Setenv(dconfig.EnvAzureStorageSASToken, "sv=2021-08-06&sig=test")
+	bs, err = newAzureBlobstore(emptyAzureCredential, "container", "/db")
+	require.NoError(t, err)
+	assert.Equal(t, "container/db", bs.Path())
+}
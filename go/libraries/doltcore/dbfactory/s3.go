@@ -0,0 +1,109 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// S3EndpointParam is a creation parameter that can be used to set the endpoint of an S3 compatible store, such as
+	// MinIO, Ceph or R2. When it is set, requests use path style addressing.
+	S3EndpointParam = "s3-endpoint"
+
+	// defaultS3EndpointRegion is the region used to sign requests to a custom endpoint when no region is configured,
+	// which S3 compatible stores generally accept.
+	defaultS3EndpointRegion = "us-east-1"
+)
+
+// S3Factory is a DBFactory implementation for creating databases stored entirely in an S3 bucket. Unlike AWSFactory,
+// it keeps the manifest in the bucket and does not need a DynamoDB table.
+type S3Factory struct {
+}
+
+func (fact S3Factory) PrepareDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) error {
+	// nothing to prepare
+	return nil
+}
+
+// CreateDB creates an S3 backed database
+func (fact S3Factory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) (datas.Database, types.ValueReadWriter, tree.NodeStore, error) {
+	cs, err := fact.newChunkStore(ctx, nbf, urlObj, params)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	vrw := types.NewValueStore(cs)
+	ns := tree.NewNodeStore(cs)
+	db := datas.NewTypesDatabase(vrw, ns)
+
+	return db, vrw, ns, nil
+}
+
+func (fact S3Factory) newChunkStore(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) (chunks.ChunkStore, error) {
+	// s3://[bucket]/[path]
+	bucket := urlObj.Hostname()
+	if bucket == "" {
+		return nil, errors.New("s3 url has an invalid format")
+	}
+
+	dbName, err := validatePath(urlObj.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := s3ConfigFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	sess := session.Must(session.NewSessionWithOptions(opts))
+	if opts.Config.Endpoint != nil && aws.StringValue(sess.Config.Region) == "" {
+		sess.Config.Region = aws.String(defaultS3EndpointRegion)
+	}
+	_, err = sess.Config.Credentials.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	q := nbs.NewUnlimitedMemQuotaProvider()
+	return nbs.NewS3Store(ctx, nbf.VersionString(), bucket, dbName, s3.New(sess), defaultMemTableSize, q)
+}
+
+func s3ConfigFromParams(params map[string]interface{}) (session.Options, error) {
+	opts, err := awsConfigFromParams(params)
+	if err != nil {
+		return opts, err
+	}
+
+	if val, ok := params[S3EndpointParam]; ok && len(val.(string)) != 0 {
+		opts.Config.Endpoint = aws.String(val.(string))
+		opts.Config.S3ForcePathStyle = aws.Bool(true)
+	}
+
+	return opts, nil
+}
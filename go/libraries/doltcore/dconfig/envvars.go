@@ -20,6 +20,7 @@ const (
 	EnvSilenceUserReqForTesting      = "DOLT_SILENCE_USER_REQ_FOR_TESTING"
 	EnvOpenAiKey                     = "OPENAI_API_KEY"
 	EnvDoltRemotePassword            = "DOLT_REMOTE_PASSWORD"
+	EnvDoltRemoteToken               = "DOLT_REMOTE_TOKEN"
 	EnvEditor                        = "EDITOR"
 	EnvSqlDebugLogVerbose            = "DOLT_SQL_DEBUG_LOG_VERBOSE"
 	EnvSqlDebugLog                   = "DOLT_SQL_DEBUG_LOG"
@@ -35,6 +36,10 @@ const (
 	EnvOssEndpoint                   = "OSS_ENDPOINT"
 	EnvOssAccessKeyID                = "OSS_ACCESS_KEY_ID"
 	EnvOssAccessKeySecret            = "OSS_ACCESS_KEY_SECRET"
+	EnvAzureStorageAccount           = "AZURE_STORAGE_ACCOUNT"
+	EnvAzureStorageKey               = "AZURE_STORAGE_KEY"
+	EnvAzureStorageSASToken          = "AZURE_STORAGE_SAS_TOKEN"
+	EnvAzureStorageEndpoint          = "AZURE_STORAGE_BLOB_ENDPOINT"
 	EnvVerboseAssertTableFilesClosed = "DOLT_VERBOSE_ASSERT_TABLE_FILES_CLOSED"
 	EnvDisableGcProcedure            = "DOLT_DISABLE_GC_PROCEDURE"
 	EnvEditTableBufferRows           = "DOLT_EDIT_TABLE_BUFFER_ROWS"
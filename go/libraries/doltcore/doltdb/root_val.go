@@ -52,7 +52,7 @@ type FeatureVersion int64
 
 // DoltFeatureVersion is described in feature_version.md.
 // only variable for testing.
-var DoltFeatureVersion FeatureVersion = 7 // last bumped when fixing bug related to GeomAddrs not getting pushed
+var DoltFeatureVersion FeatureVersion = 9 // last bumped when keying constraint violation artifacts by their violation info
 
 // RootValue is the value of the Database and is the committed value in every Dolt or Doltgres commit.
 type RootValue interface {
@@ -596,6 +596,31 @@ func GetTableByColTag(ctx context.Context, root RootValue, tag uint64) (tbl *Tab
 	return tbl, name, found, nil
 }
 
+// GetTableByIdentity retrieves the table named |tName| from |root|. If |root| has no table by that name, it looks for
+// the table that shares a column tag with |sch|, which finds the same table under its name in |root| if the table was
+// renamed.
+func GetTableByIdentity(ctx context.Context, root RootValue, tName TableName, sch schema.Schema) (*Table, bool, error) {
+	tbl, ok, err := root.GetTable(ctx, tName)
+	if err != nil || ok {
+		return tbl, ok, err
+	}
+
+	err = root.IterTables(ctx, func(tn TableName, t *Table, s schema.Schema) (bool, error) {
+		for _, tag := range sch.GetAllCols().Tags {
+			if _, ok = s.GetAllCols().GetByTag(tag); ok {
+				tbl = t
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return tbl, ok, nil
+}
+
 // GetTableNames retrieves the lists of all tables for a RootValue
 func (root *rootValue) GetTableNames(ctx context.Context, schemaName string) ([]string, error) {
 	tableMap, err := root.getTableMap(ctx, schemaName)
@@ -192,6 +192,10 @@ var getWriteableSystemTables = func() []string {
 		ProceduresTableName,
 		IgnoreTableName,
 		GetRebaseTableName(),
+		MaterializedViewsTableName,
+		PoliciesTableName,
+		ColumnMasksTableName,
+		MergeConfigTableName,
 
 		// TODO: find way to make these writable by the dolt process
 		// TODO: but not by user
@@ -288,6 +292,75 @@ const (
 	SchemasTablesSqlModeCol = "sql_mode"
 )
 
+const (
+	// MaterializedViewsTableName is the name of the table storing the definitions of materialized views
+	MaterializedViewsTableName = "dolt_materialized_views"
+	// MaterializedViewsNameCol is the name of the column storing the name of a materialized view, which is also the
+	// name of the table holding its rows
+	MaterializedViewsNameCol = "name"
+	// MaterializedViewsDefinitionCol is the name of the column storing the SELECT statement of a materialized view
+	MaterializedViewsDefinitionCol = "definition"
+	// MaterializedViewsAutoRefreshCol is the name of the column storing whether a materialized view is refreshed on
+	// every commit
+	MaterializedViewsAutoRefreshCol = "auto_refresh"
+	// MaterializedViewsSourceHashCol is the name of the column storing the hash of the source table of a materialized
+	// view as of its last refresh
+	MaterializedViewsSourceHashCol = "source_hash"
+)
+
+const (
+	// PoliciesTableName is the name of the table storing row-level security policies
+	PoliciesTableName = "dolt_policies"
+	// PoliciesNameCol is the name of the column storing the name of a policy
+	PoliciesNameCol = "name"
+	// PoliciesTableNameCol is the name of the column storing the table a policy applies to
+	PoliciesTableNameCol = "table_name"
+	// PoliciesCommandCol is the name of the column storing the statements a policy applies to: all, select, insert,
+	// update or delete
+	PoliciesCommandCol = "command"
+	// PoliciesUserCol is the name of the column storing the pattern of the users a policy applies to
+	PoliciesUserCol = "user"
+	// PoliciesHostCol is the name of the column storing the pattern of the hosts a policy applies to
+	PoliciesHostCol = "host"
+	// PoliciesBranchCol is the name of the column storing the pattern of the branches a policy applies to
+	PoliciesBranchCol = "branch"
+	// PoliciesUsingCol is the name of the column storing the expression that existing rows must satisfy to be read,
+	// updated or deleted
+	PoliciesUsingCol = "using_expr"
+	// PoliciesCheckCol is the name of the column storing the expression that inserted and updated rows must satisfy
+	PoliciesCheckCol = "check_expr"
+)
+
+const (
+	// ColumnMasksTableName is the name of the table storing the masking rules of columns holding sensitive data
+	ColumnMasksTableName = "dolt_column_masks"
+	// ColumnMasksTableNameCol is the name of the column storing the table of a masked column
+	ColumnMasksTableNameCol = "table_name"
+	// ColumnMasksColumnNameCol is the name of the column storing the name of a masked column
+	ColumnMasksColumnNameCol = "column_name"
+	// ColumnMasksTypeCol is the name of the column storing how a column is masked: redact, hash or partial
+	ColumnMasksTypeCol = "mask_type"
+	// ColumnMasksPrefixCol is the name of the column storing the number of leading characters a partial mask leaves
+	// visible
+	ColumnMasksPrefixCol = "visible_prefix"
+	// ColumnMasksSuffixCol is the name of the column storing the number of trailing characters a partial mask leaves
+	// visible
+	ColumnMasksSuffixCol = "visible_suffix"
+)
+
+const (
+	// MergeConfigTableName is the name of the table storing how the columns of each table are merged
+	MergeConfigTableName = "dolt_merge_config"
+	// MergeConfigTableNameCol is the name of the column storing the table a merge setting applies to
+	MergeConfigTableNameCol = "table_name"
+	// MergeConfigColumnNameCol is the name of the column storing the column a merge setting applies to, or the empty
+	// string for a setting that applies to all the columns of the table
+	MergeConfigColumnNameCol = "column_name"
+	// MergeConfigJsonArrayMergeCol is the name of the column storing how concurrent changes to the same array of a JSON
+	// document are merged: conflict, index, set or key:{field}
+	MergeConfigJsonArrayMergeCol = "json_array_merge"
+)
+
 const (
 	// DoltBlameViewPrefix is the prefix assigned to all the generated blame tables
 	DoltBlameViewPrefix = "dolt_blame_"
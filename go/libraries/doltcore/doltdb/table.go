@@ -290,11 +290,11 @@ func (t *Table) getProllyConflictSchemas(ctx context.Context, tblName TableName)
 		return nil, nil, nil, err
 	}
 
-	baseTbl, baseOk, err := tableFromRootIsh(ctx, t.ValueReadWriter(), t.NodeStore(), art.Metadata.BaseRootIsh, tblName)
+	baseTbl, baseOk, err := tableFromRootIsh(ctx, t.ValueReadWriter(), t.NodeStore(), art.Metadata.BaseRootIsh, tblName, ourSch)
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	theirTbl, theirOK, err := tableFromRootIsh(ctx, t.ValueReadWriter(), t.NodeStore(), art.TheirRootIsh, tblName)
+	theirTbl, theirOK, err := tableFromRootIsh(ctx, t.ValueReadWriter(), t.NodeStore(), art.TheirRootIsh, tblName, ourSch)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -310,11 +310,7 @@ func (t *Table) getProllyConflictSchemas(ctx context.Context, tblName TableName)
 	// If the table does not exist in the ancestor, pretend it existed and that
 	// it was completely empty.
 	if !baseOk {
-		if schema.SchemasAreEqual(ourSch, theirSch) {
-			return ourSch, ourSch, theirSch, nil
-		} else {
-			return nil, nil, nil, fmt.Errorf("expected our schema to equal their schema since the table did not exist in the ancestor")
-		}
+		return ourSch, ourSch, theirSch, nil
 	}
 
 	baseSch, err := baseTbl.GetSchema(ctx)
@@ -322,15 +318,39 @@ func (t *Table) getProllyConflictSchemas(ctx context.Context, tblName TableName)
 		return nil, nil, nil, err
 	}
 
+	// If the primary key was redefined on one side of the merge, the merge re-keyed the
+	// rows of the ancestor and the other side through the new primary key.
+	if baseSch, err = rekeyConflictSchema(t.Format(), baseSch, ourSch); err != nil {
+		return nil, nil, nil, err
+	}
+	if theirSch, err = rekeyConflictSchema(t.Format(), theirSch, ourSch); err != nil {
+		return nil, nil, nil, err
+	}
+
 	return baseSch, ourSch, theirSch, nil
 }
 
-func tableFromRootIsh(ctx context.Context, vrw types.ValueReadWriter, ns tree.NodeStore, h hash.Hash, tblName TableName) (*Table, bool, error) {
+// rekeyConflictSchema returns |sch| re-keyed through the primary key of |ourSch| if the primary keys of the two
+// schemas differ, and |sch| otherwise.
+func rekeyConflictSchema(format *types.NomsBinFormat, sch, ourSch schema.Schema) (schema.Schema, error) {
+	if schema.ArePrimaryKeySetsDiffable(format, sch, ourSch) {
+		return sch, nil
+	}
+	rekeyed, ok, err := schema.RekeySchema(sch, ourSch)
+	if err != nil || !ok {
+		return sch, err
+	}
+	return rekeyed, nil
+}
+
+// tableFromRootIsh loads the table named |tblName| from the root at |h|, or the table that shares its identity with
+// |sch| if it was renamed on the other side of the merge.
+func tableFromRootIsh(ctx context.Context, vrw types.ValueReadWriter, ns tree.NodeStore, h hash.Hash, tblName TableName, sch schema.Schema) (*Table, bool, error) {
 	rv, err := LoadRootValueFromRootIshAddr(ctx, vrw, ns, h)
 	if err != nil {
 		return nil, false, err
 	}
-	tbl, ok, err := rv.GetTable(ctx, tblName)
+	tbl, ok, err := GetTableByIdentity(ctx, rv, tblName, sch)
 	if err != nil {
 		return nil, false, err
 	}
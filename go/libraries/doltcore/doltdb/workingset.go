@@ -131,6 +131,17 @@ type MergeState struct {
 	// isCherryPick is set to true when the in-progress merge is a cherry-pick. This is needed so that
 	// commit knows to NOT create a commit with multiple parents when creating a commit for a cherry-pick.
 	isCherryPick bool
+	// pendingCherryPicks are the commits a cherry-pick of a range of commits still has to apply after |commit|,
+	// along with the options to apply them with.
+	pendingCherryPicks   []*Commit
+	cherryPickMainline   int
+	cherryPickAllowEmpty bool
+	// isRevert is set to true when the in-progress merge is a revert of |commit|. Like cherry-picks, reverts
+	// create commits with a single parent. The remaining fields record the progress of the revert.
+	isRevert        bool
+	revertedCommits []*Commit
+	pendingReverts  []*Commit
+	revertMainline  int
 }
 
 // todo(andy): this might make more sense in pkg merge
@@ -177,6 +188,44 @@ func (m MergeState) IsCherryPick() bool {
 	return m.isCherryPick
 }
 
+// PendingCherryPicks returns the commits an in-progress cherry-pick still has to apply after Commit().
+func (m MergeState) PendingCherryPicks() []*Commit {
+	return m.pendingCherryPicks
+}
+
+// CherryPickMainline returns the 1-based number of the parent that an in-progress cherry-pick applies the changes of
+// merge commits relative to, or 0 if none was chosen.
+func (m MergeState) CherryPickMainline() int {
+	return m.cherryPickMainline
+}
+
+// CherryPickAllowEmpty returns whether an in-progress cherry-pick keeps commits that are empty to begin with.
+func (m MergeState) CherryPickAllowEmpty() bool {
+	return m.cherryPickAllowEmpty
+}
+
+// IsRevert returns true if the current merge state is for a revert operation that stopped to resolve the conflicts
+// of reverting Commit().
+func (m MergeState) IsRevert() bool {
+	return m.isRevert
+}
+
+// RevertedCommits returns the commits an in-progress revert reverted before Commit().
+func (m MergeState) RevertedCommits() []*Commit {
+	return m.revertedCommits
+}
+
+// PendingReverts returns the commits an in-progress revert still has to revert after Commit().
+func (m MergeState) PendingReverts() []*Commit {
+	return m.pendingReverts
+}
+
+// RevertMainline returns the 1-based number of the parent that an in-progress revert reverts merge commits relative
+// to, or 0 if merge commits are reverted relative to their first parent.
+func (m MergeState) RevertMainline() int {
+	return m.revertMainline
+}
+
 func (m MergeState) PreMergeWorkingRoot() RootValue {
 	return m.preMergeWorking
 }
@@ -355,6 +404,37 @@ func (ws WorkingSet) StartCherryPick(commit *Commit, commitSpecStr string) *Work
 	return &ws
 }
 
+// WithPendingCherryPicks returns a copy of |ws|, whose cherry-pick in progress records the |pending| commits that are
+// still to be cherry-picked after the commit it stopped at, with the given |mainline| and |allowEmpty| options. Note
+// that this function does not update the current session – the returned WorkingSet must still be set using
+// DoltSession.SetWorkingSet().
+func (ws WorkingSet) WithPendingCherryPicks(pending []*Commit, mainline int, allowEmpty bool) *WorkingSet {
+	mergeState := *ws.mergeState
+	mergeState.pendingCherryPicks = pending
+	mergeState.cherryPickMainline = mainline
+	mergeState.cherryPickAllowEmpty = allowEmpty
+	ws.mergeState = &mergeState
+	return &ws
+}
+
+// StartRevert creates and returns a new working set based off of the current |ws| that records a revert stopped at
+// |commit| to resolve conflicts. |preRevertWorking| is the working root before the revert started, which aborting the
+// revert restores, |reverted| are the commits reverted before |commit| and |pending| the commits to revert after it.
+// Note that this function does not update the current session – the returned WorkingSet must still be set using
+// DoltSession.SetWorkingSet().
+func (ws WorkingSet) StartRevert(commit *Commit, commitSpecStr string, preRevertWorking RootValue, reverted, pending []*Commit, mainline int) *WorkingSet {
+	ws.mergeState = &MergeState{
+		commit:          commit,
+		commitSpecStr:   commitSpecStr,
+		preMergeWorking: preRevertWorking,
+		isRevert:        true,
+		revertedCommits: reverted,
+		pendingReverts:  pending,
+		revertMainline:  mainline,
+	}
+	return &ws
+}
+
 func (ws WorkingSet) AbortMerge() *WorkingSet {
 	ws.workingRoot = ws.mergeState.PreMergeWorkingRoot()
 	ws.stagedRoot = ws.workingRoot
@@ -412,7 +492,7 @@ func (ws *WorkingSet) MergeCommitParents() bool {
 	if !ws.MergeActive() {
 		return false
 	}
-	return ws.MergeState().IsCherryPick() == false
+	return !ws.MergeState().IsCherryPick() && !ws.MergeState().IsRevert()
 }
 
 func (ws WorkingSet) Meta() *datas.WorkingSetMeta {
@@ -511,6 +591,50 @@ func newWorkingSet(ctx context.Context, name string, vrw types.ValueReadWriter,
 			unmergableTables: unmergableTableNames,
 			isCherryPick:     isCherryPick,
 		}
+
+		isRevert, err := dsws.MergeState.IsRevert(ctx, vrw)
+		if err != nil {
+			return nil, err
+		}
+		if isRevert {
+			mergeState.isRevert = true
+			revertedAddrs, err := dsws.MergeState.RevertedCommitAddrs(ctx, vrw)
+			if err != nil {
+				return nil, err
+			}
+			mergeState.revertedCommits, err = loadCommits(ctx, vrw, ns, revertedAddrs)
+			if err != nil {
+				return nil, err
+			}
+			pendingAddrs, err := dsws.MergeState.PendingRevertCommitAddrs(ctx, vrw)
+			if err != nil {
+				return nil, err
+			}
+			mergeState.pendingReverts, err = loadCommits(ctx, vrw, ns, pendingAddrs)
+			if err != nil {
+				return nil, err
+			}
+			mainline, err := dsws.MergeState.RevertMainline(ctx, vrw)
+			if err != nil {
+				return nil, err
+			}
+			mergeState.revertMainline = int(mainline)
+		} else if isCherryPick {
+			pendingAddrs, err := dsws.MergeState.PendingCherryPickCommitAddrs(ctx, vrw)
+			if err != nil {
+				return nil, err
+			}
+			mergeState.pendingCherryPicks, err = loadCommits(ctx, vrw, ns, pendingAddrs)
+			if err != nil {
+				return nil, err
+			}
+			mainline, allowEmpty, err := dsws.MergeState.CherryPickOptions(ctx, vrw)
+			if err != nil {
+				return nil, err
+			}
+			mergeState.cherryPickMainline = int(mainline)
+			mergeState.cherryPickAllowEmpty = allowEmpty
+		}
 	}
 
 	var rebaseState *RebaseState
@@ -625,6 +749,23 @@ func (ws *WorkingSet) writeValues(ctx context.Context, db *DoltDB, meta *datas.W
 		if err != nil {
 			return nil, err
 		}
+		if ws.mergeState.isRevert {
+			reverted, err := commitAddrs(ws.mergeState.revertedCommits)
+			if err != nil {
+				return nil, err
+			}
+			pending, err := commitAddrs(ws.mergeState.pendingReverts)
+			if err != nil {
+				return nil, err
+			}
+			mergeState = mergeState.WithRevert(reverted, pending, uint16(ws.mergeState.revertMainline))
+		} else if ws.mergeState.isCherryPick {
+			pending, err := commitAddrs(ws.mergeState.pendingCherryPicks)
+			if err != nil {
+				return nil, err
+			}
+			mergeState = mergeState.WithCherryPick(pending, uint16(ws.mergeState.cherryPickMainline), ws.mergeState.cherryPickAllowEmpty)
+		}
 	}
 
 	var rebaseState *datas.RebaseState
@@ -657,3 +798,33 @@ func (ws *WorkingSet) writeValues(ctx context.Context, db *DoltDB, meta *datas.W
 		RebaseState: rebaseState,
 	}, nil
 }
+
+func loadCommits(ctx context.Context, vrw types.ValueReadWriter, ns tree.NodeStore, addrs []hash.Hash) ([]*Commit, error) {
+	commits := make([]*Commit, len(addrs))
+	for i, addr := range addrs {
+		dCommit, err := datas.LoadCommitAddr(ctx, vrw, addr)
+		if err != nil {
+			return nil, err
+		}
+		if dCommit.IsGhost() {
+			return nil, ErrGhostCommitEncountered
+		}
+		commits[i], err = NewCommit(ctx, vrw, ns, dCommit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return commits, nil
+}
+
+func commitAddrs(commits []*Commit) ([]hash.Hash, error) {
+	addrs := make([]hash.Hash, len(commits))
+	for i, cm := range commits {
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = h
+	}
+	return addrs, nil
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
 	"github.com/dolthub/dolt/go/libraries/events"
 	"github.com/dolthub/dolt/go/libraries/utils/earl"
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
@@ -104,6 +105,7 @@ func Push(ctx context.Context, tempTableDir string, mode ref.UpdateMode, destRef
 // This includes if there is a new remote branch created, upstream is set or push was rejected for a branch.
 func DoPush(ctx context.Context, pushMeta *env.PushOptions, progStarter ProgStarter, progStopper ProgStopper) (returnMsg string, err error) {
 	var successPush, setUpstreamPush, failedPush []string
+	var rejected *remotestorage.PushRejectedError
 	for _, targets := range pushMeta.Targets {
 		err = push(ctx, pushMeta.Rsr, pushMeta.TmpDir, pushMeta.SrcDb, pushMeta.DestDb, pushMeta.Remote, targets, progStarter, progStopper)
 		if err == nil {
@@ -120,6 +122,9 @@ func DoPush(ctx context.Context, pushMeta *env.PushOptions, progStarter ProgStar
 		} else if errors.Is(err, doltdb.ErrIsAhead) || errors.Is(err, ErrCantFF) || errors.Is(err, datas.ErrMergeNeeded) {
 			failedPush = append(failedPush, fmt.Sprintf(" ! [rejected]            %s -> %s (non-fast-forward)", targets.SrcRef.GetPath(), targets.DestRef.GetPath()))
 			continue
+		} else if errors.As(err, &rejected) {
+			failedPush = append(failedPush, fmt.Sprintf(" ! [remote rejected]     %s -> %s (%s)", targets.SrcRef.GetPath(), targets.DestRef.GetPath(), rejected.Reason))
+			continue
 		} else if !errors.Is(err, doltdb.ErrUpToDate) {
 			// this will allow getting successful push messages along with the error of current push
 			break
@@ -243,6 +248,7 @@ func PushToRemoteBranch(ctx context.Context, rsr env.RepoStateReader, tempTableD
 	err = Push(ctx, tempTableDir, mode, destRef.(ref.BranchRef), remoteRef.(ref.RemoteRef), localDB, remoteDB, cm, statsCh)
 	progStopper(cancelFunc, wg, statsCh)
 
+	var rejected *remotestorage.PushRejectedError
 	switch err {
 	case nil:
 		cli.Println()
@@ -250,6 +256,9 @@ func PushToRemoteBranch(ctx context.Context, rsr env.RepoStateReader, tempTableD
 	case doltdb.ErrUpToDate, doltdb.ErrIsAhead, ErrCantFF, datas.ErrMergeNeeded, datas.ErrDirtyWorkspace, ErrShallowPushImpossible:
 		return err
 	default:
+		if errors.As(err, &rejected) {
+			return err
+		}
 		return fmt.Errorf("%w; %s", ErrUnknownPushErr, err.Error())
 	}
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/signing"
 	"github.com/dolthub/dolt/go/store/datas"
 )
 
@@ -29,6 +30,8 @@ type TagProps struct {
 	TaggerName  string
 	TaggerEmail string
 	Description string
+	// Signing is the configuration used to sign the tag, or nil for an unsigned tag.
+	Signing *signing.Config
 }
 
 func CreateTag(ctx context.Context, dEnv *env.DoltEnv, tagName, startPoint string, props TagProps) error {
@@ -72,6 +75,18 @@ func CreateTagOnDB(ctx context.Context, ddb *doltdb.DoltDB, tagName, startPoint
 
 	meta := datas.NewTagMeta(props.TaggerName, props.TaggerEmail, props.Description)
 
+	if props.Signing != nil {
+		commitHash, err := cm.HashOf()
+		if err != nil {
+			return err
+		}
+
+		meta.Signature, err = signing.Sign(ctx, *props.Signing, signing.TagPayload(tagName, commitHash, meta))
+		if err != nil {
+			return err
+		}
+	}
+
 	return ddb.NewTagAtCommit(ctx, tagRef, cm, meta)
 }
 
@@ -34,6 +34,10 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/grpcendpoint"
 )
 
+// ErrRemoteTokenRequiresTLS is returned when DOLT_REMOTE_TOKEN is set for a remote that is not reached over TLS.
+var ErrRemoteTokenRequiresTLS = errors.New(dconfig.EnvDoltRemoteToken + " is only sent to remotes over TLS, or to a remote on " +
+	"this host. Use an https remote URL")
+
 var defaultDialer = &net.Dialer{
 	Timeout:   30 * time.Second,
 	KeepAlive: 30 * time.Second,
@@ -128,6 +132,13 @@ func (p GRPCDialProvider) GetGRPCDialParams(config grpcendpoint.Config) (dbfacto
 			if err != nil {
 				return dbfactory.GRPCRemoteConfig{}, err
 			}
+		} else if token, ok := os.LookupEnv(dconfig.EnvDoltRemoteToken); ok && token != "" {
+			// A bearer token grants access to anyone who sees it until it expires, so it is only sent in plaintext
+			// to a server on this host
+			if config.Insecure && !isLoopbackEndpoint(endpoint) {
+				return dbfactory.GRPCRemoteConfig{}, ErrRemoteTokenRequiresTLS
+			}
+			rpcCreds = &creds.RPCCredsForBearer{Token: token, RequireTLS: !config.Insecure}
 		} else {
 			rpcCreds, err = p.getRPCCreds(endpoint)
 			if err != nil {
@@ -146,6 +157,19 @@ func (p GRPCDialProvider) GetGRPCDialParams(config grpcendpoint.Config) (dbfacto
 	}, nil
 }
 
+// isLoopbackEndpoint returns whether the host:port |endpoint| names this host.
+func isLoopbackEndpoint(endpoint string) bool {
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 // getRPCCredsFromOSEnv returns RPC Credentials for the specified username, using the DOLT_REMOTE_PASSWORD
 func (p GRPCDialProvider) getRPCCredsFromOSEnv(username string) (credentials.PerRPCCredentials, error) {
 	if username == "" {
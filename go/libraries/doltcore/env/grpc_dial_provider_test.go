@@ -0,0 +1,58 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+	"github.com/dolthub/dolt/go/libraries/doltcore/grpcendpoint"
+)
+
+func TestRemoteTokenRequiresTLS(t *testing.T) {
+	t.Setenv(dconfig.EnvDoltRemoteToken, "token")
+	p := NewGRPCDialProvider()
+
+	tests := []struct {
+		endpoint string
+		insecure bool
+		err      error
+	}{
+		{"remotes.example.com", false, nil},
+		{"remotes.example.com:50051", false, nil},
+		{"remotes.example.com:50051", true, ErrRemoteTokenRequiresTLS},
+		{"10.0.0.1:50051", true, ErrRemoteTokenRequiresTLS},
+		{"localhost:50051", true, nil},
+		{"127.0.0.1:50051", true, nil},
+		{"[::1]:50051", true, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.endpoint, func(t *testing.T) {
+			_, err := p.GetGRPCDialParams(grpcendpoint.Config{
+				Endpoint:     test.endpoint,
+				Insecure:     test.insecure,
+				WithEnvCreds: true,
+			})
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
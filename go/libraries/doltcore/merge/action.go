@@ -46,6 +46,7 @@ type MergeSpec struct {
 	Email           string
 	Name            string
 	Date            time.Time
+	ColumnDefaults  ColumnDefaults
 }
 
 type MergeSpecOpt func(*MergeSpec)
@@ -80,6 +81,12 @@ func WithSquash(squash bool) MergeSpecOpt {
 	}
 }
 
+func WithColumnDefaults(columnDefaults ColumnDefaults) MergeSpecOpt {
+	return func(ms *MergeSpec) {
+		ms.ColumnDefaults = columnDefaults
+	}
+}
+
 // NewMergeSpec returns a MergeSpec with the arguments provided.
 func NewMergeSpec(
 	ctx context.Context,
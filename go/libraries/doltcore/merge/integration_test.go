@@ -31,9 +31,12 @@ import (
 	cmd "github.com/dolthub/dolt/go/cmd/dolt/commands"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/cnfcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	dtu "github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor/creation"
 )
 
 func TestMerge(t *testing.T) {
@@ -146,6 +149,90 @@ func TestMerge(t *testing.T) {
 	}
 }
 
+// TestMergeUpdatesIndexes checks that the secondary indexes a merge updates in place match indexes rebuilt from the
+// merged rows, for index kinds whose stored key descriptors differ from those of their columns.
+func TestMergeUpdatesIndexes(t *testing.T) {
+	tests := []struct {
+		name   string
+		create string
+		base   string
+		ours   string
+		theirs string
+	}{
+		{
+			name:   "text prefix index",
+			create: "CREATE TABLE t (pk int PRIMARY KEY, txt text, INDEX (txt(3)));",
+			base:   "INSERT INTO t VALUES (1, 'aaaa'), (2, 'bbbb');",
+			ours:   "UPDATE t SET txt = 'cccc' WHERE pk = 1;",
+			theirs: "INSERT INTO t VALUES (3, 'dddd'); DELETE FROM t WHERE pk = 2;",
+		},
+		{
+			name:   "spatial index",
+			create: "CREATE TABLE t (pk int PRIMARY KEY, p point NOT NULL SRID 0, SPATIAL INDEX (p));",
+			base:   "INSERT INTO t VALUES (1, point(1, 1)), (2, point(2, 2));",
+			ours:   "UPDATE t SET p = point(3, 3) WHERE pk = 1;",
+			theirs: "INSERT INTO t VALUES (4, point(4, 4)); DELETE FROM t WHERE pk = 2;",
+		},
+		{
+			name:   "keyless table with text prefix index",
+			create: "CREATE TABLE t (c0 int, txt text, INDEX (txt(2)));",
+			base:   "INSERT INTO t VALUES (1, 'aaaa'), (2, 'bbbb');",
+			ours:   "UPDATE t SET txt = 'cccc' WHERE c0 = 1;",
+			theirs: "INSERT INTO t VALUES (3, 'dddd'), (3, 'dddd');",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			dEnv := dtu.CreateTestEnv()
+			defer dEnv.DoltDB.Close()
+
+			commands := []testCommand{
+				{cmd.SqlCmd{}, args{"-q", test.create}},
+				{cmd.SqlCmd{}, args{"-q", test.base}},
+				{cmd.AddCmd{}, args{"."}},
+				{cmd.CommitCmd{}, args{"-am", "base"}},
+				{cmd.BranchCmd{}, args{"other"}},
+				{cmd.SqlCmd{}, args{"-q", test.ours}},
+				{cmd.CommitCmd{}, args{"-am", "ours"}},
+				{cmd.CheckoutCmd{}, args{"other"}},
+				{cmd.SqlCmd{}, args{"-q", test.theirs}},
+				{cmd.CommitCmd{}, args{"-am", "theirs"}},
+				{cmd.CheckoutCmd{}, args{env.DefaultInitBranch}},
+				{cmd.MergeCmd{}, args{"other"}},
+			}
+			for _, tc := range commands {
+				exit := tc.exec(t, ctx, dEnv)
+				require.Equal(t, 0, exit)
+			}
+
+			root, err := dEnv.WorkingRoot(ctx)
+			require.NoError(t, err)
+			tbl, _, err := root.GetTable(ctx, doltdb.TableName{Name: "t"})
+			require.NoError(t, err)
+			sch, err := tbl.GetSchema(ctx)
+			require.NoError(t, err)
+			indexes, err := tbl.GetIndexSet(ctx)
+			require.NoError(t, err)
+
+			sqlCtx := sql.NewContext(ctx)
+			for _, idx := range sch.Indexes().AllIndexes() {
+				merged, err := indexes.GetIndex(ctx, sch, nil, idx.Name())
+				require.NoError(t, err)
+				rebuilt, err := creation.BuildSecondaryIndex(sqlCtx, tbl, idx, "t", editor.Options{})
+				require.NoError(t, err)
+
+				mergedHash, err := merged.HashOf()
+				require.NoError(t, err)
+				rebuiltHash, err := rebuilt.HashOf()
+				require.NoError(t, err)
+				assert.Equal(t, rebuiltHash, mergedHash, "index %s does not match its rebuild", idx.Name())
+			}
+		})
+	}
+}
+
 func TestMergeConflicts(t *testing.T) {
 
 	setupCommon := []testCommand{
@@ -0,0 +1,382 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql/types"
+	errorkinds "gopkg.in/src-d/go-errors.v1"
+)
+
+var ErrInvalidJsonArrayMerge = errorkinds.NewKind("invalid JSON array merge strategy '%s': expected one of conflict, index, set or key:{field}")
+
+// Strategies for merging concurrent changes to the same JSON array
+const (
+	// JsonArrayMergeConflict reports concurrent changes to the same array as a conflict. This is the default.
+	JsonArrayMergeConflict = "conflict"
+	// JsonArrayMergeIndex merges arrays element by element, matching the elements of each version by their position.
+	JsonArrayMergeIndex = "index"
+	// JsonArrayMergeSet merges arrays as sets of values: values added on either side are added, and values removed on
+	// either side are removed. Concurrent changes to the same array never conflict.
+	JsonArrayMergeSet = "set"
+	// JsonArrayMergeKey merges arrays of objects, matching the elements of each version by the value of an identity
+	// field such as "id".
+	JsonArrayMergeKey = "key"
+)
+
+// JsonArrayMerge is a strategy for merging concurrent changes to the same JSON array. Changes to different keys of a
+// JSON object are always merged, whatever the strategy.
+type JsonArrayMerge struct {
+	// Strategy is one of the JsonArrayMerge constants
+	Strategy string
+	// Key is the identity field of the elements of arrays merged with JsonArrayMergeKey
+	Key string
+}
+
+// ParseJsonArrayMerge parses a JSON array merge strategy: conflict, index, set, or key:{field}. An empty string is
+// the default conflict strategy.
+func ParseJsonArrayMerge(s string) (JsonArrayMerge, error) {
+	strategy, key, hasKey := strings.Cut(strings.TrimSpace(s), ":")
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	key = strings.TrimSpace(key)
+	switch {
+	case strategy == "" && !hasKey:
+		return JsonArrayMerge{Strategy: JsonArrayMergeConflict}, nil
+	case strategy == JsonArrayMergeKey && key != "":
+		return JsonArrayMerge{Strategy: JsonArrayMergeKey, Key: key}, nil
+	case !hasKey && (strategy == JsonArrayMergeConflict || strategy == JsonArrayMergeIndex || strategy == JsonArrayMergeSet):
+		return JsonArrayMerge{Strategy: strategy}, nil
+	default:
+		return JsonArrayMerge{}, ErrInvalidJsonArrayMerge.New(s)
+	}
+}
+
+// String returns the strategy in the form accepted by ParseJsonArrayMerge.
+func (am JsonArrayMerge) String() string {
+	if am.Strategy == JsonArrayMergeKey {
+		return am.Strategy + ":" + am.Key
+	}
+	if am.Strategy == "" {
+		return JsonArrayMergeConflict
+	}
+	return am.Strategy
+}
+
+// mergesArrays returns whether concurrent changes to the same array are merged rather than reported as conflicts.
+func (am JsonArrayMerge) mergesArrays() bool {
+	return am.Strategy != "" && am.Strategy != JsonArrayMergeConflict
+}
+
+// jsonValue is a JSON value decoded into memory, or the absence of a value at some location of a document.
+type jsonValue struct {
+	val    interface{}
+	exists bool
+}
+
+// jsonMerge is an in-memory three-way merge of JSON values. It is used for the values of the documents whose
+// concurrent changes can't be merged by the ThreeWayJsonDiffer location by location, which are changes to the same
+// array. Objects are merged key by key and arrays with the array merge strategy, and the paths of the values that
+// couldn't be merged are collected in |conflicts|.
+type jsonMerge struct {
+	arrayMerge JsonArrayMerge
+	conflicts  []string
+}
+
+// merge merges the values |left| and |right| at |path|, both derived from |base|.
+func (jm *jsonMerge) merge(path string, base, left, right jsonValue) (jsonValue, error) {
+	if eq, err := jsonValuesEqual(left, right); err != nil || eq {
+		return left, err
+	}
+	if eq, err := jsonValuesEqual(base, left); err != nil || eq {
+		return right, err
+	}
+	if eq, err := jsonValuesEqual(base, right); err != nil || eq {
+		return left, err
+	}
+
+	if base.exists && left.exists && right.exists {
+		baseObj, baseIsObj := base.val.(types.JsonObject)
+		leftObj, leftIsObj := left.val.(types.JsonObject)
+		rightObj, rightIsObj := right.val.(types.JsonObject)
+		if baseIsObj && leftIsObj && rightIsObj {
+			return jm.mergeObjects(path, baseObj, leftObj, rightObj)
+		}
+
+		baseArr, baseIsArr := base.val.(types.JsonArray)
+		leftArr, leftIsArr := left.val.(types.JsonArray)
+		rightArr, rightIsArr := right.val.(types.JsonArray)
+		if baseIsArr && leftIsArr && rightIsArr {
+			switch jm.arrayMerge.Strategy {
+			case JsonArrayMergeIndex:
+				return jm.mergeArraysByIndex(path, baseArr, leftArr, rightArr)
+			case JsonArrayMergeSet:
+				return mergeArraysAsSets(baseArr, leftArr, rightArr)
+			case JsonArrayMergeKey:
+				return jm.mergeArraysByKey(path, baseArr, leftArr, rightArr)
+			}
+		}
+	}
+
+	jm.conflicts = append(jm.conflicts, path)
+	return left, nil
+}
+
+func (jm *jsonMerge) mergeObjects(path string, base, left, right types.JsonObject) (jsonValue, error) {
+	keys := make(map[string]struct{}, len(left))
+	for _, obj := range []types.JsonObject{base, left, right} {
+		for k := range obj {
+			keys[k] = struct{}{}
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	merged := make(types.JsonObject, len(keys))
+	for _, k := range sorted {
+		v, err := jm.merge(jsonObjectPath(path, k), objectValue(base, k), objectValue(left, k), objectValue(right, k))
+		if err != nil {
+			return jsonValue{}, err
+		}
+		if v.exists {
+			merged[k] = v.val
+		}
+	}
+	return jsonValue{val: merged, exists: true}, nil
+}
+
+// mergeArraysByIndex merges the elements of the arrays at each position. Elements added to the end of the array on
+// one side are kept, but the merged array can't have a gap where one side removed elements that the other side
+// changed or added after.
+func (jm *jsonMerge) mergeArraysByIndex(path string, base, left, right types.JsonArray) (jsonValue, error) {
+	n := max(len(left), len(right))
+	merged := make(types.JsonArray, 0, n)
+	end := -1
+	for i := 0; i < n; i++ {
+		elemPath := fmt.Sprintf("%s[%d]", path, i)
+		v, err := jm.merge(elemPath, arrayValue(base, i), arrayValue(left, i), arrayValue(right, i))
+		if err != nil {
+			return jsonValue{}, err
+		}
+		if !v.exists {
+			if end < 0 {
+				end = i
+			}
+			continue
+		}
+		if end >= 0 {
+			jm.conflicts = append(jm.conflicts, elemPath)
+			continue
+		}
+		merged = append(merged, v.val)
+	}
+	return jsonValue{val: merged, exists: true}, nil
+}
+
+// mergeArraysAsSets removes the values of |left| that were removed from |right|, and appends the values that were
+// added to |right| and that |left| doesn't have. The values are compared by their normalized JSON serialization.
+func mergeArraysAsSets(base, left, right types.JsonArray) (jsonValue, error) {
+	baseSet, err := jsonValueSet(base)
+	if err != nil {
+		return jsonValue{}, err
+	}
+	leftSet, err := jsonValueSet(left)
+	if err != nil {
+		return jsonValue{}, err
+	}
+	rightSet, err := jsonValueSet(right)
+	if err != nil {
+		return jsonValue{}, err
+	}
+
+	merged := make(types.JsonArray, 0, len(left))
+	for _, v := range left {
+		k, err := jsonIdentity(v)
+		if err != nil {
+			return jsonValue{}, err
+		}
+		_, inBase := baseSet[k]
+		_, inRight := rightSet[k]
+		if inBase && !inRight {
+			continue
+		}
+		merged = append(merged, v)
+	}
+	for _, v := range right {
+		k, err := jsonIdentity(v)
+		if err != nil {
+			return jsonValue{}, err
+		}
+		_, inBase := baseSet[k]
+		_, inLeft := leftSet[k]
+		if inBase || inLeft {
+			continue
+		}
+		merged = append(merged, v)
+		leftSet[k] = struct{}{}
+	}
+	return jsonValue{val: merged, exists: true}, nil
+}
+
+// mergeArraysByKey merges elements with the same identity key. The merged array has the elements of |left| in their
+// order, followed by the elements added to |right|. The path of an element is given by its position in |left|, or in
+// |right| for the elements that were added there.
+func (jm *jsonMerge) mergeArraysByKey(path string, base, left, right types.JsonArray) (jsonValue, error) {
+	baseIdx, ok, err := jm.indexByKey(base)
+	if err != nil || !ok {
+		return jm.unkeyedArray(path, left, err)
+	}
+	leftIdx, ok, err := jm.indexByKey(left)
+	if err != nil || !ok {
+		return jm.unkeyedArray(path, left, err)
+	}
+	rightIdx, ok, err := jm.indexByKey(right)
+	if err != nil || !ok {
+		return jm.unkeyedArray(path, left, err)
+	}
+
+	merged := make(types.JsonArray, 0, len(left))
+	for i, v := range left {
+		k, err := jsonIdentity(v.(types.JsonObject)[jm.arrayMerge.Key])
+		if err != nil {
+			return jsonValue{}, err
+		}
+		mv, err := jm.merge(fmt.Sprintf("%s[%d]", path, i), keyedValue(base, baseIdx, k), jsonValue{val: v, exists: true}, keyedValue(right, rightIdx, k))
+		if err != nil {
+			return jsonValue{}, err
+		}
+		if mv.exists {
+			merged = append(merged, mv.val)
+		}
+	}
+	for i, v := range right {
+		k, err := jsonIdentity(v.(types.JsonObject)[jm.arrayMerge.Key])
+		if err != nil {
+			return jsonValue{}, err
+		}
+		if _, ok := leftIdx[k]; ok {
+			continue
+		}
+		mv, err := jm.merge(fmt.Sprintf("%s[%d]", path, i), keyedValue(base, baseIdx, k), jsonValue{}, jsonValue{val: v, exists: true})
+		if err != nil {
+			return jsonValue{}, err
+		}
+		if mv.exists {
+			merged = append(merged, mv.val)
+		}
+	}
+	return jsonValue{val: merged, exists: true}, nil
+}
+
+// indexByKey maps the identity of the key field of each element of |arr| to its position. Returns false if an element
+// isn't an object with a key field, or if two elements have the same key.
+func (jm *jsonMerge) indexByKey(arr types.JsonArray) (map[string]int, bool, error) {
+	idx := make(map[string]int, len(arr))
+	for i, v := range arr {
+		obj, ok := v.(types.JsonObject)
+		if !ok {
+			return nil, false, nil
+		}
+		kv, ok := obj[jm.arrayMerge.Key]
+		if !ok {
+			return nil, false, nil
+		}
+		k, err := jsonIdentity(kv)
+		if err != nil {
+			return nil, false, err
+		}
+		if _, ok := idx[k]; ok {
+			return nil, false, nil
+		}
+		idx[k] = i
+	}
+	return idx, true, nil
+}
+
+// unkeyedArray records a conflict for an array that can't be merged by key.
+func (jm *jsonMerge) unkeyedArray(path string, left types.JsonArray, err error) (jsonValue, error) {
+	if err != nil {
+		return jsonValue{}, err
+	}
+	jm.conflicts = append(jm.conflicts, path)
+	return jsonValue{val: left, exists: true}, nil
+}
+
+func objectValue(obj types.JsonObject, key string) jsonValue {
+	v, ok := obj[key]
+	return jsonValue{val: v, exists: ok}
+}
+
+func arrayValue(arr types.JsonArray, i int) jsonValue {
+	if i >= len(arr) {
+		return jsonValue{}
+	}
+	return jsonValue{val: arr[i], exists: true}
+}
+
+func keyedValue(arr types.JsonArray, idx map[string]int, key string) jsonValue {
+	i, ok := idx[key]
+	if !ok {
+		return jsonValue{}
+	}
+	return jsonValue{val: arr[i], exists: true}
+}
+
+func jsonValuesEqual(a, b jsonValue) (bool, error) {
+	if !a.exists || !b.exists {
+		return a.exists == b.exists, nil
+	}
+	cmp, err := types.CompareJSON(a.val, b.val)
+	return cmp == 0, err
+}
+
+// jsonIdentity returns the normalized serialization of |v|, which is the same for equal JSON values.
+func jsonIdentity(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func jsonValueSet(arr types.JsonArray) (map[string]struct{}, error) {
+	set := make(map[string]struct{}, len(arr))
+	for _, v := range arr {
+		k, err := jsonIdentity(v)
+		if err != nil {
+			return nil, err
+		}
+		set[k] = struct{}{}
+	}
+	return set, nil
+}
+
+// jsonObjectPath appends the object key |key| to the JSON path expression |path|, quoting it if necessary.
+func jsonObjectPath(path, key string) string {
+	simple := key != "" && !(key[0] >= '0' && key[0] <= '9')
+	for _, c := range key {
+		if !(c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			simple = false
+			break
+		}
+	}
+	if simple {
+		return path + "." + key
+	}
+	return path + "." + fmt.Sprintf("%q", key)
+}
@@ -0,0 +1,199 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+)
+
+func TestParseJsonArrayMerge(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected JsonArrayMerge
+		invalid  bool
+	}{
+		{input: "", expected: JsonArrayMerge{Strategy: JsonArrayMergeConflict}},
+		{input: "conflict", expected: JsonArrayMerge{Strategy: JsonArrayMergeConflict}},
+		{input: "INDEX", expected: JsonArrayMerge{Strategy: JsonArrayMergeIndex}},
+		{input: " set ", expected: JsonArrayMerge{Strategy: JsonArrayMergeSet}},
+		{input: "key:id", expected: JsonArrayMerge{Strategy: JsonArrayMergeKey, Key: "id"}},
+		{input: "Key: Name", expected: JsonArrayMerge{Strategy: JsonArrayMergeKey, Key: "Name"}},
+		{input: "key", invalid: true},
+		{input: "key:", invalid: true},
+		{input: "set:id", invalid: true},
+		{input: "union", invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			am, err := ParseJsonArrayMerge(test.input)
+			if test.invalid {
+				require.Error(t, err)
+				assert.True(t, ErrInvalidJsonArrayMerge.Is(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, am)
+
+			roundTrip, err := ParseJsonArrayMerge(am.String())
+			require.NoError(t, err)
+			assert.Equal(t, am, roundTrip)
+		})
+	}
+}
+
+func TestThreeWayMergeJSONArrays(t *testing.T) {
+	tests := []struct {
+		name              string
+		strategy          string
+		base, left, right string
+		merged            string
+		conflicts         []string
+	}{
+		{
+			name:      "conflict strategy reports concurrent array changes",
+			strategy:  "conflict",
+			base:      `{"a": [1, 2]}`,
+			left:      `{"a": [1, 2, 3]}`,
+			right:     `{"a": [0, 2]}`,
+			conflicts: []string{"$.a"},
+		},
+		{
+			name:     "index strategy merges changes to different elements",
+			strategy: "index",
+			base:     `{"a": [1, 2]}`,
+			left:     `{"a": [1, 3]}`,
+			right:    `{"a": [0, 2, 4]}`,
+			merged:   `{"a": [0, 3, 4]}`,
+		},
+		{
+			name:      "index strategy reports changes to the same element",
+			strategy:  "index",
+			base:      `{"a": [1, 2]}`,
+			left:      `{"a": [1, 3]}`,
+			right:     `{"a": [1, 4]}`,
+			conflicts: []string{"$.a[1]"},
+		},
+		{
+			name:     "index strategy merges the objects of an array",
+			strategy: "index",
+			base:     `{"a": [{"x": 1, "y": 1}]}`,
+			left:     `{"a": [{"x": 2, "y": 1}]}`,
+			right:    `{"a": [{"x": 1, "y": 2}]}`,
+			merged:   `{"a": [{"x": 2, "y": 2}]}`,
+		},
+		{
+			name:      "index strategy reports gaps in the merged array",
+			strategy:  "index",
+			base:      `{"a": [1, 2, 3]}`,
+			left:      `{"a": [1]}`,
+			right:     `{"a": [1, 2, 4]}`,
+			conflicts: []string{"$.a[2]"},
+		},
+		{
+			name:     "set strategy merges additions and removals",
+			strategy: "set",
+			base:     `{"tags": ["a", "b", "c"]}`,
+			left:     `{"tags": ["a", "c", "d"]}`,
+			right:    `{"tags": ["e", "b", "a"]}`,
+			merged:   `{"tags": ["a", "d", "e"]}`,
+		},
+		{
+			name:     "set strategy does not duplicate values added on both sides",
+			strategy: "set",
+			base:     `{"tags": []}`,
+			left:     `{"tags": ["a", {"b": 1}]}`,
+			right:    `{"tags": [{"b": 1}, "c"]}`,
+			merged:   `{"tags": ["a", {"b": 1}, "c"]}`,
+		},
+		{
+			name:     "key strategy merges elements with the same key",
+			strategy: "key:id",
+			base:     `{"items": [{"id": 1, "qty": 1, "name": "x"}, {"id": 2, "qty": 1}]}`,
+			left:     `{"items": [{"id": 2, "qty": 1}, {"id": 1, "qty": 5, "name": "x"}]}`,
+			right:    `{"items": [{"id": 1, "qty": 1, "name": "y"}, {"id": 3, "qty": 1}]}`,
+			merged:   `{"items": [{"id": 1, "qty": 5, "name": "y"}, {"id": 3, "qty": 1}]}`,
+		},
+		{
+			name:      "key strategy reports changes to the same field of an element",
+			strategy:  "key:id",
+			base:      `{"items": [{"id": 1, "qty": 1}]}`,
+			left:      `{"items": [{"id": 0}, {"id": 1, "qty": 2}]}`,
+			right:     `{"items": [{"id": 1, "qty": 3}]}`,
+			conflicts: []string{"$.items[1].qty"},
+		},
+		{
+			name:      "key strategy reports arrays with elements missing the key",
+			strategy:  "key:id",
+			base:      `{"items": [{"id": 1}]}`,
+			left:      `{"items": [{"id": 1}, {"name": "x"}]}`,
+			right:     `{"items": [{"id": 1}, {"id": 2}]}`,
+			conflicts: []string{"$.items"},
+		},
+		{
+			name:     "top-level arrays",
+			strategy: "set",
+			base:     `[1, 2]`,
+			left:     `[1, 2, 3]`,
+			right:    `[2]`,
+			merged:   `[2, 3]`,
+		},
+		{
+			name:      "all conflicts are reported",
+			strategy:  "index",
+			base:      `{"a": [1], "b": {"c": 1}, "d": "x"}`,
+			left:      `{"a": [2], "b": {"c": 2}, "d": "y"}`,
+			right:     `{"a": [3], "b": {"c": 3}, "d": "z"}`,
+			conflicts: []string{"$.a[0]", "$.b.c", "$.d"},
+		},
+	}
+
+	ctx := context.Background()
+	ns := tree.NewTestNodeStore()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			am, err := ParseJsonArrayMerge(test.strategy)
+			require.NoError(t, err)
+			base, left, right := jsonDoc(t, test.base), jsonDoc(t, test.left), jsonDoc(t, test.right)
+
+			merged, conflicts, err := threeWayMergeJSON(ctx, ns, base, left, right, am, true)
+			require.NoError(t, err)
+			assert.Equal(t, test.conflicts, conflicts)
+			if test.merged != "" {
+				cmp, err := types.CompareJSON(jsonDoc(t, test.merged), merged)
+				require.NoError(t, err)
+				assert.Zero(t, cmp, "unexpected merge result %v", merged)
+			}
+
+			paths, err := JsonConflictPaths(ctx, ns, base, left, right, am)
+			require.NoError(t, err)
+			assert.Equal(t, test.conflicts, paths)
+		})
+	}
+}
+
+func jsonDoc(t *testing.T, s string) types.JSONDocument {
+	var val interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &val))
+	return types.JSONDocument{Val: val}
+}
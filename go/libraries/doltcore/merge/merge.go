@@ -44,11 +44,17 @@ var ErrCantOverwriteConflicts = errors.New("existing unresolved conflicts would
 var ErrConflictsIncompatible = errors.New("the existing conflicts are of a different schema" +
 	" than the conflicts generated by this merge. Please resolve them and try again")
 
-var ErrMultipleViolationsForRow = errors.New("multiple violations for row not supported")
-
 var ErrSameTblAddedTwice = goerrors.NewKind("table with same name '%s' added in 2 commits can't be merged")
 
-func MergeCommits(ctx *sql.Context, commit, mergeCommit *doltdb.Commit, opts editor.Options) (*Result, error) {
+var ErrOctopusMergeConflict = goerrors.NewKind("Merge with strategy octopus failed: merging '%s' produced conflicts or constraint violations. " +
+	"No changes were made; merge the branches one at a time to resolve them.")
+
+var ErrOctopusMergeNoBase = goerrors.NewKind("Merge with strategy octopus failed: '%s' has no single merge base with the branches merged before it. " +
+	"No changes were made; merge the branches one at a time.")
+
+// MergeCommits merges |mergeCommit| into |commit| against their merge base. |columnDefaults| optionally provides
+// expressions that populate existing rows for columns added on one side of the merge.
+func MergeCommits(ctx *sql.Context, commit, mergeCommit *doltdb.Commit, opts editor.Options, columnDefaults ColumnDefaults) (*Result, error) {
 	optCmt, err := doltdb.GetCommitAncestor(ctx, commit, mergeCommit)
 	if err != nil {
 		return nil, err
@@ -77,10 +83,91 @@ func MergeCommits(ctx *sql.Context, commit, mergeCommit *doltdb.Commit, opts edi
 	mo := MergeOpts{
 		IsCherryPick:        false,
 		KeepSchemaConflicts: true,
+		ColumnDefaults:      columnDefaults,
 	}
 	return MergeRoots(ctx, ourRoot, theirRoot, ancRoot, mergeCommit, ancCommit, opts, mo)
 }
 
+// MergeOctopus merges each of |mergeCommits| into |commit| in turn and returns the merged root, for an octopus merge
+// that records all of the merged commits as parents of a single merge commit. Each commit is merged against its merge
+// base with everything merged before it: |commit| and the commits that precede it in |mergeCommits|. An octopus merge
+// can't record conflicts, so the first commit that doesn't merge cleanly ends the merge with ErrOctopusMergeConflict,
+// naming that commit's entry in |mergeCommitSpecs|.
+func MergeOctopus(ctx *sql.Context, commit *doltdb.Commit, mergeCommits []*doltdb.Commit, mergeCommitSpecs []string, opts editor.Options, columnDefaults ColumnDefaults) (doltdb.RootValue, error) {
+	root, err := commit.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mo := MergeOpts{
+		IsCherryPick:        false,
+		KeepSchemaConflicts: true,
+		ColumnDefaults:      columnDefaults,
+	}
+	merged := []*doltdb.Commit{commit}
+	for i, mergeCommit := range mergeCommits {
+		ancCommit, err := octopusMergeBase(ctx, merged, mergeCommit, mergeCommitSpecs[i])
+		if err != nil {
+			return nil, err
+		}
+
+		theirRoot, err := mergeCommit.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ancRoot, err := ancCommit.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := MergeRoots(ctx, root, theirRoot, ancRoot, mergeCommit, ancCommit, opts, mo)
+		if err != nil {
+			return nil, err
+		}
+		if result.HasMergeArtifacts() {
+			return nil, ErrOctopusMergeConflict.New(mergeCommitSpecs[i])
+		}
+		root = result.Root
+		merged = append(merged, mergeCommit)
+	}
+
+	return root, nil
+}
+
+// octopusMergeBase returns the merge base of |mergeCommit| with the result of merging all of |merged|, which is the
+// latest of its merge bases with each of them. When none of those merge bases descends from all of the others, there
+// is no single merge base to merge |mergeCommit| against.
+func octopusMergeBase(ctx *sql.Context, merged []*doltdb.Commit, mergeCommit *doltdb.Commit, mergeCommitSpec string) (*doltdb.Commit, error) {
+	var base *doltdb.Commit
+	for _, cm := range merged {
+		optCmt, err := doltdb.GetCommitAncestor(ctx, cm, mergeCommit)
+		if err != nil {
+			return nil, err
+		}
+		ancCommit, ok := optCmt.ToCommit()
+		if !ok {
+			return nil, doltdb.ErrGhostCommitRuntimeFailure
+		}
+		if base == nil {
+			base = ancCommit
+			continue
+		}
+
+		// keep whichever of the two merge bases descends from the other
+		canFF, err := base.CanFastForwardTo(ctx, ancCommit)
+		switch {
+		case err == doltdb.ErrUpToDate || err == doltdb.ErrIsAhead:
+		case err != nil:
+			return nil, err
+		case canFF:
+			base = ancCommit
+		default:
+			return nil, ErrOctopusMergeNoBase.New(mergeCommitSpec)
+		}
+	}
+	return base, nil
+}
+
 type Result struct {
 	Root            doltdb.RootValue
 	SchemaConflicts []SchemaConflict
@@ -186,6 +273,12 @@ func MergeRoots(
 		}
 	}
 
+	// Apply tables renamed on one side of the merge to the other side, so that tables can be merged by name below
+	ourRoot, theirRoot, ancRoot, err = mergeTableRenames(ctx, ourRoot, theirRoot, ancRoot)
+	if err != nil {
+		return nil, err
+	}
+
 	// merge collations
 	oColl, err := ourRoot.GetCollation(ctx)
 	if err != nil {
@@ -220,7 +313,8 @@ func MergeRoots(
 	}
 
 	// Make sure to pass in ourRoot as the first RootValue so that ourRoot's table names will be merged first.
-	// This helps to avoid non-deterministic error result for table rename cases. Renaming a table creates two changes:
+	// This helps to avoid non-deterministic error result for table rename cases that mergeTableRenames can't
+	// apply, such as a table renamed differently on each side. Renaming a table creates two changes:
 	// 1. dropping the old name table
 	// 2. adding the new name table
 	// Dropping the old name table will trigger delete/modify conflict, which is the preferred error case over
@@ -233,9 +327,8 @@ func MergeRoots(
 
 	tblToStats := make(map[doltdb.TableName]*MergeStats)
 
-	// Merge tables one at a time. This is done based on name. With table names from ourRoot being merged first,
-	// renaming a table will return delete/modify conflict error consistently.
-	// TODO: merge based on a more durable table identity that persists across renames
+	// Merge tables one at a time. This is done based on name, after renames on either side have been applied to
+	// the other side.
 	merger, err := NewMerger(ourRoot, theirRoot, ancRoot, theirs, ancestor, ourRoot.VRW(), ourRoot.NodeStore())
 	if err != nil {
 		return nil, err
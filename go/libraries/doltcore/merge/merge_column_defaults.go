@@ -0,0 +1,158 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"strings"
+
+	errorkinds "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+var ErrInvalidColumnDefault = errorkinds.NewKind("invalid column default '%s': expected {table}.{column}={expression}")
+
+// ColumnDefaults holds expressions that populate a column added on one side of a merge for the existing rows of the
+// other side, keyed by the lower-cased table name and then by the lower-cased column name. They allow merging a new
+// NOT NULL column that has no default value, which would otherwise fail with ErrUnmergeableNewColumn. The expressions
+// are only used to backfill rows during the merge; the merged column definition is unchanged.
+type ColumnDefaults map[doltdb.TableName]map[string]string
+
+// ParseColumnDefaults parses a comma-separated list of {table}.{column}={expression} assignments. Commas inside
+// parentheses or quotes are part of the expression.
+func ParseColumnDefaults(s string) (ColumnDefaults, error) {
+	defaults := make(ColumnDefaults)
+	for _, assignment := range splitColumnDefaults(s) {
+		assignment = strings.TrimSpace(assignment)
+		column, expr, ok := strings.Cut(assignment, "=")
+		if !ok {
+			return nil, ErrInvalidColumnDefault.New(assignment)
+		}
+		column, expr = strings.TrimSpace(column), strings.TrimSpace(expr)
+
+		dot := strings.LastIndex(column, ".")
+		if dot <= 0 || dot == len(column)-1 || expr == "" {
+			return nil, ErrInvalidColumnDefault.New(assignment)
+		}
+		tblName := doltdb.TableName{Name: column[:dot]}.ToLower()
+		colName := strings.ToLower(column[dot+1:])
+
+		if defaults[tblName] == nil {
+			defaults[tblName] = make(map[string]string)
+		}
+		defaults[tblName][colName] = expr
+	}
+	return defaults, nil
+}
+
+// splitColumnDefaults splits |s| on the commas that aren't inside parentheses or quotes.
+func splitColumnDefaults(s string) []string {
+	var parts []string
+	var quote rune
+	depth, start := 0, 0
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// forTable returns the column defaults for the table |name|, keyed by lower-cased column name.
+func (cd ColumnDefaults) forTable(name doltdb.TableName) map[string]string {
+	if len(cd) == 0 {
+		return nil
+	}
+	return cd[name.ToLower()]
+}
+
+// applyColumnDefaults sets the expressions in |defaults| as the default values of the columns of |sch| that don't
+// exist in |ancSch| or |otherSch|, and that have no default value or generated value. Returns the updated schema and
+// the tags of the columns that were given a default value.
+func applyColumnDefaults(sch, otherSch, ancSch schema.Schema, defaults map[string]string) (schema.Schema, []uint64, error) {
+	if len(defaults) == 0 || sch == nil {
+		return sch, nil, nil
+	}
+
+	var tags []uint64
+	cols := sch.GetAllCols().GetColumns()
+	for i, col := range cols {
+		expr, ok := defaults[strings.ToLower(col.Name)]
+		if !ok || col.Default != "" || col.Generated != "" {
+			continue
+		}
+		if ancSch != nil {
+			if _, ok := ancSch.GetAllCols().GetByNameCaseInsensitive(col.Name); ok {
+				continue
+			}
+		}
+		if otherSch != nil {
+			if _, ok := otherSch.GetAllCols().GetByNameCaseInsensitive(col.Name); ok {
+				continue
+			}
+		}
+		cols[i].Default = "(" + expr + ")"
+		tags = append(tags, col.Tag)
+	}
+	if len(tags) == 0 {
+		return sch, nil, nil
+	}
+
+	updated, err := withColumns(sch, cols)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, tags, nil
+}
+
+// clearColumnDefaults removes the default values of the columns of |sch| with the given |tags|, which were set by
+// applyColumnDefaults.
+func clearColumnDefaults(sch schema.Schema, tags []uint64) (schema.Schema, error) {
+	if len(tags) == 0 {
+		return sch, nil
+	}
+	cols := sch.GetAllCols().GetColumns()
+	for i, col := range cols {
+		for _, tag := range tags {
+			if col.Tag == tag {
+				cols[i].Default = ""
+			}
+		}
+	}
+	return withColumns(sch, cols)
+}
+
+// withColumns returns a copy of |sch| with its columns replaced by |cols|, which must have the same tags.
+func withColumns(sch schema.Schema, cols []schema.Column) (schema.Schema, error) {
+	updated, err := schema.NewSchema(schema.NewColCollection(cols...), sch.GetPkOrdinals(), sch.GetCollation(), sch.Indexes(), sch.Checks())
+	if err != nil {
+		return nil, err
+	}
+	updated.SetComment(sch.GetComment())
+	return updated, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+func TestParseColumnDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected ColumnDefaults
+		invalid  bool
+	}{
+		{
+			name:     "single assignment",
+			input:    "t.c=1",
+			expected: ColumnDefaults{{Name: "t"}: {"c": "1"}},
+		},
+		{
+			name:  "several assignments",
+			input: "T.C1 = a + 1, t.c2=concat(a, ',', b), u.c='x,y'",
+			expected: ColumnDefaults{
+				{Name: "t"}: {"c1": "a + 1", "c2": "concat(a, ',', b)"},
+				{Name: "u"}: {"c": "'x,y'"},
+			},
+		},
+		{
+			name:     "expression containing an equals sign",
+			input:    "t.c=if(a = 1, 'one', 'other')",
+			expected: ColumnDefaults{{Name: "t"}: {"c": "if(a = 1, 'one', 'other')"}},
+		},
+		{
+			name:    "missing expression",
+			input:   "t.c",
+			invalid: true,
+		},
+		{
+			name:    "missing table",
+			input:   "c=1",
+			invalid: true,
+		},
+		{
+			name:    "empty expression",
+			input:   "t.c=",
+			invalid: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParseColumnDefaults(test.input)
+			if test.invalid {
+				assert.True(t, ErrInvalidColumnDefault.Is(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+			assert.Equal(t, test.expected[doltdb.TableName{Name: "t"}], actual.forTable(doltdb.TableName{Name: "T"}))
+		})
+	}
+}
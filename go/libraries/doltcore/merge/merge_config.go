@@ -0,0 +1,130 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// jsonArrayMerges holds the JSON array merge strategies set for the columns of a table in the dolt_merge_config
+// system table, keyed by lower-cased column name. The strategy of the empty column name applies to all the JSON
+// columns of the table that have no strategy of their own.
+type jsonArrayMerges map[string]JsonArrayMerge
+
+// loadJsonArrayMerges reads the JSON array merge strategies of the table |tblName| from the dolt_merge_config table
+// of |root|. Like the table itself, the merge configuration is versioned; merges use the configuration of the branch
+// being merged into.
+func loadJsonArrayMerges(ctx context.Context, root doltdb.RootValue, tblName doltdb.TableName) (jsonArrayMerges, error) {
+	if root == nil || !types.IsFormat_DOLT(root.VRW().Format()) {
+		return nil, nil
+	}
+	tbl, ok, err := root.GetTable(ctx, doltdb.TableName{Name: doltdb.MergeConfigTableName, Schema: tblName.Schema})
+	if err != nil || !ok {
+		return nil, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tableIdx, columnIdx := sch.GetPKCols().IndexOf(doltdb.MergeConfigTableNameCol), sch.GetPKCols().IndexOf(doltdb.MergeConfigColumnNameCol)
+	arrayMergeIdx := sch.GetNonPKCols().IndexOf(doltdb.MergeConfigJsonArrayMergeCol)
+	if tableIdx < 0 || columnIdx < 0 || arrayMergeIdx < 0 {
+		return nil, fmt.Errorf("%s had unexpected schema, this should never happen", doltdb.MergeConfigTableName)
+	}
+
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m := durable.ProllyMapFromIndex(rows)
+	kd, vd := m.Descriptors()
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var merges jsonArrayMerges
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			return merges, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		table, err := tree.GetField(ctx, kd, tableIdx, k, m.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := table.(string); !ok || !strings.EqualFold(s, tblName.Name) {
+			continue
+		}
+		column, err := tree.GetField(ctx, kd, columnIdx, k, m.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+		strategy, err := tree.GetField(ctx, vd, arrayMergeIdx, v, m.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+		col, _ := column.(string)
+		s, _ := strategy.(string)
+		am, err := ParseJsonArrayMerge(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", doltdb.MergeConfigTableName, err)
+		}
+		if merges == nil {
+			merges = make(jsonArrayMerges)
+		}
+		merges[strings.ToLower(col)] = am
+	}
+}
+
+// forColumn returns the JSON array merge strategy of the column |col|: the strategy set for the column in the merge
+// configuration, or else the strategy set for its table, or else the one in the dolt_json_array_merge session variable.
+func (jm jsonArrayMerges) forColumn(ctx *sql.Context, col string) (JsonArrayMerge, error) {
+	if am, ok := jm[strings.ToLower(col)]; ok {
+		return am, nil
+	}
+	if am, ok := jm[""]; ok {
+		return am, nil
+	}
+	v, err := ctx.Session.GetSessionVariable(ctx, "dolt_json_array_merge")
+	if err != nil {
+		return JsonArrayMerge{}, err
+	}
+	s, _ := v.(string)
+	return ParseJsonArrayMerge(s)
+}
+
+// JsonArrayMergeForColumn returns the JSON array merge strategy that merges use for the column |col| of the table
+// |tblName| in |root|.
+func JsonArrayMergeForColumn(ctx *sql.Context, root doltdb.RootValue, tblName doltdb.TableName, col string) (JsonArrayMerge, error) {
+	merges, err := loadJsonArrayMerges(ctx, root, tblName)
+	if err != nil {
+		return JsonArrayMerge{}, err
+	}
+	return merges.forColumn(ctx, col)
+}
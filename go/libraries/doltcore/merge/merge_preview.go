@@ -0,0 +1,415 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// The kinds of row changes listed in a detailed merge preview.
+const (
+	RowPreviewAdded               = "added"
+	RowPreviewModified            = "modified"
+	RowPreviewRemoved             = "removed"
+	RowPreviewConflict            = "conflict"
+	RowPreviewSchemaConflict      = "schema conflict"
+	RowPreviewConstraintViolation = "constraint violation"
+)
+
+// TablePreview summarizes the changes that a merge would make to a table of the branch being merged into.
+type TablePreview struct {
+	TableName            doltdb.TableName
+	Adds                 int
+	Modifications        int
+	Deletes              int
+	DataConflicts        int
+	SchemaConflicts      int
+	ConstraintViolations int
+	// Rows lists the rows that the merge would change, and the conflicts and constraint violations that it would
+	// record, when the preview is detailed.
+	Rows []RowPreview
+}
+
+// RowPreview is a change that a merge would make to a single row of a table.
+type RowPreview struct {
+	// Type is one of the RowPreview* constants.
+	Type string
+	// ViolationType is the type of a RowPreviewConstraintViolation, as listed in dolt_constraint_violations.
+	ViolationType string
+	// Key maps the primary key columns of the row to their values, or all the columns of the row for keyless
+	// tables. It's nil for schema conflicts, and for keyless rows that no longer exist.
+	Key map[string]interface{}
+}
+
+func (tp TablePreview) isEmpty() bool {
+	return tp.Adds == 0 && tp.Modifications == 0 && tp.Deletes == 0 &&
+		tp.DataConflicts == 0 && tp.SchemaConflicts == 0 && tp.ConstraintViolations == 0
+}
+
+// PreviewMerge merges |mergeCommit| into |commit| in memory and returns the changes that the merge would make to the
+// tables of |commit|, ordered by table name. Tables that the merge wouldn't change are omitted. When |detail| is true,
+// each change, conflict and constraint violation is also listed by row. Nothing is written to the database; in
+// particular the working set of the branch is unchanged, and the preview ignores its uncommitted changes.
+func PreviewMerge(ctx *sql.Context, commit, mergeCommit *doltdb.Commit, opts editor.Options, columnDefaults ColumnDefaults, detail bool) ([]TablePreview, error) {
+	canFF, err := commit.CanFastForwardTo(ctx, mergeCommit)
+	if errors.Is(err, doltdb.ErrUpToDate) || errors.Is(err, doltdb.ErrIsAhead) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	ourRoot, err := commit.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *Result
+	if canFF {
+		theirRoot, err := mergeCommit.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = &Result{Root: theirRoot, Stats: make(map[doltdb.TableName]*MergeStats)}
+	} else {
+		result, err = MergeCommits(ctx, commit, mergeCommit, opts, columnDefaults)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tblNames, err := doltdb.UnionTableNames(ctx, ourRoot, result.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	var previews []TablePreview
+	for _, tblName := range tblNames {
+		if doltdb.IsFullTextTable(tblName.Name) {
+			continue
+		}
+		tp, err := previewTable(ctx, tblName, ourRoot, result, detail)
+		if err != nil {
+			return nil, err
+		}
+		if !tp.isEmpty() {
+			previews = append(previews, tp)
+		}
+	}
+
+	sort.Slice(previews, func(i, j int) bool {
+		return previews[i].TableName.Less(previews[j].TableName)
+	})
+	return previews, nil
+}
+
+// previewTable compares the table |tblName| of |ourRoot| with the same table of the merge |result|.
+func previewTable(ctx context.Context, tblName doltdb.TableName, ourRoot doltdb.RootValue, result *Result, detail bool) (TablePreview, error) {
+	tp := TablePreview{TableName: tblName}
+	for _, sc := range result.SchemaConflicts {
+		if sc.TableName == tblName {
+			tp.SchemaConflicts += sc.Count()
+			if detail {
+				tp.Rows = append(tp.Rows, RowPreview{Type: RowPreviewSchemaConflict})
+			}
+		}
+	}
+
+	if !types.IsFormat_DOLT(ourRoot.VRW().Format()) {
+		// Row level details are only available for the new storage format
+		if stats, ok := result.Stats[tblName]; ok {
+			tp.Adds, tp.Modifications, tp.Deletes = stats.Adds, stats.Modifications, stats.Deletes
+			tp.DataConflicts, tp.ConstraintViolations = stats.DataConflicts, stats.ConstraintViolations
+		}
+		return tp, nil
+	}
+
+	ours, err := loadPreviewRows(ctx, ourRoot, tblName)
+	if err != nil {
+		return TablePreview{}, err
+	}
+	merged, err := loadPreviewRows(ctx, result.Root, tblName)
+	if err != nil {
+		return TablePreview{}, err
+	}
+
+	err = previewRowChanges(ctx, ours, merged, &tp, detail)
+	if err != nil {
+		return TablePreview{}, err
+	}
+	if merged != nil {
+		err = previewArtifacts(ctx, ours, merged, &tp, detail)
+		if err != nil {
+			return TablePreview{}, err
+		}
+	}
+	return tp, nil
+}
+
+// previewRows holds the rows and the merge artifacts of a table.
+type previewRows struct {
+	sch       schema.Schema
+	rows      prolly.Map
+	artifacts prolly.ArtifactMap
+}
+
+// loadPreviewRows loads the table |tblName| of |root|, or returns nil if |root| has no such table.
+func loadPreviewRows(ctx context.Context, root doltdb.RootValue, tblName doltdb.TableName) (*previewRows, error) {
+	tbl, ok, err := root.GetTable(ctx, tblName)
+	if err != nil || !ok {
+		return nil, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := tbl.GetArtifacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &previewRows{
+		sch:       sch,
+		rows:      durable.ProllyMapFromIndex(rows),
+		artifacts: durable.ProllyMapFromArtifactIndex(artifacts),
+	}, nil
+}
+
+// previewRowChanges counts the rows of |ours| that the merge adds, modifies or deletes to get |merged|. Either may
+// be nil if the table doesn't exist on that side. When the primary key of the table changes, all the rows are
+// replaced.
+func previewRowChanges(ctx context.Context, ours, merged *previewRows, tp *TablePreview, detail bool) error {
+	if ours == nil && merged == nil {
+		return nil
+	}
+	if ours == nil || merged == nil || !ours.rows.KeyDesc().Equals(merged.rows.KeyDesc()) {
+		if ours != nil {
+			err := previewAllRows(ctx, ours, RowPreviewRemoved, tp, detail)
+			if err != nil {
+				return err
+			}
+		}
+		if merged != nil {
+			return previewAllRows(ctx, merged, RowPreviewAdded, tp, detail)
+		}
+		return nil
+	}
+
+	err := prolly.DiffMaps(ctx, ours.rows, merged.rows, false, func(ctx context.Context, diff tree.Diff) error {
+		changeType, rows, value := RowPreviewModified, merged, val.Tuple(diff.To)
+		switch diff.Type {
+		case tree.AddedDiff:
+			tp.Adds++
+			changeType = RowPreviewAdded
+		case tree.ModifiedDiff:
+			tp.Modifications++
+		case tree.RemovedDiff:
+			tp.Deletes++
+			changeType, rows, value = RowPreviewRemoved, ours, val.Tuple(diff.From)
+		}
+		if !detail {
+			return nil
+		}
+		rowKey, err := previewRowKey(ctx, rows, val.Tuple(diff.Key), value)
+		if err != nil {
+			return err
+		}
+		tp.Rows = append(tp.Rows, RowPreview{Type: changeType, Key: rowKey})
+		return nil
+	})
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// previewAllRows records every row of |rows| as a change of type |changeType|.
+func previewAllRows(ctx context.Context, rows *previewRows, changeType string, tp *TablePreview, detail bool) error {
+	iter, err := rows.rows.IterAll(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if changeType == RowPreviewAdded {
+			tp.Adds++
+		} else {
+			tp.Deletes++
+		}
+		if detail {
+			rowKey, err := previewRowKey(ctx, rows, k, v)
+			if err != nil {
+				return err
+			}
+			tp.Rows = append(tp.Rows, RowPreview{Type: changeType, Key: rowKey})
+		}
+	}
+}
+
+// previewArtifacts counts the conflicts and constraint violations that the merge records in |merged|. The rows of
+// |ours| that already had an artifact of the same type before the merge aren't counted, whether the merge carries
+// the artifact over or finds it again.
+func previewArtifacts(ctx context.Context, ours, merged *previewRows, tp *TablePreview, detail bool) error {
+	existing := make(map[string]struct{})
+	if ours != nil {
+		err := forEachArtifact(ctx, ours.artifacts, func(art prolly.Artifact) error {
+			existing[artifactRowKey(art)] = struct{}{}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return forEachArtifact(ctx, merged.artifacts, func(art prolly.Artifact) error {
+		if _, ok := existing[artifactRowKey(art)]; ok {
+			return nil
+		}
+
+		rp := RowPreview{Type: RowPreviewConflict}
+		if art.ArtType == prolly.ArtifactTypeConflict {
+			tp.DataConflicts++
+		} else {
+			tp.ConstraintViolations++
+			rp = RowPreview{Type: RowPreviewConstraintViolation, ViolationType: violationTypeName(art.ArtType)}
+		}
+		if !detail {
+			return nil
+		}
+
+		var value val.Tuple
+		if schema.IsKeyless(merged.sch) {
+			// The key of a keyless row is a hash of its values, so look up the row to identify it
+			err := merged.rows.Get(ctx, art.SourceKey, func(_, v val.Tuple) error {
+				value = v
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		key, err := previewRowKey(ctx, merged, art.SourceKey, value)
+		if err != nil {
+			return err
+		}
+		rp.Key = key
+		tp.Rows = append(tp.Rows, rp)
+		return nil
+	})
+}
+
+// forEachArtifact calls |cb| with every artifact of |artifacts|.
+func forEachArtifact(ctx context.Context, artifacts prolly.ArtifactMap, cb func(art prolly.Artifact) error) error {
+	iter, err := artifacts.IterAllArtifacts(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		art, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err = cb(art); err != nil {
+			return err
+		}
+	}
+}
+
+// artifactRowKey identifies the row and the type of |art|, regardless of the merge that recorded it.
+func artifactRowKey(art prolly.Artifact) string {
+	return string(art.SourceKey) + string([]byte{byte(art.ArtType)})
+}
+
+// violationTypeName returns the violation_type of a constraint violation artifact in dolt_constraint_violations.
+func violationTypeName(artType prolly.ArtifactType) string {
+	switch artType {
+	case prolly.ArtifactTypeForeignKeyViol:
+		return "foreign key"
+	case prolly.ArtifactTypeUniqueKeyViol:
+		return "unique index"
+	case prolly.ArtifactTypeChkConsViol:
+		return "check constraint"
+	case prolly.ArtifactTypeNullViol:
+		return "not null"
+	default:
+		return ""
+	}
+}
+
+// previewRowKey returns the primary key columns of the row with the key |k|, or the columns of its value |v| for
+// keyless tables. |rows| holds the schema that describes the row.
+func previewRowKey(ctx context.Context, rows *previewRows, k, v val.Tuple) (map[string]interface{}, error) {
+	kd, vd := rows.rows.Descriptors()
+	ns := rows.rows.NodeStore()
+
+	rowKey := make(map[string]interface{})
+	if schema.IsKeyless(rows.sch) {
+		if v == nil {
+			return nil, nil
+		}
+		cols := rows.sch.GetNonPKCols()
+		// The first field of a keyless row is its cardinality
+		for i := 1; i < vd.Count(); i++ {
+			f, err := tree.GetField(ctx, vd, i, v, ns)
+			if err != nil {
+				return nil, err
+			}
+			rowKey[cols.GetByStoredIndex(i-1).Name] = previewValue(f)
+		}
+		return rowKey, nil
+	}
+
+	cols := rows.sch.GetPKCols().GetColumns()
+	for i := 0; i < kd.Count() && i < len(cols); i++ {
+		f, err := tree.GetField(ctx, kd, i, k, ns)
+		if err != nil {
+			return nil, err
+		}
+		rowKey[cols[i].Name] = previewValue(f)
+	}
+	return rowKey, nil
+}
+
+// previewValue converts a field value to a value that can be part of a JSON document.
+func previewValue(f interface{}) interface{} {
+	if b, ok := f.([]byte); ok {
+		return string(b)
+	}
+	return f
+}
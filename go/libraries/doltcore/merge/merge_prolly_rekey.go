@@ -0,0 +1,350 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	errorkinds "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor/creation"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// ErrMergeWithNullPrimaryKey is returned when rows from one side of a merge cannot be re-keyed through a primary key
+// that was redefined on the other side, because they hold NULL values in the new primary key columns.
+var ErrMergeWithNullPrimaryKey = errorkinds.NewKind("error: cannot merge because table %s has rows with NULL values in the columns of its new primary key")
+
+var errRekeyUnsupported = errors.New("rows cannot be re-keyed through the new primary key")
+var errRekeyNullKey = errors.New("row has a NULL value in a primary key column")
+
+// rekeyForPrimaryKeyChange prepares |tm| for merging a table whose primary key was redefined on one side of the merge
+// (e.g. a column was added to or removed from the primary key, or its columns were reordered). The ancestor and the
+// side that kept the original primary key are re-keyed through the new primary key, so the three-way diff can match
+// up rows by their new keys. Rows that collide under the new key are recorded in |tm.rekeyCollisions| and reported as
+// conflicts by the row merge. If the primary key change can't be handled this way, |tm| is left unchanged and the
+// schema merge reports the differing primary keys.
+func (tm *TableMerger) rekeyForPrimaryKeyChange(ctx *sql.Context) error {
+	if tm.leftTbl == nil || tm.rightTbl == nil || tm.ancTbl == nil {
+		return nil
+	}
+
+	format := tm.vrw.Format()
+	leftKept := schema.ArePrimaryKeySetsDiffable(format, tm.ancSch, tm.leftSch)
+	rightKept := schema.ArePrimaryKeySetsDiffable(format, tm.ancSch, tm.rightSch)
+
+	var pkSch schema.Schema
+	switch {
+	case leftKept && rightKept:
+		return nil
+	case leftKept:
+		pkSch = tm.rightSch
+	case rightKept:
+		pkSch = tm.leftSch
+	case schema.ArePrimaryKeySetsDiffable(format, tm.leftSch, tm.rightSch):
+		// both sides made the same primary key change, so only the ancestor needs to be re-keyed
+		pkSch = tm.leftSch
+	default:
+		return nil
+	}
+
+	anc, ok, err := tm.rekeyTable(ctx, tm.ancTbl, tm.ancSch, pkSch, nil)
+	if err != nil || !ok {
+		return err
+	}
+
+	var left, right *rekeyedTable
+	if leftKept {
+		if left, ok, err = tm.rekeyTable(ctx, tm.leftTbl, tm.leftSch, pkSch, &anc.rows); err != nil || !ok {
+			return err
+		}
+	}
+	if rightKept {
+		if right, ok, err = tm.rekeyTable(ctx, tm.rightTbl, tm.rightSch, pkSch, &anc.rows); err != nil || !ok {
+			return err
+		}
+	}
+
+	tm.rekeyCollisions = make(map[string]struct{})
+	for _, rt := range []*rekeyedTable{anc, left, right} {
+		if rt == nil {
+			continue
+		}
+		for _, key := range rt.collisions {
+			tm.rekeyCollisions[string(key)] = struct{}{}
+		}
+	}
+
+	tm.ancTbl, tm.ancSch = anc.tbl, anc.sch
+	if left != nil {
+		tm.leftTbl, tm.leftSch = left.tbl, left.sch
+	}
+	if right != nil {
+		tm.rightTbl, tm.rightSch = right.tbl, right.sch
+	}
+	return nil
+}
+
+// isRekeyCollision returns whether |key| identifies rows that collided when this table was re-keyed through a
+// redefined primary key.
+func (tm *TableMerger) isRekeyCollision(key val.Tuple) bool {
+	_, ok := tm.rekeyCollisions[string(key)]
+	return ok
+}
+
+type rekeyedTable struct {
+	tbl        *doltdb.Table
+	sch        schema.Schema
+	rows       prolly.Map
+	collisions []val.Tuple
+}
+
+// rekeyTable re-keys |tbl|, whose schema is |sch|, through the primary key of |pkSch|, rebuilding its secondary
+// indexes. If |base| is non-nil, colliding rows are resolved in favor of the rows that differ from |base|. The
+// returned bool is false if the table cannot be re-keyed.
+func (tm *TableMerger) rekeyTable(ctx *sql.Context, tbl *doltdb.Table, sch, pkSch schema.Schema, base *prolly.Map) (*rekeyedTable, bool, error) {
+	rekeyedSch, ok, err := schema.RekeySchema(sch, pkSch)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	if rekeyedSch.Indexes().ContainsFullTextIndex() {
+		return nil, false, nil
+	}
+
+	// artifacts are keyed by the original primary key, so tables holding any can't be re-keyed
+	arts, err := tbl.GetArtifacts(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if cnt, err := durable.ProllyMapFromArtifactIndex(arts).Count(); err != nil {
+		return nil, false, err
+	} else if cnt > 0 {
+		return nil, false, nil
+	}
+
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	rows, collisions, err := rekeyRows(ctx, durable.ProllyMapFromIndex(idx), sch, rekeyedSch, base)
+	if errors.Is(err, errRekeyUnsupported) {
+		return nil, false, nil
+	} else if errors.Is(err, errRekeyNullKey) {
+		return nil, false, ErrMergeWithNullPrimaryKey.New(tm.name)
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	indexes, err := durable.NewIndexSet(ctx, tm.vrw, tm.ns)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, def := range rekeyedSch.Indexes().AllIndexes() {
+		secondary, err := creation.BuildSecondaryProllyIndex(ctx, tm.vrw, tm.ns, rekeyedSch, tm.name.Name, def, rows)
+		if err != nil {
+			return nil, false, err
+		}
+		if indexes, err = indexes.PutIndex(ctx, def.Name(), secondary); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if tbl, err = tbl.UpdateSchema(ctx, rekeyedSch); err != nil {
+		return nil, false, err
+	}
+	if tbl, err = tbl.UpdateRows(ctx, durable.IndexFromProllyMap(rows)); err != nil {
+		return nil, false, err
+	}
+	if tbl, err = tbl.SetIndexSet(ctx, indexes); err != nil {
+		return nil, false, err
+	}
+
+	return &rekeyedTable{
+		tbl:        tbl,
+		sch:        rekeyedSch,
+		rows:       rows,
+		collisions: collisions,
+	}, true, nil
+}
+
+// ConflictRowData returns the primary index of |tbl|, the ancestor or the other side of a merge, with its rows keyed
+// the way the merge keyed them. If the merge redefined the table's primary key to that of |ourSch|, the rows are
+// re-keyed through it, and colliding rows are resolved in favor of the rows that differ from |base|, if given.
+func ConflictRowData(ctx context.Context, tbl *doltdb.Table, ourSch schema.Schema, base *prolly.Map) (prolly.Map, error) {
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return prolly.Map{}, err
+	}
+	rows := durable.ProllyMapFromIndex(idx)
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return prolly.Map{}, err
+	}
+	if schema.ArePrimaryKeySetsDiffable(tbl.Format(), sch, ourSch) {
+		return rows, nil
+	}
+
+	rekeyedSch, ok, err := schema.RekeySchema(sch, ourSch)
+	if err != nil {
+		return prolly.Map{}, err
+	} else if !ok {
+		return prolly.Map{}, errRekeyUnsupported
+	}
+	rows, _, err = rekeyRows(ctx, rows, sch, rekeyedSch, base)
+	return rows, err
+}
+
+// rekeyRows rebuilds |rows| with the key and value layout of |rekeyedSch|, which must be |sch| with a redefined
+// primary key. Only the first of several rows sharing a key under |rekeyedSch| is kept, unless it is identical to
+// the row of |base| under that key, in which case it is replaced by a row that differs from |base|. The keys of
+// all such collisions are returned.
+func rekeyRows(ctx context.Context, rows prolly.Map, sch, rekeyedSch schema.Schema, base *prolly.Map) (prolly.Map, []val.Tuple, error) {
+	fromKD, fromVD := sch.GetMapDescriptors()
+	toKD, toVD := rekeyedSch.GetMapDescriptors()
+
+	keyFields, err := rekeyFieldMapping(sch, rekeyedSch.GetPKCols(), fromKD, fromVD, toKD)
+	if err != nil {
+		return prolly.Map{}, nil, err
+	}
+	var storedCols []schema.Column
+	for _, col := range rekeyedSch.GetNonPKCols().GetColumns() {
+		if !col.Virtual {
+			storedCols = append(storedCols, col)
+		}
+	}
+	valueFields, err := rekeyFieldMapping(sch, schema.NewColCollection(storedCols...), fromKD, fromVD, toVD)
+	if err != nil {
+		return prolly.Map{}, nil, err
+	}
+
+	empty, err := prolly.NewMapFromTuples(ctx, rows.NodeStore(), toKD, toVD)
+	if err != nil {
+		return prolly.Map{}, nil, err
+	}
+	mut := empty.Mutate()
+	kb, vb := val.NewTupleBuilder(toKD), val.NewTupleBuilder(toVD)
+	p := rows.Pool()
+
+	var collisions []val.Tuple
+	seen := make(map[string]struct{})
+
+	iter, err := rows.IterAll(ctx)
+	if err != nil {
+		return prolly.Map{}, nil, err
+	}
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return prolly.Map{}, nil, err
+		}
+
+		for i, f := range keyFields {
+			field := f.get(k, v)
+			if field == nil {
+				return prolly.Map{}, nil, errRekeyNullKey
+			}
+			kb.PutRaw(i, field)
+		}
+		for i, f := range valueFields {
+			vb.PutRaw(i, f.get(k, v))
+		}
+		key, value := kb.Build(p), vb.BuildPermissive(p)
+
+		var existing val.Tuple
+		err = mut.Get(ctx, key, func(_, v val.Tuple) error {
+			existing = v
+			return nil
+		})
+		if err != nil {
+			return prolly.Map{}, nil, err
+		}
+		if existing != nil {
+			if _, ok := seen[string(key)]; !ok {
+				seen[string(key)] = struct{}{}
+				collisions = append(collisions, key)
+			}
+			if base == nil {
+				continue
+			}
+			var baseValue val.Tuple
+			err = base.Get(ctx, key, func(_, v val.Tuple) error {
+				baseValue = v
+				return nil
+			})
+			if err != nil {
+				return prolly.Map{}, nil, err
+			}
+			if !bytes.Equal(existing, baseValue) {
+				continue
+			}
+		}
+		if err = mut.Put(ctx, key, value); err != nil {
+			return prolly.Map{}, nil, err
+		}
+	}
+
+	rekeyed, err := mut.Map(ctx)
+	if err != nil {
+		return prolly.Map{}, nil, err
+	}
+	return rekeyed, collisions, nil
+}
+
+// rekeyField locates a field of a re-keyed tuple in the key or value tuple of the original row.
+type rekeyField struct {
+	fromKey bool
+	idx     int
+}
+
+func (f rekeyField) get(key, value val.Tuple) []byte {
+	if f.fromKey {
+		return key.GetField(f.idx)
+	}
+	return value.GetField(f.idx)
+}
+
+// rekeyFieldMapping maps each column of |cols| to its field in the key or value tuples of |sch|. Columns must be
+// stored with the same encoding on both sides, since fields are copied without conversion.
+func rekeyFieldMapping(sch schema.Schema, cols *schema.ColCollection, fromKD, fromVD, toDesc val.TupleDesc) ([]rekeyField, error) {
+	fields := make([]rekeyField, cols.Size())
+	for i, col := range cols.GetColumns() {
+		var f rekeyField
+		var enc val.Encoding
+		if idx, ok := sch.GetPKCols().TagToIdx[col.Tag]; ok {
+			f, enc = rekeyField{fromKey: true, idx: idx}, fromKD.Types[idx].Enc
+		} else if idx, ok := sch.GetNonPKCols().StoredIndexByTag(col.Tag); ok {
+			f, enc = rekeyField{idx: idx}, fromVD.Types[idx].Enc
+		} else {
+			return nil, fmt.Errorf("%w: column %s not found", errRekeyUnsupported, col.Name)
+		}
+		if enc != toDesc.Types[i].Enc {
+			return nil, fmt.Errorf("%w: column %s changes encoding", errRekeyUnsupported, col.Name)
+		}
+		fields[i] = f
+	}
+	return fields, nil
+}
@@ -75,6 +75,7 @@ func mergeProllyTable(
 	}
 	leftRows := durable.ProllyMapFromIndex(lr)
 	valueMerger := newValueMerger(mergedSch, tm.leftSch, tm.rightSch, tm.ancSch, leftRows.Pool(), tm.ns)
+	valueMerger.jsonArrayMerges = tm.jsonArrayMerges
 
 	if !valueMerger.leftMapping.IsIdentityMapping() {
 		mergeInfo.LeftNeedsRewrite = true
@@ -182,6 +183,26 @@ func mergeProllyTableData(ctx *sql.Context, tm *TableMerger, finalSch schema.Sch
 		} else if err != nil {
 			return nil, nil, err
 		}
+
+		if tm.isRekeyCollision(diff.Key) {
+			// Several rows of one side map to this key under the redefined primary key, so the left side's
+			// row is kept and the change is reported as a conflict to be resolved manually.
+			if diff.Left == nil && (diff.Op == tree.DiffOpRightModify || diff.Op == tree.DiffOpRightDelete) {
+				diff.Left = diff.Base
+			}
+			diff.Op = tree.DiffOpDivergentModifyConflict
+			s.DataConflicts++
+			err = conflicts.merge(ctx, diff, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+			err = pri.merge(ctx, diff, tm.leftSch)
+			if err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
 		cnt, err := uniq.validateDiff(ctx, diff)
 		if err != nil {
 			return nil, nil, err
@@ -638,23 +659,15 @@ func (uv uniqValidator) validateDiff(ctx *sql.Context, diff tree.ThreeWayDiff) (
 	return violations, err
 }
 
-// deleteArtifact deletes the unique constraint violation artifact for the row identified by |key| and returns a
+// deleteArtifact deletes the unique constraint violation artifacts for the row identified by |key| and returns a
 // boolean that indicates if an artifact was deleted, as well as an error that indicates if there were any
 // unexpected errors encountered.
 func (uv uniqValidator) deleteArtifact(ctx context.Context, key val.Tuple) (bool, error) {
-	artifactKey := uv.edits.BuildArtifactKey(ctx, key, uv.srcHash, prolly.ArtifactTypeUniqueKeyViol)
-
-	has, err := uv.edits.Has(ctx, artifactKey)
-	if err != nil || !has {
-		return false, err
-	}
-
-	err = uv.edits.Delete(ctx, artifactKey)
+	deleted, err := uv.edits.DeleteConstraintViolations(ctx, key, uv.srcHash, prolly.ArtifactTypeUniqueKeyViol, nil)
 	if err != nil {
 		return false, err
 	}
-
-	return true, nil
+	return deleted > 0, nil
 }
 
 // clearArtifactsForValue deletes the unique constraint violation artifact for the row identified by |key| and |value|
@@ -1622,6 +1635,8 @@ type valueMerger struct {
 	syncPool                               pool.BuffPool
 	keyless                                bool
 	ns                                     tree.NodeStore
+	// jsonArrayMerges are the JSON array merge strategies configured for the columns of the table
+	jsonArrayMerges jsonArrayMerges
 }
 
 func newValueMerger(merged, leftSch, rightSch, baseSch schema.Schema, syncPool pool.BuffPool, ns tree.NodeStore) *valueMerger {
@@ -1968,7 +1983,11 @@ func (m *valueMerger) processColumn(ctx *sql.Context, i int, left, right, base v
 			return nil, true, err
 		}
 		if _, ok := sqlType.(types.JsonType); ok && !disallowJsonMerge {
-			return m.mergeJSONAddr(ctx, baseCol, leftCol, rightCol)
+			arrayMerge, err := m.jsonArrayMerges.forColumn(ctx, resultColumn.Name)
+			if err != nil {
+				return nil, true, err
+			}
+			return m.mergeJSONAddr(ctx, baseCol, leftCol, rightCol, arrayMerge)
 		}
 		// otherwise, this is a conflict.
 		return nil, true, nil
@@ -1979,7 +1998,7 @@ func (m *valueMerger) processColumn(ctx *sql.Context, i int, left, right, base v
 	}
 }
 
-func (m *valueMerger) mergeJSONAddr(ctx context.Context, baseAddr []byte, leftAddr []byte, rightAddr []byte) (resultAddr []byte, conflict bool, err error) {
+func (m *valueMerger) mergeJSONAddr(ctx context.Context, baseAddr []byte, leftAddr []byte, rightAddr []byte, arrayMerge JsonArrayMerge) (resultAddr []byte, conflict bool, err error) {
 	baseDoc, err := tree.NewJSONDoc(hash.New(baseAddr), m.ns).ToIndexedJSONDocument(ctx)
 	if err != nil {
 		return nil, true, err
@@ -1993,7 +2012,7 @@ func (m *valueMerger) mergeJSONAddr(ctx context.Context, baseAddr []byte, leftAd
 		return nil, true, err
 	}
 
-	mergedDoc, conflict, err := mergeJSON(ctx, m.ns, baseDoc, leftDoc, rightDoc)
+	mergedDoc, conflict, err := mergeJSON(ctx, m.ns, baseDoc, leftDoc, rightDoc, arrayMerge)
 	if err != nil {
 		return nil, true, err
 	}
@@ -2009,37 +2028,68 @@ func (m *valueMerger) mergeJSONAddr(ctx context.Context, baseAddr []byte, leftAd
 	return mergedAddr[:], false, nil
 }
 
-func mergeJSON(ctx context.Context, ns tree.NodeStore, base, left, right sql.JSONWrapper) (resultDoc sql.JSONWrapper, conflict bool, err error) {
+func mergeJSON(ctx context.Context, ns tree.NodeStore, base, left, right sql.JSONWrapper, arrayMerge JsonArrayMerge) (resultDoc sql.JSONWrapper, conflict bool, err error) {
+	merged, conflicts, err := threeWayMergeJSON(ctx, ns, base, left, right, arrayMerge, false)
+	if err != nil {
+		return types.JSONDocument{}, true, err
+	}
+	if len(conflicts) > 0 {
+		return types.JSONDocument{}, true, nil
+	}
+	return merged, false, nil
+}
+
+// JsonConflictPaths returns the JSON paths of the values of |left| and |right| whose changes from |base| can't be
+// merged with |arrayMerge|, or nil if the documents merge cleanly.
+func JsonConflictPaths(ctx context.Context, ns tree.NodeStore, base, left, right sql.JSONWrapper, arrayMerge JsonArrayMerge) ([]string, error) {
+	_, conflicts, err := threeWayMergeJSON(ctx, ns, base, left, right, arrayMerge, true)
+	return conflicts, err
+}
+
+// threeWayMergeJSON merges the JSON documents |left| and |right|, and returns the paths of the values that couldn't be
+// merged. Unless |allConflicts| is set, it stops at the first conflict.
+func threeWayMergeJSON(ctx context.Context, ns tree.NodeStore, base, left, right sql.JSONWrapper, arrayMerge JsonArrayMerge, allConflicts bool) (resultDoc sql.JSONWrapper, conflicts []string, err error) {
 	// First, deserialize each value into JSON.
-	// We can only merge if the value at all three commits is a JSON object.
+	// We can only merge location by location if the value at all three commits is a JSON object.
 
 	baseIsObject, err := tree.IsJsonObject(base)
 	if err != nil {
-		return nil, true, err
+		return nil, nil, err
 	}
 	leftIsObject, err := tree.IsJsonObject(left)
 	if err != nil {
-		return nil, true, err
+		return nil, nil, err
 	}
 	rightIsObject, err := tree.IsJsonObject(right)
 	if err != nil {
-		return nil, true, err
+		return nil, nil, err
 	}
 
 	if !baseIsObject || !leftIsObject || !rightIsObject {
 		// At least one of the commits does not have a JSON object.
-		// If both left and right have the same value, use that value.
-		// But if they differ, this is an unresolvable merge conflict.
+		// If both left and right have the same value, use that value. Arrays can be merged with the array merge
+		// strategy, but otherwise differing values are an unresolvable merge conflict.
 		cmp, err := types.CompareJSON(left, right)
 		if err != nil {
-			return types.JSONDocument{}, true, err
+			return nil, nil, err
 		}
 		if cmp == 0 {
 			// convergent operation.
-			return left, false, nil
-		} else {
-			return types.JSONDocument{}, true, nil
+			return left, nil, nil
+		}
+		var values [3]jsonValue
+		for i, doc := range []sql.JSONWrapper{base, left, right} {
+			if values[i].val, err = doc.ToInterface(); err != nil {
+				return nil, nil, err
+			}
+			values[i].exists = true
+		}
+		jm := jsonMerge{arrayMerge: arrayMerge}
+		merged, err := jm.merge("$", values[0], values[1], values[2])
+		if err != nil || len(jm.conflicts) > 0 {
+			return nil, jm.conflicts, err
 		}
+		return types.JSONDocument{Val: merged.val}, nil, nil
 	}
 
 	indexedBase, isBaseIndexed := base.(tree.IndexedJsonDocument)
@@ -2052,16 +2102,16 @@ func mergeJSON(ctx context.Context, ns tree.NodeStore, base, left, right sql.JSO
 	if isBaseIndexed && isLeftIndexed {
 		leftDiffer, err = tree.NewIndexedJsonDiffer(ctx, indexedBase, indexedLeft)
 		if err != nil {
-			return nil, true, err
+			return nil, nil, err
 		}
 	} else {
 		baseObject, err := base.ToInterface()
 		if err != nil {
-			return nil, true, err
+			return nil, nil, err
 		}
 		leftObject, err := left.ToInterface()
 		if err != nil {
-			return nil, true, err
+			return nil, nil, err
 		}
 		leftDiffer = tree.NewJsonDiffer(baseObject.(types.JsonObject), leftObject.(types.JsonObject))
 	}
@@ -2070,16 +2120,16 @@ func mergeJSON(ctx context.Context, ns tree.NodeStore, base, left, right sql.JSO
 	if isBaseIndexed && isRightIndexed {
 		rightDiffer, err = tree.NewIndexedJsonDiffer(ctx, indexedBase, indexedRight)
 		if err != nil {
-			return nil, true, err
+			return nil, nil, err
 		}
 	} else {
 		baseObject, err := base.ToInterface()
 		if err != nil {
-			return nil, true, err
+			return nil, nil, err
 		}
 		rightObject, err := right.ToInterface()
 		if err != nil {
-			return nil, true, err
+			return nil, nil, err
 		}
 		rightDiffer = tree.NewJsonDiffer(baseObject.(types.JsonObject), rightObject.(types.JsonObject))
 	}
@@ -2088,6 +2138,10 @@ func mergeJSON(ctx context.Context, ns tree.NodeStore, base, left, right sql.JSO
 		leftDiffer:  leftDiffer,
 		rightDiffer: rightDiffer,
 		ns:          ns,
+		base:        base,
+		left:        left,
+		right:       right,
+		arrayMerge:  arrayMerge,
 	}
 
 	// Compute the merged object by applying diffs to the left object as needed.
@@ -2097,7 +2151,7 @@ func mergeJSON(ctx context.Context, ns tree.NodeStore, base, left, right sql.JSO
 	if merged, ok = left.(tree.IndexedJsonDocument); !ok {
 		root, err := tree.SerializeJsonToAddr(ctx, ns, left)
 		if err != nil {
-			return types.JSONDocument{}, true, err
+			return nil, nil, err
 		}
 		merged = tree.NewIndexedJsonDocument(ctx, root, ns)
 	}
@@ -2105,27 +2159,42 @@ func mergeJSON(ctx context.Context, ns tree.NodeStore, base, left, right sql.JSO
 	for {
 		threeWayDiff, err := threeWayDiffer.Next(ctx)
 		if err == io.EOF {
-			return merged, false, nil
+			if len(conflicts) > 0 {
+				return nil, conflicts, nil
+			}
+			return merged, nil, nil
 		}
 		if err != nil {
-			return types.JSONDocument{}, true, err
+			return nil, nil, err
 		}
 
 		switch threeWayDiff.Op {
-		case tree.DiffOpRightAdd, tree.DiffOpConvergentAdd, tree.DiffOpRightModify, tree.DiffOpConvergentModify, tree.DiffOpDivergentModifyResolved:
+		case tree.DiffOpRightAdd, tree.DiffOpConvergentAdd, tree.DiffOpRightModify, tree.DiffOpConvergentModify:
 			merged, _, err = merged.SetWithKey(ctx, threeWayDiff.Key, threeWayDiff.Right)
 			if err != nil {
-				return types.JSONDocument{}, true, err
+				return nil, nil, err
+			}
+		case tree.DiffOpDivergentModifyResolved:
+			merged, _, err = merged.SetWithKey(ctx, threeWayDiff.Key, threeWayDiff.Merged)
+			if err != nil {
+				return nil, nil, err
 			}
-		case tree.DiffOpRightDelete, tree.DiffOpConvergentDelete:
+		case tree.DiffOpRightDelete, tree.DiffOpConvergentDelete, tree.DiffOpDivergentDeleteResolved:
 			merged, _, err = merged.RemoveWithKey(ctx, threeWayDiff.Key)
 			if err != nil {
-				return types.JSONDocument{}, true, err
+				return nil, nil, err
 			}
 		case tree.DiffOpLeftAdd, tree.DiffOpLeftModify, tree.DiffOpLeftDelete:
 			// these changes already exist on the left, so do nothing.
 		case tree.DiffOpDivergentModifyConflict, tree.DiffOpDivergentDeleteConflict:
-			return types.JSONDocument{}, true, nil
+			if len(threeWayDiff.ConflictPaths) > 0 {
+				conflicts = append(conflicts, threeWayDiff.ConflictPaths...)
+			} else {
+				conflicts = append(conflicts, tree.JsonKeyToPath(threeWayDiff.Key))
+			}
+			if !allConflicts {
+				return nil, conflicts, nil
+			}
 		default:
 			panic("unreachable")
 		}
@@ -0,0 +1,165 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+// tableRename records that the table named |from| in the merge ancestor is named |to| on one side of the merge.
+type tableRename struct {
+	from, to doltdb.TableName
+}
+
+// mergeTableRenames finds the tables renamed on either side of a merge and applies each rename to the ancestor and to
+// the other side of the merge. Tables are merged by name, so without this a rename on one branch looks like a drop and
+// an add, and edits made to the table on the other branch conflict with the drop instead of following the rename.
+//
+// A rename is only applied to the other side if that side still has the table under its old name and doesn't have a
+// table with the new name. If both sides renamed the same table to the same name, only the ancestor is renamed. If
+// they renamed it to different names, nothing is renamed and the tables merge as they did before.
+func mergeTableRenames(ctx context.Context, ourRoot, theirRoot, ancRoot doltdb.RootValue) (doltdb.RootValue, doltdb.RootValue, doltdb.RootValue, error) {
+	ourRenames, err := findTableRenames(ctx, ancRoot, ourRoot)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	theirRenames, err := findTableRenames(ctx, ancRoot, theirRoot)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(ourRenames) == 0 && len(theirRenames) == 0 {
+		return ourRoot, theirRoot, ancRoot, nil
+	}
+
+	ourRenamed := make(map[doltdb.TableName]doltdb.TableName, len(ourRenames))
+	for _, r := range ourRenames {
+		ourRenamed[r.from] = r.to
+	}
+	theirRenamed := make(map[doltdb.TableName]doltdb.TableName, len(theirRenames))
+	for _, r := range theirRenames {
+		theirRenamed[r.from] = r.to
+	}
+
+	for _, r := range ourRenames {
+		if to, ok := theirRenamed[r.from]; ok {
+			if to.EqualFold(r.to) {
+				if ancRoot, err = renameTableForMerge(ctx, ancRoot, r); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+			continue
+		}
+		if ok, err := canApplyRename(ctx, theirRoot, r); err != nil {
+			return nil, nil, nil, err
+		} else if !ok {
+			continue
+		}
+		if theirRoot, err = renameTableForMerge(ctx, theirRoot, r); err != nil {
+			return nil, nil, nil, err
+		}
+		if ancRoot, err = renameTableForMerge(ctx, ancRoot, r); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	for _, r := range theirRenames {
+		if _, ok := ourRenamed[r.from]; ok {
+			continue
+		}
+		if ok, err := canApplyRename(ctx, ourRoot, r); err != nil {
+			return nil, nil, nil, err
+		} else if !ok {
+			continue
+		}
+		if ourRoot, err = renameTableForMerge(ctx, ourRoot, r); err != nil {
+			return nil, nil, nil, err
+		}
+		if ancRoot, err = renameTableForMerge(ctx, ancRoot, r); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return ourRoot, theirRoot, ancRoot, nil
+}
+
+// findTableRenames returns the tables renamed between |ancRoot| and |root|, sorted by their ancestor name. Renames are
+// detected the same way diffs detect them, by matching the column tags of a dropped table and an added table. Full-Text
+// tables and their parents are skipped, since the Full-Text pseudo-tables are named after their parent table.
+func findTableRenames(ctx context.Context, ancRoot, root doltdb.RootValue) ([]tableRename, error) {
+	deltas, err := diff.GetTableDeltas(ctx, ancRoot, root)
+	if err != nil {
+		return nil, err
+	}
+
+	var renames []tableRename
+	for _, td := range deltas {
+		if !td.IsRename() || doltdb.IsFullTextTable(td.FromName.Name) || doltdb.IsFullTextTable(td.ToName.Name) {
+			continue
+		}
+		if td.FromSch.Indexes().ContainsFullTextIndex() || td.ToSch.Indexes().ContainsFullTextIndex() {
+			continue
+		}
+		renames = append(renames, tableRename{from: td.FromName, to: td.ToName})
+	}
+
+	sort.Slice(renames, func(i, j int) bool {
+		return renames[i].from.Less(renames[j].from)
+	})
+	return renames, nil
+}
+
+// canApplyRename returns whether |root| has the table |r| renames and has no table with the name it was renamed to.
+func canApplyRename(ctx context.Context, root doltdb.RootValue, r tableRename) (bool, error) {
+	hasFrom, err := root.HasTable(ctx, r.from)
+	if err != nil || !hasFrom {
+		return false, err
+	}
+	hasTo, err := root.HasTable(ctx, r.to)
+	if err != nil {
+		return false, err
+	}
+	return !hasTo, nil
+}
+
+// renameTableForMerge renames a table in |root| along with the foreign keys that reference it by name.
+func renameTableForMerge(ctx context.Context, root doltdb.RootValue, r tableRename) (doltdb.RootValue, error) {
+	root, err := root.RenameTable(ctx, r.from, r.to)
+	if err != nil {
+		return nil, err
+	}
+
+	fkc, err := root.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fks := fkc.AllKeys()
+	for i := range fks {
+		if fks[i].TableName.EqualFold(r.from) {
+			fks[i].TableName = r.to
+		}
+		if fks[i].ReferencedTableName.EqualFold(r.from) {
+			fks[i].ReferencedTableName = r.to
+		}
+	}
+	fkc, err = doltdb.NewForeignKeyCollection(fks...)
+	if err != nil {
+		return nil, err
+	}
+	return root.PutForeignKeyCollection(ctx, fkc)
+}
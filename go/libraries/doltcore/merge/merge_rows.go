@@ -16,6 +16,7 @@ package merge
 
 import (
 	"context"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
@@ -52,6 +53,9 @@ type MergeOpts struct {
 	// dolt_verify_constraints() stored procedure to allow callers to verify constraints for a
 	// subset of tables.
 	RecordViolationsForTables map[doltdb.TableName]struct{}
+	// ColumnDefaults optionally provides expressions that populate the existing rows of one side of the
+	// merge for columns added on the other side, such as new NOT NULL columns that have no default value.
+	ColumnDefaults ColumnDefaults
 }
 
 type TableMerger struct {
@@ -76,6 +80,17 @@ type TableMerger struct {
 	// exception is for the dolt_verify_constraints() stored procedure, which allows callers to
 	// only record constraint violations for a specified subset of tables.
 	recordViolations bool
+
+	// rekeyCollisions holds the keys of rows that collided when the ancestor or one side of the merge was re-keyed
+	// through a primary key that was redefined on the other side. Any changes to these rows are reported as conflicts.
+	rekeyCollisions map[string]struct{}
+
+	// columnDefaults holds the expressions that populate existing rows for columns added on one side of the merge,
+	// keyed by lower-cased column name.
+	columnDefaults map[string]string
+
+	// jsonArrayMerges are the JSON array merge strategies set for the columns of this table in the merge configuration
+	jsonArrayMerges jsonArrayMerges
 }
 
 func (tm TableMerger) tableHashes() (left, right, anc hash.Hash, err error) {
@@ -163,6 +178,22 @@ func (rm *RootMerger) MergeTable(
 		return &MergedTable{table: finished}, stats, err
 	}
 
+	// If one side redefined the primary key, re-key the other side's rows through the new key
+	if types.IsFormat_DOLT(tm.vrw.Format()) {
+		if err = tm.rekeyForPrimaryKeyChange(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Use any column defaults given for columns added on one side of the merge as their default values while merging,
+	// so that the rows of the other side are populated with them
+	var backfilledTags []uint64
+	if types.IsFormat_DOLT(tm.vrw.Format()) {
+		if backfilledTags, err = tm.applyColumnDefaults(); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Calculate a merge of the schemas, but don't apply it yet
 	mergeSch, schConflicts, mergeInfo, diffInfo, err := SchemaMerge(ctx, tm.vrw.Format(), tm.leftSch, tm.rightSch, tm.ancSch, tblName)
 	if err != nil {
@@ -193,9 +224,39 @@ func (rm *RootMerger) MergeTable(
 	if err != nil {
 		return nil, nil, err
 	}
+
+	if len(backfilledTags) > 0 {
+		// The column defaults only apply to the merge, so restore the merged column definitions
+		finalSch, err := clearColumnDefaults(mergeSch, backfilledTags)
+		if err != nil {
+			return nil, nil, err
+		}
+		if tbl, err = tbl.UpdateSchema(ctx, finalSch); err != nil {
+			return nil, nil, err
+		}
+	}
 	return &MergedTable{table: tbl}, stats, nil
 }
 
+// applyColumnDefaults sets the column defaults given for this table as the default values of the columns that were
+// added on one side of the merge, and returns the tags of those columns.
+func (tm *TableMerger) applyColumnDefaults() ([]uint64, error) {
+	if len(tm.columnDefaults) == 0 || tm.leftSch == nil || tm.rightSch == nil {
+		return nil, nil
+	}
+
+	leftSch, leftTags, err := applyColumnDefaults(tm.leftSch, tm.rightSch, tm.ancSch, tm.columnDefaults)
+	if err != nil {
+		return nil, err
+	}
+	rightSch, rightTags, err := applyColumnDefaults(tm.rightSch, tm.leftSch, tm.ancSch, tm.columnDefaults)
+	if err != nil {
+		return nil, err
+	}
+	tm.leftSch, tm.rightSch = leftSch, rightSch
+	return append(leftTags, rightTags...), nil
+}
+
 func (rm *RootMerger) makeTableMerger(ctx context.Context, tblName doltdb.TableName, mergeOpts MergeOpts) (*TableMerger, error) {
 	recordViolations := true
 	if mergeOpts.RecordViolationsForTables != nil {
@@ -211,9 +272,14 @@ func (rm *RootMerger) makeTableMerger(ctx context.Context, tblName doltdb.TableN
 		vrw:              rm.vrw,
 		ns:               rm.ns,
 		recordViolations: recordViolations,
+		columnDefaults:   mergeOpts.ColumnDefaults.forTable(tblName),
 	}
 
 	var err error
+	if tm.jsonArrayMerges, err = loadJsonArrayMerges(ctx, rm.left, tblName); err != nil {
+		return nil, err
+	}
+
 	var leftSideTableExists, rightSideTableExists, ancTableExists bool
 
 	tm.leftTbl, leftSideTableExists, err = rm.left.GetTable(ctx, tblName)
@@ -273,11 +339,68 @@ func (rm *RootMerger) makeTableMerger(ctx context.Context, tblName doltdb.TableN
 		if err != nil {
 			return nil, err
 		}
+	} else if leftSideTableExists && rightSideTableExists && types.IsFormat_DOLT(rm.vrw.Format()) {
+		// If left & right added the same table with different schemas that share a primary key, fill tm.anc with an
+		// empty table holding the columns both sides agree on, so that the rows of both sides merge by key
+		ancSch, ok, err := addedTwiceAncestorSchema(tm.leftSch, tm.rightSch)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			tm.ancSch = ancSch
+			tm.ancTbl, err = doltdb.NewEmptyTable(ctx, rm.vrw, rm.ns, tm.ancSch)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return &tm, nil
 }
 
+// addedTwiceAncestorSchema returns the schema to merge against for a table that both sides of a merge added with
+// different schemas. The tables can only be merged if they have the same primary key columns, in the same order and
+// with the same types. The returned schema has the primary key and the other columns that both sides defined the same
+// way, so the remaining columns of each side merge as columns added on that side. Returns false if the tables can't
+// be merged.
+func addedTwiceAncestorSchema(left, right schema.Schema) (schema.Schema, bool, error) {
+	if schema.IsKeyless(left) || schema.IsKeyless(right) {
+		return nil, false, nil
+	}
+	leftPks, rightPks := left.GetPKCols(), right.GetPKCols()
+	if leftPks.Size() != rightPks.Size() {
+		return nil, false, nil
+	}
+	for i := 0; i < leftPks.Size(); i++ {
+		l, r := leftPks.GetByIndex(i), rightPks.GetByIndex(i)
+		if !strings.EqualFold(l.Name, r.Name) || !l.TypeInfo.Equals(r.TypeInfo) {
+			return nil, false, nil
+		}
+	}
+
+	var cols []schema.Column
+	_ = left.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if col.IsPartOfPK {
+			cols = append(cols, col)
+		} else if rightCol, ok := right.GetAllCols().GetByNameCaseInsensitive(col.Name); ok && col.EqualsWithoutTag(rightCol) {
+			cols = append(cols, col)
+		}
+		return false, nil
+	})
+	allCols := schema.NewColCollection(cols...)
+
+	pkOrdinals := make([]int, leftPks.Size())
+	for i, tag := range leftPks.Tags {
+		pkOrdinals[i] = allCols.TagToIdx[tag]
+	}
+
+	sch, err := schema.NewSchema(allCols, pkOrdinals, left.GetCollation(), nil, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	return sch, true, nil
+}
+
 func (rm *RootMerger) maybeShortCircuit(ctx context.Context, tm *TableMerger, opts MergeOpts) (*doltdb.Table, *MergeStats, error) {
 	// If we need to re-verify all constraints as part of this merge, then we can't short
 	// circuit considering any tables, so return immediately
@@ -78,7 +78,7 @@ func GetMutableSecondaryIdxsWithPending(ctx *sql.Context, ourSch, sch schema.Sch
 			idxKeyDesc = idxKeyDesc.PrefixDesc(idxKeyDesc.Count() - 1)
 		}
 
-		if !idxKeyDesc.Equals(index.Schema().GetKeyDescriptorWithNoConversion()) {
+		if !idxKeyDesc.Equals(index.Schema().GetKeyDescriptor()) {
 			continue
 		}
 
@@ -15,6 +15,7 @@
 package merge
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -23,6 +24,21 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 )
 
+// RevertResult is the result of reverting a series of commits with Revert.
+type RevertResult struct {
+	// Root is the root value with the changes of the reverted commits removed.
+	Root doltdb.RootValue
+	// Reverted are the commits that were reverted cleanly, in order.
+	Reverted []*doltdb.Commit
+	// Conflicted is the commit whose revert caused conflicts or constraint violations, or nil if all commits were
+	// reverted cleanly. Its revert, including the conflicts and constraint violations, is part of Root.
+	Conflicted *doltdb.Commit
+	// Pending are the commits after Conflicted that have not been reverted yet.
+	Pending []*doltdb.Commit
+	// MergeResult is the result of the merge that reverted Conflicted.
+	MergeResult *Result
+}
+
 // Revert is a convenience function for a three-way merge. In particular, given some root and a collection of commits
 // that are all parents of the root value, this applies a three-way merge with the following characteristics (assuming
 // a commit is HEAD~1):
@@ -32,66 +48,78 @@ import (
 // Theirs: HEAD~2
 //
 // The root is updated with the merged result, and this process is repeated for each commit given, in the order given.
-// Currently, we error on conflicts or constraint violations generated by the merge.
-func Revert(ctx *sql.Context, ddb *doltdb.DoltDB, root doltdb.RootValue, commits []*doltdb.Commit, opts editor.Options) (doltdb.RootValue, string, error) {
-	revertMessage := "Revert"
-
+// For merge commits, |mainline| is the 1-based number of the parent used as Theirs; zero selects the first parent.
+// If reverting a commit causes conflicts or constraint violations, the process stops at that commit so that they can
+// be resolved, and the returned result records the commits that are still pending.
+func Revert(ctx *sql.Context, ddb *doltdb.DoltDB, root doltdb.RootValue, commits []*doltdb.Commit, mainline int, opts editor.Options) (*RevertResult, error) {
+	parentIdx := 0
+	if mainline > 0 {
+		parentIdx = mainline - 1
+	}
 	for _, cm := range commits {
 		if len(cm.DatasParents()) == 0 {
 			h, err := cm.HashOf()
 			if err != nil {
-				return nil, "", err
+				return nil, err
 			}
-			return nil, "", fmt.Errorf("cannot revert commit with no parents (%s)", h.String())
+			return nil, fmt.Errorf("cannot revert commit with no parents (%s)", h.String())
+		}
+		if parentIdx >= len(cm.DatasParents()) {
+			return nil, fmt.Errorf("invalid mainline parent number %d: commit has %d parents", mainline, len(cm.DatasParents()))
 		}
 	}
 
+	res := &RevertResult{Root: root}
 	for i, baseCommit := range commits {
-		if i > 0 {
-			revertMessage += " and"
-		}
 		baseRoot, err := baseCommit.GetRootValue(ctx)
 		if err != nil {
-			return nil, "", err
-		}
-		baseMeta, err := baseCommit.GetCommitMeta(ctx)
-		if err != nil {
-			return nil, "", err
+			return nil, err
 		}
-		revertMessage = fmt.Sprintf(`%s "%s"`, revertMessage, baseMeta.Description)
 
-		optCmt, err := ddb.ResolveParent(ctx, baseCommit, 0)
+		optCmt, err := ddb.ResolveParent(ctx, baseCommit, parentIdx)
 		if err != nil {
-			return nil, "", err
+			return nil, err
 		}
 		parentCM, ok := optCmt.ToCommit()
 		if !ok {
-			return nil, "", doltdb.ErrGhostCommitEncountered
+			return nil, doltdb.ErrGhostCommitEncountered
 		}
 
 		theirRoot, err := parentCM.GetRootValue(ctx)
 		if err != nil {
-			return nil, "", err
+			return nil, err
 		}
 
-		var result *Result
-		result, err = MergeRoots(ctx, root, theirRoot, baseRoot, parentCM, baseCommit, opts, MergeOpts{IsCherryPick: false})
+		result, err := MergeRoots(ctx, res.Root, theirRoot, baseRoot, parentCM, baseCommit, opts, MergeOpts{IsCherryPick: false})
 		if err != nil {
-			return nil, "", err
+			return nil, err
 		}
-		root = result.Root
+		res.Root = result.Root
 
-		if ok, err := doltdb.HasConflicts(ctx, result.Root); err != nil {
-			return nil, "", err
-		} else if ok {
-			return nil, "", fmt.Errorf("revert currently does not handle conflicts")
-		}
-		if ok, err := doltdb.HasConstraintViolations(ctx, result.Root); err != nil {
-			return nil, "", err
-		} else if ok {
-			return nil, "", fmt.Errorf("revert currently does not handle constraint violations")
+		if result.HasMergeArtifacts() {
+			res.Conflicted = baseCommit
+			res.Pending = commits[i+1:]
+			res.MergeResult = result
+			return res, nil
 		}
+		res.Reverted = append(res.Reverted, baseCommit)
 	}
 
-	return root, revertMessage, nil
+	return res, nil
+}
+
+// RevertMessage returns the message of the commit that reverts |commits|.
+func RevertMessage(ctx context.Context, commits []*doltdb.Commit) (string, error) {
+	revertMessage := "Revert"
+	for i, cm := range commits {
+		if i > 0 {
+			revertMessage += " and"
+		}
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return "", err
+		}
+		revertMessage = fmt.Sprintf(`%s "%s"`, revertMessage, meta.Description)
+	}
+	return revertMessage, nil
 }
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
@@ -30,6 +31,10 @@ type ThreeWayJsonDiffer struct {
 	leftCurrentDiff, rightCurrentDiff *tree.JsonDiff
 	leftIsDone, rightIsDone           bool
 	ns                                tree.NodeStore
+	// base, left and right are the documents being diffed, which are read when concurrent changes to the same
+	// location have to be merged in memory
+	base, left, right sql.JSONWrapper
+	arrayMerge        JsonArrayMerge
 }
 
 type ThreeWayJsonDiff struct {
@@ -39,6 +44,8 @@ type ThreeWayJsonDiff struct {
 	// depending on the diffOp
 	Key                 []byte
 	Left, Right, Merged sql.JSONWrapper
+	// ConflictPaths are the JSON paths of the conflicting values under Key, when they are more precise than Key
+	ConflictPaths []string
 }
 
 func (differ *ThreeWayJsonDiffer) Next(ctx context.Context) (ThreeWayJsonDiff, error) {
@@ -64,35 +71,24 @@ func (differ *ThreeWayJsonDiffer) Next(ctx context.Context) (ThreeWayJsonDiff, e
 		rightKey := rightDiff.Key
 
 		cmp := bytes.Compare(leftKey, rightKey)
-		// If both sides modify the same array to different values, we currently consider that to be a conflict.
-		// This may be relaxed in the future.
-		if cmp != 0 && tree.JsonKeysModifySameArray(leftKey, rightKey) {
-			result := ThreeWayJsonDiff{
-				Op: tree.DiffOpDivergentModifyConflict,
-			}
-			return result, nil
+		// If both sides modify the same array, the whole array is merged with the array merge strategy. The default
+		// strategy considers changes to different elements to be a conflict, and merges changes to the same element.
+		if arrayKey, ok := tree.SharedJsonArrayKey(leftKey, rightKey); ok && (cmp != 0 || differ.arrayMerge.mergesArrays()) {
+			return differ.mergeValuesAt(ctx, arrayKey)
 		}
 		if cmp > 0 {
 			if tree.IsJsonKeyPrefix(leftKey, rightKey) {
 				// The right diff must be replacing or deleting an object,
 				// and the left diff makes changes to that object.
-				result := ThreeWayJsonDiff{
-					Op: tree.DiffOpDivergentModifyConflict,
-				}
-				differ.leftCurrentDiff = nil
-				return result, nil
+				return differ.mergeValuesAt(ctx, rightKey)
 			}
 			// key only changed on right
 			return differ.processRightSideOnlyDiff(), nil
 		} else if cmp < 0 {
 			if tree.IsJsonKeyPrefix(rightKey, leftKey) {
-				// The right diff must be replacing or deleting an object,
-				// and the left diff makes changes to that object.
-				result := ThreeWayJsonDiff{
-					Op: tree.DiffOpDivergentModifyConflict,
-				}
-				differ.rightCurrentDiff = nil
-				return result, nil
+				// The left diff must be replacing or deleting an object,
+				// and the right diff makes changes to that object.
+				return differ.mergeValuesAt(ctx, leftKey)
 			}
 			// left side was modified. We don't need to do anything with this diff.
 			differ.leftCurrentDiff = nil
@@ -121,14 +117,22 @@ func (differ *ThreeWayJsonDiffer) Next(ctx context.Context) (ThreeWayJsonDiff, e
 			// If the key existed at base, we can do a recursive three-way merge to resolve
 			// changes to the values.
 			// This shouldn't be necessary: if its an object on all three branches, the original diff is recursive.
-			mergedValue, conflict, err := mergeJSON(ctx, differ.ns, differ.leftCurrentDiff.From,
+			mergedValue, conflicts, err := threeWayMergeJSON(ctx, differ.ns, differ.leftCurrentDiff.From,
 				differ.leftCurrentDiff.To,
-				differ.rightCurrentDiff.To)
+				differ.rightCurrentDiff.To,
+				differ.arrayMerge, false)
 			if err != nil {
 				return ThreeWayJsonDiff{}, err
 			}
-			if conflict {
-				return differ.processMergedDiff(tree.DiffOpDivergentModifyConflict, nil), nil
+			if len(conflicts) > 0 {
+				// the paths of the conflicts are relative to the merged values
+				prefix := tree.JsonKeyToPath(differ.leftCurrentDiff.Key)
+				for i := range conflicts {
+					conflicts[i] = prefix + strings.TrimPrefix(conflicts[i], "$")
+				}
+				result := differ.processMergedDiff(tree.DiffOpDivergentModifyConflict, nil)
+				result.ConflictPaths = conflicts
+				return result, nil
 			} else {
 				return differ.processMergedDiff(tree.DiffOpDivergentModifyResolved, mergedValue), nil
 			}
@@ -136,6 +140,88 @@ func (differ *ThreeWayJsonDiffer) Next(ctx context.Context) (ThreeWayJsonDiff, e
 	}
 }
 
+// mergeValuesAt merges the values of the three documents at |key|, which both sides changed in ways that can't be
+// merged location by location, and skips the diffs of both sides at or under |key|. The values are merged in memory
+// if the array merge strategy merges arrays, and are otherwise a conflict.
+func (differ *ThreeWayJsonDiffer) mergeValuesAt(ctx context.Context, key []byte) (ThreeWayJsonDiff, error) {
+	if err := differ.skipDiffsUnder(ctx, key); err != nil {
+		return ThreeWayJsonDiff{}, err
+	}
+	if !differ.arrayMerge.mergesArrays() {
+		return ThreeWayJsonDiff{Op: tree.DiffOpDivergentModifyConflict, Key: key}, nil
+	}
+
+	base, err := lookupJsonKey(ctx, differ.base, key)
+	if err != nil {
+		return ThreeWayJsonDiff{}, err
+	}
+	left, err := lookupJsonKey(ctx, differ.left, key)
+	if err != nil {
+		return ThreeWayJsonDiff{}, err
+	}
+	right, err := lookupJsonKey(ctx, differ.right, key)
+	if err != nil {
+		return ThreeWayJsonDiff{}, err
+	}
+
+	jm := jsonMerge{arrayMerge: differ.arrayMerge}
+	merged, err := jm.merge(tree.JsonKeyToPath(key), base, left, right)
+	if err != nil {
+		return ThreeWayJsonDiff{}, err
+	}
+	if len(jm.conflicts) > 0 {
+		return ThreeWayJsonDiff{Op: tree.DiffOpDivergentModifyConflict, Key: key, ConflictPaths: jm.conflicts}, nil
+	}
+	if !merged.exists {
+		return ThreeWayJsonDiff{Op: tree.DiffOpDivergentDeleteResolved, Key: key}, nil
+	}
+	return ThreeWayJsonDiff{Op: tree.DiffOpDivergentModifyResolved, Key: key, Merged: types.JSONDocument{Val: merged.val}}, nil
+}
+
+// skipDiffsUnder discards the diffs of both sides whose location is |key| or a child of |key|.
+func (differ *ThreeWayJsonDiffer) skipDiffsUnder(ctx context.Context, key []byte) error {
+	under := func(diff *tree.JsonDiff) bool {
+		return diff != nil && (bytes.Equal(diff.Key, key) || (len(diff.Key) > len(key) && tree.IsJsonKeyPrefix(diff.Key, key)))
+	}
+	for {
+		if under(differ.leftCurrentDiff) {
+			differ.leftCurrentDiff = nil
+		} else if under(differ.rightCurrentDiff) {
+			differ.rightCurrentDiff = nil
+		} else {
+			return nil
+		}
+		if err := differ.loadNextDiff(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// lookupJsonKey returns the value of |doc| at the location encoded in |key|.
+func lookupJsonKey(ctx context.Context, doc sql.JSONWrapper, key []byte) (jsonValue, error) {
+	var v sql.JSONWrapper
+	var err error
+	switch doc := doc.(type) {
+	case tree.IndexedJsonDocument:
+		v, err = doc.LookupWithKey(ctx, key)
+	case types.SearchableJSON:
+		v, err = doc.Lookup(ctx, tree.JsonKeyToPath(key))
+	default:
+		var val interface{}
+		if val, err = doc.ToInterface(); err == nil {
+			v, err = types.JSONDocument{Val: val}.Lookup(ctx, tree.JsonKeyToPath(key))
+		}
+	}
+	if err != nil || v == nil {
+		return jsonValue{}, err
+	}
+	val, err := v.ToInterface()
+	if err != nil {
+		return jsonValue{}, err
+	}
+	return jsonValue{val: val, exists: true}, nil
+}
+
 func (differ *ThreeWayJsonDiffer) loadNextDiff(ctx context.Context) error {
 	if differ.leftCurrentDiff == nil && !differ.leftIsDone {
 		newLeftDiff, err := differ.leftDiffer.Next(ctx)
@@ -41,7 +41,9 @@ type sqlEngineTableReader struct {
 	iter sql.RowIter
 }
 
-func NewSqlEngineReader(ctx context.Context, dEnv *env.DoltEnv, tableName string) (*sqlEngineTableReader, error) {
+// NewSqlEngineReader returns a reader of the rows of |tableName|, read as the root user. If |masked| is true, the
+// columns listed in dolt_column_masks are masked, as they are for users who don't administer the database.
+func NewSqlEngineReader(ctx context.Context, dEnv *env.DoltEnv, tableName string, masked bool) (*sqlEngineTableReader, error) {
 	mrEnv, err := env.MultiEnvForDirectory(ctx, dEnv.Config.WriteableConfig(), dEnv.FS, dEnv.Version, dEnv)
 	if err != nil {
 		return nil, err
@@ -49,6 +51,7 @@ func NewSqlEngineReader(ctx context.Context, dEnv *env.DoltEnv, tableName string
 
 	config := &engine.SqlEngineConfig{
 		ServerUser: "root",
+		ServerHost: "localhost",
 		Autocommit: true,
 	}
 	se, err := engine.NewSqlEngine(
@@ -67,6 +70,18 @@ func NewSqlEngineReader(ctx context.Context, dEnv *env.DoltEnv, tableName string
 	sqlCtx.SetCurrentDatabase(mrEnv.GetFirstDatabase())
 
 	sqlEngine := se.GetUnderlyingEngine()
+	// read as a super user, like the sql command does, which column masks don't apply to unless asked for
+	rawDb := sqlEngine.Analyzer.Catalog.MySQLDb
+	ed := rawDb.Editor()
+	rawDb.AddSuperUser(ed, config.ServerUser, config.ServerHost, "")
+	ed.Close()
+	sqlCtx.Session.SetClient(sql.Client{User: config.ServerUser, Address: config.ServerHost})
+	if masked {
+		if err = sqlCtx.SetSessionVariable(sqlCtx, dsess.MaskColumns, int8(1)); err != nil {
+			return nil, err
+		}
+	}
+
 	binder := planbuilder.New(sqlCtx, sqlEngine.Analyzer.Catalog, sqlEngine.EventScheduler, sqlEngine.Parser)
 	ret, _, _, _, err := binder.Parse(fmt.Sprintf("show create table `%s`", tableName), nil, false)
 	if err != nil {
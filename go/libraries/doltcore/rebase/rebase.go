@@ -17,6 +17,7 @@ package rebase
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/shopspring/decimal"
@@ -33,12 +34,24 @@ const (
 	RebaseActionFixup  = "fixup"
 	RebaseActionDrop   = "drop"
 	RebaseActionReword = "reword"
+	RebaseActionExec   = "exec"
+)
+
+const (
+	// AutosquashFixupPrefix marks a commit message as a fixup of the commit named by the rest of the subject line.
+	AutosquashFixupPrefix = "fixup! "
+	// AutosquashSquashPrefix marks a commit message as a squash into the commit named by the rest of the subject line.
+	AutosquashSquashPrefix = "squash! "
 )
 
 // ErrInvalidRebasePlanSquashFixupWithoutPick is returned when a rebase plan attempts to squash or
 // fixup a commit without first picking or rewording a commit.
 var ErrInvalidRebasePlanSquashFixupWithoutPick = fmt.Errorf("invalid rebase plan: squash and fixup actions must appear after a pick or reword action")
 
+// ErrInvalidRebasePlanExecWithoutStatement is returned when a rebase plan contains an exec action without a SQL
+// statement to execute.
+var ErrInvalidRebasePlanExecWithoutStatement = fmt.Errorf("invalid rebase plan: exec actions must specify the SQL statement to run in the commit_message column")
+
 // RebasePlanDatabase is a database that can save and load a rebase plan.
 type RebasePlanDatabase interface {
 	// SaveRebasePlan saves the given rebase plan to the database.
@@ -54,7 +67,8 @@ type RebasePlan struct {
 }
 
 // RebasePlanStep describes a single step in a rebase plan, such as dropping a
-// commit, squashing a commit into the previous commit, etc.
+// commit, squashing a commit into the previous commit, etc. Exec steps don't
+// refer to a commit; their CommitMsg holds the SQL statement to run instead.
 type RebasePlanStep struct {
 	RebaseOrder decimal.Decimal
 	Action      string
@@ -69,12 +83,25 @@ func (rps *RebasePlanStep) RebaseOrderAsFloat() float32 {
 	return float32(f64)
 }
 
+// RebasePlanOptions controls how the commits to rebase are chosen and ordered when a rebase plan is created.
+type RebasePlanOptions struct {
+	// Autosquash moves each commit whose message starts with "fixup! " or "squash! " directly after the commit
+	// named by the rest of its subject line, and changes its action to fixup or squash.
+	Autosquash bool
+}
+
 // CreateDefaultRebasePlan creates and returns the default rebase plan for the commits between
 // |startCommit| and |upstreamCommit|, equivalent to the log of startCommit..upstreamCommit. The
 // default plan includes each of those commits, in the same order they were originally applied, and
 // each step in the plan will have the default, pick, action. If the plan cannot be generated for
 // any reason, such as disconnected or invalid commits specified, then an error is returned.
 func CreateDefaultRebasePlan(ctx *sql.Context, startCommit, upstreamCommit *doltdb.Commit) (*RebasePlan, error) {
+	return CreateRebasePlan(ctx, startCommit, upstreamCommit, RebasePlanOptions{})
+}
+
+// CreateRebasePlan creates and returns the rebase plan for the commits between |startCommit| and
+// |upstreamCommit|, like CreateDefaultRebasePlan, adjusted as requested by |opts|.
+func CreateRebasePlan(ctx *sql.Context, startCommit, upstreamCommit *doltdb.Commit, opts RebasePlanOptions) (*RebasePlan, error) {
 	commits, err := findRebaseCommits(ctx, startCommit, upstreamCommit)
 	if err != nil {
 		return nil, err
@@ -97,18 +124,98 @@ func CreateDefaultRebasePlan(ctx *sql.Context, startCommit, upstreamCommit *dolt
 		}
 
 		plan.Steps = append(plan.Steps, RebasePlanStep{
-			RebaseOrder: decimal.NewFromFloat32(float32(len(commits) - idx)),
-			Action:      RebaseActionPick,
-			CommitHash:  hash.String(),
-			CommitMsg:   meta.Description,
+			Action:     RebaseActionPick,
+			CommitHash: hash.String(),
+			CommitMsg:  meta.Description,
 		})
 	}
 
+	if opts.Autosquash {
+		plan.Steps = autosquashSteps(plan.Steps)
+	}
+	for i := range plan.Steps {
+		plan.Steps[i].RebaseOrder = decimal.NewFromFloat32(float32(i + 1))
+	}
+
 	return &plan, nil
 }
 
+// autosquashSteps reorders |steps| so that each commit marked with a "fixup! " or "squash! " subject line directly
+// follows the commit it names, after any earlier fixups of that commit, and sets its action to match the marker. A
+// commit is named by its subject line, a prefix of its subject line, or a prefix of its commit hash. Marked commits
+// that don't name an earlier commit in the plan keep their place and action.
+func autosquashSteps(steps []RebasePlanStep) []RebasePlanStep {
+	var groups [][]RebasePlanStep
+	for _, step := range steps {
+		action, target, ok := parseAutosquashSubject(step.CommitMsg)
+		if ok {
+			if i := findAutosquashTarget(groups, target); i >= 0 {
+				step.Action = action
+				groups[i] = append(groups[i], step)
+				continue
+			}
+		}
+		groups = append(groups, []RebasePlanStep{step})
+	}
+
+	reordered := make([]RebasePlanStep, 0, len(steps))
+	for _, group := range groups {
+		reordered = append(reordered, group...)
+	}
+	return reordered
+}
+
+// parseAutosquashSubject returns the action requested by the autosquash marker at the start of |commitMsg|, and the
+// subject line or hash of the commit it targets. Repeated markers, such as "fixup! fixup! ", target the commit named
+// after the last marker.
+func parseAutosquashSubject(commitMsg string) (action string, target string, ok bool) {
+	subject := commitSubject(commitMsg)
+	for {
+		switch {
+		case strings.HasPrefix(subject, AutosquashFixupPrefix):
+			subject = strings.TrimPrefix(subject, AutosquashFixupPrefix)
+			if action == "" {
+				action = RebaseActionFixup
+			}
+		case strings.HasPrefix(subject, AutosquashSquashPrefix):
+			subject = strings.TrimPrefix(subject, AutosquashSquashPrefix)
+			if action == "" {
+				action = RebaseActionSquash
+			}
+		default:
+			target = strings.TrimSpace(subject)
+			return action, target, action != "" && target != ""
+		}
+	}
+}
+
+// findAutosquashTarget returns the index of the group in |groups| whose first commit is named by |target|, or -1 if
+// there is none. Exact subject matches are preferred over hash prefixes, which are preferred over subject prefixes.
+func findAutosquashTarget(groups [][]RebasePlanStep, target string) int {
+	matchers := []func(step RebasePlanStep) bool{
+		func(step RebasePlanStep) bool { return commitSubject(step.CommitMsg) == target },
+		func(step RebasePlanStep) bool { return strings.HasPrefix(step.CommitHash, target) },
+		func(step RebasePlanStep) bool { return strings.HasPrefix(commitSubject(step.CommitMsg), target) },
+	}
+	for _, matches := range matchers {
+		for i, group := range groups {
+			if matches(group[0]) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// commitSubject returns the first line of |commitMsg|.
+func commitSubject(commitMsg string) string {
+	subject, _, _ := strings.Cut(commitMsg, "\n")
+	return subject
+}
+
 // ValidateRebasePlan returns a validation error for invalid states in a rebase plan, such as
-// squash or fixup actions appearing in the plan before a pick or reword action.
+// squash or fixup actions appearing in the plan before a pick or reword action, or exec actions
+// without a SQL statement.
 func ValidateRebasePlan(ctx *sql.Context, plan *RebasePlan) error {
 	seenPick := false
 	seenReword := false
@@ -130,6 +237,12 @@ func ValidateRebasePlan(ctx *sql.Context, plan *RebasePlan) error {
 			if !seenPick && !seenReword {
 				return ErrInvalidRebasePlanSquashFixupWithoutPick
 			}
+
+		case RebaseActionExec:
+			if strings.TrimSpace(step.CommitMsg) == "" {
+				return ErrInvalidRebasePlanExecWithoutStatement
+			}
+			continue
 		}
 
 		if err := validateCommit(ctx, step.CommitHash); err != nil {
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -47,6 +48,12 @@ type RemoteChunkStore struct {
 	HttpHost   string
 	httpScheme string
 
+	// BranchProtection is enforced against every root update made through Commit.
+	BranchProtection BranchProtection
+	// PreReceiveHooks are run, in order, against every root update made through Commit which passes
+	// BranchProtection.
+	PreReceiveHooks []PreReceiveHook
+
 	concurrencyControl remotesapi.PushConcurrencyControl
 
 	csCache DBCache
@@ -90,6 +97,14 @@ func getRepoPath(req repoRequest) string {
 	panic("unexpected empty repo_path and nil repo_id")
 }
 
+// getRepoName returns the name of the repository of |req|, which is the last element of its path.
+func getRepoName(req repoRequest) string {
+	if req.GetRepoPath() != "" {
+		return path.Base(req.GetRepoPath())
+	}
+	return req.GetRepoId().RepoName
+}
+
 func (rs *RemoteChunkStore) HasChunks(ctx context.Context, req *remotesapi.HasChunksRequest) (*remotesapi.HasChunksResponse, error) {
 	logger := getReqLogger(rs.lgr, "HasChunks")
 	if err := ValidateHasChunksRequest(req); err != nil {
@@ -501,6 +516,26 @@ func (rs *RemoteChunkStore) Commit(ctx context.Context, req *remotesapi.CommitRe
 	currHash := hash.New(req.Current)
 	lastHash := hash.New(req.Last)
 
+	pusher, _ := PrincipalFromContext(ctx)
+	ru := newRootUpdate(cs, lastHash, currHash)
+	err = checkBranchProtection(ctx, rs.BranchProtection, ru, getRepoName(req), pusher)
+	if errors.Is(err, ErrPushRejected) {
+		logger.WithError(err).Warn("push rejected by branch protection")
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		logger.WithError(err).Error("error checking branch protection")
+		return nil, status.Errorf(codes.Internal, "failed to check branch protection: %v", err)
+	}
+
+	err = runPreReceiveHooks(ctx, rs.PreReceiveHooks, repoPath, pusher, ru)
+	if errors.Is(err, ErrPushRejected) {
+		logger.WithError(err).Warn("push rejected by pre-receive hook")
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		logger.WithError(err).Error("error running pre-receive hooks")
+		return nil, status.Errorf(codes.Internal, "failed to run pre-receive hooks: %v", err)
+	}
+
 	var ok bool
 	ok, err = cs.Commit(ctx, currHash, lastHash)
 	if err != nil {
@@ -16,6 +16,7 @@ package remotesrv
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
@@ -71,10 +72,15 @@ func (si *ServerInterceptor) Stream() grpc.StreamServerInterceptor {
 			return err
 		}
 
-		if err := si.authenticate(ss.Context(), needSuperUser); err != nil {
+		ctx, err := si.authenticate(ss.Context(), needSuperUser)
+		if err != nil {
 			return err
 		}
 
+		// Streaming requests can name a different repository in each message, so each one is authorized as
+		// it is received.
+		ss = &authenticatedServerStream{ServerStream: ss, si: si, authCtx: ctx, needSuperUser: needSuperUser}
+
 		return handler(srv, ss)
 	}
 }
@@ -86,11 +92,16 @@ func (si *ServerInterceptor) Unary() grpc.UnaryServerInterceptor {
 			return nil, err
 		}
 
-		if err := si.authenticate(ctx, needSuperUser); err != nil {
+		authCtx, err := si.authenticate(ctx, needSuperUser)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := si.authorizeRepo(authCtx, req, needSuperUser); err != nil {
 			return nil, err
 		}
 
-		return handler(ctx, req)
+		return handler(authCtx, req)
 	}
 }
 
@@ -114,20 +125,87 @@ func requireSuperUser(path string) (bool, error) {
 }
 
 // authenticate checks the incoming request for authentication credentials and validates them.  If the user is
-// legitimate, an authorization check is performed. If no error is returned, the user should be allowed to proceed.
-func (si *ServerInterceptor) authenticate(ctx context.Context, needsSuperUser bool) error {
+// legitimate, an authorization check is performed. If no error is returned, the user should be allowed to proceed,
+// and the returned context carries the authenticated identity for any further per-repository authorization.
+func (si *ServerInterceptor) authenticate(ctx context.Context, needsSuperUser bool) (context.Context, error) {
 	ctx, err := si.AccessController.ApiAuthenticate(ctx)
 	if err != nil {
 		si.Lgr.Warnf("authentication failed: %s", err.Error())
-		return status.Error(codes.Unauthenticated, err.Error())
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	// Per-repository authorization happens once the request has been read.
+	if _, ok := si.AccessController.(RepoAccessControl); ok {
+		return ctx, nil
 	}
 
 	// Have a valid user in the context.  Check authorization.
 	if authorized, err := si.AccessController.ApiAuthorize(ctx, needsSuperUser); !authorized {
 		si.Lgr.Warnf("authorization failed: %s", err.Error())
-		return status.Error(codes.PermissionDenied, err.Error())
+		return nil, status.Error(codes.PermissionDenied, err.Error())
 	}
 
 	// Access Granted.
+	return ctx, nil
+}
+
+// authorizeRepo authorizes |req| against the repository it names when the AccessController is a
+// RepoAccessControl. It is a no-op otherwise.
+func (si *ServerInterceptor) authorizeRepo(ctx context.Context, req interface{}, needsSuperUser bool) error {
+	rac, ok := si.AccessController.(RepoAccessControl)
+	if !ok {
+		return nil
+	}
+
+	var authorized bool
+	var err error
+	if rr, ok := req.(repoRequest); ok && (rr.GetRepoPath() != "" || rr.GetRepoId() != nil) {
+		authorized, err = rac.ApiAuthorizeRepo(ctx, getRepoPath(rr), needsSuperUser)
+	} else {
+		authorized, err = rac.ApiAuthorize(ctx, needsSuperUser)
+	}
+	if !authorized {
+		if err == nil {
+			err = errors.New("API Authorization Failure")
+		}
+		si.Lgr.Warnf("authorization failed: %s", err.Error())
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
 	return nil
 }
+
+// authenticatedServerStream carries the authenticated context of a stream to its handler, and authorizes every
+// message received on it against the repository it names.
+type authenticatedServerStream struct {
+	grpc.ServerStream
+	si            *ServerInterceptor
+	authCtx       context.Context
+	needSuperUser bool
+}
+
+func (s *authenticatedServerStream) Context() context.Context {
+	return s.authCtx
+}
+
+func (s *authenticatedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.si.authorizeRepo(s.authCtx, m, s.needSuperUser)
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of |ctx| which records |name| as the authenticated identity making the
+// request. AccessControl implementations call it from ApiAuthenticate so that request handlers, such as the
+// branch protection checks in Commit, can see who is pushing.
+func ContextWithPrincipal(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, principalKey{}, name)
+}
+
+// PrincipalFromContext returns the identity recorded by ContextWithPrincipal, if any.
+func PrincipalFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(principalKey{}).(string)
+	return name, ok
+}
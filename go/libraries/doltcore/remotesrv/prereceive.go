@@ -0,0 +1,164 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// Push describes a root update requested by a client, after its chunks have been uploaded and before the root of
+// the repository is moved to include them.
+type Push struct {
+	// RepoPath is the path of the repository being pushed to, as requested by the client.
+	RepoPath string
+	// Pusher is the authenticated principal making the push, or "" if the server does not authenticate requests.
+	Pusher  string
+	OldRoot hash.Hash
+	NewRoot hash.Hash
+	// Updates lists every ref which differs between OldRoot and NewRoot, sorted by ref name.
+	Updates []RefUpdate
+}
+
+// PreReceiveHook validates a Push before it is accepted. Returning an error which wraps ErrPushRejected rejects the
+// push, and the error's message is returned to the client. Any other error fails the push as an internal error.
+type PreReceiveHook interface {
+	PreReceive(ctx context.Context, push Push) error
+}
+
+// DefaultPreReceiveTimeout is the time an ExecPreReceiveHook is given to run when its Timeout is zero.
+const DefaultPreReceiveTimeout = time.Minute
+
+// ExecPreReceiveHook is a PreReceiveHook which runs an external executable. The executable receives the push as a
+// JSON document on stdin, for example:
+//
+//	{
+//	  "repo": "org/repo",
+//	  "pusher": "ci-runner-1",
+//	  "old_root": "...",
+//	  "new_root": "...",
+//	  "updates": [{"ref": "refs/heads/main", "old": "<commit hash>", "new": "<commit hash>"}]
+//	}
+//
+// where "old" is empty for a created ref and "new" is empty for a deleted one. A non-zero exit status rejects the
+// push, and whatever the executable wrote to stdout and stderr is returned to the client as the reason.
+type ExecPreReceiveHook struct {
+	Path    string
+	Args    []string
+	Timeout time.Duration
+}
+
+var _ PreReceiveHook = ExecPreReceiveHook{}
+
+type execPreReceiveInput struct {
+	Repo    string                 `json:"repo"`
+	Pusher  string                 `json:"pusher"`
+	OldRoot string                 `json:"old_root"`
+	NewRoot string                 `json:"new_root"`
+	Updates []execPreReceiveUpdate `json:"updates"`
+}
+
+type execPreReceiveUpdate struct {
+	Ref string `json:"ref"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+func hashOrEmpty(h hash.Hash) string {
+	if h.IsEmpty() {
+		return ""
+	}
+	return h.String()
+}
+
+func (h ExecPreReceiveHook) PreReceive(ctx context.Context, push Push) error {
+	in := execPreReceiveInput{
+		Repo:    push.RepoPath,
+		Pusher:  push.Pusher,
+		OldRoot: hashOrEmpty(push.OldRoot),
+		NewRoot: hashOrEmpty(push.NewRoot),
+		Updates: make([]execPreReceiveUpdate, len(push.Updates)),
+	}
+	for i, u := range push.Updates {
+		in.Updates[i] = execPreReceiveUpdate{Ref: u.Ref, Old: hashOrEmpty(u.Old), New: hashOrEmpty(u.New)}
+	}
+	stdin, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	timeout := h.Timeout
+	if timeout == 0 {
+		timeout = DefaultPreReceiveTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.Path, h.Args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	// Don't wait on grandchildren which are still holding stdout open after the hook itself is killed.
+	cmd.WaitDelay = time.Second
+	err = cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w: pre-receive hook timed out after %v", ErrPushRejected, timeout)
+		}
+		msg := strings.TrimSpace(out.String())
+		if msg == "" {
+			msg = fmt.Sprintf("pre-receive hook exited with status %d", exitErr.ExitCode())
+		}
+		return fmt.Errorf("%w: %s", ErrPushRejected, msg)
+	} else if err != nil {
+		return fmt.Errorf("error running pre-receive hook %s: %w", h.Path, err)
+	}
+	return nil
+}
+
+// runPreReceiveHooks runs |hooks| in order against the push of |ru| to |repoPath|, stopping at the first error.
+func runPreReceiveHooks(ctx context.Context, hooks []PreReceiveHook, repoPath, pusher string, ru rootUpdate) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+	updates, err := ru.refUpdates(ctx)
+	if err != nil {
+		return err
+	}
+	push := Push{
+		RepoPath: repoPath,
+		Pusher:   pusher,
+		OldRoot:  ru.last,
+		NewRoot:  ru.curr,
+		Updates:  updates,
+	}
+	for _, hook := range hooks {
+		if err := hook.PreReceive(ctx, push); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestExecPreReceiveHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("requires sh")
+	}
+
+	ctx := context.Background()
+	newHead := hash.Of([]byte("new head"))
+	push := Push{
+		RepoPath: "org/repo",
+		Pusher:   "ci",
+		NewRoot:  hash.Of([]byte("new root")),
+		Updates:  []RefUpdate{{Ref: "refs/heads/main", New: newHead}},
+	}
+	sh := func(script string) ExecPreReceiveHook {
+		return ExecPreReceiveHook{Path: "sh", Args: []string{"-c", script}}
+	}
+
+	expected := `{"repo":"org/repo","pusher":"ci","old_root":"","new_root":"` + push.NewRoot.String() +
+		`","updates":[{"ref":"refs/heads/main","old":"","new":"` + newHead.String() + `"}]}`
+	assert.NoError(t, sh(`test "$(cat)" = '`+expected+`'`).PreReceive(ctx, push))
+
+	err := sh(`cat > /dev/null; echo "table secrets is forbidden" >&2; exit 1`).PreReceive(ctx, push)
+	assert.ErrorIs(t, err, ErrPushRejected)
+	assert.EqualError(t, err, "push rejected: table secrets is forbidden")
+
+	err = sh(`exit 3`).PreReceive(ctx, push)
+	assert.ErrorIs(t, err, ErrPushRejected)
+	assert.Contains(t, err.Error(), "exited with status 3")
+
+	hook := sh(`sleep 5`)
+	hook.Timeout = 10 * time.Millisecond
+	err = hook.PreReceive(ctx, push)
+	assert.ErrorIs(t, err, ErrPushRejected)
+	assert.Contains(t, err.Error(), "timed out")
+
+	err = ExecPreReceiveHook{Path: "/does/not/exist"}.PreReceive(ctx, push)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrPushRejected)
+}
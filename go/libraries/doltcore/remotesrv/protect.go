@@ -0,0 +1,302 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/signing"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const branchRefPrefix = "refs/heads/"
+
+// ErrPushRejected is the cause of every error returned when a push violates a BranchProtectionRule. The Commit RPC
+// returns it to clients with codes.FailedPrecondition.
+var ErrPushRejected = errors.New("push rejected")
+
+// BranchProtectionRule restricts how pushes may update the branches matching |Branch|, a path.Match pattern such as
+// "main" or "release/*". A zero rule matches its branches but does not restrict them.
+type BranchProtectionRule struct {
+	Branch string `yaml:"branch"`
+	// ForbidForcePush rejects updates where the new head does not descend from the current head.
+	ForbidForcePush bool `yaml:"forbid_force_push"`
+	// FastForwardOnly rejects updates where the current head is not on the first-parent history of the new head. It
+	// is stricter than ForbidForcePush: a push whose new head merged the current head in as a second parent is
+	// rejected.
+	FastForwardOnly bool `yaml:"fast_forward_only"`
+	// ForbidDeletion rejects pushes which delete the branch.
+	ForbidDeletion bool `yaml:"forbid_deletion"`
+	// AllowedPushers, when non-empty, lists the only principals which may create, update or delete the branch.
+	AllowedPushers []string `yaml:"allowed_pushers"`
+	// RequireSignedHead rejects updates whose new head commit does not have a good signature. gpg signatures must be
+	// verifiable by the server's gpg keyring, and ssh signatures must be made by a key in AllowedSignersFile. Commits
+	// signed by earlier versions of Dolt are rejected, as their signatures name the database of the client.
+	RequireSignedHead bool `yaml:"require_signed_head"`
+	// AllowedSignersFile is the ssh allowed signers file which ssh signatures on head commits are verified against.
+	AllowedSignersFile string `yaml:"allowed_signers_file"`
+}
+
+// BranchProtection is the ordered list of rules enforced when a push updates the root of a repository. The first
+// rule whose pattern matches a branch is the one applied to it.
+type BranchProtection []BranchProtectionRule
+
+// ReadBranchProtection reads BranchProtection from its YAML representation, a list of rules under the key
+// "branch_protection", for example:
+//
+//	branch_protection:
+//	- branch: main
+//	  forbid_force_push: true
+//	  forbid_deletion: true
+//	  allowed_pushers: [ci-runner-1]
+func ReadBranchProtection(r io.Reader) (BranchProtection, error) {
+	var cfg struct {
+		BranchProtection BranchProtection `yaml:"branch_protection"`
+	}
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.UnmarshalStrict(bs, &cfg); err != nil {
+		return nil, err
+	}
+	for _, rule := range cfg.BranchProtection {
+		if rule.Branch == "" {
+			return nil, errors.New("branch protection rule is missing 'branch'")
+		}
+		if _, err := path.Match(rule.Branch, ""); err != nil {
+			return nil, fmt.Errorf("invalid branch protection pattern '%s': %w", rule.Branch, err)
+		}
+	}
+	return cfg.BranchProtection, nil
+}
+
+func (bp BranchProtection) ruleFor(branch string) (BranchProtectionRule, bool) {
+	for _, rule := range bp {
+		if matched, _ := path.Match(rule.Branch, branch); matched {
+			return rule, true
+		}
+	}
+	return BranchProtectionRule{}, false
+}
+
+// RefUpdate is a change to a single ref made by moving a repository's root from one hash to another. |Old| is
+// empty for a created ref and |New| is empty for a deleted one.
+type RefUpdate struct {
+	Ref string
+	Old hash.Hash
+	New hash.Hash
+}
+
+func (u RefUpdate) IsCreate() bool {
+	return u.Old.IsEmpty()
+}
+
+func (u RefUpdate) IsDelete() bool {
+	return u.New.IsEmpty()
+}
+
+// rootUpdate gives read access to the values reachable from the old and new root of a push. Both roots must be
+// readable from the same chunk store.
+type rootUpdate struct {
+	vs   *types.ValueStore
+	ns   tree.NodeStore
+	db   datas.Database
+	last hash.Hash
+	curr hash.Hash
+}
+
+func newRootUpdate(cs chunks.ChunkStore, last, curr hash.Hash) rootUpdate {
+	vs := types.NewValueStore(cs)
+	ns := tree.NewNodeStore(cs)
+	return rootUpdate{vs: vs, ns: ns, db: datas.NewTypesDatabase(vs, ns), last: last, curr: curr}
+}
+
+// refUpdates returns every ref which differs between the old and new root, sorted by ref name.
+func (ru rootUpdate) refUpdates(ctx context.Context) ([]RefUpdate, error) {
+	oldRefs, err := ru.refs(ctx, ru.last)
+	if err != nil {
+		return nil, err
+	}
+	newRefs, err := ru.refs(ctx, ru.curr)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []RefUpdate
+	for name, oldAddr := range oldRefs {
+		if newAddr := newRefs[name]; newAddr != oldAddr {
+			updates = append(updates, RefUpdate{Ref: name, Old: oldAddr, New: newAddr})
+		}
+	}
+	for name, newAddr := range newRefs {
+		if _, ok := oldRefs[name]; !ok {
+			updates = append(updates, RefUpdate{Ref: name, New: newAddr})
+		}
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].Ref < updates[j].Ref
+	})
+	return updates, nil
+}
+
+func (ru rootUpdate) refs(ctx context.Context, root hash.Hash) (map[string]hash.Hash, error) {
+	dss, err := ru.db.DatasetsByRootHash(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string]hash.Hash)
+	err = dss.IterAll(ctx, func(name string, addr hash.Hash) error {
+		refs[name] = addr
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+func (ru rootUpdate) loadCommit(ctx context.Context, addr hash.Hash) (*datas.Commit, error) {
+	return datas.LoadCommitAddr(ctx, ru.vs, addr)
+}
+
+// checkBranchProtection returns an error wrapping ErrPushRejected if any branch updated by moving the root from
+// |last| to |curr| violates |bp|. |repoName| is the name of the repository pushed to, which commits signed by earlier
+// versions of Dolt name as their database. |pusher| is the authenticated principal making the push, or "" if the
+// server does not authenticate requests.
+func checkBranchProtection(ctx context.Context, bp BranchProtection, ru rootUpdate, repoName, pusher string) error {
+	if len(bp) == 0 {
+		return nil
+	}
+	updates, err := ru.refUpdates(ctx)
+	if err != nil {
+		return err
+	}
+	for _, u := range updates {
+		if !strings.HasPrefix(u.Ref, branchRefPrefix) {
+			continue
+		}
+		branch := strings.TrimPrefix(u.Ref, branchRefPrefix)
+		rule, ok := bp.ruleFor(branch)
+		if !ok {
+			continue
+		}
+		if err := rule.check(ctx, ru, repoName, branch, u, pusher); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rule BranchProtectionRule) check(ctx context.Context, ru rootUpdate, repoName, branch string, u RefUpdate, pusher string) error {
+	if len(rule.AllowedPushers) > 0 && !containsString(rule.AllowedPushers, pusher) {
+		if pusher == "" {
+			return fmt.Errorf("%w: branch '%s' is protected and only allows pushes from authenticated users", ErrPushRejected, branch)
+		}
+		return fmt.Errorf("%w: branch '%s' is protected and '%s' is not allowed to push to it", ErrPushRejected, branch, pusher)
+	}
+
+	if u.IsDelete() {
+		if rule.ForbidDeletion {
+			return fmt.Errorf("%w: branch '%s' is protected and cannot be deleted", ErrPushRejected, branch)
+		}
+		return nil
+	}
+
+	newHead, err := ru.loadCommit(ctx, u.New)
+	if err != nil {
+		return err
+	}
+
+	if !u.IsCreate() && (rule.ForbidForcePush || rule.FastForwardOnly) {
+		oldHead, err := ru.loadCommit(ctx, u.Old)
+		if err != nil {
+			return err
+		}
+		if rule.FastForwardOnly {
+			ok, err := isFirstParentAncestor(ctx, ru, oldHead, newHead)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("%w: branch '%s' is protected and only accepts fast-forward updates along its first-parent history", ErrPushRejected, branch)
+			}
+		} else {
+			ancestor, ok, err := datas.FindCommonAncestor(ctx, newHead, oldHead, ru.vs, ru.vs, ru.ns, ru.ns)
+			if err != nil {
+				return err
+			}
+			if !ok || ancestor != oldHead.Addr() {
+				return fmt.Errorf("%w: branch '%s' is protected and cannot be force pushed", ErrPushRejected, branch)
+			}
+		}
+	}
+
+	if rule.RequireSignedHead {
+		meta, err := datas.GetCommitMeta(ctx, newHead.NomsValue())
+		if err != nil {
+			return err
+		}
+		if meta == nil || meta.Signature == "" {
+			return fmt.Errorf("%w: branch '%s' is protected and requires a signed head commit; %s is not signed", ErrPushRejected, branch, newHead.Addr().String())
+		}
+		parents, err := datas.GetCommitParents(ctx, ru.vs, newHead.NomsValue())
+		if err != nil {
+			return err
+		}
+		cfg := signing.Config{AllowedSignersFile: rule.AllowedSignersFile}
+		if _, err := signing.VerifyCommit(ctx, cfg, repoName, newHead.NomsValue(), parents); err != nil {
+			return fmt.Errorf("%w: branch '%s' is protected and requires a signed head commit; the signature of %s could not be verified", ErrPushRejected, branch, newHead.Addr().String())
+		}
+	}
+
+	return nil
+}
+
+// isFirstParentAncestor returns true if |ancestor| is |c| or is reachable from |c| by following first parents.
+func isFirstParentAncestor(ctx context.Context, ru rootUpdate, ancestor, c *datas.Commit) (bool, error) {
+	for c.Height() > ancestor.Height() {
+		parents, err := datas.GetCommitParents(ctx, ru.vs, c.NomsValue())
+		if err != nil {
+			return false, err
+		}
+		if len(parents) == 0 {
+			return false, nil
+		}
+		c = parents[0]
+	}
+	return c.Addr() == ancestor.Addr(), nil
+}
+
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
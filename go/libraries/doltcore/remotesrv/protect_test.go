@@ -0,0 +1,190 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/gpg"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestReadBranchProtection(t *testing.T) {
+	bp, err := ReadBranchProtection(strings.NewReader(`
+branch_protection:
+- branch: main
+  forbid_force_push: true
+  forbid_deletion: true
+  allowed_pushers: [ci]
+- branch: release/*
+  fast_forward_only: true
+`))
+	require.NoError(t, err)
+	assert.Equal(t, BranchProtection{
+		{Branch: "main", ForbidForcePush: true, ForbidDeletion: true, AllowedPushers: []string{"ci"}},
+		{Branch: "release/*", FastForwardOnly: true},
+	}, bp)
+
+	_, err = ReadBranchProtection(strings.NewReader("branch_protection:\n- forbid_deletion: true\n"))
+	assert.Error(t, err)
+	_, err = ReadBranchProtection(strings.NewReader("branch_protection:\n- branch: main\n  unknown: true\n"))
+	assert.Error(t, err)
+}
+
+func TestCheckBranchProtection(t *testing.T) {
+	ctx := context.Background()
+	storage := &chunks.TestStorage{}
+	cs := storage.NewViewWithDefaultFormat()
+	db := datas.NewDatabase(cs)
+
+	root := func() hash.Hash {
+		h, err := cs.Root(ctx)
+		require.NoError(t, err)
+		return h
+	}
+	commit := func(ds datas.Dataset, v int, parents ...hash.Hash) datas.Dataset {
+		ds, err := db.Commit(ctx, ds, types.Float(v), datas.CommitOptions{Parents: parents})
+		require.NoError(t, err)
+		return ds
+	}
+
+	main, err := db.GetDataset(ctx, "refs/heads/main")
+	require.NoError(t, err)
+	main = commit(main, 1)
+	base := root()
+	firstHead, _ := main.MaybeHeadAddr()
+
+	main = commit(main, 2)
+	fastForward := root()
+
+	other, err := db.GetDataset(ctx, "refs/heads/other")
+	require.NoError(t, err)
+	other = commit(other, 3, firstHead)
+	otherHead, _ := other.MaybeHeadAddr()
+	mainHead, _ := main.MaybeHeadAddr()
+	// A merge whose first parent is |other| and whose second parent is the old head of main.
+	merged := commit(other, 4, otherHead, mainHead)
+	mergedHead, _ := merged.MaybeHeadAddr()
+
+	_, err = db.SetHead(ctx, main, otherHead, "")
+	require.NoError(t, err)
+	forcePushed := root()
+
+	main, err = db.GetDataset(ctx, "refs/heads/main")
+	require.NoError(t, err)
+	_, err = db.SetHead(ctx, main, mergedHead, "")
+	require.NoError(t, err)
+	secondParentMerge := root()
+
+	main, err = db.GetDataset(ctx, "refs/heads/main")
+	require.NoError(t, err)
+	_, err = db.Delete(ctx, main, "")
+	require.NoError(t, err)
+	deleted := root()
+
+	check := func(bp BranchProtection, last, curr hash.Hash, pusher string) error {
+		return checkBranchProtection(ctx, bp, newRootUpdate(cs, last, curr), "mydb", pusher)
+	}
+
+	t.Run("unprotected", func(t *testing.T) {
+		assert.NoError(t, check(nil, base, forcePushed, ""))
+		assert.NoError(t, check(BranchProtection{{Branch: "release/*", ForbidForcePush: true}}, base, forcePushed, ""))
+	})
+	t.Run("force push", func(t *testing.T) {
+		bp := BranchProtection{{Branch: "main", ForbidForcePush: true}}
+		assert.NoError(t, check(bp, base, fastForward, ""))
+		assert.ErrorIs(t, check(bp, fastForward, forcePushed, ""), ErrPushRejected)
+		assert.NoError(t, check(bp, forcePushed, secondParentMerge, ""))
+	})
+	t.Run("fast forward only", func(t *testing.T) {
+		bp := BranchProtection{{Branch: "main", FastForwardOnly: true}}
+		assert.NoError(t, check(bp, base, fastForward, ""))
+		assert.ErrorIs(t, check(bp, fastForward, forcePushed, ""), ErrPushRejected)
+		assert.ErrorIs(t, check(bp, fastForward, secondParentMerge, ""), ErrPushRejected)
+	})
+	t.Run("deletion", func(t *testing.T) {
+		assert.NoError(t, check(BranchProtection{{Branch: "main", ForbidForcePush: true}}, secondParentMerge, deleted, ""))
+		assert.ErrorIs(t, check(BranchProtection{{Branch: "main", ForbidDeletion: true}}, secondParentMerge, deleted, ""), ErrPushRejected)
+	})
+	t.Run("allowed pushers", func(t *testing.T) {
+		bp := BranchProtection{{Branch: "*", AllowedPushers: []string{"ci"}}}
+		assert.NoError(t, check(bp, base, fastForward, "ci"))
+		assert.ErrorIs(t, check(bp, base, fastForward, "alice"), ErrPushRejected)
+		assert.ErrorIs(t, check(bp, base, fastForward, ""), ErrPushRejected)
+	})
+	t.Run("signed head", func(t *testing.T) {
+		err := check(BranchProtection{{Branch: "main", RequireSignedHead: true}}, base, fastForward, "")
+		assert.ErrorIs(t, err, ErrPushRejected)
+		assert.Contains(t, err.Error(), "is not signed")
+	})
+}
+
+func TestCheckBranchProtectionLegacySignedHead(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+	ctx := context.Background()
+	t.Setenv("GNUPGHOME", t.TempDir())
+	t.Cleanup(func() { _ = exec.Command("gpgconf", "--kill", "gpg-agent").Run() })
+	const signer = "test@dolthub.com"
+	out, err := exec.Command("gpg", "--batch", "--passphrase", "", "--quick-gen-key", "Test User <"+signer+">", "ed25519", "sign", "never").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	storage := &chunks.TestStorage{}
+	cs := storage.NewViewWithDefaultFormat()
+	db := datas.NewDatabase(cs)
+	root := func() hash.Hash {
+		h, err := cs.Root(ctx)
+		require.NoError(t, err)
+		return h
+	}
+
+	main, err := db.GetDataset(ctx, "refs/heads/main")
+	require.NoError(t, err)
+	main, err = db.Commit(ctx, main, types.Float(1), datas.CommitOptions{})
+	require.NoError(t, err)
+	base := root()
+	head, _ := main.MaybeHead()
+	headRoot, err := datas.GetCommitRootHash(head)
+	require.NoError(t, err)
+
+	// earlier versions of Dolt clear-signed a message naming the database and the root values of the head and the commit
+	meta, err := datas.NewCommitMetaWithUserTS("Test User", signer, "a signed commit", time.Now())
+	require.NoError(t, err)
+	sign := func(ctx context.Context, valueAddr hash.Hash, _ []hash.Hash, meta *datas.CommitMeta) (string, error) {
+		message := fmt.Sprintf("db: %s\nMessage: %s\nName: %s\nEmail: %s\nDate: %s\nHead: %s\nStaged: %s\n",
+			"mydb", meta.Description, meta.Name, meta.Email, meta.Time().String(), headRoot.String(), valueAddr.String())
+		signature, err := gpg.Sign(ctx, signer, []byte(message))
+		return string(signature), err
+	}
+	_, err = db.Commit(ctx, main, types.Float(2), datas.CommitOptions{Meta: meta, Sign: sign})
+	require.NoError(t, err)
+	signed := root()
+
+	bp := BranchProtection{{Branch: "main", RequireSignedHead: true}}
+	assert.NoError(t, checkBranchProtection(ctx, bp, newRootUpdate(cs, base, signed), "mydb", ""))
+	assert.ErrorIs(t, checkBranchProtection(ctx, bp, newRootUpdate(cs, base, signed), "otherdb", ""), ErrPushRejected)
+}
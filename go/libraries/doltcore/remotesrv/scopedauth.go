@@ -0,0 +1,249 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"bufio"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/dolthub/dolt/go/libraries/utils/jwtauth"
+)
+
+// RepoAccessControl is an optional extension of AccessControl. When the AccessControl configured on a
+// ServerInterceptor also implements RepoAccessControl, every request which names a repository is authorized
+// against that repository, instead of through the repository-agnostic ApiAuthorize.
+type RepoAccessControl interface {
+	AccessControl
+	// ApiAuthorizeRepo checks that the authenticated principal in |ctx| may access |repoPath|. |superUserReq| is
+	// true for write RPCs, those in SUPER_USER_RPC_METHODS, and false for read RPCs, those in CLONE_ADMIN_RPC_METHODS.
+	ApiAuthorizeRepo(ctx context.Context, repoPath string, superUserReq bool) (bool, error)
+}
+
+// RepoScope is the level of access a RepoGrant gives to the repositories it matches.
+type RepoScope int
+
+const (
+	// ReadScope allows the read RPCs in CLONE_ADMIN_RPC_METHODS, i.e. clone, fetch and pull.
+	ReadScope RepoScope = iota
+	// WriteScope allows every RPC, including the ones in SUPER_USER_RPC_METHODS used by push. It implies ReadScope.
+	WriteScope
+)
+
+func (s RepoScope) String() string {
+	if s == WriteScope {
+		return "write"
+	}
+	return "read"
+}
+
+// RepoGrant gives |Scope| access to every repository path matching |Repo|. |Repo| is matched with path.Match,
+// so "*" matches every top level repository and "org/*" matches every repository under "org".
+type RepoGrant struct {
+	Repo  string
+	Scope RepoScope
+}
+
+// ParseRepoGrant parses a scope string of the form "read:<repo>" or "write:<repo>".
+func ParseRepoGrant(s string) (RepoGrant, error) {
+	scope, repo, ok := strings.Cut(s, ":")
+	if !ok || repo == "" {
+		return RepoGrant{}, fmt.Errorf("invalid repository scope '%s': expected read:<repo> or write:<repo>", s)
+	}
+	if _, err := path.Match(repo, ""); err != nil {
+		return RepoGrant{}, fmt.Errorf("invalid repository scope '%s': %w", s, err)
+	}
+	switch scope {
+	case "read":
+		return RepoGrant{Repo: repo, Scope: ReadScope}, nil
+	case "write":
+		return RepoGrant{Repo: repo, Scope: WriteScope}, nil
+	default:
+		return RepoGrant{}, fmt.Errorf("invalid repository scope '%s': unknown access level '%s'", s, scope)
+	}
+}
+
+// ParseRepoGrants parses a whitespace separated list of scope strings, as found in the "scope" claim of a JWT.
+func ParseRepoGrants(s string) ([]RepoGrant, error) {
+	var grants []RepoGrant
+	for _, f := range strings.Fields(s) {
+		g, err := ParseRepoGrant(f)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, g)
+	}
+	return grants, nil
+}
+
+func (g RepoGrant) allows(repoPath string, superUserReq bool) bool {
+	if superUserReq && g.Scope != WriteScope {
+		return false
+	}
+	matched, _ := path.Match(g.Repo, strings.Trim(repoPath, "/"))
+	return matched
+}
+
+// ClientCertGrants maps the identity of a verified client certificate to the repositories it may access. The
+// identity of a certificate is its subject common name, or its first DNS SAN if the common name is empty.
+type ClientCertGrants map[string][]RepoGrant
+
+// ReadClientCertGrants reads ClientCertGrants from |r|. Each non-empty line which does not start with '#' is an
+// identity followed by one or more scope strings, for example:
+//
+//	ci-runner-1 write:prod/* read:staging/*
+func ReadClientCertGrants(r io.Reader) (ClientCertGrants, error) {
+	grants := make(ClientCertGrants)
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: expected an identity followed by at least one scope", lineNum)
+		}
+		g, err := ParseRepoGrants(strings.Join(fields[1:], " "))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		grants[fields[0]] = append(grants[fields[0]], g...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// certIdentity returns the identity used to look up ClientCertGrants for |cert|.
+func certIdentity(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	return ""
+}
+
+type scopedPrincipalKey struct{}
+
+// scopedPrincipal is the authenticated identity of a request and the grants it carries.
+type scopedPrincipal struct {
+	name   string
+	grants []RepoGrant
+}
+
+// ScopedAccessControl is a RepoAccessControl which authenticates requests with either a bearer JWT or a verified
+// TLS client certificate, and authorizes them against per-repository read and write scopes.
+//
+// A bearer token is validated with |Validator|. Its grants are taken from its space separated "scope" claim, for
+// example "read:* write:ci/nightly". A client certificate must have been verified by the TLS stack, so the server
+// must be configured with ClientCAs and a ClientAuth of at least tls.VerifyClientCertIfGiven. Its grants are taken
+// from |CertGrants|.
+//
+// If a request carries a bearer token, the token is used even if a client certificate was also presented.
+type ScopedAccessControl struct {
+	Validator  jwtauth.JWTValidator
+	CertGrants ClientCertGrants
+}
+
+var _ RepoAccessControl = ScopedAccessControl{}
+
+var ErrNoCredentials = errors.New("no bearer token or verified client certificate provided")
+
+func (ac ScopedAccessControl) ApiAuthenticate(ctx context.Context) (context.Context, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if auths := md.Get("authorization"); len(auths) > 0 {
+			p, err := ac.authenticateToken(auths)
+			if err != nil {
+				return nil, err
+			}
+			return ContextWithPrincipal(context.WithValue(ctx, scopedPrincipalKey{}, p), p.name), nil
+		}
+	}
+
+	if ac.CertGrants != nil {
+		if pr, ok := peer.FromContext(ctx); ok {
+			if tlsInfo, ok := pr.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 && len(tlsInfo.State.VerifiedChains[0]) > 0 {
+				identity := certIdentity(tlsInfo.State.VerifiedChains[0][0])
+				grants, ok := ac.CertGrants[identity]
+				if !ok {
+					return nil, fmt.Errorf("client certificate '%s' has not been granted access to any repository", identity)
+				}
+				ctx = context.WithValue(ctx, scopedPrincipalKey{}, scopedPrincipal{name: identity, grants: grants})
+				return ContextWithPrincipal(ctx, identity), nil
+			}
+		}
+	}
+
+	return nil, ErrNoCredentials
+}
+
+func (ac ScopedAccessControl) authenticateToken(auths []string) (scopedPrincipal, error) {
+	if ac.Validator == nil {
+		return scopedPrincipal{}, errors.New("bearer token authentication is not configured")
+	}
+	if len(auths) != 1 {
+		return scopedPrincipal{}, errors.New("bad request: expected exactly one authorization header")
+	}
+	if !strings.HasPrefix(auths[0], "Bearer ") {
+		return scopedPrincipal{}, errors.New("bad request: authorization header did not start with 'Bearer '")
+	}
+	claims, err := ac.Validator.ValidateJWT(strings.TrimPrefix(auths[0], "Bearer "), time.Now())
+	if err != nil {
+		return scopedPrincipal{}, fmt.Errorf("invalid bearer token: %w", err)
+	}
+	grants, err := ParseRepoGrants(claims.Scope)
+	if err != nil {
+		return scopedPrincipal{}, fmt.Errorf("invalid bearer token: %w", err)
+	}
+	return scopedPrincipal{name: claims.Subject, grants: grants}, nil
+}
+
+// ApiAuthorize is used for requests which do not name a repository. It requires a grant matching every repository.
+func (ac ScopedAccessControl) ApiAuthorize(ctx context.Context, superUserReq bool) (bool, error) {
+	return ac.ApiAuthorizeRepo(ctx, "*", superUserReq)
+}
+
+func (ac ScopedAccessControl) ApiAuthorizeRepo(ctx context.Context, repoPath string, superUserReq bool) (bool, error) {
+	p, ok := ctx.Value(scopedPrincipalKey{}).(scopedPrincipal)
+	if !ok {
+		return false, errors.New("Runtime error: could not get authenticated principal from context")
+	}
+	for _, g := range p.grants {
+		if g.allows(repoPath, superUserReq) {
+			return true, nil
+		}
+	}
+	scope := ReadScope
+	if superUserReq {
+		scope = WriteScope
+	}
+	return false, fmt.Errorf("API Authorization Failure: %s has not been granted %s access to %s", p.name, scope, repoPath)
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"gopkg.in/go-jose/go-jose.v2/jwt"
+
+	"github.com/dolthub/dolt/go/libraries/utils/jwtauth"
+)
+
+type staticValidator map[string]*jwtauth.Claims
+
+func (v staticValidator) ValidateJWT(unparsed string, _ time.Time) (*jwtauth.Claims, error) {
+	if c, ok := v[unparsed]; ok {
+		return c, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+func TestParseRepoGrants(t *testing.T) {
+	grants, err := ParseRepoGrants("read:*  write:ci/nightly")
+	require.NoError(t, err)
+	assert.Equal(t, []RepoGrant{{Repo: "*", Scope: ReadScope}, {Repo: "ci/nightly", Scope: WriteScope}}, grants)
+
+	for _, bad := range []string{"read", "admin:foo", "write:", "read:[", ":foo"} {
+		_, err := ParseRepoGrants(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestReadClientCertGrants(t *testing.T) {
+	grants, err := ReadClientCertGrants(strings.NewReader(`
+# ci runners
+ci-runner-1 write:prod/*	read:staging/*
+
+ci-runner-2 read:*
+ci-runner-1 read:other
+`))
+	require.NoError(t, err)
+	assert.Equal(t, ClientCertGrants{
+		"ci-runner-1": {{Repo: "prod/*", Scope: WriteScope}, {Repo: "staging/*", Scope: ReadScope}, {Repo: "other", Scope: ReadScope}},
+		"ci-runner-2": {{Repo: "*", Scope: ReadScope}},
+	}, grants)
+
+	_, err = ReadClientCertGrants(strings.NewReader("ci-runner-1\n"))
+	assert.Error(t, err)
+}
+
+func TestScopedAccessControlBearerToken(t *testing.T) {
+	ac := ScopedAccessControl{
+		Validator: staticValidator{
+			"reader": {Claims: jwt.Claims{Subject: "reader"}, Scope: "read:*"},
+			"writer": {Claims: jwt.Claims{Subject: "writer"}, Scope: "write:ci/nightly"},
+		},
+	}
+	withAuth := func(auth string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", auth))
+	}
+
+	_, err := ac.ApiAuthenticate(withAuth("Bearer unknown"))
+	assert.Error(t, err)
+	_, err = ac.ApiAuthenticate(withAuth("Basic cm9vdDo="))
+	assert.Error(t, err)
+	_, err = ac.ApiAuthenticate(context.Background())
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	ctx, err := ac.ApiAuthenticate(withAuth("Bearer reader"))
+	require.NoError(t, err)
+	ok, _ := ac.ApiAuthorizeRepo(ctx, "prod", false)
+	assert.True(t, ok)
+	ok, err = ac.ApiAuthorizeRepo(ctx, "prod", true)
+	assert.False(t, ok)
+	assert.Contains(t, err.Error(), "reader has not been granted write access to prod")
+	ok, _ = ac.ApiAuthorize(ctx, false)
+	assert.True(t, ok)
+
+	ctx, err = ac.ApiAuthenticate(withAuth("Bearer writer"))
+	require.NoError(t, err)
+	ok, _ = ac.ApiAuthorizeRepo(ctx, "ci/nightly", true)
+	assert.True(t, ok)
+	ok, _ = ac.ApiAuthorizeRepo(ctx, "ci/nightly", false)
+	assert.True(t, ok)
+	ok, _ = ac.ApiAuthorizeRepo(ctx, "ci/weekly", false)
+	assert.False(t, ok)
+	ok, _ = ac.ApiAuthorize(ctx, false)
+	assert.False(t, ok)
+}
+
+func TestScopedAccessControlClientCert(t *testing.T) {
+	ac := ScopedAccessControl{
+		CertGrants: ClientCertGrants{"ci-runner-1": {{Repo: "prod/*", Scope: WriteScope}}},
+	}
+	withCert := func(cn string) context.Context {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+		return peer.NewContext(context.Background(), &peer.Peer{
+			Addr:     &net.TCPAddr{},
+			AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+		})
+	}
+
+	_, err := ac.ApiAuthenticate(withCert("ci-runner-2"))
+	assert.Error(t, err)
+
+	ctx, err := ac.ApiAuthenticate(withCert("ci-runner-1"))
+	require.NoError(t, err)
+	ok, _ := ac.ApiAuthorizeRepo(ctx, "prod/sales", true)
+	assert.True(t, ok)
+	ok, _ = ac.ApiAuthorizeRepo(ctx, "staging/sales", false)
+	assert.False(t, ok)
+
+	// Bearer tokens are not accepted unless a validator is configured.
+	_, err = ac.ApiAuthenticate(metadata.NewIncomingContext(withCert("ci-runner-1"), metadata.Pairs("authorization", "Bearer token")))
+	assert.Error(t, err)
+}
@@ -27,6 +27,7 @@ import (
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
@@ -69,6 +70,14 @@ type ServerArgs struct {
 
 	HttpInterceptor func(http.Handler) http.Handler
 
+	// Rules restricting how pushes may update branches. Pushers are
+	// identified by the principal recorded by the AccessControl, if any.
+	BranchProtection BranchProtection
+
+	// Hooks which validate each push after BranchProtection and before
+	// the repository's root is updated.
+	PreReceiveHooks []PreReceiveHook
+
 	// If supplied, the listener(s) returned from Listeners() will be TLS
 	// listeners. The scheme used in the URLs returned from the gRPC server
 	// will be https.
@@ -104,8 +113,18 @@ func NewServer(args ServerArgs) (*Server, error) {
 
 	s.wg.Add(2)
 	s.grpcListenAddr = args.GrpcListenAddr
-	s.grpcSrv = grpc.NewServer(append([]grpc.ServerOption{grpc.MaxRecvMsgSize(128 * 1024 * 1024)}, args.Options...)...)
-	var chnkSt remotesapi.ChunkStoreServiceServer = NewHttpFSBackedChunkStore(args.Logger, args.HttpHost, args.DBCache, args.FS, scheme, args.ConcurrencyControl, sealer)
+	grpcOpts := []grpc.ServerOption{grpc.MaxRecvMsgSize(128 * 1024 * 1024)}
+	if args.TLSConfig != nil {
+		// When gRPC has its own listener, it performs the TLS handshake
+		// itself so that client certificates are visible to
+		// interceptors through the peer's credentials.TLSInfo.
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(args.TLSConfig)))
+	}
+	s.grpcSrv = grpc.NewServer(append(grpcOpts, args.Options...)...)
+	rcs := NewHttpFSBackedChunkStore(args.Logger, args.HttpHost, args.DBCache, args.FS, scheme, args.ConcurrencyControl, sealer)
+	rcs.BranchProtection = args.BranchProtection
+	rcs.PreReceiveHooks = args.PreReceiveHooks
+	var chnkSt remotesapi.ChunkStoreServiceServer = rcs
 
 	if args.ReadOnly {
 		chnkSt = ReadOnlyChunkStore{chnkSt}
@@ -181,11 +200,7 @@ func (s *Server) Listeners() (Listeners, error) {
 	if s.httpListenAddr == s.grpcListenAddr {
 		return Listeners{http: httpListener}, nil
 	}
-	if s.tlsConfig != nil {
-		grpcListener, err = tls.Listen("tcp", s.grpcListenAddr, s.tlsConfig)
-	} else {
-		grpcListener, err = net.Listen("tcp", s.grpcListenAddr)
-	}
+	grpcListener, err = net.Listen("tcp", s.grpcListenAddr)
 	if err != nil {
 		httpListener.Close()
 		return Listeners{}, err
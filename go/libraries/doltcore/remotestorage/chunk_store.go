@@ -36,6 +36,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage/internal/reliable"
@@ -846,6 +848,9 @@ func (dcs *DoltChunkStore) Commit(ctx context.Context, current, last hash.Hash)
 	}
 	resp, err := dcs.csClient.Commit(ctx, req)
 	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.FailedPrecondition {
+			return false, &PushRejectedError{Reason: st.Message()}
+		}
 		return false, NewRpcError(err, "Commit", dcs.host, req)
 	}
 	err = dcs.loadRoot(ctx)
@@ -108,3 +108,13 @@ func GetJsonEncodedRequest(err error) (string, error) {
 
 	return string(data), nil
 }
+
+// PushRejectedError is returned from Commit when the remote refuses to move its root because the update violates
+// one of its policies, such as a protected branch rule. Its message is the reason given by the remote.
+type PushRejectedError struct {
+	Reason string
+}
+
+func (e *PushRejectedError) Error() string {
+	return e.Reason
+}
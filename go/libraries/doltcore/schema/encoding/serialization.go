@@ -429,6 +429,7 @@ func serializeSecondaryIndexes(b *fb.Builder, sch schema.Schema, indexes []schem
 		if idx.IsFullText() {
 			serial.IndexAddFulltextInfo(b, ftInfo)
 		}
+		serial.IndexAddVectorKey(b, idx.IsVector())
 		offs[i] = serial.IndexEnd(b)
 	}
 
@@ -459,6 +460,7 @@ func deserializeSecondaryIndexes(sch schema.Schema, s *serial.TableSchema) error
 			IsUnique:           idx.UniqueKey(),
 			IsSpatial:          idx.SpatialKey(),
 			IsFullText:         idx.FulltextKey(),
+			IsVector:           idx.VectorKey(),
 			IsUserDefined:      !idx.SystemDefined(),
 			Comment:            string(idx.Comment()),
 			FullTextProperties: fti,
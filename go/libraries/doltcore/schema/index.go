@@ -47,6 +47,8 @@ type Index interface {
 	IsSpatial() bool
 	// IsFullText returns whether the given index has the FULLTEXT constraint.
 	IsFullText() bool
+	// IsVector returns whether the given index has the VECTOR constraint.
+	IsVector() bool
 	// IsUserDefined returns whether the given index was created by a user or automatically generated.
 	IsUserDefined() bool
 	// Name returns the name of the index.
@@ -74,6 +76,7 @@ type indexImpl struct {
 	isUnique      bool
 	isSpatial     bool
 	isFullText    bool
+	isVector      bool
 	isUserDefined bool
 	comment       string
 	prefixLengths []uint16
@@ -94,6 +97,7 @@ func NewIndex(name string, tags, allTags []uint64, indexColl IndexCollection, pr
 		isUnique:      props.IsUnique,
 		isSpatial:     props.IsSpatial,
 		isFullText:    props.IsFullText,
+		isVector:      props.IsVector,
 		isUserDefined: props.IsUserDefined,
 		comment:       props.Comment,
 		fullTextProps: props.FullTextProperties,
@@ -141,6 +145,7 @@ func (ix *indexImpl) Equals(other Index) bool {
 
 	return ix.IsUnique() == other.IsUnique() &&
 		ix.IsSpatial() == other.IsSpatial() &&
+		ix.IsVector() == other.IsVector() &&
 		compareUint16Slices(ix.PrefixLengths(), other.PrefixLengths()) &&
 		ix.Comment() == other.Comment() &&
 		ix.Name() == other.Name()
@@ -163,6 +168,7 @@ func (ix *indexImpl) DeepEquals(other Index) bool {
 
 	return ix.IsUnique() == other.IsUnique() &&
 		ix.IsSpatial() == other.IsSpatial() &&
+		ix.IsVector() == other.IsVector() &&
 		compareUint16Slices(ix.PrefixLengths(), other.PrefixLengths()) &&
 		ix.Comment() == other.Comment() &&
 		ix.Name() == other.Name()
@@ -209,6 +215,11 @@ func (ix *indexImpl) IsFullText() bool {
 	return ix.isFullText
 }
 
+// IsVector implements Index.
+func (ix *indexImpl) IsVector() bool {
+	return ix.isVector
+}
+
 // IsUserDefined implements Index.
 func (ix *indexImpl) IsUserDefined() bool {
 	return ix.isUserDefined
@@ -80,6 +80,7 @@ type IndexProperties struct {
 	IsUnique      bool
 	IsSpatial     bool
 	IsFullText    bool
+	IsVector      bool
 	IsUserDefined bool
 	Comment       string
 	FullTextProperties
@@ -217,6 +218,7 @@ func (ixc *indexCollectionImpl) AddIndexByColTags(indexName string, tags []uint6
 		isUnique:      props.IsUnique,
 		isSpatial:     props.IsSpatial,
 		isFullText:    props.IsFullText,
+		isVector:      props.IsVector,
 		isUserDefined: props.IsUserDefined,
 		comment:       props.Comment,
 		prefixLengths: prefixLengths,
@@ -243,6 +245,7 @@ func (ixc *indexCollectionImpl) UnsafeAddIndexByColTags(indexName string, tags [
 		isUnique:      props.IsUnique,
 		isSpatial:     props.IsSpatial,
 		isFullText:    props.IsFullText,
+		isVector:      props.IsVector,
 		isUserDefined: props.IsUserDefined,
 		comment:       props.Comment,
 		prefixLengths: prefixLengths,
@@ -410,6 +413,7 @@ func (ixc *indexCollectionImpl) Merge(indexes ...Index) {
 				isUnique:      index.IsUnique(),
 				isSpatial:     index.IsSpatial(),
 				isFullText:    index.IsFullText(),
+				isVector:      index.IsVector(),
 				isUserDefined: index.IsUserDefined(),
 				comment:       index.Comment(),
 				prefixLengths: index.PrefixLengths(),
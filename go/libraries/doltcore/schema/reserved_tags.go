@@ -235,3 +235,32 @@ const (
 	// WorkflowSavedQueryStepExpectedRowColumnResultsUpdatedAtTag is the name of the tag on the updated at column on the workflow saved query step expected row column results table
 	WorkflowSavedQueryStepExpectedRowColumnResultsUpdatedAtTag
 )
+
+// Tags for the dolt_materialized_views table
+const (
+	DoltMaterializedViewsNameTag = iota + SystemTableReservedMin + uint64(10000)
+	DoltMaterializedViewsDefinitionTag
+	DoltMaterializedViewsAutoRefreshTag
+	DoltMaterializedViewsSourceHashTag
+)
+
+// Tags for the dolt_policies table
+const (
+	DoltPoliciesNameTag = iota + SystemTableReservedMin + uint64(10100)
+	DoltPoliciesTableNameTag
+	DoltPoliciesCommandTag
+	DoltPoliciesUserTag
+	DoltPoliciesHostTag
+	DoltPoliciesBranchTag
+	DoltPoliciesUsingTag
+	DoltPoliciesCheckTag
+)
+
+// Tags for the dolt_column_masks table
+const (
+	DoltColumnMasksTableNameTag = iota + SystemTableReservedMin + uint64(10200)
+	DoltColumnMasksColumnNameTag
+	DoltColumnMasksTypeTag
+	DoltColumnMasksPrefixTag
+	DoltColumnMasksSuffixTag
+)
@@ -300,6 +300,55 @@ func ArePrimaryKeySetsDiffable(format *types.NomsBinFormat, fromSch, toSch Schem
 	return true
 }
 
+// RekeySchema returns a copy of |sch| whose primary key is redefined to match the primary key of |pkSch|. Columns are
+// matched by tag, and columns that join or leave the primary key take their key membership and constraints from
+// |pkSch|. The returned bool is false if either schema is keyless, or if a primary key column of |pkSch| does not
+// exist in |sch| with the same type, in which case the rows of |sch| cannot be keyed by the primary key of |pkSch|.
+func RekeySchema(sch, pkSch Schema) (Schema, bool, error) {
+	if IsKeyless(sch) || IsKeyless(pkSch) {
+		return nil, false, nil
+	}
+
+	pkCols := pkSch.GetPKCols()
+	inPk := make(map[uint64]struct{}, pkCols.Size())
+	for _, pkCol := range pkCols.GetColumns() {
+		col, ok := sch.GetAllCols().GetByTag(pkCol.Tag)
+		if !ok || !col.TypeInfo.ToSqlType().Equals(pkCol.TypeInfo.ToSqlType()) {
+			return nil, false, nil
+		}
+		inPk[pkCol.Tag] = struct{}{}
+	}
+
+	cols := make([]Column, 0, sch.GetAllCols().Size())
+	for _, col := range sch.GetAllCols().GetColumns() {
+		if _, ok := inPk[col.Tag]; ok != col.IsPartOfPK {
+			col.IsPartOfPK = ok
+			if pkCol, found := pkSch.GetAllCols().GetByTag(col.Tag); found {
+				col.Constraints = pkCol.Constraints
+			}
+		}
+		cols = append(cols, col)
+	}
+	allCols := NewColCollection(cols...)
+
+	pkOrdinals := make([]int, 0, pkCols.Size())
+	keyCols := make([]Column, 0, pkCols.Size())
+	for _, tag := range pkCols.Tags {
+		pkOrdinals = append(pkOrdinals, allCols.TagToIdx[tag])
+		keyCols = append(keyCols, allCols.TagToCol[tag])
+	}
+
+	indexes := NewIndexCollection(allCols, NewColCollection(keyCols...))
+	indexes.AddIndex(sch.Indexes().AllIndexes()...)
+
+	rekeyed, err := NewSchema(allCols, pkOrdinals, sch.GetCollation(), indexes, sch.Checks())
+	if err != nil {
+		return nil, false, err
+	}
+	rekeyed.SetComment(sch.GetComment())
+	return rekeyed, true, nil
+}
+
 // MapSchemaBasedOnTagAndName can be used to map column values from one schema
 // to another schema. A primary key column in |inSch| is mapped to |outSch| if
 // they share the same tag. A non-primary key column in |inSch| is mapped to
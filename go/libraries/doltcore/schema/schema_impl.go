@@ -512,7 +512,8 @@ func (si *schemaImpl) getKeyColumnsDescriptor(convertAddressColumns bool) val.Tu
 				Enc:      val.Encoding(EncodingFromQueryType(query.Type_VARCHAR)),
 				Nullable: columnMissingNotNullConstraint(col),
 			}
-		} else if convertAddressColumns && !contentHashedField && queryType == query.Type_GEOMETRY {
+		} else if convertAddressColumns && !contentHashedField && (queryType == query.Type_GEOMETRY || queryType == query.Type_JSON) {
+			// JSON columns can only be indexed by vector indexes, which key them by Cell like spatial indexes
 			t = val.Type{
 				Enc:      val.Encoding(serial.EncodingCell),
 				Nullable: columnMissingNotNullConstraint(col),
@@ -355,6 +355,35 @@ func TestArePrimaryKeySetsDiffableTypeChanges(t *testing.T) {
 	}
 }
 
+func TestRekeySchema(t *testing.T) {
+	sch := MustSchemaFromCols(NewColCollection(
+		NewColumn("pk", 0, types.IntKind, true, NotNullConstraint{}),
+		NewColumn("c1", 1, types.IntKind, false),
+		NewColumn("c2", 2, types.StringKind, false)))
+	pkSch := MustSchemaFromCols(NewColCollection(
+		NewColumn("pk", 0, types.IntKind, false, NotNullConstraint{}),
+		NewColumn("c1", 1, types.IntKind, true, NotNullConstraint{}),
+		NewColumn("c2", 2, types.StringKind, true, NotNullConstraint{})))
+	require.NoError(t, pkSch.SetPkOrdinals([]int{2, 1}))
+
+	rekeyed, ok, err := RekeySchema(sch, pkSch)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []uint64{2, 1}, rekeyed.GetPKCols().Tags)
+	assert.Equal(t, []uint64{0}, rekeyed.GetNonPKCols().Tags)
+	assert.True(t, ArePrimaryKeySetsDiffable(types.Format_DOLT, rekeyed, pkSch))
+	c1, _ := rekeyed.GetAllCols().GetByTag(1)
+	assert.False(t, c1.IsNullable())
+
+	// primary key columns missing from |sch| can't be re-keyed
+	pkSch = MustSchemaFromCols(NewColCollection(
+		NewColumn("pk", 0, types.IntKind, true, NotNullConstraint{}),
+		NewColumn("c3", 3, types.IntKind, true, NotNullConstraint{})))
+	_, ok, err = RekeySchema(sch, pkSch)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
 func testSchema(method string, sch Schema, t *testing.T) {
 	validateCols(t, allCols, sch.GetAllCols(), method+"GetAllCols")
 	validateCols(t, pkCols, sch.GetPKCols(), method+"GetPKCols")
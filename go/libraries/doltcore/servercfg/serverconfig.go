@@ -62,6 +62,22 @@ const (
 	DefaultMySQLUnixSocketFilePath = "/tmp/mysql.sock"
 	DefaultMaxLoggedQueryLen       = 0
 	DefaultEncodeLoggedQuery       = false
+	DefaultAuditLogMaxSizeMB       = 100
+	DefaultAuditLogMaxFiles        = 10
+	DefaultLongQueryTimeMillis     = 10000
+	DefaultMaxQueryDigests         = 1000
+)
+
+// Classes of statement that the audit log can be restricted to.
+const (
+	// AuditLogClassDDL is the class of statements that change the schema, such as CREATE, ALTER and DROP
+	AuditLogClassDDL = "ddl"
+	// AuditLogClassDML is the class of statements that read or write rows, such as SELECT, INSERT, UPDATE and DELETE
+	AuditLogClassDML = "dml"
+	// AuditLogClassDoltProcedure is the class of calls to Dolt stored procedures, such as dolt_commit and dolt_merge
+	AuditLogClassDoltProcedure = "dolt_procedure"
+	// AuditLogClassOther is the class of every other statement, such as SET, SHOW, USE, GRANT and transaction control
+	AuditLogClassOther = "other"
 )
 
 const (
@@ -201,8 +217,19 @@ type ServerConfig interface {
 	RemotesapiPort() *int
 	// RemotesapiReadOnly is true if the remotesapi interface should be read only.
 	RemotesapiReadOnly() *bool
+	// RemotesapiPreReceive configures the checks run against pushes to the remotesapi interface, or nil if there are none.
+	RemotesapiPreReceive() *PreReceiveYAMLConfig
 	// ClusterConfig is the configuration for clustering in this sql-server.
 	ClusterConfig() ClusterConfig
+	// AuditLog configures the audit log of the statements run by this sql-server, or nil if there is none.
+	AuditLog() *AuditLogYAMLConfig
+	// SlowQueryLog configures the log of the statements run by this sql-server that take too long, or nil if there is
+	// none.
+	SlowQueryLog() *SlowQueryLogYAMLConfig
+	// QueryDigests configures the statistics this sql-server keeps about the statements it runs, or nil if it keeps none.
+	QueryDigests() *QueryDigestsYAMLConfig
+	// UserResourceLimits limits the resources that the queries of users and roles may use.
+	UserResourceLimits() []ResourceLimitsYAMLConfig
 	// EventSchedulerStatus is the configuration for enabling or disabling the event scheduler in this server.
 	EventSchedulerStatus() string
 	// ValueSet returns whether the value string provided was explicitly set in the config
@@ -274,9 +301,83 @@ func ValidateConfig(config ServerConfig) error {
 	if config.RequireSecureTransport() && config.TLSCert() == "" && config.TLSKey() == "" {
 		return fmt.Errorf("require_secure_transport can only be `true` when a tls_key and tls_cert are provided.")
 	}
+	if err := ValidateAuditLogConfig(config.AuditLog()); err != nil {
+		return err
+	}
+	if slow := config.SlowQueryLog(); slow != nil && slow.LongQueryTimeMillis() < 0 {
+		return fmt.Errorf("slow_query_log: long_query_time_millis: is %d but must be >= 0", slow.LongQueryTimeMillis())
+	}
+	if digests := config.QueryDigests(); digests != nil && digests.MaxDigests() < 1 {
+		return fmt.Errorf("query_digests: max_digests: is %d but must be >= 1", digests.MaxDigests())
+	}
+	if err := ValidateResourceLimitsConfig(config.UserResourceLimits()); err != nil {
+		return err
+	}
 	return ValidateClusterConfig(config.ClusterConfig())
 }
 
+// ValidateResourceLimitsConfig returns an error if the resource limits of users and roles are not valid.
+func ValidateResourceLimitsConfig(configs []ResourceLimitsYAMLConfig) error {
+	users := make(map[string]bool)
+	roles := make(map[string]bool)
+	for i, cfg := range configs {
+		if (cfg.User() == "") == (cfg.Role() == "") {
+			return fmt.Errorf("user_resource_limits[%d]: exactly one of user and role must be supplied", i)
+		}
+		if cfg.User() != "" {
+			if users[cfg.User()] {
+				return fmt.Errorf("user_resource_limits[%d]: user: limits for \"%s\" are already configured", i, cfg.User())
+			}
+			users[cfg.User()] = true
+		} else {
+			if roles[cfg.Role()] {
+				return fmt.Errorf("user_resource_limits[%d]: role: limits for \"%s\" are already configured", i, cfg.Role())
+			}
+			roles[cfg.Role()] = true
+		}
+		limits := []struct {
+			name  string
+			value int
+		}{
+			{"max_execution_time_millis", cfg.MaxExecutionTimeMillis()},
+			{"max_concurrent_queries", cfg.MaxConcurrentQueries()},
+			{"max_rows_returned", cfg.MaxRowsReturned()},
+			{"max_memory_mb", cfg.MaxMemoryMB()},
+		}
+		for _, limit := range limits {
+			if limit.value < 0 {
+				return fmt.Errorf("user_resource_limits[%d]: %s: is %d but must be >= 0", i, limit.name, limit.value)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateAuditLogConfig returns an error if the audit log configuration is not valid.
+func ValidateAuditLogConfig(config *AuditLogYAMLConfig) error {
+	if config == nil {
+		return nil
+	}
+	if config.Path() == "" {
+		return errors.New("audit_log: path: must supply the path of the audit log file")
+	}
+	if config.MaxSizeMB() < 0 {
+		return fmt.Errorf("audit_log: max_size_mb: is %d but must be >= 0", config.MaxSizeMB())
+	}
+	if config.MaxFiles() < 0 {
+		return fmt.Errorf("audit_log: max_files: is %d but must be >= 0", config.MaxFiles())
+	}
+	for i, class := range config.StatementClasses() {
+		switch strings.ToLower(class) {
+		case AuditLogClassDDL, AuditLogClassDML, AuditLogClassDoltProcedure, AuditLogClassOther:
+		default:
+			return fmt.Errorf("audit_log: statement_classes[%d]: is \"%s\" but must be one of %s, %s, %s or %s", i, class,
+				AuditLogClassDDL, AuditLogClassDML, AuditLogClassDoltProcedure, AuditLogClassOther)
+		}
+	}
+	return nil
+}
+
 const (
 	MaxConnectionsKey = "max_connections"
 	ReadTimeoutKey    = "net_read_timeout"
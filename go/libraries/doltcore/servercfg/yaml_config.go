@@ -33,6 +33,13 @@ func nillableStrPtr(s string) *string {
 	return &s
 }
 
+func strOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 func nillableBoolPtr(b bool) *bool {
 	if b == false {
 		return nil
@@ -104,8 +111,9 @@ type MetricsYAMLConfig struct {
 }
 
 type RemotesapiYAMLConfig struct {
-	Port_     *int  `yaml:"port,omitempty"`
-	ReadOnly_ *bool `yaml:"read_only,omitempty" minver:"1.30.5"`
+	Port_       *int                  `yaml:"port,omitempty"`
+	ReadOnly_   *bool                 `yaml:"read_only,omitempty" minver:"1.30.5"`
+	PreReceive_ *PreReceiveYAMLConfig `yaml:"pre_receive,omitempty" minver:"TBD"`
 }
 
 func (r RemotesapiYAMLConfig) Port() int {
@@ -116,6 +124,216 @@ func (r RemotesapiYAMLConfig) ReadOnly() bool {
 	return *r.ReadOnly_
 }
 
+// PreReceiveYAMLConfig configures the checks run against pushes to the remotesapi before they are accepted.
+type PreReceiveYAMLConfig struct {
+	// Command_ is an executable which receives each push as JSON on stdin and rejects it by exiting non-zero.
+	Command_ *string `yaml:"command,omitempty" minver:"TBD"`
+	// SQLAssertions_ are queries run against the pushed branch heads. A push is rejected if any of them return rows.
+	SQLAssertions_ []PreReceiveSQLAssertionYAMLConfig `yaml:"sql_assertions,omitempty" minver:"TBD"`
+}
+
+func (p *PreReceiveYAMLConfig) Command() string {
+	if p == nil || p.Command_ == nil {
+		return ""
+	}
+	return *p.Command_
+}
+
+func (p *PreReceiveYAMLConfig) SQLAssertions() []PreReceiveSQLAssertionYAMLConfig {
+	if p == nil {
+		return nil
+	}
+	return p.SQLAssertions_
+}
+
+type PreReceiveSQLAssertionYAMLConfig struct {
+	Name_    *string `yaml:"name,omitempty" minver:"TBD"`
+	Branch_  *string `yaml:"branch,omitempty" minver:"TBD"`
+	Query_   *string `yaml:"query,omitempty" minver:"TBD"`
+	Message_ *string `yaml:"message,omitempty" minver:"TBD"`
+}
+
+func (a PreReceiveSQLAssertionYAMLConfig) Name() string {
+	return strOrEmpty(a.Name_)
+}
+
+func (a PreReceiveSQLAssertionYAMLConfig) Branch() string {
+	return strOrEmpty(a.Branch_)
+}
+
+func (a PreReceiveSQLAssertionYAMLConfig) Query() string {
+	return strOrEmpty(a.Query_)
+}
+
+func (a PreReceiveSQLAssertionYAMLConfig) Message() string {
+	return strOrEmpty(a.Message_)
+}
+
+// AuditLogYAMLConfig configures the audit log, which records every statement run by the server as a line of JSON.
+type AuditLogYAMLConfig struct {
+	// Path_ is the path of the audit log file. Rotated files have a numeric suffix, the most recent being .1
+	Path_ *string `yaml:"path,omitempty" minver:"TBD"`
+	// MaxSizeMB_ is the size in megabytes at which the audit log file is rotated. Zero disables rotation.
+	MaxSizeMB_ *int `yaml:"max_size_mb,omitempty" minver:"TBD"`
+	// MaxFiles_ is the number of rotated audit log files kept. Zero keeps them all.
+	MaxFiles_ *int `yaml:"max_files,omitempty" minver:"TBD"`
+	// NormalizeStatements_ replaces the literals in logged statements with placeholders.
+	NormalizeStatements_ *bool `yaml:"normalize_statements,omitempty" minver:"TBD"`
+	// Users_ restricts the audit log to the statements of these users. Statements of every user are logged if empty.
+	Users_ []string `yaml:"users,omitempty" minver:"TBD"`
+	// StatementClasses_ restricts the audit log to these classes of statement, one of ddl, dml, dolt_procedure or
+	// other. Statements of every class are logged if empty.
+	StatementClasses_ []string `yaml:"statement_classes,omitempty" minver:"TBD"`
+}
+
+func (a *AuditLogYAMLConfig) Path() string {
+	if a == nil {
+		return ""
+	}
+	return strOrEmpty(a.Path_)
+}
+
+func (a *AuditLogYAMLConfig) MaxSizeMB() int {
+	if a == nil || a.MaxSizeMB_ == nil {
+		return DefaultAuditLogMaxSizeMB
+	}
+	return *a.MaxSizeMB_
+}
+
+func (a *AuditLogYAMLConfig) MaxFiles() int {
+	if a == nil || a.MaxFiles_ == nil {
+		return DefaultAuditLogMaxFiles
+	}
+	return *a.MaxFiles_
+}
+
+func (a *AuditLogYAMLConfig) NormalizeStatements() bool {
+	if a == nil || a.NormalizeStatements_ == nil {
+		return false
+	}
+	return *a.NormalizeStatements_
+}
+
+func (a *AuditLogYAMLConfig) Users() []string {
+	if a == nil {
+		return nil
+	}
+	return a.Users_
+}
+
+func (a *AuditLogYAMLConfig) StatementClasses() []string {
+	if a == nil {
+		return nil
+	}
+	return a.StatementClasses_
+}
+
+// SlowQueryLogYAMLConfig configures the slow query log, which records the statements that take longer than a threshold
+// to run, in the format of MySQL's slow query log.
+type SlowQueryLogYAMLConfig struct {
+	// Path_ is the path of the slow query log file. Slow statements are written to the server log if it's not set.
+	Path_ *string `yaml:"path,omitempty" minver:"TBD"`
+	// LongQueryTimeMillis_ is the time in milliseconds a statement must take to be logged.
+	LongQueryTimeMillis_ *int `yaml:"long_query_time_millis,omitempty" minver:"TBD"`
+	// IncludePlan_ logs the plan of each slow statement along with it.
+	IncludePlan_ *bool `yaml:"include_plan,omitempty" minver:"TBD"`
+}
+
+func (s *SlowQueryLogYAMLConfig) Path() string {
+	if s == nil {
+		return ""
+	}
+	return strOrEmpty(s.Path_)
+}
+
+func (s *SlowQueryLogYAMLConfig) LongQueryTimeMillis() int {
+	if s == nil || s.LongQueryTimeMillis_ == nil {
+		return DefaultLongQueryTimeMillis
+	}
+	return *s.LongQueryTimeMillis_
+}
+
+func (s *SlowQueryLogYAMLConfig) IncludePlan() bool {
+	if s == nil || s.IncludePlan_ == nil {
+		return false
+	}
+	return *s.IncludePlan_
+}
+
+// QueryDigestsYAMLConfig configures the statistics kept about the statements run by the server, by digest, which the
+// dolt_query_digests system table shows.
+type QueryDigestsYAMLConfig struct {
+	// Enabled_ turns on the statistics. Statements must be parsed again to compute their digest, so they're off by
+	// default.
+	Enabled_ *bool `yaml:"enabled,omitempty" minver:"TBD"`
+	// MaxDigests_ is the number of digests statistics are kept for. The statements of other digests are counted
+	// together.
+	MaxDigests_ *int `yaml:"max_digests,omitempty" minver:"TBD"`
+}
+
+func (q *QueryDigestsYAMLConfig) Enabled() bool {
+	if q == nil || q.Enabled_ == nil {
+		return false
+	}
+	return *q.Enabled_
+}
+
+func (q *QueryDigestsYAMLConfig) MaxDigests() int {
+	if q == nil || q.MaxDigests_ == nil {
+		return DefaultMaxQueryDigests
+	}
+	return *q.MaxDigests_
+}
+
+// ResourceLimitsYAMLConfig limits the resources that the queries of a user, or of the users granted a role, may use.
+// Zero values are unlimited.
+type ResourceLimitsYAMLConfig struct {
+	// User_ is the user the limits apply to. Exactly one of User_ and Role_ must be set.
+	User_ *string `yaml:"user,omitempty" minver:"TBD"`
+	// Role_ is the role whose users the limits apply to, unless limits are configured for the user. Users granted
+	// more than one role with limits get the most restrictive of them.
+	Role_ *string `yaml:"role,omitempty" minver:"TBD"`
+	// MaxExecutionTimeMillis_ is the time in milliseconds a query may run for.
+	MaxExecutionTimeMillis_ *int `yaml:"max_execution_time_millis,omitempty" minver:"TBD"`
+	// MaxConcurrentQueries_ is the number of queries the sessions of a user may run at the same time.
+	MaxConcurrentQueries_ *int `yaml:"max_concurrent_queries,omitempty" minver:"TBD"`
+	// MaxRowsReturned_ is the number of rows a query may return.
+	MaxRowsReturned_ *int `yaml:"max_rows_returned,omitempty" minver:"TBD"`
+	// MaxMemoryMB_ is the approximate memory in megabytes a query may use.
+	MaxMemoryMB_ *int `yaml:"max_memory_mb,omitempty" minver:"TBD"`
+}
+
+func (r ResourceLimitsYAMLConfig) User() string {
+	return strOrEmpty(r.User_)
+}
+
+func (r ResourceLimitsYAMLConfig) Role() string {
+	return strOrEmpty(r.Role_)
+}
+
+func (r ResourceLimitsYAMLConfig) MaxExecutionTimeMillis() int {
+	return intOrZero(r.MaxExecutionTimeMillis_)
+}
+
+func (r ResourceLimitsYAMLConfig) MaxConcurrentQueries() int {
+	return intOrZero(r.MaxConcurrentQueries_)
+}
+
+func (r ResourceLimitsYAMLConfig) MaxRowsReturned() int {
+	return intOrZero(r.MaxRowsReturned_)
+}
+
+func (r ResourceLimitsYAMLConfig) MaxMemoryMB() int {
+	return intOrZero(r.MaxMemoryMB_)
+}
+
+func intOrZero(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
 type UserSessionVars struct {
 	Name string            `yaml:"name"`
 	Vars map[string]string `yaml:"vars"`
@@ -138,10 +356,14 @@ type YAMLConfig struct {
 	PrivilegeFile     *string               `yaml:"privilege_file,omitempty"`
 	BranchControlFile *string               `yaml:"branch_control_file,omitempty"`
 	// TODO: Rename to UserVars_
-	Vars            []UserSessionVars      `yaml:"user_session_vars"`
-	SystemVars_     map[string]interface{} `yaml:"system_variables,omitempty" minver:"1.11.1"`
-	Jwks            []JwksConfig           `yaml:"jwks"`
-	GoldenMysqlConn *string                `yaml:"golden_mysql_conn,omitempty"`
+	Vars            []UserSessionVars          `yaml:"user_session_vars"`
+	SystemVars_     map[string]interface{}     `yaml:"system_variables,omitempty" minver:"1.11.1"`
+	Jwks            []JwksConfig               `yaml:"jwks"`
+	GoldenMysqlConn *string                    `yaml:"golden_mysql_conn,omitempty"`
+	AuditLogConfig  *AuditLogYAMLConfig        `yaml:"audit_log,omitempty" minver:"TBD"`
+	SlowQueryLogCfg *SlowQueryLogYAMLConfig    `yaml:"slow_query_log,omitempty" minver:"TBD"`
+	QueryDigestsCfg *QueryDigestsYAMLConfig    `yaml:"query_digests,omitempty" minver:"TBD"`
+	ResourceLimits_ []ResourceLimitsYAMLConfig `yaml:"user_resource_limits,omitempty" minver:"TBD"`
 }
 
 var _ ServerConfig = YAMLConfig{}
@@ -214,8 +436,9 @@ func ServerConfigAsYAMLConfig(cfg ServerConfig) *YAMLConfig {
 			Port:   ptr(cfg.MetricsPort()),
 		},
 		RemotesapiConfig: RemotesapiYAMLConfig{
-			Port_:     cfg.RemotesapiPort(),
-			ReadOnly_: cfg.RemotesapiReadOnly(),
+			Port_:       cfg.RemotesapiPort(),
+			ReadOnly_:   cfg.RemotesapiReadOnly(),
+			PreReceive_: cfg.RemotesapiPreReceive(),
 		},
 		ClusterCfg:        clusterConfigAsYAMLConfig(cfg.ClusterConfig()),
 		PrivilegeFile:     ptr(cfg.PrivilegeFilePath()),
@@ -223,6 +446,10 @@ func ServerConfigAsYAMLConfig(cfg ServerConfig) *YAMLConfig {
 		SystemVars_:       systemVars,
 		Vars:              cfg.UserVars(),
 		Jwks:              cfg.JwksConfig(),
+		AuditLogConfig:    cfg.AuditLog(),
+		SlowQueryLogCfg:   cfg.SlowQueryLog(),
+		QueryDigestsCfg:   cfg.QueryDigests(),
+		ResourceLimits_:   cfg.UserResourceLimits(),
 	}
 }
 
@@ -427,6 +654,10 @@ func (cfg YAMLConfig) RemotesapiReadOnly() *bool {
 	return cfg.RemotesapiConfig.ReadOnly_
 }
 
+func (cfg YAMLConfig) RemotesapiPreReceive() *PreReceiveYAMLConfig {
+	return cfg.RemotesapiConfig.PreReceive_
+}
+
 // PrivilegeFilePath returns the path to the file which contains all needed privilege information in the form of a
 // JSON string.
 func (cfg YAMLConfig) PrivilegeFilePath() string {
@@ -578,6 +809,22 @@ func (cfg YAMLConfig) ClusterConfig() ClusterConfig {
 	return cfg.ClusterCfg
 }
 
+func (cfg YAMLConfig) AuditLog() *AuditLogYAMLConfig {
+	return cfg.AuditLogConfig
+}
+
+func (cfg YAMLConfig) SlowQueryLog() *SlowQueryLogYAMLConfig {
+	return cfg.SlowQueryLogCfg
+}
+
+func (cfg YAMLConfig) QueryDigests() *QueryDigestsYAMLConfig {
+	return cfg.QueryDigestsCfg
+}
+
+func (cfg YAMLConfig) UserResourceLimits() []ResourceLimitsYAMLConfig {
+	return cfg.ResourceLimits_
+}
+
 func (cfg YAMLConfig) EventSchedulerStatus() string {
 	if cfg.BehaviorConfig.EventSchedulerStatus == nil {
 		return "ON"
@@ -158,6 +158,137 @@ remotesapi:
 	require.Equal(t, 8000, *config.RemotesapiPort())
 }
 
+func TestUnmarshallRemotesapiPreReceive(t *testing.T) {
+	testStr := `
+remotesapi:
+  port: 8000
+  pre_receive:
+    command: /usr/local/bin/check-push
+    sql_assertions:
+    - name: no_negative_balances
+      branch: main
+      query: select id from accounts where balance < 0
+      message: balances must not be negative
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	preReceive := config.RemotesapiPreReceive()
+	require.NotNil(t, preReceive)
+	require.Equal(t, "/usr/local/bin/check-push", preReceive.Command())
+	require.Len(t, preReceive.SQLAssertions(), 1)
+	assertion := preReceive.SQLAssertions()[0]
+	require.Equal(t, "no_negative_balances", assertion.Name())
+	require.Equal(t, "main", assertion.Branch())
+	require.Equal(t, "select id from accounts where balance < 0", assertion.Query())
+	require.Equal(t, "balances must not be negative", assertion.Message())
+
+	config, err = NewYamlConfig([]byte("remotesapi:\n  port: 8000\n"))
+	require.NoError(t, err)
+	require.Nil(t, config.RemotesapiPreReceive())
+	require.Equal(t, "", config.RemotesapiPreReceive().Command())
+}
+
+func TestUnmarshallAuditLog(t *testing.T) {
+	testStr := `
+audit_log:
+  path: /var/log/dolt/audit.log
+  max_size_mb: 50
+  normalize_statements: true
+  users: [app, admin]
+  statement_classes: [ddl, dolt_procedure]
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	auditLog := config.AuditLog()
+	require.NotNil(t, auditLog)
+	require.Equal(t, "/var/log/dolt/audit.log", auditLog.Path())
+	require.Equal(t, 50, auditLog.MaxSizeMB())
+	require.Equal(t, DefaultAuditLogMaxFiles, auditLog.MaxFiles())
+	require.True(t, auditLog.NormalizeStatements())
+	require.Equal(t, []string{"app", "admin"}, auditLog.Users())
+	require.Equal(t, []string{AuditLogClassDDL, AuditLogClassDoltProcedure}, auditLog.StatementClasses())
+	require.NoError(t, ValidateAuditLogConfig(auditLog))
+
+	config, err = NewYamlConfig([]byte("audit_log:\n  path: audit.log\n  statement_classes: [dcl]\n"))
+	require.NoError(t, err)
+	require.Error(t, ValidateAuditLogConfig(config.AuditLog()))
+
+	config, err = NewYamlConfig([]byte("audit_log:\n  max_size_mb: 10\n"))
+	require.NoError(t, err)
+	require.Error(t, ValidateAuditLogConfig(config.AuditLog()))
+
+	config, err = NewYamlConfig([]byte("log_level: info\n"))
+	require.NoError(t, err)
+	require.Nil(t, config.AuditLog())
+	require.NoError(t, ValidateAuditLogConfig(config.AuditLog()))
+}
+
+func TestUnmarshallQueryStats(t *testing.T) {
+	testStr := `
+slow_query_log:
+  long_query_time_millis: 250
+  include_plan: true
+query_digests:
+  enabled: true
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	slowLog := config.SlowQueryLog()
+	require.NotNil(t, slowLog)
+	require.Empty(t, slowLog.Path())
+	require.Equal(t, 250, slowLog.LongQueryTimeMillis())
+	require.True(t, slowLog.IncludePlan())
+	require.True(t, config.QueryDigests().Enabled())
+	require.Equal(t, DefaultMaxQueryDigests, config.QueryDigests().MaxDigests())
+
+	config, err = NewYamlConfig([]byte("log_level: info\n"))
+	require.NoError(t, err)
+	require.Nil(t, config.SlowQueryLog())
+	require.Equal(t, DefaultLongQueryTimeMillis, config.SlowQueryLog().LongQueryTimeMillis())
+	require.False(t, config.QueryDigests().Enabled())
+	require.NoError(t, ValidateConfig(config))
+
+	config, err = NewYamlConfig([]byte("query_digests:\n  enabled: true\n  max_digests: 0\n"))
+	require.NoError(t, err)
+	require.Error(t, ValidateConfig(config))
+}
+
+func TestUnmarshallResourceLimits(t *testing.T) {
+	testStr := `
+user_resource_limits:
+  - role: analyst
+    max_execution_time_millis: 30000
+    max_concurrent_queries: 2
+  - user: etl
+    max_rows_returned: 1000000
+    max_memory_mb: 512
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateConfig(config))
+	limits := config.UserResourceLimits()
+	require.Len(t, limits, 2)
+	assert.Equal(t, "analyst", limits[0].Role())
+	assert.Empty(t, limits[0].User())
+	assert.Equal(t, 30000, limits[0].MaxExecutionTimeMillis())
+	assert.Equal(t, 2, limits[0].MaxConcurrentQueries())
+	assert.Equal(t, 0, limits[0].MaxRowsReturned())
+	assert.Equal(t, "etl", limits[1].User())
+	assert.Equal(t, 1000000, limits[1].MaxRowsReturned())
+	assert.Equal(t, 512, limits[1].MaxMemoryMB())
+
+	for _, invalid := range []string{
+		"user_resource_limits:\n  - max_rows_returned: 10\n",
+		"user_resource_limits:\n  - user: etl\n    role: analyst\n",
+		"user_resource_limits:\n  - user: etl\n    max_memory_mb: -1\n",
+		"user_resource_limits:\n  - role: analyst\n  - role: analyst\n",
+	} {
+		config, err = NewYamlConfig([]byte(invalid))
+		require.NoError(t, err)
+		assert.Error(t, ValidateConfig(config), invalid)
+	}
+}
+
 func TestUnmarshallCluster(t *testing.T) {
 	testStr := `
 cluster:
@@ -0,0 +1,258 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signing signs and verifies commits and tags with either OpenPGP keys, using gpg, or ssh keys, in process.
+package signing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/dolthub/dolt/go/libraries/utils/gpg"
+	"github.com/dolthub/dolt/go/libraries/utils/sshsig"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	FormatOpenPGP = "openpgp"
+	FormatSSH     = "ssh"
+
+	// SSHNamespace is the namespace of ssh signatures over commits and tags, which keeps them from being valid
+	// signatures of anything else.
+	SSHNamespace = "dolt"
+)
+
+var ErrNotSigned = errors.New("no signature found")
+var ErrBadSignature = errors.New("bad signature")
+var ErrUntrustedSigner = errors.New("signature is not from an allowed signer")
+
+// Config holds the settings used to sign and verify signatures.
+type Config struct {
+	// Format is the format of new signatures, either FormatOpenPGP or FormatSSH. Existing signatures are verified
+	// according to their own format.
+	Format string
+	// Key identifies the signing key. For OpenPGP it is a key id, and for ssh it is a key file or a "key::" literal.
+	Key string
+	// AllowedSignersFile is the path of the ssh allowed signers file which ssh signatures are verified against.
+	AllowedSignersFile string
+}
+
+// CommitPayload returns the data which is signed for a commit of the root value |rootHash| with parents |parents| and
+// metadata |meta|.
+func CommitPayload(rootHash hash.Hash, parents []hash.Hash, meta *datas.CommitMeta) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "root %s\n", rootHash.String())
+	for _, parent := range parents {
+		fmt.Fprintf(&sb, "parent %s\n", parent.String())
+	}
+	fmt.Fprintf(&sb, "author %s <%s> %d\n", meta.Name, meta.Email, meta.UserTimestamp)
+	sb.WriteString("\n")
+	sb.WriteString(meta.Description)
+	return []byte(sb.String())
+}
+
+// CommitSigner returns a function which signs commits according to |cfg|, for use as datas.CommitOptions.Sign. The
+// signature is only made once the commit is written, when its parents are known.
+func CommitSigner(cfg Config) func(ctx context.Context, rootHash hash.Hash, parents []hash.Hash, meta *datas.CommitMeta) (string, error) {
+	return func(ctx context.Context, rootHash hash.Hash, parents []hash.Hash, meta *datas.CommitMeta) (string, error) {
+		return Sign(ctx, cfg, CommitPayload(rootHash, parents, meta))
+	}
+}
+
+// TagPayload returns the data which is signed for the tag |tagName| of the commit |commitHash| with metadata |meta|.
+func TagPayload(tagName string, commitHash hash.Hash, meta *datas.TagMeta) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "object %s\n", commitHash.String())
+	fmt.Fprintf(&sb, "tag %s\n", tagName)
+	fmt.Fprintf(&sb, "tagger %s <%s> %d\n", meta.Name, meta.Email, meta.UserTimestamp)
+	sb.WriteString("\n")
+	sb.WriteString(meta.Description)
+	return []byte(sb.String())
+}
+
+// Sign signs |payload| according to |cfg| and returns the armored signature.
+//
+// OpenPGP signatures are clear-signed messages, which hold the payload as well as the signature. Earlier versions of
+// Dolt clear-signed a different message, and check signatures with gpg --verify alone, so they can still check these.
+func Sign(ctx context.Context, cfg Config, payload []byte) (string, error) {
+	switch strings.ToLower(cfg.Format) {
+	case "", FormatOpenPGP:
+		sig, err := gpg.Sign(ctx, cfg.Key, payload)
+		if err != nil {
+			return "", err
+		}
+		return string(sig), nil
+	case FormatSSH:
+		sig, err := sshsig.SignWithKey(cfg.Key, SSHNamespace, payload)
+		if err != nil {
+			return "", err
+		}
+		return string(sig), nil
+	default:
+		return "", fmt.Errorf("unknown signature format '%s', expected '%s' or '%s'", cfg.Format, FormatOpenPGP, FormatSSH)
+	}
+}
+
+// Verify verifies that |signature| is a good signature of |payload|, made at time |signedAt|, and returns a human
+// readable description of the signature. Errors wrap ErrBadSignature for signatures which are invalid or which sign
+// something other than |payload|, and ErrUntrustedSigner for valid ssh signatures by keys which are not in the allowed
+// signers file of |cfg|.
+func Verify(ctx context.Context, cfg Config, signature string, payload []byte, signedAt time.Time) (string, error) {
+	return verify(ctx, cfg, signature, payload, signedAt, nil)
+}
+
+// VerifyCommit verifies the signature of the commit |commit|, whose parents are |parents|, in the database |dbName|.
+//
+// Commits signed before signatures were made over CommitPayload hold a clear-signed message naming the database, the
+// root values of the first parent and of the commit, and the metadata of the commit. Those are checked against the
+// commit instead.
+func VerifyCommit(ctx context.Context, cfg Config, dbName string, commit types.Value, parents []*datas.Commit) (string, error) {
+	meta, err := datas.GetCommitMeta(ctx, commit)
+	if err != nil {
+		return "", err
+	}
+	rootHash, err := datas.GetCommitRootHash(commit)
+	if err != nil {
+		return "", err
+	}
+	parentHashes := make([]hash.Hash, len(parents))
+	for i, parent := range parents {
+		parentHashes[i] = parent.Addr()
+	}
+
+	legacy := func(message string) bool {
+		if len(parents) == 0 || parents[0].IsGhost() {
+			return false
+		}
+		headHash, err := datas.GetCommitRootHash(parents[0].NomsValue())
+		if err != nil {
+			return false
+		}
+		return legacyCommitPayloadMatches(message, dbName, headHash, rootHash, meta)
+	}
+
+	return verify(ctx, cfg, meta.Signature, CommitPayload(rootHash, parentHashes, meta), meta.Time(), legacy)
+}
+
+// VerifyTag verifies the signature of the tag |tagName| of the commit |commitHash| with metadata |meta|.
+func VerifyTag(ctx context.Context, cfg Config, tagName string, commitHash hash.Hash, meta *datas.TagMeta) (string, error) {
+	return Verify(ctx, cfg, meta.Signature, TagPayload(tagName, commitHash, meta), time.UnixMilli(meta.UserTimestamp))
+}
+
+// verify verifies that |signature| is a good signature of |payload|. If |legacy| is not nil, clear-signed messages which
+// don't match |payload| are also accepted if |legacy| returns true for them.
+func verify(ctx context.Context, cfg Config, signature string, payload []byte, signedAt time.Time, legacy func(message string) bool) (string, error) {
+	if signature == "" {
+		return "", ErrNotSigned
+	}
+
+	if sshsig.IsSignature([]byte(signature)) {
+		return verifySSH(cfg, signature, payload, signedAt)
+	}
+	if !gpg.IsClearSigned([]byte(signature)) {
+		return "", fmt.Errorf("%w: unrecognized signature format", ErrBadSignature)
+	}
+
+	message, out, err := gpg.VerifyClearSigned(ctx, []byte(signature))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+	signed := normalizeClearSigned(string(message))
+	if signed != normalizeClearSigned(string(payload)) && (legacy == nil || !legacy(signed)) {
+		return "", fmt.Errorf("%w: the signed message does not match", ErrBadSignature)
+	}
+	return string(out), nil
+}
+
+// normalizeClearSigned strips the trailing whitespace of each line of |message|, and its trailing newlines, which
+// OpenPGP clear-signed messages do not preserve.
+func normalizeClearSigned(message string) string {
+	lines := strings.Split(message, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t\r")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// legacyCommitDateLayout is the layout of the Date line of legacy commit signatures, which is that of time.Time.String
+// without the name of the time zone.
+const legacyCommitDateLayout = "2006-01-02 15:04:05.999999999 -0700"
+
+// legacyCommitPayloadMatches returns true if the normalized clear-signed |message| is the message earlier versions of
+// Dolt signed for a commit with metadata |meta| of the staged root value |stagedHash| onto the head root value
+// |headHash| in the database |dbName|. Those messages hold the author date as formatted by time.Time.String, which is
+// compared to the millisecond precision of the metadata.
+func legacyCommitPayloadMatches(message, dbName string, headHash, stagedHash hash.Hash, meta *datas.CommitMeta) bool {
+	prefix := normalizeClearSigned(fmt.Sprintf("db: %s\nMessage: %s\nName: %s\nEmail: %s\nDate:", dbName, meta.Description, meta.Name, meta.Email))
+	suffix := fmt.Sprintf("\nHead: %s\nStaged: %s", headHash.String(), stagedHash.String())
+	if len(message) < len(prefix)+len(suffix) || !strings.HasPrefix(message, prefix) || !strings.HasSuffix(message, suffix) {
+		return false
+	}
+
+	// the date is followed by the name of the time zone, and by the monotonic clock reading of dates read from the clock
+	dateLine := message[len(prefix) : len(message)-len(suffix)]
+	date := strings.Fields(dateLine)
+	if strings.Contains(dateLine, "\n") || len(date) < 4 || len(date) > 5 || (len(date) == 5 && !strings.HasPrefix(date[4], "m=")) {
+		return false
+	}
+	t, err := time.Parse(legacyCommitDateLayout, strings.Join(date[:3], " "))
+	return err == nil && t.UnixMilli() == meta.UserTimestamp
+}
+
+func verifySSH(cfg Config, signature string, payload []byte, signedAt time.Time) (string, error) {
+	pub, err := sshsig.Verify([]byte(signature), SSHNamespace, payload)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+	desc := fmt.Sprintf("%s key %s", sshKeyTypeName(pub), ssh.FingerprintSHA256(pub))
+
+	if cfg.AllowedSignersFile == "" {
+		return "", fmt.Errorf("%w: allowedsignersfile must be set to verify the ssh signature by %s", ErrUntrustedSigner, desc)
+	}
+	allowed, err := sshsig.LoadAllowedSigners(cfg.AllowedSignersFile)
+	if err != nil {
+		return "", err
+	}
+
+	principal, ok := allowed.FindPrincipal(pub, SSHNamespace, signedAt)
+	if !ok {
+		return "", fmt.Errorf("%w: no principal matched %s", ErrUntrustedSigner, desc)
+	}
+	return fmt.Sprintf("Good %q signature for %s with %s\n", SSHNamespace, principal, desc), nil
+}
+
+// sshKeyTypeName returns the name ssh-keygen uses for the type of |pub|.
+func sshKeyTypeName(pub ssh.PublicKey) string {
+	switch pub.Type() {
+	case ssh.KeyAlgoED25519:
+		return "ED25519"
+	case ssh.KeyAlgoSKED25519:
+		return "ED25519-SK"
+	case ssh.KeyAlgoRSA:
+		return "RSA"
+	case ssh.KeyAlgoECDSA256, ssh.KeyAlgoECDSA384, ssh.KeyAlgoECDSA521:
+		return "ECDSA"
+	case ssh.KeyAlgoSKECDSA256:
+		return "ECDSA-SK"
+	default:
+		return strings.ToUpper(pub.Type())
+	}
+}
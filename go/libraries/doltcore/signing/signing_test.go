@@ -0,0 +1,97 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestCommitSignaturesCoverParents(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "id_ed25519")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(block), 0600))
+	pub, err := ssh.NewPublicKey(priv.Public())
+	require.NoError(t, err)
+	allowedSigners := filepath.Join(dir, "allowed_signers")
+	require.NoError(t, os.WriteFile(allowedSigners, []byte("test@dolthub.com "+string(ssh.MarshalAuthorizedKey(pub))), 0600))
+
+	cfg := Config{Format: FormatSSH, Key: keyFile, AllowedSignersFile: allowedSigners}
+	meta, err := datas.NewCommitMetaWithUserTS("Test User", "test@dolthub.com", "a commit", time.UnixMilli(1700000000000))
+	require.NoError(t, err)
+	root := hash.Of([]byte("root"))
+	parents := []hash.Hash{hash.Of([]byte("parent")), hash.Of([]byte("merge parent"))}
+
+	signature, err := CommitSigner(cfg)(ctx, root, parents, meta)
+	require.NoError(t, err)
+
+	_, err = Verify(ctx, cfg, signature, CommitPayload(root, parents, meta), meta.Time())
+	assert.NoError(t, err)
+	_, err = Verify(ctx, cfg, signature, CommitPayload(root, parents[:1], meta), meta.Time())
+	assert.ErrorIs(t, err, ErrBadSignature)
+	_, err = Verify(ctx, cfg, signature, CommitPayload(root, []hash.Hash{parents[1], parents[0]}, meta), meta.Time())
+	assert.ErrorIs(t, err, ErrBadSignature)
+}
+
+func TestLegacyCommitPayloadMatches(t *testing.T) {
+	head, staged := hash.Of([]byte("head")), hash.Of([]byte("staged"))
+	date := time.Now().Add(-time.Hour)
+	meta, err := datas.NewCommitMetaWithUserTS("Test User", "test@dolthub.com", "a commit\n\n- with a list  ", date)
+	require.NoError(t, err)
+
+	// the message signed by earlier versions of Dolt, normalized as gpg returns it
+	legacy := func(dbName string, date fmt.Stringer, head, staged hash.Hash) string {
+		lines := []string{
+			"db: " + dbName,
+			"Message: " + meta.Description,
+			"Name: " + meta.Name,
+			"Email: " + meta.Email,
+			"Date: " + date.String(),
+			"Head: " + head.String(),
+			"Staged: " + staged.String(),
+		}
+		return normalizeClearSigned(strings.Join(lines, "\n") + "\n")
+	}
+
+	assert.True(t, legacyCommitPayloadMatches(legacy("mydb", date, head, staged), "mydb", head, staged, meta))
+	assert.True(t, legacyCommitPayloadMatches(legacy("mydb", date.UTC(), head, staged), "mydb", head, staged, meta))
+	assert.True(t, legacyCommitPayloadMatches(legacy("mydb", date.In(time.FixedZone("", -7*60*60)), head, staged), "mydb", head, staged, meta))
+
+	assert.False(t, legacyCommitPayloadMatches(legacy("otherdb", date, head, staged), "mydb", head, staged, meta))
+	assert.False(t, legacyCommitPayloadMatches(legacy("mydb", date, staged, head), "mydb", head, staged, meta))
+	assert.False(t, legacyCommitPayloadMatches(legacy("mydb", date, head, head), "mydb", head, staged, meta))
+	assert.False(t, legacyCommitPayloadMatches(legacy("mydb", date.Add(time.Second), head, staged), "mydb", head, staged, meta))
+	assert.False(t, legacyCommitPayloadMatches("a different message", "mydb", head, staged, meta))
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package colmask
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// AppliesTo returns whether masks apply to the current user of |db|. They apply to everyone but the admins of the
+// branch of |db| and the users with the database privileges to administer every branch, unless the session asked
+// for masked values with the dolt_mask_columns system variable. Revisions of |db| that aren't branches can only be
+// read unmasked with database privileges.
+func AppliesTo(ctx *sql.Context, db dsess.SqlDatabase) (bool, error) {
+	if force, err := dsess.GetBooleanSystemVar(ctx, dsess.MaskColumns); err != nil || force {
+		return force, err
+	}
+	bas := branch_control.GetBranchAwareSession(ctx)
+	if bas == nil {
+		return false, nil
+	}
+	dbName, _ := dsess.SplitRevisionDbName(db.RevisionQualifiedName())
+	if branch_control.HasDatabasePrivileges(bas, dbName) {
+		return false, nil
+	}
+	if db.RevisionType() != dsess.RevisionTypeBranch {
+		return true, nil
+	}
+	return dsess.CheckAdminAccessForDb(ctx, db) != nil, nil
+}
+
+// CheckSource returns an error if |source|, which exposes the unmasked values of the rows of |tableName| in |db|,
+// such as its history or diffs, may not be read by the current user because the masks of the table apply to them.
+// Masks of columns that no longer exist still count, since those columns can exist in the history of the table.
+func CheckSource(ctx *sql.Context, db dsess.SqlDatabase, tableName, source string) error {
+	if tableName == "" || doltdb.IsSystemTable(doltdb.TableName{Name: tableName}) {
+		return nil
+	}
+	if apply, err := AppliesTo(ctx, db); err != nil || !apply {
+		return err
+	}
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return err
+	}
+	masksTbl, ok, err := root.GetTable(ctx, doltdb.TableName{Name: doltdb.ColumnMasksTableName})
+	if err != nil || !ok {
+		return err
+	}
+	masks, err := cachedMasks(ctx, masksTbl)
+	if err != nil {
+		return err
+	}
+	for _, m := range masks {
+		if strings.EqualFold(m.Table, tableName) {
+			return ErrMaskedHistory.New(m.Table, source)
+		}
+	}
+	return nil
+}
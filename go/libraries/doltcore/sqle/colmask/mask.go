@@ -0,0 +1,287 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package colmask implements column masking rules. A mask hides the values of a column holding sensitive data from
+// users who don't administer the database, by redacting them, replacing them with a hash, or leaving only a few
+// leading and trailing characters visible. Masks are stored in the dolt_column_masks system table, so they are
+// versioned and merged along with the tables they protect, and they are also applied when data is exported with
+// dolt dump, dolt table export and dolt clone --masked.
+package colmask
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/vt/proto/query"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var ErrMaskExists = errors.NewKind("column %s.%s is already masked")
+var ErrMaskNotFound = errors.NewKind("column %s.%s is not masked")
+var ErrInvalidMaskType = errors.NewKind("invalid mask type '%s', expected one of redact, hash or partial")
+var ErrInvalidVisibleLength = errors.NewKind("visible prefix and suffix lengths must not be negative, and can only be given for partial masks")
+var ErrTableNotFound = errors.NewKind("table %s does not exist")
+var ErrColumnNotFound = errors.NewKind("column %s does not exist in table %s")
+var ErrSystemTable = errors.NewKind("columns of system table %s cannot be masked")
+var ErrStringColumnRequired = errors.NewKind("%s masks can only be applied to string columns, but %s.%s is %s")
+var ErrOldFormat = errors.NewKind("column masks are not supported in the old storage format")
+
+// ErrMaskedWrite is returned when a user that the masks of a table apply to updates or deletes its rows, which they
+// can only read masked.
+var ErrMaskedWrite = errors.NewKind("table %s has masked columns, so its rows cannot be updated or deleted by users they are masked for")
+
+// ErrMaskedHistory is returned when a user that the masks of a table apply to reads a system table that exposes the
+// unmasked values of its rows, such as its history or diffs.
+var ErrMaskedHistory = errors.NewKind("table %s has masked columns, so %s cannot be read by users they are masked for")
+
+// Types of mask
+const (
+	// TypeRedact replaces string values with asterisks and other values with the zero value of their type
+	TypeRedact = "redact"
+	// TypeHash replaces string values with the hex encoded SHA-256 hash of the value, which keeps equal values equal
+	TypeHash = "hash"
+	// TypePartial replaces every character of string values with an asterisk, apart from a number of leading and
+	// trailing characters
+	TypePartial = "partial"
+)
+
+// Mask is a row of the dolt_column_masks table.
+type Mask struct {
+	Table  string
+	Column string
+	// Type is how values are masked, one of the Type constants
+	Type string
+	// Prefix and Suffix are the number of leading and trailing characters a partial mask leaves visible
+	Prefix int
+	Suffix int
+}
+
+var masksSchema = mustSchema(
+	mustCol(doltdb.ColumnMasksTableNameCol, schema.DoltColumnMasksTableNameTag, varchar(64), true),
+	mustCol(doltdb.ColumnMasksColumnNameCol, schema.DoltColumnMasksColumnNameTag, varchar(64), true),
+	mustCol(doltdb.ColumnMasksTypeCol, schema.DoltColumnMasksTypeTag, varchar(16), false),
+	mustCol(doltdb.ColumnMasksPrefixCol, schema.DoltColumnMasksPrefixTag, typeinfo.Int32Type, false),
+	mustCol(doltdb.ColumnMasksSuffixCol, schema.DoltColumnMasksSuffixTag, typeinfo.Int32Type, false),
+)
+
+// MasksSchema returns the schema of the dolt_column_masks table.
+func MasksSchema() schema.Schema {
+	return masksSchema
+}
+
+func varchar(length int64) typeinfo.TypeInfo {
+	return typeinfo.CreateVarStringTypeFromSqlType(gmstypes.MustCreateString(query.Type_VARCHAR, length, sql.Collation_utf8mb4_0900_ai_ci))
+}
+
+func mustCol(name string, tag uint64, ti typeinfo.TypeInfo, partOfPK bool) schema.Column {
+	col, err := schema.NewColumnWithTypeInfo(name, tag, ti, partOfPK, "", false, "", schema.NotNullConstraint{})
+	if err != nil {
+		panic(err)
+	}
+	return col
+}
+
+func mustSchema(cols ...schema.Column) schema.Schema {
+	return schema.MustSchemaFromCols(schema.NewColCollection(cols...))
+}
+
+// ListMasks returns the masks defined in |root|.
+func ListMasks(ctx *sql.Context, root doltdb.RootValue) ([]Mask, error) {
+	tbl, ok, err := root.GetTable(ctx, doltdb.TableName{Name: doltdb.ColumnMasksTableName})
+	if err != nil || !ok {
+		return nil, err
+	}
+	return masksFromTable(ctx, tbl)
+}
+
+func masksFromTable(ctx *sql.Context, tbl *doltdb.Table) ([]Mask, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Format() != types.Format_DOLT {
+		return nil, ErrOldFormat.New()
+	}
+	m := durable.ProllyMapFromIndex(rows)
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var masks []Mask
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		row, err := index.BuildRow(ctx, k, v, sch, m.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+		masks = append(masks, Mask{
+			Table:  row[0].(string),
+			Column: row[1].(string),
+			Type:   strings.ToLower(row[2].(string)),
+			Prefix: int(row[3].(int32)),
+			Suffix: int(row[4].(int32)),
+		})
+	}
+	return masks, nil
+}
+
+func (m Mask) toRow() sql.Row {
+	return sql.Row{m.Table, m.Column, m.Type, int32(m.Prefix), int32(m.Suffix)}
+}
+
+// CreateMask adds |m| to the masks in the working set of |dbName|. An empty mask type redacts the column.
+func CreateMask(ctx *sql.Context, dbName string, m Mask) error {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	root := roots.Working
+	if !types.IsFormat_DOLT(root.VRW().Format()) {
+		return ErrOldFormat.New()
+	}
+
+	m.Type = strings.ToLower(m.Type)
+	switch m.Type {
+	case "":
+		m.Type = TypeRedact
+	case TypeRedact, TypeHash, TypePartial:
+	default:
+		return ErrInvalidMaskType.New(m.Type)
+	}
+	if m.Prefix < 0 || m.Suffix < 0 || (m.Type != TypePartial && (m.Prefix != 0 || m.Suffix != 0)) {
+		return ErrInvalidVisibleLength.New()
+	}
+
+	if doltdb.IsSystemTable(doltdb.TableName{Name: m.Table}) {
+		return ErrSystemTable.New(m.Table)
+	}
+	tbl, tName, ok, err := doltdb.GetTableInsensitive(ctx, root, doltdb.TableName{Name: m.Table})
+	if err != nil {
+		return err
+	} else if !ok {
+		return ErrTableNotFound.New(m.Table)
+	}
+	m.Table = tName
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	col, ok := sch.GetAllCols().GetByNameCaseInsensitive(m.Column)
+	if !ok {
+		return ErrColumnNotFound.New(m.Column, m.Table)
+	}
+	m.Column = col.Name
+	if m.Type != TypeRedact && !gmstypes.IsText(col.TypeInfo.ToSqlType()) {
+		return ErrStringColumnRequired.New(m.Type, m.Table, m.Column, col.TypeInfo.ToSqlType().String())
+	}
+
+	masks, err := ListMasks(ctx, root)
+	if err != nil {
+		return err
+	}
+	for _, existing := range masks {
+		if strings.EqualFold(existing.Table, m.Table) && strings.EqualFold(existing.Column, m.Column) {
+			return ErrMaskExists.New(m.Table, m.Column)
+		}
+	}
+	if len(masks) == 0 {
+		if _, ok, err := root.GetTable(ctx, doltdb.TableName{Name: doltdb.ColumnMasksTableName}); err != nil {
+			return err
+		} else if !ok {
+			root, err = doltdb.CreateEmptyTable(ctx, root, doltdb.TableName{Name: doltdb.ColumnMasksTableName}, masksSchema)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	ed, err := writer.NewRootEditor(ctx, dbName, root)
+	if err != nil {
+		return err
+	}
+	if err = ed.Insert(ctx, doltdb.ColumnMasksTableName, m.toRow()); err != nil {
+		return err
+	}
+	if root, err = ed.Finish(ctx); err != nil {
+		return err
+	}
+	return dSess.SetWorkingRoot(ctx, dbName, root)
+}
+
+// DropMask removes the mask of |table|.|column| from the working set of |dbName|.
+func DropMask(ctx *sql.Context, dbName, table, column string) error {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	root := roots.Working
+
+	masks, err := ListMasks(ctx, root)
+	if err != nil {
+		return err
+	}
+	var mask *Mask
+	for i := range masks {
+		if strings.EqualFold(masks[i].Table, table) && strings.EqualFold(masks[i].Column, column) {
+			mask = &masks[i]
+		}
+	}
+	if mask == nil {
+		return ErrMaskNotFound.New(table, column)
+	}
+
+	if len(masks) == 1 {
+		// this is the last mask, so remove the system table as well
+		root, err = root.RemoveTables(ctx, false, false, doltdb.TableName{Name: doltdb.ColumnMasksTableName})
+		if err != nil {
+			return err
+		}
+	} else {
+		ed, err := writer.NewRootEditor(ctx, dbName, root)
+		if err != nil {
+			return err
+		}
+		if err = ed.Delete(ctx, doltdb.ColumnMasksTableName, mask.toRow()); err != nil {
+			return err
+		}
+		if root, err = ed.Finish(ctx); err != nil {
+			return err
+		}
+	}
+	return dSess.SetWorkingRoot(ctx, dbName, root)
+}
@@ -0,0 +1,298 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package colmask
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// redacted is the value that redacted strings are replaced with
+const redacted = "****"
+
+// maxCachedMaskTables is the number of dolt_column_masks tables whose masks are kept in the cache
+const maxCachedMaskTables = 1024
+
+// cache holds the masks read from dolt_column_masks tables, keyed by the hash of the table, so that statements don't
+// need to read the table again.
+var cache = struct {
+	sync.Mutex
+	entries map[hash.Hash][]Mask
+}{entries: make(map[hash.Hash][]Mask)}
+
+// columnMask is a Mask resolved against the schema of its table.
+type columnMask struct {
+	Mask
+	idx int
+	typ sql.Type
+}
+
+// Masker masks the values of the masked columns of a table.
+type Masker struct {
+	table string
+	masks []columnMask
+}
+
+// ForTable returns the Masker for the table |tableName| with the schema |sch|, according to the masks defined in
+// |root|. Rows are expected to have every column of |sch|, in schema order. Returns nil if none of the columns of the
+// table are masked. Masks of columns that no longer exist are ignored.
+func ForTable(ctx *sql.Context, root doltdb.RootValue, tableName string, sch schema.Schema) (*Masker, error) {
+	masksTbl, ok, err := root.GetTable(ctx, doltdb.TableName{Name: doltdb.ColumnMasksTableName})
+	if err != nil || !ok {
+		return nil, err
+	}
+	masks, err := cachedMasks(ctx, masksTbl)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved []columnMask
+	allCols := sch.GetAllCols()
+	for _, m := range masks {
+		if !strings.EqualFold(m.Table, tableName) {
+			continue
+		}
+		col, ok := allCols.GetByNameCaseInsensitive(m.Column)
+		if !ok {
+			continue
+		}
+		resolved = append(resolved, columnMask{
+			Mask: m,
+			idx:  allCols.TagToIdx[col.Tag],
+			typ:  col.TypeInfo.ToSqlType(),
+		})
+	}
+	if len(resolved) == 0 {
+		return nil, nil
+	}
+	return &Masker{table: tableName, masks: resolved}, nil
+}
+
+func cachedMasks(ctx *sql.Context, masksTbl *doltdb.Table) ([]Mask, error) {
+	h, err := masksTbl.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Lock()
+	masks, ok := cache.entries[h]
+	cache.Unlock()
+	if ok {
+		return masks, nil
+	}
+
+	masks, err = masksFromTable(ctx, masksTbl)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Lock()
+	defer cache.Unlock()
+	if len(cache.entries) >= maxCachedMaskTables {
+		cache.entries = make(map[hash.Hash][]Mask)
+	}
+	cache.entries[h] = masks
+	return masks, nil
+}
+
+// MaskRow returns a copy of |row| with the values of the masked columns masked.
+func (m *Masker) MaskRow(row sql.Row) (sql.Row, error) {
+	masked := row.Copy()
+	for _, cm := range m.masks {
+		v, err := cm.apply(row[cm.idx])
+		if err != nil {
+			return nil, err
+		}
+		masked[cm.idx] = v
+	}
+	return masked, nil
+}
+
+// apply returns the masked |v|. NULL values are left as they are.
+func (cm columnMask) apply(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	st, ok := cm.typ.(sql.StringType)
+	if !ok {
+		// hash and partial masks can only be created for string columns, so anything else is redacted
+		return cm.typ.Zero(), nil
+	}
+
+	var s string
+	binary := false
+	switch v := v.(type) {
+	case string:
+		s = v
+	case []byte:
+		s, binary = string(v), true
+	default:
+		return nil, fmt.Errorf("unexpected type %T for masked column %s.%s", v, cm.Table, cm.Column)
+	}
+
+	var masked string
+	switch cm.Type {
+	case TypeHash:
+		sum := sha256.Sum256([]byte(s))
+		masked = hex.EncodeToString(sum[:])
+	case TypePartial:
+		masked = partial(s, cm.Prefix, cm.Suffix, binary)
+	default:
+		// masks with an unknown type written to the table directly are treated as redactions
+		masked = redacted
+	}
+
+	// masked values must still fit in the column, so that they can be exported and imported again
+	if binary {
+		if int64(len(masked)) > st.MaxByteLength() {
+			masked = masked[:st.MaxByteLength()]
+		}
+		return []byte(masked), nil
+	}
+	if max := st.MaxCharacterLength(); int64(utf8.RuneCountInString(masked)) > max {
+		masked = string([]rune(masked)[:max])
+	}
+	return masked, nil
+}
+
+// partial returns |s| with every character replaced with an asterisk, apart from the first |prefix| and the last
+// |suffix| ones. Values too short to hide anything are masked completely.
+func partial(s string, prefix, suffix int, binary bool) string {
+	if binary {
+		if prefix+suffix >= len(s) {
+			return strings.Repeat("*", len(s))
+		}
+		return s[:prefix] + strings.Repeat("*", len(s)-prefix-suffix) + s[len(s)-suffix:]
+	}
+	runes := []rune(s)
+	if prefix+suffix >= len(runes) {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:prefix]) + strings.Repeat("*", len(runes)-prefix-suffix) + string(runes[len(runes)-suffix:])
+}
+
+// RowIter returns an iterator over the rows of |iter| with the values of the masked columns masked. The rows of |iter|
+// must be complete.
+func (m *Masker) RowIter(iter sql.RowIter) sql.RowIter {
+	return &maskIter{masker: m, child: iter}
+}
+
+type maskIter struct {
+	masker *Masker
+	child  sql.RowIter
+}
+
+var _ sql.RowIter = (*maskIter)(nil)
+
+// Next implements sql.RowIter
+func (i *maskIter) Next(ctx *sql.Context) (sql.Row, error) {
+	row, err := i.child.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return i.masker.MaskRow(row)
+}
+
+// Close implements sql.RowIter
+func (i *maskIter) Close(ctx *sql.Context) error {
+	return i.child.Close(ctx)
+}
+
+// MaskRoot returns |root|, which belongs to the database |dbName|, with the values of every masked column replaced
+// by their masked values, so that the raw values are no longer stored in it.
+func MaskRoot(ctx *sql.Context, dbName string, root doltdb.RootValue) (doltdb.RootValue, error) {
+	masks, err := ListMasks(ctx, root)
+	if err != nil || len(masks) == 0 {
+		return root, err
+	}
+	tableNames, err := root.GetTableNames(ctx, doltdb.DefaultSchemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	ed, err := writer.NewRootEditor(ctx, dbName, root)
+	if err != nil {
+		return nil, err
+	}
+	for _, tableName := range tableNames {
+		if doltdb.IsSystemTable(doltdb.TableName{Name: tableName}) {
+			continue
+		}
+		tbl, _, err := root.GetTable(ctx, doltdb.TableName{Name: tableName})
+		if err != nil {
+			return nil, err
+		}
+		sch, err := tbl.GetSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		masker, err := ForTable(ctx, root, tableName, sch)
+		if err != nil {
+			return nil, err
+		} else if masker == nil {
+			continue
+		}
+		if err = maskTable(ctx, ed, tableName, tbl, sch, masker); err != nil {
+			return nil, err
+		}
+	}
+	return ed.Finish(ctx)
+}
+
+// maskTable replaces the rows of |tbl| with their masked rows, using |ed|.
+func maskTable(ctx *sql.Context, ed *writer.RootEditor, tableName string, tbl *doltdb.Table, sch schema.Schema, masker *Masker) error {
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	m := durable.ProllyMapFromIndex(rows)
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		row, err := index.BuildRow(ctx, k, v, sch, m.NodeStore())
+		if err != nil {
+			return err
+		}
+		masked, err := masker.MaskRow(row)
+		if err != nil {
+			return err
+		}
+		if err = ed.Update(ctx, tableName, row, masked); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/colmask"
+)
+
+// unfilteredSourcePrefixes are the prefixes of the system tables that expose the unmasked and unfiltered rows of the
+// table they're named after.
+var unfilteredSourcePrefixes = []string{
+	doltdb.DoltDiffTablePrefix,
+	doltdb.DoltCommitDiffTablePrefix,
+	doltdb.DoltHistoryTablePrefix,
+	doltdb.DoltConfTablePrefix,
+	doltdb.DoltConstViolTablePrefix,
+	doltdb.DoltWorkspaceTablePrefix,
+}
+
+// columnMasker returns the Masker for this table if it has masked columns and their masks apply to the current user,
+// or nil otherwise. Like row-level security policies, masks are read from the session's root for the table's branch.
+func (t *DoltTable) columnMasker(ctx *sql.Context) (*colmask.Masker, error) {
+	if doltdb.IsSystemTable(t.TableName()) {
+		return nil, nil
+	}
+	if apply, err := colmask.AppliesTo(ctx, t.db); err != nil || !apply {
+		return nil, err
+	}
+	root, err := t.db.GetRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return colmask.ForTable(ctx, root, t.tableName, t.sch)
+}
+
+// ReadsRestricted returns whether row-level security policies or column masks change the rows of this table that the
+// current user reads. Rows of such tables must be read through PartitionRows, which filters and masks them.
+func (t *DoltTable) ReadsRestricted(ctx *sql.Context) (bool, error) {
+	if restricted, err := t.RestrictedByRowPolicies(ctx); err != nil || restricted {
+		return restricted, err
+	}
+	masker, err := t.columnMasker(ctx)
+	return masker != nil, err
+}
+
+// checkMaskedWrite returns an error if the current user reads this table masked. Rows are updated and deleted as they
+// were read, so the masked values would be written back over the real ones.
+func (t *WritableDoltTable) checkMaskedWrite(ctx *sql.Context) error {
+	if masker, err := t.columnMasker(ctx); err != nil {
+		return err
+	} else if masker != nil {
+		return colmask.ErrMaskedWrite.New(t.tableName)
+	}
+	return nil
+}
+
+// checkMaskedSource returns an error if |tblName| is a system table exposing the unmasked rows of a table whose masks
+// apply to the current user.
+func (db Database) checkMaskedSource(ctx *sql.Context, tblName string) error {
+	lwrName := strings.ToLower(tblName)
+	for _, prefix := range unfilteredSourcePrefixes {
+		if strings.HasPrefix(lwrName, prefix) {
+			return colmask.CheckSource(ctx, db, tblName[len(prefix):], tblName)
+		}
+	}
+	return nil
+}
@@ -47,6 +47,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/globalstate"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/querydigest"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/resolve"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
@@ -301,6 +302,9 @@ func (db Database) GetTableInsensitiveAsOf(ctx *sql.Context, tableName string, a
 		if err != nil {
 			return nil, false, err
 		}
+		if dt, ok := versionedTable.(*DoltTable); ok {
+			dt.asOf = asOf
+		}
 		return versionedTable, true, nil
 
 	case *plan.EmptyTable:
@@ -317,6 +321,13 @@ func (db Database) GetTableInsensitiveAsOf(ctx *sql.Context, tableName string, a
 func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds *dsess.DoltSession, root doltdb.RootValue, tblName string, asOf interface{}) (sql.Table, bool, error) {
 	lwrName := strings.ToLower(tblName)
 
+	if err := db.checkMaskedSource(ctx, tblName); err != nil {
+		return nil, false, err
+	}
+	if err := db.checkRowPolicySource(ctx, tblName); err != nil {
+		return nil, false, err
+	}
+
 	// TODO: these tables that cache a root value at construction time should not, they need to get it from the session
 	//  at runtime
 	switch {
@@ -628,6 +639,8 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 				dt, found = dtables.NewBranchControlTable(controller.Access), true
 			}
 		}
+	case dtables.QueryDigestsTableName:
+		dt, found = dtables.NewQueryDigestsTable(querydigest.Default), true
 	case dtables.NamespaceTableName:
 		basCtx := branch_control.GetBranchAwareSession(ctx)
 		if basCtx != nil {
@@ -654,6 +667,25 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 			versionableTable := backingTable.(dtables.VersionableTable)
 			dt, found = dtables.NewIgnoreTable(ctx, versionableTable, db.schemaName), true
 		}
+	case doltdb.MergeConfigTableName:
+		if resolve.UseSearchPath && db.schemaName == "" {
+			schemaName, err := resolve.FirstExistingSchemaOnSearchPath(ctx, root)
+			if err != nil {
+				return nil, false, err
+			}
+			db.schemaName = schemaName
+		}
+
+		backingTable, _, err := db.getTable(ctx, root, doltdb.MergeConfigTableName)
+		if err != nil {
+			return nil, false, err
+		}
+		if backingTable == nil {
+			dt, found = dtables.NewEmptyMergeConfigTable(ctx, db.schemaName), true
+		} else {
+			versionableTable := backingTable.(dtables.VersionableTable)
+			dt, found = dtables.NewMergeConfigTable(ctx, versionableTable, db.schemaName), true
+		}
 	case doltdb.GetDocTableName(), doltdb.DocTableName:
 		isDoltgresSystemTable, err := resolve.IsDoltgresSystemTable(ctx, tname, root)
 		if err != nil {
@@ -64,8 +64,9 @@ type DoltDatabaseProvider struct {
 	fs            filesys.Filesys
 	remoteDialer  dbfactory.GRPCDialProvider // TODO: why isn't this a method defined on the remote object
 
-	dbFactoryUrl string
-	isStandby    *bool
+	dbFactoryUrl    string
+	isStandby       *bool
+	statementRunner *dsess.StatementRunner
 }
 
 var _ sql.DatabaseProvider = (*DoltDatabaseProvider)(nil)
@@ -156,6 +157,7 @@ func NewDoltDatabaseProviderWithDatabases(defaultBranch string, fs filesys.Files
 		dbFactoryUrl:           dbFactoryUrl,
 		InitDatabaseHooks:      []InitDatabaseHook{ConfigureReplicationDatabaseHook},
 		isStandby:              new(bool),
+		statementRunner:        new(dsess.StatementRunner),
 		droppedDatabaseManager: newDroppedDatabaseManager(fs),
 	}, nil
 }
@@ -211,6 +213,21 @@ func (p *DoltDatabaseProvider) SetIsStandby(standby bool) {
 	*p.isStandby = standby
 }
 
+// SetStatementRunner sets the runner of the engine that serves this provider's databases, which procedures use to
+// run the statements they're given.
+func (p *DoltDatabaseProvider) SetStatementRunner(runner dsess.StatementRunner) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	*p.statementRunner = runner
+}
+
+// StatementRunner implements dsess.DoltDatabaseProvider
+func (p *DoltDatabaseProvider) StatementRunner() dsess.StatementRunner {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return *p.statementRunner
+}
+
 // FileSystemForDatabase returns a filesystem, with the working directory set to the root directory
 // of the requested database. If the requested database isn't found, a database not found error
 // is returned.
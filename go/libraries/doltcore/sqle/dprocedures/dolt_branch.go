@@ -126,6 +126,14 @@ func renameBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseRe
 		return err
 	}
 	force := apr.Contains(cli.ForceFlag)
+	if err := checkDefaultBranchPolicies(ctx, dbData, dbName, oldBranchName, ""); err != nil {
+		return err
+	}
+	if force {
+		if err := checkDefaultBranchPolicies(ctx, dbData, dbName, newBranchName, oldBranchName); err != nil {
+			return err
+		}
+	}
 
 	if !force {
 		err := validateBranchNotActiveInAnySession(ctx, oldBranchName)
@@ -223,6 +231,11 @@ func deleteBranches(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParse
 		if err = branch_control.CanDeleteBranch(ctx, branchName); err != nil {
 			return err
 		}
+		if !apr.Contains(cli.RemoteParam) {
+			if err = checkDefaultBranchPolicies(ctx, dbData, dbName, branchName, ""); err != nil {
+				return err
+			}
+		}
 	}
 
 	dSess := dsess.DSessFromSess(ctx.Session)
@@ -405,6 +418,11 @@ func createNewBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgPars
 	if err != nil {
 		return err
 	}
+	if apr.Contains(cli.ForceFlag) {
+		if err = checkDefaultBranchPolicies(ctx, dbData, ctx.GetCurrentDatabase(), branchName, startPt); err != nil {
+			return err
+		}
+	}
 
 	err = actions.CreateBranchWithStartPt(ctx, dbData, branchName, startPt, apr.Contains(cli.ForceFlag), rsc)
 	if err != nil {
@@ -451,6 +469,9 @@ func copyABranch(ctx *sql.Context, dbData env.DbData, srcBr string, destBr strin
 		if err := branch_control.CanDeleteBranch(ctx, destBr); err != nil {
 			return err
 		}
+		if err := checkDefaultBranchPolicies(ctx, dbData, ctx.GetCurrentDatabase(), destBr, srcBr); err != nil {
+			return err
+		}
 	}
 	err := actions.CopyBranchOnDB(ctx, dbData.Ddb, srcBr, destBr, force, rsc)
 	if err != nil {
@@ -471,3 +492,35 @@ func copyABranch(ctx *sql.Context, dbData env.DbData, srcBr string, destBr strin
 
 	return nil
 }
+
+// checkDefaultBranchPolicies returns an error if |branch| is the default branch of |dbName|, and pointing it at the
+// commit |startPt|, or deleting it if |startPt| is empty, would change the row-level security policies stored in its
+// working set when the current user may not change them.
+func checkDefaultBranchPolicies(ctx *sql.Context, dbData env.DbData, dbName, branch, startPt string) error {
+	if isDefault, err := dsess.IsDefaultBranch(ctx, dbName, branch); err != nil || !isDefault {
+		return err
+	}
+	var root doltdb.RootValue
+	if startPt != "" {
+		cs, err := doltdb.NewCommitSpec(startPt)
+		if err != nil {
+			return err
+		}
+		headRef, err := dbData.Rsr.CWBHeadRef()
+		if err != nil {
+			return err
+		}
+		optCmt, err := dbData.Ddb.Resolve(ctx, cs, headRef)
+		if err != nil {
+			return err
+		}
+		cm, ok := optCmt.ToCommit()
+		if !ok {
+			return doltdb.ErrGhostCommitEncountered
+		}
+		if root, err = cm.GetRootValue(ctx); err != nil {
+			return err
+		}
+	}
+	return dsess.CheckReplacePolicies(ctx, dbName, dbData.Ddb, root)
+}
@@ -0,0 +1,198 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/colmask"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/rowpolicy"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var cdcSchema = stringSchema("commit_hash", "table_name", "op", "event")
+
+// doltCdc is the stored procedure version of the CLI command `dolt cdc`. It returns a row for every change event made
+// by the commits on a branch after the --since commit, holding the event in the same JSON format the command writes.
+// Consumers resume by passing the last commit hash they processed as --since.
+func doltCdc(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return nil, fmt.Errorf("empty database name")
+	}
+
+	apr, err := cli.CreateCdcArgParser(true).Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	dbData, ok := dsess.DSessFromSess(ctx.Session).GetDbData(ctx, dbName)
+	if !ok {
+		return nil, fmt.Errorf("could not load database %s", dbName)
+	}
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return nil, err
+	}
+	branch := headRef.GetPath()
+	if b, ok := apr.GetValue(cli.BranchParam); ok {
+		branch = b
+		headRef = ref.NewBranchRef(b)
+	}
+
+	var since hash.Hash
+	if s, ok := apr.GetValue(cli.SinceParam); ok {
+		cs, err := doltdb.NewCommitSpec(s)
+		if err != nil {
+			return nil, err
+		}
+		optCmt, err := dbData.Ddb.Resolve(ctx, cs, headRef)
+		if err != nil {
+			return nil, err
+		}
+		cm, ok := optCmt.ToCommit()
+		if !ok {
+			return nil, doltdb.ErrGhostCommitEncountered
+		}
+		if since, err = cm.HashOf(); err != nil {
+			return nil, err
+		}
+	}
+
+	baseName, _ := dsess.SplitRevisionDbName(dbName)
+	db, err := dsess.DSessFromSess(ctx.Session).Provider().Database(ctx, dsess.RevisionDbName(baseName, branch))
+	if err != nil {
+		return nil, err
+	}
+	sqlDb, ok := db.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", db)
+	}
+
+	child, cancel := context.WithCancel(ctx)
+	streamCtx := ctx.WithContext(child)
+	iter := cdcRowIter{
+		rows:    make(chan sql.Row, 64),
+		errChan: make(chan error),
+		cancel:  cancel,
+	}
+	sink := cdcRowSink{
+		rows: iter.rows,
+		// events hold the unmasked and unfiltered rows of their table in their before and after images, so tables
+		// with column masks or row-level security policies can't be streamed
+		readable: func(tableName string) error {
+			if err := colmask.CheckSource(streamCtx, sqlDb, tableName, "dolt_cdc"); err != nil {
+				return err
+			}
+			return rowpolicy.CheckReadable(streamCtx, sqlDb, tableName, "dolt_cdc")
+		},
+		checked: make(map[string]error),
+	}
+	streamer := cdc.NewStreamer(dbData.Ddb, baseName, branch, sink, cdc.NewMemoryCheckpoint(since))
+	go func() {
+		iter.queueRows(streamCtx, streamer)
+	}()
+	return iter, nil
+}
+
+// cdcRowIter returns the events of a cdc.Streamer as they are emitted, so that the events of a long history are never
+// all held in memory at once.
+type cdcRowIter struct {
+	rows    chan sql.Row
+	errChan chan error
+	cancel  context.CancelFunc
+}
+
+var _ sql.RowIter = cdcRowIter{}
+
+func (itr cdcRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-itr.errChan:
+		return nil, err
+	case row, ok := <-itr.rows:
+		if !ok {
+			return nil, io.EOF
+		}
+		return row, nil
+	}
+}
+
+func (itr cdcRowIter) Close(*sql.Context) error {
+	itr.cancel()
+	return nil
+}
+
+func (itr cdcRowIter) queueRows(ctx *sql.Context, streamer *cdc.Streamer) {
+	if _, err := streamer.Poll(ctx); err != nil {
+		select {
+		case <-ctx.Done():
+		case itr.errChan <- err:
+		}
+		return
+	}
+	close(itr.rows)
+}
+
+// cdcRowSink is the cdc.Sink of a cdcRowIter, which sends each event to the iterator as a row.
+type cdcRowSink struct {
+	rows chan sql.Row
+	// readable returns an error if the events of a table may not be read by the current user
+	readable func(tableName string) error
+	// checked holds the result of |readable| for each table that events were emitted for
+	checked map[string]error
+}
+
+var _ cdc.Sink = cdcRowSink{}
+
+func (s cdcRowSink) Emit(ctx context.Context, ev cdc.Event) error {
+	err, ok := s.checked[ev.Source.Table]
+	if !ok {
+		err = s.readable(ev.Source.Table)
+		s.checked[ev.Source.Table] = err
+	}
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case s.rows <- sql.Row{ev.Source.Commit, ev.Source.Table, string(ev.Op), string(data)}:
+		return nil
+	}
+}
+
+func (s cdcRowSink) Flush(context.Context) error {
+	return nil
+}
+
+func (s cdcRowSink) Close() error {
+	return nil
+}
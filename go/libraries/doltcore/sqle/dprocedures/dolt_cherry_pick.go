@@ -17,6 +17,7 @@ package dprocedures
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
@@ -25,10 +26,16 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/cherry_pick"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
 
 var ErrEmptyCherryPick = errors.New("cannot cherry-pick empty string")
 
+var ErrNoCherryPickInProgress = errors.New("error: There is no cherry-pick in progress")
+
+var ErrCherryPickUnresolvedConflicts = errors.New("error: cannot continue the cherry-pick until all conflicts and " +
+	"constraint violations are resolved")
+
 var cherryPickSchema = []*sql.Column{
 	{
 		Name:     "hash",
@@ -62,7 +69,7 @@ func doltCherryPick(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 }
 
 // doDoltCherryPick attempts to perform a cherry-pick merge based on the arguments specified in |args| and returns
-// the new, created commit hash (if it was successful created), a count of the number of tables with data conflicts,
+// the hash of the last commit created (if all commits were successfully applied), a count of the number of tables with data conflicts,
 // a count of the number of tables with schema conflicts, and a count of the number of tables with constraint violations.
 func doDoltCherryPick(ctx *sql.Context, args []string) (string, int, int, int, error) {
 	// Get the information for the sql context.
@@ -83,17 +90,17 @@ func doDoltCherryPick(ctx *sql.Context, args []string) (string, int, int, int, e
 	if apr.Contains(cli.AbortParam) {
 		return "", 0, 0, 0, cherry_pick.AbortCherryPick(ctx, dbName)
 	}
+	if apr.Contains(cli.ContinueFlag) {
+		return continueCherryPick(ctx, dbName)
+	}
 
-	// we only support cherry-picking a single commit for now.
 	if apr.NArg() == 0 {
 		return "", 0, 0, 0, ErrEmptyCherryPick
-	} else if apr.NArg() > 1 {
-		return "", 0, 0, 0, fmt.Errorf("cherry-picking multiple commits is not supported yet")
 	}
-
-	cherryStr := apr.Arg(0)
-	if len(cherryStr) == 0 {
-		return "", 0, 0, 0, ErrEmptyCherryPick
+	for _, cherryStr := range apr.Args {
+		if len(cherryStr) == 0 {
+			return "", 0, 0, 0, ErrEmptyCherryPick
+		}
 	}
 
 	cherryPickOptions := cherry_pick.NewCherryPickOptions()
@@ -103,18 +110,204 @@ func doDoltCherryPick(ctx *sql.Context, args []string) (string, int, int, int, e
 		cherryPickOptions.EmptyCommitHandling = doltdb.KeepEmptyCommit
 	}
 
-	commit, mergeResult, err := cherry_pick.CherryPick(ctx, cherryStr, cherryPickOptions)
+	if mainline, ok := apr.GetInt(cli.MainlineParam); ok {
+		if mainline < 1 {
+			return "", 0, 0, 0, fmt.Errorf("error: --mainline must be a parent number of at least 1")
+		}
+		cherryPickOptions.Mainline = mainline
+	}
+
+	commits, err := cherry_pick.ExpandRevisions(ctx, dbName, apr.Args)
 	if err != nil {
 		return "", 0, 0, 0, err
 	}
+	if len(commits) == 0 {
+		return "", 0, 0, 0, fmt.Errorf("error: no commits to cherry-pick in %s", strings.Join(apr.Args, " "))
+	}
+
+	return cherryPickCommits(ctx, dbName, commits, cherryPickOptions)
+}
+
+// cherryPickCommits applies |commits| one at a time, each as its own new commit. If a commit can't be applied cleanly,
+// the cherry-pick stops there so that its conflicts can be resolved, and the commits after it are recorded in the
+// working set, to be applied by --continue.
+func cherryPickCommits(ctx *sql.Context, dbName string, commits []string, cherryPickOptions cherry_pick.CherryPickOptions) (string, int, int, int, error) {
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	var newCommitHash string
+	for i, cherryStr := range commits {
+		// Committing a cherry-picked commit ends the transaction, so start a new one to keep the session in sync
+		// with the changes of the previous commit
+		if doltSession.GetTransaction() == nil {
+			if _, err := doltSession.StartTransaction(ctx, sql.ReadWrite); err != nil {
+				return "", 0, 0, 0, err
+			}
+		}
+
+		commit, mergeResult, err := cherry_pick.CherryPick(ctx, cherryStr, cherryPickOptions)
+		if err != nil {
+			return "", 0, 0, 0, err
+		}
+
+		if mergeResult != nil {
+			if err = recordPendingCherryPicks(ctx, dbName, commits[i+1:], cherryPickOptions); err != nil {
+				return "", 0, 0, 0, err
+			}
+			return "",
+				mergeResult.CountOfTablesWithDataConflicts(),
+				mergeResult.CountOfTablesWithSchemaConflicts(),
+				mergeResult.CountOfTablesWithConstraintViolations(),
+				nil
+		}
+		if commit != "" {
+			newCommitHash = commit
+		}
+	}
 
-	if mergeResult != nil {
-		return "",
-			mergeResult.CountOfTablesWithDataConflicts(),
-			mergeResult.CountOfTablesWithSchemaConflicts(),
-			mergeResult.CountOfTablesWithConstraintViolations(),
-			nil
+	return newCommitHash, 0, 0, 0, nil
+}
+
+// recordPendingCherryPicks records the |pending| commits, and the options to cherry-pick them with, in the
+// cherry-pick in progress of the working set.
+func recordPendingCherryPicks(ctx *sql.Context, dbName string, pending []string, cherryPickOptions cherry_pick.CherryPickOptions) error {
+	if len(pending) == 0 {
+		return nil
 	}
 
-	return commit, 0, 0, 0, nil
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := doltSession.GetDoltDB(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("failed to get DoltDB")
+	}
+	headRef, err := doltSession.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return err
+	}
+
+	commits := make([]*doltdb.Commit, len(pending))
+	for i, cherryStr := range pending {
+		cs, err := doltdb.NewCommitSpec(cherryStr)
+		if err != nil {
+			return err
+		}
+		optCmt, err := ddb.Resolve(ctx, cs, headRef)
+		if err != nil {
+			return err
+		}
+		commit, ok := optCmt.ToCommit()
+		if !ok {
+			return doltdb.ErrGhostCommitEncountered
+		}
+		commits[i] = commit
+	}
+
+	ws, err := doltSession.WorkingSet(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	allowEmpty := cherryPickOptions.EmptyCommitHandling == doltdb.KeepEmptyCommit
+	return doltSession.SetWorkingSet(ctx, dbName, ws.WithPendingCherryPicks(commits, cherryPickOptions.Mainline, allowEmpty))
+}
+
+// continueCherryPick commits the resolved changes of the commit that the cherry-pick in progress stopped at, and then
+// cherry-picks the commits that were pending after it.
+func continueCherryPick(ctx *sql.Context, dbName string) (string, int, int, int, error) {
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	ws, err := doltSession.WorkingSet(ctx, dbName)
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
+	if !ws.MergeActive() || !ws.MergeState().IsCherryPick() {
+		return "", 0, 0, 0, ErrNoCherryPickInProgress
+	}
+
+	workingRoot := ws.WorkingRoot()
+	if hasConflicts, err := doltdb.HasConflicts(ctx, workingRoot); err != nil {
+		return "", 0, 0, 0, err
+	} else if hasConflicts {
+		return "", 0, 0, 0, ErrCherryPickUnresolvedConflicts
+	}
+	if hasViolations, err := doltdb.HasConstraintViolations(ctx, workingRoot); err != nil {
+		return "", 0, 0, 0, err
+	} else if hasViolations {
+		return "", 0, 0, 0, ErrCherryPickUnresolvedConflicts
+	}
+	if ws.MergeState().HasSchemaConflicts() {
+		return "", 0, 0, 0, ErrCherryPickUnresolvedConflicts
+	}
+
+	mergeState := ws.MergeState()
+	cherryPickOptions := cherry_pick.NewCherryPickOptions()
+	cherryPickOptions.Mainline = mergeState.CherryPickMainline()
+	if mergeState.CherryPickAllowEmpty() {
+		cherryPickOptions.EmptyCommitHandling = doltdb.KeepEmptyCommit
+	}
+	pending := make([]string, len(mergeState.PendingCherryPicks()))
+	for i, commit := range mergeState.PendingCherryPicks() {
+		h, err := commit.HashOf()
+		if err != nil {
+			return "", 0, 0, 0, err
+		}
+		pending[i] = h.String()
+	}
+
+	newCommitHash, err := commitResolvedCherryPick(ctx, dbName, mergeState.Commit())
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
+
+	lastCommitHash, dataConflicts, schemaConflicts, constraintViolations, err := cherryPickCommits(ctx, dbName, pending, cherryPickOptions)
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
+	if dataConflicts > 0 || schemaConflicts > 0 || constraintViolations > 0 {
+		return "", dataConflicts, schemaConflicts, constraintViolations, nil
+	}
+	if lastCommitHash != "" {
+		newCommitHash = lastCommitHash
+	}
+	return newCommitHash, 0, 0, 0, nil
+}
+
+// commitResolvedCherryPick commits the working set of a cherry-pick that stopped at |commit|, once its conflicts are
+// resolved, with the message of |commit|. If the resolved changes leave nothing to commit, the cherry-pick in progress
+// is cleared instead, and the empty string is returned.
+func commitResolvedCherryPick(ctx *sql.Context, dbName string, commit *doltdb.Commit) (string, error) {
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	headCommit, err := doltSession.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	headRoot, err := headCommit.GetRootValue(ctx)
+	if err != nil {
+		return "", err
+	}
+	ws, err := doltSession.WorkingSet(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	if equal, err := rootsHaveSameHash(headRoot, ws.WorkingRoot()); err != nil {
+		return "", err
+	} else if equal {
+		ws = ws.WithStagedRoot(headRoot).ClearMerge()
+		return "", doltSession.SetWorkingSet(ctx, dbName, ws)
+	}
+
+	meta, err := commit.GetCommitMeta(ctx)
+	if err != nil {
+		return "", err
+	}
+	newCommitHash, _, err := doDoltCommit(ctx, []string{"-a", "-m", meta.Description})
+	return newCommitHash, err
+}
+
+func rootsHaveSameHash(root1, root2 doltdb.RootValue) (bool, error) {
+	root1Hash, err := root1.HashOf()
+	if err != nil {
+		return false, err
+	}
+	root2Hash, err := root2.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return root1Hash.Equal(root2Hash), nil
 }
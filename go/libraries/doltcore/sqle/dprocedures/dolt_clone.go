@@ -15,6 +15,7 @@
 package dprocedures
 
 import (
+	"fmt"
 	"path"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -36,6 +37,9 @@ func doltClone(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 	if err != nil {
 		return nil, err
 	}
+	if apr.Contains(cli.MaskedFlag) {
+		return nil, fmt.Errorf("--%s is only supported by the dolt clone command", cli.MaskedFlag)
+	}
 
 	remoteName := apr.GetValueOrDefault(cli.RemoteParam, "origin")
 	branch := apr.GetValueOrDefault(cli.BranchParam, "")
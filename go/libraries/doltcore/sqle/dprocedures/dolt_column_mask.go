@@ -0,0 +1,79 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/colmask"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// doltCreateColumnMask masks a column holding sensitive data from the users who don't administer the database. Only
+// branch admins and database administrators can create masks. Usage:
+//
+//	CALL dolt_create_column_mask('table', 'column', [--type redact|hash|partial], [--visible-prefix n],
+//	    [--visible-suffix n])
+func doltCreateColumnMask(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if err := checkColumnMaskAccess(ctx); err != nil {
+		return nil, err
+	}
+	apr, err := cli.CreateColumnMaskArgParser().Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if apr.NArg() != 2 {
+		return nil, fmt.Errorf("dolt_create_column_mask requires a table name and a column name")
+	}
+
+	m := colmask.Mask{Table: apr.Arg(0), Column: apr.Arg(1)}
+	m.Type, _ = apr.GetValue(cli.MaskTypeParam)
+	m.Prefix = apr.GetIntOrDefault(cli.VisiblePrefixParam, 0)
+	m.Suffix = apr.GetIntOrDefault(cli.VisibleSuffixParam, 0)
+	if err = colmask.CreateMask(ctx, ctx.GetCurrentDatabase(), m); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
+
+// doltDropColumnMask removes the mask of a column. Only branch admins and database administrators can drop masks.
+func doltDropColumnMask(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if err := checkColumnMaskAccess(ctx); err != nil {
+		return nil, err
+	}
+	if len(args) != 2 {
+		return nil, fmt.Errorf("dolt_drop_column_mask requires a table name and a column name")
+	}
+	if err := colmask.DropMask(ctx, ctx.GetCurrentDatabase(), args[0], args[1]); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
+
+// checkColumnMaskAccess returns an error unless the current user may change the column masks of the current database.
+func checkColumnMaskAccess(ctx *sql.Context) error {
+	db, err := dsess.DSessFromSess(ctx.Session).Provider().Database(ctx, ctx.GetCurrentDatabase())
+	if err != nil {
+		return err
+	}
+	sqlDb, ok := db.(dsess.SqlDatabase)
+	if !ok {
+		return fmt.Errorf("database %s does not support column masks", ctx.GetCurrentDatabase())
+	}
+	return dsess.CheckAdminAccessForDb(ctx, sqlDb)
+}
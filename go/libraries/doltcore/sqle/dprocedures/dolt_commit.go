@@ -17,7 +17,6 @@ package dprocedures
 import (
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
@@ -25,10 +24,10 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
-	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/signing"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
-	"github.com/dolthub/dolt/go/libraries/utils/gpg"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/matview"
 	"github.com/dolthub/dolt/go/store/datas"
 )
 
@@ -166,11 +165,16 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 		Email:      email,
 	}
 
-	shouldSign, err := dsess.GetBooleanSystemVar(ctx, "gpgsign")
+	shouldSign, err := dsess.GetBooleanSystemVar(ctx, dsess.GPGSign)
 	if err != nil {
 		return "", false, fmt.Errorf("failed to get gpgsign: %w", err)
 	}
 
+	roots, err = matview.RefreshOnCommit(ctx, dbName, roots)
+	if err != nil {
+		return "", false, err
+	}
+
 	pendingCommit, err := dSess.NewPendingCommit(ctx, dbName, roots, csp)
 	if err != nil {
 		return "", false, err
@@ -184,28 +188,13 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 	}
 
 	if apr.Contains(cli.SignFlag) || shouldSign {
-		keyId := apr.GetValueOrDefault(cli.SignFlag, "")
-
-		if keyId == "" {
-			v, err := ctx.GetSessionVariable(ctx, "signingkey")
-			if err != nil && !sql.ErrUnknownSystemVariable.Is(err) {
-				return "", false, fmt.Errorf("failed to get signingkey: %w", err)
-			} else if err == nil {
-				keyId = v.(string)
-			}
-		}
-
-		strToSign, err := commitSignatureStr(ctx, dbName, roots, csp)
-		if err != nil {
-			return "", false, err
-		}
-
-		signature, err := gpg.Sign(ctx, keyId, []byte(strToSign))
+		cfg, err := dsess.GetSigningConfig(ctx, apr.GetValueOrDefault(cli.SignFlag, ""))
 		if err != nil {
 			return "", false, err
 		}
 
-		pendingCommit.CommitOptions.Meta.Signature = string(signature)
+		// the commit is signed when it is written, once its parents are known
+		pendingCommit.CommitOptions.Sign = signing.CommitSigner(cfg)
 	}
 
 	newCommit, err := dSess.DoltCommit(ctx, dbName, dSess.GetTransaction(), pendingCommit)
@@ -241,27 +230,3 @@ func getDoltArgs(ctx *sql.Context, row sql.Row, children []sql.Expression) ([]st
 
 	return args, nil
 }
-
-func commitSignatureStr(ctx *sql.Context, dbName string, roots doltdb.Roots, csp actions.CommitStagedProps) (string, error) {
-	var lines []string
-	lines = append(lines, fmt.Sprint("db: ", dbName))
-	lines = append(lines, fmt.Sprint("Message: ", csp.Message))
-	lines = append(lines, fmt.Sprint("Name: ", csp.Name))
-	lines = append(lines, fmt.Sprint("Email: ", csp.Email))
-	lines = append(lines, fmt.Sprint("Date: ", csp.Date.String()))
-
-	head, err := roots.Head.HashOf()
-	if err != nil {
-		return "", err
-	}
-
-	staged, err := roots.Staged.HashOf()
-	if err != nil {
-		return "", err
-	}
-
-	lines = append(lines, fmt.Sprint("Head: ", head.String()))
-	lines = append(lines, fmt.Sprint("Staged: ", staged.String()))
-
-	return strings.Join(lines, "\n"), nil
-}
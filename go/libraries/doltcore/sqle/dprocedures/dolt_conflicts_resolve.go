@@ -58,22 +58,22 @@ type DoltConflictsCatFunc struct {
 	children []sql.Expression
 }
 
-func getProllyRowMaps(ctx *sql.Context, vrw types.ValueReadWriter, ns tree.NodeStore, hash hash.Hash, tblName string) (prolly.Map, error) {
+// getProllyRowMaps returns the rows of |tblName| in the root at |hash|, keyed by the primary key of |sch|. See
+// merge.ConflictRowData for the meaning of |base|.
+func getProllyRowMaps(ctx *sql.Context, vrw types.ValueReadWriter, ns tree.NodeStore, hash hash.Hash, tblName string, sch schema.Schema, base *prolly.Map) (prolly.Map, error) {
 	rootVal, err := doltdb.LoadRootValueFromRootIshAddr(ctx, vrw, ns, hash)
-	tbl, ok, err := rootVal.GetTable(ctx, doltdb.TableName{Name: tblName})
 	if err != nil {
 		return prolly.Map{}, err
 	}
-	if !ok {
-		return prolly.Map{}, doltdb.ErrTableNotFound
-	}
-
-	idx, err := tbl.GetRowData(ctx)
+	tbl, ok, err := doltdb.GetTableByIdentity(ctx, rootVal, doltdb.TableName{Name: tblName}, sch)
 	if err != nil {
 		return prolly.Map{}, err
 	}
+	if !ok {
+		return prolly.Map{}, doltdb.ErrTableNotFound
+	}
 
-	return durable.ProllyMapFromIndex(idx), nil
+	return merge.ConflictRowData(ctx, tbl, sch, base)
 }
 
 func resolveProllyConflicts(ctx *sql.Context, tbl *doltdb.Table, tblName string, ourSch, sch schema.Schema) (*doltdb.Table, error) {
@@ -120,7 +120,14 @@ func resolveProllyConflicts(ctx *sql.Context, tbl *doltdb.Table, tblName string,
 
 		// reload if their root hash changes
 		if theirRoot != cnfArt.TheirRootIsh {
-			theirMap, err = getProllyRowMaps(ctx, tbl.ValueReadWriter(), tbl.NodeStore(), cnfArt.TheirRootIsh, tblName)
+			var base *prolly.Map
+			baseMap, err := getProllyRowMaps(ctx, tbl.ValueReadWriter(), tbl.NodeStore(), cnfArt.Metadata.BaseRootIsh, tblName, sch, nil)
+			if err == nil {
+				base = &baseMap
+			} else if !errors.Is(err, doltdb.ErrTableNotFound) {
+				return nil, err
+			}
+			theirMap, err = getProllyRowMaps(ctx, tbl.ValueReadWriter(), tbl.NodeStore(), cnfArt.TheirRootIsh, tblName, sch, base)
 			if err != nil {
 				return nil, err
 			}
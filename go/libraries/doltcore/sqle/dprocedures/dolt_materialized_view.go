@@ -0,0 +1,91 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/matview"
+)
+
+var refreshMaterializedViewSchema = stringSchema("view_name", "refresh")
+
+// doltCreateMaterializedView creates a materialized view, which stores the result of a SELECT statement in a table
+// that is kept up to date by doltRefreshMaterializedView. Usage:
+//
+//	CALL dolt_create_materialized_view([--auto-refresh], 'name', 'SELECT ...')
+func doltCreateMaterializedView(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return nil, err
+	}
+	apr, err := cli.CreateMaterializedViewArgParser().Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if apr.NArg() != 2 {
+		return nil, fmt.Errorf("dolt_create_materialized_view requires a name and a SELECT statement")
+	}
+
+	err = matview.CreateView(ctx, ctx.GetCurrentDatabase(), apr.Arg(0), apr.Arg(1), apr.Contains(cli.AutoRefreshFlag))
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
+
+// doltRefreshMaterializedView brings the named materialized views, or all of them with --all, up to date with their
+// source tables. It returns whether each view was refreshed incrementally, fully, or was already up to date.
+func doltRefreshMaterializedView(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return nil, err
+	}
+	apr, err := cli.CreateRefreshMaterializedViewArgParser().Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if apr.NArg() == 0 && !apr.Contains(cli.AllFlag) {
+		return nil, fmt.Errorf("must specify the materialized views to refresh, or --all")
+	} else if apr.NArg() > 0 && apr.Contains(cli.AllFlag) {
+		return nil, fmt.Errorf("--all cannot be used with the names of materialized views")
+	}
+
+	names, kinds, err := matview.RefreshViews(ctx, ctx.GetCurrentDatabase(), apr.Args)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]sql.Row, len(names))
+	for i := range names {
+		rows[i] = sql.Row{names[i], kinds[i]}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// doltDropMaterializedView drops a materialized view and the table holding its rows.
+func doltDropMaterializedView(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return nil, err
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("dolt_drop_materialized_view requires the name of a materialized view")
+	}
+	if err := matview.DropView(ctx, ctx.GetCurrentDatabase(), args[0]); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
@@ -153,6 +153,10 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, string, err
 		return "", noConflictsOrViolations, threeWayMerge, "merge aborted", nil
 	}
 
+	if apr.NArg() > 1 {
+		return doDoltOctopusMerge(ctx, sess, ws, roots, dbName, apr)
+	}
+
 	branchName := apr.Arg(0)
 
 	mergeSpec, err := createMergeSpec(ctx, sess, dbName, apr, branchName)
@@ -160,6 +164,19 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, string, err
 		return "", noConflictsOrViolations, threeWayMerge, "", err
 	}
 
+	return doDoltMergeSpec(ctx, sess, ws, dbName, apr, branchName, mergeSpec)
+}
+
+// doDoltMergeSpec merges the single branch named |branchName|, described by |mergeSpec|, into the current branch.
+func doDoltMergeSpec(
+	ctx *sql.Context,
+	sess *dsess.DoltSession,
+	ws *doltdb.WorkingSet,
+	dbName string,
+	apr *argparser.ArgParseResults,
+	branchName string,
+	mergeSpec *merge.MergeSpec,
+) (string, int, int, string, error) {
 	dbData, ok := sess.GetDbData(ctx, dbName)
 	if !ok {
 		return "", noConflictsOrViolations, threeWayMerge, "", fmt.Errorf("Could not load database %s", dbName)
@@ -185,6 +202,177 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, string, err
 	return commit, conflicts, fastForward, message, nil
 }
 
+// doDoltOctopusMerge merges all the branches named in |apr| into the current branch with a single merge commit, whose
+// parents are HEAD and each merged branch. Branches that are already merged into HEAD or into another of the branches
+// are skipped, and if only one branch is left to merge it is merged normally. The merge is all or nothing: if merging
+// any of the branches produces conflicts or constraint violations, the merge is aborted without changing the working
+// set.
+func doDoltOctopusMerge(
+	ctx *sql.Context,
+	sess *dsess.DoltSession,
+	ws *doltdb.WorkingSet,
+	roots doltdb.Roots,
+	dbName string,
+	apr *argparser.ArgParseResults,
+) (string, int, int, string, error) {
+	for _, param := range []string{cli.SquashParam, cli.NoCommitFlag, cli.NoFFParam} {
+		if apr.Contains(param) {
+			return "", noConflictsOrViolations, threeWayMerge, "", fmt.Errorf("error: Flag '--%s' is not supported when merging more than one branch", param)
+		}
+	}
+	if ws.MergeActive() {
+		return "", noConflictsOrViolations, threeWayMerge, "", doltdb.ErrMergeActive
+	}
+	if clean, err := rootsAreClean(roots); err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, "", err
+	} else if !clean {
+		return "", noConflictsOrViolations, threeWayMerge, "", ErrUncommittedChanges.New()
+	}
+
+	var specs []*merge.MergeSpec
+	var branchNames []string
+	merged := make(map[hash.Hash]struct{})
+	for _, branchName := range apr.Args {
+		spec, err := createMergeSpec(ctx, sess, dbName, apr, branchName)
+		if err != nil {
+			return "", noConflictsOrViolations, threeWayMerge, "", err
+		}
+		if _, ok := merged[spec.MergeH]; ok {
+			continue
+		}
+		if _, err = spec.HeadC.CanFastForwardTo(ctx, spec.MergeC); err == doltdb.ErrIsAhead || err == doltdb.ErrUpToDate {
+			continue
+		} else if err != nil {
+			return "", noConflictsOrViolations, threeWayMerge, "", err
+		}
+		merged[spec.MergeH] = struct{}{}
+		specs = append(specs, spec)
+		branchNames = append(branchNames, branchName)
+	}
+	specs, branchNames, err := dropMergedSpecs(ctx, specs, branchNames)
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, "", err
+	}
+
+	switch len(specs) {
+	case 0:
+		ctx.Warn(DoltMergeWarningCode, doltdb.ErrUpToDate.Error())
+		return "", noConflictsOrViolations, threeWayMerge, doltdb.ErrUpToDate.Error(), nil
+	case 1:
+		return doDoltMergeSpec(ctx, sess, ws, dbName, apr, branchNames[0], specs[0])
+	}
+
+	dbData, ok := sess.GetDbData(ctx, dbName)
+	if !ok {
+		return "", noConflictsOrViolations, threeWayMerge, "", fmt.Errorf("Could not load database %s", dbName)
+	}
+	dbState, ok, err := sess.LookupDbState(ctx, dbName)
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, "", err
+	} else if !ok {
+		return "", noConflictsOrViolations, threeWayMerge, "", sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, "", err
+	}
+	msg := fmt.Sprintf("Merge branches %s into %s", quoteBranchNames(branchNames), headRef.GetPath())
+	if userMsg, mOk := apr.GetValue(cli.MessageArg); mOk {
+		msg = userMsg
+	}
+
+	mergeCommits := make([]*doltdb.Commit, len(specs))
+	for i, spec := range specs {
+		mergeCommits[i] = spec.MergeC
+	}
+	mergedRoot, err := merge.MergeOctopus(ctx, specs[0].HeadC, mergeCommits, branchNames, dbState.EditOpts(), specs[0].ColumnDefaults)
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, "", err
+	}
+
+	ws = ws.WithWorkingRoot(mergedRoot).WithStagedRoot(mergedRoot)
+	if err = sess.SetWorkingSet(ctx, dbName, ws); err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, "", err
+	}
+	roots, _ = sess.GetRoots(ctx, dbName)
+
+	pendingCommit, err := actions.GetCommitStaged(ctx, roots, ws, mergeCommits, dbData.Ddb, actions.CommitStagedProps{
+		Message:    msg,
+		Date:       specs[0].Date,
+		AllowEmpty: true,
+		Force:      specs[0].Force,
+		Name:       specs[0].Name,
+		Email:      specs[0].Email,
+	})
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, "", err
+	}
+	commit, err := sess.DoltCommit(ctx, dbName, sess.GetTransaction(), pendingCommit)
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, "", err
+	}
+	h, err := commit.HashOf()
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, "", err
+	}
+
+	return h.String(), noConflictsOrViolations, threeWayMerge, "merge successful", nil
+}
+
+// dropMergedSpecs returns |specs| and their |branchNames| without the branches that are already part of another
+// branch of |specs|, since merging that other branch merges them too.
+func dropMergedSpecs(ctx *sql.Context, specs []*merge.MergeSpec, branchNames []string) ([]*merge.MergeSpec, []string, error) {
+	var keptSpecs []*merge.MergeSpec
+	var keptNames []string
+	for i, spec := range specs {
+		reachable := false
+		for j, other := range specs {
+			if i == j {
+				continue
+			}
+			canFF, err := spec.MergeC.CanFastForwardTo(ctx, other.MergeC)
+			if err != nil && err != doltdb.ErrIsAhead {
+				return nil, nil, err
+			} else if err == nil && canFF {
+				reachable = true
+				break
+			}
+		}
+		if !reachable {
+			keptSpecs = append(keptSpecs, spec)
+			keptNames = append(keptNames, branchNames[i])
+		}
+	}
+	return keptSpecs, keptNames, nil
+}
+
+// rootsAreClean returns whether the working and staged roots of |roots| are the same as its head root.
+func rootsAreClean(roots doltdb.Roots) (bool, error) {
+	headHash, err := roots.Head.HashOf()
+	if err != nil {
+		return false, err
+	}
+	stagedHash, err := roots.Staged.HashOf()
+	if err != nil {
+		return false, err
+	}
+	workingHash, err := roots.Working.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return headHash == stagedHash && headHash == workingHash, nil
+}
+
+// quoteBranchNames returns |names| quoted and joined for a merge commit message, e.g. 'a', 'b' and 'c'.
+func quoteBranchNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("'%s'", name)
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " and " + quoted[len(quoted)-1]
+}
+
 // performMerge encapsulates server merge logic, switching between
 // fast-forward, no fast-forward, merge commit, and merging into working set.
 // Returns a new WorkingSet, whether there were merge conflicts, and whether a
@@ -271,7 +459,7 @@ func performMerge(
 		return ws, "", noConflictsOrViolations, threeWayMerge, "", sql.ErrDatabaseNotFound.New(dbName)
 	}
 
-	ws, err = executeMerge(ctx, sess, dbName, spec.Squash, spec.Force, spec.HeadC, spec.MergeC, spec.MergeCSpecStr, ws, dbState.EditOpts(), spec.WorkingDiffs)
+	ws, err = executeMerge(ctx, sess, dbName, spec.Squash, spec.Force, spec.HeadC, spec.MergeC, spec.MergeCSpecStr, ws, dbState.EditOpts(), spec.WorkingDiffs, spec.ColumnDefaults)
 	if err == doltdb.ErrUnresolvedConflictsOrViolations {
 		// if there are unresolved conflicts, write the resulting working set back to the session and return an
 		// error message
@@ -318,8 +506,9 @@ func executeMerge(
 	ws *doltdb.WorkingSet,
 	opts editor.Options,
 	workingDiffs map[doltdb.TableName]hash.Hash,
+	columnDefaults merge.ColumnDefaults,
 ) (*doltdb.WorkingSet, error) {
-	result, err := merge.MergeCommits(ctx, head, cm, opts)
+	result, err := merge.MergeCommits(ctx, head, cm, opts, columnDefaults)
 	if err != nil {
 		switch err {
 		case doltdb.ErrUpToDate:
@@ -478,6 +667,14 @@ func createMergeSpec(ctx *sql.Context, sess *dsess.DoltSession, dbName string, a
 	if apr.Contains(cli.NoCommitFlag) && apr.Contains(cli.CommitFlag) {
 		return nil, errors.New("cannot define both 'commit' and 'no-commit' flags at the same time")
 	}
+
+	var columnDefaults merge.ColumnDefaults
+	if columnDefaultsStr, ok := apr.GetValue(cli.ColumnDefaultParam); ok {
+		columnDefaults, err = merge.ParseColumnDefaults(columnDefaultsStr)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return merge.NewMergeSpec(
 		ctx,
 		dbData.Rsr,
@@ -492,6 +689,7 @@ func createMergeSpec(ctx *sql.Context, sess *dsess.DoltSession, dbName string, a
 		merge.WithForce(apr.Contains(cli.ForceFlag)),
 		merge.WithNoCommit(apr.Contains(cli.NoCommitFlag)),
 		merge.WithNoEdit(apr.Contains(cli.NoEditFlag)),
+		merge.WithColumnDefaults(columnDefaults),
 	)
 }
 
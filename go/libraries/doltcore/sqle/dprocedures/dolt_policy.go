@@ -0,0 +1,63 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/rowpolicy"
+)
+
+// doltCreatePolicy creates a row-level security policy, which restricts the rows of a table that matching users can
+// read and write, to the working set of the current branch. Policies take effect once they are in the working set of the
+// default branch. Only admins of every branch and database administrators can create policies. Usage:
+//
+//	CALL dolt_create_policy('name', 'table', [--for command], [--user pattern], [--host pattern], [--branch pattern],
+//	    [--using 'expression'], [--check 'expression'])
+func doltCreatePolicy(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	apr, err := cli.CreatePolicyArgParser().Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if apr.NArg() != 2 {
+		return nil, fmt.Errorf("dolt_create_policy requires a policy name and a table name")
+	}
+
+	p := rowpolicy.Policy{Name: apr.Arg(0), Table: apr.Arg(1)}
+	p.Command, _ = apr.GetValue(cli.ForParam)
+	p.User, _ = apr.GetValue(cli.UserFlag)
+	p.Host, _ = apr.GetValue(cli.HostFlag)
+	p.Branch, _ = apr.GetValue(cli.BranchParam)
+	p.Using, _ = apr.GetValue(cli.UsingParam)
+	p.Check, _ = apr.GetValue(cli.CheckParam)
+	if err = rowpolicy.CreatePolicy(ctx, ctx.GetCurrentDatabase(), p); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
+
+// doltDropPolicy drops a row-level security policy. The same users that can create a policy can drop it.
+func doltDropPolicy(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("dolt_drop_policy requires the name of a policy")
+	}
+	if err := rowpolicy.DropPolicy(ctx, ctx.GetCurrentDatabase(), args[0]); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
@@ -53,7 +53,8 @@ var RebaseActionEnumType = types.MustCreateEnumType([]string{
 	rebase.RebaseActionPick,
 	rebase.RebaseActionReword,
 	rebase.RebaseActionSquash,
-	rebase.RebaseActionFixup}, sql.Collation_Default)
+	rebase.RebaseActionFixup,
+	rebase.RebaseActionExec}, sql.Collation_Default)
 
 // GetDoltRebaseSystemTableSchema returns the schema for the dolt_rebase system table.
 // This is used by Doltgres to update the dolt_rebase schema using Doltgres types.
@@ -123,6 +124,15 @@ var ErrRebaseConflictWithAbortError = goerrors.NewKind(
 	"merge conflict detected while rebasing commit %s. " +
 		"attempted to abort rebase operation, but encountered error: %w")
 
+// ErrRebaseExecFailed is used when the SQL statement of an exec step fails, or when it returns a false result.
+var ErrRebaseExecFailed = goerrors.NewKind("exec step %s failed: %s\n\n" +
+	"The rebase has been stopped. Fix the problem, then continue the rebase by calling dolt_rebase('--continue'), " +
+	"or abort it by calling dolt_rebase('--abort')")
+
+// ErrRebaseExecUncommittedChanges is used when the SQL statement of an exec step leaves changes in the working set.
+var ErrRebaseExecUncommittedChanges = goerrors.NewKind("exec step %s left uncommitted changes. " +
+	"Commit or discard them, then continue the rebase by calling dolt_rebase('--continue')")
+
 // SuccessfulRebaseMessage is used when a rebase finishes successfully. The branch that was rebased should be appended
 // to the end of the message.
 var SuccessfulRebaseMessage = "Successfully rebased and updated refs/heads/"
@@ -182,7 +192,11 @@ func doDoltRebase(ctx *sql.Context, args []string) (int, string, error) {
 		if !apr.Contains(cli.InteractiveFlag) {
 			return 1, "", fmt.Errorf("non-interactive rebases not currently supported")
 		}
-		err = startRebase(ctx, apr.Arg(0), commitBecomesEmptyHandling, emptyCommitHandling)
+		onto, _ := apr.GetValue(cli.OntoParam)
+		planOpts := rebase.RebasePlanOptions{
+			Autosquash: apr.Contains(cli.AutosquashFlag),
+		}
+		err = startRebase(ctx, apr.Arg(0), onto, planOpts, commitBecomesEmptyHandling, emptyCommitHandling)
 		if err != nil {
 			return 1, "", err
 		}
@@ -222,12 +236,17 @@ func processCommitBecomesEmptyParams(apr *argparser.ArgParseResults) (doltdb.Emp
 }
 
 // startRebase starts a new interactive rebase operation. |upstreamPoint| specifies the commit where the new rebased
-// commits will be based off of, |commitBecomesEmptyHandling| specifies how to  handle commits that are not empty, but
-// do not produce any changes when applied, and |emptyCommitHandling| specifies how to handle empty commits.
-func startRebase(ctx *sql.Context, upstreamPoint string, commitBecomesEmptyHandling doltdb.EmptyCommitHandling, emptyCommitHandling doltdb.EmptyCommitHandling) error {
+// commits will be based off of, unless |ontoPoint| is set, in which case the commits between |upstreamPoint| and the
+// current branch are based off of |ontoPoint| instead. |planOpts| controls how the rebase plan is created,
+// |commitBecomesEmptyHandling| specifies how to  handle commits that are not empty, but do not produce any changes
+// when applied, and |emptyCommitHandling| specifies how to handle empty commits.
+func startRebase(ctx *sql.Context, upstreamPoint, ontoPoint string, planOpts rebase.RebasePlanOptions, commitBecomesEmptyHandling doltdb.EmptyCommitHandling, emptyCommitHandling doltdb.EmptyCommitHandling) error {
 	if upstreamPoint == "" {
 		return fmt.Errorf("no upstream branch specified")
 	}
+	if ontoPoint == "" {
+		ontoPoint = upstreamPoint
+	}
 
 	err := validateWorkingSetCanStartRebase(ctx)
 	if err != nil {
@@ -268,11 +287,27 @@ func startRebase(ctx *sql.Context, upstreamPoint string, commitBecomesEmptyHandl
 		return doltdb.ErrGhostCommitEncountered
 	}
 
+	ontoCommit := upstreamCommit
+	if ontoPoint != upstreamPoint {
+		commitSpec, err = doltdb.NewCommitSpec(ontoPoint)
+		if err != nil {
+			return err
+		}
+		optCmt, err = dbData.Ddb.Resolve(ctx, commitSpec, headRef)
+		if err != nil {
+			return err
+		}
+		ontoCommit, ok = optCmt.ToCommit()
+		if !ok {
+			return doltdb.ErrGhostCommitEncountered
+		}
+	}
+
 	// rebaseWorkingBranch is the name of the temporary branch used when performing a rebase. In Git, a rebase
 	// happens with a detached HEAD, but Dolt doesn't support that, we use a temporary branch.
 	rebaseWorkingBranch := "dolt_rebase_" + rebaseBranch
 	var rsc doltdb.ReplicationStatusController
-	err = actions.CreateBranchWithStartPt(ctx, dbData, rebaseWorkingBranch, upstreamPoint, false, &rsc)
+	err = actions.CreateBranchWithStartPt(ctx, dbData, rebaseWorkingBranch, ontoPoint, false, &rsc)
 	if err != nil {
 		return err
 	}
@@ -311,7 +346,7 @@ func startRebase(ctx *sql.Context, upstreamPoint string, commitBecomesEmptyHandl
 		return err
 	}
 
-	newWorkingSet, err := workingSet.StartRebase(ctx, upstreamCommit, rebaseBranch, branchRoots.Working,
+	newWorkingSet, err := workingSet.StartRebase(ctx, ontoCommit, rebaseBranch, branchRoots.Working,
 		commitBecomesEmptyHandling, emptyCommitHandling)
 	if err != nil {
 		return err
@@ -323,7 +358,7 @@ func startRebase(ctx *sql.Context, upstreamPoint string, commitBecomesEmptyHandl
 	}
 
 	// Create the rebase plan and save it in the database
-	rebasePlan, err := rebase.CreateDefaultRebasePlan(ctx, startCommit, upstreamCommit)
+	rebasePlan, err := rebase.CreateRebasePlan(ctx, startCommit, upstreamCommit, planOpts)
 	if err != nil {
 		abortErr := abortRebase(ctx)
 		if abortErr != nil {
@@ -751,6 +786,11 @@ func continueRebase(ctx *sql.Context) (string, error) {
 // resolved by the caller before rebasing has been continued. This involves building the correct commit
 // message based on the details of the rebase plan |step| and then creating the commit.
 func commitManuallyStagedChangesForStep(ctx *sql.Context, step rebase.RebasePlanStep) error {
+	// Exec steps don't create a commit, so there's nothing to attribute the staged changes to
+	if step.Action == rebase.RebaseActionExec {
+		return ErrRebaseExecUncommittedChanges.New(step.RebaseOrder.String())
+	}
+
 	doltSession := dsess.DSessFromSess(ctx.Session)
 	workingSet, err := doltSession.WorkingSet(ctx, ctx.GetCurrentDatabase())
 	if err != nil {
@@ -759,6 +799,9 @@ func commitManuallyStagedChangesForStep(ctx *sql.Context, step rebase.RebasePlan
 
 	options, err := createCherryPickOptionsForRebaseStep(ctx, &step, workingSet.RebaseState().CommitBecomesEmptyHandling(),
 		workingSet.RebaseState().EmptyCommitHandling())
+	if err != nil {
+		return err
+	}
 
 	commitProps, err := cherry_pick.CreateCommitStagedPropsFromCherryPickOptions(ctx, *options)
 	if err != nil {
@@ -809,6 +852,10 @@ func processRebasePlanStep(ctx *sql.Context, planStep *rebase.RebasePlanStep,
 		return nil
 	}
 
+	if planStep.Action == rebase.RebaseActionExec {
+		return execRebasePlanStep(ctx, planStep)
+	}
+
 	options, err := createCherryPickOptionsForRebaseStep(ctx, planStep, commitBecomesEmptyHandling, emptyCommitHandling)
 	if err != nil {
 		return err
@@ -850,6 +897,54 @@ func createCherryPickOptionsForRebaseStep(ctx *sql.Context, planStep *rebase.Reb
 	return &options, nil
 }
 
+// execRebasePlanStep runs the SQL statement of the exec |planStep|. If the statement fails, if it returns a result
+// whose first column is false, zero, or NULL, or if it leaves uncommitted changes in the working set, then
+// ErrRebaseExecFailed is returned and the rebase stops. Since the step has already been recorded as attempted,
+// continuing the rebase moves on to the next step.
+func execRebasePlanStep(ctx *sql.Context, planStep *rebase.RebasePlanStep) error {
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	stepOrder := planStep.RebaseOrder.String()
+
+	// the statement runs with the session's engine, so it's checked against the privileges of the session's user
+	runner := doltSession.Provider().StatementRunner()
+	if runner == nil {
+		return ErrRebaseExecFailed.New(stepOrder, "no SQL engine is available to run the statement")
+	}
+	sch, iter, _, err := runner.Query(ctx, planStep.CommitMsg)
+	if err != nil {
+		return ErrRebaseExecFailed.New(stepOrder, err.Error())
+	}
+	rows, err := sql.RowIterToRows(ctx, iter)
+	if err != nil {
+		return ErrRebaseExecFailed.New(stepOrder, err.Error())
+	}
+
+	if !types.IsOkResultSchema(sch) && len(rows) > 0 && len(rows[0]) > 0 {
+		ok, _, err := types.Boolean.Convert(rows[0][0])
+		if err != nil {
+			return ErrRebaseExecFailed.New(stepOrder, err.Error())
+		}
+		if ok == nil || ok == int8(0) || ok == false {
+			return ErrRebaseExecFailed.New(stepOrder, fmt.Sprintf("%q returned %v", planStep.CommitMsg, rows[0][0]))
+		}
+	}
+
+	if doltSession.GetTransaction() == nil {
+		if _, err = doltSession.StartTransaction(ctx, sql.ReadWrite); err != nil {
+			return err
+		}
+	}
+	hasStagedChanges, hasUnstagedChanges, err := workingSetStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if hasStagedChanges || hasUnstagedChanges {
+		return ErrRebaseExecUncommittedChanges.New(stepOrder)
+	}
+
+	return nil
+}
+
 // handleRebaseCherryPick runs a cherry-pick for the specified |commitHash|, using the specified
 // cherry-pick |options| and checks the results for any errors or merge conflicts. If a data conflict
 // is detected, then the ErrRebaseDataConflict error is returned. If a schema conflict is detected,
@@ -0,0 +1,43 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/querydigest"
+)
+
+// doltResetQueryDigests discards the statistics of the statements run by the server, which the dolt_query_digests
+// system table shows.
+func doltResetQueryDigests(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) > 0 {
+		return nil, fmt.Errorf("dolt_reset_query_digests does not take any arguments")
+	}
+
+	// The statistics are shared by every user, so only allow admins to discard them
+	privs, counter := ctx.GetPrivilegeSet()
+	if counter == 0 {
+		return nil, fmt.Errorf("unable to check user privileges for dolt_reset_query_digests procedure")
+	}
+	if !privs.Has(sql.PrivilegeType_Super) {
+		return nil, sql.ErrPrivilegeCheckFailed.New(ctx.Session.Client().User)
+	}
+
+	querydigest.Default.Reset()
+	return rowToIter(int64(cmdSuccess)), nil
+}
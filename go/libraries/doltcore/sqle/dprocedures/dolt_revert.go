@@ -15,6 +15,7 @@
 package dprocedures
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -27,8 +28,18 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 )
 
+var ErrNoRevertInProgress = errors.New("error: There is no revert in progress")
+
+var ErrRevertInProgress = errors.New("error: a revert is already in progress. " +
+	"Use --continue to finish it, --skip to skip the current commit, or --abort to undo it")
+
+var ErrRevertUnresolvedConflicts = errors.New("error: cannot continue the revert until all conflicts and " +
+	"constraint violations are resolved")
+
 // doltRevert is the stored procedure version for the CLI command `dolt revert`.
 func doltRevert(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 	res, err := doDoltRevert(ctx, args)
@@ -38,6 +49,10 @@ func doltRevert(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 	return rowToIter(int64(res)), nil
 }
 
+// doDoltRevert reverts the commits given in |args|, or continues, skips or aborts a revert in progress. It returns
+// 0 when the revert is done, and 1 when it stopped at a commit whose revert caused conflicts or constraint violations.
+// The conflicts are then recorded in the working set, along with the state of the revert, so that they can be
+// resolved before the revert is continued.
 func doDoltRevert(ctx *sql.Context, args []string) (int, error) {
 	dbName := ctx.GetCurrentDatabase()
 	dSess := dsess.DSessFromSess(ctx.Session)
@@ -49,6 +64,28 @@ func doDoltRevert(ctx *sql.Context, args []string) (int, error) {
 		return 1, err
 	}
 
+	apr, err := cli.CreateRevertArgParser().Parse(args)
+	if err != nil {
+		return 1, err
+	}
+
+	switch {
+	case apr.Contains(cli.AbortParam):
+		return abortRevert(ctx, dbName)
+	case apr.Contains(cli.ContinueFlag):
+		return continueRevert(ctx, apr, ddb, dbName)
+	case apr.Contains(cli.SkipFlag):
+		return skipRevert(ctx, apr, ddb, dbName)
+	}
+
+	workingSet, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return 1, err
+	}
+	if workingSet.MergeActive() && workingSet.MergeState().IsRevert() {
+		return 1, ErrRevertInProgress
+	}
+
 	roots, ok := dSess.GetRoots(ctx, dbName)
 	if !ok {
 		return 1, fmt.Errorf("Could not load session roots")
@@ -60,93 +97,222 @@ func doDoltRevert(ctx *sql.Context, args []string) (int, error) {
 		return 1, fmt.Errorf("You must commit any changes before using revert")
 	}
 
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return 1, err
+	}
+
+	commits := make([]*doltdb.Commit, apr.NArg())
+	for i, revisionStr := range apr.Args {
+		commitSpec, err := doltdb.NewCommitSpec(revisionStr)
+		if err != nil {
+			return 1, err
+		}
+		optCmt, err := ddb.Resolve(ctx, commitSpec, headRef)
+		if err != nil {
+			return 1, err
+		}
+		commit, ok := optCmt.ToCommit()
+		if !ok {
+			return 1, doltdb.ErrGhostCommitEncountered
+		}
+
+		commits[i] = commit
+	}
+
+	mainline := apr.GetIntOrDefault(cli.MainlineParam, 0)
+	if apr.Contains(cli.MainlineParam) && mainline < 1 {
+		return 1, fmt.Errorf("error: --mainline must be a parent number of at least 1")
+	}
+
+	opts, err := revertEditOpts(ctx, dbName)
+	if err != nil {
+		return 1, err
+	}
+	result, err := merge.Revert(ctx, ddb, workingSet.WorkingRoot(), commits, mainline, opts)
+	if err != nil {
+		return 1, err
+	}
+	return finishRevert(ctx, apr, dbName, workingSet.WorkingRoot(), nil, result, mainline)
+}
+
+// continueRevert continues the revert in progress once the conflicts of the commit it stopped at are resolved.
+func continueRevert(ctx *sql.Context, apr *argparser.ArgParseResults, ddb *doltdb.DoltDB, dbName string) (int, error) {
+	dSess := dsess.DSessFromSess(ctx.Session)
 	workingSet, err := dSess.WorkingSet(ctx, dbName)
 	if err != nil {
 		return 1, err
 	}
+	if !workingSet.MergeActive() || !workingSet.MergeState().IsRevert() {
+		return 1, ErrNoRevertInProgress
+	}
+
 	workingRoot := workingSet.WorkingRoot()
-	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if hasConflicts, err := doltdb.HasConflicts(ctx, workingRoot); err != nil {
+		return 1, err
+	} else if hasConflicts {
+		return 1, ErrRevertUnresolvedConflicts
+	}
+	if hasViolations, err := doltdb.HasConstraintViolations(ctx, workingRoot); err != nil {
+		return 1, err
+	} else if hasViolations {
+		return 1, ErrRevertUnresolvedConflicts
+	}
+	if workingSet.MergeState().HasSchemaConflicts() {
+		return 1, ErrRevertUnresolvedConflicts
+	}
+
+	mergeState := workingSet.MergeState()
+	reverted := append(append([]*doltdb.Commit{}, mergeState.RevertedCommits()...), mergeState.Commit())
+
+	opts, err := revertEditOpts(ctx, dbName)
 	if err != nil {
 		return 1, err
 	}
-	headRoot, err := headCommit.GetRootValue(ctx)
+	result, err := merge.Revert(ctx, ddb, workingRoot, mergeState.PendingReverts(), mergeState.RevertMainline(), opts)
 	if err != nil {
 		return 1, err
 	}
-	headHash, err := headRoot.HashOf()
+	return finishRevert(ctx, apr, dbName, mergeState.PreMergeWorkingRoot(), reverted, result, mergeState.RevertMainline())
+}
+
+// skipRevert continues the revert in progress without reverting the commit it stopped at.
+func skipRevert(ctx *sql.Context, apr *argparser.ArgParseResults, ddb *doltdb.DoltDB, dbName string) (int, error) {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	workingSet, err := dSess.WorkingSet(ctx, dbName)
 	if err != nil {
 		return 1, err
 	}
-	workingHash, err := workingRoot.HashOf()
+	if !workingSet.MergeActive() || !workingSet.MergeState().IsRevert() {
+		return 1, ErrNoRevertInProgress
+	}
+
+	// Start over from the working root before the revert, reverting the commits that were reverted cleanly before
+	// the skipped commit again, followed by the commits after it.
+	mergeState := workingSet.MergeState()
+	commits := append(append([]*doltdb.Commit{}, mergeState.RevertedCommits()...), mergeState.PendingReverts()...)
+
+	opts, err := revertEditOpts(ctx, dbName)
 	if err != nil {
 		return 1, err
 	}
+	result, err := merge.Revert(ctx, ddb, mergeState.PreMergeWorkingRoot(), commits, mergeState.RevertMainline(), opts)
+	if err != nil {
+		return 1, err
+	}
+	return finishRevert(ctx, apr, dbName, mergeState.PreMergeWorkingRoot(), nil, result, mergeState.RevertMainline())
+}
 
-	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+// abortRevert undoes the revert in progress, restoring the working set from before the revert started.
+func abortRevert(ctx *sql.Context, dbName string) (int, error) {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	workingSet, err := dSess.WorkingSet(ctx, dbName)
 	if err != nil {
 		return 1, err
 	}
+	if !workingSet.MergeActive() || !workingSet.MergeState().IsRevert() {
+		return 1, ErrNoRevertInProgress
+	}
 
-	apr, err := cli.CreateRevertArgParser().Parse(args)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return 1, fmt.Errorf("Could not load session roots")
+	}
+	newWorkingSet, err := merge.AbortMerge(ctx, workingSet, roots)
 	if err != nil {
 		return 1, err
 	}
+	return 0, dSess.SetWorkingSet(ctx, dbName, newWorkingSet)
+}
 
-	commits := make([]*doltdb.Commit, apr.NArg())
-	for i, revisionStr := range apr.Args {
-		commitSpec, err := doltdb.NewCommitSpec(revisionStr)
-		if err != nil {
+// finishRevert records the outcome of reverting commits with merge.Revert. If the revert stopped with conflicts, the
+// working set records the revert in progress. Otherwise, a commit is created for |reverted| and the commits reverted
+// by |result|, unless that leaves nothing to commit. |preRevertWorking| is the working root before the revert started.
+func finishRevert(ctx *sql.Context, apr *argparser.ArgParseResults, dbName string, preRevertWorking doltdb.RootValue, reverted []*doltdb.Commit, result *merge.RevertResult, mainline int) (int, error) {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	reverted = append(reverted, result.Reverted...)
+
+	if result.Conflicted != nil {
+		if err := dSess.SetWorkingRoot(ctx, dbName, result.Root); err != nil {
 			return 1, err
 		}
-		optCmt, err := ddb.Resolve(ctx, commitSpec, headRef)
+		workingSet, err := dSess.WorkingSet(ctx, dbName)
 		if err != nil {
 			return 1, err
 		}
-		commit, ok := optCmt.ToCommit()
-		if !ok {
-			return 1, doltdb.ErrGhostCommitEncountered
+		h, err := result.Conflicted.HashOf()
+		if err != nil {
+			return 1, err
 		}
-
-		commits[i] = commit
+		workingSet = workingSet.StartRevert(result.Conflicted, h.String(), preRevertWorking, reverted, result.Pending, mainline)
+		return 1, dSess.SetWorkingSet(ctx, dbName, workingSet)
 	}
 
-	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
 	if err != nil {
 		return 1, err
-	} else if !ok {
-		return 1, fmt.Errorf("Could not load database %s", dbName)
 	}
-
-	workingRoot, revertMessage, err := merge.Revert(ctx, ddb, workingRoot, commits, dbState.EditOpts())
+	headRoot, err := headCommit.GetRootValue(ctx)
 	if err != nil {
 		return 1, err
 	}
-	workingHash, err = workingRoot.HashOf()
+	headHash, err := headRoot.HashOf()
 	if err != nil {
 		return 1, err
 	}
-	if !headHash.Equal(workingHash) {
-		err = dSess.SetWorkingRoot(ctx, dbName, workingRoot)
+	workingHash, err := result.Root.HashOf()
+	if err != nil {
+		return 1, err
+	}
+
+	if headHash.Equal(workingHash) {
+		// Nothing is left to commit, but a revert in progress still needs to be cleared
+		workingSet, err := dSess.WorkingSet(ctx, dbName)
 		if err != nil {
 			return 1, err
 		}
-		stringType := typeinfo.StringDefaultType.ToSqlType()
+		if workingSet.MergeActive() {
+			workingSet = workingSet.WithWorkingRoot(result.Root).WithStagedRoot(headRoot).ClearMerge()
+			if err = dSess.SetWorkingSet(ctx, dbName, workingSet); err != nil {
+				return 1, err
+			}
+		}
+		return 0, nil
+	}
 
-		expressions := []sql.Expression{expression.NewLiteral("-a", stringType), expression.NewLiteral("-m", stringType), expression.NewLiteral(revertMessage, stringType)}
+	if err = dSess.SetWorkingRoot(ctx, dbName, result.Root); err != nil {
+		return 1, err
+	}
+	revertMessage, err := merge.RevertMessage(ctx, reverted)
+	if err != nil {
+		return 1, err
+	}
+	stringType := typeinfo.StringDefaultType.ToSqlType()
 
-		author, hasAuthor := apr.GetValue(cli.AuthorParam)
-		if hasAuthor {
-			expressions = append(expressions, expression.NewLiteral("--author", stringType), expression.NewLiteral(author, stringType))
-		}
+	expressions := []sql.Expression{expression.NewLiteral("-a", stringType), expression.NewLiteral("-m", stringType), expression.NewLiteral(revertMessage, stringType)}
 
-		commitArgs, err := getDoltArgs(ctx, nil, expressions)
-		if err != nil {
-			return 1, err
-		}
-		_, _, err = doDoltCommit(ctx, commitArgs)
-		if err != nil {
-			return 1, err
-		}
+	author, hasAuthor := apr.GetValue(cli.AuthorParam)
+	if hasAuthor {
+		expressions = append(expressions, expression.NewLiteral("--author", stringType), expression.NewLiteral(author, stringType))
+	}
+
+	commitArgs, err := getDoltArgs(ctx, nil, expressions)
+	if err != nil {
+		return 1, err
+	}
+	_, _, err = doDoltCommit(ctx, commitArgs)
+	if err != nil {
+		return 1, err
 	}
 	return 0, nil
 }
+
+func revertEditOpts(ctx *sql.Context, dbName string) (editor.Options, error) {
+	dbState, ok, err := dsess.DSessFromSess(ctx.Session).LookupDbState(ctx, dbName)
+	if err != nil {
+		return editor.Options{}, err
+	} else if !ok {
+		return editor.Options{}, fmt.Errorf("Could not load database %s", dbName)
+	}
+	return dbState.EditOpts(), nil
+}
@@ -61,6 +61,9 @@ func doDoltTag(ctx *sql.Context, args []string) (int, error) {
 		if apr.Contains(cli.MessageArg) {
 			return 1, fmt.Errorf("delete and tag message options are incompatible")
 		}
+		if apr.Contains(cli.SignTagFlag) || apr.Contains(cli.LocalUserParam) {
+			return 1, fmt.Errorf("delete and sign options are incompatible")
+		}
 		err = actions.DeleteTagsOnDB(ctx, dbData.Ddb, apr.Args...)
 		if err != nil {
 			return 1, err
@@ -92,6 +95,14 @@ func doDoltTag(ctx *sql.Context, args []string) (int, error) {
 		Description: msg,
 	}
 
+	if apr.Contains(cli.SignTagFlag) || apr.Contains(cli.LocalUserParam) {
+		cfg, err := dsess.GetSigningConfig(ctx, apr.GetValueOrDefault(cli.LocalUserParam, ""))
+		if err != nil {
+			return 1, err
+		}
+		props.Signing = &cfg
+	}
+
 	tagName := apr.Arg(0)
 	startPoint := "head"
 	if len(apr.Args) > 1 {
@@ -0,0 +1,130 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/signing"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var verifyCommitSchema = stringSchema("hash", "signature")
+var verifyTagSchema = stringSchema("tag_name", "signature")
+
+// doltVerifyCommit is the stored procedure version for the CLI command `dolt verify-commit`. It returns the result of
+// verifying the signature of each of the commits named by its arguments, and fails if any of them is not signed by a
+// trusted key.
+func doltVerifyCommit(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	revs, ddb, headRef, cfg, err := verifySignatureArgs(ctx, cli.CreateVerifyCommitArgParser(), args)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []sql.Row
+	for _, rev := range revs {
+		cs, err := doltdb.NewCommitSpec(rev)
+		if err != nil {
+			return nil, err
+		}
+		optCmt, err := ddb.Resolve(ctx, cs, headRef)
+		if err != nil {
+			return nil, err
+		}
+		commit, ok := optCmt.ToCommit()
+		if !ok {
+			return nil, doltdb.ErrGhostCommitEncountered
+		}
+
+		h, err := commit.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		out, err := signing.VerifyCommit(ctx, cfg, ctx.GetCurrentDatabase(), commit.Value(), commit.DatasParents())
+		if err != nil {
+			return nil, fmt.Errorf("commit %s: %w", h.String(), err)
+		}
+		rows = append(rows, sql.Row{h.String(), out})
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// doltVerifyTag is the stored procedure version for the CLI command `dolt verify-tag`. It returns the result of
+// verifying the signature of each of the tags named by its arguments, and fails if any of them is not signed by a
+// trusted key.
+func doltVerifyTag(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	tagNames, ddb, _, cfg, err := verifySignatureArgs(ctx, cli.CreateVerifyTagArgParser(), args)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []sql.Row
+	for _, tagName := range tagNames {
+		tag, err := ddb.ResolveTag(ctx, ref.NewTagRef(tagName))
+		if err != nil {
+			return nil, fmt.Errorf("tag %s: %w", tagName, err)
+		}
+		commitHash, err := tag.Commit.HashOf()
+		if err != nil {
+			return nil, err
+		}
+
+		out, err := signing.VerifyTag(ctx, cfg, tag.Name, commitHash, tag.Meta)
+		if err != nil {
+			return nil, fmt.Errorf("tag %s: %w", tagName, err)
+		}
+		rows = append(rows, sql.Row{tag.Name, out})
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+func verifySignatureArgs(ctx *sql.Context, ap *argparser.ArgParser, args []string) ([]string, *doltdb.DoltDB, ref.DoltRef, signing.Config, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return nil, nil, nil, signing.Config{}, fmt.Errorf("empty database name")
+	}
+
+	apr, err := ap.Parse(args)
+	if err != nil {
+		return nil, nil, nil, signing.Config{}, err
+	}
+	if len(apr.Args) == 0 {
+		return nil, nil, nil, signing.Config{}, fmt.Errorf("%s requires at least one argument", ap.Name)
+	}
+
+	dbData, ok := dsess.DSessFromSess(ctx.Session).GetDbData(ctx, dbName)
+	if !ok {
+		return nil, nil, nil, signing.Config{}, fmt.Errorf("could not load database %s", dbName)
+	}
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return nil, nil, nil, signing.Config{}, err
+	}
+
+	cfg, err := dsess.GetSigningConfig(ctx, "")
+	if err != nil {
+		return nil, nil, nil, signing.Config{}, err
+	}
+
+	return apr.Args, dbData.Ddb, headRef, cfg, nil
+}
@@ -23,18 +23,26 @@ var DoltProcedures = []sql.ExternalStoredProcedureDetails{
 	{Name: "dolt_add", Schema: int64Schema("status"), Function: doltAdd},
 	{Name: "dolt_backup", Schema: int64Schema("status"), Function: doltBackup, ReadOnly: true, AdminOnly: true},
 	{Name: "dolt_branch", Schema: int64Schema("status"), Function: doltBranch},
+	{Name: "dolt_cdc", Schema: cdcSchema, Function: doltCdc, ReadOnly: true},
 	{Name: "dolt_checkout", Schema: doltCheckoutSchema, Function: doltCheckout, ReadOnly: true},
 	{Name: "dolt_cherry_pick", Schema: cherryPickSchema, Function: doltCherryPick},
 	{Name: "dolt_clean", Schema: int64Schema("status"), Function: doltClean},
 	{Name: "dolt_clone", Schema: int64Schema("status"), Function: doltClone, AdminOnly: true},
 	{Name: "dolt_commit", Schema: stringSchema("hash"), Function: doltCommit},
 	{Name: "dolt_commit_hash_out", Schema: stringSchema("hash"), Function: doltCommitHashOut},
+	{Name: "dolt_create_materialized_view", Schema: int64Schema("status"), Function: doltCreateMaterializedView},
+	{Name: "dolt_create_policy", Schema: int64Schema("status"), Function: doltCreatePolicy},
+	{Name: "dolt_create_column_mask", Schema: int64Schema("status"), Function: doltCreateColumnMask},
 	{Name: "dolt_conflicts_resolve", Schema: int64Schema("status"), Function: doltConflictsResolve},
 	{Name: "dolt_count_commits", Schema: int64Schema("ahead", "behind"), Function: doltCountCommits, ReadOnly: true},
 	{Name: "dolt_fetch", Schema: int64Schema("status"), Function: doltFetch, AdminOnly: true},
 	{Name: "dolt_undrop", Schema: int64Schema("status"), Function: doltUndrop, AdminOnly: true},
+	{Name: "dolt_drop_materialized_view", Schema: int64Schema("status"), Function: doltDropMaterializedView},
+	{Name: "dolt_drop_policy", Schema: int64Schema("status"), Function: doltDropPolicy},
+	{Name: "dolt_drop_column_mask", Schema: int64Schema("status"), Function: doltDropColumnMask},
 	{Name: "dolt_purge_dropped_databases", Schema: int64Schema("status"), Function: doltPurgeDroppedDatabases, AdminOnly: true},
 	{Name: "dolt_rebase", Schema: doltRebaseProcedureSchema, Function: doltRebase},
+	{Name: "dolt_reset_query_digests", Schema: int64Schema("status"), Function: doltResetQueryDigests, ReadOnly: true, AdminOnly: true},
 
 	// dolt_gc is enabled behind a feature flag for now, see dolt_gc.go
 	{Name: "dolt_gc", Schema: int64Schema("status"), Function: doltGC, ReadOnly: true, AdminOnly: true},
@@ -42,11 +50,14 @@ var DoltProcedures = []sql.ExternalStoredProcedureDetails{
 	{Name: "dolt_merge", Schema: doltMergeSchema, Function: doltMerge},
 	{Name: "dolt_pull", Schema: doltPullSchema, Function: doltPull, AdminOnly: true},
 	{Name: "dolt_push", Schema: doltPushSchema, Function: doltPush, AdminOnly: true},
+	{Name: "dolt_refresh_materialized_view", Schema: refreshMaterializedViewSchema, Function: doltRefreshMaterializedView},
 	{Name: "dolt_remote", Schema: int64Schema("status"), Function: doltRemote, AdminOnly: true},
 	{Name: "dolt_reset", Schema: int64Schema("status"), Function: doltReset},
 	{Name: "dolt_revert", Schema: int64Schema("status"), Function: doltRevert},
 	{Name: "dolt_tag", Schema: int64Schema("status"), Function: doltTag},
+	{Name: "dolt_verify_commit", Schema: verifyCommitSchema, Function: doltVerifyCommit, ReadOnly: true},
 	{Name: "dolt_verify_constraints", Schema: int64Schema("violations"), Function: doltVerifyConstraints},
+	{Name: "dolt_verify_tag", Schema: verifyTagSchema, Function: doltVerifyTag, ReadOnly: true},
 
 	{Name: "dolt_stats_drop", Schema: statsFuncSchema, Function: statsFunc(statsDrop)},
 	{Name: "dolt_stats_restart", Schema: statsFuncSchema, Function: statsFunc(statsRestart)},
@@ -57,3 +57,19 @@ func CheckAccessForDb(ctx context.Context, db SqlDatabase, flags branch_control.
 	}
 	return branch_control.ErrIncorrectPermissions.New(user, host, branch)
 }
+
+// CheckAdminAccessForDb returns an error unless the current user is an admin of the branch of |db|, or has the
+// database privileges that allow them to administer every branch of |db|, as when editing dolt_branch_control.
+func CheckAdminAccessForDb(ctx context.Context, db SqlDatabase) error {
+	err := CheckAccessForDb(ctx, db, branch_control.Permissions_Admin)
+	if err == nil {
+		return nil
+	}
+	if branchAwareSession := branch_control.GetBranchAwareSession(ctx); branchAwareSession != nil {
+		dbName, _ := SplitRevisionDbName(db.RevisionQualifiedName())
+		if branch_control.HasDatabasePrivileges(branchAwareSession, dbName) {
+			return nil
+		}
+	}
+	return err
+}
@@ -0,0 +1,337 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+)
+
+// The resources that ResourceLimits limit, as they are named in errors and in the counts of violations.
+const (
+	LimitMaxExecutionTime     = "max_execution_time"
+	LimitMaxConcurrentQueries = "max_concurrent_queries"
+	LimitMaxRowsReturned      = "max_rows_returned"
+	LimitMaxMemory            = "max_memory"
+)
+
+// ResourceLimitNames are the names of every resource that ResourceLimits limit.
+var ResourceLimitNames = []string{LimitMaxExecutionTime, LimitMaxConcurrentQueries, LimitMaxRowsReturned, LimitMaxMemory}
+
+// ResourceLimits limit the resources that the queries of a session may use. Zero values are unlimited.
+type ResourceLimits struct {
+	// MaxExecutionTime is the time a query may run for
+	MaxExecutionTime time.Duration
+	// MaxConcurrentQueries is the number of queries that the sessions of a user may run at the same time
+	MaxConcurrentQueries int
+	// MaxRowsReturned is the number of rows a query may return
+	MaxRowsReturned uint64
+	// MaxMemoryBytes is the memory a query may use. Memory isn't accounted per query, so it's approximated by the bytes
+	// of the table data that the query reads, which bounds the rows that it can buffer.
+	MaxMemoryBytes uint64
+}
+
+// IsZero returns whether |l| doesn't limit anything.
+func (l ResourceLimits) IsZero() bool {
+	return l == ResourceLimits{}
+}
+
+// Restrict returns the limits that are the most restrictive of |l| and |other|.
+func (l ResourceLimits) Restrict(other ResourceLimits) ResourceLimits {
+	return ResourceLimits{
+		MaxExecutionTime:     minLimit(l.MaxExecutionTime, other.MaxExecutionTime),
+		MaxConcurrentQueries: minLimit(l.MaxConcurrentQueries, other.MaxConcurrentQueries),
+		MaxRowsReturned:      minLimit(l.MaxRowsReturned, other.MaxRowsReturned),
+		MaxMemoryBytes:       minLimit(l.MaxMemoryBytes, other.MaxMemoryBytes),
+	}
+}
+
+// minLimit returns the lower of |a| and |b|, where zero is unlimited.
+func minLimit[T time.Duration | int | uint64](a, b T) T {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// ErrMaxExecutionTime is returned by queries that run for longer than their session's limit.
+var ErrMaxExecutionTime = mysql.NewSQLError(mysql.ERQueryTimeout, mysql.SSUnknownSQLState,
+	"Query execution was interrupted, maximum statement execution time exceeded")
+
+// ErrResourceLimit returns the error of a query of |user| that exceeds the limit of |resource|, whose value is |limit|.
+func ErrResourceLimit(user, resource string, limit uint64) error {
+	return mysql.NewSQLError(mysql.ERUserLimitReached, "42000",
+		"User '%s' has exceeded the '%s' resource (current value: %d)", user, resource, limit)
+}
+
+var resourceLimitViolations = func() map[string]*atomic.Uint64 {
+	m := make(map[string]*atomic.Uint64)
+	for _, name := range ResourceLimitNames {
+		m[name] = new(atomic.Uint64)
+	}
+	return m
+}()
+
+// ResourceLimitViolations returns the number of queries that have been stopped for exceeding the limit of |resource|.
+func ResourceLimitViolations(resource string) uint64 {
+	if n, ok := resourceLimitViolations[resource]; ok {
+		return n.Load()
+	}
+	return 0
+}
+
+// SetResourceLimits sets the limits of the resources that the queries of this session may use. They're enforced by
+// a ResourceLimitingProcessList.
+func (d *DoltSession) SetResourceLimits(limits ResourceLimits) {
+	d.resourceLimits = limits
+}
+
+// ResourceLimits returns the limits of the resources that the queries of this session may use.
+func (d *DoltSession) ResourceLimits() ResourceLimits {
+	return d.resourceLimits
+}
+
+// TakeResourceLimitError returns the error of the limit that the last query of |sess| exceeded, if it was stopped for
+// exceeding one, and forgets it. Queries that are stopped often fail with a cancellation error rather than the error of
+// the limit, which this can replace.
+func TakeResourceLimitError(sess sql.Session) error {
+	dSess, ok := sess.(*DoltSession)
+	if !ok {
+		return nil
+	}
+	err := dSess.resourceLimitErr
+	dSess.resourceLimitErr = nil
+	return err
+}
+
+// AddRowsReturned counts |n| more rows returned by the current query of |sess|, and returns an error, stopping the
+// query, if it has returned more rows than its session's limit, or if it has already been stopped for exceeding another
+// limit. Rows are sent to the client after the query has finished reading them, so they're counted by the caller
+// sending them.
+func AddRowsReturned(sess sql.Session, n int) error {
+	dSess, ok := sess.(*DoltSession)
+	if !ok {
+		return nil
+	}
+	if err := dSess.queryLimitErr(); err != nil {
+		return err
+	}
+	limit := dSess.resourceLimits.MaxRowsReturned
+	if limit == 0 {
+		return nil
+	}
+	dSess.rowsReturned += uint64(n)
+	if dSess.rowsReturned <= limit {
+		return nil
+	}
+	resourceLimitViolations[LimitMaxRowsReturned].Add(1)
+	err := ErrResourceLimit(dSess.Client().User, LimitMaxRowsReturned, limit)
+	if q := dSess.runningQuery.Load(); q != nil {
+		q.ctx.stop(err, "")
+	}
+	return err
+}
+
+// queryLimitErr returns the error of the limit that the current query of this session was stopped for exceeding, if
+// it was stopped for exceeding one. Queries don't always notice that they were stopped before they finish.
+func (d *DoltSession) queryLimitErr() error {
+	if q := d.runningQuery.Load(); q != nil {
+		return q.ctx.limitErr()
+	}
+	return d.resourceLimitErr
+}
+
+// ResourceLimitingProcessList is a sql.ProcessList that enforces the ResourceLimits of the sessions whose queries it
+// tracks. Queries that exceed their limits are stopped with an error.
+type ResourceLimitingProcessList struct {
+	sql.ProcessList
+
+	mu sync.Mutex
+	// running is the number of queries being run by each user with a limit on concurrent queries
+	running map[string]int
+}
+
+var _ sql.ProcessList = (*ResourceLimitingProcessList)(nil)
+
+// NewResourceLimitingProcessList returns a ResourceLimitingProcessList that tracks queries with |pl|.
+func NewResourceLimitingProcessList(pl sql.ProcessList) *ResourceLimitingProcessList {
+	return &ResourceLimitingProcessList{ProcessList: pl, running: make(map[string]int)}
+}
+
+// limitedQuery is a query that is being run by a session with resource limits.
+type limitedQuery struct {
+	user   string
+	limits ResourceLimits
+	ctx    *limitedContext
+}
+
+// BeginQuery implements sql.ProcessList.
+func (pl *ResourceLimitingProcessList) BeginQuery(ctx *sql.Context, query string) (*sql.Context, error) {
+	dSess, ok := ctx.Session.(*DoltSession)
+	if !ok || dSess.resourceLimits.IsZero() {
+		return pl.ProcessList.BeginQuery(ctx, query)
+	}
+
+	dSess.rowsReturned = 0
+	dSess.resourceLimitErr = nil
+	q := &limitedQuery{user: ctx.Session.Client().User, limits: dSess.resourceLimits}
+	if maxQueries := q.limits.MaxConcurrentQueries; maxQueries > 0 {
+		pl.mu.Lock()
+		if pl.running[q.user] >= maxQueries {
+			pl.mu.Unlock()
+			resourceLimitViolations[LimitMaxConcurrentQueries].Add(1)
+			return nil, ErrResourceLimit(q.user, LimitMaxConcurrentQueries, uint64(maxQueries))
+		}
+		pl.running[q.user]++
+		pl.mu.Unlock()
+	}
+
+	q.ctx = newLimitedContext(ctx.Context, q.user, q.limits)
+	newCtx, err := pl.ProcessList.BeginQuery(ctx.WithContext(q.ctx), query)
+	if err != nil {
+		pl.endQuery(q)
+		return nil, err
+	}
+	dSess.runningQuery.Store(q)
+	return newCtx, nil
+}
+
+// EndQuery implements sql.ProcessList. It's called when a query has finished reading its rows, and again when they've
+// been sent to the client.
+func (pl *ResourceLimitingProcessList) EndQuery(ctx *sql.Context) {
+	pl.ProcessList.EndQuery(ctx)
+	if dSess, ok := ctx.Session.(*DoltSession); ok {
+		if q := dSess.runningQuery.Swap(nil); q != nil {
+			dSess.resourceLimitErr = q.ctx.limitErr()
+			pl.endQuery(q)
+		}
+	}
+}
+
+func (pl *ResourceLimitingProcessList) endQuery(q *limitedQuery) {
+	q.ctx.stop(nil, "")
+	if q.limits.MaxConcurrentQueries > 0 {
+		pl.mu.Lock()
+		defer pl.mu.Unlock()
+		if pl.running[q.user]--; pl.running[q.user] <= 0 {
+			delete(pl.running, q.user)
+		}
+	}
+}
+
+// limitedContext is a context.Context that's canceled when its query exceeds its time or memory limit. Unlike the
+// contexts of the context package, its Err() is the error of the limit that was exceeded, which the contexts derived
+// from it inherit, so that the query fails with that error.
+type limitedContext struct {
+	context.Context
+	done chan struct{}
+
+	user      string
+	maxMemory uint64
+	// bytesRead is the bytes of table data that the query has read, which approximates the memory it uses
+	bytesRead atomic.Uint64
+
+	mu  sync.Mutex
+	err error
+	// exceeded is the resource whose limit was exceeded, if any
+	exceeded string
+}
+
+var _ tree.ReadTracker = (*limitedContext)(nil)
+
+func newLimitedContext(parent context.Context, user string, limits ResourceLimits) *limitedContext {
+	c := &limitedContext{Context: parent, done: make(chan struct{}), user: user, maxMemory: limits.MaxMemoryBytes}
+	if c.maxMemory > 0 {
+		c.Context = tree.WithReadTracker(parent, c)
+	}
+
+	var timer *time.Timer
+	var timeout <-chan time.Time
+	if limits.MaxExecutionTime > 0 {
+		timer = time.NewTimer(limits.MaxExecutionTime)
+		timeout = timer.C
+	}
+
+	go func() {
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+		select {
+		case <-c.done:
+		case <-parent.Done():
+			c.stop(parent.Err(), "")
+		case <-timeout:
+			c.stop(ErrMaxExecutionTime, LimitMaxExecutionTime)
+		}
+	}()
+	return c
+}
+
+// stop cancels |c| with |err|, counting a violation of the limit of |resource| if it's set. A nil |err| releases
+// the resources of |c| once its query is done.
+func (c *limitedContext) stop(err error, resource string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+	if err == nil {
+		err = context.Canceled
+	} else if resource != "" {
+		resourceLimitViolations[resource].Add(1)
+		c.exceeded = resource
+	}
+	c.err = err
+	close(c.done)
+}
+
+// NodesRead implements tree.ReadTracker. It stops the query once it has read more table data than its memory limit.
+func (c *limitedContext) NodesRead(bytes uint64) {
+	total := c.bytesRead.Add(bytes)
+	if total > c.maxMemory && total-bytes <= c.maxMemory {
+		c.stop(ErrResourceLimit(c.user, LimitMaxMemory, c.maxMemory), LimitMaxMemory)
+	}
+}
+
+func (c *limitedContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *limitedContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// limitErr returns the error of the limit that |c| was stopped for exceeding, or nil if it wasn't.
+func (c *limitedContext) limitErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.exceeded == "" {
+		return nil
+	}
+	return c.err
+}
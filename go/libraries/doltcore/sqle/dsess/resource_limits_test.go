@@ -0,0 +1,89 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceLimitsRestrict(t *testing.T) {
+	a := ResourceLimits{MaxExecutionTime: time.Second, MaxRowsReturned: 100}
+	b := ResourceLimits{MaxExecutionTime: time.Minute, MaxConcurrentQueries: 2, MaxRowsReturned: 10}
+	expected := ResourceLimits{MaxExecutionTime: time.Second, MaxConcurrentQueries: 2, MaxRowsReturned: 10}
+	assert.Equal(t, expected, a.Restrict(b))
+	assert.Equal(t, expected, b.Restrict(a))
+	assert.Equal(t, a, a.Restrict(ResourceLimits{}))
+	assert.True(t, ResourceLimits{}.IsZero())
+	assert.False(t, a.IsZero())
+}
+
+func TestLimitedContext(t *testing.T) {
+	t.Run("max execution time", func(t *testing.T) {
+		violations := ResourceLimitViolations(LimitMaxExecutionTime)
+		c := newLimitedContext(context.Background(), "alice", ResourceLimits{MaxExecutionTime: 10 * time.Millisecond})
+		// contexts derived from a limitedContext fail with the error of the limit
+		child, cancel := context.WithCancel(c)
+		defer cancel()
+		select {
+		case <-child.Done():
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "query was not stopped")
+		}
+		assert.Equal(t, ErrMaxExecutionTime, child.Err())
+		assert.Equal(t, ErrMaxExecutionTime, c.limitErr())
+		assert.Equal(t, violations+1, ResourceLimitViolations(LimitMaxExecutionTime))
+	})
+
+	t.Run("max memory", func(t *testing.T) {
+		violations := ResourceLimitViolations(LimitMaxMemory)
+		c := newLimitedContext(context.Background(), "alice", ResourceLimits{MaxMemoryBytes: 1 << 20})
+		c.NodesRead(1 << 10)
+		require.NoError(t, c.Err())
+
+		c.NodesRead(1 << 20)
+		select {
+		case <-c.Done():
+		default:
+			require.Fail(t, "query was not stopped")
+		}
+		assert.Contains(t, c.Err().Error(), "User 'alice' has exceeded the 'max_memory' resource")
+		c.NodesRead(1 << 10)
+		assert.Equal(t, violations+1, ResourceLimitViolations(LimitMaxMemory))
+	})
+
+	t.Run("finished queries", func(t *testing.T) {
+		violations := ResourceLimitViolations(LimitMaxExecutionTime)
+		c := newLimitedContext(context.Background(), "alice", ResourceLimits{MaxExecutionTime: 10 * time.Millisecond})
+		c.stop(nil, "")
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, context.Canceled, c.Err())
+		assert.NoError(t, c.limitErr())
+		assert.Equal(t, violations, ResourceLimitViolations(LimitMaxExecutionTime))
+	})
+
+	t.Run("canceled queries", func(t *testing.T) {
+		parent, cancel := context.WithCancel(context.Background())
+		c := newLimitedContext(parent, "alice", ResourceLimits{MaxExecutionTime: time.Minute})
+		cancel()
+		<-c.Done()
+		assert.Equal(t, context.Canceled, c.Err())
+		assert.NoError(t, c.limitErr())
+	})
+}
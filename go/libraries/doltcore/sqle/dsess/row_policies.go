@@ -0,0 +1,166 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// PoliciesRoot returns the root value that the row-level security policies of the database |baseName| are read from:
+// the working root of its default branch, as it was when the current transaction began. Policies are stored in the
+// versioned dolt_policies table, but are read from a single branch rather than from the revision being queried, so
+// that they also apply to branches and commits made before they were created, and so that writes to any other branch
+// can't weaken them. |ddb| is the DoltDB of the database. Returns nil if the default branch has no working set.
+func PoliciesRoot(ctx *sql.Context, baseName string, ddb *doltdb.DoltDB) (doltdb.RootValue, error) {
+	if ddb == nil {
+		return nil, nil
+	}
+	wsRef, err := defaultWorkingSetRef(ctx, baseName)
+	if err != nil {
+		return nil, err
+	}
+
+	var nomsRoot hash.Hash
+	ok := false
+	if tx, isDoltTx := ctx.GetTransaction().(*DoltTransaction); isDoltTx {
+		nomsRoot, ok = tx.GetInitialRoot(baseName)
+	}
+	if !ok {
+		if nomsRoot, err = ddb.NomsRoot(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	ws, err := ddb.ResolveWorkingSetAtRoot(ctx, wsRef, nomsRoot)
+	if err == doltdb.ErrWorkingSetNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return ws.WorkingRoot(), nil
+}
+
+// defaultWorkingSetRef returns the ref of the working set of the default branch of the database |baseName|.
+func defaultWorkingSetRef(ctx *sql.Context, baseName string) (ref.WorkingSetRef, error) {
+	db, ok := DSessFromSess(ctx.Session).Provider().BaseDatabase(ctx, baseName)
+	if !ok {
+		return ref.WorkingSetRef{}, sql.ErrDatabaseNotFound.New(baseName)
+	}
+	head, err := DefaultHead(baseName, db)
+	if err != nil {
+		return ref.WorkingSetRef{}, err
+	}
+	return ref.WorkingSetRefForHead(ref.NewBranchRef(head))
+}
+
+// IsDefaultBranch returns whether |branch| is the default branch of the database |dbName|, whose working set stores
+// the row-level security policies of the database.
+func IsDefaultBranch(ctx *sql.Context, dbName, branch string) (bool, error) {
+	dbName, _ = SplitRevisionDbName(dbName)
+	wsRef, err := defaultWorkingSetRef(ctx, dbName)
+	if err != nil {
+		return false, err
+	}
+	return wsRef.GetPath() == path.Join(string(ref.BranchRefType), branch), nil
+}
+
+// CheckReplacePolicies returns an error if replacing the working set of the default branch of |dbName| with |root|, or
+// deleting the default branch if |root| is nil, would change the row-level security policies of the database and the
+// current user may not change them. It guards the branch operations that replace or delete a branch without a
+// transaction commit. |ddb| is the DoltDB of the database.
+func CheckReplacePolicies(ctx *sql.Context, dbName string, ddb *doltdb.DoltDB, root doltdb.RootValue) error {
+	dbName, _ = SplitRevisionDbName(dbName)
+	current, err := PoliciesRoot(ctx, dbName, ddb)
+	if err != nil {
+		return err
+	}
+	before, err := policiesHash(ctx, current)
+	if err != nil {
+		return err
+	}
+	after, err := policiesHash(ctx, root)
+	if err != nil || before == after {
+		return err
+	}
+	return CheckPolicyAdminAccess(ctx, dbName)
+}
+
+// policiesHash returns the hash of the dolt_policies table of |root|, or an empty hash if |root| is nil or has no
+// policies.
+func policiesHash(ctx *sql.Context, root doltdb.RootValue) (hash.Hash, error) {
+	if root == nil {
+		return hash.Hash{}, nil
+	}
+	h, _, err := root.GetTableHash(ctx, doltdb.TableName{Name: doltdb.PoliciesTableName})
+	return h, err
+}
+
+// CheckPolicyAdminAccess returns an error unless the current user may change the row-level security policies of the
+// database |dbName|. Policies apply to every branch, so only admins of every branch, and users with the database
+// privileges that allow them to administer every branch, may change them.
+func CheckPolicyAdminAccess(ctx context.Context, dbName string) error {
+	bas := branch_control.GetBranchAwareSession(ctx)
+	if bas == nil {
+		return nil
+	}
+	dbName, _ = SplitRevisionDbName(dbName)
+	if branch_control.HasDatabasePrivileges(bas, dbName) {
+		return nil
+	}
+	controller := bas.GetController()
+	if controller == nil {
+		return branch_control.ErrMissingController.New()
+	}
+
+	controller.Access.RWMutex.RLock()
+	defer controller.Access.RWMutex.RUnlock()
+	// Matching the pattern itself as though it were a branch name finds the rows that match every branch
+	_, perms := controller.Access.Match(strings.ToLower(dbName), "%", bas.GetUser(), bas.GetHost())
+	if perms&branch_control.Permissions_Admin != branch_control.Permissions_Admin {
+		return branch_control.ErrIncorrectPermissions.New(bas.GetUser(), bas.GetHost(), "%")
+	}
+	return nil
+}
+
+// checkPolicyChanges returns an error if committing |workingSet|, whose state at the start of the transaction was
+// |startState|, changes the row-level security policies of the database |dbName| and the current user may not change
+// them. This covers every statement that changes the working set of the default branch, such as merges and resets, not
+// only those that write to dolt_policies directly.
+func checkPolicyChanges(ctx *sql.Context, dbName string, startState, workingSet *doltdb.WorkingSet) error {
+	if wsRef, err := defaultWorkingSetRef(ctx, dbName); err != nil || wsRef != workingSet.Ref() {
+		return err
+	}
+	before, err := policiesHash(ctx, startState.WorkingRoot())
+	if err != nil {
+		return err
+	}
+	after, err := policiesHash(ctx, workingSet.WorkingRoot())
+	if err != nil {
+		return err
+	}
+	if before == after {
+		return nil
+	}
+	return CheckPolicyAdminAccess(ctx, dbName)
+}
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -67,6 +68,15 @@ type DoltSession struct {
 	// If non-nil, this will be returned from ValidateSession.
 	// Used by sqle/cluster to put a session into a terminal err state.
 	validateErr error
+
+	// resourceLimits limit the resources used by the queries of this session
+	resourceLimits ResourceLimits
+	// runningQuery is the query being run by this session, if it has resource limits
+	runningQuery *atomic.Pointer[limitedQuery]
+	// resourceLimitErr is the error of the limit exceeded by the last query of this session, if any
+	resourceLimitErr error
+	// rowsReturned is the number of rows returned by the current query of this session, if it has resource limits
+	rowsReturned uint64
 }
 
 var _ sql.Session = (*DoltSession)(nil)
@@ -89,6 +99,7 @@ func DefaultSession(pro DoltDatabaseProvider, sessFunc WriteSessFunc) *DoltSessi
 		mu:               &sync.Mutex{},
 		fs:               pro.FileSystem(),
 		writeSessProv:    sessFunc,
+		runningQuery:     &atomic.Pointer[limitedQuery]{},
 	}
 }
 
@@ -119,6 +130,7 @@ func NewDoltSession(
 		mu:               &sync.Mutex{},
 		fs:               pro.FileSystem(),
 		writeSessProv:    writeSessProv,
+		runningQuery:     &atomic.Pointer[limitedQuery]{},
 	}
 
 	return sess, nil
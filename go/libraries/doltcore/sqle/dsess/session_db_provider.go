@@ -70,6 +70,12 @@ type RemoteReadReplicaDatabase interface {
 	PullFromRemote(ctx *sql.Context) error
 }
 
+// StatementRunner runs SQL statements with the engine that serves a session, so that they are checked against the
+// privileges of the session's user like the statements the user runs directly.
+type StatementRunner interface {
+	Query(ctx *sql.Context, query string) (sql.Schema, sql.RowIter, *sql.QueryFlags, error)
+}
+
 type DoltDatabaseProvider interface {
 	sql.MutableDatabaseProvider
 	// FileSystem returns the filesystem used by this provider, rooted at the data directory for all databases.
@@ -111,6 +117,9 @@ type DoltDatabaseProvider interface {
 	// PurgeDroppedDatabases permanently deletes any dropped databases that are being held in temporary storage
 	// in case they need to be restored. This operation is not reversible, so use with caution!
 	PurgeDroppedDatabases(ctx *sql.Context) error
+	// StatementRunner returns the runner of the engine that serves this provider's databases, or nil if it hasn't been
+	// set.
+	StatementRunner() StatementRunner
 }
 
 type SessionDatabaseBranchSpec struct {
@@ -390,6 +390,9 @@ func (tx *DoltTransaction) doCommit(
 	if err != nil {
 		return nil, nil, err
 	}
+	if err = checkPolicyChanges(ctx, branchState.dbState.dbName, startState, workingSet); err != nil {
+		return nil, nil, err
+	}
 
 	// TODO: no-op if the working set hasn't changed since the transaction started
 
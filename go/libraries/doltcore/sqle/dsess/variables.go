@@ -20,6 +20,8 @@ import (
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/signing"
 )
 
 // Per-DB system variables
@@ -54,6 +56,7 @@ const (
 	ShowBranchDatabases                  = "dolt_show_branch_databases"
 	DoltLogLevel                         = "dolt_log_level"
 	ShowSystemTables                     = "dolt_show_system_tables"
+	MaskColumns                          = "dolt_mask_columns"
 
 	DoltClusterRoleVariable         = "dolt_cluster_role"
 	DoltClusterRoleEpochVariable    = "dolt_cluster_role_epoch"
@@ -65,6 +68,11 @@ const (
 	DoltStatsAutoRefreshInterval  = "dolt_stats_auto_refresh_interval"
 	DoltStatsMemoryOnly           = "dolt_stats_memory_only"
 	DoltStatsBranches             = "dolt_stats_branches"
+
+	SigningKey         = "signingkey"
+	GPGSign            = "gpgsign"
+	GPGFormat          = "gpgformat"
+	AllowedSignersFile = "allowedsignersfile"
 )
 
 const URLTemplateDatabasePlaceholder = "{database}"
@@ -187,6 +195,36 @@ func GetBooleanSystemVar(ctx *sql.Context, varName string) (bool, error) {
 	return i8 == int8(1), nil
 }
 
+// GetSigningConfig returns the configuration for signing and verifying commits and tags from the signing system
+// variables. A non-empty |keyId| overrides the signingkey variable.
+func GetSigningConfig(ctx *sql.Context, keyId string) (signing.Config, error) {
+	getString := func(varName string) (string, error) {
+		v, err := ctx.GetSessionVariable(ctx, varName)
+		if sql.ErrUnknownSystemVariable.Is(err) {
+			return "", nil
+		} else if err != nil {
+			return "", fmt.Errorf("failed to get %s: %w", varName, err)
+		}
+		s, _ := v.(string)
+		return s, nil
+	}
+
+	cfg := signing.Config{Key: keyId}
+	var err error
+	if cfg.Key == "" {
+		if cfg.Key, err = getString(SigningKey); err != nil {
+			return signing.Config{}, err
+		}
+	}
+	if cfg.Format, err = getString(GPGFormat); err != nil {
+		return signing.Config{}, err
+	}
+	if cfg.AllowedSignersFile, err = getString(AllowedSignersFile); err != nil {
+		return signing.Config{}, err
+	}
+	return cfg, nil
+}
+
 // IgnoreReplicationErrors returns true if the dolt_skip_replication_errors system variable is set to true, which means
 // that errors that occur during replication should be logged and ignored.
 func IgnoreReplicationErrors() bool {
@@ -28,9 +28,11 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/colmask"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/resolve"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/rowpolicy"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -179,6 +181,9 @@ func (dtf *DiffTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter,
 	if !ok {
 		return nil, fmt.Errorf("unable to get dolt database")
 	}
+	if err = checkRestrictedDelta(ctx, sqledb, dtf.tableDelta, dtf.Name()); err != nil {
+		return nil, err
+	}
 
 	fromCommitStr, toCommitStr, err := loadCommitStrings(ctx, fromCommitVal, toCommitVal, dotCommitVal, sqledb)
 	if err != nil {
@@ -191,6 +196,20 @@ func (dtf *DiffTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter,
 	return dtables.NewDiffPartitionRowIter(dp, ddb, dtf.joiner), nil
 }
 
+// checkRestrictedDelta returns an error if the current user may not read the row changes of |delta| with the table
+// function |source|, because its table has column masks or row-level security policies that apply to them.
+func checkRestrictedDelta(ctx *sql.Context, db dsess.SqlDatabase, delta diff.TableDelta, source string) error {
+	for _, tableName := range []string{delta.ToName.Name, delta.FromName.Name} {
+		if err := colmask.CheckSource(ctx, db, tableName, source); err != nil {
+			return err
+		}
+		if err := rowpolicy.CheckReadable(ctx, db, tableName, source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // findMatchingDelta returns the best matching table delta for the table name
 // given, taking renames into account
 // TODO: schema name
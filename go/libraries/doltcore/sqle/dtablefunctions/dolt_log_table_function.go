@@ -15,6 +15,7 @@
 package dtablefunctions
 
 import (
+	goerrors "errors"
 	"fmt"
 	"strings"
 
@@ -27,8 +28,8 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/signing"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
-	"github.com/dolthub/dolt/go/libraries/utils/gpg"
 	"github.com/dolthub/dolt/go/store/hash"
 )
 
@@ -780,12 +781,12 @@ func (itr *logTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
 
 	if itr.showSignature {
 		if len(meta.Signature) > 0 {
-			out, err := gpg.Verify(ctx, []byte(meta.Signature))
+			out, err := verifyCommitSignature(ctx, commit)
 			if err != nil {
 				return nil, err
 			}
 
-			row = row.Append(sql.NewRow(string(out)))
+			row = row.Append(sql.NewRow(out))
 		} else {
 			row = row.Append(sql.NewRow(""))
 		}
@@ -794,6 +795,21 @@ func (itr *logTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
 	return row, nil
 }
 
+// verifyCommitSignature returns the result of verifying the signature of |commit|. Signatures which fail to verify are
+// described in the result rather than failing the query, like git log --show-signature.
+func verifyCommitSignature(ctx *sql.Context, commit *doltdb.Commit) (string, error) {
+	cfg, err := dsess.GetSigningConfig(ctx, "")
+	if err != nil {
+		return "", err
+	}
+
+	out, err := signing.VerifyCommit(ctx, cfg, ctx.GetCurrentDatabase(), commit.Value(), commit.DatasParents())
+	if goerrors.Is(err, signing.ErrBadSignature) || goerrors.Is(err, signing.ErrUntrustedSigner) {
+		return err.Error(), nil
+	}
+	return out, err
+}
+
 func (itr *logTableFunctionRowIter) Close(_ *sql.Context) error {
 	return nil
 }
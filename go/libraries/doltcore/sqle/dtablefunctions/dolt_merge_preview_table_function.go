@@ -0,0 +1,312 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtablefunctions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/colmask"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/rowpolicy"
+)
+
+var _ sql.TableFunction = (*MergePreviewTableFunction)(nil)
+var _ sql.ExecSourceRel = (*MergePreviewTableFunction)(nil)
+var _ sql.AuthorizationCheckerNode = (*MergePreviewTableFunction)(nil)
+
+// MergePreviewTableFunction implements the dolt_merge_preview() table function, which merges a branch into another
+// in memory and reports what the merge would change, without touching either branch or the working set.
+type MergePreviewTableFunction struct {
+	ctx      *sql.Context
+	database sql.Database
+
+	baseSpec       string
+	mergeSpec      string
+	detail         bool
+	columnDefaults string
+}
+
+var mergePreviewTableSchema = sql.Schema{
+	&sql.Column{Name: "table_name", Type: types.LongText},
+	&sql.Column{Name: "adds", Type: types.Int64},
+	&sql.Column{Name: "modifies", Type: types.Int64},
+	&sql.Column{Name: "deletes", Type: types.Int64},
+	&sql.Column{Name: "data_conflicts", Type: types.Int64},
+	&sql.Column{Name: "schema_conflicts", Type: types.Int64},
+	&sql.Column{Name: "constraint_violations", Type: types.Int64},
+}
+
+var mergePreviewDetailTableSchema = sql.Schema{
+	&sql.Column{Name: "table_name", Type: types.LongText},
+	&sql.Column{Name: "change_type", Type: types.LongText},
+	&sql.Column{Name: "violation_type", Type: types.LongText, Nullable: true},
+	&sql.Column{Name: "row_key", Type: types.JSON, Nullable: true},
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (mp *MergePreviewTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &MergePreviewTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.evalArguments(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// evalArguments parses the literal arguments of the function with the same argument parser as the CLI.
+func (mp *MergePreviewTableFunction) evalArguments(expressions ...sql.Expression) (sql.Node, error) {
+	for _, expr := range expressions {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(mp.Name(), expr.String())
+		}
+		// prepared statements resolve functions beforehand, so above check fails
+		if _, ok := expr.(sql.FunctionExpression); ok {
+			return nil, ErrInvalidNonLiteralArgument.New(mp.Name(), expr.String())
+		}
+	}
+
+	args, err := getDoltArgs(mp.ctx, expressions, mp.Name())
+	if err != nil {
+		return nil, err
+	}
+	apr, err := cli.CreateMergePreviewArgParser().Parse(args)
+	if err != nil {
+		return nil, sql.ErrInvalidArgumentDetails.New(mp.Name(), err.Error())
+	}
+
+	newMp := *mp
+	switch apr.NArg() {
+	case 1:
+		newMp.mergeSpec = apr.Arg(0)
+	case 2:
+		newMp.baseSpec, newMp.mergeSpec = apr.Arg(0), apr.Arg(1)
+	default:
+		return nil, sql.ErrInvalidArgumentNumber.New(mp.Name(), "1 or 2 revisions", apr.NArg())
+	}
+	newMp.detail = apr.Contains(cli.DetailFlag)
+	newMp.columnDefaults = apr.GetValueOrDefault(cli.ColumnDefaultParam, "")
+
+	return &newMp, nil
+}
+
+// Database implements the sql.Databaser interface
+func (mp *MergePreviewTableFunction) Database() sql.Database {
+	return mp.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (mp *MergePreviewTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	nmp := *mp
+	nmp.database = database
+	return &nmp, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (mp *MergePreviewTableFunction) Name() string {
+	return "dolt_merge_preview"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (mp *MergePreviewTableFunction) Resolved() bool {
+	return true
+}
+
+func (mp *MergePreviewTableFunction) IsReadOnly() bool {
+	return true
+}
+
+// String implements the Stringer interface
+func (mp *MergePreviewTableFunction) String() string {
+	var args []string
+	if mp.baseSpec != "" {
+		args = append(args, mp.baseSpec)
+	}
+	args = append(args, mp.mergeSpec)
+	if mp.detail {
+		args = append(args, "--"+cli.DetailFlag)
+	}
+	if mp.columnDefaults != "" {
+		args = append(args, "--"+cli.ColumnDefaultParam, mp.columnDefaults)
+	}
+	return fmt.Sprintf("DOLT_MERGE_PREVIEW(%s)", strings.Join(args, ", "))
+}
+
+// Schema implements the sql.Node interface.
+func (mp *MergePreviewTableFunction) Schema() sql.Schema {
+	if mp.detail {
+		return mergePreviewDetailTableSchema
+	}
+	return mergePreviewTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (mp *MergePreviewTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (mp *MergePreviewTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return mp, nil
+}
+
+// CheckAuth implements the interface sql.AuthorizationCheckerNode.
+func (mp *MergePreviewTableFunction) CheckAuth(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tblNames, err := mp.database.GetTableNames(ctx)
+	if err != nil {
+		return false
+	}
+
+	var operations []sql.PrivilegedOperation
+	for _, tblName := range tblNames {
+		subject := sql.PrivilegeCheckSubject{Database: mp.database.Name(), Table: tblName}
+		operations = append(operations, sql.NewPrivilegedOperation(subject, sql.PrivilegeType_Select))
+	}
+
+	return opChecker.UserHasPrivileges(ctx, operations...)
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (mp *MergePreviewTableFunction) Expressions() []sql.Expression {
+	return []sql.Expression{}
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (mp *MergePreviewTableFunction) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
+	if len(exprs) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(0, len(exprs))
+	}
+	return mp, nil
+}
+
+// RowIter implements the sql.Node interface
+func (mp *MergePreviewTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	sqledb, ok := mp.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", mp.database)
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	dbName := sqledb.RevisionQualifiedName()
+	headRef, err := sess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	ddb := sqledb.DbData().Ddb
+
+	resolve := func(spec string) (*doltdb.Commit, error) {
+		cs, err := doltdb.NewCommitSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		optCmt, err := ddb.Resolve(ctx, cs, headRef)
+		if err != nil {
+			return nil, err
+		}
+		commit, ok := optCmt.ToCommit()
+		if !ok {
+			return nil, doltdb.ErrGhostCommitEncountered
+		}
+		return commit, nil
+	}
+
+	var commit *doltdb.Commit
+	if mp.baseSpec == "" {
+		commit, err = sess.GetHeadCommit(ctx, dbName)
+	} else {
+		commit, err = resolve(mp.baseSpec)
+	}
+	if err != nil {
+		return nil, err
+	}
+	mergeCommit, err := resolve(mp.mergeSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var columnDefaults merge.ColumnDefaults
+	if mp.columnDefaults != "" {
+		columnDefaults, err = merge.ParseColumnDefaults(mp.columnDefaults)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dbState, ok, err := sess.LookupDbState(ctx, dbName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	previews, err := merge.PreviewMerge(ctx, commit, mergeCommit, dbState.EditOpts(), columnDefaults, mp.detail)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []sql.Row
+	for _, tp := range previews {
+		tblName := tp.TableName.String()
+		if !mp.detail {
+			rows = append(rows, sql.Row{
+				tblName,
+				int64(tp.Adds),
+				int64(tp.Modifications),
+				int64(tp.Deletes),
+				int64(tp.DataConflicts),
+				int64(tp.SchemaConflicts),
+				int64(tp.ConstraintViolations),
+			})
+			continue
+		}
+
+		// the keys of the detail listing hold unmasked values of the rows of both branches, and keyless tables list
+		// every column of their rows
+		if len(tp.Rows) > 0 {
+			if err = colmask.CheckSource(ctx, sqledb, tp.TableName.Name, mp.Name()); err != nil {
+				return nil, err
+			}
+			if err = rowpolicy.CheckReadable(ctx, sqledb, tp.TableName.Name, mp.Name()); err != nil {
+				return nil, err
+			}
+		}
+		for _, rp := range tp.Rows {
+			var violationType, rowKey interface{}
+			if rp.ViolationType != "" {
+				violationType = rp.ViolationType
+			}
+			if rp.Key != nil {
+				rowKey = types.JSONDocument{Val: rp.Key}
+			}
+			rows = append(rows, sql.Row{tblName, rp.Type, violationType, rowKey})
+		}
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
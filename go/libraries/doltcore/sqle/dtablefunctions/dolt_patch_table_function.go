@@ -168,6 +168,14 @@ func (p *PatchTableFunction) PartitionRows(ctx *sql.Context, partition sql.Parti
 	includeSchemaDiff := bytes.Equal(partition.Key(), schemaAndDataChangePartitionKey) || bytes.Equal(partition.Key(), schemaChangePartitionKey)
 	includeDataDiff := bytes.Equal(partition.Key(), schemaAndDataChangePartitionKey) || bytes.Equal(partition.Key(), dataChangePartitionKey)
 
+	if includeDataDiff {
+		for _, delta := range tableDeltas {
+			if err = checkRestrictedDelta(ctx, sqledb, delta, p.Name()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	patches, err := getPatchNodes(ctx, sqledb.DbData(), tableDeltas, fromRefDetails, toRefDetails, includeSchemaDiff, includeDataDiff)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,737 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtablefunctions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/colmask"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/resolve"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/rowpolicy"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+const (
+	SystemTimeTableFunctionName = "dolt_system_time"
+
+	// SystemTimeStartColumn and SystemTimeEndColumn are the pseudo-columns added to every row version returned by
+	// dolt_system_time: the date of the commit that wrote the version, and the date of the commit that changed or
+	// deleted it.
+	SystemTimeStartColumn = "system_time_start"
+	SystemTimeEndColumn   = "system_time_end"
+
+	// The modes of dolt_system_time, one for each range form of FOR SYSTEM_TIME.
+	SystemTimeAll         = "all"
+	SystemTimeFromTo      = "from"
+	SystemTimeBetween     = "between"
+	SystemTimeContainedIn = "contained"
+)
+
+var ErrInvalidSystemTimeMode = errors.NewKind("invalid %s mode '%s', expected one of all, from, between or contained")
+var ErrInvalidSystemTimeBound = errors.NewKind("invalid FOR SYSTEM_TIME bound '%v': %s")
+
+// systemTimeEnd is the end of every row version that is still current at the head of the branch, the largest
+// DATETIME(6) value, following the convention of system-versioned tables.
+var systemTimeEnd = time.Date(9999, 12, 31, 23, 59, 59, 999999000, time.UTC)
+
+var _ sql.TableFunction = (*SystemTimeTableFunction)(nil)
+var _ sql.ExecSourceRel = (*SystemTimeTableFunction)(nil)
+var _ sql.AuthorizationCheckerNode = (*SystemTimeTableFunction)(nil)
+
+// SystemTimeTableFunction implements dolt_system_time(table, mode, start, end[, database]), which returns every
+// version of the rows of a table over the first-parent history of the current branch, each with the commit dates
+// between which it was valid. Queries using the range forms of FOR SYSTEM_TIME are rewritten to use it.
+type SystemTimeTableFunction struct {
+	ctx           *sql.Context
+	database      sql.Database
+	tableNameExpr sql.Expression
+	modeExpr      sql.Expression
+	startExpr     sql.Expression
+	endExpr       sql.Expression
+	databaseExpr  sql.Expression
+
+	tableName string
+	mode      string
+	sqlSch    sql.Schema
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (stf *SystemTimeTableFunction) NewInstance(ctx *sql.Context, database sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &SystemTimeTableFunction{
+		ctx:      ctx,
+		database: database,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (stf *SystemTimeTableFunction) Name() string {
+	return SystemTimeTableFunctionName
+}
+
+// Database implements the sql.Databaser interface
+func (stf *SystemTimeTableFunction) Database() sql.Database {
+	return stf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (stf *SystemTimeTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	if stf.databaseExpr != nil {
+		// the database was named explicitly, so it doesn't follow the current database
+		return stf, nil
+	}
+	nstf := *stf
+	nstf.database = database
+	return &nstf, nil
+}
+
+// Expressions implements the sql.Expressioner interface
+func (stf *SystemTimeTableFunction) Expressions() []sql.Expression {
+	exprs := []sql.Expression{stf.tableNameExpr, stf.modeExpr, stf.startExpr, stf.endExpr}
+	if stf.databaseExpr != nil {
+		exprs = append(exprs, stf.databaseExpr)
+	}
+	return exprs
+}
+
+// WithExpressions implements the sql.Expressioner interface
+func (stf *SystemTimeTableFunction) WithExpressions(expression ...sql.Expression) (sql.Node, error) {
+	if len(expression) < 4 || len(expression) > 5 {
+		return nil, sql.ErrInvalidArgumentNumber.New(stf.Name(), "4 to 5", len(expression))
+	}
+
+	// The table, mode and database determine the schema, so they must be known before the query is analyzed. The
+	// bounds of the range are only evaluated when the rows are read.
+	literals := []sql.Expression{expression[0], expression[1]}
+	if len(expression) == 5 {
+		literals = append(literals, expression[4])
+	}
+	for _, expr := range literals {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(stf.Name(), expr.String())
+		}
+		if _, ok := expr.(sql.FunctionExpression); ok {
+			return nil, ErrInvalidNonLiteralArgument.New(stf.Name(), expr.String())
+		}
+	}
+
+	nstf := *stf
+	nstf.tableNameExpr = expression[0]
+	nstf.modeExpr = expression[1]
+	nstf.startExpr = expression[2]
+	nstf.endExpr = expression[3]
+	nstf.databaseExpr = nil
+	if len(expression) == 5 {
+		nstf.databaseExpr = expression[4]
+	}
+
+	if !nstf.Resolved() {
+		return &nstf, nil
+	}
+	if err := nstf.evaluateArguments(); err != nil {
+		return nil, err
+	}
+	if err := nstf.generateSchema(nstf.ctx); err != nil {
+		return nil, err
+	}
+	return &nstf, nil
+}
+
+// evaluateArguments evaluates the literal table name, mode and database arguments.
+func (stf *SystemTimeTableFunction) evaluateArguments() error {
+	tableName, err := stf.evaluateString(stf.tableNameExpr)
+	if err != nil {
+		return err
+	}
+	if tableName == "" {
+		return ErrInvalidTableName.New(stf.tableNameExpr.String())
+	}
+
+	mode, err := stf.evaluateString(stf.modeExpr)
+	if err != nil {
+		return err
+	}
+	mode = strings.ToLower(mode)
+	switch mode {
+	case SystemTimeAll, SystemTimeFromTo, SystemTimeBetween, SystemTimeContainedIn:
+	default:
+		return ErrInvalidSystemTimeMode.New(stf.Name(), mode)
+	}
+
+	if stf.databaseExpr != nil {
+		dbName, err := stf.evaluateString(stf.databaseExpr)
+		if err != nil {
+			return err
+		}
+		db, err := dsess.DSessFromSess(stf.ctx.Session).Provider().Database(stf.ctx, dbName)
+		if err != nil {
+			return err
+		}
+		stf.database = db
+	}
+
+	stf.tableName = tableName
+	stf.mode = mode
+	return nil
+}
+
+func (stf *SystemTimeTableFunction) evaluateString(expr sql.Expression) (string, error) {
+	if !gmstypes.IsText(expr.Type()) {
+		return "", sql.ErrInvalidArgumentDetails.New(stf.Name(), expr.String())
+	}
+	v, err := expr.Eval(stf.ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", sql.ErrInvalidArgumentDetails.New(stf.Name(), expr.String())
+	}
+	return s, nil
+}
+
+// generateSchema builds the schema of the results from the table's schema at the head of the branch, followed by
+// the system time pseudo-columns.
+func (stf *SystemTimeTableFunction) generateSchema(ctx *sql.Context) error {
+	sch, err := stf.headSchema(ctx)
+	if err != nil {
+		return err
+	}
+
+	// TODO: sql.Columns include a Source that indicates the table it came from, but we don't have a real table
+	//       when the column comes from a table function, so we omit the table name when we create these columns.
+	sqlSch, err := sqlutil.FromDoltSchema("", "", sch)
+	if err != nil {
+		return err
+	}
+	stf.sqlSch = append(sqlSch.Schema.Copy(),
+		&sql.Column{Name: SystemTimeStartColumn, Type: gmstypes.DatetimeMaxPrecision},
+		&sql.Column{Name: SystemTimeEndColumn, Type: gmstypes.DatetimeMaxPrecision},
+	)
+	return nil
+}
+
+func (stf *SystemTimeTableFunction) headSchema(ctx *sql.Context) (schema.Schema, error) {
+	head, err := stf.headCommit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	root, err := head.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, tbl, ok, err := resolve.Table(ctx, root, stf.tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, sql.ErrTableNotFound.New(stf.tableName)
+	}
+	return tbl.GetSchema(ctx)
+}
+
+func (stf *SystemTimeTableFunction) headCommit(ctx *sql.Context) (*doltdb.Commit, error) {
+	sqledb, ok := stf.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", stf.database)
+	}
+	if !types.IsFormat_DOLT(sqledb.DbData().Ddb.Format()) {
+		return nil, fmt.Errorf("%s is only supported for databases in the %s format", stf.Name(), types.Format_DOLT.VersionString())
+	}
+	return dsess.DSessFromSess(ctx.Session).GetHeadCommit(ctx, sqledb.RevisionQualifiedName())
+}
+
+// Schema implements the sql.Node interface
+func (stf *SystemTimeTableFunction) Schema() sql.Schema {
+	if !stf.Resolved() {
+		return nil
+	}
+	return stf.sqlSch
+}
+
+// Resolved implements the sql.Resolvable interface
+func (stf *SystemTimeTableFunction) Resolved() bool {
+	for _, expr := range stf.Expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsReadOnly implements the sql.Node interface
+func (stf *SystemTimeTableFunction) IsReadOnly() bool {
+	return true
+}
+
+// String implements the Stringer interface
+func (stf *SystemTimeTableFunction) String() string {
+	var args []string
+	for _, expr := range stf.Expressions() {
+		args = append(args, expr.String())
+	}
+	return fmt.Sprintf("DOLT_SYSTEM_TIME(%s)", strings.Join(args, ", "))
+}
+
+// Children implements the sql.Node interface
+func (stf *SystemTimeTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface
+func (stf *SystemTimeTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return stf, nil
+}
+
+// CheckAuth implements the interface sql.AuthorizationCheckerNode
+func (stf *SystemTimeTableFunction) CheckAuth(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	if stf.tableName == "" {
+		return ExpressionIsDeferred(stf.tableNameExpr)
+	}
+
+	subject := sql.PrivilegeCheckSubject{Database: stf.database.Name(), Table: stf.tableName}
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(subject, sql.PrivilegeType_Select))
+}
+
+// RowIter implements the sql.Node interface
+func (stf *SystemTimeTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	var start, end time.Time
+	if stf.mode != SystemTimeAll {
+		var err error
+		if start, err = stf.evaluateBound(ctx, stf.startExpr, row); err != nil {
+			return nil, err
+		}
+		if end, err = stf.evaluateBound(ctx, stf.endExpr, row); err != nil {
+			return nil, err
+		}
+	}
+
+	head, err := stf.headCommit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sqledb := stf.database.(dsess.SqlDatabase)
+	if err = colmask.CheckSource(ctx, sqledb, stf.tableName, stf.Name()); err != nil {
+		return nil, err
+	}
+	if err = rowpolicy.CheckReadable(ctx, sqledb, stf.tableName, stf.Name()); err != nil {
+		return nil, err
+	}
+	h := &systemTimeHistory{
+		ddb:        sqledb.DbData().Ddb,
+		tableName:  stf.tableName,
+		outSch:     stf.sqlSch[:len(stf.sqlSch)-2],
+		mode:       stf.mode,
+		start:      start,
+		end:        end,
+		ends:       make(map[string][]time.Time),
+		defaultEnd: systemTimeEnd,
+	}
+
+	child, cancel := context.WithCancel(ctx)
+	iter := systemTimeRowIter{
+		rows:    make(chan sql.Row, 64),
+		errChan: make(chan error),
+		cancel:  cancel,
+	}
+	go func() {
+		iter.queueRows(ctx.WithContext(child), h, head)
+	}()
+	return iter, nil
+}
+
+// systemTimeRowIter returns the row versions found by a systemTimeHistory as it walks the history of the branch.
+type systemTimeRowIter struct {
+	rows    chan sql.Row
+	errChan chan error
+	cancel  context.CancelFunc
+}
+
+var _ sql.RowIter = systemTimeRowIter{}
+
+func (itr systemTimeRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-itr.errChan:
+		return nil, err
+	case row, ok := <-itr.rows:
+		if !ok {
+			return nil, io.EOF
+		}
+		return row, nil
+	}
+}
+
+func (itr systemTimeRowIter) Close(*sql.Context) error {
+	itr.cancel()
+	return nil
+}
+
+func (itr systemTimeRowIter) queueRows(ctx *sql.Context, h *systemTimeHistory, head *doltdb.Commit) {
+	h.send = func(r sql.Row) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case itr.rows <- r:
+			return nil
+		}
+	}
+	if err := h.walk(ctx, head); err != nil {
+		select {
+		case <-ctx.Done():
+		case itr.errChan <- err:
+		}
+		return
+	}
+	close(itr.rows)
+}
+
+// evaluateBound evaluates a bound of the range, which is either a point in time or a commit spec standing for the
+// date of the commit it resolves to.
+func (stf *SystemTimeTableFunction) evaluateBound(ctx *sql.Context, expr sql.Expression, row sql.Row) (time.Time, error) {
+	v, err := expr.Eval(ctx, row)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if v == nil {
+		return time.Time{}, ErrInvalidSystemTimeBound.New(expr.String(), "bound cannot be NULL")
+	}
+
+	converted, _, err := gmstypes.DatetimeMaxPrecision.Convert(v)
+	if err == nil {
+		return converted.(time.Time).UTC(), nil
+	}
+	spec, ok := v.(string)
+	if !ok {
+		return time.Time{}, ErrInvalidSystemTimeBound.New(v, err.Error())
+	}
+
+	sqledb, ok := stf.database.(dsess.SqlDatabase)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected database type: %T", stf.database)
+	}
+	headRef, err := dsess.DSessFromSess(ctx.Session).CWBHeadRef(ctx, sqledb.RevisionQualifiedName())
+	if err != nil {
+		return time.Time{}, err
+	}
+	cm, err := resolveCommit(ctx, sqledb.DbData().Ddb, headRef, spec)
+	if err != nil {
+		return time.Time{}, ErrInvalidSystemTimeBound.New(spec, err.Error())
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return meta.Time().UTC(), nil
+}
+
+// systemTimeHistory walks the first-parent history of a branch back from its head, and sends the versions of the rows
+// of a table that are in the range of a query as it finds them. Walking back, the end of a version is found before its
+// start, when the commit that changed or deleted the row is diffed against its parent. The walk stops once no
+// version it could still find is in the range, assuming that the dates of the commits decrease along the history, as
+// they do unless commits were made with explicit dates.
+type systemTimeHistory struct {
+	ddb       *doltdb.DoltDB
+	tableName string
+	outSch    sql.Schema
+
+	mode       string
+	start, end time.Time
+
+	// ends holds the ends of the versions of each row in the commit being walked, by key, for the versions that end
+	// before the head of the branch. Every other version ends at defaultEnd. The versions of a keyless row are copies
+	// of it, which are ended most recent first, so the ends of their versions are matched to their starts as a stack.
+	ends       map[string][]time.Time
+	defaultEnd time.Time
+	// live is the number of ends in |ends| after the start of the range
+	live int
+
+	send func(sql.Row) error
+}
+
+// systemTimeTable is a table as of a commit, or the lack of one.
+type systemTimeTable struct {
+	sch  schema.Schema
+	rows prolly.Map
+	ok   bool
+}
+
+func (h *systemTimeHistory) walk(ctx *sql.Context, head *doltdb.Commit) error {
+	cm := head
+	tbl, err := h.table(ctx, cm)
+	if err != nil {
+		return err
+	}
+	for {
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return err
+		}
+		date := meta.Time().UTC()
+		if h.done(date) {
+			return nil
+		}
+
+		var parent *doltdb.Commit
+		var parentTbl systemTimeTable
+		if cm.NumParents() > 0 {
+			optCmt, err := cm.GetParent(ctx, 0)
+			if err != nil {
+				return err
+			}
+			var ok bool
+			if parent, ok = optCmt.ToCommit(); !ok {
+				return doltdb.ErrGhostCommitEncountered
+			}
+			if parentTbl, err = h.table(ctx, parent); err != nil {
+				return err
+			}
+		}
+
+		if err = h.addCommit(ctx, date, parentTbl, tbl); err != nil {
+			return err
+		}
+		if parent == nil {
+			return nil
+		}
+		cm, tbl = parent, parentTbl
+	}
+}
+
+func (h *systemTimeHistory) table(ctx *sql.Context, cm *doltdb.Commit) (systemTimeTable, error) {
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return systemTimeTable{}, err
+	}
+	_, tbl, ok, err := resolve.Table(ctx, root, h.tableName)
+	if err != nil || !ok {
+		return systemTimeTable{}, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return systemTimeTable{}, err
+	}
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return systemTimeTable{}, err
+	}
+	return systemTimeTable{sch: sch, rows: durable.ProllyMapFromIndex(idx), ok: true}, nil
+}
+
+// done returns true if no version that starts at or before |date| can be in the range.
+func (h *systemTimeHistory) done(date time.Time) bool {
+	if !date.Before(h.start) {
+		return false
+	}
+	switch h.mode {
+	case SystemTimeContainedIn:
+		return true
+	case SystemTimeFromTo, SystemTimeBetween:
+		return !h.defaultEnd.After(h.start) && h.live == 0
+	default:
+		return false
+	}
+}
+
+// addCommit sends the versions of the rows of |tbl| that start at |date|, the date of the commit it is read from, and
+// records the ends of the versions of the rows of |parent| that end at |date|.
+func (h *systemTimeHistory) addCommit(ctx *sql.Context, date time.Time, parent, tbl systemTimeTable) error {
+	if !tbl.ok {
+		// every version of the parent ends when the table is dropped
+		h.endAll(date)
+		return nil
+	}
+
+	from := parent.rows
+	restart := !parent.ok || !schema.SchemasAreEqual(parent.sch, tbl.sch)
+	if restart {
+		// Row versions can't be matched up across a schema change, so every row starts a new version
+		empty, err := durable.NewEmptyIndex(ctx, h.ddb.ValueReadWriter(), h.ddb.NodeStore(), tbl.sch, false)
+		if err != nil {
+			return err
+		}
+		from = durable.ProllyMapFromIndex(empty)
+	}
+
+	mapping := outputMapping(h.outSch, tbl.sch)
+	keyless := schema.IsKeyless(tbl.sch)
+	err := prolly.DiffMaps(ctx, from, tbl.rows, false, func(_ context.Context, d tree.Diff) error {
+		key := string(d.Key)
+		var started, ended uint64
+		if keyless {
+			// a keyless row is identified by its contents, so only the number of copies of it can change
+			var oldCard, newCard uint64
+			if d.Type != tree.AddedDiff {
+				oldCard = val.ReadKeylessCardinality(val.Tuple(d.From))
+			}
+			if d.Type != tree.RemovedDiff {
+				newCard = val.ReadKeylessCardinality(val.Tuple(d.To))
+			}
+			if newCard < oldCard {
+				ended = oldCard - newCard
+			} else {
+				started = newCard - oldCard
+			}
+		} else {
+			if d.Type != tree.AddedDiff {
+				ended = 1
+			}
+			if d.Type != tree.RemovedDiff {
+				started = 1
+			}
+		}
+
+		if started > 0 {
+			r, err := index.BuildRow(ctx, val.Tuple(d.Key), val.Tuple(d.To), tbl.sch, tbl.rows.NodeStore())
+			if err != nil {
+				return err
+			}
+			out, err := mapRow(ctx, h.outSch, mapping, r)
+			if err != nil {
+				return err
+			}
+			for i := uint64(0); i < started; i++ {
+				end := h.popEnd(key)
+				if !h.inRange(date, end) {
+					continue
+				}
+				version := make(sql.Row, 0, len(out)+2)
+				if err = h.send(append(append(version, out...), date, end)); err != nil {
+					return err
+				}
+			}
+		}
+		for i := uint64(0); i < ended; i++ {
+			h.pushEnd(key, date)
+		}
+		return nil
+	})
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	if restart {
+		h.endAll(date)
+	}
+	return nil
+}
+
+// pushEnd records that a version of the row with |key| ends at |date|.
+func (h *systemTimeHistory) pushEnd(key string, date time.Time) {
+	h.ends[key] = append(h.ends[key], date)
+	if date.After(h.start) {
+		h.live++
+	}
+}
+
+// popEnd returns the end of the most recently started version of the row with |key|.
+func (h *systemTimeHistory) popEnd(key string) time.Time {
+	ends := h.ends[key]
+	if len(ends) == 0 {
+		return h.defaultEnd
+	}
+	end := ends[len(ends)-1]
+	if len(ends) == 1 {
+		delete(h.ends, key)
+	} else {
+		h.ends[key] = ends[:len(ends)-1]
+	}
+	if end.After(h.start) {
+		h.live--
+	}
+	return end
+}
+
+// endAll records that every version of every row ends at |date|.
+func (h *systemTimeHistory) endAll(date time.Time) {
+	h.ends = make(map[string][]time.Time)
+	h.live = 0
+	h.defaultEnd = date
+}
+
+// inRange reports whether a row version valid from |rowStart| to |rowEnd| overlaps the range of the query, following
+// the definitions of the SQL:2011 system time periods.
+func (h *systemTimeHistory) inRange(rowStart, rowEnd time.Time) bool {
+	switch h.mode {
+	case SystemTimeFromTo:
+		return rowStart.Before(h.end) && rowEnd.After(h.start)
+	case SystemTimeBetween:
+		return !rowStart.After(h.end) && rowEnd.After(h.start)
+	case SystemTimeContainedIn:
+		return !rowStart.Before(h.start) && !rowEnd.After(h.end)
+	default:
+		return true
+	}
+}
+
+// outputMapping maps each column of |outSch| to the index of the column with the same name in |sch|, or -1.
+func outputMapping(outSch sql.Schema, sch schema.Schema) []int {
+	cols := sch.GetAllCols().GetColumns()
+	mapping := make([]int, len(outSch))
+	for i, col := range outSch {
+		mapping[i] = -1
+		for j, c := range cols {
+			if strings.EqualFold(col.Name, c.Name) {
+				mapping[i] = j
+				break
+			}
+		}
+	}
+	return mapping
+}
+
+func mapRow(ctx *sql.Context, outSch sql.Schema, mapping []int, r sql.Row) (sql.Row, error) {
+	out := make(sql.Row, len(outSch))
+	for i, j := range mapping {
+		if j < 0 || r[j] == nil {
+			continue
+		}
+		v, _, err := outSch[i].Type.Convert(r[j])
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
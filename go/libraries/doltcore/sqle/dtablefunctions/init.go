@@ -25,4 +25,6 @@ var DoltTableFunctions = []sql.TableFunction{
 	&SchemaDiffTableFunction{},
 	&ReflogTableFunction{},
 	&QueryDiffTableFunction{},
+	&SystemTimeTableFunction{},
+	&MergePreviewTableFunction{},
 }
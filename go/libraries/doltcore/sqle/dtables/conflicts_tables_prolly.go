@@ -19,12 +19,14 @@ import (
 	"fmt"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
 	"github.com/zeebo/xxh3"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/store/hash"
@@ -144,6 +146,17 @@ type prollyConflictRowIter struct {
 	baseHash, theirHash hash.Hash
 	baseRows            prolly.Map
 	theirRows           prolly.Map
+
+	// jsonCols are the JSON columns whose conflicting paths are reported in the dolt_conflict_json_paths column
+	jsonCols []conflictJsonCol
+}
+
+// conflictJsonCol is a JSON column of a table with conflicts, with the positions of its base, our and their values in
+// the rows of the conflicts table and the array merge strategy its values are merged with.
+type conflictJsonCol struct {
+	name       string
+	b, o, t    int
+	arrayMerge merge.JsonArrayMerge
 }
 
 var _ sql.RowIter = (*prollyConflictRowIter)(nil)
@@ -180,6 +193,11 @@ func newProllyConflictRowIter(ctx *sql.Context, ct ProllyConflictsTable) (*proll
 		n = t + theirsVD.Count() + 4
 	}
 
+	jsonCols, err := getConflictJsonCols(ctx, ct)
+	if err != nil {
+		return nil, err
+	}
+
 	return &prollyConflictRowIter{
 		itr:      itr,
 		tblName:  ct.tblName,
@@ -196,16 +214,47 @@ func newProllyConflictRowIter(ctx *sql.Context, ct ProllyConflictsTable) (*proll
 		o:        o,
 		t:        t,
 		n:        n,
+		jsonCols: jsonCols,
 	}, nil
 }
 
+// getConflictJsonCols returns the JSON columns of the conflicts table |ct|.
+func getConflictJsonCols(ctx *sql.Context, ct ProllyConflictsTable) ([]conflictJsonCol, error) {
+	var jsonCols []conflictJsonCol
+	for _, col := range ct.ourSch.GetNonPKCols().GetColumns() {
+		if col.TypeInfo.GetTypeIdentifier() != typeinfo.JSONTypeIdentifier {
+			continue
+		}
+		jc := conflictJsonCol{
+			name: col.Name,
+			b:    ct.sqlSch.Schema.IndexOfColName("base_" + col.Name),
+			o:    ct.sqlSch.Schema.IndexOfColName("our_" + col.Name),
+			t:    ct.sqlSch.Schema.IndexOfColName("their_" + col.Name),
+		}
+		if jc.b < 0 || jc.o < 0 || jc.t < 0 {
+			continue
+		}
+		var err error
+		jc.arrayMerge, err = merge.JsonArrayMergeForColumn(ctx, ct.root, ct.tblName, col.Name)
+		if err != nil {
+			return nil, err
+		}
+		jsonCols = append(jsonCols, jc)
+	}
+	return jsonCols, nil
+}
+
 func (itr *prollyConflictRowIter) Next(ctx *sql.Context) (sql.Row, error) {
 	c, err := itr.nextConflictVals(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	r := make(sql.Row, itr.n)
+	n := itr.n
+	if len(itr.jsonCols) > 0 {
+		n++
+	}
+	r := make(sql.Row, n)
 	r[0] = c.h.String()
 
 	if !itr.keyless {
@@ -237,9 +286,57 @@ func (itr *prollyConflictRowIter) Next(ctx *sql.Context) (sql.Row, error) {
 		}
 	}
 
+	if len(itr.jsonCols) > 0 {
+		r[itr.n], err = itr.getConflictJsonPaths(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return r, nil
 }
 
+// getConflictJsonPaths returns a JSON object holding, for each JSON column of the conflict row |r| that was changed on
+// both sides, the paths of the values inside the documents whose changes couldn't be merged. Returns nil if there are
+// no such paths.
+func (itr *prollyConflictRowIter) getConflictJsonPaths(ctx *sql.Context, r sql.Row) (interface{}, error) {
+	var paths gmstypes.JsonObject
+	for _, jc := range itr.jsonCols {
+		base, ok := r[jc.b].(sql.JSONWrapper)
+		if !ok {
+			continue
+		}
+		ours, ok := r[jc.o].(sql.JSONWrapper)
+		if !ok {
+			continue
+		}
+		theirs, ok := r[jc.t].(sql.JSONWrapper)
+		if !ok {
+			continue
+		}
+
+		conflictPaths, err := merge.JsonConflictPaths(ctx, itr.ns, base, ours, theirs, jc.arrayMerge)
+		if err != nil {
+			return nil, err
+		}
+		if len(conflictPaths) == 0 {
+			continue
+		}
+		if paths == nil {
+			paths = make(gmstypes.JsonObject)
+		}
+		vals := make(gmstypes.JsonArray, len(conflictPaths))
+		for i, p := range conflictPaths {
+			vals[i] = p
+		}
+		paths[jc.name] = vals
+	}
+	if paths == nil {
+		return nil, nil
+	}
+	return gmstypes.JSONDocument{Val: paths}, nil
+}
+
 func (itr *prollyConflictRowIter) putConflictRowVals(ctx *sql.Context, c conf, r sql.Row) error {
 	if c.bV != nil {
 		for i := 0; i < itr.baseVD.Count(); i++ {
@@ -408,23 +505,23 @@ func (itr *prollyConflictRowIter) loadTableMaps(ctx *sql.Context, baseHash, thei
 		if err != nil {
 			return err
 		}
-		baseTbl, ok, err := rv.GetTable(ctx, itr.tblName)
+		baseTbl, ok, err := doltdb.GetTableByIdentity(ctx, rv, itr.tblName, itr.ourSch)
 		if err != nil {
 			return err
 		}
 
-		var idx durable.Index
 		if !ok {
-			idx, err = durable.NewEmptyIndex(ctx, itr.vrw, itr.ns, itr.ourSch, false)
+			idx, err := durable.NewEmptyIndex(ctx, itr.vrw, itr.ns, itr.ourSch, false)
+			if err != nil {
+				return err
+			}
+			itr.baseRows = durable.ProllyMapFromIndex(idx)
 		} else {
-			idx, err = baseTbl.GetRowData(ctx)
-		}
-
-		if err != nil {
-			return err
+			itr.baseRows, err = merge.ConflictRowData(ctx, baseTbl, itr.ourSch, nil)
+			if err != nil {
+				return err
+			}
 		}
-
-		itr.baseRows = durable.ProllyMapFromIndex(idx)
 		itr.baseHash = baseHash
 	}
 
@@ -434,7 +531,7 @@ func (itr *prollyConflictRowIter) loadTableMaps(ctx *sql.Context, baseHash, thei
 			return err
 		}
 
-		theirTbl, ok, err := rv.GetTable(ctx, itr.tblName)
+		theirTbl, ok, err := doltdb.GetTableByIdentity(ctx, rv, itr.tblName, itr.ourSch)
 		if err != nil {
 			return err
 		}
@@ -442,11 +539,10 @@ func (itr *prollyConflictRowIter) loadTableMaps(ctx *sql.Context, baseHash, thei
 			return fmt.Errorf("failed to find table %s in right root value", itr.tblName)
 		}
 
-		idx, err := theirTbl.GetRowData(ctx)
+		itr.theirRows, err = merge.ConflictRowData(ctx, theirTbl, itr.ourSch, &itr.baseRows)
 		if err != nil {
 			return err
 		}
-		itr.theirRows = durable.ProllyMapFromIndex(idx)
 		itr.theirHash = theirHash
 	}
 
@@ -565,12 +661,13 @@ func (cd *prollyConflictDeleter) Delete(ctx *sql.Context, r sql.Row) (err error)
 		return err
 	}
 
-	// then the hash follows. It is the first column of the row and the second to last in the key
+	// then the hash follows. It is the first column of the row and follows the source key fields in the key
+	numSrcKeyFields := prolly.ArtifactKeySourceFieldCount(cd.kd)
 	h := hash.Parse(r[0].(string))
-	cd.kB.PutCommitAddr(cd.kd.Count()-2, h)
+	cd.kB.PutCommitAddr(numSrcKeyFields, h)
 
-	// Finally the artifact type which is always a conflict
-	cd.kB.PutUint8(cd.kd.Count()-1, uint8(prolly.ArtifactTypeConflict))
+	// Finally the artifact type which is always a conflict. Conflicts have no violation info hash.
+	cd.kB.PutUint8(numSrcKeyFields+1, uint8(prolly.ArtifactTypeConflict))
 
 	key := cd.kB.Build(cd.pool)
 	err = cd.ed.Delete(ctx, key)
@@ -583,19 +680,20 @@ func (cd *prollyConflictDeleter) Delete(ctx *sql.Context, r sql.Row) (err error)
 
 func (cd *prollyConflictDeleter) putPrimaryKeys(ctx *sql.Context, r sql.Row) error {
 	// get keys from either base, ours, or theirs
+	numSrcKeyFields := prolly.ArtifactKeySourceFieldCount(cd.kd)
 	o := func() int {
 		if o := 1; r[o] != nil {
 			return o
-		} else if o = 1 + cd.kd.Count() - 2 + cd.vd.Count(); r[o] != nil {
+		} else if o = 1 + numSrcKeyFields + cd.vd.Count(); r[o] != nil {
 			return o
-		} else if o = 1 + (cd.kd.Count()-2+cd.vd.Count())*2 + 1; r[o] != nil {
+		} else if o = 1 + (numSrcKeyFields+cd.vd.Count())*2 + 1; r[o] != nil {
 			return o
 		} else {
 			panic("neither base, ours, or theirs had a key")
 		}
 	}()
 
-	for i := 0; i < cd.kd.Count()-2; i++ {
+	for i := 0; i < numSrcKeyFields; i++ {
 		err := tree.PutField(ctx, cd.ed.NodeStore(), cd.kB, i, r[o+i])
 
 		if err != nil {
@@ -668,6 +766,10 @@ func (cd *prollyConflictDeleter) Close(ctx *sql.Context) error {
 	return cd.ct.rs.SetRoot(ctx, updatedRoot)
 }
 
+// conflictJsonPathsCol is the column of the conflicts tables of tables with JSON columns that lists, for each JSON
+// column changed on both sides of a conflict, the paths inside the documents whose changes couldn't be merged.
+const conflictJsonPathsCol = "dolt_conflict_json_paths"
+
 type versionMappings struct {
 	ourMapping, theirMapping, baseMapping val.OrdinalMapping
 }
@@ -679,6 +781,15 @@ func calculateConflictSchema(base, ours, theirs schema.Schema) (schema.Schema, *
 	if keyless {
 		n += 3
 	}
+	hasJsonCols := false
+	for _, col := range ours.GetNonPKCols().GetColumns() {
+		if col.TypeInfo.GetTypeIdentifier() == typeinfo.JSONTypeIdentifier {
+			hasJsonCols = true
+		}
+	}
+	if hasJsonCols {
+		n++
+	}
 
 	cols := make([]schema.Column, n)
 
@@ -752,6 +863,15 @@ func calculateConflictSchema(base, ours, theirs schema.Schema) (schema.Schema, *
 		i++
 	}
 
+	if hasJsonCols {
+		// the paths inside the JSON documents of the conflicting values that couldn't be merged
+		cols[i], err = schema.NewColumnWithTypeInfo(conflictJsonPathsCol, uint64(i), typeinfo.JSONType, false, "", false, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		i++
+	}
+
 	sch, err := schema.NewSchema(schema.NewColCollection(cols...), nil, schema.Collation_Default, nil, nil)
 	if err != nil {
 		return nil, nil, err
@@ -15,6 +15,7 @@
 package dtables
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 
@@ -273,40 +274,66 @@ func (itr prollyCVIter) Next(ctx *sql.Context) (sql.Row, error) {
 		o += itr.vd.Count() - 1
 	}
 
-	switch art.ArtType {
+	r[o], err = violationInfo(art.ArtType, meta.VInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// violationInfo decodes the violation info, |vInfo|, of a constraint violation of |artType|.
+func violationInfo(artType prolly.ArtifactType, vInfo []byte) (sql.JSONWrapper, error) {
+	switch artType {
 	case prolly.ArtifactTypeForeignKeyViol:
 		var m merge.FkCVMeta
-		err = json.Unmarshal(meta.VInfo, &m)
-		if err != nil {
-			return nil, err
-		}
-		r[o] = m
+		err := json.Unmarshal(vInfo, &m)
+		return m, err
 	case prolly.ArtifactTypeUniqueKeyViol:
 		var m merge.UniqCVMeta
-		err = json.Unmarshal(meta.VInfo, &m)
-		if err != nil {
-			return nil, err
-		}
-		r[o] = m
+		err := json.Unmarshal(vInfo, &m)
+		return m, err
 	case prolly.ArtifactTypeNullViol:
 		var m merge.NullViolationMeta
-		err = json.Unmarshal(meta.VInfo, &m)
-		if err != nil {
-			return nil, err
-		}
-		r[o] = m
+		err := json.Unmarshal(vInfo, &m)
+		return m, err
 	case prolly.ArtifactTypeChkConsViol:
 		var m merge.CheckCVMeta
-		err = json.Unmarshal(meta.VInfo, &m)
+		err := json.Unmarshal(vInfo, &m)
+		return m, err
+	default:
+		panic("json not implemented for artifact type")
+	}
+}
+
+// canonicalViolationInfo returns a canonical JSON encoding of the violation_info value |v|, so that violation info
+// read back from a row can be compared with the violation info stored in an artifact.
+func canonicalViolationInfo(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case sql.JSONWrapper:
+		i, err := v.ToInterface()
 		if err != nil {
 			return nil, err
 		}
-		r[o] = m
+		return canonicalViolationInfo(i)
+	case string:
+		var i interface{}
+		if err := json.Unmarshal([]byte(v), &i); err != nil {
+			return nil, err
+		}
+		return json.Marshal(i)
 	default:
-		panic("json not implemented for artifact type")
+		// round trip through json so that the values of structs and maps are represented the same way
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		var i interface{}
+		if err = json.Unmarshal(b, &i); err != nil {
+			return nil, err
+		}
+		return json.Marshal(i)
 	}
-
-	return r, nil
 }
 
 type prollyCVDeleter struct {
@@ -324,28 +351,36 @@ func (d *prollyCVDeleter) Delete(ctx *sql.Context, r sql.Row) error {
 	// When we delete a row, we need to build the primary key from the row data.
 	// The PK has 3+ fields: from_root_ish, violation_type, plus all PK fields from the source table.
 	// If the source table is keyless and has no PK, then we use the unique row hash provided by keyless tables.
-	for i := 0; i < d.kd.Count()-2; i++ {
+	numSrcKeyFields := prolly.ArtifactKeySourceFieldCount(d.kd)
+	for i := 0; i < numSrcKeyFields; i++ {
 		err := tree.PutField(ctx, d.cvt.artM.NodeStore(), d.kb, i, r[i+2])
 		if err != nil {
 			return err
 		}
 	}
+	srcKey := d.kb.BuildPrefix(d.pool, numSrcKeyFields)
 
-	// then the hash
 	h := hash.Parse(r[0].(string))
-	d.kb.PutCommitAddr(d.kd.Count()-2, h)
-
-	// Finally the artifact type
 	artType := merge.UnmapCVType(r[1])
-	d.kb.PutUint8(d.kd.Count()-1, uint8(artType))
 
-	key := d.kb.Build(d.pool)
-	err := d.ed.Delete(ctx, key)
+	// A row may carry several violations of the same type, so only delete the
+	// one whose violation info matches the deleted row.
+	want, err := canonicalViolationInfo(r[len(r)-1])
 	if err != nil {
 		return err
 	}
-
-	return nil
+	_, err = d.ed.DeleteConstraintViolations(ctx, srcKey, h, artType, func(meta prolly.ConstraintViolationMeta) (bool, error) {
+		info, err := violationInfo(artType, meta.VInfo)
+		if err != nil {
+			return false, err
+		}
+		got, err := canonicalViolationInfo(info)
+		if err != nil {
+			return false, err
+		}
+		return bytes.Equal(got, want), nil
+	})
+	return err
 }
 
 // StatementBegin implements the interface sql.TableEditor. Currently a no-op.
@@ -0,0 +1,109 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/querydigest"
+)
+
+// QueryDigestsTableName is the name of the table of the statistics of the statements run by the server, by digest.
+const QueryDigestsTableName = "dolt_query_digests"
+
+var queryDigestsSchema = sql.Schema{
+	&sql.Column{Name: "digest", Type: types.MustCreateString(sqltypes.VarChar, 64, sql.Collation_ascii_bin), Source: QueryDigestsTableName, Nullable: true},
+	&sql.Column{Name: "database", Type: types.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_bin), Source: QueryDigestsTableName, Nullable: true},
+	&sql.Column{Name: "digest_text", Type: types.LongText, Source: QueryDigestsTableName, Nullable: true},
+	&sql.Column{Name: "count", Type: types.Uint64, Source: QueryDigestsTableName},
+	&sql.Column{Name: "errors", Type: types.Uint64, Source: QueryDigestsTableName},
+	&sql.Column{Name: "total_latency_us", Type: types.Uint64, Source: QueryDigestsTableName},
+	&sql.Column{Name: "avg_latency_us", Type: types.Uint64, Source: QueryDigestsTableName},
+	&sql.Column{Name: "p99_latency_us", Type: types.Uint64, Source: QueryDigestsTableName},
+	&sql.Column{Name: "max_latency_us", Type: types.Uint64, Source: QueryDigestsTableName},
+	&sql.Column{Name: "rows_examined", Type: types.Uint64, Source: QueryDigestsTableName},
+	&sql.Column{Name: "rows_returned", Type: types.Uint64, Source: QueryDigestsTableName},
+	&sql.Column{Name: "first_seen", Type: types.DatetimeMaxPrecision, Source: QueryDigestsTableName, Nullable: true},
+	&sql.Column{Name: "last_seen", Type: types.DatetimeMaxPrecision, Source: QueryDigestsTableName, Nullable: true},
+}
+
+// QueryDigestsTable is a read-only view of the statistics of the statements run by the server, aggregated by their
+// digest. Its last row, whose digest is NULL, counts the statements whose digests didn't fit in the store. Since
+// digests include the statements of every user, reading them requires the PROCESS privilege.
+type QueryDigestsTable struct {
+	store *querydigest.Store
+}
+
+var _ sql.Table = QueryDigestsTable{}
+
+// NewQueryDigestsTable returns a QueryDigestsTable of the statistics in |store|.
+func NewQueryDigestsTable(store *querydigest.Store) QueryDigestsTable {
+	return QueryDigestsTable{store: store}
+}
+
+// Name implements the interface sql.Table.
+func (t QueryDigestsTable) Name() string {
+	return QueryDigestsTableName
+}
+
+// String implements the interface sql.Table.
+func (t QueryDigestsTable) String() string {
+	return QueryDigestsTableName
+}
+
+// Schema implements the interface sql.Table.
+func (t QueryDigestsTable) Schema() sql.Schema {
+	return queryDigestsSchema
+}
+
+// Collation implements the interface sql.Table.
+func (t QueryDigestsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions implements the interface sql.Table.
+func (t QueryDigestsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows implements the interface sql.Table.
+func (t QueryDigestsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	privs, counter := ctx.GetPrivilegeSet()
+	if counter == 0 {
+		return nil, fmt.Errorf("unable to check user privileges for %s", QueryDigestsTableName)
+	}
+	if !privs.Has(sql.PrivilegeType_Process) {
+		return nil, sql.ErrPrivilegeCheckFailed.New(ctx.Session.Client().User)
+	}
+
+	summaries := t.store.Summaries()
+	rows := make([]sql.Row, len(summaries))
+	for i, s := range summaries {
+		row := sql.Row{nil, nil, nil, s.Count, s.Errors,
+			uint64(s.TotalLatency.Microseconds()), uint64(s.AvgLatency().Microseconds()),
+			uint64(s.P99Latency.Microseconds()), uint64(s.MaxLatency.Microseconds()),
+			s.RowsExamined, s.RowsSent, s.FirstSeen.UTC(), s.LastSeen.UTC()}
+		if s.Digest != "" {
+			row[0], row[1], row[2] = s.Digest, s.Database, s.Text
+		}
+		rows[i] = row
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
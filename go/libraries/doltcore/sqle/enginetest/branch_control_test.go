@@ -1365,55 +1365,60 @@ var BranchControlTests = []BranchControlTest{
 
 func TestBranchControl(t *testing.T) {
 	for _, test := range BranchControlTests {
-		harness := newDoltHarness(t)
-		defer harness.Close()
-		t.Run(test.Name, func(t *testing.T) {
-			engine, err := harness.NewEngine(t)
-			require.NoError(t, err)
-			defer engine.Close()
+		runBranchControlTest(t, test)
+	}
+}
 
-			ctx := enginetest.NewContext(harness)
-			ctx.NewCtxWithClient(sql.Client{
-				User:    "root",
-				Address: "localhost",
-			})
-			engine.EngineAnalyzer().Catalog.MySQLDb.AddRootAccount()
-			engine.EngineAnalyzer().Catalog.MySQLDb.SetPersister(&mysql_db.NoopPersister{})
+// runBranchControlTest runs |test| against a new engine, running each assertion as its user.
+func runBranchControlTest(t *testing.T, test BranchControlTest) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	t.Run(test.Name, func(t *testing.T) {
+		engine, err := harness.NewEngine(t)
+		require.NoError(t, err)
+		defer engine.Close()
 
-			for _, statement := range test.SetUpScript {
-				enginetest.RunQueryWithContext(t, engine, harness, ctx, statement)
+		ctx := enginetest.NewContext(harness)
+		ctx.NewCtxWithClient(sql.Client{
+			User:    "root",
+			Address: "localhost",
+		})
+		engine.EngineAnalyzer().Catalog.MySQLDb.AddRootAccount()
+		engine.EngineAnalyzer().Catalog.MySQLDb.SetPersister(&mysql_db.NoopPersister{})
+
+		for _, statement := range test.SetUpScript {
+			enginetest.RunQueryWithContext(t, engine, harness, ctx, statement)
+		}
+
+		for _, assertion := range test.Assertions {
+			user := assertion.User
+			host := assertion.Host
+			if user == "" {
+				user = "root"
 			}
+			if host == "" {
+				host = "localhost"
+			}
+			ctx = ctx.NewCtxWithClient(sql.Client{
+				User:    user,
+				Address: host,
+			})
 
-			for _, assertion := range test.Assertions {
-				user := assertion.User
-				host := assertion.Host
-				if user == "" {
-					user = "root"
-				}
-				if host == "" {
-					host = "localhost"
-				}
-				ctx = ctx.NewCtxWithClient(sql.Client{
-					User:    user,
-					Address: host,
+			if assertion.ExpectedErr != nil {
+				t.Run(assertion.Query, func(t *testing.T) {
+					enginetest.AssertErrWithCtx(t, engine, harness, ctx, assertion.Query, nil, assertion.ExpectedErr)
+				})
+			} else if assertion.ExpectedErrStr != "" {
+				t.Run(assertion.Query, func(t *testing.T) {
+					enginetest.AssertErrWithCtx(t, engine, harness, ctx, assertion.Query, nil, nil, assertion.ExpectedErrStr)
+				})
+			} else {
+				t.Run(assertion.Query, func(t *testing.T) {
+					enginetest.TestQueryWithContext(t, ctx, engine, harness, assertion.Query, assertion.Expected, nil, nil, nil)
 				})
-
-				if assertion.ExpectedErr != nil {
-					t.Run(assertion.Query, func(t *testing.T) {
-						enginetest.AssertErrWithCtx(t, engine, harness, ctx, assertion.Query, nil, assertion.ExpectedErr)
-					})
-				} else if assertion.ExpectedErrStr != "" {
-					t.Run(assertion.Query, func(t *testing.T) {
-						enginetest.AssertErrWithCtx(t, engine, harness, ctx, assertion.Query, nil, nil, assertion.ExpectedErrStr)
-					})
-				} else {
-					t.Run(assertion.Query, func(t *testing.T) {
-						enginetest.TestQueryWithContext(t, ctx, engine, harness, assertion.Query, assertion.Expected, nil, nil, nil)
-					})
-				}
 			}
-		})
-	}
+		}
+	})
 }
 
 func TestBranchControlBlocks(t *testing.T) {
@@ -0,0 +1,302 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/colmask"
+)
+
+// columnMaskSetUp creates a user that can write to every branch but isn't an admin of any, and a table with each kind of mask.
+var columnMaskSetUp = []string{
+	"DELETE FROM dolt_branch_control WHERE user = '%';",
+	"INSERT INTO dolt_branch_control VALUES ('%', '%', 'root', 'localhost', 'admin');",
+	"INSERT INTO dolt_branch_control VALUES ('%', '%', 'alice', 'localhost', 'write');",
+	"CREATE USER alice@localhost;",
+	"GRANT SELECT, INSERT, UPDATE, DELETE, EXECUTE ON mydb.* TO alice@localhost;",
+	"CREATE TABLE people (id INT PRIMARY KEY, name VARCHAR(32), email VARCHAR(64), ssn VARCHAR(11), salary INT);",
+	"INSERT INTO people VALUES (1, 'ann', 'ann@example.com', '123-45-6789', 100), (2, 'bo', NULL, '987-65-4321', 200);",
+	"CALL dolt_create_column_mask('people', 'email', '--type', 'hash');",
+	"CALL dolt_create_column_mask('people', 'ssn', '--type', 'partial', '--visible-suffix', '4');",
+	"CALL dolt_create_column_mask('people', 'salary');",
+	"CALL dolt_commit('-Am', 'masks');",
+}
+
+var ColumnMaskTests = []BranchControlTest{
+	{
+		Name:        "masks apply to reads",
+		SetUpScript: columnMaskSetUp,
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:  "alice",
+				Host:  "localhost",
+				Query: "SELECT * FROM people ORDER BY id;",
+				Expected: []sql.Row{
+					{1, "ann", "71d4f55f72fa128dfb468a1a3901507c804b74316488744d769d7f4b16696476", "*******6789", 0},
+					{2, "bo", nil, "*******4321", 0},
+				},
+			},
+			{ // masks don't apply to admins
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT email, ssn, salary FROM people WHERE id = 1;",
+				Expected: []sql.Row{{"ann@example.com", "123-45-6789", 100}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT ssn FROM people WHERE id = 2;",
+				Expected: []sql.Row{{"*******4321"}},
+			},
+			{ // filters see the masked values
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT id FROM people WHERE salary > 0;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM people;",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT ssn FROM people AS OF 'HEAD' WHERE id = 1;",
+				Expected: []sql.Row{{"*******6789"}},
+			},
+			{ // admins can ask for masked values
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SET dolt_mask_columns = 1;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT ssn, salary FROM people WHERE id = 1;",
+				Expected: []sql.Row{{"*******6789", 0}},
+			},
+		},
+	},
+	{
+		Name:        "masked tables can't be written or read through their history",
+		SetUpScript: columnMaskSetUp,
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "UPDATE people SET name = 'anne' WHERE id = 1;",
+				ExpectedErr: colmask.ErrMaskedWrite,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "DELETE FROM people WHERE id = 1;",
+				ExpectedErr: colmask.ErrMaskedWrite,
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "INSERT INTO people VALUES (3, 'cy', 'cy@example.com', '555-55-5555', 300);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_history_people;",
+				ExpectedErr: colmask.ErrMaskedHistory,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_diff_people;",
+				ExpectedErr: colmask.ErrMaskedHistory,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_diff('HEAD', 'WORKING', 'people');",
+				ExpectedErr: colmask.ErrMaskedHistory,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_system_time('people', 'all', NULL, NULL);",
+				ExpectedErr: colmask.ErrMaskedHistory,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM people FOR SYSTEM_TIME ALL;",
+				ExpectedErr: colmask.ErrMaskedHistory,
+			},
+			{ // change events hold the unmasked before and after images of the rows
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "CALL dolt_cdc();",
+				ExpectedErr: colmask.ErrMaskedHistory,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_history_people;",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL dolt_branch('other');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "USE `mydb/other`;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "UPDATE people SET ssn = '111-11-1111' WHERE id = 2;",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL dolt_commit('-am', 'ssn');",
+				Expected: []sql.Row{{doltCommit}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "USE mydb;",
+				Expected: []sql.Row{},
+			},
+			{ // the summary doesn't hold any values of the rows
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_merge_preview('other');",
+				Expected: []sql.Row{{"people", int64(0), int64(1), int64(0), int64(0), int64(0), int64(0)}},
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_merge_preview('other', '--detail');",
+				ExpectedErr: colmask.ErrMaskedHistory,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT table_name, change_type FROM dolt_merge_preview('other', '--detail');",
+				Expected: []sql.Row{{"people", "modified"}},
+			},
+		},
+	},
+	{
+		Name:        "only admins can change masks",
+		SetUpScript: columnMaskSetUp,
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "CALL dolt_drop_column_mask('people', 'ssn');",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "DELETE FROM dolt_column_masks;",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:  "alice",
+				Host:  "localhost",
+				Query: "SELECT * FROM dolt_column_masks ORDER BY column_name;",
+				Expected: []sql.Row{
+					{"people", "email", "hash", 0, 0},
+					{"people", "salary", "redact", 0, 0},
+					{"people", "ssn", "partial", 0, 4},
+				},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL dolt_drop_column_mask('people', 'ssn');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT ssn FROM people WHERE id = 1;",
+				Expected: []sql.Row{{"123-45-6789"}},
+			},
+		},
+	},
+	{
+		Name: "invalid masks",
+		SetUpScript: []string{
+			"CREATE TABLE t (id INT PRIMARY KEY, s VARCHAR(10));",
+			"CALL dolt_create_column_mask('t', 's');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				Query:       "CALL dolt_create_column_mask('t', 's', '--type', 'hash');",
+				ExpectedErr: colmask.ErrMaskExists,
+			},
+			{
+				Query:       "CALL dolt_create_column_mask('t', 'id', '--type', 'hash');",
+				ExpectedErr: colmask.ErrStringColumnRequired,
+			},
+			{
+				Query:       "CALL dolt_create_column_mask('t', 'id', '--type', 'shuffle');",
+				ExpectedErr: colmask.ErrInvalidMaskType,
+			},
+			{
+				Query:       "CALL dolt_create_column_mask('t', 'id', '--visible-prefix', '2');",
+				ExpectedErr: colmask.ErrInvalidVisibleLength,
+			},
+			{
+				Query:       "CALL dolt_create_column_mask('nope', 'id');",
+				ExpectedErr: colmask.ErrTableNotFound,
+			},
+			{
+				Query:       "CALL dolt_create_column_mask('t', 'nope');",
+				ExpectedErr: colmask.ErrColumnNotFound,
+			},
+			{
+				Query:       "CALL dolt_create_column_mask('dolt_log', 'message');",
+				ExpectedErr: colmask.ErrSystemTable,
+			},
+			{
+				Query:       "CALL dolt_drop_column_mask('t', 'id');",
+				ExpectedErr: colmask.ErrMaskNotFound,
+			},
+		},
+	},
+}
+
+func TestColumnMasks(t *testing.T) {
+	for _, test := range ColumnMaskTests {
+		runBranchControlTest(t, test)
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/statspro"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
@@ -910,6 +911,81 @@ func TestFulltextIndexes(t *testing.T) {
 	enginetest.TestFulltextIndexes(t, h)
 }
 
+func TestVectorIndexes(t *testing.T) {
+	skipOldFormat(t)
+	h := newDoltHarness(t)
+	defer h.Close()
+	enginetest.TestVectorIndexes(t, h)
+}
+
+func TestDoltVectorIndexes(t *testing.T) {
+	skipOldFormat(t)
+	h := newDoltEnginetestHarness(t)
+	RunDoltVectorIndexTests(t, h)
+}
+
+func TestDoltCdc(t *testing.T) {
+	skipOldFormat(t)
+	harness := newDoltHarness(t)
+	harness.Setup(setup.MydbData)
+	engine := mustNewEngine(t, harness)
+	defer engine.Close()
+
+	for _, q := range []string{
+		"create table t (pk int primary key, c1 varchar(10))",
+		"insert into t values (1, 'a'), (2, 'b')",
+		"call dolt_commit('-Am', 'first')",
+		"update t set c1 = 'c' where pk = 2",
+		"delete from t where pk = 1",
+		"call dolt_commit('-am', 'second')",
+	} {
+		enginetest.RunQueryWithContext(t, engine, harness, nil, q)
+	}
+
+	ops := func(q string) []string {
+		ctx := enginetest.NewContext(harness)
+		_, iter, _, err := engine.Query(ctx, q)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(ctx, iter)
+		require.NoError(t, err)
+		ops := make([]string, len(rows))
+		for i, row := range rows {
+			require.Equal(t, "t", row[1])
+			ops[i] = row[2].(string)
+		}
+		return ops
+	}
+	require.Equal(t, []string{"c", "c", "d", "u"}, ops("call dolt_cdc()"))
+	require.Equal(t, []string{"d", "u"}, ops("call dolt_cdc('--since', 'HEAD~1')"))
+	require.Empty(t, ops("call dolt_cdc('--since', 'HEAD')"))
+
+	// events are streamed, so a consumer can stop reading before the end of the history
+	ctx := enginetest.NewContext(harness)
+	_, iter, _, err := engine.Query(ctx, "call dolt_cdc()")
+	require.NoError(t, err)
+	_, err = iter.Next(ctx)
+	require.NoError(t, err)
+	require.NoError(t, iter.Close(ctx))
+}
+
+func TestDoltMaterializedViews(t *testing.T) {
+	skipOldFormat(t)
+	h := newDoltEnginetestHarness(t)
+	RunDoltMaterializedViewTests(t, h)
+}
+
+func TestDoltSystemTime(t *testing.T) {
+	skipOldFormat(t)
+	h := newDoltEnginetestHarness(t)
+	RunDoltSystemTimeTests(t, h)
+}
+
+func TestDoltMergePreview(t *testing.T) {
+	skipOldFormat(t)
+	h := newDoltEnginetestHarness(t)
+	RunDoltMergePreviewTests(t, h)
+}
+
 func TestCreateCheckConstraints(t *testing.T) {
 	h := newDoltHarness(t)
 	defer h.Close()
@@ -1227,6 +1303,49 @@ func TestDoltRebase(t *testing.T) {
 	RunDoltRebaseTests(t, h)
 }
 
+// TestDoltRebaseExecPrivileges tests that the statements of exec steps are checked against the privileges of the user
+// running the rebase.
+func TestDoltRebaseExecPrivileges(t *testing.T) {
+	skipOldFormat(t)
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	harness.Setup(setup.MydbData)
+	engine, err := harness.NewEngine(t)
+	require.NoError(t, err)
+	defer engine.Close()
+
+	engine.EngineAnalyzer().Catalog.MySQLDb.AddRootAccount()
+	engine.EngineAnalyzer().Catalog.MySQLDb.SetPersister(&mysql_db.NoopPersister{})
+	ctx := enginetest.NewContextWithClient(harness, sql.Client{User: "root", Address: "localhost"})
+	for _, statement := range []string{
+		"create database secret;",
+		"create table secret.s (pk int primary key);",
+		"insert into secret.s values (1);",
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"call dolt_branch('branch1');",
+		"insert into t values (0);",
+		"call dolt_commit('-am', 'inserting row 0');",
+		"create user tester@localhost;",
+		"grant all on mydb.* to tester@localhost;",
+	} {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, statement)
+	}
+
+	ctx = enginetest.NewContextWithClient(harness, sql.Client{User: "tester", Address: "localhost"})
+	for _, statement := range []string{
+		"call dolt_checkout('branch1');",
+		"insert into t values (1);",
+		"call dolt_commit('-am', 'inserting row 1');",
+		"call dolt_rebase('-i', 'main');",
+		"insert into dolt_rebase values (1.5, 'exec', '', 'select count(*) = 1 from secret.s');",
+	} {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, statement)
+	}
+	enginetest.AssertErrWithCtx(t, engine, harness, ctx, "call dolt_rebase('--continue');", nil, dprocedures.ErrRebaseExecFailed,
+		dprocedures.ErrRebaseExecFailed.New("1.5", sql.ErrDatabaseAccessDeniedForUser.New("'tester'@'localhost'", "secret").Error()).Error())
+}
+
 func TestDoltRebasePrepared(t *testing.T) {
 	h := newDoltHarness(t)
 	RunDoltRebasePreparedTests(t, h)
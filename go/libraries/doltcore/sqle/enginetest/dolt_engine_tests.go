@@ -894,6 +894,54 @@ func RunDoltRebasePreparedTests(t *testing.T, h DoltEnginetestHarness) {
 	}
 }
 
+func RunDoltVectorIndexTests(t *testing.T, h DoltEnginetestHarness) {
+	for _, script := range DoltVectorIndexScripts {
+		// harness can't reset effectively when there are new commits / branches created, so use a new harness for
+		// each script
+		func() {
+			h := h.NewHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func RunDoltMaterializedViewTests(t *testing.T, h DoltEnginetestHarness) {
+	for _, script := range DoltMaterializedViewScripts {
+		// harness can't reset effectively when there are new commits / branches created, so use a new harness for
+		// each script
+		func() {
+			h := h.NewHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func RunDoltSystemTimeTests(t *testing.T, h DoltEnginetestHarness) {
+	for _, script := range DoltSystemTimeScripts {
+		// harness can't reset effectively when there are new commits / branches created, so use a new harness for
+		// each script
+		func() {
+			h := h.NewHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func RunDoltMergePreviewTests(t *testing.T, h DoltEnginetestHarness) {
+	for _, script := range DoltMergePreviewScripts {
+		// harness can't reset effectively when there are new commits / branches created, so use a new harness for
+		// each script
+		func() {
+			h := h.NewHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
 func RunDoltRevertTests(t *testing.T, h DoltEnginetestHarness) {
 	for _, script := range RevertScripts {
 		// harness can't reset effectively. Use a new harness for each script
@@ -1844,6 +1892,7 @@ func RunThreeWayMergeWithSchemaChangeScripts(t *testing.T, h DoltEnginetestHarne
 	runMergeScriptTestsInBothDirections(t, SchemaChangeTestsSchemaConflicts, "schema conflicts", false)
 	runMergeScriptTestsInBothDirections(t, SchemaChangeTestsGeneratedColumns, "generated columns", false)
 	runMergeScriptTestsInBothDirections(t, SchemaChangeTestsForJsonConflicts, "json merge", false)
+	runMergeScriptTestsInBothDirections(t, SchemaChangeTestsPrimaryKeys, "primary key changes", false)
 
 	// Run non-symmetric schema merge tests in just one direction
 	t.Run("type changes", func(t *testing.T) {
@@ -1867,6 +1916,7 @@ func RunThreeWayMergeWithSchemaChangeScriptsPrepared(t *testing.T, h DoltEnginet
 	runMergeScriptTestsInBothDirections(t, SchemaChangeTestsSchemaConflicts, "schema conflicts", false)
 	runMergeScriptTestsInBothDirections(t, SchemaChangeTestsGeneratedColumns, "generated columns", false)
 	runMergeScriptTestsInBothDirections(t, SchemaChangeTestsForJsonConflicts, "json merge", false)
+	runMergeScriptTestsInBothDirections(t, SchemaChangeTestsPrimaryKeys, "primary key changes", false)
 
 	// Run non-symmetric schema merge tests in just one direction
 	t.Run("type changes", func(t *testing.T) {
@@ -253,6 +253,8 @@ func (d *DoltHarness) NewEngine(t *testing.T) (enginetest.QueryEngine, error) {
 			return nil, err
 		}
 		e.Analyzer.ExecBuilder = rowexec.NewOverrideBuilder(kvexec.Builder{})
+		e.Parser = sqle.NewParser(e.Parser)
+		doltProvider.SetStatementRunner(e)
 		d.engine = e
 
 		ctx := enginetest.NewContext(d)
@@ -433,6 +435,8 @@ func (d *DoltHarness) NewDatabases(names ...string) []sql.Database {
 
 	e := enginetest.NewEngineWithProvider(d.t, d, d.provider)
 	require.NoError(d.t, err)
+	e.Parser = sqle.NewParser(e.Parser)
+	doltProvider.SetStatementRunner(e)
 	d.engine = e
 
 	for _, name := range names {
@@ -486,7 +490,10 @@ func (d *DoltHarness) NewReadOnlyEngine(provider sql.DatabaseProvider) (enginete
 	d.session, err = dsess.NewDoltSession(enginetest.NewBaseSession(), readOnlyProvider, d.multiRepoEnv.Config(), d.branchControl, d.statsPro, writer.NewWriteSession)
 	require.NoError(d.t, err)
 
-	return enginetest.NewEngineWithProvider(nil, d, readOnlyProvider), nil
+	e := enginetest.NewEngineWithProvider(nil, d, readOnlyProvider)
+	e.Parser = sqle.NewParser(e.Parser)
+	readOnlyProvider.SetStatementRunner(e)
+	return e, nil
 }
 
 func (d *DoltHarness) NewDatabaseProvider() sql.MutableDatabaseProvider {
@@ -581,6 +588,7 @@ func (d *DoltHarness) NewTableAsOf(db sql.VersionedDatabase, name string, schema
 // branch with the given name.
 func (d *DoltHarness) SnapshotTable(db sql.VersionedDatabase, tableName string, asOf interface{}) error {
 	e := enginetest.NewEngineWithProvider(d.t, d, d.NewDatabaseProvider())
+	e.Parser = sqle.NewParser(e.Parser)
 
 	asOfString, ok := asOf.(string)
 	require.True(d.t, ok)
@@ -24,6 +24,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/types"
 	"github.com/dolthub/vitess/go/vt/sqlparser"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtablefunctions"
 )
 
@@ -6006,7 +6007,7 @@ var DoltCherryPickTests = []queries.ScriptTest{
 		},
 	},
 	{
-		Name: "error cases: merge commits cannot be cherry-picked",
+		Name: "error cases: merge commits require a mainline parent",
 		SetUpScript: []string{
 			"create table t (pk int primary key, v varchar(100));",
 			"call dolt_commit('-Am', 'create table t');",
@@ -6023,7 +6024,23 @@ var DoltCherryPickTests = []queries.ScriptTest{
 			},
 			{
 				Query:          "CALL dolt_cherry_pick('HEAD');",
-				ExpectedErrStr: "cherry-picking a merge commit is not supported",
+				ExpectedErrStr: "cherry-picking a merge commit requires choosing a parent with --mainline",
+			},
+			{
+				Query:          "CALL dolt_cherry_pick('-m', '3', 'HEAD');",
+				ExpectedErrStr: "invalid mainline parent number 3: commit has 2 parents",
+			},
+			{
+				Query:          "CALL dolt_cherry_pick('-m', '0', 'HEAD');",
+				ExpectedErrStr: "error: --mainline must be a parent number of at least 1",
+			},
+			{
+				Query:          "CALL dolt_cherry_pick('HEAD^2..HEAD');",
+				ExpectedErrStr: "cherry-picking a merge commit requires choosing a parent with --mainline",
+			},
+			{
+				Query:          "CALL dolt_cherry_pick('HEAD..HEAD');",
+				ExpectedErrStr: "error: no commits to cherry-pick in HEAD..HEAD",
 			},
 		},
 	},
@@ -6055,20 +6072,31 @@ var DoltCherryPickTests = []queries.ScriptTest{
 		},
 	},
 	{
-		Name: "error cases: different primary keys",
+		Name: "cherry-pick a primary key change",
 		SetUpScript: []string{
 			"create table t (pk int primary key, v varchar(100));",
+			"insert into t values (1, 'one');",
 			"call dolt_commit('-Am', 'create table t');",
 			"call dolt_checkout('-b', 'branch1');",
 			"ALTER TABLE t DROP PRIMARY KEY, ADD PRIMARY KEY (pk, v);",
 			"call dolt_commit('-am', 'adding row 1');",
 			"set @commit1 = hashof('HEAD');",
 			"call dolt_checkout('main');",
+			"insert into t values (2, 'two');",
+			"call dolt_commit('-am', 'adding row 2');",
 		},
 		Assertions: []queries.ScriptTestAssertion{
 			{
-				Query:          "CALL Dolt_Cherry_Pick(@commit1);",
-				ExpectedErrStr: "error: cannot merge because table t has different primary keys",
+				Query:    "CALL Dolt_Cherry_Pick(@commit1);",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select column_name from information_schema.statistics where table_name = 't' and index_name = 'PRIMARY' order by seq_in_index;",
+				Expected: []sql.Row{{"pk"}, {"v"}},
+			},
+			{
+				Query:    "SELECT * FROM t order by pk;",
+				Expected: []sql.Row{{1, "one"}, {2, "two"}},
 			},
 		},
 	},
@@ -6114,6 +6142,112 @@ var DoltCherryPickTests = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "cherry-pick a merge commit relative to a mainline parent",
+		SetUpScript: []string{
+			"create table t (pk int primary key, v varchar(100));",
+			"call dolt_commit('-Am', 'create table t');",
+			"call dolt_branch('maint');",
+			"call dolt_checkout('-b', 'feature');",
+			"insert into t values (1, 'one');",
+			"call dolt_commit('-am', 'adding row 1');",
+			"insert into t values (2, 'two');",
+			"call dolt_commit('-am', 'adding row 2');",
+			"call dolt_checkout('main');",
+			"insert into t values (10, 'ten');",
+			"call dolt_commit('-am', 'adding row 10');",
+			"call dolt_merge('--no-ff', '-m', 'merge feature', 'feature');",
+			"call dolt_checkout('maint');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_cherry_pick('-m', '1', 'main');",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, "one"}, {2, "two"}},
+			},
+			{
+				Query:    "select message from dolt_log limit 1;",
+				Expected: []sql.Row{{"merge feature"}},
+			},
+			{
+				// Assert that our new commit only has one parent (i.e. not a merge commit)
+				Query:    "select count(*) from dolt_commit_ancestors where commit_hash = hashof('HEAD');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "call dolt_reset('--hard', 'HEAD~1');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_cherry_pick('--mainline', '2', 'main');",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{10, "ten"}},
+			},
+		},
+	},
+	{
+		Name: "cherry-pick multiple commits and commit ranges",
+		SetUpScript: []string{
+			"create table t (pk int primary key, v varchar(100));",
+			"call dolt_commit('-Am', 'create table t');",
+			"call dolt_checkout('-b', 'branch1');",
+			"insert into t values (1, 'one');",
+			"call dolt_commit('-am', 'adding row 1');",
+			"insert into t values (2, 'two');",
+			"call dolt_commit('-am', 'adding row 2');",
+			"insert into t values (3, 'three');",
+			"call dolt_commit('-am', 'adding row 3');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_cherry_pick('branch1~2..branch1');",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{2, "two"}, {3, "three"}},
+			},
+			{
+				Query:    "select message from dolt_log limit 3;",
+				Expected: []sql.Row{{"adding row 3"}, {"adding row 2"}, {"create table t"}},
+			},
+			{
+				Query:    "call dolt_reset('--hard', 'HEAD~2');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_cherry_pick('main..branch1');",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select message from dolt_log limit 4;",
+				Expected: []sql.Row{{"adding row 3"}, {"adding row 2"}, {"adding row 1"}, {"create table t"}},
+			},
+			{
+				Query:    "call dolt_reset('--hard', 'HEAD~3');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_cherry_pick('branch1', 'branch1~2');",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, "one"}, {3, "three"}},
+			},
+			{
+				Query:    "select message from dolt_log limit 3;",
+				Expected: []sql.Row{{"adding row 1"}, {"adding row 3"}, {"create table t"}},
+			},
+		},
+	},
 	{
 		Name: "keyless table",
 		SetUpScript: []string{
@@ -6483,6 +6617,121 @@ var DoltCherryPickTests = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "continue a range cherry-pick that stopped with conflicts",
+		SetUpScript: []string{
+			"set @@autocommit=1;",
+			"SET @@dolt_allow_commit_conflicts=1;",
+			"create table t (pk int primary key, c1 varchar(100));",
+			"insert into t values (1, 'one');",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_checkout('-b', 'branch1');",
+			"insert into t values (2, 'two');",
+			"call dolt_commit('-am', 'inserting row 2');",
+			"update t set c1 = 'uno' where pk = 1;",
+			"call dolt_commit('-am', 'updating row 1 -> uno');",
+			"insert into t values (3, 'three');",
+			"call dolt_commit('-am', 'inserting row 3');",
+			"insert into t values (4, 'four');",
+			"call dolt_commit('-am', 'inserting row 4');",
+			"call dolt_checkout('main');",
+			"update t set c1 = 'ein' where pk = 1;",
+			"call dolt_commit('-am', 'updating row 1 -> ein');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_cherry_pick('--continue');",
+				ExpectedErrStr: dprocedures.ErrNoCherryPickInProgress.Error(),
+			},
+			{
+				Query:    "call dolt_cherry_pick('main..branch1');",
+				Expected: []sql.Row{{"", 1, 0, 0}},
+			},
+			{
+				Query:    "select message from dolt_log limit 2;",
+				Expected: []sql.Row{{"inserting row 2"}, {"updating row 1 -> ein"}},
+			},
+			{
+				Query:          "call dolt_cherry_pick('--continue');",
+				ExpectedErrStr: dprocedures.ErrCherryPickUnresolvedConflicts.Error(),
+			},
+			{
+				Query:    "call dolt_conflicts_resolve('--theirs', 't');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_cherry_pick('--continue');",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, "uno"}, {2, "two"}, {3, "three"}, {4, "four"}},
+			},
+			{
+				Query: "select message from dolt_log limit 5;",
+				Expected: []sql.Row{
+					{"inserting row 4"},
+					{"inserting row 3"},
+					{"updating row 1 -> uno"},
+					{"inserting row 2"},
+					{"updating row 1 -> ein"},
+				},
+			},
+			{
+				// the resolved commit only has one parent (i.e. is not a merge commit)
+				Query:    "select count(*) from dolt_commit_ancestors where commit_hash = hashof('HEAD~2');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from dolt_status;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "call dolt_cherry_pick('--continue');",
+				ExpectedErrStr: dprocedures.ErrNoCherryPickInProgress.Error(),
+			},
+		},
+	},
+	{
+		Name: "continue a range cherry-pick whose resolved commit has no changes left",
+		SetUpScript: []string{
+			"set @@autocommit=1;",
+			"SET @@dolt_allow_commit_conflicts=1;",
+			"create table t (pk int primary key, c1 varchar(100));",
+			"insert into t values (1, 'one');",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_checkout('-b', 'branch1');",
+			"update t set c1 = 'uno' where pk = 1;",
+			"call dolt_commit('-am', 'updating row 1 -> uno');",
+			"insert into t values (2, 'two');",
+			"call dolt_commit('-am', 'inserting row 2');",
+			"call dolt_checkout('main');",
+			"update t set c1 = 'ein' where pk = 1;",
+			"call dolt_commit('-am', 'updating row 1 -> ein');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_cherry_pick('main..branch1');",
+				Expected: []sql.Row{{"", 1, 0, 0}},
+			},
+			{
+				Query:    "call dolt_conflicts_resolve('--ours', 't');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_cherry_pick('--continue');",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, "ein"}, {2, "two"}},
+			},
+			{
+				Query:    "select message from dolt_log limit 2;",
+				Expected: []sql.Row{{"inserting row 2"}, {"updating row 1 -> ein"}},
+			},
+		},
+	},
 	{
 		Name: "abort (@@autocommit=1) with ignored table",
 		SetUpScript: []string{
@@ -0,0 +1,411 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"github.com/dolthub/go-mysql-server/enginetest/queries"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/matview"
+)
+
+var DoltMaterializedViewScripts = []queries.ScriptTest{
+	{
+		Name: "filtered projection is refreshed incrementally",
+		SetUpScript: []string{
+			"create table orders (id int primary key, region varchar(10) not null, amount int not null, note varchar(20));",
+			"insert into orders values (1, 'east', 50, null), (2, 'west', 150, 'rush'), (3, 'east', 200, null);",
+			"call dolt_create_materialized_view('big_orders', 'select id, region, amount * 2 as doubled from orders where amount >= 100');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from big_orders order by id;",
+				Expected: []sql.Row{{2, "west", 300}, {3, "east", 400}},
+			},
+			{
+				Query:    "select name, definition, auto_refresh from dolt_materialized_views;",
+				Expected: []sql.Row{{"big_orders", "select id, region, amount * 2 as doubled from orders where amount >= 100", 0}},
+			},
+			{
+				Query:    "call dolt_refresh_materialized_view('big_orders');",
+				Expected: []sql.Row{{"big_orders", "unchanged"}},
+			},
+			{
+				Query:    "insert into orders values (4, 'north', 120, null), (5, 'south', 10, null);",
+				Expected: []sql.Row{{types.NewOkResult(2)}},
+			},
+			{
+				Query:    "update orders set amount = 500 where id = 1;",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "update orders set amount = 20 where id = 2;",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "delete from orders where id = 3;",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from big_orders order by id;",
+				Expected: []sql.Row{{2, "west", 300}, {3, "east", 400}},
+			},
+			{
+				Query:    "call dolt_refresh_materialized_view('big_orders');",
+				Expected: []sql.Row{{"big_orders", matview.RefreshIncremental}},
+			},
+			{
+				Query:    "select * from big_orders order by id;",
+				Expected: []sql.Row{{1, "east", 1000}, {4, "north", 240}},
+			},
+			{
+				// changing a column which is not selected does not change the view
+				Query:    "update orders set note = 'gift' where id = 4;",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "call dolt_refresh_materialized_view('big_orders');",
+				Expected: []sql.Row{{"big_orders", matview.RefreshIncremental}},
+			},
+			{
+				Query:    "select * from big_orders order by id;",
+				Expected: []sql.Row{{1, "east", 1000}, {4, "north", 240}},
+			},
+		},
+	},
+	{
+		Name: "grouped counts and sums are refreshed incrementally",
+		SetUpScript: []string{
+			"create table sales (id int primary key, region varchar(10) not null, amount int not null);",
+			"insert into sales values (1, 'east', 10), (2, 'east', 20), (3, 'west', 5);",
+			"call dolt_create_materialized_view('sales_by_region', 'select region, count(*) as n, sum(amount) as total from sales group by region');",
+			"call dolt_create_materialized_view('sales_total', 'select count(*) as n, sum(amount) as total from sales');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select region, n, cast(total as signed) from sales_by_region order by region;",
+				Expected: []sql.Row{{"east", 2, 30}, {"west", 1, 5}},
+			},
+			{
+				Query:    "select n, cast(total as signed) from sales_total;",
+				Expected: []sql.Row{{3, 35}},
+			},
+			{
+				Query:    "insert into sales values (4, 'north', 7), (5, 'east', 1);",
+				Expected: []sql.Row{{types.NewOkResult(2)}},
+			},
+			{
+				Query:    "delete from sales where id = 3;",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "update sales set amount = 100 where id = 1;",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "call dolt_refresh_materialized_view('--all');",
+				Expected: []sql.Row{{"sales_by_region", matview.RefreshIncremental}, {"sales_total", matview.RefreshIncremental}},
+			},
+			{
+				Query:    "select region, n, cast(total as signed) from sales_by_region order by region;",
+				Expected: []sql.Row{{"east", 3, 121}, {"north", 1, 7}},
+			},
+			{
+				Query:    "select n, cast(total as signed) from sales_total;",
+				Expected: []sql.Row{{4, 128}},
+			},
+			{
+				Query:    "delete from sales;",
+				Expected: []sql.Row{{types.NewOkResult(4)}},
+			},
+			{
+				Query:    "call dolt_refresh_materialized_view('sales_by_region', 'sales_total');",
+				Expected: []sql.Row{{"sales_by_region", matview.RefreshIncremental}, {"sales_total", matview.RefreshIncremental}},
+			},
+			{
+				Query:    "select * from sales_by_region;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select n, total from sales_total;",
+				Expected: []sql.Row{{0, nil}},
+			},
+		},
+	},
+	{
+		Name: "views which cannot be maintained incrementally are recomputed",
+		SetUpScript: []string{
+			"create table sales (id int primary key, region varchar(10) not null, amount int not null);",
+			"insert into sales values (1, 'east', 10), (2, 'east', 20), (3, 'west', 5);",
+			"call dolt_create_materialized_view('max_sale', 'select region, max(amount) as biggest from sales group by region');",
+			"call dolt_create_materialized_view('east_sales', 'select id, amount from sales where region = ''east''');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from max_sale order by region;",
+				Expected: []sql.Row{{"east", 20}, {"west", 5}},
+			},
+			{
+				Query:    "delete from sales where id = 2;",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "call dolt_refresh_materialized_view('max_sale');",
+				Expected: []sql.Row{{"max_sale", matview.RefreshFull}},
+			},
+			{
+				Query:    "select * from max_sale order by region;",
+				Expected: []sql.Row{{"east", 10}, {"west", 5}},
+			},
+			{
+				// a schema change to the source table makes the previous version impossible to diff against
+				Query:    "alter table sales add column note text;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "insert into sales values (6, 'east', 60, 'big');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "call dolt_refresh_materialized_view('east_sales');",
+				Expected: []sql.Row{{"east_sales", matview.RefreshFull}},
+			},
+			{
+				Query:    "select * from east_sales order by id;",
+				Expected: []sql.Row{{1, 10}, {6, 60}},
+			},
+		},
+	},
+	{
+		Name: "auto refreshed views are refreshed on commit",
+		SetUpScript: []string{
+			"create table sales (id int primary key, region varchar(10) not null, amount int not null);",
+			"insert into sales values (1, 'east', 10), (2, 'west', 20);",
+			"call dolt_create_materialized_view('--auto-refresh', 'sales_count', 'select region, count(*) as n from sales group by region');",
+			"call dolt_create_materialized_view('manual_count', 'select count(*) as n from sales');",
+			"call dolt_commit('-Am', 'create views');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select name, auto_refresh from dolt_materialized_views order by name;",
+				Expected: []sql.Row{{"manual_count", 0}, {"sales_count", 1}},
+			},
+			{
+				Query:    "insert into sales values (3, 'east', 30);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:            "call dolt_commit('-am', 'add a sale');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "select * from sales_count as of 'HEAD' order by region;",
+				Expected: []sql.Row{{"east", 2}, {"west", 1}},
+			},
+			{
+				Query:    "select * from manual_count as of 'HEAD';",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "select * from dolt_status;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "update dolt_materialized_views set auto_refresh = false where name = 'sales_count';",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "insert into sales values (4, 'north', 40);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:            "call dolt_commit('-am', 'add another sale');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "select * from sales_count as of 'HEAD' order by region;",
+				Expected: []sql.Row{{"east", 2}, {"west", 1}},
+			},
+		},
+	},
+	{
+		Name: "only staged changes are refreshed into the commit",
+		SetUpScript: []string{
+			"create table sales (id int primary key, region varchar(10) not null, amount int not null);",
+			"insert into sales values (1, 'east', 10);",
+			"call dolt_create_materialized_view('--auto-refresh', 'sales_count', 'select region, count(*) as n from sales group by region');",
+			"call dolt_commit('-Am', 'create view');",
+			"insert into sales values (2, 'west', 20);",
+			"call dolt_add('sales');",
+			"insert into sales values (3, 'north', 30);",
+			"call dolt_commit('-m', 'add a sale');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from sales_count as of 'HEAD' order by region;",
+				Expected: []sql.Row{{"east", 1}, {"west", 1}},
+			},
+			{
+				Query:    "select * from sales_count order by region;",
+				Expected: []sql.Row{{"east", 1}, {"north", 1}, {"west", 1}},
+			},
+			{
+				Query:    "select table_name, staged, status from dolt_status order by table_name;",
+				Expected: []sql.Row{{"dolt_materialized_views", false, "modified"}, {"sales", false, "modified"}, {"sales_count", false, "modified"}},
+			},
+			{
+				Query:            "call dolt_commit('-am', 'add another sale');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "select * from sales_count as of 'HEAD' order by region;",
+				Expected: []sql.Row{{"east", 1}, {"north", 1}, {"west", 1}},
+			},
+			{
+				Query:    "select * from dolt_status;",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "materialized view statements",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 int not null);",
+			"insert into t values (1, 1), (2, 2), (3, 2);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "create materialized view big as select pk, c1 from t where c1 > 1;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "CREATE MATERIALIZED VIEW `c1 counts` REFRESH ON COMMIT AS SELECT c1, count(*) AS n FROM t GROUP BY c1;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query: "select name, definition, auto_refresh from dolt_materialized_views order by name;",
+				Expected: []sql.Row{
+					{"big", "select pk, c1 from t where c1 > 1", 0},
+					{"c1 counts", "SELECT c1, count(*) AS n FROM t GROUP BY c1", 1},
+				},
+			},
+			{
+				Query:    "select * from `c1 counts` order by c1;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				Query:    "insert into t values (4, 3);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "refresh materialized view big, `c1 counts`;",
+				Expected: []sql.Row{{"big", "incremental"}, {"c1 counts", "incremental"}},
+			},
+			{
+				Query:    "select * from big order by pk;",
+				Expected: []sql.Row{{2, 2}, {3, 2}, {4, 3}},
+			},
+			{
+				Query:          "create materialized view mv as insert into t values (5, 5);",
+				ExpectedErrStr: "materialized views must be defined by a SELECT statement",
+			},
+			{
+				Query:       "refresh materialized view big c1;",
+				ExpectedErr: sql.ErrSyntaxError,
+			},
+			{
+				Query:    "drop materialized view big;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select name from dolt_materialized_views;",
+				Expected: []sql.Row{{"c1 counts"}},
+			},
+		},
+	},
+	{
+		Name: "creating and dropping materialized views",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 int, c2 varchar(10));",
+			"create table u (pk int primary key, c1 int);",
+			"create table keyless (c1 int);",
+			"insert into t values (1, 1, 'a'), (2, 2, 'b');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_create_materialized_view('mv', 'select c1 from t');",
+				ExpectedErrStr: "materialized views must select primary key column pk of t",
+			},
+			{
+				Query:          "call dolt_create_materialized_view('mv', 'select t.pk from t join u on t.pk = u.pk');",
+				ExpectedErrStr: "materialized views do not support a join",
+			},
+			{
+				Query:          "call dolt_create_materialized_view('mv', 'select pk from t order by c1 limit 1');",
+				ExpectedErrStr: "materialized views do not support LIMIT",
+			},
+			{
+				Query:          "call dolt_create_materialized_view('mv', 'select c2, count(*) from t group by c2');",
+				ExpectedErrStr: "materialized views cannot group by nullable column c2",
+			},
+			{
+				Query:          "call dolt_create_materialized_view('mv', 'select c1 from keyless');",
+				ExpectedErrStr: "materialized views require a source table with a primary key, but keyless has none",
+			},
+			{
+				Query:          "call dolt_create_materialized_view('u', 'select pk from t');",
+				ExpectedErrStr: "table u already exists",
+			},
+			{
+				Query:          "call dolt_create_materialized_view('dolt_mv', 'select pk from t');",
+				ExpectedErrStr: "Invalid materialized view name dolt_mv. Table names beginning with `dolt_` are reserved for internal use",
+			},
+			{
+				Query:          "call dolt_refresh_materialized_view('mv');",
+				ExpectedErrStr: "materialized view mv does not exist",
+			},
+			{
+				Query:    "call dolt_create_materialized_view('mv', 'select pk, c2 from t');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_create_materialized_view('mv2', 'select pk from t where c1 > 1');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_drop_materialized_view('mv');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select name from dolt_materialized_views;",
+				Expected: []sql.Row{{"mv2"}},
+			},
+			{
+				Query:    "call dolt_drop_materialized_view('mv2');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "show tables;",
+				Expected: []sql.Row{{"keyless"}, {"t"}, {"u"}},
+			},
+			{
+				Query:          "call dolt_drop_materialized_view('mv2');",
+				ExpectedErrStr: "materialized view mv2 does not exist",
+			},
+		},
+	},
+}
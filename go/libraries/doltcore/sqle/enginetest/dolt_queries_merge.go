@@ -26,6 +26,7 @@ import (
 	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
 
@@ -1547,10 +1548,76 @@ var MergeScripts = []queries.ScriptTest{
 			"INSERT into t VALUES (2, 2);",
 			"CALL DOLT_COMMIT('-am', 'left');",
 		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('other');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "SELECT * from t;",
+				Expected: []sql.Row{{1, 1, 1}, {2, 2, nil}},
+			},
+		},
+	},
+	{
+		Name: "insert two tables with the same name and different schema that conflict",
+		SetUpScript: []string{
+			"SET dolt_allow_commit_conflicts = on;",
+			"CALL DOLT_CHECKOUT('-b', 'other');",
+			"CREATE TABLE t (pk int PRIMARY key, col1 int, col2 varchar(10));",
+			"INSERT into t VALUES (1, 1, 'a'), (2, 2, 'b');",
+			"CALL DOLT_COMMIT('-Am', 'right');",
+
+			"CALL DOLT_CHECKOUT('main');",
+			"CREATE TABLE t (pk int PRIMARY key, col1 int, col3 int);",
+			"INSERT into t VALUES (2, 20, 200), (3, 3, 300);",
+			"CALL DOLT_COMMIT('-Am', 'left');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('other');",
+				Expected: []sql.Row{{"", 0, 1, "conflicts found"}},
+			},
+			{
+				Query:    "SELECT base_pk, base_col1, our_pk, our_col1, our_col3, their_pk, their_col1, their_col2 from dolt_conflicts_t;",
+				Expected: []sql.Row{{nil, nil, 2, 20, 200, 2, 2, "b"}},
+			},
+			{
+				Query:    "SELECT * from t;",
+				Expected: []sql.Row{{1, 1, nil, "a"}, {2, 20, 200, nil}, {3, 3, 300, nil}},
+			},
+			{
+				Query:    "UPDATE t SET col1 = 2, col2 = 'b' WHERE pk = 2;",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "DELETE FROM dolt_conflicts_t;",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "SELECT * from t;",
+				Expected: []sql.Row{{1, 1, nil, "a"}, {2, 2, 200, "b"}, {3, 3, 300, nil}},
+			},
+		},
+	},
+	{
+		Name: "insert two tables with the same name and different primary keys",
+		SetUpScript: []string{
+			"SET dolt_allow_commit_conflicts = on;",
+			"CALL DOLT_CHECKOUT('-b', 'other');",
+			"CREATE TABLE t (pk int PRIMARY key, col1 int);",
+			"INSERT into t VALUES (1, 1);",
+			"CALL DOLT_COMMIT('-Am', 'right');",
+
+			"CALL DOLT_CHECKOUT('main');",
+			"CREATE TABLE t (pk int, col1 int, PRIMARY KEY (pk, col1));",
+			"INSERT into t VALUES (2, 2);",
+			"CALL DOLT_COMMIT('-Am', 'left');",
+		},
 		Assertions: []queries.ScriptTestAssertion{
 			{
 				Query:          "CALL DOLT_MERGE('other');",
-				ExpectedErrStr: "table with same name 't' added in 2 commits can't be merged",
+				ExpectedErrStr: merge.ErrSameTblAddedTwice.New("t").Error(),
 			},
 		},
 	},
@@ -2159,6 +2226,539 @@ var MergeScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "Merge re-keys rows through a primary key redefined on the other branch",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int primary key, name varchar(20) not null, v int);",
+			"INSERT INTO t VALUES (1, 'a', 1), (2, 'b', 2);",
+			"CALL DOLT_COMMIT('-Am', 'setup');",
+
+			"CALL DOLT_CHECKOUT('-b', 'right');",
+			"ALTER TABLE t DROP PRIMARY KEY, ADD PRIMARY KEY (name);",
+			"UPDATE t SET v = 10 WHERE name = 'a';",
+			"CALL DOLT_COMMIT('-am', 'right commit');",
+
+			"CALL DOLT_CHECKOUT('main');",
+			"UPDATE t SET v = 20 WHERE pk = 2;",
+			"INSERT INTO t VALUES (3, 'c', 3);",
+			"CALL DOLT_COMMIT('-am', 'left commit');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "select column_name from information_schema.statistics where table_name = 't' and index_name = 'PRIMARY' order by seq_in_index;",
+				Expected: []sql.Row{{"name"}},
+			},
+			{
+				Query:    "SELECT pk, name, v FROM t ORDER BY pk;",
+				Expected: []sql.Row{{1, "a", 10}, {2, "b", 20}, {3, "c", 3}},
+			},
+		},
+	},
+	{
+		Name: "Merge reports rows colliding under a primary key redefined on the other branch as conflicts",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int primary key, name varchar(20) not null, v int);",
+			"INSERT INTO t VALUES (1, 'a', 1), (2, 'b', 2);",
+			"CALL DOLT_COMMIT('-Am', 'setup');",
+
+			"CALL DOLT_CHECKOUT('-b', 'right');",
+			"ALTER TABLE t DROP PRIMARY KEY, ADD PRIMARY KEY (name);",
+			"CALL DOLT_COMMIT('-am', 'right commit');",
+
+			"CALL DOLT_CHECKOUT('main');",
+			"INSERT INTO t VALUES (3, 'a', 3);",
+			"UPDATE t SET v = 20 WHERE pk = 2;",
+			"CALL DOLT_COMMIT('-am', 'left commit');",
+			"SET @@autocommit = 0;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{"", 0, 1, "conflicts found"}},
+			},
+			{
+				Query:    "SELECT base_pk, base_name, our_pk, our_name, their_pk, their_name FROM dolt_conflicts_t;",
+				Expected: []sql.Row{{1, "a", 3, "a", 1, "a"}},
+			},
+			{
+				Query:    "SELECT pk, name, v FROM t ORDER BY pk;",
+				Expected: []sql.Row{{2, "b", 20}, {3, "a", 3}},
+			},
+			{
+				Query:    "CALL DOLT_CONFLICTS_RESOLVE('--theirs', 't');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT pk, name, v FROM t ORDER BY pk;",
+				Expected: []sql.Row{{1, "a", 1}, {2, "b", 20}},
+			},
+		},
+	},
+	{
+		Name: "Merge reports rows colliding under a primary key redefined on this branch as conflicts",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int primary key, name varchar(20) not null, v int);",
+			"INSERT INTO t VALUES (1, 'a', 1), (2, 'b', 2);",
+			"CALL DOLT_COMMIT('-Am', 'setup');",
+
+			"CALL DOLT_CHECKOUT('-b', 'right');",
+			"INSERT INTO t VALUES (3, 'a', 3);",
+			"CALL DOLT_COMMIT('-am', 'right commit');",
+
+			"CALL DOLT_CHECKOUT('main');",
+			"ALTER TABLE t DROP PRIMARY KEY, ADD PRIMARY KEY (name);",
+			"CALL DOLT_COMMIT('-am', 'left commit');",
+			"SET @@autocommit = 0;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{"", 0, 1, "conflicts found"}},
+			},
+			{
+				Query:    "SELECT base_pk, base_name, our_pk, our_name, their_pk, their_name FROM dolt_conflicts_t;",
+				Expected: []sql.Row{{1, "a", 1, "a", 3, "a"}},
+			},
+			{
+				Query:    "SELECT pk, name, v FROM t ORDER BY pk;",
+				Expected: []sql.Row{{1, "a", 1}, {2, "b", 2}},
+			},
+			{
+				Query:    "CALL DOLT_CONFLICTS_RESOLVE('--ours', 't');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_conflicts_t;",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "Merge errors if rows have NULL values in a primary key redefined on the other branch",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int primary key, name varchar(20));",
+			"INSERT INTO t VALUES (1, 'a');",
+			"CALL DOLT_COMMIT('-Am', 'setup');",
+
+			"CALL DOLT_CHECKOUT('-b', 'right');",
+			"ALTER TABLE t DROP PRIMARY KEY, ADD PRIMARY KEY (name);",
+			"CALL DOLT_COMMIT('-am', 'right commit');",
+
+			"CALL DOLT_CHECKOUT('main');",
+			"INSERT INTO t VALUES (2, NULL);",
+			"CALL DOLT_COMMIT('-am', 'left commit');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "CALL DOLT_MERGE('right');",
+				ExpectedErrStr: merge.ErrMergeWithNullPrimaryKey.New("t").Error(),
+			},
+		},
+	},
+	{
+		Name: "merge edits into a table renamed on this branch",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c1 int);",
+			"INSERT INTO t VALUES (1, 1), (2, 2);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+			"CALL DOLT_CHECKOUT('-b', 'right');",
+			"UPDATE t SET c1 = 10 WHERE pk = 1;",
+			"INSERT INTO t VALUES (3, 3);",
+			"CALL DOLT_COMMIT('-am', 'right commit');",
+			"CALL DOLT_CHECKOUT('main');",
+			"RENAME TABLE t TO t2;",
+			"INSERT INTO t2 VALUES (4, 4);",
+			"CALL DOLT_COMMIT('-Am', 'left commit');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "SHOW TABLES;",
+				Expected: []sql.Row{{"t2"}},
+			},
+			{
+				Query:    "SELECT * FROM t2;",
+				Expected: []sql.Row{{1, 10}, {2, 2}, {3, 3}, {4, 4}},
+			},
+		},
+	},
+	{
+		Name: "merge a table rename into a branch that edited the table",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c1 int);",
+			"INSERT INTO t VALUES (1, 1), (2, 2);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+			"CALL DOLT_CHECKOUT('-b', 'right');",
+			"RENAME TABLE t TO t2;",
+			"INSERT INTO t2 VALUES (4, 4);",
+			"CALL DOLT_COMMIT('-Am', 'right commit');",
+			"CALL DOLT_CHECKOUT('main');",
+			"UPDATE t SET c1 = 10 WHERE pk = 1;",
+			"INSERT INTO t VALUES (3, 3);",
+			"CALL DOLT_COMMIT('-am', 'left commit');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "SHOW TABLES;",
+				Expected: []sql.Row{{"t2"}},
+			},
+			{
+				Query:    "SELECT * FROM t2;",
+				Expected: []sql.Row{{1, 10}, {2, 2}, {3, 3}, {4, 4}},
+			},
+		},
+	},
+	{
+		Name: "merge conflicting edits into a renamed table",
+		SetUpScript: []string{
+			"SET dolt_allow_commit_conflicts = on;",
+			"CREATE TABLE t (pk int PRIMARY KEY, c1 int);",
+			"INSERT INTO t VALUES (1, 1), (2, 2);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+			"CALL DOLT_CHECKOUT('-b', 'right');",
+			"UPDATE t SET c1 = 10 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'right commit');",
+			"CALL DOLT_CHECKOUT('main');",
+			"RENAME TABLE t TO t2;",
+			"UPDATE t2 SET c1 = 100 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-Am', 'left commit');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{"", 0, 1, "conflicts found"}},
+			},
+			{
+				Query:    "SELECT base_pk, base_c1, our_pk, our_c1, their_pk, their_c1 FROM dolt_conflicts_t2;",
+				Expected: []sql.Row{{1, 1, 1, 100, 1, 10}},
+			},
+			{
+				Query:    "CALL DOLT_CONFLICTS_RESOLVE('--theirs', 't2');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT * FROM t2;",
+				Expected: []sql.Row{{1, 10}, {2, 2}},
+			},
+		},
+	},
+	{
+		Name: "merge a table renamed the same way on both branches",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c1 int);",
+			"INSERT INTO t VALUES (1, 1), (2, 2);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+			"CALL DOLT_CHECKOUT('-b', 'right');",
+			"RENAME TABLE t TO t2;",
+			"UPDATE t2 SET c1 = 10 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-Am', 'right commit');",
+			"CALL DOLT_CHECKOUT('main');",
+			"RENAME TABLE t TO t2;",
+			"UPDATE t2 SET c1 = 20 WHERE pk = 2;",
+			"CALL DOLT_COMMIT('-Am', 'left commit');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "SELECT * FROM t2;",
+				Expected: []sql.Row{{1, 10}, {2, 20}},
+			},
+		},
+	},
+	{
+		Name: "merge rows referencing a parent table renamed on this branch",
+		SetUpScript: []string{
+			"CREATE TABLE parent (pk int PRIMARY KEY);",
+			"CREATE TABLE child (pk int PRIMARY KEY, parent_pk int, FOREIGN KEY (parent_pk) REFERENCES parent (pk));",
+			"INSERT INTO parent VALUES (1);",
+			"INSERT INTO child VALUES (1, 1);",
+			"CALL DOLT_COMMIT('-Am', 'create tables');",
+			"CALL DOLT_CHECKOUT('-b', 'right');",
+			"INSERT INTO parent VALUES (2);",
+			"INSERT INTO child VALUES (2, 2);",
+			"CALL DOLT_COMMIT('-am', 'right commit');",
+			"CALL DOLT_CHECKOUT('main');",
+			"RENAME TABLE parent TO parent2;",
+			"CALL DOLT_COMMIT('-Am', 'left commit');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "SELECT * FROM parent2;",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				Query:    "SELECT * FROM child;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				Query:    "SELECT COUNT(*) FROM dolt_constraint_violations;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:       "INSERT INTO child VALUES (3, 3);",
+				ExpectedErr: sql.ErrForeignKeyChildViolation,
+			},
+		},
+	},
+	{
+		Name: "octopus merge of several branches",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+			"CALL DOLT_BRANCH('b1');",
+			"CALL DOLT_BRANCH('b2');",
+			"CALL DOLT_BRANCH('b3');",
+			"INSERT INTO t VALUES (2, 2);",
+			"CALL DOLT_COMMIT('-am', 'main commit');",
+			"CALL DOLT_CHECKOUT('b1');",
+			"INSERT INTO t VALUES (3, 3);",
+			"CALL DOLT_COMMIT('-am', 'b1 commit');",
+			"CALL DOLT_CHECKOUT('b2');",
+			"UPDATE t SET c = 10 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'b2 commit');",
+			"CALL DOLT_CHECKOUT('b3');",
+			"CREATE TABLE t2 (pk int PRIMARY KEY);",
+			"INSERT INTO t2 VALUES (4);",
+			"CALL DOLT_COMMIT('-Am', 'b3 commit');",
+			"CALL DOLT_CHECKOUT('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('b1', 'b2', 'b3');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "SELECT * FROM t;",
+				Expected: []sql.Row{{1, 10}, {2, 2}, {3, 3}},
+			},
+			{
+				Query:    "SELECT * FROM t2;",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				Query: "SELECT parent_index FROM dolt_commit_ancestors WHERE commit_hash = HASHOF('HEAD') AND parent_hash IN " +
+					"(HASHOF('HEAD~1'), HASHOF('b1'), HASHOF('b2'), HASHOF('b3')) ORDER BY parent_index;",
+				Expected: []sql.Row{{0}, {1}, {2}, {3}},
+			},
+			{
+				Query:    "SELECT message FROM dolt_log LIMIT 1;",
+				Expected: []sql.Row{{"Merge branches 'b1', 'b2' and 'b3' into main"}},
+			},
+			{
+				Query:    "SELECT COUNT(*) FROM dolt_status;",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "octopus merge aborts on the first conflicting branch",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+			"CALL DOLT_BRANCH('b1');",
+			"CALL DOLT_BRANCH('b2');",
+			"CALL DOLT_BRANCH('b3');",
+			"CALL DOLT_CHECKOUT('b1');",
+			"INSERT INTO t VALUES (2, 2);",
+			"CALL DOLT_COMMIT('-am', 'b1 commit');",
+			"CALL DOLT_CHECKOUT('b2');",
+			"UPDATE t SET c = 10 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'b2 commit');",
+			"CALL DOLT_CHECKOUT('b3');",
+			"UPDATE t SET c = 20 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'b3 commit');",
+			"CALL DOLT_CHECKOUT('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "CALL DOLT_MERGE('b1', 'b2', 'b3');",
+				ExpectedErrStr: merge.ErrOctopusMergeConflict.New("b3").Error(),
+			},
+			{
+				Query:    "SELECT * FROM t;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "SELECT COUNT(*) FROM dolt_status;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT COUNT(*) FROM dolt_conflicts;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT is_merging FROM dolt_merge_status;",
+				Expected: []sql.Row{{false}},
+			},
+			{
+				Query:    "SELECT message FROM dolt_log LIMIT 1;",
+				Expected: []sql.Row{{"create table"}},
+			},
+		},
+	},
+	{
+		Name: "octopus merge skips branches that are already merged",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+			"CALL DOLT_BRANCH('old');",
+			"CALL DOLT_CHECKOUT('-b', 'b1');",
+			"INSERT INTO t VALUES (1, 1);",
+			"CALL DOLT_COMMIT('-am', 'b1 commit');",
+			"CALL DOLT_CHECKOUT('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('old', 'main');",
+				Expected: []sql.Row{{"", 0, 0, "Everything up-to-date"}},
+			},
+			{
+				Query:    "CALL DOLT_MERGE('old', 'b1', 'b1');",
+				Expected: []sql.Row{{doltCommit, 1, 0, "merge successful"}},
+			},
+			{
+				Query:    "SELECT * FROM t;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "SELECT message FROM dolt_log LIMIT 1;",
+				Expected: []sql.Row{{"b1 commit"}},
+			},
+		},
+	},
+	{
+		Name: "octopus merge of nested branches",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+			"CALL DOLT_BRANCH('b3');",
+			"CALL DOLT_CHECKOUT('-b', 'b1');",
+			"UPDATE t SET c = 10 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'b1 commit');",
+			"CALL DOLT_CHECKOUT('-b', 'b2');",
+			"UPDATE t SET c = 20 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'b2 commit');",
+			"CALL DOLT_BRANCH('b4');",
+			"CALL DOLT_CHECKOUT('b1');",
+			"INSERT INTO t VALUES (2, 2);",
+			"CALL DOLT_COMMIT('-am', 'second b1 commit');",
+			"CALL DOLT_CHECKOUT('b3');",
+			"INSERT INTO t VALUES (3, 3);",
+			"CALL DOLT_COMMIT('-am', 'b3 commit');",
+			"CALL DOLT_CHECKOUT('main');",
+			"INSERT INTO t VALUES (4, 4);",
+			"CALL DOLT_COMMIT('-am', 'main commit');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				// b1 is merged against the first commit of b1, which b2 already merged
+				Query:    "CALL DOLT_MERGE('b2', 'b1', 'b4', 'b3');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "SELECT * FROM t;",
+				Expected: []sql.Row{{1, 20}, {2, 2}, {3, 3}, {4, 4}},
+			},
+			{
+				// b4 is the same commit as b2, so it isn't a parent of its own
+				Query: "SELECT parent_index FROM dolt_commit_ancestors WHERE commit_hash = HASHOF('HEAD') AND parent_hash IN " +
+					"(HASHOF('HEAD~1'), HASHOF('b2'), HASHOF('b1'), HASHOF('b3')) ORDER BY parent_index;",
+				Expected: []sql.Row{{0}, {1}, {2}, {3}},
+			},
+			{
+				Query:    "SELECT message FROM dolt_log LIMIT 1;",
+				Expected: []sql.Row{{"Merge branches 'b2', 'b1' and 'b3' into main"}},
+			},
+		},
+	},
+	{
+		Name: "octopus merge skips branches that another branch contains",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+			"CALL DOLT_BRANCH('b3');",
+			"CALL DOLT_CHECKOUT('-b', 'b1');",
+			"UPDATE t SET c = 10 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'b1 commit');",
+			"CALL DOLT_CHECKOUT('-b', 'b2');",
+			"UPDATE t SET c = 20 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'b2 commit');",
+			"CALL DOLT_CHECKOUT('b3');",
+			"INSERT INTO t VALUES (3, 3);",
+			"CALL DOLT_COMMIT('-am', 'b3 commit');",
+			"CALL DOLT_CHECKOUT('main');",
+			"INSERT INTO t VALUES (4, 4);",
+			"CALL DOLT_COMMIT('-am', 'main commit');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('b1', 'b2', 'b3');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "SELECT * FROM t;",
+				Expected: []sql.Row{{1, 20}, {3, 3}, {4, 4}},
+			},
+			{
+				Query:    "SELECT COUNT(*) FROM dolt_commit_ancestors WHERE commit_hash = HASHOF('HEAD');",
+				Expected: []sql.Row{{3}},
+			},
+			{
+				Query:    "SELECT message FROM dolt_log LIMIT 1;",
+				Expected: []sql.Row{{"Merge branches 'b2' and 'b3' into main"}},
+			},
+		},
+	},
+	{
+		Name: "octopus merge errors",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+			"CALL DOLT_BRANCH('b1');",
+			"CALL DOLT_BRANCH('b2');",
+			"INSERT INTO t VALUES (1, 1);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "CALL DOLT_MERGE('b1', 'b2');",
+				ExpectedErrStr: dprocedures.ErrUncommittedChanges.New().Error(),
+			},
+			{
+				Query:          "CALL DOLT_MERGE('--squash', 'b1', 'b2');",
+				ExpectedErrStr: "error: Flag '--squash' is not supported when merging more than one branch",
+			},
+			{
+				Query:          "CALL DOLT_MERGE('--no-commit', 'b1', 'b2');",
+				ExpectedErrStr: "error: Flag '--no-commit' is not supported when merging more than one branch",
+			},
+			{
+				Query:          "CALL DOLT_MERGE('--no-ff', 'b1', 'b2');",
+				ExpectedErrStr: "error: Flag '--no-ff' is not supported when merging more than one branch",
+			},
+		},
+	},
 	{
 		Name:        "`Delete from table` should keep artifacts - conflicts",
 		SetUpScript: createConflictsSetupScript,
@@ -2528,6 +3128,40 @@ var MergeScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		// prefix and spatial indexes store different encodings than their columns, which must not stop merges from
+		// updating them
+		Name: "merge updates prefix and spatial indexes",
+		SetUpScript: []string{
+			"create table t (id int primary key, txt text, p point not null srid 0, index (txt(3)), spatial index (p));",
+			"insert into t values (1, 'aaa', point(1, 1)), (2, 'bbb', point(2, 2));",
+			"call dolt_commit('-Am', 'setup');",
+			"call dolt_checkout('-b', 'other');",
+			"insert into t values (4, 'ddd', point(4, 4));",
+			"call dolt_commit('-am', 'insert on other');",
+			"call dolt_checkout('main');",
+			"update t set txt = 'ccc', p = point(3, 3) where id = 1;",
+			"call dolt_commit('-am', 'update on main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('other')",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "select id from t where txt = 'ddd'",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				Query:    "select id from t where txt = 'ccc'",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select id from t where st_intersects(p, point(4, 4))",
+				Expected: []sql.Row{{4}},
+			},
+		},
+	},
 }
 
 var KeylessMergeCVsAndConflictsScripts = []queries.ScriptTest{
@@ -3639,7 +4273,7 @@ var MergeArtifactsScripts = []queries.ScriptTest{
 		},
 	},
 	{
-		Name: "Multiple foreign key violations for a given row not supported",
+		Name: "Multiple foreign key violations for a given row",
 		SetUpScript: []string{
 			"SET dolt_force_transaction_commit = on;",
 			`
@@ -3672,8 +4306,8 @@ var MergeArtifactsScripts = []queries.ScriptTest{
 		},
 		Assertions: []queries.ScriptTestAssertion{
 			{
-				Query:          "CALL DOLT_MERGE('right');",
-				ExpectedErrStr: "error storing constraint violation for primary key (( 1 )): another violation already exists\nnew violation: {\"Columns\":[\"col1\"],\"ForeignKey\":\"child_ibfk_1\",\"Index\":\"col1\",\"OnDelete\":\"RESTRICT\",\"OnUpdate\":\"RESTRICT\",\"ReferencedColumns\":[\"col1\"],\"ReferencedIndex\":\"par_col1_idx\",\"ReferencedTable\":\"parent\",\"Table\":\"child\"} old violation: ({\"Columns\":[\"col2\"],\"ForeignKey\":\"child_ibfk_2\",\"Index\":\"col2\",\"OnDelete\":\"RESTRICT\",\"OnUpdate\":\"RESTRICT\",\"ReferencedColumns\":[\"col2\"],\"ReferencedIndex\":\"par_col2_idx\",\"ReferencedTable\":\"parent\",\"Table\":\"child\"})",
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{"", 0, 1, "conflicts found"}},
 			},
 			{
 				Query:    "SELECT * from parent;",
@@ -3681,12 +4315,27 @@ var MergeArtifactsScripts = []queries.ScriptTest{
 			},
 			{
 				Query:    "SELECT * from child;",
-				Expected: []sql.Row{},
+				Expected: []sql.Row{{1, 1, 1}},
+			},
+			{
+				Query: "SELECT violation_type, pk, violation_info->>'$.ForeignKey' from dolt_constraint_violations_child order by 3;",
+				Expected: []sql.Row{
+					{"foreign key", 1, "child_ibfk_1"},
+					{"foreign key", 1, "child_ibfk_2"},
+				},
+			},
+			{
+				Query:    "DELETE from dolt_constraint_violations_child where violation_info->>'$.ForeignKey' = 'child_ibfk_1';",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "SELECT violation_type, pk, violation_info->>'$.ForeignKey' from dolt_constraint_violations_child;",
+				Expected: []sql.Row{{"foreign key", 1, "child_ibfk_2"}},
 			},
 		},
 	},
 	{
-		Name: "Multiple unique key violations for a given row not supported",
+		Name: "Multiple unique key violations for a given row",
 		SetUpScript: []string{
 			"SET dolt_force_transaction_commit = on;",
 			"CREATE table t (pk int PRIMARY KEY, col1 int UNIQUE, col2 int UNIQUE);",
@@ -3703,12 +4352,41 @@ var MergeArtifactsScripts = []queries.ScriptTest{
 		},
 		Assertions: []queries.ScriptTestAssertion{
 			{
-				Query:          "CALL DOLT_MERGE('right');",
-				ExpectedErrStr: "error storing constraint violation for primary key (( 1 )): another violation already exists\nnew violation: {\"Columns\":[\"col1\"],\"Name\":\"col1\"} old violation: ({\"Columns\":[\"col2\"],\"Name\":\"col2\"})",
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{"", 0, 1, "conflicts found"}},
 			},
 			{
 				Query:    "SELECT * from t;",
-				Expected: []sql.Row{{1, 1, 1}},
+				Expected: []sql.Row{{1, 1, 1}, {2, 1, 1}},
+			},
+			{
+				Query: "SELECT violation_type, pk, violation_info->>'$.Name' from dolt_constraint_violations_t order by 2, 3;",
+				Expected: []sql.Row{
+					{"unique index", 1, "col1"},
+					{"unique index", 1, "col2"},
+					{"unique index", 2, "col1"},
+					{"unique index", 2, "col2"},
+				},
+			},
+			{
+				Query:    "DELETE from dolt_constraint_violations_t where pk = 1 and violation_info->>'$.Name' = 'col2';",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query: "SELECT violation_type, pk, violation_info->>'$.Name' from dolt_constraint_violations_t order by 2, 3;",
+				Expected: []sql.Row{
+					{"unique index", 1, "col1"},
+					{"unique index", 2, "col1"},
+					{"unique index", 2, "col2"},
+				},
+			},
+			{
+				Query:    "DELETE from dolt_constraint_violations_t where pk = 2;",
+				Expected: []sql.Row{{types.NewOkResult(2)}},
+			},
+			{
+				Query:    "SELECT violation_type, pk, violation_info->>'$.Name' from dolt_constraint_violations_t;",
+				Expected: []sql.Row{{"unique index", 1, "col1"}},
 			},
 		},
 	},
@@ -0,0 +1,315 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"github.com/dolthub/go-mysql-server/enginetest/queries"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+)
+
+var DoltMergePreviewScripts = []queries.ScriptTest{
+	{
+		Name: "dolt_merge_preview counts the changes of a merge without merging",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c varchar(10));",
+			"insert into t values (1, 'a'), (2, 'b'), (3, 'c');",
+			"call dolt_commit('-Am', 'create t');",
+			"call dolt_branch('other');",
+			"insert into t values (5, 'e');",
+			"call dolt_commit('-am', 'insert on main');",
+			"call dolt_checkout('other');",
+			"update t set c = 'z' where pk = 1;",
+			"delete from t where pk = 2;",
+			"insert into t values (4, 'd');",
+			"create table u (pk int primary key);",
+			"insert into u values (1), (2);",
+			"call dolt_commit('-Am', 'changes on other');",
+			"call dolt_checkout('main');",
+			"insert into t values (6, 'f');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select * from dolt_merge_preview('other');",
+				Expected: []sql.Row{
+					{"t", int64(1), int64(1), int64(1), int64(0), int64(0), int64(0)},
+					{"u", int64(2), int64(0), int64(0), int64(0), int64(0), int64(0)},
+				},
+			},
+			{
+				Query: "select * from dolt_merge_preview('main', 'other');",
+				Expected: []sql.Row{
+					{"t", int64(1), int64(1), int64(1), int64(0), int64(0), int64(0)},
+					{"u", int64(2), int64(0), int64(0), int64(0), int64(0), int64(0)},
+				},
+			},
+			{
+				Query: "select * from dolt_merge_preview('other', 'main');",
+				Expected: []sql.Row{
+					{"t", int64(1), int64(0), int64(0), int64(0), int64(0), int64(0)},
+				},
+			},
+			{
+				Query: "select * from dolt_merge_preview('other', '--detail');",
+				Expected: []sql.Row{
+					{"t", "modified", nil, types.MustJSON(`{"pk": 1}`)},
+					{"t", "removed", nil, types.MustJSON(`{"pk": 2}`)},
+					{"t", "added", nil, types.MustJSON(`{"pk": 4}`)},
+					{"u", "added", nil, types.MustJSON(`{"pk": 1}`)},
+					{"u", "added", nil, types.MustJSON(`{"pk": 2}`)},
+				},
+			},
+			{
+				// the uncommitted changes of the working set are left alone
+				Query:    "select * from dolt_status;",
+				Expected: []sql.Row{{"t", false, "modified"}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, "a"}, {2, "b"}, {3, "c"}, {5, "e"}, {6, "f"}},
+			},
+			{
+				Query:    "show tables;",
+				Expected: []sql.Row{{"t"}},
+			},
+			{
+				Query:    "select message from dolt_log limit 1;",
+				Expected: []sql.Row{{"insert on main"}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge_preview reports data conflicts",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c varchar(10));",
+			"insert into t values (1, 'a'), (2, 'b');",
+			"call dolt_commit('-Am', 'create t');",
+			"call dolt_branch('other');",
+			"update t set c = 'main' where pk = 1;",
+			"call dolt_commit('-am', 'update on main');",
+			"call dolt_checkout('other');",
+			"update t set c = 'other' where pk = 1;",
+			"update t set c = 'other' where pk = 2;",
+			"call dolt_commit('-am', 'update on other');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from dolt_merge_preview('other');",
+				Expected: []sql.Row{{"t", int64(0), int64(1), int64(0), int64(1), int64(0), int64(0)}},
+			},
+			{
+				Query: "select * from dolt_merge_preview('other', '--detail');",
+				Expected: []sql.Row{
+					{"t", "modified", nil, types.MustJSON(`{"pk": 2}`)},
+					{"t", "conflict", nil, types.MustJSON(`{"pk": 1}`)},
+				},
+			},
+			{
+				Query:    "select * from dolt_conflicts;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from dolt_status;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, "main"}, {2, "b"}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge_preview reports constraint violations",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c varchar(10), unique key (c));",
+			"insert into t values (1, 'a');",
+			"call dolt_commit('-Am', 'create t');",
+			"call dolt_branch('other');",
+			"insert into t values (2, 'x');",
+			"call dolt_commit('-am', 'insert on main');",
+			"call dolt_checkout('other');",
+			"insert into t values (3, 'x');",
+			"call dolt_commit('-am', 'insert on other');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from dolt_merge_preview('other');",
+				Expected: []sql.Row{{"t", int64(1), int64(0), int64(0), int64(0), int64(0), int64(2)}},
+			},
+			{
+				Query: "select * from dolt_merge_preview('other', '--detail');",
+				Expected: []sql.Row{
+					{"t", "added", nil, types.MustJSON(`{"pk": 3}`)},
+					{"t", "constraint violation", "unique index", types.MustJSON(`{"pk": 2}`)},
+					{"t", "constraint violation", "unique index", types.MustJSON(`{"pk": 3}`)},
+				},
+			},
+			{
+				Query:    "select count(*) from dolt_constraint_violations;",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge_preview doesn't count the constraint violations the current commit already has",
+		SetUpScript: []string{
+			"create table parent (pk int primary key);",
+			"create table child (pk int primary key, parent_fk int, foreign key (parent_fk) references parent(pk));",
+			"insert into parent values (1), (2);",
+			"call dolt_commit('-Am', 'create tables');",
+			"call dolt_branch('violating');",
+			"call dolt_branch('other');",
+			"delete from parent where pk = 1;",
+			"call dolt_commit('-am', 'delete parent 1');",
+			"call dolt_checkout('violating');",
+			"insert into child values (1, 1);",
+			"call dolt_commit('-am', 'insert child of parent 1');",
+			"call dolt_checkout('other');",
+			"insert into child values (2, 2);",
+			"call dolt_commit('-am', 'insert child of parent 2');",
+			"call dolt_checkout('main');",
+			"set dolt_force_transaction_commit = on;",
+			"call dolt_merge('violating');",
+			"call dolt_commit('-afm', 'commit constraint violations');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select count(*) from dolt_constraint_violations_child;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from dolt_merge_preview('other');",
+				Expected: []sql.Row{{"child", int64(1), int64(0), int64(0), int64(0), int64(0), int64(0)}},
+			},
+			{
+				Query:    "select * from dolt_merge_preview('other', '--detail');",
+				Expected: []sql.Row{{"child", "added", nil, types.MustJSON(`{"pk": 2}`)}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge_preview reports schema conflicts",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-Am', 'create t');",
+			"call dolt_branch('other');",
+			"alter table t modify column c varchar(10);",
+			"call dolt_commit('-am', 'change type on main');",
+			"call dolt_checkout('other');",
+			"alter table t modify column c bigint;",
+			"call dolt_commit('-am', 'change type on other');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from dolt_merge_preview('other');",
+				Expected: []sql.Row{{"t", int64(0), int64(0), int64(0), int64(0), int64(1), int64(0)}},
+			},
+			{
+				Query:    "select * from dolt_merge_preview('other', '--detail');",
+				Expected: []sql.Row{{"t", "schema conflict", nil, nil}},
+			},
+			{
+				Query:    "select count(*) from dolt_schema_conflicts;",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge_preview of fast-forward and up-to-date merges",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'create t');",
+			"call dolt_branch('other');",
+			"call dolt_checkout('other');",
+			"insert into t values (1), (2);",
+			"call dolt_commit('-am', 'insert on other');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from dolt_merge_preview('other');",
+				Expected: []sql.Row{{"t", int64(2), int64(0), int64(0), int64(0), int64(0), int64(0)}},
+			},
+			{
+				Query:    "select * from dolt_merge_preview('other', 'main');",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from dolt_merge_preview('main', 'main');",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select count(*) from t;",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge_preview backfills new columns with --column-default",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"insert into t values (1);",
+			"call dolt_commit('-Am', 'create t');",
+			"call dolt_branch('other');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'insert on main');",
+			"call dolt_checkout('other');",
+			"alter table t add column c int not null;",
+			"call dolt_commit('-am', 'add column on other');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:       "select * from dolt_merge_preview('other');",
+				ExpectedErr: merge.ErrUnmergeableNewColumn,
+			},
+			{
+				Query:    "select * from dolt_merge_preview('other', '--column-default', 't.c=pk * 10');",
+				Expected: []sql.Row{{"t", int64(0), int64(2), int64(0), int64(0), int64(0), int64(0)}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge_preview argument errors",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'create t');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:       "select * from dolt_merge_preview();",
+				ExpectedErr: sql.ErrInvalidArgumentNumber,
+			},
+			{
+				Query:       "select * from dolt_merge_preview('main', 'main', 'main');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:       "select * from dolt_merge_preview('main', '--unknown');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:          "select * from dolt_merge_preview('missing');",
+				ExpectedErrStr: "branch not found: missing",
+			},
+		},
+	},
+}
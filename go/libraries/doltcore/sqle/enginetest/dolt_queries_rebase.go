@@ -47,6 +47,9 @@ var DoltRebaseScriptTests = []queries.ScriptTest{
 			}, {
 				Query:          "call dolt_rebase('--abrot');",
 				ExpectedErrStr: "error: unknown option `abrot'",
+			}, {
+				Query:          "call dolt_rebase('-i', '--rebase-merges', 'main');",
+				ExpectedErrStr: "error: unknown option `rebase-merges'",
 			}, {
 				Query:          "call dolt_rebase('-i', 'doesnotexist');",
 				ExpectedErrStr: "branch not found: doesnotexist",
@@ -1611,6 +1614,293 @@ var DoltRebaseScriptTests = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "dolt_rebase: --onto",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_checkout('-b', 'branch1');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'inserting row 1');",
+			"call dolt_checkout('-b', 'branch2');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'inserting row 2');",
+			"insert into t values (3);",
+			"call dolt_commit('-am', 'inserting row 3');",
+			"call dolt_checkout('main');",
+			"insert into t values (0);",
+			"call dolt_commit('-am', 'inserting row 0');",
+			"call dolt_checkout('branch2');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "call dolt_rebase('-i', '--onto', 'main', 'branch1');",
+				Expected: []sql.Row{{0, "interactive rebase started on branch dolt_rebase_branch2; " +
+					"adjust the rebase plan in the dolt_rebase table, then " +
+					"continue rebasing by calling dolt_rebase('--continue')"}},
+			},
+			{
+				Query: "select * from dolt_rebase order by rebase_order;",
+				Expected: []sql.Row{
+					{"1", "pick", doltCommit, "inserting row 2"},
+					{"2", "pick", doltCommit, "inserting row 3"},
+				},
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{0, "Successfully rebased and updated refs/heads/branch2"}},
+			},
+			{
+				Query: "select message from dolt_log;",
+				Expected: []sql.Row{
+					{"inserting row 3"},
+					{"inserting row 2"},
+					{"inserting row 0"},
+					{"creating table t"},
+					{"Initialize data repository"},
+				},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{0}, {2}, {3}},
+			},
+			{
+				Query:          "call dolt_rebase('-i', '--onto', 'doesnotexist', 'main');",
+				ExpectedErrStr: "branch not found: doesnotexist",
+			},
+		},
+	},
+	{
+		Name: "dolt_rebase: exec steps",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_branch('branch1');",
+			"insert into t values (0);",
+			"call dolt_commit('-am', 'inserting row 0');",
+			"call dolt_checkout('branch1');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'inserting row 1');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'inserting row 2');",
+			"insert into t values (3);",
+			"call dolt_commit('-am', 'inserting row 3');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "call dolt_rebase('-i', 'main');",
+				Expected: []sql.Row{{0, "interactive rebase started on branch dolt_rebase_branch1; " +
+					"adjust the rebase plan in the dolt_rebase table, then " +
+					"continue rebasing by calling dolt_rebase('--continue')"}},
+			},
+			{
+				Query:    "insert into dolt_rebase values (1.5, 'exec', '', 'select count(*) = 2 from t');",
+				Expected: []sql.Row{{gmstypes.NewOkResult(1)}},
+			},
+			{
+				Query:    "insert into dolt_rebase values (2.5, 'exec', '', 'select count(*) = 4 from t');",
+				Expected: []sql.Row{{gmstypes.NewOkResult(1)}},
+			},
+			{
+				Query:    "insert into dolt_rebase values (3.5, 'exec', '', 'select pk from t where pk = 1');",
+				Expected: []sql.Row{{gmstypes.NewOkResult(1)}},
+			},
+			{
+				Query: "call dolt_rebase('--continue');",
+				ExpectedErrStr: dprocedures.ErrRebaseExecFailed.New("2.5",
+					`"select count(*) = 4 from t" returned false`).Error(),
+			},
+			{
+				// The rebase stops after the failed step, with the earlier steps applied
+				Query:    "select active_branch();",
+				Expected: []sql.Row{{"dolt_rebase_branch1"}},
+			},
+			{
+				Query: "select message from dolt_log;",
+				Expected: []sql.Row{
+					{"inserting row 2"},
+					{"inserting row 1"},
+					{"inserting row 0"},
+					{"creating table t"},
+					{"Initialize data repository"},
+				},
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{0, "Successfully rebased and updated refs/heads/branch1"}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{0}, {1}, {2}, {3}},
+			},
+		},
+	},
+	{
+		Name: "dolt_rebase: exec step errors",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_branch('branch1');",
+			"insert into t values (0);",
+			"call dolt_commit('-am', 'inserting row 0');",
+			"call dolt_checkout('branch1');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'inserting row 1');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'inserting row 2');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "call dolt_rebase('-i', 'main');",
+				Expected: []sql.Row{{0, "interactive rebase started on branch dolt_rebase_branch1; " +
+					"adjust the rebase plan in the dolt_rebase table, then " +
+					"continue rebasing by calling dolt_rebase('--continue')"}},
+			},
+			{
+				Query:    "insert into dolt_rebase values (1.5, 'exec', '', '');",
+				Expected: []sql.Row{{gmstypes.NewOkResult(1)}},
+			},
+			{
+				Query:          "call dolt_rebase('--continue');",
+				ExpectedErrStr: rebase.ErrInvalidRebasePlanExecWithoutStatement.Error(),
+			},
+			{
+				Query:    "update dolt_rebase set commit_message = 'select * from doesnotexist' where rebase_order = 1.5;",
+				Expected: []sql.Row{{gmstypes.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query: "call dolt_rebase('--continue');",
+				ExpectedErrStr: dprocedures.ErrRebaseExecFailed.New("1.5",
+					"table not found: doesnotexist").Error(),
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{0, "Successfully rebased and updated refs/heads/branch1"}},
+			},
+			{
+				Query: "select message from dolt_log;",
+				Expected: []sql.Row{
+					{"inserting row 2"},
+					{"inserting row 1"},
+					{"inserting row 0"},
+					{"creating table t"},
+					{"Initialize data repository"},
+				},
+			},
+		},
+	},
+	{
+		Name: "dolt_rebase: exec step leaves uncommitted changes",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_branch('branch1');",
+			"insert into t values (0);",
+			"call dolt_commit('-am', 'inserting row 0');",
+			"call dolt_checkout('branch1');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'inserting row 1');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'inserting row 2');",
+			"set @@autocommit=0;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "call dolt_rebase('-i', 'main');",
+				Expected: []sql.Row{{0, "interactive rebase started on branch dolt_rebase_branch1; " +
+					"adjust the rebase plan in the dolt_rebase table, then " +
+					"continue rebasing by calling dolt_rebase('--continue')"}},
+			},
+			{
+				Query:    "insert into dolt_rebase values (1.5, 'exec', '', 'insert into t values (100)');",
+				Expected: []sql.Row{{gmstypes.NewOkResult(1)}},
+			},
+			{
+				Query:          "call dolt_rebase('--continue');",
+				ExpectedErrStr: dprocedures.ErrRebaseExecUncommittedChanges.New("1.5").Error(),
+			},
+			{
+				Query:    "call dolt_commit('-am', 'inserting row 100');",
+				Expected: []sql.Row{{doltCommit}},
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{0, "Successfully rebased and updated refs/heads/branch1"}},
+			},
+			{
+				Query: "select message from dolt_log;",
+				Expected: []sql.Row{
+					{"inserting row 2"},
+					{"inserting row 100"},
+					{"inserting row 1"},
+					{"inserting row 0"},
+					{"creating table t"},
+					{"Initialize data repository"},
+				},
+			},
+		},
+	},
+	{
+		Name: "dolt_rebase: --autosquash",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 varchar(100));",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_branch('branch1');",
+			"insert into t values (0, 'zero');",
+			"call dolt_commit('-am', 'inserting row 0');",
+			"call dolt_checkout('branch1');",
+			"insert into t values (1, 'one');",
+			"call dolt_commit('-am', 'inserting row 1');",
+			"insert into t values (2, 'two');",
+			"call dolt_commit('-am', 'inserting row 2');",
+			"update t set c1 = 'uno' where pk = 1;",
+			"call dolt_commit('-am', 'fixup! inserting row 1');",
+			"update t set c1 = 'dos' where pk = 2;",
+			"call dolt_commit('-am', 'squash! inserting row 2');",
+			"update t set c1 = 'eins' where pk = 1;",
+			"call dolt_commit('-am', 'fixup! fixup! inserting row 1');",
+			"insert into t values (3, 'three');",
+			"call dolt_commit('-am', 'fixup! no such commit');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "call dolt_rebase('-i', '--autosquash', 'main');",
+				Expected: []sql.Row{{0, "interactive rebase started on branch dolt_rebase_branch1; " +
+					"adjust the rebase plan in the dolt_rebase table, then " +
+					"continue rebasing by calling dolt_rebase('--continue')"}},
+			},
+			{
+				Query: "select * from dolt_rebase order by rebase_order;",
+				Expected: []sql.Row{
+					{"1", "pick", doltCommit, "inserting row 1"},
+					{"2", "fixup", doltCommit, "fixup! inserting row 1"},
+					{"3", "fixup", doltCommit, "fixup! fixup! inserting row 1"},
+					{"4", "pick", doltCommit, "inserting row 2"},
+					{"5", "squash", doltCommit, "squash! inserting row 2"},
+					{"6", "pick", doltCommit, "fixup! no such commit"},
+				},
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{0, "Successfully rebased and updated refs/heads/branch1"}},
+			},
+			{
+				Query: "select message from dolt_log;",
+				Expected: []sql.Row{
+					{"fixup! no such commit"},
+					{"inserting row 2\n\nsquash! inserting row 2"},
+					{"inserting row 1"},
+					{"inserting row 0"},
+					{"creating table t"},
+					{"Initialize data repository"},
+				},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{0, "zero"}, {1, "eins"}, {2, "dos"}, {3, "three"}},
+			},
+		},
+	},
 }
 
 var DoltRebaseMultiSessionScriptTests = []queries.ScriptTest{
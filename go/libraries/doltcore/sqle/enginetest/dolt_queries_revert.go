@@ -17,6 +17,9 @@ package enginetest
 import (
 	"github.com/dolthub/go-mysql-server/enginetest/queries"
 	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
 
 var RevertScripts = []queries.ScriptTest{
@@ -70,6 +73,47 @@ var RevertScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "dolt_revert() reverts a merge commit relative to a mainline parent",
+		SetUpScript: []string{
+			"create table test (pk int primary key, c0 int)",
+			"insert into test values (1,1);",
+			"call dolt_commit('-Am', 'seed table');",
+			"call dolt_checkout('-b', 'feature');",
+			"insert into test values (2,2);",
+			"call dolt_commit('-am', 'adding row 2');",
+			"call dolt_checkout('main');",
+			"insert into test values (3,3);",
+			"call dolt_commit('-am', 'adding row 3');",
+			"call dolt_merge('--no-ff', '-m', 'merge feature', 'feature');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_revert('-m', '3', 'HEAD');",
+				ExpectedErrStr: "invalid mainline parent number 3: commit has 2 parents",
+			},
+			{
+				Query:    "call dolt_revert('-m', '1', 'HEAD');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select * from test order by pk;",
+				Expected: []sql.Row{{1, 1}, {3, 3}},
+			},
+			{
+				Query:    "call dolt_reset('--hard', 'HEAD~1');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_revert('--mainline', '2', 'HEAD');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select * from test order by pk;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+		},
+	},
 	{
 		Name: "dolt_revert() detects conflicts",
 		SetUpScript: []string{
@@ -84,7 +128,126 @@ var RevertScripts = []queries.ScriptTest{
 		Assertions: []queries.ScriptTestAssertion{
 			{
 				Query:          "call dolt_revert('HEAD~1');",
-				ExpectedErrStr: "revert currently does not handle conflicts",
+				ExpectedErrStr: dsess.ErrUnresolvedConflictsAutoCommit.Error(),
+			},
+		},
+	},
+	{
+		Name: "dolt_revert() stops on conflicts and continues once they are resolved",
+		SetUpScript: []string{
+			"create table test (pk int primary key, c0 int)",
+			"insert into test values (1,1),(2,2),(3,3);",
+			"call dolt_commit('-Am', 'seed table');",
+			"update test set c0 = 42 where pk = 2;",
+			"call dolt_commit('-am', 'first change');",
+			"update test set c0 = 23 where pk = 2;",
+			"call dolt_commit('-am', 'second change');",
+			"insert into test values (4,4);",
+			"call dolt_commit('-am', 'third change');",
+			"set @@dolt_allow_commit_conflicts = 1;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_revert('HEAD', 'HEAD~2');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select is_merging, unmerged_tables from dolt_merge_status;",
+				Expected: []sql.Row{{true, "test"}},
+			},
+			{
+				Query:    "select base_c0, our_c0, their_c0 from dolt_conflicts_test;",
+				Expected: []sql.Row{{42, 23, 2}},
+			},
+			{
+				Query:          "call dolt_revert('--continue');",
+				ExpectedErrStr: dprocedures.ErrRevertUnresolvedConflicts.Error(),
+			},
+			{
+				Query:          "call dolt_revert('HEAD');",
+				ExpectedErrStr: dprocedures.ErrRevertInProgress.Error(),
+			},
+			{
+				Query:    "call dolt_conflicts_resolve('--theirs', 'test');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_revert('--continue');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select * from test order by pk;",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {3, 3}},
+			},
+			{
+				Query:    "select message from dolt_log limit 1;",
+				Expected: []sql.Row{{`Revert "third change" and "first change"`}},
+			},
+			{
+				// Assert that our new commit only has one parent (i.e. not a merge commit)
+				Query:    "select count(*) from dolt_commit_ancestors where commit_hash = hashof('HEAD');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select is_merging from dolt_merge_status;",
+				Expected: []sql.Row{{false}},
+			},
+		},
+	},
+	{
+		Name: "dolt_revert() skips or aborts conflicting commits",
+		SetUpScript: []string{
+			"create table test (pk int primary key, c0 int)",
+			"insert into test values (1,1),(2,2),(3,3);",
+			"call dolt_commit('-Am', 'seed table');",
+			"update test set c0 = 42 where pk = 2;",
+			"call dolt_commit('-am', 'first change');",
+			"update test set c0 = 23 where pk = 2;",
+			"call dolt_commit('-am', 'second change');",
+			"insert into test values (4,4);",
+			"call dolt_commit('-am', 'third change');",
+			"set @@dolt_allow_commit_conflicts = 1;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_revert('--skip');",
+				ExpectedErrStr: dprocedures.ErrNoRevertInProgress.Error(),
+			},
+			{
+				Query:    "call dolt_revert('HEAD~2', 'HEAD');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "call dolt_revert('--skip');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select * from test order by pk;",
+				Expected: []sql.Row{{1, 1}, {2, 23}, {3, 3}},
+			},
+			{
+				Query:    "select message from dolt_log limit 1;",
+				Expected: []sql.Row{{`Revert "third change"`}},
+			},
+			{
+				Query:    "call dolt_revert('HEAD~3');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "call dolt_revert('--abort');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select * from test order by pk;",
+				Expected: []sql.Row{{1, 1}, {2, 23}, {3, 3}},
+			},
+			{
+				Query:    "select count(*) from dolt_status;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:          "call dolt_revert('--abort');",
+				ExpectedErrStr: dprocedures.ErrNoRevertInProgress.Error(),
 			},
 		},
 	},
@@ -152,8 +315,28 @@ var RevertScripts = []queries.ScriptTest{
 		},
 		Assertions: []queries.ScriptTestAssertion{
 			{
-				Query:          "call dolt_revert('head~1');",
-				ExpectedErrStr: "revert currently does not handle constraint violations",
+				Query:    "set @@dolt_force_transaction_commit = 1;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "call dolt_revert('head~1');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select violation_type, pk from dolt_constraint_violations_test2;",
+				Expected: []sql.Row{{"not null", 2}},
+			},
+			{
+				Query:          "call dolt_revert('--continue');",
+				ExpectedErrStr: dprocedures.ErrRevertUnresolvedConflicts.Error(),
+			},
+			{
+				Query:    "call dolt_revert('--abort');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select * from test2 order by pk;",
+				Expected: []sql.Row{{1, 1}, {3, 3}},
 			},
 		},
 	},
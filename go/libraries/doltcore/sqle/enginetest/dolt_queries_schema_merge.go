@@ -2009,6 +2009,43 @@ var SchemaChangeTestsSchemaConflicts = []MergeScriptTest{
 			},
 		},
 	},
+	{
+		Name: "add a non-nullable column, with no default value, populated with --column-default",
+		AncSetUpScript: []string{
+			"set autocommit = 0;",
+			"CREATE table t (pk int primary key, col1 int);",
+			"INSERT into t values (1, 10);",
+		},
+		RightSetUpScript: []string{
+			"alter table t add column col3 int not null;",
+			"alter table t add index idx1 (col3, col1);",
+		},
+		LeftSetUpScript: []string{
+			"insert into t values (2, 20);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_merge('--column-default', 't.col3', 'right');",
+				ExpectedErrStr: merge.ErrInvalidColumnDefault.New("t.col3").Error(),
+			},
+			{
+				Query:    "call dolt_merge('--column-default', 't.col3 = col1 * 2, t.other = concat(1, 2)', 'right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 10, 0}, {2, 20, 40}},
+			},
+			{
+				Query:    "select pk from t where col3 = 40;",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "show create table t;",
+				Expected: []sql.Row{{"t", "CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  `col1` int,\n  `col3` int NOT NULL,\n  PRIMARY KEY (`pk`),\n  KEY `idx1` (`col3`,`col1`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin"}},
+			},
+		},
+	},
 	{
 		// This merge test reports a conflict on pk=1, because the tuple value is different on the left side, right
 		// side, and base. The value is the base is (10, '100'), on the right is nil, and on the left is ('100'),
@@ -2558,6 +2595,281 @@ var SchemaChangeTestsForJsonConflicts = []MergeScriptTest{
 			},
 		},
 	},
+	{
+		Name: "json conflicts report the paths of the conflicting values",
+		AncSetUpScript: []string{
+			"set autocommit = 0;",
+			"CREATE table t (pk int primary key, j json);",
+			`INSERT into t values (1, '{"a": 1, "b": {"c": 1}}');`,
+		},
+		RightSetUpScript: []string{
+			`update t set j = '{"a": 2, "b": {"c": 2}}';`,
+		},
+		LeftSetUpScript: []string{
+			`update t set j = '{"a": 3, "b": {"c": 2}}';`,
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{"", 0, 1, "conflicts found"}},
+			},
+			{
+				Query:    "select base_pk, dolt_conflict_json_paths from dolt_conflicts_t;",
+				Expected: []sql.Row{{1, `{"j": ["$.a"]}`}},
+			},
+		},
+	},
+	{
+		Name: "json arrays merge by index with @@dolt_json_array_merge",
+		AncSetUpScript: []string{
+			"set autocommit = 0;",
+			"set @@dolt_json_array_merge = 'index';",
+			"CREATE table t (pk int primary key, j json);",
+			`INSERT into t values (1, '{"a": [1, 2]}'), (2, '{"a": [1, 2]}');`,
+		},
+		RightSetUpScript: []string{
+			`update t set j = '{"a": [0, 2]}' where pk = 1;`,
+			`update t set j = '{"a": [1, 4]}' where pk = 2;`,
+		},
+		LeftSetUpScript: []string{
+			`update t set j = '{"a": [1, 3]}' where pk = 1;`,
+			`update t set j = '{"a": [1, 3]}' where pk = 2;`,
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{"", 0, 1, "conflicts found"}},
+			},
+			{
+				Query:    "select * from t where pk = 1;",
+				Expected: []sql.Row{{1, `{"a": [0, 3]}`}},
+			},
+			{
+				Query:    "select base_pk, dolt_conflict_json_paths from dolt_conflicts_t;",
+				Expected: []sql.Row{{2, `{"j": ["$.a[1]"]}`}},
+			},
+		},
+	},
+	{
+		Name: "json arrays merge as sets with @@dolt_json_array_merge",
+		AncSetUpScript: []string{
+			"set autocommit = 0;",
+			"set @@dolt_json_array_merge = 'set';",
+			"CREATE table t (pk int primary key, j json);",
+			`INSERT into t values (1, '{"tags": ["a", "b"]}');`,
+		},
+		RightSetUpScript: []string{
+			`update t set j = '{"tags": ["a", "b", "c"]}';`,
+		},
+		LeftSetUpScript: []string{
+			`update t set j = '{"tags": ["b"]}';`,
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, `{"tags": ["b", "c"]}`}},
+			},
+		},
+	},
+	{
+		Name: "json arrays merge by key with dolt_merge_config",
+		AncSetUpScript: []string{
+			"set autocommit = 0;",
+			"CREATE table t (pk int primary key, j json);",
+			`INSERT into t values (1, '[{"id": 1, "qty": 1}, {"id": 2, "qty": 1}]');`,
+			"INSERT into dolt_merge_config values ('t', 'j', 'key:id');",
+		},
+		RightSetUpScript: []string{
+			`update t set j = '[{"id": 1, "qty": 1}, {"id": 2, "qty": 5}]';`,
+		},
+		LeftSetUpScript: []string{
+			`update t set j = '[{"id": 1, "qty": 3}, {"id": 2, "qty": 1}]';`,
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, `[{"id": 1, "qty": 3}, {"id": 2, "qty": 5}]`}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge_config column strategies override table strategies",
+		AncSetUpScript: []string{
+			"set autocommit = 0;",
+			"CREATE table t (pk int primary key, j json, k json);",
+			`INSERT into t values (1, '{"x": [1, 2]}', '{"x": [1, 2], "y": 1}');`,
+			"INSERT into dolt_merge_config values ('t', '', 'set'), ('t', 'k', 'conflict');",
+		},
+		RightSetUpScript: []string{
+			`update t set j = '{"x": [1, 2, 3]}', k = '{"x": [1, 2, 3], "y": 1}';`,
+		},
+		LeftSetUpScript: []string{
+			`update t set j = '{"x": [2]}', k = '{"x": [0, 2], "y": 1}';`,
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from dolt_merge_config order by column_name;",
+				Expected: []sql.Row{{"t", "", "set"}, {"t", "k", "conflict"}},
+			},
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{"", 0, 1, "conflicts found"}},
+			},
+			{
+				Query:    "select base_pk, dolt_conflict_json_paths from dolt_conflicts_t;",
+				Expected: []sql.Row{{1, `{"k": ["$.x"]}`}},
+			},
+		},
+	},
+	{
+		Name: "invalid @@dolt_json_array_merge",
+		AncSetUpScript: []string{
+			"set autocommit = 0;",
+			"CREATE table t (pk int primary key, j json);",
+			`INSERT into t values (1, '{"a": [1, 2]}');`,
+		},
+		RightSetUpScript: []string{
+			`update t set j = '{"a": [0, 2]}';`,
+		},
+		LeftSetUpScript: []string{
+			`update t set j = '{"a": [1, 3]}';`,
+			"set @@dolt_json_array_merge = 'union';",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_merge('right');",
+				ExpectedErrStr: merge.ErrInvalidJsonArrayMerge.New("union").Error(),
+			},
+		},
+	},
+}
+
+var SchemaChangeTestsPrimaryKeys = []MergeScriptTest{
+	{
+		Name: "adding a column to the primary key",
+		AncSetUpScript: []string{
+			"CREATE table t (pk int primary key, col1 int not null, col2 varchar(100));",
+			"INSERT into t values (1, 10, '100'), (2, 20, '200');",
+		},
+		RightSetUpScript: []string{
+			"alter table t drop primary key, add primary key (pk, col1);",
+			"insert into t values (3, 30, '300');",
+		},
+		LeftSetUpScript: []string{
+			"update t set col2 = '2000' where pk = 2;",
+			"insert into t values (4, 40, '400');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "select column_name from information_schema.statistics where table_name = 't' and index_name = 'PRIMARY' order by seq_in_index;",
+				Expected: []sql.Row{{"pk"}, {"col1"}},
+			},
+			{
+				Query:    "select pk, col1, col2 from t order by pk;",
+				Expected: []sql.Row{{1, 10, "100"}, {2, 20, "2000"}, {3, 30, "300"}, {4, 40, "400"}},
+			},
+		},
+	},
+	{
+		Name: "removing a column from the primary key",
+		AncSetUpScript: []string{
+			"CREATE table t (pk1 int, pk2 int, col1 int, primary key (pk1, pk2));",
+			"INSERT into t values (1, 10, 100), (2, 20, 200);",
+		},
+		RightSetUpScript: []string{
+			"alter table t drop primary key, add primary key (pk1);",
+			"insert into t values (3, 30, 300);",
+		},
+		LeftSetUpScript: []string{
+			"update t set col1 = 1000 where pk1 = 1;",
+			"delete from t where pk1 = 2;",
+			"insert into t values (4, 40, 400);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "select column_name from information_schema.statistics where table_name = 't' and index_name = 'PRIMARY' order by seq_in_index;",
+				Expected: []sql.Row{{"pk1"}},
+			},
+			{
+				Query:    "select pk1, pk2, col1 from t order by pk1;",
+				Expected: []sql.Row{{1, 10, 1000}, {3, 30, 300}, {4, 40, 400}},
+			},
+		},
+	},
+	{
+		Name: "reordering the primary key columns",
+		AncSetUpScript: []string{
+			"CREATE table t (a int, b int, c int, primary key (a, b), index idx_c (c));",
+			"INSERT into t values (1, 2, 3), (2, 1, 4);",
+		},
+		RightSetUpScript: []string{
+			"alter table t drop primary key, add primary key (b, a);",
+			"insert into t values (3, 0, 5);",
+		},
+		LeftSetUpScript: []string{
+			"update t set c = 6 where a = 1;",
+			"insert into t values (4, 3, 7);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "select column_name from information_schema.statistics where table_name = 't' and index_name = 'PRIMARY' order by seq_in_index;",
+				Expected: []sql.Row{{"b"}, {"a"}},
+			},
+			{
+				Query:    "select * from t order by b, a;",
+				Expected: []sql.Row{{3, 0, 5}, {2, 1, 4}, {1, 2, 6}, {4, 3, 7}},
+			},
+			{
+				Query:    "select a, b from t where c = 6;",
+				Expected: []sql.Row{{1, 2}},
+			},
+		},
+	},
+	{
+		Name: "both sides make the same primary key change",
+		AncSetUpScript: []string{
+			"CREATE table t (pk int primary key, col1 int not null);",
+			"INSERT into t values (1, 10), (2, 20);",
+		},
+		RightSetUpScript: []string{
+			"alter table t drop primary key, add primary key (col1);",
+			"insert into t values (3, 30);",
+		},
+		LeftSetUpScript: []string{
+			"alter table t drop primary key, add primary key (col1);",
+			"delete from t where pk = 1;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "select pk, col1 from t order by col1;",
+				Expected: []sql.Row{{2, 20}, {3, 30}},
+			},
+		},
+	},
 }
 
 // These tests are not run because they cause panics during set-up.
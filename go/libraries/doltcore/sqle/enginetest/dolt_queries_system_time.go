@@ -0,0 +1,175 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"time"
+
+	"github.com/dolthub/go-mysql-server/enginetest/queries"
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtablefunctions"
+)
+
+func systemTimeDate(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04:05", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+var systemTimeForever = time.Date(9999, 12, 31, 23, 59, 59, 999999000, time.UTC)
+
+var DoltSystemTimeScripts = []queries.ScriptTest{
+	{
+		Name: "FOR SYSTEM_TIME ranges over a table with a primary key",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c varchar(10));",
+			"insert into t values (1, 'a'), (2, 'b');",
+			"call dolt_commit('-Am', 'one', '--date', '2023-01-01T00:00:00');",
+			"update t set c = 'x' where pk = 1;",
+			"call dolt_commit('-am', 'two', '--date', '2023-02-01T00:00:00');",
+			"delete from t where pk = 2;",
+			"insert into t values (3, 'c');",
+			"call dolt_commit('-am', 'three', '--date', '2023-03-01T00:00:00');",
+			// uncommitted changes aren't part of the history
+			"insert into t values (4, 'd');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select * from t for system_time all order by system_time_start, pk;",
+				Expected: []sql.Row{
+					{1, "a", systemTimeDate("2023-01-01 00:00:00"), systemTimeDate("2023-02-01 00:00:00")},
+					{2, "b", systemTimeDate("2023-01-01 00:00:00"), systemTimeDate("2023-03-01 00:00:00")},
+					{1, "x", systemTimeDate("2023-02-01 00:00:00"), systemTimeForever},
+					{3, "c", systemTimeDate("2023-03-01 00:00:00"), systemTimeForever},
+				},
+			},
+			{
+				Query:    "select pk, c from t for system_time from '2023-02-01' to '2023-03-01' order by pk, c;",
+				Expected: []sql.Row{{1, "x"}, {2, "b"}},
+			},
+			{
+				Query:    "select pk, c from t for system_time between '2023-02-01' and '2023-03-01' order by pk, c;",
+				Expected: []sql.Row{{1, "x"}, {2, "b"}, {3, "c"}},
+			},
+			{
+				Query:    "select pk, c from t for system_time contained in ('2023-01-01', '2023-03-01') order by pk, c;",
+				Expected: []sql.Row{{1, "a"}, {2, "b"}},
+			},
+			{
+				// commit specs stand for the dates of the commits they resolve to
+				Query:    "select pk, c from t for system_time from 'HEAD~1' to 'HEAD' order by pk, c;",
+				Expected: []sql.Row{{1, "x"}, {2, "b"}},
+			},
+			{
+				Query:    "select x.c from t for system_time between '2023-01-15' and '2023-01-20' as x where x.pk = 1;",
+				Expected: []sql.Row{{"a"}},
+			},
+			{
+				Query:    "select t.c, u.c from t for system_time all join t u on t.pk = u.pk where t.system_time_end < '2024-01-01' order by 1;",
+				Expected: []sql.Row{{"a", "x"}},
+			},
+			{
+				Query:    "select count(*) from mydb.t for system_time all;",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				Query:    "select count(*) from t for system_time as of 'HEAD~1';",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:          "select * from t for system_time from 'nope' to 'HEAD';",
+				ExpectedErrStr: "invalid FOR SYSTEM_TIME bound 'nope': branch not found: nope",
+			},
+			{
+				Query:       "select * from dolt_system_time('t', 'sometimes', null, null);",
+				ExpectedErr: dtablefunctions.ErrInvalidSystemTimeMode,
+			},
+			{
+				Query:       "select * from missing for system_time all;",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+		},
+	},
+	{
+		Name: "FOR SYSTEM_TIME over keyless tables and schema changes",
+		SetUpScript: []string{
+			"create table k (c int);",
+			"insert into k values (7), (7), (8);",
+			"call dolt_commit('-Am', 'one', '--date', '2023-01-01T00:00:00');",
+			"delete from k where c = 7 limit 1;",
+			"call dolt_commit('-am', 'two', '--date', '2023-02-01T00:00:00');",
+			"alter table k add column d int;",
+			"call dolt_commit('-am', 'three', '--date', '2023-03-01T00:00:00');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select c, d, system_time_start, system_time_end from k for system_time all order by system_time_start, system_time_end, c;",
+				Expected: []sql.Row{
+					{7, nil, systemTimeDate("2023-01-01 00:00:00"), systemTimeDate("2023-02-01 00:00:00")},
+					{7, nil, systemTimeDate("2023-01-01 00:00:00"), systemTimeDate("2023-03-01 00:00:00")},
+					{8, nil, systemTimeDate("2023-01-01 00:00:00"), systemTimeDate("2023-03-01 00:00:00")},
+					{7, nil, systemTimeDate("2023-03-01 00:00:00"), systemTimeForever},
+					{8, nil, systemTimeDate("2023-03-01 00:00:00"), systemTimeForever},
+				},
+			},
+		},
+	},
+	{
+		Name: "FOR SYSTEM_TIME ranges that start after the beginning of the history",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"create table k (c int);",
+			"insert into t values (1, 1);",
+			"insert into k values (7), (7);",
+			"call dolt_commit('-Am', 'one', '--date', '2023-01-01T00:00:00');",
+			"update t set c = 2;",
+			"insert into k values (7);",
+			"call dolt_commit('-am', 'two', '--date', '2023-02-01T00:00:00');",
+			"update t set c = 3;",
+			"delete from k limit 1;",
+			"call dolt_commit('-am', 'three', '--date', '2023-03-01T00:00:00');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select pk, c from t for system_time contained in ('2023-02-01', '2023-03-01');",
+				Expected: []sql.Row{{1, 2}},
+			},
+			{
+				Query:    "select pk, c from t for system_time from '2023-02-15' to '2023-02-20';",
+				Expected: []sql.Row{{1, 2}},
+			},
+			{
+				Query:    "select pk, c from t for system_time between '2023-03-01' and '2023-04-01';",
+				Expected: []sql.Row{{1, 3}},
+			},
+			{
+				// the copy of a keyless row added last is the first one deleted
+				Query: "select c, system_time_start, system_time_end from k for system_time all order by system_time_start, system_time_end;",
+				Expected: []sql.Row{
+					{7, systemTimeDate("2023-01-01 00:00:00"), systemTimeForever},
+					{7, systemTimeDate("2023-01-01 00:00:00"), systemTimeForever},
+					{7, systemTimeDate("2023-02-01 00:00:00"), systemTimeDate("2023-03-01 00:00:00")},
+				},
+			},
+			{
+				Query:    "select count(*) from k for system_time from '2023-02-15' to '2023-04-01';",
+				Expected: []sql.Row{{3}},
+			},
+		},
+	},
+}
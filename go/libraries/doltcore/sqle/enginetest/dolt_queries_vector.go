@@ -0,0 +1,122 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"github.com/dolthub/go-mysql-server/enginetest/queries"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+var DoltVectorIndexScripts = []queries.ScriptTest{
+	{
+		Name: "vector index lookups return the nearest rows",
+		SetUpScript: []string{
+			"create table vectors (id int primary key, label varchar(20), v json);",
+			`insert into vectors values (1, 'a', '[4.0,3.0]'), (2, 'b', '[0.0,0.0]'), (3, 'c', '[-1.0,1.0]'), (4, 'd', '[0.0,-2.0]'), (5, 'e', null);`,
+			"create vector index v_idx on vectors(v);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select * from vectors order by vec_distance('[0.0,0.0]', v) limit 3",
+				Expected: []sql.Row{
+					{5, "e", nil},
+					{2, "b", types.MustJSON(`[0.0, 0.0]`)},
+					{3, "c", types.MustJSON(`[-1.0, 1.0]`)},
+				},
+				ExpectedIndexes: []string{"v_idx"},
+			},
+			{
+				Query:    "select id from vectors order by vec_distance(v, '[4.0,4.0]') limit 2",
+				Expected: []sql.Row{{5}, {1}},
+			},
+			{
+				Query:    "select id from vectors order by vec_distance('[4.0,4.0]', v) limit 0",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "create vector index v_idx2 on vectors(v, label);",
+				ExpectedErrStr: "vector indexes must have exactly one column",
+			},
+			{
+				Query:          "create vector index v_idx2 on vectors(label);",
+				ExpectedErrStr: "vector index column 'label' must be of type JSON",
+			},
+		},
+	},
+	{
+		Name: "vector index lookups into large indexes find existing vectors",
+		SetUpScript: []string{
+			"create table vectors (id int primary key, v json, vector index (v));",
+			"set @@cte_max_recursion_depth = 5000;",
+			"insert into vectors with recursive s(i) as (select 1 union all select i + 1 from s where i < 3000) select i, json_array(cast(i % 97 as double), cast(i % 89 as double), cast(i div 97 as double)) from s;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:           "select * from vectors order by vec_distance('[70, 77, 12]', v) limit 1",
+				Expected:        []sql.Row{{1234, types.MustJSON(`[70.0, 77.0, 12.0]`)}},
+				ExpectedIndexes: []string{"v"},
+			},
+		},
+	},
+	{
+		Name: "vector indexes are versioned and merged",
+		SetUpScript: []string{
+			"create table vectors (id int primary key, v json);",
+			"create vector index v_idx on vectors(v);",
+			`insert into vectors values (1, '[4.0,3.0]'), (2, '[0.0,0.0]'), (3, '[-1.0,1.0]');`,
+			"call dolt_commit('-Am', 'add vectors');",
+			"call dolt_checkout('-b', 'other');",
+			`insert into vectors values (4, '[0.5,0.5]');`,
+			"call dolt_commit('-am', 'add a vector on other');",
+			"call dolt_checkout('main');",
+			`update vectors set v = '[0.1,0.1]' where id = 1;`,
+			"call dolt_commit('-am', 'move a vector on main');",
+			"call dolt_merge('other');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select * from vectors order by vec_distance('[0.0,0.0]', v) limit 3",
+				Expected: []sql.Row{
+					{2, types.MustJSON(`[0.0, 0.0]`)},
+					{1, types.MustJSON(`[0.1, 0.1]`)},
+					{4, types.MustJSON(`[0.5, 0.5]`)},
+				},
+				ExpectedIndexes: []string{"v_idx"},
+			},
+			{
+				Query:    "select id from vectors as of 'HEAD~1' order by vec_distance('[0.0,0.0]', v) limit 2",
+				Expected: []sql.Row{{2}, {1}},
+			},
+			{
+				Query:    "select id from vectors as of 'other' order by vec_distance('[0.0,0.0]', v) limit 2",
+				Expected: []sql.Row{{2}, {4}},
+			},
+			{
+				Query:    "select v from vectors where id = 1",
+				Expected: []sql.Row{{types.MustJSON(`[0.1, 0.1]`)}},
+			},
+			{
+				Query: "show create table vectors",
+				Expected: []sql.Row{{"vectors", "CREATE TABLE `vectors` (\n" +
+					"  `id` int NOT NULL,\n" +
+					"  `v` json,\n" +
+					"  PRIMARY KEY (`id`),\n" +
+					"  VECTOR KEY `v_idx` (`v`)\n" +
+					") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin"}},
+			},
+		},
+	},
+}
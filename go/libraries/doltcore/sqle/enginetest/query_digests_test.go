@@ -0,0 +1,65 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+var QueryDigestTests = []BranchControlTest{
+	{
+		Name: "query digests require privileges",
+		SetUpScript: []string{
+			"CREATE USER alice@localhost;",
+			"GRANT SELECT, EXECUTE ON *.* TO alice@localhost;",
+			"CREATE USER bob@localhost;",
+			"GRANT SELECT, EXECUTE, PROCESS, SUPER ON *.* TO bob@localhost;",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_query_digests;",
+				ExpectedErr: sql.ErrPrivilegeCheckFailed,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "CALL dolt_reset_query_digests();",
+				ExpectedErr: sql.ErrPrivilegeCheckFailed,
+			},
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "CALL dolt_reset_query_digests();",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_query_digests;",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+}
+
+func TestQueryDigests(t *testing.T) {
+	for _, test := range QueryDigestTests {
+		runBranchControlTest(t, test)
+	}
+}
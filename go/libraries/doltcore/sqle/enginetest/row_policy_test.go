@@ -0,0 +1,673 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/rowpolicy"
+)
+
+// rowPolicySetUp creates two tenants' users, each given a policy that restricts them to their own rows of t.
+var rowPolicySetUp = []string{
+	"CREATE USER alice@localhost;",
+	"GRANT ALL ON *.* TO alice@localhost;",
+	"CREATE USER bob@localhost;",
+	"GRANT ALL ON *.* TO bob@localhost;",
+	"CREATE TABLE t (id INT PRIMARY KEY, tenant VARCHAR(10), v INT, KEY (tenant));",
+	"INSERT INTO t VALUES (1, 'a', 10), (2, 'b', 20), (3, 'a', 30), (4, 'b', 40);",
+	"CALL dolt_create_policy('tenant_a', 't', '--user', 'alice', '--using', 'tenant = ''a''');",
+	"CALL dolt_create_policy('tenant_b', 't', '--user', 'bob', '--using', 'tenant = ''b''');",
+}
+
+var RowPolicyTests = []BranchControlTest{
+	{
+		Name:        "policies filter reads",
+		SetUpScript: rowPolicySetUp,
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT * FROM t ORDER BY id;",
+				Expected: []sql.Row{{1, "a", 10}, {3, "a", 30}},
+			},
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "SELECT * FROM t ORDER BY id;",
+				Expected: []sql.Row{{2, "b", 20}, {4, "b", 40}},
+			},
+			{ // no policy applies to root
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT id FROM t ORDER BY id;",
+				Expected: []sql.Row{{1}, {2}, {3}, {4}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT v FROM t ORDER BY v;",
+				Expected: []sql.Row{{10}, {30}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM t;",
+				Expected: []sql.Row{{2}},
+			},
+			{ // lookups on the primary key and on secondary indexes are filtered too
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT * FROM t WHERE id = 2;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT * FROM t WHERE tenant = 'b';",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "SELECT a.id, b.id FROM t a JOIN t b ON a.id = b.id ORDER BY a.id;",
+				Expected: []sql.Row{{2, 2}, {4, 4}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT tenant, SUM(v) FROM t GROUP BY tenant;",
+				Expected: []sql.Row{{"a", float64(40)}},
+			},
+		},
+	},
+	{
+		Name:        "policies check writes",
+		SetUpScript: rowPolicySetUp,
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "INSERT INTO t VALUES (5, 'a', 50);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "INSERT INTO t VALUES (6, 'b', 60);",
+				ExpectedErr: rowpolicy.ErrNewRowViolation,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "UPDATE t SET tenant = 'b' WHERE id = 1;",
+				ExpectedErr: rowpolicy.ErrNewRowViolation,
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "UPDATE t SET v = v + 1;",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 3, Info: plan.UpdateInfo{Matched: 3, Updated: 3}}}},
+			},
+			{ // only alice's rows are deleted, even though the statement is run as a truncate
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "DELETE FROM t;",
+				Expected: []sql.Row{{types.NewOkResult(3)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM t ORDER BY id;",
+				Expected: []sql.Row{{2, "b", 20}, {4, "b", 40}},
+			},
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "TRUNCATE TABLE t;",
+				Expected: []sql.Row{{types.NewOkResult(2)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM t;",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "policies for a single command",
+		SetUpScript: []string{
+			"CREATE USER alice@localhost;",
+			"GRANT ALL ON *.* TO alice@localhost;",
+			"CREATE TABLE t (id INT PRIMARY KEY, owner VARCHAR(32));",
+			"INSERT INTO t VALUES (1, 'alice'), (2, 'bob');",
+			"CALL dolt_create_policy('own_rows', 't', '--for', 'delete', '--using', 'owner = substring_index(current_user(), ''@'', 1)');",
+			"CALL dolt_create_policy('insert_own', 't', '--for', 'insert', '--user', 'alice', '--check', 'owner = ''alice''');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{ // the policies don't restrict reads
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT * FROM t ORDER BY id;",
+				Expected: []sql.Row{{1, "alice"}, {2, "bob"}},
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "DELETE FROM t WHERE id = 2;",
+				ExpectedErr: rowpolicy.ErrExistingRowViolation,
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "DELETE FROM t WHERE id = 1;",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "INSERT INTO t VALUES (3, 'bob');",
+				ExpectedErr: rowpolicy.ErrNewRowViolation,
+			},
+			{ // the insert policy only applies to alice
+				User:     "root",
+				Host:     "localhost",
+				Query:    "INSERT INTO t VALUES (3, 'bob');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "UPDATE t SET owner = 'carol' WHERE id = 2;",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+		},
+	},
+	{
+		Name: "policies for matching branches",
+		SetUpScript: append(rowPolicySetUp,
+			"CALL dolt_create_policy('main_only', 't', '--user', 'bob', '--branch', 'main', '--using', 'FALSE');",
+			"CALL dolt_drop_policy('tenant_b');",
+			"CALL dolt_commit('-Am', 'policies');",
+			"CALL dolt_branch('other');",
+			"CALL dolt_branch('tenant_branch');",
+			"CALL dolt_create_policy('tenant_branch_b', 't', '--user', 'bob', '--branch', 'tenant_branch', '--using', 'tenant = ''b''');",
+		),
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "SELECT * FROM t;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM `mydb/other`.t;",
+				Expected: []sql.Row{{4}},
+			},
+			{ // AS OF queries use the policies of the branch they read from, rather than those of the session's branch
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM t AS OF 'other';",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "SELECT id FROM t AS OF 'tenant_branch' ORDER BY id;",
+				Expected: []sql.Row{{2}, {4}},
+			},
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM `mydb/tenant_branch`.t;",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM `mydb/other`.t AS OF 'main';",
+				Expected: []sql.Row{{0}},
+			},
+			{ // every policy applies to commits, whichever branch they are on
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "SELECT id FROM t AS OF 'HEAD' ORDER BY id;",
+				Expected: []sql.Row{{2}, {4}},
+			},
+			{ // alice's policy applies to every branch
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM `mydb/other`.t;",
+				Expected: []sql.Row{{2}},
+			},
+			{ // policies are read from the default branch, so they apply to historical queries as well
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM t AS OF 'HEAD';",
+				Expected: []sql.Row{{2}},
+			},
+		},
+	},
+	{
+		Name: "policies apply to branches and commits made before them",
+		SetUpScript: []string{
+			"CREATE USER alice@localhost;",
+			"GRANT ALL ON *.* TO alice@localhost;",
+			"CREATE TABLE t (id INT PRIMARY KEY, tenant VARCHAR(10));",
+			"INSERT INTO t VALUES (1, 'a'), (2, 'b'), (3, 'a');",
+			"CALL dolt_commit('-Am', 'before policies');",
+			"CALL dolt_tag('v1');",
+			"CALL dolt_branch('old');",
+			"CALL dolt_create_policy('tenant_a', 't', '--user', 'alice', '--using', 'tenant = ''a''');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM `mydb/old`.t;",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM `mydb/v1`.t;",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM t AS OF 'v1';",
+				Expected: []sql.Row{{2}},
+			},
+			{ // alice becomes the admin of the branches she creates, but that doesn't let her change the policies
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "CALL dolt_branch('mine');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "USE `mydb/mine`;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM t;",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "CALL dolt_drop_policy('tenant_a');",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{ // nor add policies to it, as they would apply to every branch once merged
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "CALL dolt_create_policy('mine_only', 't', '--user', 'alice', '--branch', 'mine', '--using', 'TRUE');",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "USE mydb;",
+				Expected: []sql.Row{},
+			},
+			{ // statements that would remove the policies from the default branch are rejected
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "DROP TABLE dolt_policies;",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "ROLLBACK;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM t;",
+				Expected: []sql.Row{{2}},
+			},
+		},
+	},
+	{
+		Name: "policies restrict the system tables and functions that read unfiltered rows",
+		SetUpScript: append(rowPolicySetUp,
+			"CALL dolt_commit('-Am', 'policies');",
+			"UPDATE t SET v = v + 1;",
+			"CALL dolt_commit('-am', 'update');",
+		),
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_history_t;",
+				ExpectedErr: rowpolicy.ErrRestrictedAccess,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_diff_t;",
+				ExpectedErr: rowpolicy.ErrRestrictedAccess,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_commit_diff_t WHERE from_commit = 'HEAD~1' AND to_commit = 'HEAD';",
+				ExpectedErr: rowpolicy.ErrRestrictedAccess,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_blame_t;",
+				ExpectedErr: rowpolicy.ErrRestrictedAccess,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_conflicts_t;",
+				ExpectedErr: rowpolicy.ErrRestrictedAccess,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_constraint_violations_t;",
+				ExpectedErr: rowpolicy.ErrRestrictedAccess,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_workspace_t;",
+				ExpectedErr: rowpolicy.ErrRestrictedAccess,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_diff('HEAD~1', 'HEAD', 't');",
+				ExpectedErr: rowpolicy.ErrRestrictedAccess,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_patch('HEAD~1', 'HEAD', 't');",
+				ExpectedErr: rowpolicy.ErrRestrictedAccess,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_system_time('t', 'all', NULL, NULL);",
+				ExpectedErr: rowpolicy.ErrRestrictedAccess,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "SELECT * FROM t FOR SYSTEM_TIME ALL;",
+				ExpectedErr: rowpolicy.ErrRestrictedAccess,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "CALL dolt_cdc();",
+				ExpectedErr: rowpolicy.ErrRestrictedAccess,
+			},
+			{ // the schema of the table can still be diffed
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_patch('HEAD~1', 'HEAD', 't') WHERE diff_type = 'schema';",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_history_t;",
+				Expected: []sql.Row{{8}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_diff('HEAD~1', 'HEAD', 't');",
+				Expected: []sql.Row{{4}},
+			},
+		},
+	},
+	{
+		Name:        "only admins can change policies",
+		SetUpScript: rowPolicySetUp,
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "CALL dolt_drop_policy('tenant_a');",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "CALL dolt_create_policy('all_rows', 't', '--user', 'alice', '--using', 'TRUE');",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "DELETE FROM dolt_policies;",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "UPDATE dolt_policies SET using_expr = 'TRUE';",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT name, table_name, command, user, host, branch, using_expr, check_expr FROM dolt_policies ORDER BY name;",
+				Expected: []sql.Row{{"tenant_a", "t", "all", "alice", "%", "%", "tenant = 'a'", nil}, {"tenant_b", "t", "all", "bob", "%", "%", "tenant = 'b'", nil}},
+			},
+			{ // policies apply to every branch, so administering main isn't enough to change them
+				User:     "root",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_control VALUES ('mydb', 'main', 'alice', 'localhost', 'admin');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "UPDATE dolt_policies SET using_expr = 'TRUE' WHERE name = 'tenant_a';",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{ // admins of every branch can change them
+				User:     "root",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_control VALUES ('mydb', '%', 'alice', 'localhost', 'admin');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "UPDATE dolt_policies SET using_expr = 'TRUE' WHERE name = 'tenant_a';",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM t;",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "CALL dolt_drop_policy('tenant_b');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "CALL dolt_drop_policy('tenant_a');",
+				Expected: []sql.Row{{0}},
+			},
+			{ // dropping the last policy removes the table
+				User:        "root",
+				Host:        "localhost",
+				Query:       "SELECT COUNT(*) FROM dolt_policies;",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT table_name, staged, status FROM dolt_status;",
+				Expected: []sql.Row{{"t", false, "new table"}},
+			},
+		},
+	},
+	{
+		Name: "policies are versioned and take effect from the default branch",
+		SetUpScript: []string{
+			"CREATE USER alice@localhost;",
+			"GRANT ALL ON *.* TO alice@localhost;",
+			"CREATE TABLE t (id INT PRIMARY KEY, tenant VARCHAR(10));",
+			"INSERT INTO t VALUES (1, 'a'), (2, 'b');",
+			"CALL dolt_create_policy('tenant_a', 't', '--user', 'alice', '--using', 'tenant = ''a''');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT table_name, staged, status FROM dolt_status ORDER BY table_name;",
+				Expected: []sql.Row{{"dolt_policies", false, "new table"}, {"t", false, "new table"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL dolt_commit('-Am', 'policies');",
+				Expected: []sql.Row{{doltCommit}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL dolt_checkout('-b', 'other');",
+				Expected: []sql.Row{{0, "Switched to branch 'other'"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL dolt_drop_policy('tenant_a');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL dolt_create_policy('nothing', 't', '--user', 'alice', '--using', 'FALSE');",
+				Expected: []sql.Row{{0}},
+			},
+			{ // policies changed on another branch don't apply until they are merged into the default branch
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT id FROM t;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL dolt_commit('-am', 'other policies');",
+				Expected: []sql.Row{{doltCommit}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL dolt_checkout('main');",
+				Expected: []sql.Row{{0, "Switched to branch 'main'"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL dolt_merge('other');",
+				Expected: []sql.Row{{doltCommit, 1, 0, "merge successful"}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT id FROM t;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT id FROM `mydb/other`.t;",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "invalid policies",
+		SetUpScript: []string{
+			"CREATE TABLE t (id INT PRIMARY KEY, tenant VARCHAR(10));",
+			"CALL dolt_create_policy('p', 't', '--using', 'tenant = ''a''');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				Query:       "CALL dolt_create_policy('p', 't', '--using', 'TRUE');",
+				ExpectedErr: rowpolicy.ErrPolicyExists,
+			},
+			{
+				Query:       "CALL dolt_create_policy('q', 'nope', '--using', 'TRUE');",
+				ExpectedErr: rowpolicy.ErrTableNotFound,
+			},
+			{
+				Query:       "CALL dolt_create_policy('q', 'dolt_log', '--using', 'TRUE');",
+				ExpectedErr: rowpolicy.ErrSystemTable,
+			},
+			{
+				Query:       "CALL dolt_create_policy('q', 't');",
+				ExpectedErr: rowpolicy.ErrMissingExpression,
+			},
+			{
+				Query:       "CALL dolt_create_policy('q', 't', '--for', 'merge', '--using', 'TRUE');",
+				ExpectedErr: rowpolicy.ErrInvalidCommand,
+			},
+			{
+				Query:       "CALL dolt_create_policy('q', 't', '--using', 'nope = 1');",
+				ExpectedErr: rowpolicy.ErrInvalidExpression,
+			},
+			{
+				Query:       "CALL dolt_drop_policy('q');",
+				ExpectedErr: rowpolicy.ErrPolicyNotFound,
+			},
+			{
+				Query:    "SELECT name FROM dolt_policies;",
+				Expected: []sql.Row{{"p"}},
+			},
+		},
+	},
+}
+
+func TestRowPolicies(t *testing.T) {
+	for _, test := range RowPolicyTests {
+		runBranchControlTest(t, test)
+	}
+}
@@ -216,6 +216,11 @@ func validateKeylessIndex(ctx context.Context, sch schema.Schema, def schema.Ind
 				}
 				cell := tree.ZCell(geom.(sqltypes.GeometryValue))
 				field = cell[:]
+			} else if def.IsVector() {
+				field, err = vectorCellField(ctx, vd, j+1, value, secondary.NodeStore())
+				if err != nil {
+					return err
+				}
 			}
 
 			// Apply prefix lengths if they are configured
@@ -311,6 +316,11 @@ func validatePkIndex(ctx context.Context, sch schema.Schema, def schema.Index, p
 					}
 					cell := tree.ZCell(geom.(sqltypes.GeometryValue))
 					field = cell[:]
+				} else if def.IsVector() {
+					field, err = vectorCellField(ctx, vd, j-pkSize, value, secondary.NodeStore())
+					if err != nil {
+						return err
+					}
 				}
 
 				// Apply prefix lengths if they are configured
@@ -390,6 +400,20 @@ func dereferenceContent(ctx context.Context, tableValueDescriptor val.TupleDesc,
 // and return a GeometryType. |tableValueDescriptor| is the tuple descriptor for the value tuple of the main
 // table, |tablePos| is the field index into the value tuple, and |tuple| is the value tuple from the
 // main table.
+// vectorCellField returns the vector index Cell of the vector in field |tablePos| of |tuple|, or nil if it is NULL.
+func vectorCellField(ctx context.Context, tableValueDescriptor val.TupleDesc, tablePos int, tuple val.Tuple, ns tree.NodeStore) ([]byte, error) {
+	v, err := tree.GetField(ctx, tableValueDescriptor, tablePos, tuple, ns)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	vec, err := sql.ConvertToVector(v)
+	if err != nil {
+		return nil, err
+	}
+	cell := tree.VectorCell(vec)
+	return cell[:], nil
+}
+
 func dereferenceGeometry(ctx context.Context, tableValueDescriptor val.TupleDesc, tablePos int, tuple val.Tuple, ns tree.NodeStore) (interface{}, error) {
 	v, err := tree.GetField(ctx, tableValueDescriptor, tablePos, tuple, ns)
 	if err != nil {
@@ -71,6 +71,30 @@ func ResolveCheckExpression(ctx *sql.Context, tableName string, sch schema.Schem
 	return nil, fmt.Errorf("unable to find check expression")
 }
 
+// ResolveRowExpression returns a sql.Expression for the boolean expression |expr| over the columns of a table with
+// schema |sch|. The returned expression is evaluated against complete rows of the table, in schema order.
+func ResolveRowExpression(ctx *sql.Context, tableName string, sch schema.Schema, expr string) (sql.Expression, error) {
+	// The expression is resolved as an extra check constraint on the table, whose expressions are resolved against
+	// complete rows in the same way.
+	const checkName = "dolt_row_expression"
+	sch = sch.Copy()
+	if _, err := sch.Checks().AddCheck(checkName, expr, true); err != nil {
+		return nil, err
+	}
+	ct, err := parseCreateTable(ctx, tableName, sch)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, check := range ct.Checks() {
+		if check.Name == checkName {
+			return check.Expr, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to find row expression")
+}
+
 func stripTableNamesFromExpression(expr sql.Expression) sql.Expression {
 	e, _, _ := transform.Expr(expr, func(e sql.Expression) (sql.Expression, transform.TreeIdentity, error) {
 		if col, ok := e.(*expression.GetField); ok {
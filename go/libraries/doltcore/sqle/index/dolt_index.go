@@ -23,6 +23,7 @@ import (
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function/vector"
 	"github.com/dolthub/go-mysql-server/sql/fulltext"
 	sqltypes "github.com/dolthub/go-mysql-server/sql/types"
 
@@ -393,6 +394,7 @@ func getSecondaryIndex(ctx context.Context, db, tbl string, t *doltdb.Table, sch
 		unique:                        idx.IsUnique(),
 		spatial:                       idx.IsSpatial(),
 		fulltext:                      idx.IsFullText(),
+		vector:                        idx.IsVector(),
 		isPk:                          false,
 		comment:                       idx.Comment(),
 		vrw:                           vrw,
@@ -555,6 +557,7 @@ type doltIndex struct {
 	unique   bool
 	spatial  bool
 	fulltext bool
+	vector   bool
 	isPk     bool
 	comment  string
 	order    sql.IndexOrder
@@ -613,13 +616,20 @@ var _ DoltIndex = (*doltIndex)(nil)
 var _ sql.ExtendedIndex = (*doltIndex)(nil)
 
 // CanSupport implements sql.Index
-func (di *doltIndex) CanSupport(...sql.Range) bool {
-	return true
+func (di *doltIndex) CanSupport(ranges ...sql.Range) bool {
+	// vector indexes are ordered by the Cells of their vectors, which are meaningless for range lookups, so
+	// they only support the unbounded lookups used to order by distance
+	return !di.vector || len(ranges) == 0
 }
 
 // CanSupportOrderBy implements the interface sql.Index.
-func (di *doltIndex) CanSupportOrderBy(_ sql.Expression) bool {
-	return false
+func (di *doltIndex) CanSupportOrderBy(expr sql.Expression) bool {
+	// lookups read vectors from the primary index, which doesn't store virtual columns
+	if !di.vector || schema.IsKeyless(di.tableSch) || di.columns[0].Virtual {
+		return false
+	}
+	dist, ok := expr.(*vector.Distance)
+	return ok && vectorDistanceSupported(dist.DistanceType)
 }
 
 // ColumnExpressionTypes implements the interface sql.Index.
@@ -842,7 +852,7 @@ func (di *doltIndex) coversColumns(s *durableIndexState, cols []uint64) bool {
 		return false
 	}
 
-	if di.IsSpatial() {
+	if di.IsSpatial() || di.IsVector() {
 		return false
 	}
 
@@ -993,7 +1003,7 @@ func (di *doltIndex) IsFullText() bool {
 
 // IsVector implements sql.Index
 func (di *doltIndex) IsVector() bool {
-	return false
+	return di.vector
 }
 
 // IsPrimaryKey implements DoltIndex.
@@ -109,6 +109,9 @@ func NewRangePartitionIter(ctx *sql.Context, t DoltTableable, lookup sql.IndexLo
 	if _, ok := lookup.Ranges.(DoltgresRangeCollection); ok {
 		return NewDoltgresPartitionIter(ctx, lookup)
 	}
+	if lookup.VectorOrderAndLimit.OrderBy != nil {
+		return newVectorPartitionIter(lookup), nil
+	}
 	mysqlRanges := lookup.Ranges.(sql.MySQLRangeCollection)
 	idx := lookup.Index.(*doltIndex)
 	if lookup.IsPointLookup && isDoltFmt {
@@ -632,7 +635,14 @@ func (ib *nonCoveringIndexImplBuilder) NewRangeMapIter(ctx context.Context, r pr
 
 // NewPartitionRowIter implements IndexScanBuilder
 func (ib *nonCoveringIndexImplBuilder) NewPartitionRowIter(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
-	rangeIter, err := ib.rangeIter(ctx, part)
+	var rangeIter prolly.MapIter
+	var err error
+	if p, ok := part.(vectorPartition); ok {
+		// vector indexes don't cover their vectors, so lookups into them read the primary index
+		rangeIter, err = newVectorIndexIter(ctx, ib.vectorLookup(), p.orderAndLimit)
+	} else {
+		rangeIter, err = ib.rangeIter(ctx, part)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -650,6 +660,20 @@ func (ib *nonCoveringIndexImplBuilder) NewPartitionRowIter(ctx *sql.Context, par
 	}, nil
 }
 
+func (ib *nonCoveringIndexImplBuilder) vectorLookup() *vectorLookup {
+	vecOrd := 0
+	vecTag := ib.idx.columns[0].Tag
+	for _, col := range ib.idx.tableSch.GetNonPKCols().GetColumns() {
+		if col.Tag == vecTag {
+			break
+		}
+		if !col.Virtual {
+			vecOrd++
+		}
+	}
+	return &vectorLookup{sec: ib.sec, pri: ib.pri, pkMap: ib.pkMap, pkBld: ib.pkBld, vecOrd: vecOrd}
+}
+
 func (ib *nonCoveringIndexImplBuilder) NewSecondaryIter(strict bool, cnt int, nullSafe []bool) SecondaryLookupIterGen {
 	if strict {
 		return &nonCovStrictSecondaryLookupGen{pri: ib.pri, sec: ib.sec, pkMap: ib.pkMap, pkBld: ib.pkBld, sch: ib.idx.tableSch, prefixDesc: ib.secKd.PrefixDesc(cnt)}
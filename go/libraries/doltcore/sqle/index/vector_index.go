@@ -0,0 +1,363 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression/function/vector"
+	"github.com/dolthub/go-mysql-server/sql/iters"
+
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// A vector index is a secondary index over a single JSON column holding an array of floats. Its entries are keyed by
+// the vector Cell of the vector (see tree.VectorCell) followed by the primary key, so it is stored, versioned, diffed
+// and merged exactly like any other secondary index, and vectors that point in similar directions are stored close to
+// each other. Lookups serve queries of the form
+//
+//	SELECT ... ORDER BY VEC_DISTANCE(<literal>, <column>) LIMIT <k>
+//
+// by splitting the index into buckets of entries sharing a Cell prefix, and comparing the literal against the vectors
+// of the buckets closest to the bucket of the literal, until enough candidates have been found. Indexes holding fewer
+// than vectorMinCandidates entries are searched exhaustively, so their lookups are exact. Lookups into larger indexes
+// are approximate: they may miss a near vector whose Cell prefix differs from that of the literal in many bits.
+
+const (
+	// vectorMinCandidates is the least number of vectors a lookup compares against its literal.
+	vectorMinCandidates = 512
+	// vectorCandidatesPerRow is the least number of vectors a lookup compares against its literal for each row it
+	// returns.
+	vectorCandidatesPerRow = 8
+	// vectorMinProbeRadius is the number of bits by which the buckets a lookup always searches may differ from the
+	// bucket of its literal.
+	vectorMinProbeRadius = 1
+	// vectorMaxBucketBits is the longest Cell prefix an index is bucketed by.
+	vectorMaxBucketBits = 32
+)
+
+// vectorDistanceSupported returns whether vector index lookups can be ordered by |distanceType|.
+func vectorDistanceSupported(distanceType vector.DistanceType) bool {
+	return distanceType.CanEval(vector.DistanceL2Squared{})
+}
+
+func newVectorPartitionIter(lookup sql.IndexLookup) sql.PartitionIter {
+	return &vectorPartitionIter{part: vectorPartition{orderAndLimit: lookup.VectorOrderAndLimit}}
+}
+
+var _ sql.PartitionIter = (*vectorPartitionIter)(nil)
+var _ sql.Partition = vectorPartition{}
+
+// vectorPartitionIter returns the single partition of a vector index lookup.
+type vectorPartitionIter struct {
+	part vectorPartition
+	used bool
+}
+
+func (p *vectorPartitionIter) Next(*sql.Context) (sql.Partition, error) {
+	if p.used {
+		return nil, io.EOF
+	}
+	p.used = true
+	return p.part, nil
+}
+
+func (p *vectorPartitionIter) Close(*sql.Context) error {
+	return nil
+}
+
+type vectorPartition struct {
+	orderAndLimit sql.OrderAndLimit
+}
+
+func (p vectorPartition) Key() []byte {
+	return []byte{0}
+}
+
+// vectorLookup finds the entries of a vector index closest to a vector. The vectors themselves are read from the
+// primary index, as the vector index only holds their Cells.
+type vectorLookup struct {
+	sec, pri prolly.Map
+	pkMap    val.OrdinalMapping
+	pkBld    *val.TupleBuilder
+	// vecOrd is the ordinal of the indexed column in the value tuples of |pri|.
+	vecOrd int
+
+	// examined is the number of vectors compared against the literal of the lookup.
+	examined int
+}
+
+// newVectorIndexIter returns a prolly.MapIter over the entries of the vector index of |l| which are closest to the
+// literal of |ol|, in ascending order of distance. Entries with a NULL vector sort first, as they would in a sort of
+// the entire table.
+func newVectorIndexIter(ctx *sql.Context, l *vectorLookup, ol sql.OrderAndLimit) (prolly.MapIter, error) {
+	dist := ol.OrderBy.(*vector.Distance)
+	lit, err := ol.Literal.Eval(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var target []float64
+	if lit != nil {
+		target, err = sql.ConvertToVector(lit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	limit := int64(math.MaxInt64)
+	if ol.Limit != nil {
+		limit, err = iters.GetInt64Value(ctx, ol.Limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	kd, _ := l.sec.Descriptors()
+	nearest := &vectorEntryHeap{kd: kd}
+	if limit > 0 {
+		if err = l.search(ctx, dist.DistanceType, target, limit, nearest); err != nil {
+			return nil, err
+		}
+	}
+
+	entries := nearest.entries
+	sort.Slice(entries, func(i, j int) bool {
+		return nearest.less(entries[i], entries[j])
+	})
+	return &vectorEntryIter{entries: entries}, nil
+}
+
+// search adds the |limit| entries closest to |target| it finds to |nearest|.
+func (l *vectorLookup) search(ctx context.Context, distanceType vector.DistanceType, target []float64, limit int64, nearest *vectorEntryHeap) error {
+	// NULL vectors sort first in the index. Their distance is NULL, as is the distance of every vector to a NULL
+	// |target|, and NULL distances sort before any other.
+	iter, err := l.sec.IterAll(ctx)
+	if err != nil {
+		return err
+	}
+	for int64(nearest.Len()) < limit {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if target != nil && !k.FieldIsNull(0) {
+			break
+		}
+		heap.Push(nearest, vectorEntry{distance: math.Inf(-1), key: k, value: v})
+	}
+	if target == nil || int64(nearest.Len()) >= limit {
+		return nil
+	}
+
+	cnt, err := l.sec.Count()
+	if err != nil {
+		return err
+	}
+	want := vectorMinCandidates
+	if limit < int64(cnt)/vectorCandidatesPerRow {
+		want = max(want, int(limit)*vectorCandidatesPerRow)
+	} else {
+		want = max(want, cnt)
+	}
+
+	// bucket the index by the longest Cell prefix that leaves at least |want| entries on average in the buckets within
+	// vectorMinProbeRadius of the bucket of |target|
+	bucketBits := 0
+	for bucketBits < vectorMaxBucketBits && vectorProbeBuckets(bucketBits+1)*(cnt>>(bucketBits+1)) >= want {
+		bucketBits++
+	}
+
+	cell := tree.VectorCell(target)
+	prefix := binary.BigEndian.Uint64(cell[:8])
+	for radius := 0; radius <= bucketBits; radius++ {
+		err = forEachVectorBucketFlip(bucketBits, radius, func(flip uint64) error {
+			return l.searchBucket(ctx, distanceType, target, prefix^flip, bucketBits, limit, nearest)
+		})
+		if err != nil {
+			return err
+		}
+		if l.examined >= want && radius >= vectorMinProbeRadius {
+			break
+		}
+	}
+	return nil
+}
+
+// searchBucket compares |target| against the vectors of the entries whose Cells share the first |bits| bits of
+// |prefix|, and adds those among the |limit| closest found so far to |nearest|.
+func (l *vectorLookup) searchBucket(ctx context.Context, distanceType vector.DistanceType, target []float64, prefix uint64, bits int, limit int64, nearest *vectorEntryHeap) error {
+	rest := uint64(1)<<(64-bits) - 1
+	var lo, hi val.Cell
+	binary.BigEndian.PutUint64(lo[:8], prefix&^rest)
+	binary.BigEndian.PutUint64(hi[:8], prefix|rest)
+	for i := 8; i < len(hi); i++ {
+		hi[i] = 0xff
+	}
+
+	kd, _ := l.sec.Descriptors()
+	iter, err := l.sec.IterRange(ctx, prolly.Range{
+		Fields: []prolly.RangeField{{
+			Lo: prolly.Bound{Binding: true, Inclusive: true, Value: lo[:]},
+			Hi: prolly.Bound{Binding: true, Inclusive: true, Value: hi[:]},
+		}},
+		Desc:                   kd,
+		SkipRangeMatchCallback: true,
+		IsContiguous:           true,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		d, err := l.distance(ctx, distanceType, target, k)
+		if err != nil {
+			return err
+		}
+		e := vectorEntry{distance: d, key: k, value: v}
+		if int64(nearest.Len()) < limit {
+			heap.Push(nearest, e)
+		} else if nearest.less(e, nearest.entries[0]) {
+			nearest.entries[0] = e
+			heap.Fix(nearest, 0)
+		}
+	}
+}
+
+// distance returns the distance between |target| and the vector of the index entry keyed by |k|. NULL distances are
+// returned as negative infinity.
+func (l *vectorLookup) distance(ctx context.Context, distanceType vector.DistanceType, target []float64, k val.Tuple) (float64, error) {
+	l.examined++
+	for to := range l.pkMap {
+		from := l.pkMap.MapOrdinal(to)
+		l.pkBld.PutRaw(to, k.GetField(from))
+	}
+	pk := l.pkBld.Build(sharePool)
+
+	_, vd := l.pri.Descriptors()
+	var field interface{}
+	err := l.pri.Get(ctx, pk, func(_, v val.Tuple) (err error) {
+		if v != nil {
+			field, err = tree.GetField(ctx, vd, l.vecOrd, v, l.pri.NodeStore())
+		}
+		return
+	})
+	if err != nil {
+		return 0, err
+	}
+	if field == nil {
+		return math.Inf(-1), nil
+	}
+	vec, err := sql.ConvertToVector(field)
+	if err != nil {
+		return 0, err
+	}
+	return distanceType.Eval(target, vec)
+}
+
+// vectorProbeBuckets returns the number of buckets within vectorMinProbeRadius of a bucket when an index is bucketed by
+// a Cell prefix of |bits| bits.
+func vectorProbeBuckets(bits int) int {
+	buckets, choose := 0, 1
+	for r := 0; r <= min(vectorMinProbeRadius, bits); r++ {
+		buckets += choose
+		choose = choose * (bits - r) / (r + 1)
+	}
+	return buckets
+}
+
+// forEachVectorBucketFlip calls |cb| with every mask of |radius| bits among the highest |bits| bits of a uint64.
+func forEachVectorBucketFlip(bits, radius int, cb func(flip uint64) error) error {
+	var flips func(from, left int, flip uint64) error
+	flips = func(from, left int, flip uint64) error {
+		if left == 0 {
+			return cb(flip)
+		}
+		for i := from; i <= bits-left; i++ {
+			if err := flips(i+1, left-1, flip|1<<(63-i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return flips(0, radius, 0)
+}
+
+type vectorEntry struct {
+	distance   float64
+	key, value val.Tuple
+}
+
+// vectorEntryHeap is a max-heap of the closest entries found so far, so that the furthest can be replaced.
+type vectorEntryHeap struct {
+	entries []vectorEntry
+	kd      val.TupleDesc
+}
+
+var _ heap.Interface = (*vectorEntryHeap)(nil)
+
+// less orders entries by distance, and entries at the same distance by their order in the index.
+func (h *vectorEntryHeap) less(e, other vectorEntry) bool {
+	if e.distance != other.distance {
+		return e.distance < other.distance
+	}
+	return h.kd.Compare(e.key, other.key) < 0
+}
+
+func (h *vectorEntryHeap) Len() int           { return len(h.entries) }
+func (h *vectorEntryHeap) Less(i, j int) bool { return h.less(h.entries[j], h.entries[i]) }
+func (h *vectorEntryHeap) Swap(i, j int)      { h.entries[i], h.entries[j] = h.entries[j], h.entries[i] }
+
+func (h *vectorEntryHeap) Push(x any) {
+	h.entries = append(h.entries, x.(vectorEntry))
+}
+
+func (h *vectorEntryHeap) Pop() any {
+	old := h.entries
+	e := old[len(old)-1]
+	h.entries = old[:len(old)-1]
+	return e
+}
+
+type vectorEntryIter struct {
+	entries []vectorEntry
+}
+
+var _ prolly.MapIter = (*vectorEntryIter)(nil)
+
+func (i *vectorEntryIter) Next(context.Context) (val.Tuple, val.Tuple, error) {
+	if len(i.entries) == 0 {
+		return nil, nil, io.EOF
+	}
+	e := i.entries[0]
+	i.entries = i.entries[1:]
+	return e.key, e.value, nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"io"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function/vector"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+func TestVectorIndexLookup(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	rnd := rand.New(rand.NewSource(0))
+	vectors := make([][]float64, 20_000)
+	for i := range vectors {
+		vectors[i] = make([]float64, 8)
+		for j := range vectors[i] {
+			vectors[i][j] = rnd.NormFloat64()
+		}
+	}
+	lookup := newTestVectorLookup(t, ctx, vectors)
+
+	t.Run("small indexes are searched exhaustively", func(t *testing.T) {
+		small := newTestVectorLookup(t, ctx, vectors[:vectorMinCandidates])
+		for q := 0; q < 10; q++ {
+			target := vectors[vectorMinCandidates+q]
+			ids := testVectorLookup(t, ctx, small, target, 10)
+			assert.Equal(t, nearestVectors(vectors[:vectorMinCandidates], target, 10), ids)
+			assert.Equal(t, vectorMinCandidates, small.examined)
+			small.examined = 0
+		}
+	})
+
+	t.Run("large indexes are searched by bucket", func(t *testing.T) {
+		var found int
+		for q := 0; q < 20; q++ {
+			// a vector in the index is in the bucket of its own Cell, so it is always found
+			id := rnd.Intn(len(vectors))
+			ids := testVectorLookup(t, ctx, lookup, vectors[id], 10)
+			require.Len(t, ids, 10)
+			assert.Equal(t, int64(id), ids[0])
+
+			nearest := nearestVectors(vectors, vectors[id], 10)
+			for _, id := range ids {
+				for _, n := range nearest {
+					if id == n {
+						found++
+					}
+				}
+			}
+			assert.Less(t, lookup.examined, len(vectors)/4)
+			lookup.examined = 0
+		}
+		// most of the nearest vectors are found
+		assert.Greater(t, found, 20*10/2)
+	})
+}
+
+func newTestVectorLookup(t *testing.T, ctx *sql.Context, vectors [][]float64) *vectorLookup {
+	ns := tree.NewTestNodeStore()
+	priKd := val.NewTupleDescriptor(val.Type{Enc: val.Int64Enc})
+	priVd := val.NewTupleDescriptor(val.Type{Enc: val.JSONAddrEnc, Nullable: true})
+	secKd := val.NewTupleDescriptor(val.Type{Enc: val.CellEnc, Nullable: true}, val.Type{Enc: val.Int64Enc})
+
+	pri, err := prolly.NewMapFromTuples(ctx, ns, priKd, priVd)
+	require.NoError(t, err)
+	sec, err := prolly.NewMapFromTuples(ctx, ns, secKd, val.NewTupleDescriptor())
+	require.NoError(t, err)
+	priMut, secMut := pri.Mutate(), sec.Mutate()
+
+	kb, vb, sb := val.NewTupleBuilder(priKd), val.NewTupleBuilder(priVd), val.NewTupleBuilder(secKd)
+	for i, vec := range vectors {
+		arr := make([]interface{}, len(vec))
+		for j := range vec {
+			arr[j] = vec[j]
+		}
+		doc := types.JSONDocument{Val: arr}
+
+		kb.PutInt64(0, int64(i))
+		require.NoError(t, tree.PutField(ctx, ns, vb, 0, doc))
+		require.NoError(t, priMut.Put(ctx, kb.Build(sharePool), vb.Build(sharePool)))
+
+		require.NoError(t, tree.PutField(ctx, ns, sb, 0, doc))
+		sb.PutInt64(1, int64(i))
+		require.NoError(t, secMut.Put(ctx, sb.Build(sharePool), val.EmptyTuple))
+	}
+	pri, err = priMut.Map(ctx)
+	require.NoError(t, err)
+	sec, err = secMut.Map(ctx)
+	require.NoError(t, err)
+
+	return &vectorLookup{
+		sec:   sec,
+		pri:   pri,
+		pkMap: val.OrdinalMapping{1},
+		pkBld: val.NewTupleBuilder(priKd),
+	}
+}
+
+// testVectorLookup returns the ids of the |limit| entries of |l| closest to |target|.
+func testVectorLookup(t *testing.T, ctx *sql.Context, l *vectorLookup, target []float64, limit int64) []int64 {
+	arr := make([]interface{}, len(target))
+	for i := range target {
+		arr[i] = target[i]
+	}
+	lit := expression.NewLiteral(types.JSONDocument{Val: arr}, types.JSON)
+	iter, err := newVectorIndexIter(ctx, l, sql.OrderAndLimit{
+		OrderBy: vector.NewL2SquaredDistance(lit, expression.NewGetField(0, types.JSON, "v", true)).(*vector.Distance),
+		Literal: lit,
+		Limit:   expression.NewLiteral(limit, types.Int64),
+	})
+	require.NoError(t, err)
+
+	var ids []int64
+	for {
+		k, _, err := iter.Next(ctx)
+		if err == io.EOF {
+			return ids
+		}
+		require.NoError(t, err)
+		id, _ := l.sec.KeyDesc().GetInt64(1, k)
+		ids = append(ids, id)
+	}
+}
+
+// nearestVectors returns the ids of the |limit| |vectors| closest to |target|.
+func nearestVectors(vectors [][]float64, target []float64, limit int) []int64 {
+	ids := make([]int64, len(vectors))
+	dists := make([]float64, len(vectors))
+	for i := range vectors {
+		ids[i] = int64(i)
+		dists[i], _ = vector.DistanceL2Squared{}.Eval(target, vectors[i])
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return dists[ids[i]] < dists[ids[j]]
+	})
+	return ids[:limit]
+}
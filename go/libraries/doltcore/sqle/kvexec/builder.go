@@ -388,19 +388,23 @@ func getSourceKv(ctx *sql.Context, n sql.Node, isSrc bool) (prolly.Map, prolly.M
 		}
 
 	case *plan.ResolvedTable:
-		switch dt := n.UnderlyingTable().(type) {
+		var dt *sqle.DoltTable
+		switch t := n.UnderlyingTable().(type) {
 		case *sqle.WritableDoltTable:
-			tags = dt.ProjectedTags()
-			table, err = dt.DoltTable.DoltTable(ctx)
+			dt = t.DoltTable
 		case *sqle.AlterableDoltTable:
-			tags = dt.ProjectedTags()
-			table, err = dt.DoltTable.DoltTable(ctx)
+			dt = t.DoltTable
 		case *sqle.DoltTable:
-			tags = dt.ProjectedTags()
-			table, err = dt.DoltTable(ctx)
+			dt = t
 		default:
 			return prolly.Map{}, prolly.Map{}, nil, nil, nil, nil, nil, nil, nil
 		}
+		// rows hidden by row-level security policies and masked values are only handled by the table's own row iterator
+		if restricted, err := dt.ReadsRestricted(ctx); err != nil || restricted {
+			return prolly.Map{}, prolly.Map{}, nil, nil, nil, nil, nil, nil, err
+		}
+		tags = dt.ProjectedTags()
+		table, err = dt.DoltTable(ctx)
 		if err != nil {
 			return prolly.Map{}, prolly.Map{}, nil, nil, nil, nil, nil, nil, err
 		}
@@ -610,3 +614,21 @@ func getMergeKv(ctx *sql.Context, n sql.Node) (mergeState, error) {
 		return ms, fmt.Errorf("unsupported kvmerge child node")
 	}
 }
+
+// countingMapIter is a prolly.MapIter that counts the rows read from the iterator it wraps. kvexec iterators read the
+// maps of their tables directly, bypassing the row iterators of the tables that count the rows examined by a
+// statement, so they add their counts themselves when they're closed.
+type countingMapIter struct {
+	prolly.MapIter
+	count *int
+}
+
+var _ prolly.MapIter = countingMapIter{}
+
+func (i countingMapIter) Next(ctx context.Context) (val.Tuple, val.Tuple, error) {
+	k, v, err := i.MapIter.Next(ctx)
+	if err == nil && k != nil {
+		*i.count++
+	}
+	return k, v, err
+}
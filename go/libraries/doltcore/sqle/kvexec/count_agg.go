@@ -22,6 +22,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/expression"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/store/prolly"
 )
 
@@ -51,12 +52,13 @@ func newCountAggregationKvIter(srcIter prolly.MapIter, sch schema.Schema, e sql.
 		// todo: tuple
 	}
 
-	return &countAggKvIter{
-		srcIter:  srcIter,
+	l := &countAggKvIter{
 		nullable: nullable,
 		isKeyRef: isKeyRef,
 		idx:      idx,
-	}, true, nil
+	}
+	l.srcIter = countingMapIter{MapIter: srcIter, count: &l.srcRows}
+	return l, true, nil
 }
 
 type countAggKvIter struct {
@@ -65,9 +67,14 @@ type countAggKvIter struct {
 	isKeyRef bool
 	idx      int
 	done     bool
+
+	// rows examined
+	srcRows int
 }
 
-func (l *countAggKvIter) Close(_ *sql.Context) error {
+func (l *countAggKvIter) Close(ctx *sql.Context) error {
+	sqle.CountRowsRead(ctx, sqle.HandlerReadRndNext, l.srcRows)
+	l.srcRows = 0
 	return nil
 }
 
@@ -24,6 +24,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/expression"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 	"github.com/dolthub/dolt/go/store/pool"
 	"github.com/dolthub/dolt/go/store/prolly"
@@ -56,9 +57,16 @@ type lookupJoinKvIter struct {
 	excludeNulls bool
 	isLeftJoin   bool
 	returnedARow bool
+
+	// rows examined
+	srcRows int
+	dstRows int
 }
 
-func (l *lookupJoinKvIter) Close(_ *sql.Context) error {
+func (l *lookupJoinKvIter) Close(ctx *sql.Context) error {
+	sqle.CountRowsRead(ctx, sqle.HandlerReadRndNext, l.srcRows)
+	sqle.CountRowsRead(ctx, sqle.HandlerReadNext, l.dstRows)
+	l.srcRows, l.dstRows = 0, 0
 	return nil
 }
 
@@ -79,8 +87,7 @@ func newLookupKvIter(
 		}
 	}
 
-	return &lookupJoinKvIter{
-		srcIter:        srcIter,
+	l := &lookupJoinKvIter{
 		dstIterGen:     targetIter,
 		joiner:         joiner,
 		keyTupleMapper: mapping,
@@ -89,7 +96,9 @@ func newLookupKvIter(
 		joinFilter:     joinFilter,
 		isLeftJoin:     isLeftJoin,
 		excludeNulls:   excludeNulls,
-	}, nil
+	}
+	l.srcIter = countingMapIter{MapIter: srcIter, count: &l.srcRows}
+	return l, nil
 }
 
 func (l *lookupJoinKvIter) Next(ctx *sql.Context) (sql.Row, error) {
@@ -118,10 +127,11 @@ func (l *lookupJoinKvIter) Next(ctx *sql.Context) (sql.Row, error) {
 				return nil, err
 			}
 
-			l.dstIter, err = l.dstIterGen.New(ctx, l.dstKey)
+			dstIter, err := l.dstIterGen.New(ctx, l.dstKey)
 			if err != nil {
 				return nil, err
 			}
+			l.dstIter = countingMapIter{MapIter: dstIter, count: &l.dstRows}
 		}
 
 		dstKey, dstVal, err := l.dstIter.Next(ctx)
@@ -21,6 +21,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/plan"
 	"github.com/dolthub/go-mysql-server/sql/planbuilder"
+	"github.com/dolthub/go-mysql-server/sql/rowexec"
 	"github.com/dolthub/go-mysql-server/sql/transform"
 	"github.com/stretchr/testify/require"
 
@@ -206,3 +207,82 @@ func getJoin(n sql.Node) sql.Node {
 	}
 	return j
 }
+
+// TestRowsExamined ensures that the rows read by kvexec operators are counted in the handler status variables that
+// make up the rows examined by a statement.
+func TestRowsExamined(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected int
+	}{
+		{
+			// the two rows of ab are scanned, and the two rows of xy that match them are looked up
+			name:     "lookup join",
+			query:    "select /*+ LOOKUP_JOIN(xy,ab) */ * from xy join ab on x = a",
+			expected: 4,
+		},
+		{
+			// both tables are read in full
+			name:     "merge join",
+			query:    "select /*+ MERGE_JOIN(xy,ab) */ * from xy join ab on x = a",
+			expected: 5,
+		},
+		{
+			name:     "count",
+			query:    "select count(y) from xy",
+			expected: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			defer dEnv.DoltDB.Close()
+
+			tmpDir, err := dEnv.TempTableFilesDir()
+			require.NoError(t, err)
+
+			opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+			db, err := sqle.NewDatabase(context.Background(), "dolt", dEnv.DbData(), opts)
+			require.NoError(t, err)
+
+			engine, ctx, err := sqle.NewTestEngine(dEnv, context.Background(), db)
+			require.NoError(t, err)
+			engine.Analyzer.ExecBuilder = rowexec.NewOverrideBuilder(Builder{})
+
+			err = ctx.Session.SetSessionVariable(ctx, sql.AutoCommitSessionVar, false)
+			require.NoError(t, err)
+
+			setup := []string{
+				"create table xy (x int primary key, y int)",
+				"create table ab (a int primary key, b int)",
+				"insert into xy values (1, 1), (2, 2), (3, 3)",
+				"insert into ab values (1, 1), (3, 3)",
+			}
+			for _, q := range setup {
+				_, iter, _, err := engine.Query(ctx, q)
+				require.NoError(t, err)
+				_, err = sql.RowIterToRows(ctx, iter)
+				require.NoError(t, err)
+			}
+
+			before := rowsRead(t, ctx)
+			_, iter, _, err := engine.Query(ctx, tt.query)
+			require.NoError(t, err)
+			_, err = sql.RowIterToRows(ctx, iter)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, rowsRead(t, ctx)-before)
+		})
+	}
+}
+
+func rowsRead(t *testing.T, ctx *sql.Context) int {
+	var n int
+	for _, name := range []string{sqle.HandlerReadNext, sqle.HandlerReadRndNext} {
+		v, err := ctx.Session.GetStatusVariable(ctx, name)
+		require.NoError(t, err)
+		n += int(v.(uint64))
+	}
+	return n
+}
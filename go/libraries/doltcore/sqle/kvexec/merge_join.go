@@ -22,6 +22,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/expression"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/store/prolly"
 	"github.com/dolthub/dolt/go/store/val"
 )
@@ -34,9 +35,7 @@ func newMergeKvIter(
 	isLeftJoin bool,
 	excludeNulls bool,
 ) (*mergeJoinKvIter, error) {
-	return &mergeJoinKvIter{
-		leftIter:     leftState.iter,
-		rightIter:    rightState.iter,
+	l := &mergeJoinKvIter{
 		joiner:       joiner,
 		lrCmp:        lrComparer,
 		llCmp:        llComparer,
@@ -47,7 +46,10 @@ func newMergeKvIter(
 		rightFilter:  rightState.filter,
 		isLeftJoin:   isLeftJoin,
 		excludeNulls: excludeNulls,
-	}, nil
+	}
+	l.leftIter = countingMapIter{MapIter: leftState.iter, count: &l.leftRows}
+	l.rightIter = countingMapIter{MapIter: rightState.iter, count: &l.rightRows}
+	return l, nil
 }
 
 type mergeJoinKvIter struct {
@@ -90,11 +92,18 @@ type mergeJoinKvIter struct {
 	isLeftJoin   bool
 	matchedLeft  bool
 	exhaustLeft  bool
+
+	// rows examined
+	leftRows  int
+	rightRows int
 }
 
 var _ sql.RowIter = (*mergeJoinKvIter)(nil)
 
-func (l *mergeJoinKvIter) Close(_ *sql.Context) error {
+func (l *mergeJoinKvIter) Close(ctx *sql.Context) error {
+	// merge joins read both of their sides in index order
+	sqle.CountRowsRead(ctx, sqle.HandlerReadNext, l.leftRows+l.rightRows)
+	l.leftRows, l.rightRows = 0, 0
 	return nil
 }
 
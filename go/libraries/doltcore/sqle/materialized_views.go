@@ -0,0 +1,109 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	ast "github.com/dolthub/vitess/go/vt/sqlparser"
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+var ErrMaterializedViewNotSelect = errors.NewKind("materialized views must be defined by a SELECT statement")
+
+const materializedViewIdent = "`(?:[^`]|``)+`|[0-9a-z_$]+"
+
+var createMaterializedViewRegex = regexp.MustCompile(`(?is)^create\s+materialized\s+view\s+(` + materializedViewIdent + `)\s+(refresh\s+on\s+commit\s+)?as\s+`)
+var refreshMaterializedViewRegex = regexp.MustCompile(`(?is)^refresh\s+materialized\s+view\s+((?:` + materializedViewIdent + `)(?:\s*,\s*(?:` + materializedViewIdent + `))*)`)
+var dropMaterializedViewRegex = regexp.MustCompile(`(?is)^drop\s+materialized\s+view\s+(` + materializedViewIdent + `)`)
+var materializedViewIdentRegex = regexp.MustCompile(`(?i)` + materializedViewIdent)
+
+// parseMaterializedView recognizes the materialized view statements the engine's parser doesn't understand:
+//
+//	CREATE MATERIALIZED VIEW name [REFRESH ON COMMIT] AS SELECT ...
+//	REFRESH MATERIALIZED VIEW name [, name ...]
+//	DROP MATERIALIZED VIEW name
+//
+// If |query| starts with one of them, it returns the CALL statement of the stored procedure that executes it and the
+// length of the statement in |query|, including its |delimiter|. |parseSelect| parses the SELECT statement at the
+// start of the string it is passed, and returns it and its length in the same way.
+func parseMaterializedView(query string, delimiter rune, parseSelect func(string) (ast.Statement, int, error)) (call string, n int, ok bool, err error) {
+	s := strings.TrimLeftFunc(query, unicode.IsSpace)
+	start := len(query) - len(s)
+
+	if m := createMaterializedViewRegex.FindStringSubmatchIndex(s); m != nil {
+		sel, n, err := parseSelect(s[m[1]:])
+		if err != nil {
+			return "", 0, false, err
+		}
+		switch sel.(type) {
+		case *ast.Select, *ast.SetOp:
+		default:
+			return "", 0, false, ErrMaterializedViewNotSelect.New()
+		}
+
+		definition := strings.TrimRightFunc(s[m[1]:m[1]+n], func(r rune) bool {
+			return r == delimiter || unicode.IsSpace(r)
+		})
+		var args []string
+		if m[4] >= 0 {
+			args = append(args, "--auto-refresh")
+		}
+		args = append(args, unquoteMaterializedViewIdent(s[m[2]:m[3]]), definition)
+		return procedureCall("dolt_create_materialized_view", args), start + m[1] + n, true, nil
+	}
+
+	proc, m := "dolt_refresh_materialized_view", refreshMaterializedViewRegex.FindStringSubmatchIndex(s)
+	if m == nil {
+		proc, m = "dolt_drop_materialized_view", dropMaterializedViewRegex.FindStringSubmatchIndex(s)
+	}
+	if m == nil {
+		return "", 0, false, nil
+	}
+
+	// the names must be followed by the end of the statement, or else the engine's parser reports the syntax error
+	rest := strings.TrimLeftFunc(s[m[1]:], unicode.IsSpace)
+	n = len(s)
+	if rest != "" {
+		if !strings.HasPrefix(rest, string(delimiter)) {
+			return "", 0, false, nil
+		}
+		n = len(s) - len(rest) + len(string(delimiter))
+	}
+
+	var args []string
+	for _, name := range materializedViewIdentRegex.FindAllString(s[m[2]:m[3]], -1) {
+		args = append(args, unquoteMaterializedViewIdent(name))
+	}
+	return procedureCall(proc, args), start + n, true, nil
+}
+
+func unquoteMaterializedViewIdent(name string) string {
+	if strings.HasPrefix(name, "`") {
+		return strings.ReplaceAll(name[1:len(name)-1], "``", "`")
+	}
+	return name
+}
+
+func procedureCall(name string, args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = ast.String(ast.NewStrVal([]byte(arg)))
+	}
+	return fmt.Sprintf("call %s(%s)", name, strings.Join(quoted, ", "))
+}
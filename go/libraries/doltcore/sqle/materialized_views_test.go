@@ -0,0 +1,84 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	ast "github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMaterializedViewStatements(t *testing.T) {
+	ctx := context.Background()
+	p := NewParser(sql.NewMysqlParser())
+
+	tests := []struct {
+		query     string
+		call      string
+		parsed    string
+		remainder string
+	}{
+		{
+			query:  "create materialized view mv as select pk from t where c = 'a;b';",
+			call:   "call dolt_create_materialized_view('mv', 'select pk from t where c = \\'a;b\\'')",
+			parsed: "create materialized view mv as select pk from t where c = 'a;b'",
+		},
+		{
+			query:     "  CREATE MATERIALIZED VIEW `my ``view``` REFRESH ON COMMIT AS SELECT pk FROM t ;\nselect 1;",
+			call:      "call dolt_create_materialized_view('--auto-refresh', 'my `view`', 'SELECT pk FROM t')",
+			parsed:    "CREATE MATERIALIZED VIEW `my ``view``` REFRESH ON COMMIT AS SELECT pk FROM t",
+			remainder: "\nselect 1",
+		},
+		{
+			query:     "refresh materialized view a , `b`; drop materialized view a",
+			call:      "call dolt_refresh_materialized_view('a', 'b')",
+			parsed:    "refresh materialized view a , `b`",
+			remainder: " drop materialized view a",
+		},
+		{
+			query:  "drop materialized view a",
+			call:   "call dolt_drop_materialized_view('a')",
+			parsed: "drop materialized view a",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, parsed, remainder, err := p.ParseWithOptions(ctx, test.query, ';', true, ast.ParserOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, test.call, ast.String(stmt))
+			assert.Equal(t, test.parsed, parsed)
+			assert.Equal(t, test.remainder, remainder)
+
+			one, idx, err := p.ParseOneWithOptions(ctx, test.query, ast.ParserOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, test.call, ast.String(one))
+			if test.remainder == "" {
+				assert.Equal(t, 0, idx)
+			} else {
+				assert.Equal(t, test.remainder, strings.TrimSuffix(test.query[idx:], ";"))
+			}
+		})
+	}
+
+	_, err := p.ParseSimple("create materialized view mv as delete from t")
+	assert.True(t, ErrMaterializedViewNotSelect.Is(err))
+	_, err = p.ParseSimple("refresh materialized view a b")
+	assert.Error(t, err)
+}
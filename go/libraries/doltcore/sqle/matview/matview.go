@@ -0,0 +1,448 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package matview implements materialized views. A materialized view is stored as a regular, versioned table holding
+// the result of a SELECT over a single source table. Its definition is recorded in the dolt_materialized_views system
+// table along with the hash of the source table as of the last refresh, which allows a refresh to diff the source
+// table against that version and update only the affected rows of the view.
+package matview
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/vt/proto/query"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var ErrUnsupportedDefinition = errors.NewKind("materialized views do not support %s")
+var ErrSourceNotFound = errors.NewKind("source table %s of materialized view not found")
+var ErrKeylessSource = errors.NewKind("materialized views require a source table with a primary key, but %s has none")
+var ErrMissingPrimaryKey = errors.NewKind("materialized views must select primary key column %s of %s")
+var ErrNullableGroupBy = errors.NewKind("materialized views cannot group by nullable column %s")
+var ErrUnselectedGroupBy = errors.NewKind("materialized views must select grouping column %s")
+var ErrReservedName = errors.NewKind("Invalid materialized view name %s. Table names beginning with `dolt_` are reserved for internal use")
+var ErrViewExists = errors.NewKind("table %s already exists")
+var ErrViewNotFound = errors.NewKind("materialized view %s does not exist")
+var ErrViewSchemaChanged = errors.NewKind("the schema of materialized view %s no longer matches its definition; drop and recreate it")
+var ErrOldFormat = errors.NewKind("materialized views are not supported in the old storage format")
+
+// Refresh kinds reported by RefreshViews
+const (
+	// RefreshUnchanged means that the source table had not changed since the last refresh
+	RefreshUnchanged = "unchanged"
+	// RefreshIncremental means that only the source rows which changed since the last refresh were applied to the view
+	RefreshIncremental = "incremental"
+	// RefreshFull means that the view was recomputed from every row of the source table
+	RefreshFull = "full"
+)
+
+// View is a row of the dolt_materialized_views table.
+type View struct {
+	Name        string
+	Definition  string
+	AutoRefresh bool
+	// SourceHash is the hash of the source table as of the last refresh
+	SourceHash string
+}
+
+var materializedViewsSchema = mustSchema(
+	mustCol(doltdb.MaterializedViewsNameCol, schema.DoltMaterializedViewsNameTag, typeinfo.CreateVarStringTypeFromSqlType(gmstypes.MustCreateString(query.Type_VARCHAR, 64, sql.Collation_utf8mb4_0900_ai_ci)), true),
+	mustCol(doltdb.MaterializedViewsDefinitionCol, schema.DoltMaterializedViewsDefinitionTag, typeinfo.CreateVarStringTypeFromSqlType(gmstypes.LongText), false),
+	mustCol(doltdb.MaterializedViewsAutoRefreshCol, schema.DoltMaterializedViewsAutoRefreshTag, typeinfo.Int8Type, false),
+	mustCol(doltdb.MaterializedViewsSourceHashCol, schema.DoltMaterializedViewsSourceHashTag, typeinfo.CreateVarStringTypeFromSqlType(gmstypes.MustCreateString(query.Type_VARCHAR, 32, sql.Collation_ascii_bin)), false),
+)
+
+// MaterializedViewsSchema returns the schema of the dolt_materialized_views table.
+func MaterializedViewsSchema() schema.Schema {
+	return materializedViewsSchema
+}
+
+func mustCol(name string, tag uint64, ti typeinfo.TypeInfo, partOfPK bool) schema.Column {
+	col, err := schema.NewColumnWithTypeInfo(name, tag, ti, partOfPK, "", false, "", schema.NotNullConstraint{})
+	if err != nil {
+		panic(err)
+	}
+	return col
+}
+
+func mustSchema(cols ...schema.Column) schema.Schema {
+	return schema.MustSchemaFromCols(schema.NewColCollection(cols...))
+}
+
+// ListViews returns the materialized views defined in |root|.
+func ListViews(ctx *sql.Context, root doltdb.RootValue) ([]View, error) {
+	tbl, ok, err := root.GetTable(ctx, doltdb.TableName{Name: doltdb.MaterializedViewsTableName})
+	if err != nil || !ok {
+		return nil, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m := durable.ProllyMapFromIndex(rows)
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var views []View
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		row, err := index.BuildRow(ctx, k, v, sch, m.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, viewFromRow(row))
+	}
+	return views, nil
+}
+
+func viewFromRow(row sql.Row) View {
+	return View{
+		Name:        row[0].(string),
+		Definition:  row[1].(string),
+		AutoRefresh: row[2].(int8) != 0,
+		SourceHash:  row[3].(string),
+	}
+}
+
+func (v View) toRow() sql.Row {
+	var autoRefresh int8
+	if v.AutoRefresh {
+		autoRefresh = 1
+	}
+	return sql.Row{v.Name, v.Definition, autoRefresh, v.SourceHash}
+}
+
+// getView returns the materialized view named |name| in |root|.
+func getView(ctx *sql.Context, root doltdb.RootValue, name string) (View, bool, error) {
+	views, err := ListViews(ctx, root)
+	if err != nil {
+		return View{}, false, err
+	}
+	for _, v := range views {
+		if strings.EqualFold(v.Name, name) {
+			return v, true, nil
+		}
+	}
+	return View{}, false, nil
+}
+
+// CreateView creates the materialized view |name| defined by the SELECT statement |definition| in the working set of
+// |dbName|, and populates it.
+func CreateView(ctx *sql.Context, dbName, name, definition string, autoRefresh bool) error {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	root := roots.Working
+	if !types.IsFormat_DOLT(root.VRW().Format()) {
+		return ErrOldFormat.New()
+	}
+
+	if doltdb.IsSystemTable(doltdb.TableName{Name: name}) {
+		return ErrReservedName.New(name)
+	}
+	_, _, exists, err := doltdb.GetTableInsensitive(ctx, root, doltdb.TableName{Name: name})
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrViewExists.New(name)
+	}
+
+	p, err := planView(ctx, dbName, root, definition)
+	if err != nil {
+		return err
+	}
+
+	tName := doltdb.TableName{Name: name}
+	sch, err := sqlutil.ToDoltSchema(ctx, root, tName, p.schema, roots.Head, sql.Collation_Default)
+	if err != nil {
+		return err
+	}
+	root, err = doltdb.CreateEmptyTable(ctx, root, tName, sch)
+	if err != nil {
+		return err
+	}
+	if _, ok, err := root.GetTable(ctx, doltdb.TableName{Name: doltdb.MaterializedViewsTableName}); err != nil {
+		return err
+	} else if !ok {
+		root, err = doltdb.CreateEmptyTable(ctx, root, doltdb.TableName{Name: doltdb.MaterializedViewsTableName}, materializedViewsSchema)
+		if err != nil {
+			return err
+		}
+	}
+
+	view := View{Name: name, Definition: definition, AutoRefresh: autoRefresh}
+	ed, err := writer.NewRootEditor(ctx, dbName, root)
+	if err != nil {
+		return err
+	}
+	if err = ed.Insert(ctx, doltdb.MaterializedViewsTableName, view.toRow()); err != nil {
+		return err
+	}
+	if root, err = ed.Finish(ctx); err != nil {
+		return err
+	}
+
+	root, _, err = refresh(ctx, dbName, root, view)
+	if err != nil {
+		return err
+	}
+	return dSess.SetWorkingRoot(ctx, dbName, root)
+}
+
+// DropView drops the materialized view |name| from the working set of |dbName|, along with the table holding its rows.
+func DropView(ctx *sql.Context, dbName, name string) error {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	root := roots.Working
+
+	views, err := ListViews(ctx, root)
+	if err != nil {
+		return err
+	}
+	var view View
+	for _, v := range views {
+		if strings.EqualFold(v.Name, name) {
+			view = v
+		}
+	}
+	if view.Name == "" {
+		return ErrViewNotFound.New(name)
+	}
+
+	if len(views) == 1 {
+		// this is the last materialized view, so remove the system table as well
+		root, err = root.RemoveTables(ctx, false, false, doltdb.TableName{Name: doltdb.MaterializedViewsTableName})
+		if err != nil {
+			return err
+		}
+	} else {
+		ed, err := writer.NewRootEditor(ctx, dbName, root)
+		if err != nil {
+			return err
+		}
+		if err = ed.Delete(ctx, doltdb.MaterializedViewsTableName, view.toRow()); err != nil {
+			return err
+		}
+		if root, err = ed.Finish(ctx); err != nil {
+			return err
+		}
+	}
+
+	tName := doltdb.TableName{Name: view.Name}
+	if _, ok, err := root.GetTable(ctx, tName); err != nil {
+		return err
+	} else if ok {
+		root, err = root.RemoveTables(ctx, false, false, tName)
+		if err != nil {
+			return err
+		}
+	}
+	return dSess.SetWorkingRoot(ctx, dbName, root)
+}
+
+// RefreshViews refreshes the materialized views |names| in the working set of |dbName|, or every materialized view if
+// |names| is empty. It returns how each view was refreshed, in the order of |names|.
+func RefreshViews(ctx *sql.Context, dbName string, names []string) ([]string, []string, error) {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return nil, nil, fmt.Errorf("Could not load database %s", dbName)
+	}
+	root := roots.Working
+
+	var views []View
+	if len(names) == 0 {
+		all, err := ListViews(ctx, root)
+		if err != nil {
+			return nil, nil, err
+		}
+		views = all
+	} else {
+		for _, name := range names {
+			v, ok, err := getView(ctx, root, name)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !ok {
+				return nil, nil, ErrViewNotFound.New(name)
+			}
+			views = append(views, v)
+		}
+	}
+
+	refreshed := make([]string, len(views))
+	kinds := make([]string, len(views))
+	for i, v := range views {
+		var err error
+		root, kinds[i], err = refresh(ctx, dbName, root, v)
+		if err != nil {
+			return nil, nil, err
+		}
+		refreshed[i] = v.Name
+	}
+	if err := dSess.SetWorkingRoot(ctx, dbName, root); err != nil {
+		return nil, nil, err
+	}
+	return refreshed, kinds, nil
+}
+
+// RefreshOnCommit refreshes the materialized views of |roots| which are marked to refresh automatically. It is
+// called before |roots| are committed. Views in the staged root are refreshed from the staged source tables, so that
+// the commit includes views that agree with the committed data. A view in the working root whose source table is the
+// same as the staged one is given the rows of the staged view, so that the two don't differ, and any other view in the
+// working root is refreshed from the working source table.
+func RefreshOnCommit(ctx *sql.Context, dbName string, roots doltdb.Roots) (doltdb.Roots, error) {
+	var err error
+	roots.Staged, err = refreshAuto(ctx, dbName, roots.Staged)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	roots.Working, err = refreshWorking(ctx, dbName, roots.Staged, roots.Working)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	return roots, nil
+}
+
+// refreshWorking refreshes the views of |working| which are marked to refresh automatically, copying the views of
+// |staged| where they are defined and sourced in the same way.
+func refreshWorking(ctx *sql.Context, dbName string, staged, working doltdb.RootValue) (doltdb.RootValue, error) {
+	views, err := ListViews(ctx, working)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		if !v.AutoRefresh {
+			continue
+		}
+		tName := doltdb.TableName{Name: v.Name}
+		if _, ok, err := working.GetTable(ctx, tName); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+
+		sv, ok, err := stagedView(ctx, dbName, staged, working, v)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			working, _, err = refresh(ctx, dbName, working, v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to refresh materialized view %s: %w", v.Name, err)
+			}
+			continue
+		}
+
+		tbl, _, err := staged.GetTable(ctx, tName)
+		if err != nil {
+			return nil, err
+		}
+		if working, err = working.PutTable(ctx, tName, tbl); err != nil {
+			return nil, err
+		}
+		ed, err := writer.NewRootEditor(ctx, dbName, working)
+		if err != nil {
+			return nil, err
+		}
+		if err = ed.Update(ctx, doltdb.MaterializedViewsTableName, v.toRow(), sv.toRow()); err != nil {
+			return nil, err
+		}
+		if working, err = ed.Finish(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return working, nil
+}
+
+// stagedView returns the view in |staged| matching the view |v| of |working|, if it has the same definition, its table
+// is staged, and its source table is the same in both roots.
+func stagedView(ctx *sql.Context, dbName string, staged, working doltdb.RootValue, v View) (View, bool, error) {
+	sv, ok, err := getView(ctx, staged, v.Name)
+	if err != nil || !ok || sv.Definition != v.Definition || !sv.AutoRefresh {
+		return View{}, false, err
+	}
+	if _, ok, err := staged.GetTable(ctx, doltdb.TableName{Name: v.Name}); err != nil || !ok {
+		return View{}, false, err
+	}
+
+	p, err := planView(ctx, dbName, working, v.Definition)
+	if err != nil {
+		return View{}, false, err
+	}
+	src := doltdb.TableName{Name: p.source}
+	stagedHash, ok, err := staged.GetTableHash(ctx, src)
+	if err != nil || !ok {
+		return View{}, false, err
+	}
+	workingHash, ok, err := working.GetTableHash(ctx, src)
+	if err != nil || !ok {
+		return View{}, false, err
+	}
+	return sv, stagedHash == workingHash, nil
+}
+
+func refreshAuto(ctx *sql.Context, dbName string, root doltdb.RootValue) (doltdb.RootValue, error) {
+	views, err := ListViews(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		if !v.AutoRefresh {
+			continue
+		}
+		if _, ok, err := root.GetTable(ctx, doltdb.TableName{Name: v.Name}); err != nil {
+			return nil, err
+		} else if !ok {
+			// the view has not been staged yet
+			continue
+		}
+		root, _, err = refresh(ctx, dbName, root, v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to refresh materialized view %s: %w", v.Name, err)
+		}
+	}
+	return root, nil
+}
@@ -0,0 +1,467 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function/aggregation"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/planbuilder"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+)
+
+// viewPlan is the definition of a materialized view bound to the schema of its source table. Expressions in a
+// viewPlan index into rows of the source table.
+type viewPlan struct {
+	// source is the name of the table the view selects from
+	source string
+	// sourceSch is the schema of the source table the plan was bound to
+	sourceSch schema.Schema
+	// schema is the schema of the rows of the view
+	schema sql.PrimaryKeySchema
+	// filter is the WHERE clause of the view, or nil
+	filter sql.Expression
+	// columns describe how to compute each column of the view
+	columns []outputColumn
+	// grouped is true when the view aggregates rows, with or without a GROUP BY clause
+	grouped bool
+	// groupBy are the GROUP BY expressions of a grouped view
+	groupBy []sql.Expression
+	// aggs are the aggregate functions of a grouped view
+	aggs []sql.Aggregation
+}
+
+// outputColumn describes how a single column of a view is computed. Exactly one of its fields is set.
+type outputColumn struct {
+	// expr is a scalar expression over a source row
+	expr sql.Expression
+	// group is the index of a GROUP BY expression, or -1
+	group int
+	// agg is the index of an aggregate function, or -1
+	agg int
+}
+
+// planView parses |definition| and binds it to the schema of its source table in |root|.
+func planView(ctx *sql.Context, dbName string, root doltdb.RootValue, definition string) (*viewPlan, error) {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	b := planbuilder.New(ctx, analyzer.NewCatalog(dSess.Provider()), nil, nil)
+	b.SetParserOptions(sql.LoadSqlMode(ctx).ParserOptions())
+	node, _, _, _, err := b.Parse(definition, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	proj, ok := node.(*plan.Project)
+	if !ok {
+		return nil, ErrUnsupportedDefinition.New(describeNode(node))
+	}
+
+	p := &viewPlan{}
+	child := proj.Child
+	var groupBy *plan.GroupBy
+	if gb, ok := child.(*plan.GroupBy); ok {
+		groupBy = gb
+		child = gb.Child
+	}
+	if f, ok := child.(*plan.Filter); ok {
+		p.filter = f.Expression
+		child = f.Child
+	}
+	if ta, ok := child.(*plan.TableAlias); ok {
+		child = ta.Child
+	}
+	rt, ok := child.(*plan.ResolvedTable)
+	if !ok {
+		return nil, ErrUnsupportedDefinition.New(describeNode(child))
+	}
+	if rt.Database() == nil || !strings.EqualFold(rt.Database().Name(), dbName) {
+		return nil, ErrUnsupportedDefinition.New("a table in another database")
+	}
+	p.source = rt.Name()
+
+	tbl, tName, ok, err := doltdb.GetTableInsensitive(ctx, root, doltdb.TableName{Name: p.source})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrSourceNotFound.New(p.source)
+	}
+	p.source = tName
+	p.sourceSch, err = tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schema.IsKeyless(p.sourceSch) {
+		return nil, ErrKeylessSource.New(p.source)
+	}
+	srcSch, err := sqlutil.FromDoltSchema("", p.source, p.sourceSch)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.filter != nil {
+		if p.filter, err = bindExpression(p.filter, srcSch.Schema); err != nil {
+			return nil, err
+		}
+	}
+
+	outSch := proj.Schema()
+	var pkOrdinals []int
+	if groupBy == nil {
+		pkOrdinals, err = p.bindProjection(proj, srcSch.Schema)
+	} else {
+		pkOrdinals, err = p.bindGroupBy(proj, groupBy, srcSch.Schema)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make(sql.Schema, len(outSch))
+	for i, c := range outSch {
+		cols[i] = &sql.Column{
+			Name:     c.Name,
+			Type:     p.columnType(i, c.Type),
+			Nullable: true,
+		}
+	}
+	for _, ord := range pkOrdinals {
+		cols[ord].PrimaryKey = true
+		cols[ord].Nullable = false
+	}
+	p.schema = sql.NewPrimaryKeySchema(cols, pkOrdinals...)
+	return p, nil
+}
+
+// bindProjection binds the columns of a view without aggregates. Every primary key column of the source table must be
+// selected, and these columns become the primary key of the view.
+func (p *viewPlan) bindProjection(proj *plan.Project, srcSch sql.Schema) ([]int, error) {
+	pkOrdinals := make([]int, 0, p.sourceSch.GetPKCols().Size())
+	for _, pkCol := range p.sourceSch.GetPKCols().GetColumns() {
+		found := -1
+		for i, e := range proj.Projections {
+			if gf, ok := unwrapAlias(e).(*expression.GetField); ok && strings.EqualFold(gf.Name(), pkCol.Name) {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return nil, ErrMissingPrimaryKey.New(pkCol.Name, p.source)
+		}
+		pkOrdinals = append(pkOrdinals, found)
+	}
+
+	p.columns = make([]outputColumn, len(proj.Projections))
+	for i, e := range proj.Projections {
+		bound, err := bindExpression(unwrapAlias(e), srcSch)
+		if err != nil {
+			return nil, err
+		}
+		p.columns[i] = outputColumn{expr: bound, group: -1, agg: -1}
+	}
+	return pkOrdinals, nil
+}
+
+// bindGroupBy binds the columns of a view with aggregates. Every GROUP BY expression must be a selected non-nullable
+// column, and these columns become the primary key of the view. A view without a GROUP BY clause has a single row and
+// no primary key.
+func (p *viewPlan) bindGroupBy(proj *plan.Project, gb *plan.GroupBy, srcSch sql.Schema) ([]int, error) {
+	p.grouped = true
+
+	aggIds := make(map[sql.ColumnId]int)
+	for _, e := range gb.SelectedExprs {
+		agg, ok := e.(sql.Aggregation)
+		if !ok {
+			continue
+		}
+		bound, err := bindExpression(agg, srcSch)
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := agg.(sql.IdExpression); ok {
+			aggIds[id.Id()] = len(p.aggs)
+		}
+		p.aggs = append(p.aggs, bound.(sql.Aggregation))
+	}
+
+	groupCols := make([]string, len(gb.GroupByExprs))
+	for i, e := range gb.GroupByExprs {
+		gf, ok := unwrapAlias(e).(*expression.GetField)
+		if !ok {
+			return nil, ErrUnsupportedDefinition.New(fmt.Sprintf("grouping by expression %s", e.String()))
+		}
+		col, ok := p.sourceSch.GetAllCols().GetByNameCaseInsensitive(gf.Name())
+		if !ok {
+			return nil, sql.ErrColumnNotFound.New(gf.Name())
+		}
+		if col.IsNullable() {
+			return nil, ErrNullableGroupBy.New(col.Name)
+		}
+		bound, err := bindExpression(gf, srcSch)
+		if err != nil {
+			return nil, err
+		}
+		groupCols[i] = col.Name
+		p.groupBy = append(p.groupBy, bound)
+	}
+
+	pkOrdinals := make([]int, len(groupCols))
+	for i := range pkOrdinals {
+		pkOrdinals[i] = -1
+	}
+	p.columns = make([]outputColumn, len(proj.Projections))
+	for i, e := range proj.Projections {
+		gf, ok := unwrapAlias(e).(*expression.GetField)
+		if !ok {
+			return nil, ErrUnsupportedDefinition.New(fmt.Sprintf("the expression %s over aggregated rows", e.String()))
+		}
+		if idx, ok := aggIds[gf.Id()]; ok {
+			p.columns[i] = outputColumn{group: -1, agg: idx}
+			continue
+		}
+		group := -1
+		for j, name := range groupCols {
+			if strings.EqualFold(gf.Name(), name) {
+				group = j
+				break
+			}
+		}
+		if group < 0 {
+			return nil, ErrUnsupportedDefinition.New(fmt.Sprintf("selecting %s which is not grouped", gf.Name()))
+		}
+		p.columns[i] = outputColumn{group: group, agg: -1}
+		if pkOrdinals[group] < 0 {
+			pkOrdinals[group] = i
+		}
+	}
+	for i, ord := range pkOrdinals {
+		if ord < 0 {
+			return nil, ErrUnselectedGroupBy.New(groupCols[i])
+		}
+	}
+	return pkOrdinals, nil
+}
+
+// columnType returns the type to store column |i| of the view as. Sums are widened so that they cannot overflow the
+// type of the summed column.
+func (p *viewPlan) columnType(i int, typ sql.Type) sql.Type {
+	c := p.columns[i]
+	if c.agg < 0 {
+		return typ
+	}
+	switch a := p.aggs[c.agg].(type) {
+	case *aggregation.Count:
+		return gmstypes.Int64
+	case *aggregation.Sum:
+		child := a.Child.Type()
+		if gmstypes.IsInteger(child) {
+			return gmstypes.MustCreateDecimalType(gmstypes.DecimalTypeMaxPrecision, 0)
+		} else if dt, ok := child.(sql.DecimalType); ok {
+			return gmstypes.MustCreateDecimalType(gmstypes.DecimalTypeMaxPrecision, dt.Scale())
+		}
+		return gmstypes.Float64
+	}
+	return typ
+}
+
+// incremental returns whether a change to the source table can be applied to the view without recomputing it. This is
+// always the case for views without aggregates. Aggregated views must only use COUNT and SUM over non-nullable exact
+// numeric expressions, and must count rows with a non-nullable COUNT, so that a group can be removed once its count
+// drops to zero.
+func (p *viewPlan) incremental() bool {
+	if !p.grouped {
+		return true
+	}
+	return p.rowCount() >= 0
+}
+
+// rowCount returns the index of an aggregate which counts every row of its group, or -1 if the view's aggregates
+// cannot be maintained incrementally.
+func (p *viewPlan) rowCount() int {
+	rowCount := -1
+	for i, a := range p.aggs {
+		if p.aggColumn(i) < 0 {
+			return -1
+		}
+		switch a := a.(type) {
+		case *aggregation.Count:
+			if !a.Child.IsNullable() && rowCount < 0 {
+				rowCount = i
+			}
+		case *aggregation.Sum:
+			typ := a.Child.Type()
+			if a.Child.IsNullable() || !(gmstypes.IsInteger(typ) || gmstypes.IsDecimal(typ)) {
+				return -1
+			}
+		default:
+			return -1
+		}
+	}
+	return rowCount
+}
+
+// aggColumn returns the index of the column of the view holding aggregate |i|, or -1 if it is not selected.
+func (p *viewPlan) aggColumn(i int) int {
+	for j, c := range p.columns {
+		if c.agg == i {
+			return j
+		}
+	}
+	return -1
+}
+
+// matches returns whether |row| of the source table passes the view's filter.
+func (p *viewPlan) matches(ctx *sql.Context, row sql.Row) (bool, error) {
+	if p.filter == nil {
+		return true, nil
+	}
+	res, err := sql.EvaluateCondition(ctx, p.filter, row)
+	if err != nil {
+		return false, err
+	}
+	return sql.IsTrue(res), nil
+}
+
+// project returns the view row for |row| of the source table of a view without aggregates.
+func (p *viewPlan) project(ctx *sql.Context, row sql.Row) (sql.Row, error) {
+	out := make(sql.Row, len(p.columns))
+	for i, c := range p.columns {
+		v, err := c.expr.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return p.convert(out)
+}
+
+// convert converts the values of |row| to the types of the view's columns.
+func (p *viewPlan) convert(row sql.Row) (sql.Row, error) {
+	for i, col := range p.schema.Schema {
+		if row[i] == nil {
+			continue
+		}
+		v, _, err := col.Type.Convert(row[i])
+		if err != nil {
+			return nil, err
+		}
+		row[i] = v
+	}
+	return row, nil
+}
+
+// groupKey evaluates the GROUP BY expressions of a grouped view for |row| of the source table, and returns their
+// values along with a hash that is equal for rows of the same group.
+func (p *viewPlan) groupKey(ctx *sql.Context, row sql.Row) (uint64, sql.Row, error) {
+	hash := xxhash.New()
+	vals := make(sql.Row, len(p.groupBy))
+	for i, expr := range p.groupBy {
+		v, err := expr.Eval(ctx, row)
+		if err != nil {
+			return 0, nil, err
+		}
+		vals[i] = v
+
+		if i > 0 {
+			// separate each expression in the grouping key with a nil byte
+			if _, err = hash.Write([]byte{0}); err != nil {
+				return 0, nil, err
+			}
+		}
+		t, isStringType := expr.Type().(sql.StringType)
+		if isStringType && v != nil {
+			v, err = gmstypes.ConvertToString(v, t)
+			if err == nil {
+				err = t.Collation().WriteWeightString(hash, v.(string))
+			}
+		} else {
+			_, err = fmt.Fprintf(hash, "%v", v)
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	return hash.Sum64(), vals, nil
+}
+
+// bindExpression rebinds the column references of |e| to the column indexes of |sch|. Subqueries are not supported.
+func bindExpression(e sql.Expression, sch sql.Schema) (sql.Expression, error) {
+	var err error
+	transform.InspectExpr(e, func(e sql.Expression) bool {
+		if _, ok := e.(*plan.Subquery); ok {
+			err = ErrUnsupportedDefinition.New("a subquery")
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bound, _, err := transform.Expr(e, func(e sql.Expression) (sql.Expression, transform.TreeIdentity, error) {
+		gf, ok := e.(*expression.GetField)
+		if !ok {
+			return e, transform.SameTree, nil
+		}
+		idx := sch.IndexOfColName(gf.Name())
+		if idx < 0 {
+			return nil, transform.SameTree, sql.ErrColumnNotFound.New(gf.Name())
+		}
+		return gf.WithIndex(idx), transform.NewTree, nil
+	})
+	return bound, err
+}
+
+func unwrapAlias(e sql.Expression) sql.Expression {
+	if a, ok := e.(*expression.Alias); ok {
+		return a.Child
+	}
+	return e
+}
+
+// describeNode names the clause of a SELECT statement that |n| was built from, for error messages.
+func describeNode(n sql.Node) string {
+	switch n.(type) {
+	case *plan.Having:
+		return "HAVING"
+	case *plan.Distinct, *plan.OrderedDistinct:
+		return "DISTINCT"
+	case *plan.Sort, *plan.TopN:
+		return "ORDER BY"
+	case *plan.Limit, *plan.Offset:
+		return "LIMIT"
+	case *plan.Window:
+		return "a window function"
+	case *plan.JoinNode:
+		return "a join"
+	case *plan.SetOp:
+		return "UNION"
+	case *plan.SubqueryAlias:
+		return "a subquery"
+	default:
+		return fmt.Sprintf("%T", n)
+	}
+}
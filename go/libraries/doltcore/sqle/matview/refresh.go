@@ -0,0 +1,613 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matview
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression/function/aggregation"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// errNotIncremental is returned when the rows of a view are not consistent with the changes to its source table, in
+// which case the view is recomputed.
+var errNotIncremental = errors.New("materialized view cannot be refreshed incrementally")
+
+// refresh brings the rows of |view| in |root| up to date with its source table in |root|, and records the source
+// table's hash in the dolt_materialized_views table. It returns the new root and the kind of refresh performed.
+func refresh(ctx *sql.Context, dbName string, root doltdb.RootValue, view View) (doltdb.RootValue, string, error) {
+	p, err := planView(ctx, dbName, root, view.Definition)
+	if err != nil {
+		return nil, "", err
+	}
+	srcHash, ok, err := root.GetTableHash(ctx, doltdb.TableName{Name: p.source})
+	if err != nil {
+		return nil, "", err
+	}
+	if !ok {
+		return nil, "", ErrSourceNotFound.New(p.source)
+	}
+	if srcHash.String() == view.SourceHash {
+		return root, RefreshUnchanged, nil
+	}
+
+	tName := doltdb.TableName{Name: view.Name}
+	mvTbl, ok, err := root.GetTable(ctx, tName)
+	if err != nil {
+		return nil, "", err
+	}
+	if !ok {
+		return nil, "", ErrViewNotFound.New(view.Name)
+	}
+	mvSch, err := mvTbl.GetSchema(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if err = checkViewSchema(view.Name, p, mvSch); err != nil {
+		return nil, "", err
+	}
+
+	kind := RefreshFull
+	ed, err := writer.NewRootEditor(ctx, dbName, root)
+	if err != nil {
+		return nil, "", err
+	}
+	if view.SourceHash != "" && p.incremental() {
+		err = refreshIncremental(ctx, p, root, view, mvTbl, mvSch, ed)
+		if err == nil {
+			kind = RefreshIncremental
+		} else if !errors.Is(err, errNotIncremental) {
+			return nil, "", err
+		}
+	}
+
+	if kind == RefreshFull {
+		// discard any edits of an incremental refresh that was abandoned, and recompute the view from scratch
+		empty, err := doltdb.NewEmptyTable(ctx, root.VRW(), root.NodeStore(), mvSch)
+		if err != nil {
+			return nil, "", err
+		}
+		root, err = root.PutTable(ctx, tName, empty)
+		if err != nil {
+			return nil, "", err
+		}
+		ed, err = writer.NewRootEditor(ctx, dbName, root)
+		if err != nil {
+			return nil, "", err
+		}
+		if err = refreshFull(ctx, p, root, view, ed); err != nil {
+			return nil, "", err
+		}
+	}
+
+	refreshed := view
+	refreshed.SourceHash = srcHash.String()
+	if err = ed.Update(ctx, doltdb.MaterializedViewsTableName, view.toRow(), refreshed.toRow()); err != nil {
+		return nil, "", err
+	}
+	root, err = ed.Finish(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return root, kind, nil
+}
+
+// checkViewSchema returns an error if the table holding the rows of |view| no longer has the schema its definition
+// produces, e.g. because the table was altered or the source table's columns changed type.
+func checkViewSchema(view string, p *viewPlan, mvSch schema.Schema) error {
+	sqlSch, err := sqlutil.FromDoltSchema("", view, mvSch)
+	if err != nil {
+		return err
+	}
+	if len(sqlSch.Schema) != len(p.schema.Schema) || len(sqlSch.PkOrdinals) != len(p.schema.PkOrdinals) {
+		return ErrViewSchemaChanged.New(view)
+	}
+	for i, col := range sqlSch.Schema {
+		expected := p.schema.Schema[i]
+		if !strings.EqualFold(col.Name, expected.Name) || !col.Type.Equals(expected.Type) {
+			return ErrViewSchemaChanged.New(view)
+		}
+	}
+	for i, ord := range sqlSch.PkOrdinals {
+		if ord != p.schema.PkOrdinals[i] {
+			return ErrViewSchemaChanged.New(view)
+		}
+	}
+	return nil
+}
+
+// refreshFull replaces the rows of |view| with the result of evaluating it over every row of its source table. The
+// table holding the view's rows must be empty.
+func refreshFull(ctx *sql.Context, p *viewPlan, root doltdb.RootValue, view View, ed *writer.RootEditor) error {
+	src, ok, err := root.GetTable(ctx, doltdb.TableName{Name: p.source})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSourceNotFound.New(p.source)
+	}
+	rows, err := src.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	m := durable.ProllyMapFromIndex(rows)
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	type group struct {
+		key     sql.Row
+		buffers []sql.AggregationBuffer
+	}
+	newGroup := func(key sql.Row) (*group, error) {
+		g := &group{key: key, buffers: make([]sql.AggregationBuffer, len(p.aggs))}
+		for i, a := range p.aggs {
+			b, err := a.NewBuffer()
+			if err != nil {
+				return nil, err
+			}
+			g.buffers[i] = b
+		}
+		return g, nil
+	}
+	groups := make(map[uint64]*group)
+	var order []uint64
+
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		row, err := index.BuildRow(ctx, k, v, p.sourceSch, m.NodeStore())
+		if err != nil {
+			return err
+		}
+		if ok, err := p.matches(ctx, row); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		if !p.grouped {
+			out, err := p.project(ctx, row)
+			if err != nil {
+				return err
+			}
+			if err = ed.Insert(ctx, view.Name, out); err != nil {
+				return err
+			}
+			continue
+		}
+
+		h, key, err := p.groupKey(ctx, row)
+		if err != nil {
+			return err
+		}
+		g, ok := groups[h]
+		if !ok {
+			if g, err = newGroup(key); err != nil {
+				return err
+			}
+			groups[h] = g
+			order = append(order, h)
+		}
+		for _, b := range g.buffers {
+			if err = b.Update(ctx, row); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !p.grouped {
+		return nil
+	}
+	if len(order) == 0 && len(p.groupBy) == 0 {
+		// an aggregate without GROUP BY has a single row, even if no rows match
+		g, err := newGroup(nil)
+		if err != nil {
+			return err
+		}
+		groups[0] = g
+		order = append(order, 0)
+	}
+
+	for _, h := range order {
+		g := groups[h]
+		vals := make([]interface{}, len(g.buffers))
+		for i, b := range g.buffers {
+			if vals[i], err = b.Eval(ctx); err != nil {
+				return err
+			}
+			b.Dispose()
+		}
+		out := make(sql.Row, len(p.columns))
+		for i, c := range p.columns {
+			if c.group >= 0 {
+				out[i] = g.key[c.group]
+			} else {
+				out[i] = vals[c.agg]
+			}
+		}
+		if out, err = p.convert(out); err != nil {
+			return err
+		}
+		if err = ed.Insert(ctx, view.Name, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshIncremental applies the differences between the source table as of the last refresh of |view| and its
+// source table in |root| to the rows of the view. It returns errNotIncremental if the previous version of the source
+// table is unavailable or has a different schema, or if the view's rows are inconsistent with the changes.
+func refreshIncremental(ctx *sql.Context, p *viewPlan, root doltdb.RootValue, view View, mvTbl *doltdb.Table, mvSch schema.Schema, ed *writer.RootEditor) error {
+	addr, ok := hash.MaybeParse(view.SourceHash)
+	if !ok {
+		return errNotIncremental
+	}
+	vrw, ns := root.VRW(), root.NodeStore()
+	// the previous version of the source table may have been garbage collected
+	if v, err := vrw.ReadValue(ctx, addr); err != nil {
+		return err
+	} else if v == nil {
+		return errNotIncremental
+	}
+	dt, err := durable.TableFromAddr(ctx, vrw, ns, addr)
+	if err != nil {
+		return err
+	}
+	from := doltdb.NewTableFromDurable(dt)
+	fromSch, err := from.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	if !schema.SchemasAreEqual(fromSch, p.sourceSch) {
+		return errNotIncremental
+	}
+	to, ok, err := root.GetTable(ctx, doltdb.TableName{Name: p.source})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSourceNotFound.New(p.source)
+	}
+
+	fromRows, err := from.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	toRows, err := to.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	fromMap, toMap := durable.ProllyMapFromIndex(fromRows), durable.ProllyMapFromIndex(toRows)
+
+	var d differ
+	if p.grouped {
+		d = newAggregateDiffer(p)
+	} else {
+		d = &projectionDiffer{p: p, view: view.Name, ed: ed}
+	}
+	err = prolly.DiffMaps(ctx, fromMap, toMap, false, func(_ context.Context, diff tree.Diff) error {
+		var oldRow, newRow sql.Row
+		if diff.Type != tree.AddedDiff {
+			row, err := index.BuildRow(ctx, val.Tuple(diff.Key), val.Tuple(diff.From), p.sourceSch, ns)
+			if err != nil {
+				return err
+			}
+			if ok, err := p.matches(ctx, row); err != nil {
+				return err
+			} else if ok {
+				oldRow = row
+			}
+		}
+		if diff.Type != tree.RemovedDiff {
+			row, err := index.BuildRow(ctx, val.Tuple(diff.Key), val.Tuple(diff.To), p.sourceSch, ns)
+			if err != nil {
+				return err
+			}
+			if ok, err := p.matches(ctx, row); err != nil {
+				return err
+			} else if ok {
+				newRow = row
+			}
+		}
+		return d.apply(ctx, oldRow, newRow)
+	})
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	mvRows, err := mvTbl.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	return d.finish(ctx, durable.ProllyMapFromIndex(mvRows), mvSch, view.Name, ed)
+}
+
+// differ applies changes of source rows to the rows of a view.
+type differ interface {
+	// apply applies the change of a source row from |oldRow| to |newRow|. Either row is nil if the row did not exist
+	// or did not match the view's filter.
+	apply(ctx *sql.Context, oldRow, newRow sql.Row) error
+	// finish writes any changes accumulated by apply. |mv| holds the rows of the view before the refresh.
+	finish(ctx *sql.Context, mv prolly.Map, mvSch schema.Schema, view string, ed *writer.RootEditor) error
+}
+
+// projectionDiffer maintains a view without aggregates, whose rows correspond one to one with matching source rows.
+type projectionDiffer struct {
+	p    *viewPlan
+	view string
+	ed   *writer.RootEditor
+}
+
+func (d *projectionDiffer) apply(ctx *sql.Context, oldRow, newRow sql.Row) (err error) {
+	if oldRow != nil {
+		if oldRow, err = d.p.project(ctx, oldRow); err != nil {
+			return err
+		}
+	}
+	if newRow != nil {
+		if newRow, err = d.p.project(ctx, newRow); err != nil {
+			return err
+		}
+	}
+	switch {
+	case oldRow != nil && newRow != nil:
+		return d.ed.Update(ctx, d.view, oldRow, newRow)
+	case oldRow != nil:
+		return d.ed.Delete(ctx, d.view, oldRow)
+	case newRow != nil:
+		return d.ed.Insert(ctx, d.view, newRow)
+	}
+	return nil
+}
+
+func (d *projectionDiffer) finish(*sql.Context, prolly.Map, schema.Schema, string, *writer.RootEditor) error {
+	return nil
+}
+
+// aggregateDiffer maintains a view of COUNT and SUM aggregates by accumulating, for each affected group, the change
+// in each aggregate, and then adding those changes to the view's current values.
+type aggregateDiffer struct {
+	p        *viewPlan
+	rowCount int
+	deltas   map[uint64]*groupDelta
+	order    []uint64
+}
+
+type groupDelta struct {
+	key    sql.Row
+	counts []int64
+	sums   []decimal.Decimal
+}
+
+func newAggregateDiffer(p *viewPlan) *aggregateDiffer {
+	return &aggregateDiffer{p: p, rowCount: p.rowCount(), deltas: make(map[uint64]*groupDelta)}
+}
+
+func (d *aggregateDiffer) apply(ctx *sql.Context, oldRow, newRow sql.Row) error {
+	if oldRow != nil {
+		if err := d.add(ctx, oldRow, -1); err != nil {
+			return err
+		}
+	}
+	if newRow != nil {
+		if err := d.add(ctx, newRow, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *aggregateDiffer) add(ctx *sql.Context, row sql.Row, sign int64) error {
+	h, key, err := d.p.groupKey(ctx, row)
+	if err != nil {
+		return err
+	}
+	delta, ok := d.deltas[h]
+	if !ok {
+		delta = &groupDelta{
+			key:    key,
+			counts: make([]int64, len(d.p.aggs)),
+			sums:   make([]decimal.Decimal, len(d.p.aggs)),
+		}
+		d.deltas[h] = delta
+		d.order = append(d.order, h)
+	}
+	for i, a := range d.p.aggs {
+		switch a := a.(type) {
+		case *aggregation.Count:
+			v, err := a.Child.Eval(ctx, row)
+			if err != nil {
+				return err
+			}
+			if v != nil {
+				delta.counts[i] += sign
+			}
+		case *aggregation.Sum:
+			v, err := a.Child.Eval(ctx, row)
+			if err != nil {
+				return err
+			}
+			dec, err := toDecimal(v)
+			if err != nil {
+				return err
+			}
+			delta.sums[i] = delta.sums[i].Add(dec.Mul(decimal.NewFromInt(sign)))
+		}
+	}
+	return nil
+}
+
+func (d *aggregateDiffer) finish(ctx *sql.Context, mv prolly.Map, mvSch schema.Schema, view string, ed *writer.RootEditor) error {
+	for _, h := range d.order {
+		delta := d.deltas[h]
+		old, err := d.lookup(ctx, mv, mvSch, delta.key)
+		if err != nil {
+			return err
+		}
+
+		vals := make([]interface{}, len(d.p.aggs))
+		var count int64
+		for i, a := range d.p.aggs {
+			var prev interface{}
+			if old != nil {
+				prev = old[d.p.aggColumn(i)]
+			}
+			switch a.(type) {
+			case *aggregation.Count:
+				n, err := toInt64(prev)
+				if err != nil {
+					return err
+				}
+				vals[i] = n + delta.counts[i]
+				if i == d.rowCount {
+					count = n + delta.counts[i]
+				}
+			case *aggregation.Sum:
+				sum, err := toDecimal(prev)
+				if err != nil {
+					return err
+				}
+				vals[i] = sum.Add(delta.sums[i])
+			}
+		}
+
+		global := len(d.p.groupBy) == 0
+		switch {
+		case count < 0, old == nil && global:
+			return errNotIncremental
+		case old == nil && count == 0:
+			// rows of a group which did not exist were added and removed again
+			continue
+		}
+		if count == 0 {
+			if !global {
+				if err = ed.Delete(ctx, view, old); err != nil {
+					return err
+				}
+				continue
+			}
+			// an aggregate without GROUP BY over no rows counts zero rows and sums to NULL
+			for i, a := range d.p.aggs {
+				if _, ok := a.(*aggregation.Sum); ok {
+					vals[i] = nil
+				}
+			}
+		}
+
+		out := make(sql.Row, len(d.p.columns))
+		for i, c := range d.p.columns {
+			if c.group >= 0 {
+				out[i] = delta.key[c.group]
+			} else {
+				out[i] = vals[c.agg]
+			}
+		}
+		if out, err = d.p.convert(out); err != nil {
+			return err
+		}
+		if old == nil {
+			err = ed.Insert(ctx, view, out)
+		} else {
+			err = ed.Update(ctx, view, old, out)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup returns the row of the view for the group |key|, or nil if the view has no row for the group.
+func (d *aggregateDiffer) lookup(ctx *sql.Context, mv prolly.Map, mvSch schema.Schema, key sql.Row) (sql.Row, error) {
+	if len(d.p.groupBy) == 0 {
+		// the view has a single row
+		iter, err := mv.IterAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		return index.BuildRow(ctx, k, v, mvSch, mv.NodeStore())
+	}
+
+	kb := val.NewTupleBuilder(mvSch.GetKeyDescriptor())
+	for i, ord := range d.p.schema.PkOrdinals {
+		// primary key columns of the view are the grouping columns, in GROUP BY order
+		v, _, err := d.p.schema.Schema[ord].Type.Convert(key[i])
+		if err != nil {
+			return nil, err
+		}
+		if err = tree.PutField(ctx, mv.NodeStore(), kb, i, v); err != nil {
+			return nil, err
+		}
+	}
+	var row sql.Row
+	err := mv.Get(ctx, kb.Build(mv.Pool()), func(k, v val.Tuple) (err error) {
+		if k != nil {
+			row, err = index.BuildRow(ctx, k, v, mvSch, mv.NodeStore())
+		}
+		return err
+	})
+	return row, err
+}
+
+func toInt64(v interface{}) (int64, error) {
+	if v == nil {
+		return 0, nil
+	}
+	n, _, err := gmstypes.Int64.Convert(v)
+	if err != nil {
+		return 0, err
+	}
+	return n.(int64), nil
+}
+
+func toDecimal(v interface{}) (decimal.Decimal, error) {
+	if v == nil {
+		return decimal.Zero, nil
+	}
+	dec, _, err := gmstypes.InternalDecimalType.Convert(v)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	return dec.(decimal.Decimal), nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	ast "github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// parser is a sql.Parser that rewrites the statements returned by another parser with RewriteStatement, and parses
+// the materialized view statements into calls of the procedures implementing them. Engines created by Dolt use it in
+// place of the engine's default parser.
+type parser struct {
+	sql.Parser
+}
+
+var _ sql.Parser = parser{}
+
+// NewParser wraps |p| so that the statements it returns are rewritten with RewriteStatement.
+func NewParser(p sql.Parser) sql.Parser {
+	if _, ok := p.(parser); ok {
+		return p
+	}
+	return parser{Parser: p}
+}
+
+// RewriteStatement rewrites the syntax Dolt supports that the engine doesn't in |stmt| into statements the engine
+// does understand. Statements parsed by something other than the engine's parser must be rewritten before they are
+// executed.
+func RewriteStatement(stmt ast.Statement) error {
+	return RewriteSystemTime(stmt)
+}
+
+func (p parser) ParseSimple(query string) (ast.Statement, error) {
+	call, _, ok, err := parseMaterializedView(query, ';', func(sel string) (ast.Statement, int, error) {
+		stmt, err := p.Parser.ParseSimple(sel)
+		return stmt, len(sel), err
+	})
+	if err != nil {
+		return nil, err
+	} else if ok {
+		return p.Parser.ParseSimple(call)
+	}
+
+	stmt, err := p.Parser.ParseSimple(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt, RewriteStatement(stmt)
+}
+
+func (p parser) Parse(ctx *sql.Context, query string, multi bool) (ast.Statement, string, string, error) {
+	return p.ParseWithOptions(ctx, query, ';', multi, sql.LoadSqlMode(ctx).ParserOptions())
+}
+
+func (p parser) ParseWithOptions(ctx context.Context, query string, delimiter rune, multi bool, options ast.ParserOptions) (ast.Statement, string, string, error) {
+	s := sql.RemoveSpaceAndDelimiter(query, delimiter)
+	call, n, ok, err := parseMaterializedView(s, delimiter, func(sel string) (ast.Statement, int, error) {
+		stmt, _, remainder, err := p.Parser.ParseWithOptions(ctx, sel, delimiter, multi, options)
+		return stmt, len(sel) - len(remainder), err
+	})
+	if err != nil {
+		return nil, s, "", err
+	} else if ok {
+		stmt, err := p.Parser.ParseSimple(call)
+		return stmt, sql.RemoveSpaceAndDelimiter(s[:n], delimiter), s[n:], err
+	}
+
+	stmt, parsed, remainder, err := p.Parser.ParseWithOptions(ctx, query, delimiter, multi, options)
+	if err != nil {
+		return nil, parsed, remainder, err
+	}
+	return stmt, parsed, remainder, RewriteStatement(stmt)
+}
+
+func (p parser) ParseOneWithOptions(ctx context.Context, query string, options ast.ParserOptions) (ast.Statement, int, error) {
+	call, n, ok, err := parseMaterializedView(query, ';', func(sel string) (ast.Statement, int, error) {
+		stmt, idx, err := p.Parser.ParseOneWithOptions(ctx, sel, options)
+		if idx == 0 {
+			idx = len(sel)
+		}
+		return stmt, idx, err
+	})
+	if err != nil {
+		return nil, 0, err
+	} else if ok {
+		if n >= len(query) {
+			n = 0
+		}
+		stmt, err := p.Parser.ParseSimple(call)
+		return stmt, n, err
+	}
+
+	stmt, idx, err := p.Parser.ParseOneWithOptions(ctx, query, options)
+	if err != nil {
+		return nil, idx, err
+	}
+	return stmt, idx, RewriteStatement(stmt)
+}
@@ -0,0 +1,214 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package querydigest aggregates statistics about the statements run by a sql-server by their digest, the text of the
+// statement with its literals replaced with placeholders, in the manner of MySQL's
+// performance_schema.events_statements_summary_by_digest table.
+package querydigest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultMaxDigests is the default number of digests a Store keeps statistics for.
+const DefaultMaxDigests = 1000
+
+// Default is the Store of the statements run by this process, which the dolt_query_digests system table reads.
+var Default = NewStore(DefaultMaxDigests)
+
+// Statement is a statement that was run, to be recorded in a Store.
+type Statement struct {
+	// Database is the current database of the session that ran the statement
+	Database string
+	// Text is the normalized text of the statement
+	Text string
+	// Start is the time the statement started
+	Start time.Time
+	// Latency is the time the statement took to run
+	Latency time.Duration
+	// RowsExamined is the number of rows read from tables to run the statement
+	RowsExamined uint64
+	// RowsSent is the number of rows the statement returned
+	RowsSent uint64
+	// Failed is true if the statement returned an error
+	Failed bool
+}
+
+// Summary is the statistics of the statements with the same digest.
+type Summary struct {
+	// Digest is the hex-encoded SHA-256 hash of the database and the text of the statements, or empty for the summary
+	// of the statements that didn't fit in the Store.
+	Digest       string
+	Database     string
+	Text         string
+	Count        uint64
+	Errors       uint64
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+	// P99Latency is an estimate of the 99th percentile of the latencies of the statements, accurate to within 20%.
+	P99Latency   time.Duration
+	RowsExamined uint64
+	RowsSent     uint64
+	FirstSeen    time.Time
+	LastSeen     time.Time
+}
+
+// AvgLatency returns the average latency of the statements.
+func (s Summary) AvgLatency() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Count)
+}
+
+// latencyBucketsPerDoubling is the number of histogram buckets latencies are counted in for each doubling of latency.
+const latencyBucketsPerDoubling = 4
+
+// numLatencyBuckets covers latencies up to 2^40 microseconds, about 12 days. Longer latencies go in the last bucket.
+const numLatencyBuckets = 40 * latencyBucketsPerDoubling
+
+// latencyBucket returns the histogram bucket of |latency|. Bucket i holds latencies up to latencyBucketBound(i).
+func latencyBucket(latency time.Duration) int {
+	us := float64(latency.Microseconds())
+	if us <= 1 {
+		return 0
+	}
+	b := int(math.Ceil(math.Log2(us) * latencyBucketsPerDoubling))
+	if b >= numLatencyBuckets {
+		return numLatencyBuckets - 1
+	}
+	return b
+}
+
+// latencyBucketBound returns the greatest latency in bucket |b|.
+func latencyBucketBound(b int) time.Duration {
+	return time.Duration(math.Pow(2, float64(b)/latencyBucketsPerDoubling) * float64(time.Microsecond))
+}
+
+type digest struct {
+	Summary
+	latencies [numLatencyBuckets]uint64
+}
+
+func (d *digest) record(stmt Statement) {
+	if d.Count == 0 {
+		d.FirstSeen = stmt.Start
+	}
+	d.Count++
+	if stmt.Failed {
+		d.Errors++
+	}
+	d.TotalLatency += stmt.Latency
+	if stmt.Latency > d.MaxLatency {
+		d.MaxLatency = stmt.Latency
+	}
+	d.latencies[latencyBucket(stmt.Latency)]++
+	d.RowsExamined += stmt.RowsExamined
+	d.RowsSent += stmt.RowsSent
+	d.LastSeen = stmt.Start
+}
+
+// summary returns the Summary of |d|, estimating its 99th percentile latency from its histogram.
+func (d *digest) summary() Summary {
+	s := d.Summary
+	rank := uint64(math.Ceil(float64(s.Count) * 0.99))
+	var seen uint64
+	for b, n := range d.latencies {
+		seen += n
+		if n > 0 && seen >= rank {
+			s.P99Latency = latencyBucketBound(b)
+			break
+		}
+	}
+	if s.P99Latency > s.MaxLatency {
+		s.P99Latency = s.MaxLatency
+	}
+	return s
+}
+
+// Store aggregates the statistics of the statements recorded in it by their digest. It keeps statistics for a limited
+// number of digests, and counts the statements of every other digest together, as MySQL does.
+type Store struct {
+	mu         sync.Mutex
+	maxDigests int
+	digests    map[string]*digest
+	overflow   digest
+}
+
+// NewStore returns a Store that keeps statistics for up to |maxDigests| digests.
+func NewStore(maxDigests int) *Store {
+	return &Store{maxDigests: maxDigests, digests: make(map[string]*digest)}
+}
+
+// SetMaxDigests changes the number of digests that |s| keeps statistics for. Digests that are already in |s| are kept.
+func (s *Store) SetMaxDigests(maxDigests int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxDigests = maxDigests
+}
+
+// Record adds |stmt| to the statistics of its digest.
+func (s *Store) Record(stmt Statement) {
+	key := digestOf(stmt.Database, stmt.Text)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.digests[key]
+	if !ok {
+		if len(s.digests) >= s.maxDigests {
+			s.overflow.record(stmt)
+			return
+		}
+		d = &digest{Summary: Summary{Digest: key, Database: stmt.Database, Text: stmt.Text}}
+		s.digests[key] = d
+	}
+	d.record(stmt)
+}
+
+// Summaries returns the statistics of every digest in |s|, ordered by their digest, followed by the statistics of the
+// statements that didn't fit in |s| if there are any.
+func (s *Store) Summaries() []Summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	summaries := make([]Summary, 0, len(s.digests)+1)
+	for _, d := range s.digests {
+		summaries = append(summaries, d.summary())
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Digest < summaries[j].Digest
+	})
+	if s.overflow.Count > 0 {
+		summaries = append(summaries, s.overflow.summary())
+	}
+	return summaries
+}
+
+// Reset discards the statistics of every digest in |s|.
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.digests = make(map[string]*digest)
+	s.overflow = digest{}
+}
+
+// digestOf returns the digest of the statements with the normalized text |text| run in the database |database|.
+func digestOf(database, text string) string {
+	sum := sha256.Sum256([]byte(database + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querydigest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	s := NewStore(2)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= 100; i++ {
+		s.Record(Statement{
+			Database:     "db",
+			Text:         "select * from t where id = ?",
+			Start:        start.Add(time.Duration(i) * time.Second),
+			Latency:      time.Duration(i) * time.Millisecond,
+			RowsExamined: 10,
+			RowsSent:     1,
+			Failed:       i%10 == 0,
+		})
+	}
+	s.Record(Statement{Database: "other", Text: "select * from t where id = ?", Start: start, Latency: time.Second})
+	s.Record(Statement{Database: "db", Text: "insert into t values (?)", Start: start, Latency: time.Second})
+	s.Record(Statement{Database: "db", Text: "delete from t", Start: start, Latency: 2 * time.Second})
+
+	summaries := s.Summaries()
+	require.Len(t, summaries, 3)
+	var sel Summary
+	for _, summary := range summaries[:2] {
+		if summary.Database == "db" {
+			sel = summary
+		} else {
+			assert.Equal(t, "other", summary.Database)
+			assert.Equal(t, uint64(1), summary.Count)
+		}
+	}
+	assert.Equal(t, "select * from t where id = ?", sel.Text)
+	assert.Len(t, sel.Digest, 64)
+	assert.Equal(t, uint64(100), sel.Count)
+	assert.Equal(t, uint64(10), sel.Errors)
+	assert.Equal(t, 5050*time.Millisecond, sel.TotalLatency)
+	assert.Equal(t, 50500*time.Microsecond, sel.AvgLatency())
+	assert.Equal(t, 100*time.Millisecond, sel.MaxLatency)
+	assert.InDelta(t, float64(99*time.Millisecond), float64(sel.P99Latency), float64(20*time.Millisecond))
+	assert.Equal(t, uint64(1000), sel.RowsExamined)
+	assert.Equal(t, uint64(100), sel.RowsSent)
+	assert.Equal(t, start.Add(time.Second), sel.FirstSeen)
+	assert.Equal(t, start.Add(100*time.Second), sel.LastSeen)
+
+	// statements of digests that don't fit are counted together
+	overflow := summaries[2]
+	assert.Empty(t, overflow.Digest)
+	assert.Empty(t, overflow.Text)
+	assert.Equal(t, uint64(2), overflow.Count)
+	assert.Equal(t, 2*time.Second, overflow.MaxLatency)
+	assert.Equal(t, 2*time.Second, overflow.P99Latency)
+
+	s.Reset()
+	assert.Empty(t, s.Summaries())
+}
+
+func TestLatencyBuckets(t *testing.T) {
+	for _, latency := range []time.Duration{0, time.Microsecond, 3 * time.Microsecond, time.Millisecond, 1234567 * time.Microsecond, time.Hour} {
+		b := latencyBucket(latency)
+		assert.LessOrEqual(t, latency, latencyBucketBound(b)+time.Microsecond, latency.String())
+		if b > 0 {
+			assert.Greater(t, latency, latencyBucketBound(b-1), latency.String())
+		}
+	}
+	assert.Equal(t, numLatencyBuckets-1, latencyBucket(1000*time.Hour*24))
+}
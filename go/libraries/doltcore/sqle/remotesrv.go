@@ -16,7 +16,11 @@ package sqle
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"strings"
 
+	sqle "github.com/dolthub/go-mysql-server"
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
@@ -102,3 +106,74 @@ func WithUserPasswordAuth(args remotesrv.ServerArgs, authnz remotesrv.AccessCont
 	args.Options = append(args.Options, si.Options()...)
 	return args
 }
+
+// PreReceiveAssertion is a SQL query which must return no rows when run against the new head of each pushed branch
+// matching |Branch|, a path.Match pattern. An empty |Branch| matches every branch. If the query returns rows, the
+// push is rejected with |Message|, or with a description of the first row if |Message| is empty.
+type PreReceiveAssertion struct {
+	Name    string
+	Branch  string
+	Query   string
+	Message string
+}
+
+// sqlPreReceiveHook is a remotesrv.PreReceiveHook which runs PreReceiveAssertions. Each assertion is run with the
+// pushed head commit as the current database, so unqualified table names resolve to the tables of the pushed root.
+type sqlPreReceiveHook struct {
+	ctxFactory func(context.Context) (*sql.Context, error)
+	engine     *sqle.Engine
+	assertions []PreReceiveAssertion
+}
+
+var _ remotesrv.PreReceiveHook = sqlPreReceiveHook{}
+
+// NewSQLPreReceiveHook returns a remotesrv.PreReceiveHook which runs |assertions| through |engine| against the
+// databases served by a remotesapi server created with RemoteSrvFSAndDBCache.
+func NewSQLPreReceiveHook(ctxFactory func(context.Context) (*sql.Context, error), engine *sqle.Engine, assertions []PreReceiveAssertion) remotesrv.PreReceiveHook {
+	return sqlPreReceiveHook{ctxFactory: ctxFactory, engine: engine, assertions: assertions}
+}
+
+func (h sqlPreReceiveHook) PreReceive(ctx context.Context, push remotesrv.Push) error {
+	for _, u := range push.Updates {
+		if u.IsDelete() || !strings.HasPrefix(u.Ref, "refs/heads/") {
+			continue
+		}
+		branch := strings.TrimPrefix(u.Ref, "refs/heads/")
+		for _, a := range h.assertions {
+			if a.Branch != "" {
+				if matched, _ := path.Match(a.Branch, branch); !matched {
+					continue
+				}
+			}
+			if err := h.runAssertion(ctx, push.RepoPath, branch, u.New.String(), a); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (h sqlPreReceiveHook) runAssertion(ctx context.Context, dbName, branch, commit string, a PreReceiveAssertion) error {
+	sqlCtx, err := h.ctxFactory(ctx)
+	if err != nil {
+		return err
+	}
+	sqlCtx.SetCurrentDatabase(dsess.RevisionDbName(dbName, commit))
+
+	_, iter, _, err := h.engine.Query(sqlCtx, a.Query)
+	if err != nil {
+		return fmt.Errorf("%w: assertion '%s' failed to run on branch '%s': %s", remotesrv.ErrPushRejected, a.Name, branch, err.Error())
+	}
+	rows, err := sql.RowIterToRows(sqlCtx, iter)
+	if err != nil {
+		return fmt.Errorf("%w: assertion '%s' failed to run on branch '%s': %s", remotesrv.ErrPushRejected, a.Name, branch, err.Error())
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if a.Message != "" {
+		return fmt.Errorf("%w: assertion '%s' failed on branch '%s': %s", remotesrv.ErrPushRejected, a.Name, branch, a.Message)
+	}
+	return fmt.Errorf("%w: assertion '%s' failed on branch '%s': query returned %d row(s), first row: %v", remotesrv.ErrPushRejected, a.Name, branch, len(rows), rows[0])
+}
@@ -0,0 +1,234 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	gms "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/remotesrv"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestSQLPreReceiveHook(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+	db, err := NewDatabase(ctx, "dolt", dEnv.DbData(), opts)
+	require.NoError(t, err)
+	engine, sqlCtx, err := NewTestEngine(dEnv, ctx, db)
+	require.NoError(t, err)
+	pro := engine.Analyzer.Catalog.DbProvider.(dsess.DoltDatabaseProvider)
+	ctxFactory := func(ctx context.Context) (*sql.Context, error) {
+		return NewTestSQLCtxWithProvider(ctx, pro, nil), nil
+	}
+
+	query := func(q string) []sql.Row {
+		_, iter, _, err := engine.Query(sqlCtx, q)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(sqlCtx, iter)
+		require.NoError(t, err)
+		return rows
+	}
+	query("create table t (pk int primary key, v int)")
+	query("insert into t values (1, 1), (2, -2)")
+	rows := query("call dolt_commit('-Am', 'add t', '--author', 'test <test@example.com>')")
+	head, ok := hash.MaybeParse(rows[0][0].(string))
+	require.True(t, ok)
+
+	push := remotesrv.Push{
+		RepoPath: "dolt",
+		Updates:  []remotesrv.RefUpdate{{Ref: "refs/heads/main", New: head}},
+	}
+
+	hook := NewSQLPreReceiveHook(ctxFactory, engine, []PreReceiveAssertion{
+		{Name: "has_rows", Query: "select 1 from dual where (select count(*) from t) = 0"},
+		{Name: "release_only", Branch: "release/*", Query: "select * from t"},
+	})
+	assert.NoError(t, hook.PreReceive(ctx, push))
+
+	hook = NewSQLPreReceiveHook(ctxFactory, engine, []PreReceiveAssertion{
+		{Name: "non_negative", Branch: "main", Query: "select pk from t where v < 0"},
+	})
+	err = hook.PreReceive(ctx, push)
+	assert.ErrorIs(t, err, remotesrv.ErrPushRejected)
+	assert.Contains(t, err.Error(), "assertion 'non_negative' failed on branch 'main'")
+
+	hook = NewSQLPreReceiveHook(ctxFactory, engine, []PreReceiveAssertion{
+		{Name: "no_forbidden_tables", Query: "select table_name from information_schema.tables where table_schema = database() and table_name = 't'", Message: "table t is not allowed"},
+	})
+	err = hook.PreReceive(ctx, push)
+	assert.ErrorIs(t, err, remotesrv.ErrPushRejected)
+	assert.Contains(t, err.Error(), "table t is not allowed")
+
+	// Deleted branches have no new head to check.
+	push.Updates[0] = remotesrv.RefUpdate{Ref: "refs/heads/main", Old: head}
+	assert.NoError(t, hook.PreReceive(ctx, push))
+}
+
+// branchCheckingHook is a remotesrv.PreReceiveHook which records, for each pushed branch, whether the branch already
+// pointed at its pushed head when the hook ran.
+type branchCheckingHook struct {
+	ctxFactory func(context.Context) (*sql.Context, error)
+	engine     *gms.Engine
+	swappedIn  map[string]bool
+}
+
+func (h branchCheckingHook) PreReceive(ctx context.Context, push remotesrv.Push) error {
+	sqlCtx, err := h.ctxFactory(ctx)
+	if err != nil {
+		return err
+	}
+	for _, u := range push.Updates {
+		branch := strings.TrimPrefix(u.Ref, "refs/heads/")
+		_, iter, _, err := h.engine.Query(sqlCtx, fmt.Sprintf("select hash from dolt_branches where name = '%s'", branch))
+		if err != nil {
+			return err
+		}
+		rows, err := sql.RowIterToRows(sqlCtx, iter)
+		if err != nil {
+			return err
+		}
+		h.swappedIn[branch] = len(rows) > 0 && rows[0][0] == u.New.String()
+	}
+	return nil
+}
+
+func TestPreReceiveHookOnPush(t *testing.T) {
+	ctx := context.Background()
+
+	newEngine := func(dEnv *env.DoltEnv) (*gms.Engine, *sql.Context, func(context.Context) (*sql.Context, error)) {
+		tmpDir, err := dEnv.TempTableFilesDir()
+		require.NoError(t, err)
+		opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+		db, err := NewDatabase(ctx, "dolt", dEnv.DbData(), opts)
+		require.NoError(t, err)
+		pro, err := NewDoltDatabaseProviderWithDatabase(env.DefaultInitBranch, dEnv.FS, db, dEnv.FS)
+		require.NoError(t, err)
+		pro = pro.WithRemoteDialer(env.NewGRPCDialProvider())
+		ctxFactory := func(ctx context.Context) (*sql.Context, error) {
+			return NewTestSQLCtxWithProvider(ctx, pro, nil), nil
+		}
+		sqlCtx, err := ctxFactory(ctx)
+		require.NoError(t, err)
+		return gms.NewDefault(pro), sqlCtx, ctxFactory
+	}
+	query := func(engine *gms.Engine, sqlCtx *sql.Context, q string) ([]sql.Row, error) {
+		_, iter, _, err := engine.Query(sqlCtx, q)
+		if err != nil {
+			return nil, err
+		}
+		return sql.RowIterToRows(sqlCtx, iter)
+	}
+
+	serverEnv := dtestutils.CreateTestEnvForLocalFilesystem()
+	defer serverEnv.DoltDB.Close()
+	serverEngine, serverCtx, serverCtxFactory := newEngine(serverEnv)
+
+	checkingHook := branchCheckingHook{ctxFactory: serverCtxFactory, engine: serverEngine, swappedIn: make(map[string]bool)}
+	assertionHook := NewSQLPreReceiveHook(serverCtxFactory, serverEngine, []PreReceiveAssertion{
+		{Name: "non_negative", Query: "select pk from t where v < 0", Message: "v must not be negative"},
+	})
+
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	fs, dbCache, err := RemoteSrvFSAndDBCache(serverCtxFactory, DoNotCreateUnknownDatabases)
+	require.NoError(t, err)
+	srv, err := remotesrv.NewServer(remotesrv.ServerArgs{
+		HttpListenAddr:  addr,
+		GrpcListenAddr:  addr,
+		FS:              fs,
+		DBCache:         dbCache,
+		PreReceiveHooks: []remotesrv.PreReceiveHook{checkingHook, assertionHook},
+	})
+	require.NoError(t, err)
+	listeners, err := srv.Listeners()
+	require.NoError(t, err)
+	go srv.Serve(listeners)
+	defer srv.GracefulStop()
+
+	clientEnv := dtestutils.CreateTestEnvForLocalFilesystem()
+	defer clientEnv.DoltDB.Close()
+	clientEngine, clientCtx, _ := newEngine(clientEnv)
+	for _, q := range []string{
+		fmt.Sprintf("call dolt_remote('add', 'origin', 'http://%s/dolt')", addr),
+		"call dolt_checkout('-b', 'feature')",
+		"create table t (pk int primary key, v int)",
+		"insert into t values (1, 1), (2, -2)",
+		"call dolt_commit('-Am', 'add t', '--author', 'test <test@example.com>')",
+	} {
+		_, err = query(clientEngine, clientCtx, q)
+		require.NoError(t, err, q)
+	}
+
+	// The hook runs against the uploaded commit before the branch is created, and rejecting the push leaves the
+	// server without the branch.
+	_, err = query(clientEngine, clientCtx, "call dolt_push('origin', 'feature')")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "v must not be negative")
+	assert.Equal(t, map[string]bool{"feature": false}, checkingHook.swappedIn)
+	rows, err := query(serverEngine, serverCtx, "select name from dolt_branches")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"main"}}, rows)
+
+	for _, q := range []string{
+		"update t set v = 2 where pk = 2",
+		"call dolt_commit('-am', 'fix v', '--author', 'test <test@example.com>')",
+		"call dolt_push('origin', 'feature')",
+	} {
+		_, err = query(clientEngine, clientCtx, q)
+		require.NoError(t, err, q)
+	}
+	assert.Equal(t, map[string]bool{"feature": false}, checkingHook.swappedIn)
+	rows, err = query(clientEngine, clientCtx, "select hashof('feature')")
+	require.NoError(t, err)
+	pushed := rows[0][0]
+
+	// A rejected update of an existing branch leaves it at its previous head.
+	for _, q := range []string{
+		"insert into t values (3, -3)",
+		"call dolt_commit('-am', 'insert negative v', '--author', 'test <test@example.com>')",
+	} {
+		_, err = query(clientEngine, clientCtx, q)
+		require.NoError(t, err, q)
+	}
+	_, err = query(clientEngine, clientCtx, "call dolt_push('origin', 'feature')")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "v must not be negative")
+	rows, err = query(serverEngine, serverCtx, "select name, hash from dolt_branches order by name")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, sql.Row{"feature", pushed}, rows[0])
+	rows, err = query(serverEngine, serverCtx, "select * from t as of 'feature' order by pk")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int32(1), int32(1)}, {int32(2), int32(2)}}, rows)
+}
@@ -0,0 +1,206 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/rowpolicy"
+)
+
+// rowFilter returns the row-level security filter for the current user on this table, or nil if no policy applies
+// to them. Policies are read from the default branch rather than from the revision of the table, so AS OF queries and
+// other revisions can't be used to read rows from before a policy was created.
+func (t *DoltTable) rowFilter(ctx *sql.Context) (*rowpolicy.Filter, error) {
+	if doltdb.IsSystemTable(t.TableName()) {
+		return nil, nil
+	}
+	table, err := t.DoltTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	branch, err := t.policyBranch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rowpolicy.ForTable(ctx, t.db, branch, t.tableName, table)
+}
+
+// policyBranch returns the branch this table is read from, whose policies apply to it: the branch named by the AS OF
+// clause of the query for tables read as of one, or the branch of the table's database otherwise. Returns an empty
+// string if the table is read from a commit, a tag or a point in time, to which every policy applies.
+func (t *DoltTable) policyBranch(ctx *sql.Context) (string, error) {
+	if t.asOf == nil {
+		return rowpolicy.Branch(t.db), nil
+	}
+	name, ok := t.asOf.(string)
+	if !ok {
+		return "", nil
+	}
+	if name == doltdb.Working || name == doltdb.Staged {
+		return rowpolicy.Branch(t.db), nil
+	}
+	if isBranch, err := actions.IsBranch(ctx, t.db.DbData().Ddb, name); err != nil || !isBranch {
+		return "", err
+	}
+	return name, nil
+}
+
+// selectFilter returns the row-level security filter for the current user on this table if it restricts which rows
+// they can read, or nil otherwise.
+func (t *DoltTable) selectFilter(ctx *sql.Context) (*rowpolicy.Filter, error) {
+	filter, err := t.rowFilter(ctx)
+	if err != nil || filter == nil || !filter.Restricts(rowpolicy.CommandSelect) {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// RestrictedByRowPolicies returns whether a row-level security policy restricts which rows of this table the current
+// user can read. Rows of restricted tables must be read through PartitionRows, which filters them.
+func (t *DoltTable) RestrictedByRowPolicies(ctx *sql.Context) (bool, error) {
+	filter, err := t.selectFilter(ctx)
+	return filter != nil, err
+}
+
+// checkRowPolicySource returns an error if |tblName| is a system table exposing the unfiltered rows of a table whose
+// row-level security policies restrict the rows that the current user can read.
+func (db Database) checkRowPolicySource(ctx *sql.Context, tblName string) error {
+	lwrName := strings.ToLower(tblName)
+	for _, prefix := range unfilteredSourcePrefixes {
+		if strings.HasPrefix(lwrName, prefix) {
+			return rowpolicy.CheckReadable(ctx, db, tblName[len(prefix):], tblName)
+		}
+	}
+	return nil
+}
+
+// newProjectingRowIter returns a RowIter that projects the complete rows of |wrappedIter| to the projected columns of
+// |t|.
+func newProjectingRowIter(t *DoltTable, wrappedIter sql.RowIter) sql.RowIter {
+	allCols := t.sch.GetAllCols()
+	ordinals := make([]int, len(t.projectedCols))
+	for i, tag := range t.projectedCols {
+		ordinals[i] = allCols.TagToIdx[tag]
+	}
+	return &mappingRowIter{
+		child: wrappedIter,
+		rowConvFunc: func(_ *sql.Context, row sql.Row) (sql.Row, error) {
+			projected := make(sql.Row, len(ordinals))
+			for i, ord := range ordinals {
+				projected[i] = row[ord]
+			}
+			return projected, nil
+		},
+	}
+}
+
+// withRowFilter wraps |ed| so that the rows it writes are checked against the row-level security policies of the
+// table, if any apply to the current user.
+func (t *WritableDoltTable) withRowFilter(ctx *sql.Context, ed dsess.TableWriter) (dsess.TableWriter, error) {
+	filter, err := t.rowFilter(ctx)
+	if err != nil || filter == nil {
+		return ed, err
+	}
+	return rowPolicyWriter{TableWriter: ed, filter: filter}, nil
+}
+
+// deleteVisibleRows deletes the rows of the table that the current user is allowed to delete, for a TRUNCATE (or a
+// DELETE converted to one) by a user restricted by |filter|. The rows that the user can't see are left alone.
+func (t *WritableDoltTable) deleteVisibleRows(ctx *sql.Context, filter *rowpolicy.Filter) (int, error) {
+	table, err := t.DoltTable.DoltTable(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rowData, err := table.GetRowData(ctx)
+	if err != nil {
+		return 0, err
+	}
+	iter, err := partitionRows(ctx, table, t.sch.GetAllCols().Tags, index.SinglePartition{RowData: rowData})
+	if err != nil {
+		return 0, err
+	}
+	if filter.Restricts(rowpolicy.CommandSelect) {
+		iter = filter.RowIter(iter)
+	}
+	defer iter.Close(ctx)
+
+	ed, err := t.getTableEditor(ctx)
+	if err != nil {
+		return 0, err
+	}
+	ed = rowPolicyWriter{TableWriter: ed, filter: filter}
+	ed.StatementBegin(ctx)
+
+	deleted := 0
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			ed.DiscardChanges(ctx, err)
+			return 0, err
+		}
+		if err = ed.Delete(ctx, row); err != nil {
+			ed.DiscardChanges(ctx, err)
+			return 0, err
+		}
+		deleted++
+	}
+	if err = ed.StatementComplete(ctx); err != nil {
+		return 0, err
+	}
+	return deleted, ed.Close(ctx)
+}
+
+// rowPolicyWriter is a dsess.TableWriter that rejects writes which the row-level security policies of its table don't
+// allow.
+type rowPolicyWriter struct {
+	dsess.TableWriter
+	filter *rowpolicy.Filter
+}
+
+var _ dsess.TableWriter = rowPolicyWriter{}
+
+// Insert implements sql.RowInserter
+func (w rowPolicyWriter) Insert(ctx *sql.Context, row sql.Row) error {
+	if err := w.filter.CheckInsert(ctx, row); err != nil {
+		return err
+	}
+	return w.TableWriter.Insert(ctx, row)
+}
+
+// Update implements sql.RowUpdater
+func (w rowPolicyWriter) Update(ctx *sql.Context, old, new sql.Row) error {
+	if err := w.filter.CheckUpdate(ctx, old, new); err != nil {
+		return err
+	}
+	return w.TableWriter.Update(ctx, old, new)
+}
+
+// Delete implements sql.RowDeleter
+func (w rowPolicyWriter) Delete(ctx *sql.Context, row sql.Row) error {
+	if err := w.filter.CheckDelete(ctx, row); err != nil {
+		return err
+	}
+	return w.TableWriter.Delete(ctx, row)
+}
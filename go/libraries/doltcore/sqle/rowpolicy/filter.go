@@ -0,0 +1,356 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rowpolicy
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/expranalysis"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// maxCachedPolicies is the number of compiled policies kept in the cache
+const maxCachedPolicies = 1024
+
+// ErrRestrictedAccess is returned when a table restricted by row-level security policies is read through a system
+// table or function that can't filter its rows.
+var ErrRestrictedAccess = errors.NewKind("row-level security policies restrict the rows of table %s, so it cannot be read through %s")
+
+// compiledPolicy is a Policy with its expressions resolved against the schema of its table.
+type compiledPolicy struct {
+	Policy
+	using sql.Expression
+	check sql.Expression
+}
+
+type cacheKey struct {
+	policy Policy
+	schema hash.Hash
+}
+
+// cache holds the policies read from dolt_policies tables, keyed by the hash of the table, and compiled policies, keyed
+// by the policy and the hash of the schema they were resolved against, so that statements don't need to read the
+// policies or parse their expressions again.
+var cache = struct {
+	sync.Mutex
+	tables   map[hash.Hash][]Policy
+	compiled map[cacheKey]compiledPolicy
+}{tables: make(map[hash.Hash][]Policy), compiled: make(map[cacheKey]compiledPolicy)}
+
+// Filter restricts the rows of a table that the current user can read and write, according to the policies that apply
+// to them.
+type Filter struct {
+	table    string
+	policies []compiledPolicy
+}
+
+// ForTable returns the Filter for the rows of |tbl|, the table |tableName| of |db| read from |branch|, that the user of
+// |ctx| can read and write. |branch| is empty if the table is read from a commit, a tag or a point in time rather than
+// a branch. Rows are expected to have every column of |tbl|, in schema order. Returns nil if no policy applies to the
+// user, or if |ctx| is not a SQL session.
+func ForTable(ctx *sql.Context, db dsess.SqlDatabase, branch, tableName string, tbl *doltdb.Table) (*Filter, error) {
+	applicable, err := applicablePolicies(ctx, db, branch, tableName)
+	if err != nil || len(applicable) == 0 {
+		return nil, err
+	}
+	schHash, err := tbl.GetSchemaHash(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]compiledPolicy, len(applicable))
+	var sch schema.Schema
+	for i, p := range applicable {
+		key := cacheKey{policy: p, schema: schHash}
+		cache.Lock()
+		cp, ok := cache.compiled[key]
+		cache.Unlock()
+		if !ok {
+			if sch == nil {
+				if sch, err = tbl.GetSchema(ctx); err != nil {
+					return nil, err
+				}
+			}
+			if cp, err = compile(ctx, p, tableName, sch); err != nil {
+				return nil, err
+			}
+			cache.Lock()
+			if len(cache.compiled) >= maxCachedPolicies {
+				cache.compiled = make(map[cacheKey]compiledPolicy)
+			}
+			cache.compiled[key] = cp
+			cache.Unlock()
+		}
+		policies[i] = cp
+	}
+	return &Filter{table: tableName, policies: policies}, nil
+}
+
+// Restricts returns whether any policy of |db| restricts the statements of kind |command| that the user of |ctx| runs
+// against the table |tableName|.
+func Restricts(ctx *sql.Context, db dsess.SqlDatabase, tableName string, command string) (bool, error) {
+	policies, err := applicablePolicies(ctx, db, Branch(db), tableName)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range policies {
+		if p.appliesTo(command) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CheckReadable returns ErrRestrictedAccess if any policy of |db| restricts which rows of the table |tableName| the
+// user of |ctx| can read. It's used by the system tables and functions named by |source| that read the rows of a table
+// without filtering them, such as its history and diffs.
+func CheckReadable(ctx *sql.Context, db dsess.SqlDatabase, tableName string, source string) error {
+	if restricted, err := Restricts(ctx, db, tableName, CommandSelect); err != nil {
+		return err
+	} else if restricted {
+		return ErrRestrictedAccess.New(tableName, source)
+	}
+	return nil
+}
+
+// Branch returns the branch of |db|, or an empty string if |db| is a commit or a tag rather than a branch.
+func Branch(db dsess.SqlDatabase) string {
+	if db.RevisionType() != dsess.RevisionTypeBranch {
+		return ""
+	}
+	_, branch := dsess.SplitRevisionDbName(db.RevisionQualifiedName())
+	return branch
+}
+
+// applicablePolicies returns the policies of |db| for the table |tableName|, read from |branch|, that apply to the user
+// of |ctx|. Every policy of the table applies when |branch| is empty, whatever its branch pattern, as commits and tags
+// may be reachable from any branch.
+func applicablePolicies(ctx *sql.Context, db dsess.SqlDatabase, branch, tableName string) ([]Policy, error) {
+	bas := branch_control.GetBranchAwareSession(ctx)
+	if bas == nil {
+		return nil, nil
+	}
+	dbName, _ := dsess.SplitRevisionDbName(db.RevisionQualifiedName())
+
+	root, err := dsess.PoliciesRoot(ctx, dbName, db.DbData().Ddb)
+	if err != nil || root == nil {
+		return nil, err
+	}
+	policies, err := storedPolicies(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	var applicable []Policy
+	for _, p := range policies {
+		if strings.EqualFold(p.Table, tableName) &&
+			matches(p.User, bas.GetUser(), sql.Collation_utf8mb4_0900_bin) &&
+			matches(p.Host, bas.GetHost(), sql.Collation_utf8mb4_0900_ai_ci) &&
+			(branch == "" || matches(p.Branch, branch, sql.Collation_utf8mb4_0900_ai_ci)) {
+			applicable = append(applicable, p)
+		}
+	}
+	return applicable, nil
+}
+
+// storedPolicies returns the policies stored in the dolt_policies table of |root|.
+func storedPolicies(ctx *sql.Context, root doltdb.RootValue) ([]Policy, error) {
+	tbl, ok, err := root.GetTable(ctx, doltdb.TableName{Name: doltdb.PoliciesTableName})
+	if err != nil || !ok {
+		return nil, err
+	}
+	h, err := tbl.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Lock()
+	policies, ok := cache.tables[h]
+	cache.Unlock()
+	if ok {
+		return policies, nil
+	}
+	if policies, err = policiesFromTable(ctx, tbl); err != nil {
+		return nil, err
+	}
+	cache.Lock()
+	if len(cache.tables) >= maxCachedPolicies {
+		cache.tables = make(map[hash.Hash][]Policy)
+	}
+	cache.tables[h] = policies
+	cache.Unlock()
+	return policies, nil
+}
+
+// compile resolves the expressions of |p| against |sch|, the schema of the table |tableName|.
+func compile(ctx *sql.Context, p Policy, tableName string, sch schema.Schema) (cp compiledPolicy, err error) {
+	cp.Policy = p
+	if p.Using != "" {
+		if cp.using, err = expranalysis.ResolveRowExpression(ctx, tableName, sch, p.Using); err != nil {
+			return cp, ErrInvalidExpression.New(p.Name, err.Error())
+		}
+	}
+	if p.Check != "" {
+		if cp.check, err = expranalysis.ResolveRowExpression(ctx, tableName, sch, p.Check); err != nil {
+			return cp, ErrInvalidExpression.New(p.Name, err.Error())
+		}
+	}
+	return cp, nil
+}
+
+// matches returns whether |str| matches the branch control style |pattern|.
+func matches(pattern, str string, collation sql.CollationID) bool {
+	expr := branch_control.MatchExpression{
+		SortOrders: branch_control.ParseExpression(branch_control.FoldExpression(pattern), collation),
+	}
+	return len(branch_control.Match([]branch_control.MatchExpression{expr}, str, collation)) > 0
+}
+
+// Restricts returns whether any policy applies to statements of kind |command|. When none do, statements of that kind
+// are not restricted.
+func (f *Filter) Restricts(command string) bool {
+	for _, p := range f.policies {
+		if p.appliesTo(command) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanSelect returns whether |row| can be read.
+func (f *Filter) CanSelect(ctx *sql.Context, row sql.Row) (bool, error) {
+	return f.allowsExisting(ctx, CommandSelect, row)
+}
+
+// CheckInsert returns an error if |row| cannot be inserted.
+func (f *Filter) CheckInsert(ctx *sql.Context, row sql.Row) error {
+	return f.checkNew(ctx, CommandInsert, row)
+}
+
+// CheckUpdate returns an error if |old| cannot be updated, or cannot be updated to |new|.
+func (f *Filter) CheckUpdate(ctx *sql.Context, old, new sql.Row) error {
+	if ok, err := f.allowsExisting(ctx, CommandUpdate, old); err != nil {
+		return err
+	} else if !ok {
+		return ErrExistingRowViolation.New(f.table, "updated")
+	}
+	return f.checkNew(ctx, CommandUpdate, new)
+}
+
+// CheckDelete returns an error if |row| cannot be deleted.
+func (f *Filter) CheckDelete(ctx *sql.Context, row sql.Row) error {
+	if ok, err := f.allowsExisting(ctx, CommandDelete, row); err != nil {
+		return err
+	} else if !ok {
+		return ErrExistingRowViolation.New(f.table, "deleted")
+	}
+	return nil
+}
+
+// allowsExisting returns whether any policy for |command| allows the existing row |row|, or true if there are no
+// policies for |command|.
+func (f *Filter) allowsExisting(ctx *sql.Context, command string, row sql.Row) (bool, error) {
+	restricted := false
+	for _, p := range f.policies {
+		if !p.appliesTo(command) {
+			continue
+		}
+		restricted = true
+		if p.using == nil {
+			return true, nil
+		}
+		if ok, err := eval(ctx, p.using, row); err != nil || ok {
+			return ok, err
+		}
+	}
+	return !restricted, nil
+}
+
+// checkNew returns an error unless any policy for |command| allows the new row |row|, or there are no policies for
+// |command|. Policies without a CHECK expression use their USING expression instead.
+func (f *Filter) checkNew(ctx *sql.Context, command string, row sql.Row) error {
+	restricted := false
+	for _, p := range f.policies {
+		if !p.appliesTo(command) {
+			continue
+		}
+		restricted = true
+		expr := p.check
+		if expr == nil {
+			expr = p.using
+		}
+		if expr == nil {
+			return nil
+		}
+		if ok, err := eval(ctx, expr, row); err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+	}
+	if restricted {
+		return ErrNewRowViolation.New(f.table)
+	}
+	return nil
+}
+
+// eval evaluates |expr| against |row|. Unlike check constraints, a NULL result doesn't satisfy a policy.
+func eval(ctx *sql.Context, expr sql.Expression, row sql.Row) (bool, error) {
+	res, err := expr.Eval(ctx, row)
+	if err != nil || res == nil {
+		return false, err
+	}
+	return sql.ConvertToBool(ctx, res)
+}
+
+// RowIter returns an iterator over the rows of |iter| that can be read. The rows of |iter| must be complete.
+func (f *Filter) RowIter(iter sql.RowIter) sql.RowIter {
+	return &filterIter{filter: f, child: iter}
+}
+
+type filterIter struct {
+	filter *Filter
+	child  sql.RowIter
+}
+
+var _ sql.RowIter = (*filterIter)(nil)
+
+// Next implements sql.RowIter
+func (i *filterIter) Next(ctx *sql.Context) (sql.Row, error) {
+	for {
+		row, err := i.child.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := i.filter.CanSelect(ctx, row); err != nil {
+			return nil, err
+		} else if ok {
+			return row, nil
+		}
+	}
+}
+
+// Close implements sql.RowIter
+func (i *filterIter) Close(ctx *sql.Context) error {
+	return i.child.Close(ctx)
+}
@@ -0,0 +1,353 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rowpolicy implements row-level security policies. A policy restricts the rows of a table that matching
+// users can read and write on matching branches, using boolean expressions over the columns of the table. Policies are
+// stored in the versioned dolt_policies system table, so they are diffed, merged and pushed along with the tables they
+// protect. They take effect once they are in the working set of the default branch of the database, and are read from
+// there whichever revision is queried, so that they also apply to branches and commits made before they were created.
+//
+// Policies are permissive: a row can be read if any policy for the user allows it. A user that no policy applies to
+// is not restricted at all. As a policy applies to every branch, only users that administer every branch of a database
+// may change its policies, on any branch.
+package rowpolicy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/vt/proto/query"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/expranalysis"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var ErrPolicyExists = errors.NewKind("policy %s already exists")
+var ErrPolicyNotFound = errors.NewKind("policy %s does not exist")
+var ErrInvalidCommand = errors.NewKind("invalid policy command '%s', expected one of all, select, insert, update or delete")
+var ErrMissingExpression = errors.NewKind("policy %s must have a USING or a CHECK expression")
+var ErrTableNotFound = errors.NewKind("table %s does not exist")
+var ErrSystemTable = errors.NewKind("policies cannot be defined on system table %s")
+var ErrInvalidExpression = errors.NewKind("invalid expression for policy %s: %s")
+var ErrOldFormat = errors.NewKind("row-level security policies are not supported in the old storage format")
+
+// ErrNewRowViolation is returned when an inserted or updated row doesn't satisfy the policies of its table.
+var ErrNewRowViolation = errors.NewKind("new row violates row-level security policy for table %s")
+
+// ErrExistingRowViolation is returned when a row that the policies of its table don't allow to be updated or deleted
+// is updated or deleted.
+var ErrExistingRowViolation = errors.NewKind("row-level security policy for table %s does not allow this row to be %s")
+
+// Commands that a policy can apply to
+const (
+	CommandAll    = "all"
+	CommandSelect = "select"
+	CommandInsert = "insert"
+	CommandUpdate = "update"
+	CommandDelete = "delete"
+)
+
+// Policy is a row of the dolt_policies table.
+type Policy struct {
+	Name  string
+	Table string
+	// Command is the kind of statement the policy applies to, one of the Command constants
+	Command string
+	// User, Host and Branch are patterns, with the same syntax as those of dolt_branch_control, that select the users
+	// and branches the policy applies to
+	User   string
+	Host   string
+	Branch string
+	// Using is the expression that existing rows must satisfy to be read, updated or deleted. Empty if the policy
+	// doesn't restrict existing rows.
+	Using string
+	// Check is the expression that inserted and updated rows must satisfy. Empty if the policy uses its Using
+	// expression for new rows as well.
+	Check string
+}
+
+var policiesSchema = mustSchema(
+	mustCol(doltdb.PoliciesNameCol, schema.DoltPoliciesNameTag, varchar(64, sql.Collation_utf8mb4_0900_ai_ci), true, false),
+	mustCol(doltdb.PoliciesTableNameCol, schema.DoltPoliciesTableNameTag, varchar(64, sql.Collation_utf8mb4_0900_ai_ci), false, false),
+	mustCol(doltdb.PoliciesCommandCol, schema.DoltPoliciesCommandTag, varchar(16, sql.Collation_utf8mb4_0900_ai_ci), false, false),
+	mustCol(doltdb.PoliciesUserCol, schema.DoltPoliciesUserTag, varchar(32, sql.Collation_utf8mb4_0900_bin), false, false),
+	mustCol(doltdb.PoliciesHostCol, schema.DoltPoliciesHostTag, varchar(255, sql.Collation_utf8mb4_0900_ai_ci), false, false),
+	mustCol(doltdb.PoliciesBranchCol, schema.DoltPoliciesBranchTag, varchar(767, sql.Collation_utf8mb4_0900_ai_ci), false, false),
+	mustCol(doltdb.PoliciesUsingCol, schema.DoltPoliciesUsingTag, typeinfo.CreateVarStringTypeFromSqlType(gmstypes.LongText), false, true),
+	mustCol(doltdb.PoliciesCheckCol, schema.DoltPoliciesCheckTag, typeinfo.CreateVarStringTypeFromSqlType(gmstypes.LongText), false, true),
+)
+
+// PoliciesSchema returns the schema of the dolt_policies table.
+func PoliciesSchema() schema.Schema {
+	return policiesSchema
+}
+
+func varchar(length int64, collation sql.CollationID) typeinfo.TypeInfo {
+	return typeinfo.CreateVarStringTypeFromSqlType(gmstypes.MustCreateString(query.Type_VARCHAR, length, collation))
+}
+
+func mustCol(name string, tag uint64, ti typeinfo.TypeInfo, partOfPK, nullable bool) schema.Column {
+	var constraints []schema.ColConstraint
+	if !nullable {
+		constraints = append(constraints, schema.NotNullConstraint{})
+	}
+	col, err := schema.NewColumnWithTypeInfo(name, tag, ti, partOfPK, "", false, "", constraints...)
+	if err != nil {
+		panic(err)
+	}
+	return col
+}
+
+func mustSchema(cols ...schema.Column) schema.Schema {
+	return schema.MustSchemaFromCols(schema.NewColCollection(cols...))
+}
+
+// ListPolicies returns the policies defined in |root|.
+func ListPolicies(ctx *sql.Context, root doltdb.RootValue) ([]Policy, error) {
+	tbl, ok, err := root.GetTable(ctx, doltdb.TableName{Name: doltdb.PoliciesTableName})
+	if err != nil || !ok {
+		return nil, err
+	}
+	return policiesFromTable(ctx, tbl)
+}
+
+func policiesFromTable(ctx *sql.Context, tbl *doltdb.Table) ([]Policy, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Format() != types.Format_DOLT {
+		return nil, ErrOldFormat.New()
+	}
+	m := durable.ProllyMapFromIndex(rows)
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []Policy
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		row, err := index.BuildRow(ctx, k, v, sch, m.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policyFromRow(row))
+	}
+	return policies, nil
+}
+
+func policyFromRow(row sql.Row) Policy {
+	p := Policy{
+		Name:    row[0].(string),
+		Table:   row[1].(string),
+		Command: strings.ToLower(row[2].(string)),
+		User:    row[3].(string),
+		Host:    row[4].(string),
+		Branch:  row[5].(string),
+	}
+	if row[6] != nil {
+		p.Using = row[6].(string)
+	}
+	if row[7] != nil {
+		p.Check = row[7].(string)
+	}
+	return p
+}
+
+func (p Policy) toRow() sql.Row {
+	row := sql.Row{p.Name, p.Table, p.Command, p.User, p.Host, p.Branch, nil, nil}
+	if p.Using != "" {
+		row[6] = p.Using
+	}
+	if p.Check != "" {
+		row[7] = p.Check
+	}
+	return row
+}
+
+// appliesTo returns whether the policy restricts statements of kind |command|.
+func (p Policy) appliesTo(command string) bool {
+	return p.Command == CommandAll || p.Command == command
+}
+
+// CreatePolicy adds |p| to the policies in the working set of |dbName|, validating it against that working set. Empty
+// patterns of |p| match everything, and an empty command makes the policy apply to all statements.
+func CreatePolicy(ctx *sql.Context, dbName string, p Policy) error {
+	if err := dsess.CheckPolicyAdminAccess(ctx, dbName); err != nil {
+		return err
+	}
+	p, err := validatePolicy(ctx, dbName, p)
+	if err != nil {
+		return err
+	}
+	dSess := dsess.DSessFromSess(ctx.Session)
+	roots, _ := dSess.GetRoots(ctx, dbName)
+	root := roots.Working
+
+	policies, err := ListPolicies(ctx, root)
+	if err != nil {
+		return err
+	}
+	for _, existing := range policies {
+		if strings.EqualFold(existing.Name, p.Name) {
+			return ErrPolicyExists.New(p.Name)
+		}
+	}
+	if len(policies) == 0 {
+		if _, ok, err := root.GetTable(ctx, doltdb.TableName{Name: doltdb.PoliciesTableName}); err != nil {
+			return err
+		} else if !ok {
+			root, err = doltdb.CreateEmptyTable(ctx, root, doltdb.TableName{Name: doltdb.PoliciesTableName}, policiesSchema)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	ed, err := writer.NewRootEditor(ctx, dbName, root)
+	if err != nil {
+		return err
+	}
+	if err = ed.Insert(ctx, doltdb.PoliciesTableName, p.toRow()); err != nil {
+		return err
+	}
+	if root, err = ed.Finish(ctx); err != nil {
+		return err
+	}
+	return dSess.SetWorkingRoot(ctx, dbName, root)
+}
+
+// DropPolicy removes the policy |name| from the working set of |dbName|.
+func DropPolicy(ctx *sql.Context, dbName, name string) error {
+	if err := dsess.CheckPolicyAdminAccess(ctx, dbName); err != nil {
+		return err
+	}
+	dSess := dsess.DSessFromSess(ctx.Session)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	root := roots.Working
+
+	policies, err := ListPolicies(ctx, root)
+	if err != nil {
+		return err
+	}
+	var policy Policy
+	for _, p := range policies {
+		if strings.EqualFold(p.Name, name) {
+			policy = p
+		}
+	}
+	if policy.Name == "" {
+		return ErrPolicyNotFound.New(name)
+	}
+
+	if len(policies) == 1 {
+		// this is the last policy, so remove the system table as well
+		root, err = root.RemoveTables(ctx, false, false, doltdb.TableName{Name: doltdb.PoliciesTableName})
+		if err != nil {
+			return err
+		}
+	} else {
+		ed, err := writer.NewRootEditor(ctx, dbName, root)
+		if err != nil {
+			return err
+		}
+		if err = ed.Delete(ctx, doltdb.PoliciesTableName, policy.toRow()); err != nil {
+			return err
+		}
+		if root, err = ed.Finish(ctx); err != nil {
+			return err
+		}
+	}
+	return dSess.SetWorkingRoot(ctx, dbName, root)
+}
+
+// validatePolicy returns |p| with its defaults filled in, or an error if it is not a valid policy for the working set
+// of |dbName|.
+func validatePolicy(ctx *sql.Context, dbName string, p Policy) (Policy, error) {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return p, fmt.Errorf("Could not load database %s", dbName)
+	}
+	root := roots.Working
+	if !types.IsFormat_DOLT(root.VRW().Format()) {
+		return p, ErrOldFormat.New()
+	}
+
+	p.Command = strings.ToLower(p.Command)
+	switch p.Command {
+	case "":
+		p.Command = CommandAll
+	case CommandAll, CommandSelect, CommandInsert, CommandUpdate, CommandDelete:
+	default:
+		return p, ErrInvalidCommand.New(p.Command)
+	}
+	for _, pattern := range []*string{&p.User, &p.Host, &p.Branch} {
+		if *pattern == "" {
+			*pattern = "%"
+		}
+	}
+	if p.Using == "" && p.Check == "" {
+		return p, ErrMissingExpression.New(p.Name)
+	}
+
+	if doltdb.IsSystemTable(doltdb.TableName{Name: p.Table}) {
+		return p, ErrSystemTable.New(p.Table)
+	}
+	tbl, tName, ok, err := doltdb.GetTableInsensitive(ctx, root, doltdb.TableName{Name: p.Table})
+	if err != nil {
+		return p, err
+	} else if !ok {
+		return p, ErrTableNotFound.New(p.Table)
+	}
+	p.Table = tName
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return p, err
+	}
+	// make sure the expressions are valid before storing them, rather than when the table is next read
+	for _, expr := range []string{p.Using, p.Check} {
+		if expr == "" {
+			continue
+		}
+		if _, err = expranalysis.ResolveRowExpression(ctx, p.Table, sch, expr); err != nil {
+			return p, ErrInvalidExpression.New(p.Name, err.Error())
+		}
+	}
+	return p, nil
+}
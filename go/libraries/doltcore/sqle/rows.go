@@ -285,3 +285,47 @@ func DoltTablePartitionToRowIter(ctx *sql.Context, name string, table *doltdb.Ta
 
 	return pkSch.Schema, rowIter, nil
 }
+
+// Status variables that count the rows read from tables, as MySQL's handlers do.
+const (
+	// HandlerReadRndNext counts the rows read by table scans
+	HandlerReadRndNext = "Handler_read_rnd_next"
+	// HandlerReadNext counts the rows read by index lookups
+	HandlerReadNext = "Handler_read_next"
+)
+
+// countingRowIter is a sql.RowIter that adds the number of rows read from the iterator it wraps to a status variable
+// when it's closed, which is how the rows examined by a statement are counted.
+type countingRowIter struct {
+	sql.RowIter
+	statusVar string
+	count     int
+}
+
+var _ sql.RowIter = (*countingRowIter)(nil)
+
+func newCountingRowIter(iter sql.RowIter, statusVar string) sql.RowIter {
+	return &countingRowIter{RowIter: iter, statusVar: statusVar}
+}
+
+func (i *countingRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	row, err := i.RowIter.Next(ctx)
+	if err == nil {
+		i.count++
+	}
+	return row, err
+}
+
+func (i *countingRowIter) Close(ctx *sql.Context) error {
+	CountRowsRead(ctx, i.statusVar, i.count)
+	i.count = 0
+	return i.RowIter.Close(ctx)
+}
+
+// CountRowsRead adds |n| rows read from tables to the status variable |statusVar|, for iterators that read the storage
+// of tables directly rather than through their row iterators.
+func CountRowsRead(ctx *sql.Context, statusVar string, n int) {
+	if n > 0 && ctx.Session != nil && sql.StatusVariables != nil {
+		sql.IncrementStatusVariable(ctx, statusVar, n)
+	}
+}
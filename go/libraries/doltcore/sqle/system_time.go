@@ -0,0 +1,81 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	ast "github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtablefunctions"
+)
+
+// RewriteSystemTime replaces every table reference in |stmt| that has a FOR SYSTEM_TIME range with a derived table
+// selecting from dolt_system_time, aliased to the table's name (or its own alias) so that the rest of the statement
+// still resolves. The engine only understands the AS OF form, which Dolt already implements, and would otherwise
+// ignore the others, so FOR SYSTEM_TIME AS OF is left alone.
+func RewriteSystemTime(stmt ast.Statement) error {
+	if stmt == nil {
+		return nil
+	}
+	return ast.Walk(func(node ast.SQLNode) (bool, error) {
+		ate, ok := node.(*ast.AliasedTableExpr)
+		if !ok || ate.AsOf == nil || ate.AsOf.Time != nil {
+			return true, nil
+		}
+		tn, ok := ate.Expr.(ast.TableName)
+		if !ok {
+			return true, nil
+		}
+
+		asOf := ate.AsOf
+		var mode string
+		switch {
+		case asOf.All:
+			mode = dtablefunctions.SystemTimeAll
+		case asOf.StartInclusive && asOf.EndInclusive:
+			mode = dtablefunctions.SystemTimeContainedIn
+		case asOf.EndInclusive:
+			mode = dtablefunctions.SystemTimeBetween
+		default:
+			mode = dtablefunctions.SystemTimeFromTo
+		}
+
+		start, end := asOf.Start, asOf.End
+		if asOf.All {
+			start, end = &ast.NullVal{}, &ast.NullVal{}
+		}
+		args := ast.SelectExprs{
+			&ast.AliasedExpr{Expr: ast.NewStrVal([]byte(tn.Name.String()))},
+			&ast.AliasedExpr{Expr: ast.NewStrVal([]byte(mode))},
+			&ast.AliasedExpr{Expr: start},
+			&ast.AliasedExpr{Expr: end},
+		}
+		if !tn.DbQualifier.IsEmpty() {
+			args = append(args, &ast.AliasedExpr{Expr: ast.NewStrVal([]byte(tn.DbQualifier.String()))})
+		}
+
+		ate.Expr = &ast.Subquery{Select: &ast.Select{
+			SelectExprs: ast.SelectExprs{&ast.StarExpr{}},
+			From: ast.TableExprs{&ast.TableFuncExpr{
+				Name:  dtablefunctions.SystemTimeTableFunctionName,
+				Exprs: args,
+			}},
+		}}
+		if ate.As.IsEmpty() {
+			ate.As = tn.Name
+		}
+		ate.AsOf = nil
+		return true, nil
+	}, stmt)
+}
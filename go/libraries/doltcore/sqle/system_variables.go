@@ -21,6 +21,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/types"
 	_ "github.com/dolthub/go-mysql-server/sql/variables"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/signing"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
 
@@ -204,6 +205,13 @@ var DoltSystemVariables = []sql.SystemVariable{
 		Type:    types.NewSystemBoolType(dsess.ShowSystemTables),
 		Default: int8(0),
 	},
+	&sql.MysqlSystemVariable{
+		Name:    dsess.MaskColumns,
+		Dynamic: true,
+		Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Both),
+		Type:    types.NewSystemBoolType(dsess.MaskColumns),
+		Default: int8(0),
+	},
 	&sql.MysqlSystemVariable{
 		Name:    "dolt_dont_merge_json",
 		Dynamic: true,
@@ -211,6 +219,13 @@ var DoltSystemVariables = []sql.SystemVariable{
 		Type:    types.NewSystemBoolType("dolt_dont_merge_json"),
 		Default: int8(0),
 	},
+	&sql.MysqlSystemVariable{
+		Name:    "dolt_json_array_merge",
+		Dynamic: true,
+		Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Both),
+		Type:    types.NewSystemStringType("dolt_json_array_merge"),
+		Default: "conflict",
+	},
 	&sql.MysqlSystemVariable{
 		Name:    dsess.DoltStatsAutoRefreshEnabled,
 		Dynamic: true,
@@ -431,6 +446,13 @@ func AddDoltSystemVariables() {
 			Type:    types.NewSystemBoolType(dsess.ShowSystemTables),
 			Default: int8(0),
 		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.MaskColumns,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Both),
+			Type:    types.NewSystemBoolType(dsess.MaskColumns),
+			Default: int8(0),
+		},
 		&sql.MysqlSystemVariable{
 			Name:    "dolt_dont_merge_json",
 			Dynamic: true,
@@ -438,6 +460,13 @@ func AddDoltSystemVariables() {
 			Type:    types.NewSystemBoolType("dolt_dont_merge_json"),
 			Default: int8(0),
 		},
+		&sql.MysqlSystemVariable{
+			Name:    "dolt_json_array_merge",
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Both),
+			Type:    types.NewSystemStringType("dolt_json_array_merge"),
+			Default: "conflict",
+		},
 		&sql.MysqlSystemVariable{
 			Name:    dsess.DoltStatsAutoRefreshEnabled,
 			Dynamic: true,
@@ -481,19 +510,33 @@ func AddDoltSystemVariables() {
 			Default: "",
 		},
 		&sql.MysqlSystemVariable{
-			Name:    "signingkey",
+			Name:    dsess.SigningKey,
 			Dynamic: true,
 			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_PersistOnly),
-			Type:    types.NewSystemStringType("signingkey"),
+			Type:    types.NewSystemStringType(dsess.SigningKey),
 			Default: "",
 		},
 		&sql.MysqlSystemVariable{
-			Name:    "gpgsign",
+			Name:    dsess.GPGSign,
 			Dynamic: true,
 			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_PersistOnly),
-			Type:    types.NewSystemBoolType("gpgsign"),
+			Type:    types.NewSystemBoolType(dsess.GPGSign),
 			Default: int8(0),
 		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.GPGFormat,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_PersistOnly),
+			Type:    types.NewSystemEnumType(dsess.GPGFormat, signing.FormatOpenPGP, signing.FormatSSH),
+			Default: signing.FormatOpenPGP,
+		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.AllowedSignersFile,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_PersistOnly),
+			Type:    types.NewSystemStringType(dsess.AllowedSignersFile),
+			Default: "",
+		},
 	})
 	sql.SystemVariables.AddSystemVariables(DoltSystemVariables)
 }
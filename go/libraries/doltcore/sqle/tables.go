@@ -42,6 +42,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/rowpolicy"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
@@ -96,6 +97,9 @@ type DoltTable struct {
 
 	// overriddenSchema is set when the @@dolt_override_schema system var is in use
 	overriddenSchema schema.Schema
+
+	// asOf is the revision that an AS OF query reads this table from, or nil if it is read from the revision of db
+	asOf interface{}
 }
 
 func (t *DoltTable) TableName() doltdb.TableName {
@@ -111,6 +115,10 @@ func (t *DoltTable) SkipIndexCosting() bool {
 }
 
 func (t *DoltTable) LookupForExpressions(ctx *sql.Context, exprs ...sql.Expression) (sql.IndexLookup, *sql.FuncDepSet, sql.Expression, bool, error) {
+	if restricted, err := t.ReadsRestricted(ctx); err != nil || restricted {
+		return sql.IndexLookup{}, nil, nil, false, err
+	}
+
 	root, err := t.workingRoot(ctx)
 	if err != nil {
 		return sql.IndexLookup{}, nil, nil, false, err
@@ -382,6 +390,10 @@ func (t *DoltTable) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
 	if t.overriddenSchema != nil {
 		return nil, nil
 	}
+	// Similarly, index lookups would bypass the row-level security filter and column masks applied in PartitionRows.
+	if restricted, err := t.ReadsRestricted(ctx); err != nil || restricted {
+		return nil, err
+	}
 
 	key, tableIsCacheable, err := t.IndexCacheKey(ctx)
 	if err != nil {
@@ -583,7 +595,12 @@ func (t *DoltTable) DataLength(ctx *sql.Context) (uint64, error) {
 // RowCount implements the sql.StatisticsTable interface.
 func (t *DoltTable) RowCount(ctx *sql.Context) (uint64, bool, error) {
 	rows, err := t.numRows(ctx)
-	return rows, true, err
+	if err != nil {
+		return 0, false, err
+	}
+	// the count includes rows that a row-level security policy may hide from the current user
+	restricted, err := t.RestrictedByRowPolicies(ctx)
+	return rows, !restricted, err
 }
 
 func (t *DoltTable) PrimaryKeySchema() sql.PrimaryKeySchema {
@@ -614,8 +631,19 @@ func (t *DoltTable) PartitionRows(ctx *sql.Context, partition sql.Partition) (sq
 	// to pass in the full column projection for the original/data schema so that we get all columns back. Then,
 	// the mappingRowIterator that we apply on top of the original row iterator will take care of mapping the
 	// original row and shrinking it down to the projected columns.
+	// The same goes for row-level security policies, whose expressions are evaluated against complete rows, and for
+	// column masks.
+	filter, err := t.selectFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	masker, err := t.columnMasker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	projCols := t.projectedCols
-	if t.overriddenSchema != nil {
+	if t.overriddenSchema != nil || filter != nil || masker != nil {
 		originalSchemaCols := t.sch.GetAllCols().GetColumns()
 		projCols = make([]uint64, len(originalSchemaCols))
 		for i, col := range originalSchemaCols {
@@ -627,9 +655,18 @@ func (t *DoltTable) PartitionRows(ctx *sql.Context, partition sql.Partition) (sq
 	if err != nil {
 		return originalRowIter, err
 	}
+	originalRowIter = newCountingRowIter(originalRowIter, HandlerReadRndNext)
+	if filter != nil {
+		originalRowIter = filter.RowIter(originalRowIter)
+	}
+	if masker != nil {
+		originalRowIter = masker.RowIter(originalRowIter)
+	}
 
 	if t.overriddenSchema != nil {
 		return newMappingRowIter(ctx, t, originalRowIter)
+	} else if (filter != nil || masker != nil) && t.projectedCols != nil {
+		return newProjectingRowIter(t, originalRowIter), nil
 	} else {
 		return originalRowIter, err
 	}
@@ -697,16 +734,33 @@ func (t *WritableDoltTable) WithProjections(colNames []string) sql.Table {
 
 // Inserter implements sql.InsertableTable
 func (t *WritableDoltTable) Inserter(ctx *sql.Context) sql.RowInserter {
-	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
+	if err := t.checkWriteAccess(ctx); err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
 	te, err := t.getTableEditor(ctx)
 	if err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
+	te, err = t.withRowFilter(ctx, te)
+	if err != nil {
+		return sqlutil.NewStaticErrorEditor(err)
+	}
 	return te
 }
 
+// checkWriteAccess returns an error if the current user may not write to this table. Row-level security policies apply
+// to every branch, so only those who administer every branch may change them. Column masks restrict every other user of
+// a branch, so only those who administer it may change them.
+func (t *WritableDoltTable) checkWriteAccess(ctx *sql.Context) error {
+	if strings.EqualFold(t.tableName, doltdb.PoliciesTableName) {
+		return dsess.CheckPolicyAdminAccess(ctx, t.db.Name())
+	}
+	if strings.EqualFold(t.tableName, doltdb.ColumnMasksTableName) {
+		return dsess.CheckAdminAccessForDb(ctx, t.db)
+	}
+	return dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write)
+}
+
 func (t *WritableDoltTable) getTableEditor(ctx *sql.Context) (ed dsess.TableWriter, err error) {
 	ds := dsess.DSessFromSess(ctx.Session)
 
@@ -957,32 +1011,48 @@ func emptyFulltextTable(
 
 // Deleter implements sql.DeletableTable
 func (t *WritableDoltTable) Deleter(ctx *sql.Context) sql.RowDeleter {
-	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
+	if err := t.checkWriteAccess(ctx); err != nil {
+		return sqlutil.NewStaticErrorEditor(err)
+	}
+	if err := t.checkMaskedWrite(ctx); err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
 	te, err := t.getTableEditor(ctx)
 	if err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
+	te, err = t.withRowFilter(ctx, te)
+	if err != nil {
+		return sqlutil.NewStaticErrorEditor(err)
+	}
 	return te
 }
 
 // Replacer implements sql.ReplaceableTable
 func (t *WritableDoltTable) Replacer(ctx *sql.Context) sql.RowReplacer {
-	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
+	if err := t.checkWriteAccess(ctx); err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
 	te, err := t.getTableEditor(ctx)
 	if err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
+	te, err = t.withRowFilter(ctx, te)
+	if err != nil {
+		return sqlutil.NewStaticErrorEditor(err)
+	}
 	return te
 }
 
 // Truncate implements sql.TruncateableTable
 func (t *WritableDoltTable) Truncate(ctx *sql.Context) (int, error) {
-	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
+	if err := t.checkWriteAccess(ctx); err != nil {
+		return 0, err
+	}
+	if filter, err := t.rowFilter(ctx); err != nil {
 		return 0, err
+	} else if filter != nil && (filter.Restricts(rowpolicy.CommandSelect) || filter.Restricts(rowpolicy.CommandDelete)) {
+		return t.deleteVisibleRows(ctx, filter)
 	}
 	table, err := t.DoltTable.DoltTable(ctx)
 	if err != nil {
@@ -1123,13 +1193,20 @@ func copyConstraintViolationsAndConflicts(ctx context.Context, from, to *doltdb.
 
 // Updater implements sql.UpdatableTable
 func (t *WritableDoltTable) Updater(ctx *sql.Context) sql.RowUpdater {
-	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
+	if err := t.checkWriteAccess(ctx); err != nil {
+		return sqlutil.NewStaticErrorEditor(err)
+	}
+	if err := t.checkMaskedWrite(ctx); err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
 	te, err := t.getTableEditor(ctx)
 	if err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
+	te, err = t.withRowFilter(ctx, te)
+	if err != nil {
+		return sqlutil.NewStaticErrorEditor(err)
+	}
 	return te
 }
 
@@ -2085,6 +2162,7 @@ func modifyIndexesForTableRewrite(ctx *sql.Context, oldSch schema.Schema, oldCol
 				IsUnique:           index.IsUnique(),
 				IsSpatial:          index.IsSpatial(),
 				IsFullText:         index.IsFullText(),
+				IsVector:           index.IsVector(),
 				IsUserDefined:      index.IsUserDefined(),
 				Comment:            index.Comment(),
 				FullTextProperties: index.FullTextProperties(),
@@ -2459,8 +2537,20 @@ func (t *AlterableDoltTable) CreateIndex(ctx *sql.Context, idx sql.IndexDef) err
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
-	if idx.Constraint != sql.IndexConstraint_None && idx.Constraint != sql.IndexConstraint_Unique && idx.Constraint != sql.IndexConstraint_Spatial {
-		return fmt.Errorf("only the following types of index constraints are supported: none, unique, spatial")
+	switch idx.Constraint {
+	case sql.IndexConstraint_None, sql.IndexConstraint_Unique, sql.IndexConstraint_Spatial:
+	case sql.IndexConstraint_Vector:
+		if !types.IsFormat_DOLT(t.Format()) {
+			return fmt.Errorf("VECTOR indexes are not supported on storage format %s. Run `dolt migrate` to upgrade to the latest storage format.", t.Format().VersionString())
+		}
+		if len(idx.Columns) != 1 {
+			return fmt.Errorf("vector indexes must have exactly one column")
+		}
+		if col, ok := t.sch.GetAllCols().GetByNameCaseInsensitive(idx.Columns[0].Name); ok && !sqltypes.IsJSON(col.TypeInfo.ToSqlType()) {
+			return fmt.Errorf("vector index column '%s' must be of type JSON", col.Name)
+		}
+	default:
+		return fmt.Errorf("only the following types of index constraints are supported: none, unique, spatial, vector")
 	}
 
 	return t.createIndex(ctx, idx, fulltext.KeyColumns{}, fulltext.IndexTableNames{})
@@ -2574,6 +2664,7 @@ func (t *AlterableDoltTable) createIndex(ctx *sql.Context, idx sql.IndexDef, key
 		IsUnique:      idx.Constraint == sql.IndexConstraint_Unique,
 		IsSpatial:     idx.Constraint == sql.IndexConstraint_Spatial,
 		IsFullText:    idx.Constraint == sql.IndexConstraint_Fulltext,
+		IsVector:      idx.Constraint == sql.IndexConstraint_Vector,
 		IsUserDefined: true,
 		Comment:       idx.Comment,
 		FullTextProperties: schema.FullTextProperties{
@@ -82,7 +82,7 @@ func ExecuteSql(dEnv *env.DoltEnv, root doltdb.RootValue, statements string) (do
 			return nil, errors.New("Show statements aren't handled")
 		case *sqlparser.Select, *sqlparser.OtherRead:
 			return nil, errors.New("Select statements aren't handled")
-		case *sqlparser.Insert:
+		case *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete:
 			var rowIter sql.RowIter
 			_, rowIter, _, execErr = engine.Query(ctx, query)
 			if execErr == nil {
@@ -133,6 +133,7 @@ func NewTestEngine(dEnv *env.DoltEnv, ctx context.Context, db dsess.SqlDatabase)
 	}
 
 	engine := sqle.NewDefault(pro)
+	engine.Parser = NewParser(engine.Parser)
 
 	sqlCtx := NewTestSQLCtxWithProvider(ctx, pro, nil)
 	sqlCtx.SetCurrentDatabase(db.Name())
@@ -0,0 +1,106 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+// RootEditor edits the tables of a root value outside of the session's working set. It is used to maintain system
+// tables on behalf of stored procedures, where the edited root is only set as the working root once every change has
+// been made.
+type RootEditor struct {
+	dbName  string
+	session dsess.WriteSession
+	writers map[string]dsess.TableWriter
+}
+
+// NewRootEditor returns a RootEditor for |root|, which belongs to the database |dbName|.
+func NewRootEditor(ctx *sql.Context, dbName string, root doltdb.RootValue) (*RootEditor, error) {
+	ws, err := dsess.DSessFromSess(ctx.Session).WorkingSet(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	ws = ws.WithWorkingRoot(root)
+	ait, err := dsess.NewAutoIncrementTracker(ctx, dbName, ws)
+	if err != nil {
+		return nil, err
+	}
+	return &RootEditor{
+		dbName:  dbName,
+		session: NewWriteSession(root.VRW().Format(), ws, ait, editor.Options{}),
+		writers: make(map[string]dsess.TableWriter),
+	}, nil
+}
+
+func (e *RootEditor) writer(ctx *sql.Context, table string) (dsess.TableWriter, error) {
+	if w, ok := e.writers[table]; ok {
+		return w, nil
+	}
+	// the root is read back from the session in Finish, so there is nothing for the setter to do
+	setter := func(*sql.Context, string, doltdb.RootValue) error { return nil }
+	w, err := e.session.GetTableWriter(ctx, doltdb.TableName{Name: table}, e.dbName, setter, false)
+	if err != nil {
+		return nil, err
+	}
+	w.StatementBegin(ctx)
+	e.writers[table] = w
+	return w, nil
+}
+
+// Insert inserts |row| into |table|.
+func (e *RootEditor) Insert(ctx *sql.Context, table string, row sql.Row) error {
+	w, err := e.writer(ctx, table)
+	if err != nil {
+		return err
+	}
+	return w.Insert(ctx, row)
+}
+
+// Update replaces |old| with |new| in |table|.
+func (e *RootEditor) Update(ctx *sql.Context, table string, old, new sql.Row) error {
+	w, err := e.writer(ctx, table)
+	if err != nil {
+		return err
+	}
+	return w.Update(ctx, old, new)
+}
+
+// Delete deletes |row| from |table|.
+func (e *RootEditor) Delete(ctx *sql.Context, table string, row sql.Row) error {
+	w, err := e.writer(ctx, table)
+	if err != nil {
+		return err
+	}
+	return w.Delete(ctx, row)
+}
+
+// Finish applies the edits and returns the edited root.
+func (e *RootEditor) Finish(ctx *sql.Context) (doltdb.RootValue, error) {
+	for _, w := range e.writers {
+		if err := w.StatementComplete(ctx); err != nil {
+			return nil, err
+		}
+	}
+	ws, err := e.session.Flush(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ws.WorkingRoot(), nil
+}
@@ -149,6 +149,23 @@ func Verify(ctx context.Context, signature []byte) ([]byte, error) {
 	return errBuf.Bytes(), nil
 }
 
+// VerifyClearSigned verifies a clear-signed message created by Sign, and returns the message it signs along with the
+// output of gpg describing the signature
+func VerifyClearSigned(ctx context.Context, signature []byte) ([]byte, []byte, error) {
+	args := []string{"--decrypt"}
+	outBuf, errBuf, err := execGpgAndReadOutput(ctx, signature, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return outBuf.Bytes(), errBuf.Bytes(), nil
+}
+
+// IsClearSigned returns true if the signature is a clear-signed message created by Sign
+func IsClearSigned(signature []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(signature), []byte("-----BEGIN PGP SIGNED MESSAGE-----"))
+}
+
 func listenToOut(ctx context.Context, eg *errgroup.Group, r io.Reader) *bytes.Buffer {
 	buf := bytes.NewBuffer(nil)
 	eg.Go(func() error {
@@ -15,6 +15,7 @@
 package gpg
 
 import (
+	"bytes"
 	"context"
 	"encoding/pem"
 	"fmt"
@@ -49,6 +50,24 @@ func TestSign(t *testing.T) {
 	require.NotNil(t, output)
 }
 
+func TestVerifyClearSigned(t *testing.T) {
+	ctx := context.Background()
+	signTestSetup(t, ctx)
+
+	message := []byte("I did a thing")
+	signature, err := Sign(ctx, keyId, message)
+	require.NoError(t, err)
+	require.True(t, IsClearSigned(signature))
+
+	signed, output, err := VerifyClearSigned(ctx, signature)
+	require.NoError(t, err)
+	require.Equal(t, "I did a thing\n", string(signed))
+	require.Contains(t, string(output), "Good signature")
+
+	_, _, err = VerifyClearSigned(ctx, bytes.Replace(signature, []byte("a thing"), []byte("a thong"), 1))
+	require.Error(t, err)
+}
+
 func TestDecodeAllPemBlocks(t *testing.T) {
 	pemBlock := `
 -----BEGIN PGP SIGNED MESSAGE-----
@@ -21,4 +21,6 @@ import (
 type Claims struct {
 	jwt.Claims
 	OnBehalfOf string `json:"on_behalf_of"`
+	// Scope is the space separated list of scopes granted to the bearer, as in RFC 8693.
+	Scope string `json:"scope,omitempty"`
 }
@@ -0,0 +1,232 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshsig
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// AllowedSigner is an entry of an allowed signers file, which trusts |Key| to sign as any of |Principals|.
+type AllowedSigner struct {
+	// Principals are the patterns of the principals the key may sign as, e.g. "*@example.com".
+	Principals []string
+	// Namespaces are the patterns of the namespaces the key may sign for. Empty means any namespace.
+	Namespaces  []string
+	ValidAfter  time.Time
+	ValidBefore time.Time
+	Key         ssh.PublicKey
+}
+
+// AllowedSigners is a parsed allowed signers file, in the format described in the ALLOWED SIGNERS section of
+// ssh-keygen(1) and used by git's gpg.ssh.allowedSignersFile. Certificate authorities are not supported.
+type AllowedSigners []AllowedSigner
+
+// LoadAllowedSigners reads and parses the allowed signers file at |path|.
+func LoadAllowedSigners(path string) (AllowedSigners, error) {
+	data, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowed signers file: %w", err)
+	}
+
+	as, err := ParseAllowedSigners(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return as, nil
+}
+
+// ParseAllowedSigners parses the contents of an allowed signers file. Each line holds comma separated principal
+// patterns, followed by optional options, a key type and a base64 encoded public key.
+func ParseAllowedSigners(data []byte) (AllowedSigners, error) {
+	var as AllowedSigners
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		signer, err := parseAllowedSigner(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		as = append(as, signer)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+func parseAllowedSigner(line string) (AllowedSigner, error) {
+	var principals, rest string
+	if strings.HasPrefix(line, `"`) {
+		end := strings.Index(line[1:], `"`)
+		if end < 0 {
+			return AllowedSigner{}, fmt.Errorf("unterminated quote in principals")
+		}
+		principals, rest = line[1:end+1], line[end+2:]
+	} else {
+		i := strings.IndexAny(line, " \t")
+		if i < 0 {
+			return AllowedSigner{}, fmt.Errorf("missing public key")
+		}
+		principals, rest = line[:i], line[i:]
+	}
+
+	key, _, options, _, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(rest)))
+	if err != nil {
+		return AllowedSigner{}, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	signer := AllowedSigner{
+		Principals: strings.Split(principals, ","),
+		Key:        key,
+	}
+	for _, opt := range options {
+		name, value, _ := strings.Cut(opt, "=")
+		value = strings.Trim(value, `"`)
+		switch strings.ToLower(name) {
+		case "namespaces":
+			signer.Namespaces = strings.Split(value, ",")
+		case "valid-after":
+			signer.ValidAfter, err = parseValidityTime(value)
+		case "valid-before":
+			signer.ValidBefore, err = parseValidityTime(value)
+		case "cert-authority":
+			return AllowedSigner{}, fmt.Errorf("certificate authorities are not supported")
+		default:
+			return AllowedSigner{}, fmt.Errorf("unsupported option '%s'", name)
+		}
+		if err != nil {
+			return AllowedSigner{}, err
+		}
+	}
+
+	return signer, nil
+}
+
+// parseValidityTime parses the YYYYMMDD[HHMM[SS]][Z] times of the valid-after and valid-before options. Times without
+// the Z suffix are in the local time zone.
+func parseValidityTime(s string) (time.Time, error) {
+	loc := time.Local
+	if strings.HasSuffix(s, "Z") || strings.HasSuffix(s, "z") {
+		s, loc = s[:len(s)-1], time.UTC
+	}
+
+	var layout string
+	switch len(s) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("invalid validity time '%s'", s)
+	}
+
+	t, err := time.ParseInLocation(layout, s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid validity time '%s': %w", s, err)
+	}
+	return t, nil
+}
+
+// FindPrincipal returns the first principal pattern which |key| is allowed to sign as in |namespace| at time |at|, and
+// whether there is one.
+func (as AllowedSigners) FindPrincipal(key ssh.PublicKey, namespace string, at time.Time) (string, bool) {
+	want := key.Marshal()
+	for _, signer := range as {
+		if !bytes.Equal(signer.Key.Marshal(), want) || !signer.allows(namespace, at) {
+			continue
+		}
+		return strings.Join(signer.Principals, ","), true
+	}
+	return "", false
+}
+
+// IsAllowed returns whether |key| is allowed to sign as |principal| in |namespace| at time |at|.
+func (as AllowedSigners) IsAllowed(key ssh.PublicKey, principal, namespace string, at time.Time) bool {
+	want := key.Marshal()
+	for _, signer := range as {
+		if bytes.Equal(signer.Key.Marshal(), want) && signer.allows(namespace, at) && matchPatternList(principal, signer.Principals) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s AllowedSigner) allows(namespace string, at time.Time) bool {
+	if len(s.Namespaces) > 0 && !matchPatternList(namespace, s.Namespaces) {
+		return false
+	}
+	if !s.ValidAfter.IsZero() && at.Before(s.ValidAfter) {
+		return false
+	}
+	if !s.ValidBefore.IsZero() && !at.Before(s.ValidBefore) {
+		return false
+	}
+	return true
+}
+
+// matchPatternList returns whether |s| matches any of |patterns|, and none of the negated patterns prefixed with "!".
+func matchPatternList(s string, patterns []string) bool {
+	matched := false
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if negated := strings.HasPrefix(p, "!"); negated {
+			if matchPattern(s, p[1:]) {
+				return false
+			}
+		} else if matchPattern(s, p) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// matchPattern matches |s| against |pattern|, in which '*' matches any sequence of characters and '?' matches any
+// single character.
+func matchPattern(s, pattern string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := 0; i <= len(s); i++ {
+				if matchPattern(s[i:], pattern[1:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+		default:
+			if len(s) == 0 || s[0] != pattern[0] {
+				return false
+			}
+		}
+		s, pattern = s[1:], pattern[1:]
+	}
+	return len(s) == 0
+}
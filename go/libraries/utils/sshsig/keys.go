@@ -0,0 +1,108 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshsig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// keyLiteralPrefix marks a signing key given as a public key literal, rather than as a path, in the same way as git's
+// user.signingKey.
+const keyLiteralPrefix = "key::"
+
+// SignWithKey signs |message| for use in |namespace| with the key described by |keySpec|, which is either
+//   - the path of an unencrypted private key file,
+//   - the path of a public key file, or of an encrypted private key file, whose private key is held by ssh-agent, or
+//   - a public key literal prefixed with "key::", whose private key is held by ssh-agent.
+func SignWithKey(keySpec, namespace string, message []byte) ([]byte, error) {
+	if keySpec == "" {
+		return nil, errors.New("no ssh signing key configured")
+	}
+
+	var pub ssh.PublicKey
+	if strings.HasPrefix(keySpec, keyLiteralPrefix) {
+		var err error
+		pub, _, _, _, err = ssh.ParseAuthorizedKey([]byte(strings.TrimPrefix(keySpec, keyLiteralPrefix)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ssh signing key literal: %w", err)
+		}
+	} else {
+		data, err := os.ReadFile(expandHome(keySpec))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ssh signing key: %w", err)
+		}
+
+		signer, err := ssh.ParsePrivateKey(data)
+		var missingErr *ssh.PassphraseMissingError
+		if err == nil {
+			return Sign(signer, namespace, message)
+		} else if errors.As(err, &missingErr) && missingErr.PublicKey != nil {
+			pub = missingErr.PublicKey
+		} else {
+			pub, _, _, _, err = ssh.ParseAuthorizedKey(data)
+			if err != nil {
+				return nil, fmt.Errorf("ssh signing key '%s' is neither an unencrypted private key nor a public key", keySpec)
+			}
+		}
+	}
+
+	return signWithAgent(pub, namespace, message)
+}
+
+// signWithAgent signs |message| with the private key of |pub| held by the ssh-agent listening on SSH_AUTH_SOCK.
+func signWithAgent(pub ssh.PublicKey, namespace string, message []byte) ([]byte, error) {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, errors.New("the private key of the ssh signing key is not available: SSH_AUTH_SOCK is not set")
+	}
+
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ssh-agent: %w", err)
+	}
+	defer conn.Close()
+
+	signers, err := agent.NewClient(conn).Signers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ssh-agent keys: %w", err)
+	}
+
+	want := pub.Marshal()
+	for _, signer := range signers {
+		if bytes.Equal(signer.PublicKey().Marshal(), want) {
+			return Sign(signer, namespace, message)
+		}
+	}
+
+	return nil, fmt.Errorf("ssh-agent does not hold the private key of %s", ssh.FingerprintSHA256(pub))
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
@@ -0,0 +1,209 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sshsig implements the signature format of `ssh-keygen -Y sign`, as described in PROTOCOL.sshsig of the
+// OpenSSH sources, so that ssh keys can be used to sign and verify data without shelling out to ssh-keygen.
+package sshsig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	magicPreamble = "SSHSIG"
+	sigVersion    = 1
+
+	armorStart = "-----BEGIN SSH SIGNATURE-----"
+	armorEnd   = "-----END SSH SIGNATURE-----"
+
+	// armorLineLen matches the line length used by ssh-keygen
+	armorLineLen = 70
+
+	hashSHA256 = "sha256"
+	hashSHA512 = "sha512"
+)
+
+var ErrNotSSHSignature = errors.New("not an ssh signature")
+var ErrBadSignature = errors.New("bad ssh signature")
+
+// IsSignature returns whether |sig| looks like an armored ssh signature.
+func IsSignature(sig []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(sig), []byte(armorStart))
+}
+
+// wrappedSig is the binary representation of an ssh signature.
+type wrappedSig struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// signedData is the blob which is signed by the key.
+type signedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+func newHash(alg string) (hash.Hash, error) {
+	switch alg {
+	case hashSHA256:
+		return sha256.New(), nil
+	case hashSHA512:
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported ssh signature hash algorithm '%s'", alg)
+	}
+}
+
+func dataToSign(namespace, hashAlg string, message []byte) ([]byte, error) {
+	h, err := newHash(hashAlg)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(message)
+
+	sd := signedData{
+		Namespace:     namespace,
+		HashAlgorithm: hashAlg,
+		Hash:          h.Sum(nil),
+	}
+	return append([]byte(magicPreamble), ssh.Marshal(sd)...), nil
+}
+
+// Sign signs |message| with |signer| for use in |namespace|, and returns the armored signature.
+func Sign(signer ssh.Signer, namespace string, message []byte) ([]byte, error) {
+	if namespace == "" {
+		return nil, errors.New("ssh signatures require a namespace")
+	}
+
+	data, err := dataToSign(namespace, hashSHA512, message)
+	if err != nil {
+		return nil, err
+	}
+
+	var sig *ssh.Signature
+	pub := signer.PublicKey()
+	if algSigner, ok := signer.(ssh.AlgorithmSigner); ok && pub.Type() == ssh.KeyAlgoRSA {
+		// SHA-1 rsa signatures are not accepted by ssh-keygen
+		sig, err = algSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = signer.Sign(rand.Reader, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ws := wrappedSig{
+		Version:       sigVersion,
+		PublicKey:     pub.Marshal(),
+		Namespace:     namespace,
+		HashAlgorithm: hashSHA512,
+		Signature:     ssh.Marshal(sig),
+	}
+	return armor(append([]byte(magicPreamble), ssh.Marshal(ws)...)), nil
+}
+
+// Verify checks that |signature| is a valid signature of |message| for use in |namespace|, and returns the public key
+// which made it. It does not check whether the key is trusted, see AllowedSigners for that.
+func Verify(signature []byte, namespace string, message []byte) (ssh.PublicKey, error) {
+	bs, err := dearmor(signature)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(bs, []byte(magicPreamble)) {
+		return nil, ErrNotSSHSignature
+	}
+
+	var ws wrappedSig
+	if err := ssh.Unmarshal(bs[len(magicPreamble):], &ws); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotSSHSignature, err)
+	}
+	if ws.Version != sigVersion {
+		return nil, fmt.Errorf("unsupported ssh signature version %d", ws.Version)
+	}
+	if ws.Namespace != namespace {
+		return nil, fmt.Errorf("%w: signature namespace '%s' does not match expected namespace '%s'", ErrBadSignature, ws.Namespace, namespace)
+	}
+
+	pub, err := ssh.ParsePublicKey(ws.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var sig ssh.Signature
+	if err := ssh.Unmarshal(ws.Signature, &sig); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotSSHSignature, err)
+	}
+	if sig.Format == ssh.KeyAlgoRSA {
+		return nil, fmt.Errorf("%w: SHA-1 rsa signatures are not supported", ErrBadSignature)
+	}
+
+	data, err := dataToSign(namespace, ws.HashAlgorithm, message)
+	if err != nil {
+		return nil, err
+	}
+	if err := pub.Verify(data, &sig); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+
+	return pub, nil
+}
+
+func armor(bs []byte) []byte {
+	enc := base64.StdEncoding.EncodeToString(bs)
+
+	var sb strings.Builder
+	sb.WriteString(armorStart)
+	sb.WriteString("\n")
+	for len(enc) > armorLineLen {
+		sb.WriteString(enc[:armorLineLen])
+		sb.WriteString("\n")
+		enc = enc[armorLineLen:]
+	}
+	sb.WriteString(enc)
+	sb.WriteString("\n")
+	sb.WriteString(armorEnd)
+	sb.WriteString("\n")
+	return []byte(sb.String())
+}
+
+func dearmor(sig []byte) ([]byte, error) {
+	s := strings.TrimSpace(string(sig))
+	if !strings.HasPrefix(s, armorStart) || !strings.HasSuffix(s, armorEnd) {
+		return nil, ErrNotSSHSignature
+	}
+	s = s[len(armorStart) : len(s)-len(armorEnd)]
+	s = strings.Join(strings.Fields(s), "")
+
+	bs, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotSSHSignature, err)
+	}
+	return bs, nil
+}
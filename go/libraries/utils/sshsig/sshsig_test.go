@@ -0,0 +1,165 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshsig
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func newEd25519Signer(t *testing.T) ssh.Signer {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+	return signer
+}
+
+func TestSignAndVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaSigner, err := ssh.NewSignerFromKey(rsaKey)
+	require.NoError(t, err)
+
+	for name, signer := range map[string]ssh.Signer{"ed25519": newEd25519Signer(t), "rsa": rsaSigner} {
+		t.Run(name, func(t *testing.T) {
+			message := []byte("I did a thing")
+			sig, err := Sign(signer, "dolt", message)
+			require.NoError(t, err)
+			assert.True(t, IsSignature(sig))
+			assert.True(t, strings.HasPrefix(string(sig), armorStart+"\n"))
+
+			pub, err := Verify(sig, "dolt", message)
+			require.NoError(t, err)
+			assert.Equal(t, signer.PublicKey().Marshal(), pub.Marshal())
+
+			_, err = Verify(sig, "dolt", []byte("I did another thing"))
+			assert.ErrorIs(t, err, ErrBadSignature)
+
+			_, err = Verify(sig, "git", message)
+			assert.ErrorIs(t, err, ErrBadSignature)
+		})
+	}
+
+	_, err = Verify([]byte("-----BEGIN PGP SIGNATURE-----\n-----END PGP SIGNATURE-----"), "dolt", nil)
+	assert.ErrorIs(t, err, ErrNotSSHSignature)
+}
+
+func TestSignWithKey(t *testing.T) {
+	signer := newEd25519Signer(t)
+	dir := t.TempDir()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "id_ed25519")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(block), 0600))
+
+	sig, err := SignWithKey(keyFile, "dolt", []byte("message"))
+	require.NoError(t, err)
+	pub, err := Verify(sig, "dolt", []byte("message"))
+	require.NoError(t, err)
+	expected, err := ssh.NewPublicKey(priv.Public())
+	require.NoError(t, err)
+	assert.Equal(t, expected.Marshal(), pub.Marshal())
+
+	t.Setenv("SSH_AUTH_SOCK", "")
+	pubFile := filepath.Join(dir, "id_ed25519.pub")
+	require.NoError(t, os.WriteFile(pubFile, ssh.MarshalAuthorizedKey(signer.PublicKey()), 0600))
+	_, err = SignWithKey(pubFile, "dolt", []byte("message"))
+	assert.ErrorContains(t, err, "SSH_AUTH_SOCK")
+
+	_, err = SignWithKey(filepath.Join(dir, "missing"), "dolt", []byte("message"))
+	assert.Error(t, err)
+}
+
+func TestAllowedSigners(t *testing.T) {
+	alice := newEd25519Signer(t)
+	bob := newEd25519Signer(t)
+	carol := newEd25519Signer(t)
+	authorized := func(s ssh.Signer) string {
+		return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(s.PublicKey())))
+	}
+
+	file := "# release signers\n" +
+		"alice@example.com,*@ops.example.com " + authorized(alice) + " alice's laptop\n" +
+		"\n" +
+		`"bob@example.com" namespaces="dolt,file" ` + authorized(bob) + "\n" +
+		`carol@example.com valid-after="20200101",valid-before="20300101Z" ` + authorized(carol) + "\n"
+
+	as, err := ParseAllowedSigners([]byte(file))
+	require.NoError(t, err)
+	require.Len(t, as, 3)
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	principal, ok := as.FindPrincipal(alice.PublicKey(), "dolt", now)
+	assert.True(t, ok)
+	assert.Equal(t, "alice@example.com,*@ops.example.com", principal)
+	assert.True(t, as.IsAllowed(alice.PublicKey(), "alice@example.com", "dolt", now))
+	assert.True(t, as.IsAllowed(alice.PublicKey(), "deploy@ops.example.com", "dolt", now))
+	assert.False(t, as.IsAllowed(alice.PublicKey(), "bob@example.com", "dolt", now))
+
+	principal, ok = as.FindPrincipal(bob.PublicKey(), "dolt", now)
+	assert.True(t, ok)
+	assert.Equal(t, "bob@example.com", principal)
+	_, ok = as.FindPrincipal(bob.PublicKey(), "git", now)
+	assert.False(t, ok)
+
+	_, ok = as.FindPrincipal(carol.PublicKey(), "dolt", now)
+	assert.True(t, ok)
+	_, ok = as.FindPrincipal(carol.PublicKey(), "dolt", time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+
+	_, ok = as.FindPrincipal(newEd25519Signer(t).PublicKey(), "dolt", now)
+	assert.False(t, ok)
+
+	_, err = ParseAllowedSigners([]byte("alice@example.com cert-authority " + authorized(alice)))
+	assert.ErrorContains(t, err, "certificate authorities are not supported")
+	_, err = ParseAllowedSigners([]byte("alice@example.com"))
+	assert.ErrorContains(t, err, "line 1")
+}
+
+func TestMatchPatternList(t *testing.T) {
+	tests := []struct {
+		s        string
+		patterns string
+		expected bool
+	}{
+		{"alice@example.com", "alice@example.com", true},
+		{"alice@example.com", "*@example.com", true},
+		{"alice@example.com", "?lice@example.com", true},
+		{"alice@example.com", "*@example.org", false},
+		{"alice@example.com", "*@example.com,!alice@*", false},
+		{"bob@example.com", "*@example.com,!alice@*", true},
+		{"alice@example.com", "!bob@example.com", false},
+		{"", "*", true},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, matchPatternList(test.s, strings.Split(test.patterns, ",")), "%s %s", test.s, test.patterns)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	azureBlockIDWidth = 8
+
+	// azureUploadBlockSize is the size of the blocks a Put of unknown length is staged in.
+	azureUploadBlockSize = 8 * 1024 * 1024
+
+	// azureStageConcurrency is the number of blocks staged at once by Concatenate.
+	azureStageConcurrency = 8
+
+	// azureCopySourceExpiry is how long the SAS a Concatenate signs for its sources is valid for.
+	azureCopySourceExpiry = time.Hour
+)
+
+// AzureBlobstore provides an Azure Blob Storage implementation of the Blobstore interface. Blobs are stored as block
+// blobs, and their ETags are used as versions.
+type AzureBlobstore struct {
+	client        *container.Client
+	containerName string
+	prefix        string
+}
+
+var _ Blobstore = &AzureBlobstore{}
+
+// NewAzureBlobstore creates a new instance of an AzureBlobstore storing blobs under |prefix| in the container of
+// |client|.
+func NewAzureBlobstore(client *container.Client, prefix string) (*AzureBlobstore, error) {
+	parts, err := azblob.ParseURL(client.URL())
+	if err != nil {
+		return nil, err
+	}
+	if parts.ContainerName == "" {
+		return nil, fmt.Errorf("invalid azure container url '%s'", client.URL())
+	}
+	return &AzureBlobstore{
+		client:        client,
+		containerName: parts.ContainerName,
+		prefix:        normalizePrefix(prefix),
+	}, nil
+}
+
+func (bs *AzureBlobstore) Path() string {
+	return path.Join(bs.containerName, bs.prefix)
+}
+
+func (bs *AzureBlobstore) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := bs.blockBlob(key).GetProperties(ctx, nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (bs *AzureBlobstore) Get(ctx context.Context, key string, br BlobRange) (io.ReadCloser, string, error) {
+	client := bs.blockBlob(key)
+	if br.offset < 0 {
+		// The Blob service does not support suffix ranges.
+		props, err := client.GetProperties(ctx, nil)
+		if err != nil {
+			return nil, "", bs.notFoundOr(key, err)
+		}
+		br = br.positiveRange(*props.ContentLength)
+	}
+
+	resp, err := client.DownloadStream(ctx, &blob.DownloadStreamOptions{
+		Range: blob.HTTPRange{Offset: br.offset, Count: br.length},
+	})
+	if err != nil {
+		return nil, "", bs.notFoundOr(key, err)
+	}
+	return &deferredEOFReader{ReadCloser: resp.Body}, etagString(resp.ETag), nil
+}
+
+func (bs *AzureBlobstore) Put(ctx context.Context, key string, totalSize int64, reader io.Reader) (string, error) {
+	return bs.upload(ctx, key, reader, nil)
+}
+
+func (bs *AzureBlobstore) CheckAndPut(ctx context.Context, expectedVersion, key string, totalSize int64, reader io.Reader) (string, error) {
+	var conditions blob.ModifiedAccessConditions
+	if expectedVersion == "" {
+		conditions.IfNoneMatch = to.Ptr(azcore.ETagAny)
+	} else {
+		conditions.IfMatch = to.Ptr(azcore.ETag(expectedVersion))
+	}
+
+	ver, err := bs.upload(ctx, key, reader, &blob.AccessConditions{ModifiedAccessConditions: &conditions})
+	// A failed If-Match is reported as ConditionNotMet, and a failed If-None-Match as BlobAlreadyExists.
+	if bloberror.HasCode(err, bloberror.ConditionNotMet, bloberror.BlobAlreadyExists) {
+		var respErr *azcore.ResponseError
+		errors.As(err, &respErr)
+		return "", CheckAndPutError{
+			Key:             key,
+			ExpectedVersion: expectedVersion,
+			ActualVersion:   fmt.Sprintf("unknown (azure error code %s)", respErr.ErrorCode),
+		}
+	}
+	return ver, err
+}
+
+// upload streams |reader| into the block blob keyed by |key|. Blobs that fit in a single block are written with a
+// single Put Blob, larger ones are staged block by block and committed once all of |reader| has been read.
+func (bs *AzureBlobstore) upload(ctx context.Context, key string, reader io.Reader, conditions *blob.AccessConditions) (string, error) {
+	resp, err := bs.blockBlob(key).UploadStream(ctx, reader, &blockblob.UploadStreamOptions{
+		BlockSize:        azureUploadBlockSize,
+		AccessConditions: conditions,
+	})
+	if err != nil {
+		return "", err
+	}
+	return etagString(resp.ETag), nil
+}
+
+// Concatenate stages each of |sources| as a block of a new block blob keyed by |key| with Put Block From URL, so that
+// the data of the sources is copied by the Blob service, and then commits the block list.
+func (bs *AzureBlobstore) Concatenate(ctx context.Context, key string, sources []string) (string, error) {
+	dst := bs.blockBlob(key)
+	blockIDs := make([]string, len(sources))
+	for i := range sources {
+		blockIDs[i] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%0*d", azureBlockIDWidth, i)))
+	}
+
+	eg, ectx := errgroup.WithContext(ctx)
+	eg.SetLimit(azureStageConcurrency)
+	for i, src := range sources {
+		i, src := i, src
+		eg.Go(func() error {
+			srcURL, err := bs.copySourceURL(src)
+			if err != nil {
+				return err
+			}
+			_, err = dst.StageBlockFromURL(ectx, blockIDs[i], srcURL, nil)
+			return bs.notFoundOr(src, err)
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return "", err
+	}
+
+	resp, err := dst.CommitBlockList(ctx, blockIDs, nil)
+	if err != nil {
+		return "", err
+	}
+	return etagString(resp.ETag), nil
+}
+
+// copySourceURL returns a URL the Blob service can read the blob keyed by |key| from. With a shared key, the URL
+// carries a short-lived read-only SAS. Otherwise it carries the SAS of the client, if any.
+func (bs *AzureBlobstore) copySourceURL(key string) (string, error) {
+	src := bs.client.NewBlobClient(bs.absKey(key))
+	u, err := src.GetSASURL(sas.BlobPermissions{Read: true}, time.Now().Add(azureCopySourceExpiry), nil)
+	if errors.Is(err, bloberror.MissingSharedKeyCredential) {
+		return src.URL(), nil
+	}
+	return u, err
+}
+
+func (bs *AzureBlobstore) blockBlob(key string) *blockblob.Client {
+	return bs.client.NewBlockBlobClient(bs.absKey(key))
+}
+
+func (bs *AzureBlobstore) absKey(key string) string {
+	return path.Join(bs.prefix, key)
+}
+
+func (bs *AzureBlobstore) notFoundOr(key string, err error) error {
+	if bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.CannotVerifyCopySource) {
+		return NotFound{"azure://" + path.Join(bs.containerName, bs.absKey(key))}
+	}
+	return err
+}
+
+func etagString(etag *azcore.ETag) string {
+	if etag == nil {
+		return ""
+	}
+	return string(*etag)
+}
@@ -0,0 +1,316 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// The well known account used by the Azurite emulator.
+	testAzureAccount = "devstoreaccount1"
+	testAzureKey     = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+)
+
+// fakeAzureBlobService is an in-memory implementation of the parts of the Blob service REST API used by
+// AzureBlobstore. Like the Azurite emulator, it serves a single account under a path prefix. It doesn't check
+// signatures, which are left to the SDK.
+type fakeAzureBlobService struct {
+	mu      sync.Mutex
+	blobs   map[string][]byte
+	etags   map[string]string
+	blocks  map[string]map[string][]byte
+	nextTag int
+
+	// the number of blocks staged with and without a copy source
+	copiedBlocks, uploadedBlocks int
+}
+
+func newFakeAzureBlobService() *fakeAzureBlobService {
+	return &fakeAzureBlobService{
+		blobs:  make(map[string][]byte),
+		etags:  make(map[string]string),
+		blocks: make(map[string]map[string][]byte),
+	}
+}
+
+func (f *fakeAzureBlobService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("x-ms-version") == "" {
+		f.fail(w, http.StatusBadRequest, "MissingRequiredHeader")
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey "+testAzureAccount+":") && r.URL.Query().Get("sig") == "" {
+		f.fail(w, http.StatusForbidden, "AuthenticationFailed")
+		return
+	}
+
+	name := f.blobName(r.URL)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		f.fail(w, http.StatusBadRequest, "InvalidInput")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodHead:
+		data, ok := f.blobs[name]
+		if !ok {
+			f.fail(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		w.Header().Set("ETag", f.etags[name])
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusOK)
+
+	case r.Method == http.MethodGet:
+		data, ok := f.blobs[name]
+		if !ok {
+			f.fail(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		w.Header().Set("ETag", f.etags[name])
+		rng := r.Header.Get("x-ms-range")
+		if rng == "" {
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+			return
+		}
+		var start, end int
+		if _, err := fmt.Sscanf(rng, "bytes=%d-%d", &start, &end); err != nil {
+			if _, err := fmt.Sscanf(rng, "bytes=%d-", &start); err != nil {
+				f.fail(w, http.StatusBadRequest, "InvalidRange")
+				return
+			}
+			end = len(data) - 1
+		}
+		end = min(end, len(data)-1)
+		w.Header().Set("Content-Length", strconv.Itoa(end+1-start))
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(data[start : end+1])
+
+	case r.Method == http.MethodPut && query.Get("comp") == "block":
+		if src := r.Header.Get("x-ms-copy-source"); src != "" {
+			srcURL, err := url.Parse(src)
+			if err != nil {
+				f.fail(w, http.StatusBadRequest, "InvalidHeaderValue")
+				return
+			}
+			data, ok := f.blobs[f.blobName(srcURL)]
+			if !ok {
+				f.fail(w, http.StatusNotFound, "CannotVerifyCopySource")
+				return
+			}
+			body = data
+			f.copiedBlocks++
+		} else {
+			f.uploadedBlocks++
+		}
+		if f.blocks[name] == nil {
+			f.blocks[name] = make(map[string][]byte)
+		}
+		f.blocks[name][query.Get("blockid")] = body
+		w.WriteHeader(http.StatusCreated)
+
+	case r.Method == http.MethodPut && query.Get("comp") == "blocklist":
+		if !f.checkConditions(w, r, name) {
+			return
+		}
+		var list struct {
+			Latest []string `xml:"Latest"`
+		}
+		if err := xml.Unmarshal(body, &list); err != nil {
+			f.fail(w, http.StatusBadRequest, "InvalidXmlDocument")
+			return
+		}
+		var data []byte
+		for _, id := range list.Latest {
+			block, ok := f.blocks[name][id]
+			if !ok {
+				f.fail(w, http.StatusBadRequest, "InvalidBlockList")
+				return
+			}
+			data = append(data, block...)
+		}
+		delete(f.blocks, name)
+		f.store(w, name, data)
+
+	case r.Method == http.MethodPut:
+		if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+			f.fail(w, http.StatusBadRequest, "InvalidHeaderValue")
+			return
+		}
+		if !f.checkConditions(w, r, name) {
+			return
+		}
+		f.store(w, name, body)
+
+	default:
+		f.fail(w, http.StatusMethodNotAllowed, "UnsupportedHttpVerb")
+	}
+}
+
+func (f *fakeAzureBlobService) blobName(u *url.URL) string {
+	return strings.TrimPrefix(u.Path, "/"+testAzureAccount+"/")
+}
+
+func (f *fakeAzureBlobService) checkConditions(w http.ResponseWriter, r *http.Request, name string) bool {
+	etag, exists := f.etags[name]
+	if r.Header.Get("If-None-Match") == "*" && exists {
+		f.fail(w, http.StatusConflict, "BlobAlreadyExists")
+		return false
+	}
+	if m := r.Header.Get("If-Match"); m != "" && m != etag {
+		f.fail(w, http.StatusPreconditionFailed, "ConditionNotMet")
+		return false
+	}
+	return true
+}
+
+func (f *fakeAzureBlobService) store(w http.ResponseWriter, name string, data []byte) {
+	f.nextTag++
+	f.blobs[name] = data
+	f.etags[name] = fmt.Sprintf("\"0x%X\"", f.nextTag)
+	w.Header().Set("ETag", f.etags[name])
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (f *fakeAzureBlobService) fail(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(status)
+}
+
+func newFakeAzureBlobstore(prefix string) (*AzureBlobstore, *fakeAzureBlobService, *httptest.Server) {
+	fake := newFakeAzureBlobService()
+	srv := httptest.NewServer(fake)
+	cred, err := container.NewSharedKeyCredential(testAzureAccount, testAzureKey)
+	if err != nil {
+		panic(err)
+	}
+	client, err := container.NewClientWithSharedKeyCredential(srv.URL+"/"+testAzureAccount+"/container", cred, &container.ClientOptions{
+		ClientOptions: azcore.ClientOptions{
+			Transport: srv.Client(),
+			Retry:     policy.RetryOptions{MaxRetries: -1},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	bs, err := NewAzureBlobstore(client, prefix)
+	if err != nil {
+		panic(err)
+	}
+	return bs, fake, srv
+}
+
+func appendAzureTest(tests []BlobstoreTest) []BlobstoreTest {
+	bs, _, _ := newFakeAzureBlobstore(uuid.New().String() + "/")
+	return append(tests, BlobstoreTest{"azure", bs, 10, 20})
+}
+
+func TestAzureBlobstore(t *testing.T) {
+	ctx := context.Background()
+	bs, fake, srv := newFakeAzureBlobstore("/db")
+	defer srv.Close()
+
+	assert.Equal(t, "container/db", bs.Path())
+
+	_, _, err := bs.Get(ctx, "missing", AllRange)
+	assert.True(t, IsNotFoundError(err))
+	assert.EqualError(t, err, "Blob not found: azure://container/db/missing")
+	_, _, err = bs.Get(ctx, "missing", NewBlobRange(-4, 0))
+	assert.True(t, IsNotFoundError(err))
+
+	ver, err := PutBytes(ctx, bs, "manifest", []byte("0123456789"))
+	require.NoError(t, err)
+	data, getVer, err := GetBytes(ctx, bs, "manifest", NewBlobRange(-4, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "6789", string(data))
+	assert.Equal(t, ver, getVer)
+	data, _, err = GetBytes(ctx, bs, "manifest", NewBlobRange(2, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "23456789", string(data))
+
+	_, err = bs.CheckAndPut(ctx, "", "manifest", 1, bytes.NewReader([]byte("x")))
+	assert.True(t, IsCheckAndPutError(err))
+	_, err = bs.CheckAndPut(ctx, "\"0x0\"", "manifest", 1, bytes.NewReader([]byte("x")))
+	assert.True(t, IsCheckAndPutError(err))
+	_, err = bs.CheckAndPut(ctx, ver, "manifest", 1, bytes.NewReader([]byte("x")))
+	assert.NoError(t, err)
+
+	ver, err = PutBytes(ctx, bs, "empty", nil)
+	require.NoError(t, err)
+	data, getVer, err = GetBytes(ctx, bs, "empty", AllRange)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+	assert.Equal(t, ver, getVer)
+
+	// Concatenate copies the sources inside the service
+	_, err = PutBytes(ctx, bs, "a", []byte("abc"))
+	require.NoError(t, err)
+	_, err = PutBytes(ctx, bs, "b", []byte("def"))
+	require.NoError(t, err)
+	fake.copiedBlocks, fake.uploadedBlocks = 0, 0
+	ver, err = bs.Concatenate(ctx, "ab", []string{"a", "b", "a"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, fake.copiedBlocks)
+	assert.Equal(t, 0, fake.uploadedBlocks)
+	data, getVer, err = GetBytes(ctx, bs, "ab", AllRange)
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefabc", string(data))
+	assert.Equal(t, ver, getVer)
+	_, err = bs.Concatenate(ctx, "ab", []string{"a", "missing"})
+	assert.True(t, IsNotFoundError(err))
+}
+
+func TestAzureBlobstorePutStreamsBlocks(t *testing.T) {
+	ctx := context.Background()
+	bs, fake, srv := newFakeAzureBlobstore("/db")
+	defer srv.Close()
+
+	// a reader that can't be seeked or sized is staged in blocks rather than read into memory in one piece
+	data := bytes.Repeat([]byte("0123456789abcdef"), (2*azureUploadBlockSize+1024)/16)
+	ver, err := bs.Put(ctx, "table", int64(len(data)), io.MultiReader(bytes.NewReader(data)))
+	require.NoError(t, err)
+	assert.Equal(t, 3, fake.uploadedBlocks)
+
+	read, getVer, err := GetBytes(ctx, bs, "table", AllRange)
+	require.NoError(t, err)
+	assert.Equal(t, data, read)
+	assert.Equal(t, ver, getVer)
+}
@@ -65,3 +65,22 @@ func PutBytes(ctx context.Context, bs Blobstore, key string, data []byte) (strin
 	reader := bytes.NewReader(data)
 	return bs.Put(ctx, key, int64(len(data)), reader)
 }
+
+// deferredEOFReader defers the io.EOF which net/http returns along with the last bytes of a response body to the
+// following call to Read, so that a single read of an entire range succeeds as it does for the local Blobstores.
+type deferredEOFReader struct {
+	io.ReadCloser
+	eof bool
+}
+
+func (r *deferredEOFReader) Read(p []byte) (int, error) {
+	if r.eof {
+		return 0, io.EOF
+	}
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF && n > 0 {
+		r.eof = true
+		err = nil
+	}
+	return n, err
+}
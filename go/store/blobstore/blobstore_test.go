@@ -117,6 +117,8 @@ func newBlobStoreTests() []BlobstoreTest {
 	var tests []BlobstoreTest
 	tests = append(tests, BlobstoreTest{"inmem", NewInMemoryBlobstore(""), 10, 20})
 	tests = appendLocalTest(tests)
+	tests = appendAzureTest(tests)
+	tests = appendS3Test(tests)
 	tests = appendGCSTest(tests)
 	tests = appendOCITest(tests)
 
@@ -0,0 +1,284 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+const (
+	// s3PartSize is the size of the parts a Put of a reader that can't be seeked is uploaded in, unless the size of
+	// the blob calls for larger ones.
+	s3PartSize = 8 * 1024 * 1024
+
+	// s3MaxParts is the maximum number of parts in a multipart upload.
+	s3MaxParts = 10000
+)
+
+// S3Blobstore provides an S3 implementation of the Blobstore interface. Object ETags are used as versions, and
+// CheckAndPut relies on the conditional writes supported by S3 and by most S3 compatible stores, which reject a put
+// with 412 Precondition Failed when its If-Match or If-None-Match header is not satisfied.
+type S3Blobstore struct {
+	s3     s3iface.S3API
+	bucket string
+	prefix string
+}
+
+var _ Blobstore = &S3Blobstore{}
+
+// NewS3Blobstore creates a new instance of an S3Blobstore storing objects under |prefix| in |bucket|.
+func NewS3Blobstore(s3 s3iface.S3API, bucket, prefix string) *S3Blobstore {
+	return &S3Blobstore{s3: s3, bucket: bucket, prefix: normalizePrefix(prefix)}
+}
+
+func (bs *S3Blobstore) Path() string {
+	return path.Join(bs.bucket, bs.prefix)
+}
+
+func (bs *S3Blobstore) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := bs.s3.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bs.bucket),
+		Key:    aws.String(bs.absKey(key)),
+	})
+	if isS3NotFoundErr(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (bs *S3Blobstore) Get(ctx context.Context, key string, br BlobRange) (io.ReadCloser, string, error) {
+	absKey := bs.absKey(key)
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bs.bucket),
+		Key:    aws.String(absKey),
+	}
+	if !br.isAllRange() {
+		if br.offset < 0 && br.length != 0 {
+			// A suffix range can't be limited in length, so resolve it against the size of the object.
+			head, err := bs.s3.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+				Bucket: aws.String(bs.bucket),
+				Key:    aws.String(absKey),
+			})
+			if isS3NotFoundErr(err) {
+				return nil, "", NotFound{"s3://" + path.Join(bs.bucket, absKey)}
+			} else if err != nil {
+				return nil, "", err
+			}
+			br = br.positiveRange(aws.Int64Value(head.ContentLength))
+		}
+		if br.offset < 0 {
+			input.Range = aws.String(fmt.Sprintf("bytes=%d", br.offset))
+		} else if br.length == 0 {
+			input.Range = aws.String(fmt.Sprintf("bytes=%d-", br.offset))
+		} else {
+			input.Range = aws.String(fmt.Sprintf("bytes=%d-%d", br.offset, br.offset+br.length-1))
+		}
+	}
+
+	out, err := bs.s3.GetObjectWithContext(ctx, input)
+	if isS3NotFoundErr(err) {
+		return nil, "", NotFound{"s3://" + path.Join(bs.bucket, absKey)}
+	} else if err != nil {
+		return nil, "", err
+	}
+	return &deferredEOFReader{ReadCloser: out.Body}, aws.StringValue(out.ETag), nil
+}
+
+func (bs *S3Blobstore) Put(ctx context.Context, key string, totalSize int64, reader io.Reader) (string, error) {
+	return bs.put(ctx, key, totalSize, reader, nil)
+}
+
+func (bs *S3Blobstore) CheckAndPut(ctx context.Context, expectedVersion, key string, totalSize int64, reader io.Reader) (string, error) {
+	conditions := map[string]string{}
+	if expectedVersion == "" {
+		conditions["If-None-Match"] = "*"
+	} else {
+		conditions["If-Match"] = expectedVersion
+	}
+
+	ver, err := bs.put(ctx, key, totalSize, reader, conditions)
+	if reqErr, ok := err.(awserr.RequestFailure); ok {
+		// 409 Conflict is returned when a concurrent conditional write to the same key wins the race.
+		if reqErr.StatusCode() == http.StatusPreconditionFailed || reqErr.StatusCode() == http.StatusConflict {
+			return "", CheckAndPutError{
+				Key:             key,
+				ExpectedVersion: expectedVersion,
+				ActualVersion:   fmt.Sprintf("unknown (S3 error code %s)", reqErr.Code()),
+			}
+		}
+	}
+	return ver, err
+}
+
+// put writes |reader| to the object keyed by |key|, applying the conditional |headers| to the request which creates
+// the object. PutObject needs an io.ReadSeeker, so that the request can be signed and retried. Other readers are read
+// a part at a time: a reader that fits in a single part is written with one PutObject, and a larger one is streamed
+// into a multipart upload, so that at most one part is held in memory. |totalSize|, when known, picks a part size
+// large enough to stay within the part limit of a multipart upload.
+func (bs *S3Blobstore) put(ctx context.Context, key string, totalSize int64, reader io.Reader, headers map[string]string) (string, error) {
+	if body, ok := reader.(io.ReadSeeker); ok {
+		return bs.putObject(ctx, key, body, headers)
+	}
+
+	partSize := int64(s3PartSize)
+	if totalSize > partSize*s3MaxParts {
+		partSize = (totalSize + s3MaxParts - 1) / s3MaxParts
+	}
+	buf := make([]byte, partSize)
+	n, err := io.ReadFull(reader, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return bs.putObject(ctx, key, bytes.NewReader(buf[:n]), headers)
+	} else if err != nil {
+		return "", err
+	}
+	return bs.multipartUpload(ctx, key, io.MultiReader(bytes.NewReader(buf[:n]), reader), buf, headers)
+}
+
+func (bs *S3Blobstore) putObject(ctx context.Context, key string, body io.ReadSeeker, headers map[string]string) (string, error) {
+	out, err := bs.s3.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bs.bucket),
+		Key:    aws.String(bs.absKey(key)),
+		Body:   body,
+	}, request.WithSetRequestHeaders(headers))
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.ETag), nil
+}
+
+// multipartUpload uploads |reader| as the parts of a multipart upload, reading each part into |buf|. The upload is
+// aborted if any part fails.
+func (bs *S3Blobstore) multipartUpload(ctx context.Context, key string, reader io.Reader, buf []byte, headers map[string]string) (_ string, err error) {
+	absKey := bs.absKey(key)
+	created, err := bs.s3.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bs.bucket),
+		Key:    aws.String(absKey),
+	})
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			_, _ = bs.s3.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(bs.bucket),
+				Key:      aws.String(absKey),
+				UploadId: created.UploadId,
+			})
+		}
+	}()
+
+	var parts []*s3.CompletedPart
+	for partNum := int64(1); ; partNum++ {
+		n, rerr := io.ReadFull(reader, buf)
+		if rerr != nil && rerr != io.EOF && rerr != io.ErrUnexpectedEOF {
+			return "", rerr
+		}
+		if n > 0 {
+			if partNum > s3MaxParts {
+				return "", fmt.Errorf("failed to upload s3://%s: more than %d parts", path.Join(bs.bucket, absKey), s3MaxParts)
+			}
+			out, err := bs.s3.UploadPartWithContext(ctx, &s3.UploadPartInput{
+				Bucket:     aws.String(bs.bucket),
+				Key:        aws.String(absKey),
+				UploadId:   created.UploadId,
+				PartNumber: aws.Int64(partNum),
+				Body:       bytes.NewReader(buf[:n]),
+			})
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, &s3.CompletedPart{ETag: out.ETag, PartNumber: aws.Int64(partNum)})
+		}
+		if rerr != nil {
+			break
+		}
+	}
+
+	out, err := bs.s3.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bs.bucket),
+		Key:             aws.String(absKey),
+		UploadId:        created.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	}, request.WithSetRequestHeaders(headers))
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.ETag), nil
+}
+
+// Concatenate streams each of |sources| in order into a new object keyed by |key|.
+func (bs *S3Blobstore) Concatenate(ctx context.Context, key string, sources []string) (string, error) {
+	return bs.put(ctx, key, -1, &s3ConcatReader{ctx: ctx, bs: bs, sources: sources}, nil)
+}
+
+// s3ConcatReader reads the objects keyed by |sources| one after another, opening each one only when it is reached.
+type s3ConcatReader struct {
+	ctx     context.Context
+	bs      *S3Blobstore
+	sources []string
+	curr    io.ReadCloser
+}
+
+func (r *s3ConcatReader) Read(p []byte) (int, error) {
+	for {
+		if r.curr == nil {
+			if len(r.sources) == 0 {
+				return 0, io.EOF
+			}
+			rc, _, err := r.bs.Get(r.ctx, r.sources[0], AllRange)
+			if err != nil {
+				return 0, err
+			}
+			r.curr, r.sources = rc, r.sources[1:]
+		}
+		n, err := r.curr.Read(p)
+		if err == io.EOF {
+			r.curr.Close()
+			r.curr = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (bs *S3Blobstore) absKey(key string) string {
+	return path.Join(bs.prefix, key)
+}
+
+func isS3NotFoundErr(err error) bool {
+	if reqErr, ok := err.(awserr.RequestFailure); ok && reqErr.StatusCode() == http.StatusNotFound {
+		return true
+	}
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code() == s3.ErrCodeNoSuchKey || aerr.Code() == "NotFound"
+	}
+	return false
+}
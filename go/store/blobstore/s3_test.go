@@ -0,0 +1,281 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3Service is an in-memory, path style S3 endpoint supporting the object operations used by S3Blobstore,
+// including conditional puts and multipart uploads, in the way S3 compatible stores such as MinIO implement them.
+type fakeS3Service struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	etags   map[string]string
+	uploads map[string]map[int][]byte
+	nextTag int
+
+	// the number of parts uploaded and the number of multipart uploads aborted
+	uploadedParts, abortedUploads int
+}
+
+func newFakeS3Service() *fakeS3Service {
+	return &fakeS3Service{objects: map[string][]byte{}, etags: map[string]string{}, uploads: map[string]map[int][]byte{}}
+}
+
+func (f *fakeS3Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		f.fail(w, r, http.StatusForbidden, "AccessDenied")
+		return
+	}
+	key := r.URL.Path
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		f.fail(w, r, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, exists := f.objects[key]
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		f.nextTag++
+		id := strconv.Itoa(f.nextTag)
+		f.uploads[id] = map[int][]byte{}
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", id)
+
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		parts, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			f.fail(w, r, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		partNum, _ := strconv.Atoi(query.Get("partNumber"))
+		parts[partNum] = body
+		f.uploadedParts++
+		w.Header().Set("ETag", fmt.Sprintf("\"part%d\"", partNum))
+		w.WriteHeader(http.StatusOK)
+
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		parts, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			f.fail(w, r, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		if !f.checkConditions(w, r, key) {
+			return
+		}
+		var complete struct {
+			Parts []struct {
+				PartNumber int
+			} `xml:"Part"`
+		}
+		if err := xml.Unmarshal(body, &complete); err != nil {
+			f.fail(w, r, http.StatusBadRequest, "MalformedXML")
+			return
+		}
+		var data []byte
+		for _, p := range complete.Parts {
+			data = append(data, parts[p.PartNumber]...)
+		}
+		delete(f.uploads, query.Get("uploadId"))
+		etag := f.store(key, data)
+		fmt.Fprintf(w, "<CompleteMultipartUploadResult><ETag>%s</ETag></CompleteMultipartUploadResult>", etag)
+
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(f.uploads, query.Get("uploadId"))
+		f.abortedUploads++
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodHead || r.Method == http.MethodGet:
+		if !exists {
+			f.fail(w, r, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", f.etags[key])
+		rng := r.Header.Get("Range")
+		if rng == "" {
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.WriteHeader(http.StatusOK)
+			if r.Method == http.MethodGet {
+				w.Write(data)
+			}
+			return
+		}
+		start, end := 0, len(data)-1
+		if _, err := fmt.Sscanf(rng, "bytes=-%d", &start); err == nil {
+			start = len(data) - start
+		} else if _, err := fmt.Sscanf(rng, "bytes=%d-%d", &start, &end); err != nil {
+			if _, err := fmt.Sscanf(rng, "bytes=%d-", &start); err != nil {
+				f.fail(w, r, http.StatusRequestedRangeNotSatisfiable, "InvalidRange")
+				return
+			}
+		}
+		end = min(end, len(data)-1)
+		w.Header().Set("Content-Length", strconv.Itoa(end-start+1))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(data[start : end+1])
+
+	case r.Method == http.MethodPut:
+		if !f.checkConditions(w, r, key) {
+			return
+		}
+		w.Header().Set("ETag", f.store(key, body))
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		f.fail(w, r, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeS3Service) checkConditions(w http.ResponseWriter, r *http.Request, key string) bool {
+	etag, exists := f.etags[key]
+	if r.Header.Get("If-None-Match") == "*" && exists {
+		f.fail(w, r, http.StatusPreconditionFailed, "PreconditionFailed")
+		return false
+	}
+	if m := r.Header.Get("If-Match"); m != "" && m != etag {
+		f.fail(w, r, http.StatusPreconditionFailed, "PreconditionFailed")
+		return false
+	}
+	return true
+}
+
+func (f *fakeS3Service) store(key string, data []byte) string {
+	f.nextTag++
+	f.objects[key] = data
+	f.etags[key] = fmt.Sprintf("\"%x\"", f.nextTag)
+	return f.etags[key]
+}
+
+func (f *fakeS3Service) fail(w http.ResponseWriter, r *http.Request, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+	}
+}
+
+func newFakeS3Blobstore(prefix string) (*S3Blobstore, *fakeS3Service, *httptest.Server) {
+	fake := newFakeS3Service()
+	srv := httptest.NewServer(fake)
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials:      credentials.NewStaticCredentials("id", "secret", ""),
+		Endpoint:         aws.String(srv.URL),
+		Region:           aws.String("us-east-1"),
+		S3ForcePathStyle: aws.Bool(true),
+	}))
+	return NewS3Blobstore(s3.New(sess), "bucket", prefix), fake, srv
+}
+
+func appendS3Test(tests []BlobstoreTest) []BlobstoreTest {
+	bs, _, _ := newFakeS3Blobstore(uuid.New().String() + "/")
+	return append(tests, BlobstoreTest{"s3", bs, 10, 20})
+}
+
+func TestS3Blobstore(t *testing.T) {
+	ctx := context.Background()
+	bs, _, srv := newFakeS3Blobstore("/db")
+	defer srv.Close()
+
+	assert.Equal(t, "bucket/db", bs.Path())
+
+	ok, err := bs.Exists(ctx, "manifest")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, _, err = bs.Get(ctx, "manifest", AllRange)
+	assert.True(t, IsNotFoundError(err))
+	assert.EqualError(t, err, "Blob not found: s3://bucket/db/manifest")
+
+	ver, err := bs.CheckAndPut(ctx, "", "manifest", 10, strings.NewReader("0123456789"))
+	require.NoError(t, err)
+	ok, err = bs.Exists(ctx, "manifest")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	data, getVer, err := GetBytes(ctx, bs, "manifest", NewBlobRange(-4, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "6789", string(data))
+	assert.Equal(t, ver, getVer)
+
+	_, err = bs.CheckAndPut(ctx, "", "manifest", 1, strings.NewReader("x"))
+	assert.True(t, IsCheckAndPutError(err))
+	_, err = bs.CheckAndPut(ctx, "\"stale\"", "manifest", 1, strings.NewReader("x"))
+	assert.True(t, IsCheckAndPutError(err))
+	_, err = bs.CheckAndPut(ctx, ver, "manifest", 1, strings.NewReader("x"))
+	assert.NoError(t, err)
+}
+
+func TestS3BlobstorePutStreamsParts(t *testing.T) {
+	ctx := context.Background()
+	bs, fake, srv := newFakeS3Blobstore("/db")
+	defer srv.Close()
+
+	// a reader that can't be seeked is uploaded a part at a time rather than read into memory in one piece
+	data := bytes.Repeat([]byte("0123456789abcdef"), (2*s3PartSize+1024)/16)
+	ver, err := bs.Put(ctx, "table", int64(len(data)), io.MultiReader(bytes.NewReader(data)))
+	require.NoError(t, err)
+	assert.Equal(t, 3, fake.uploadedParts)
+	read, getVer, err := GetBytes(ctx, bs, "table", AllRange)
+	require.NoError(t, err)
+	assert.Equal(t, data, read)
+	assert.Equal(t, ver, getVer)
+
+	// the conditions of a CheckAndPut apply to the completion of the upload
+	_, err = bs.CheckAndPut(ctx, "", "table", int64(len(data)), io.MultiReader(bytes.NewReader(data)))
+	assert.True(t, IsCheckAndPutError(err))
+	assert.Equal(t, 1, fake.abortedUploads)
+	assert.Empty(t, fake.uploads)
+	ver, err = bs.CheckAndPut(ctx, ver, "table", int64(len(data)), io.MultiReader(bytes.NewReader(data[:s3PartSize+1])))
+	require.NoError(t, err)
+	read, getVer, err = GetBytes(ctx, bs, "table", AllRange)
+	require.NoError(t, err)
+	assert.Equal(t, data[:s3PartSize+1], read)
+	assert.Equal(t, ver, getVer)
+
+	// small readers are written with a single put
+	fake.uploadedParts = 0
+	_, err = bs.Put(ctx, "small", 3, io.MultiReader(strings.NewReader("abc")))
+	require.NoError(t, err)
+	assert.Equal(t, 0, fake.uploadedParts)
+
+	ver, err = bs.Concatenate(ctx, "concat", []string{"small", "small"})
+	require.NoError(t, err)
+	read, getVer, err = GetBytes(ctx, bs, "concat", AllRange)
+	require.NoError(t, err)
+	assert.Equal(t, "abcabc", string(read))
+	assert.Equal(t, ver, getVer)
+}
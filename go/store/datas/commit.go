@@ -207,6 +207,21 @@ func newCommitForValue(ctx context.Context, cs chunks.ChunkStore, vrw types.Valu
 		opts.Meta = &CommitMeta{}
 	}
 
+	if opts.Sign != nil {
+		valueAddr, err := v.Hash(vrw.Format())
+		if err != nil {
+			return nil, err
+		}
+		signature, err := opts.Sign(ctx, valueAddr, opts.Parents, opts.Meta)
+		if err != nil {
+			return nil, err
+		}
+		// the meta is shared with callers which retry the commit, so sign a copy of it
+		meta := *opts.Meta
+		meta.Signature = signature
+		opts.Meta = &meta
+	}
+
 	if vrw.Format().UsesFlatbuffers() {
 		r, err := vrw.WriteValue(ctx, v)
 		if err != nil {
@@ -22,6 +22,8 @@
 package datas
 
 import (
+	"context"
+
 	"github.com/dolthub/dolt/go/store/hash"
 )
 
@@ -33,4 +35,9 @@ type CommitOptions struct {
 	Parents []hash.Hash
 
 	Meta *CommitMeta
+
+	// Sign, if provided, is called once the parents of the commit are known
+	// with the address of the committed value, the parents and the metadata
+	// of the commit. It returns the signature to store in the metadata.
+	Sign func(ctx context.Context, valueAddr hash.Hash, parents []hash.Hash, meta *CommitMeta) (string, error)
 }
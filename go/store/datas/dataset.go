@@ -213,6 +213,15 @@ type MergeState struct {
 	unmergableTables    []string
 	isCherryPick        bool
 
+	isRevert                 bool
+	revertedCommitAddrs      []hash.Hash
+	pendingRevertCommitAddrs []hash.Hash
+	revertMainline           uint16
+
+	pendingCherryPickCommitAddrs []hash.Hash
+	cherryPickMainline           uint16
+	cherryPickAllowEmpty         bool
+
 	nomsMergeStateRef *types.Ref
 	nomsMergeState    *types.Struct
 }
@@ -310,6 +319,56 @@ func (ms *MergeState) IsCherryPick(_ context.Context, vr types.ValueReader) (boo
 	return false, nil
 }
 
+// IsRevert returns whether the merge state records an in-progress revert.
+func (ms *MergeState) IsRevert(_ context.Context, vr types.ValueReader) (bool, error) {
+	if vr.Format().UsesFlatbuffers() {
+		return ms.isRevert, nil
+	}
+	return false, nil
+}
+
+// RevertedCommitAddrs returns the addresses of the commits an in-progress revert reverted before its commit.
+func (ms *MergeState) RevertedCommitAddrs(_ context.Context, vr types.ValueReader) ([]hash.Hash, error) {
+	if vr.Format().UsesFlatbuffers() {
+		return ms.revertedCommitAddrs, nil
+	}
+	return nil, nil
+}
+
+// PendingRevertCommitAddrs returns the addresses of the commits an in-progress revert reverts after its commit.
+func (ms *MergeState) PendingRevertCommitAddrs(_ context.Context, vr types.ValueReader) ([]hash.Hash, error) {
+	if vr.Format().UsesFlatbuffers() {
+		return ms.pendingRevertCommitAddrs, nil
+	}
+	return nil, nil
+}
+
+// RevertMainline returns the parent number merge commits are reverted relative to by an in-progress revert.
+func (ms *MergeState) RevertMainline(_ context.Context, vr types.ValueReader) (uint16, error) {
+	if vr.Format().UsesFlatbuffers() {
+		return ms.revertMainline, nil
+	}
+	return 0, nil
+}
+
+// PendingCherryPickCommitAddrs returns the addresses of the commits an in-progress cherry-pick applies after its
+// commit.
+func (ms *MergeState) PendingCherryPickCommitAddrs(_ context.Context, vr types.ValueReader) ([]hash.Hash, error) {
+	if vr.Format().UsesFlatbuffers() {
+		return ms.pendingCherryPickCommitAddrs, nil
+	}
+	return nil, nil
+}
+
+// CherryPickOptions returns the parent number merge commits are cherry-picked relative to by an in-progress
+// cherry-pick, and whether it keeps commits that are empty to begin with.
+func (ms *MergeState) CherryPickOptions(_ context.Context, vr types.ValueReader) (uint16, bool, error) {
+	if vr.Format().UsesFlatbuffers() {
+		return ms.cherryPickMainline, ms.cherryPickAllowEmpty, nil
+	}
+	return 0, false, nil
+}
+
 func (ms *MergeState) UnmergableTables(ctx context.Context, vr types.ValueReader) ([]string, error) {
 	if vr.Format().UsesFlatbuffers() {
 		return ms.unmergableTables, nil
@@ -376,6 +435,7 @@ func (h serialTagHead) HeadTag() (*TagMeta, hash.Hash, error) {
 		Timestamp:     h.msg.TimestampMillis(),
 		Description:   string(h.msg.Desc()),
 		UserTimestamp: h.msg.UserTimestampMillis(),
+		Signature:     string(h.msg.Signature()),
 	}
 	return meta, addr, nil
 }
@@ -442,7 +502,18 @@ func (h serialWorkingSetHead) HeadWorkingSet() (*WorkingSetHead, error) {
 		for i := range ret.MergeState.unmergableTables {
 			ret.MergeState.unmergableTables[i] = string(mergeState.UnmergableTables(i))
 		}
-		ret.MergeState.isCherryPick = mergeState.IsCherryPick()
+		ret.MergeState.isCherryPick = mergeState.IsCherryPick() && !mergeState.IsRevert()
+		if mergeState.IsRevert() {
+			ret.MergeState.isRevert = true
+			ret.MergeState.revertedCommitAddrs = splitAddrs(mergeState.RevertedCommitAddrsBytes())
+			ret.MergeState.pendingRevertCommitAddrs = splitAddrs(mergeState.PendingRevertCommitAddrsBytes())
+			ret.MergeState.revertMainline = mergeState.RevertMainline()
+		}
+		if ret.MergeState.isCherryPick {
+			ret.MergeState.pendingCherryPickCommitAddrs = splitAddrs(mergeState.PendingCherryPickCommitAddrsBytes())
+			ret.MergeState.cherryPickMainline = mergeState.CherryPickMainline()
+			ret.MergeState.cherryPickAllowEmpty = mergeState.CherryPickAllowEmpty()
+		}
 	}
 
 	rebaseState, err := h.msg.TryRebaseState(nil)
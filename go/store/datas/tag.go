@@ -114,11 +114,14 @@ func newTag(ctx context.Context, db *database, commitAddr hash.Hash, meta *TagMe
 func tag_flatbuffer(commitAddr hash.Hash, meta *TagMeta) serial.Message {
 	builder := flatbuffers.NewBuilder(1024)
 	addroff := builder.CreateByteVector(commitAddr[:])
-	var nameOff, emailOff, descOff flatbuffers.UOffsetT
+	var nameOff, emailOff, descOff, sigOff flatbuffers.UOffsetT
 	if meta != nil {
 		nameOff = builder.CreateString(meta.Name)
 		emailOff = builder.CreateString(meta.Email)
 		descOff = builder.CreateString(meta.Description)
+		if meta.Signature != "" {
+			sigOff = builder.CreateString(meta.Signature)
+		}
 	}
 	serial.TagStart(builder)
 	serial.TagAddCommitAddr(builder, addroff)
@@ -128,6 +131,10 @@ func tag_flatbuffer(commitAddr hash.Hash, meta *TagMeta) serial.Message {
 		serial.TagAddDesc(builder, descOff)
 		serial.TagAddTimestampMillis(builder, meta.Timestamp)
 		serial.TagAddUserTimestampMillis(builder, meta.UserTimestamp)
+		// Unsigned tags leave the field unset, so that they can still be read by older clients.
+		if meta.Signature != "" {
+			serial.TagAddSignature(builder, sigOff)
+		}
 	}
 	return serial.FinishMessage(builder, serial.TagEnd(builder), []byte(serial.TagFileID))
 }
@@ -175,12 +175,33 @@ func workingset_flatbuffer(working hash.Hash, staged *hash.Hash, mergeState *Mer
 		fromaddroff := builder.CreateByteVector((*mergeState.fromCommitAddr)[:])
 		fromspecoff := builder.CreateString(mergeState.fromCommitSpec)
 		unmergableoff := SerializeStringVector(builder, mergeState.unmergableTables)
+		var revertedoff, pendingoff flatbuffers.UOffsetT
+		if mergeState.isRevert {
+			revertedoff = builder.CreateByteVector(concatAddrs(mergeState.revertedCommitAddrs))
+			pendingoff = builder.CreateByteVector(concatAddrs(mergeState.pendingRevertCommitAddrs))
+		} else if len(mergeState.pendingCherryPickCommitAddrs) > 0 {
+			pendingoff = builder.CreateByteVector(concatAddrs(mergeState.pendingCherryPickCommitAddrs))
+		}
 		serial.MergeStateStart(builder)
 		serial.MergeStateAddPreWorkingRootAddr(builder, prerootaddroff)
 		serial.MergeStateAddFromCommitAddr(builder, fromaddroff)
 		serial.MergeStateAddFromCommitSpecStr(builder, fromspecoff)
 		serial.MergeStateAddUnmergableTables(builder, unmergableoff)
-		serial.MergeStateAddIsCherryPick(builder, mergeState.isCherryPick)
+		// Reverts also record themselves as cherry-picks, so that clients that don't know about reverts still create
+		// a commit with a single parent when the revert is concluded.
+		serial.MergeStateAddIsCherryPick(builder, mergeState.isCherryPick || mergeState.isRevert)
+		if mergeState.isRevert {
+			serial.MergeStateAddIsRevert(builder, true)
+			serial.MergeStateAddRevertedCommitAddrs(builder, revertedoff)
+			serial.MergeStateAddPendingRevertCommitAddrs(builder, pendingoff)
+			serial.MergeStateAddRevertMainline(builder, mergeState.revertMainline)
+		} else if mergeState.isCherryPick {
+			if len(mergeState.pendingCherryPickCommitAddrs) > 0 {
+				serial.MergeStateAddPendingCherryPickCommitAddrs(builder, pendingoff)
+			}
+			serial.MergeStateAddCherryPickMainline(builder, mergeState.cherryPickMainline)
+			serial.MergeStateAddCherryPickAllowEmpty(builder, mergeState.cherryPickAllowEmpty)
+		}
 		mergeStateOff = serial.MergeStateEnd(builder)
 	}
 
@@ -264,6 +285,46 @@ func NewMergeState(
 	}
 }
 
+// WithRevert returns a copy of |ms| that records an in-progress revert of its commit, along with the commits that
+// were reverted before it, the commits still to be reverted after it, and the parent number merge commits are
+// reverted relative to. Reverts are only recorded for the flatbuffers format.
+func (ms *MergeState) WithRevert(reverted, pending []hash.Hash, mainline uint16) *MergeState {
+	ret := *ms
+	ret.isRevert = true
+	ret.revertedCommitAddrs = reverted
+	ret.pendingRevertCommitAddrs = pending
+	ret.revertMainline = mainline
+	return &ret
+}
+
+// WithCherryPick returns a copy of |ms| that records the commits an in-progress cherry-pick still has to apply after
+// its commit, the parent number merge commits are cherry-picked relative to, and whether commits that are empty to
+// begin with are kept. These are only recorded for the flatbuffers format.
+func (ms *MergeState) WithCherryPick(pending []hash.Hash, mainline uint16, allowEmpty bool) *MergeState {
+	ret := *ms
+	ret.isCherryPick = true
+	ret.pendingCherryPickCommitAddrs = pending
+	ret.cherryPickMainline = mainline
+	ret.cherryPickAllowEmpty = allowEmpty
+	return &ret
+}
+
+func concatAddrs(addrs []hash.Hash) []byte {
+	bs := make([]byte, 0, len(addrs)*hash.ByteLen)
+	for _, addr := range addrs {
+		bs = append(bs, addr[:]...)
+	}
+	return bs
+}
+
+func splitAddrs(bs []byte) []hash.Hash {
+	addrs := make([]hash.Hash, len(bs)/hash.ByteLen)
+	for i := range addrs {
+		addrs[i] = hash.New(bs[i*hash.ByteLen : (i+1)*hash.ByteLen])
+	}
+	return addrs
+}
+
 func NewRebaseState(preRebaseWorkingRoot hash.Hash, commitAddr hash.Hash, branch string, commitBecomesEmptyHandling uint8, emptyCommitHandling uint8, lastAttemptedStep float32, rebasingStarted bool) *RebaseState {
 	return &RebaseState{
 		preRebaseWorkingAddr:       &preRebaseWorkingRoot,
@@ -0,0 +1,92 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRevertMergeState(t *testing.T) {
+	working, preMerge, from := hash.Of([]byte("working")), hash.Of([]byte("pre merge")), hash.Of([]byte("from"))
+	reverted := []hash.Hash{hash.Of([]byte("reverted 1")), hash.Of([]byte("reverted 2"))}
+	pending := []hash.Hash{hash.Of([]byte("pending"))}
+	ms := &MergeState{preMergeWorkingAddr: &preMerge, fromCommitAddr: &from, fromCommitSpec: "HEAD~1"}
+	ms = ms.WithRevert(reverted, pending, 2)
+
+	msg := workingset_flatbuffer(working, nil, ms, nil, &WorkingSetMeta{Name: "name", Email: "email"})
+
+	// the commits of the revert are reachable from the working set
+	var addrs []hash.Hash
+	err := types.SerialMessage(msg).WalkAddrs(types.Format_DOLT, func(addr hash.Hash) error {
+		addrs = append(addrs, addr)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []hash.Hash{working, preMerge, from, reverted[0], reverted[1], pending[0]}, addrs)
+
+	// clients that don't know about reverts see a cherry-pick
+	fb, err := serial.TryGetRootAsWorkingSet(msg, serial.MessagePrefixSz)
+	require.NoError(t, err)
+	fbMergeState, err := fb.TryMergeState(nil)
+	require.NoError(t, err)
+	assert.True(t, fbMergeState.IsCherryPick())
+
+	head, err := newSerialWorkingSetHead(msg, hash.Of(msg))
+	require.NoError(t, err)
+	wsHead, err := head.HeadWorkingSet()
+	require.NoError(t, err)
+	read := wsHead.MergeState
+	assert.False(t, read.isCherryPick)
+	assert.True(t, read.isRevert)
+	assert.Equal(t, reverted, read.revertedCommitAddrs)
+	assert.Equal(t, pending, read.pendingRevertCommitAddrs)
+	assert.Equal(t, uint16(2), read.revertMainline)
+}
+
+func TestCherryPickMergeState(t *testing.T) {
+	working, preMerge, from := hash.Of([]byte("working")), hash.Of([]byte("pre merge")), hash.Of([]byte("from"))
+	pending := []hash.Hash{hash.Of([]byte("pending 1")), hash.Of([]byte("pending 2"))}
+	ms := &MergeState{preMergeWorkingAddr: &preMerge, fromCommitAddr: &from, fromCommitSpec: "HEAD~1"}
+	ms = ms.WithCherryPick(pending, 1, true)
+
+	msg := workingset_flatbuffer(working, nil, ms, nil, &WorkingSetMeta{Name: "name", Email: "email"})
+
+	// the commits still to be cherry-picked are reachable from the working set
+	var addrs []hash.Hash
+	err := types.SerialMessage(msg).WalkAddrs(types.Format_DOLT, func(addr hash.Hash) error {
+		addrs = append(addrs, addr)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []hash.Hash{working, preMerge, from, pending[0], pending[1]}, addrs)
+
+	head, err := newSerialWorkingSetHead(msg, hash.Of(msg))
+	require.NoError(t, err)
+	wsHead, err := head.HeadWorkingSet()
+	require.NoError(t, err)
+	read := wsHead.MergeState
+	assert.True(t, read.isCherryPick)
+	assert.False(t, read.isRevert)
+	assert.Equal(t, pending, read.pendingCherryPickCommitAddrs)
+	assert.Equal(t, uint16(1), read.cherryPickMainline)
+	assert.True(t, read.cherryPickAllowEmpty)
+}
@@ -110,6 +110,12 @@ func TestReadRecordRanges(t *testing.T) {
 
 	ranges, err := jcs.getRecordRanges(ctx, gets)
 	require.NoError(t, err)
+	assert.Len(t, ranges, len(data))
+
+	// the records are marked found, so later chunk sources don't report them again
+	for _, g := range gets {
+		assert.True(t, g.found)
+	}
 
 	for h, rng := range ranges {
 		b, err := jcs.get(ctx, h, &Stats{})
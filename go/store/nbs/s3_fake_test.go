@@ -24,7 +24,10 @@ package nbs
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -33,6 +36,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/client/metadata"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -247,9 +251,30 @@ func (m *fakeS3) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput,
 	return &s3.GetObjectOutput{
 		Body:          io.NopCloser(bytes.NewReader(obj)),
 		ContentLength: aws.Int64(int64(len(obj))),
+		ETag:          aws.String(fakeETag(m.data[*input.Key])),
 	}, nil
 }
 
+func (m *fakeS3) HeadObjectWithContext(ctx aws.Context, input *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	m.assert.NotNil(input.Bucket, "Bucket is a required field")
+	m.assert.NotNil(input.Key, "Key is a required field")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, present := m.data[*input.Key]
+	if !present {
+		return nil, mockAWSError("NotFound")
+	}
+	return &s3.HeadObjectOutput{
+		ContentLength: aws.Int64(int64(len(obj))),
+		ETag:          aws.String(fakeETag(obj)),
+	}, nil
+}
+
+func fakeETag(data []byte) string {
+	return fmt.Sprintf("\"%x\"", md5.Sum(data))
+}
+
 func parseRange(hdr string, total int) (start, end int) {
 	d.PanicIfFalse(len(hdr) > len(s3RangePrefix))
 	hdr = hdr[len(s3RangePrefix):]
@@ -274,14 +299,27 @@ func (m *fakeS3) PutObjectWithContext(ctx aws.Context, input *s3.PutObjectInput,
 	m.assert.NotNil(input.Bucket, "Bucket is a required field")
 	m.assert.NotNil(input.Key, "Key is a required field")
 
+	// Collect any headers set by |opts|, for conditional puts.
+	r := &request.Request{HTTPRequest: &http.Request{Header: http.Header{}}}
+	r.ApplyOptions(opts...)
+	r.Handlers.Build.Run(r)
+
 	buff := &bytes.Buffer{}
 	_, err := io.Copy(buff, input.Body)
 	m.assert.NoError(err)
 	m.mu.Lock()
 	defer m.mu.Unlock()
+
+	existing, present := m.data[*input.Key]
+	if r.HTTPRequest.Header.Get("If-None-Match") == "*" && present {
+		return nil, awserr.NewRequestFailure(mockAWSError("PreconditionFailed"), http.StatusPreconditionFailed, "")
+	}
+	if ifMatch := r.HTTPRequest.Header.Get("If-Match"); ifMatch != "" && (!present || ifMatch != fakeETag(existing)) {
+		return nil, awserr.NewRequestFailure(mockAWSError("PreconditionFailed"), http.StatusPreconditionFailed, "")
+	}
 	m.data[*input.Key] = buff.Bytes()
 
-	return &s3.PutObjectOutput{}, nil
+	return &s3.PutObjectOutput{ETag: aws.String(fakeETag(buff.Bytes()))}, nil
 }
 
 func (m *fakeS3) GetObjectRequest(input *s3.GetObjectInput) (*request.Request, *s3.GetObjectOutput) {
@@ -532,6 +532,24 @@ func NewAWSStore(ctx context.Context, nbfVerStr string, table, ns, bucket string
 	return newNomsBlockStore(ctx, nbfVerStr, mm, p, q, inlineConjoiner{defaultMaxTables}, memTableSize)
 }
 
+// NewS3Store returns an nbs implementation which keeps both its table files and its manifest in |bucket| under the
+// prefix |ns|. Unlike NewAWSStore, it does not need a DynamoDB table: manifest updates are made with conditional
+// puts, so it works with any S3 compatible store which supports If-Match and If-None-Match on PutObject.
+func NewS3Store(ctx context.Context, nbfVerStr string, bucket, ns string, s3 s3iface.S3API, memTableSize uint64, q MemoryQuotaProvider) (*NomsBlockStore, error) {
+	cacheOnce.Do(makeGlobalCaches)
+	readRateLimiter := make(chan struct{}, 32)
+	p := &awsTablePersister{
+		s3,
+		bucket,
+		readRateLimiter,
+		awsLimits{defaultS3PartSize, minS3PartSize, maxS3PartSize},
+		ns,
+		q,
+	}
+	mm := makeManifestManager(blobstoreManifest{blobstore.NewS3Blobstore(s3, bucket, ns)})
+	return newNomsBlockStore(ctx, nbfVerStr, mm, p, q, inlineConjoiner{defaultMaxTables}, memTableSize)
+}
+
 // NewGCSStore returns an nbs implementation backed by a GCSBlobstore
 func NewGCSStore(ctx context.Context, nbfVerStr string, bucketName, path string, gcs *storage.Client, memTableSize uint64, q MemoryQuotaProvider) (*NomsBlockStore, error) {
 	cacheOnce.Do(makeGlobalCaches)
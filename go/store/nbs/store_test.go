@@ -659,3 +659,46 @@ func TestGuessPrefixOrdinal(t *testing.T) {
 		assert.Equal(t, i, guess)
 	}
 }
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+	s3svc := makeFakeS3(t)
+	q := NewUnlimitedMemQuotaProvider()
+	store, err := NewS3Store(ctx, types.Format_Default.VersionString(), "bucket", "db", s3svc, defaultMemTableSize, q)
+	require.NoError(t, err)
+	defer store.Close()
+
+	rootChunk := chunks.NewChunk([]byte("root"))
+	require.NoError(t, store.Put(ctx, rootChunk, noopGetAddrs))
+	ok, err := store.Commit(ctx, rootChunk.Hash(), hash.Hash{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Contains(t, s3svc.data, "db/manifest")
+
+	// A second store sees the committed root and its chunks.
+	other, err := NewS3Store(ctx, types.Format_Default.VersionString(), "bucket", "db", s3svc, defaultMemTableSize, q)
+	require.NoError(t, err)
+	defer other.Close()
+	root, err := other.Root(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, rootChunk.Hash(), root)
+	has, err := other.Has(ctx, rootChunk.Hash())
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	// Advance the root through |other|, after which a commit through |store| based on its stale root must fail.
+	next := chunks.NewChunk([]byte("next"))
+	require.NoError(t, other.Put(ctx, next, noopGetAddrs))
+	ok, err = other.Commit(ctx, next.Hash(), root)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	stale := chunks.NewChunk([]byte("stale"))
+	require.NoError(t, store.Put(ctx, stale, noopGetAddrs))
+	ok, err = store.Commit(ctx, stale.Hash(), root)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	root, err = store.Root(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, next.Hash(), root)
+}
@@ -47,8 +47,20 @@ const (
 
 const (
 	artifactMapPendingBufferSize = 650_000
+
+	// artifactKeySuffixFieldCount is the number of fields that follow the source key fields in an artifact key: the
+	// source rootish, the artifact type and the violation info hash.
+	artifactKeySuffixFieldCount = 3
+
+	hash128Size = 16
 )
 
+// ArtifactKeySourceFieldCount returns the number of leading fields of an artifact key described by |artKD| that hold
+// the key of the source row.
+func ArtifactKeySourceFieldCount(artKD val.TupleDesc) int {
+	return artKD.Count() - artifactKeySuffixFieldCount
+}
+
 type ArtifactMap struct {
 	tuples tree.StaticMap[val.Tuple, val.Tuple, val.TupleDesc]
 	// the description of the source table where these artifacts come from
@@ -364,24 +376,29 @@ func (wr *ArtifactsEditor) Add(ctx context.Context, srcKey val.Tuple, srcRootish
 	return wr.mut.Put(ctx, key, value)
 }
 
+// buildConstraintViolationKey builds the artifact key for a constraint violation. In addition to the fields of
+// BuildArtifactKey, the key includes a hash of the violation info, |vInfo|, so that a single row can carry several
+// independent violations of the same type.
+func (wr *ArtifactsEditor) buildConstraintViolationKey(srcKey val.Tuple, srcRootish hash.Hash, artType ArtifactType, vInfo []byte) val.Tuple {
+	for i := 0; i < srcKey.Count(); i++ {
+		wr.artKB.PutRaw(i, srcKey.GetField(i))
+	}
+	wr.artKB.PutCommitAddr(srcKey.Count(), srcRootish)
+	wr.artKB.PutUint8(srcKey.Count()+1, uint8(artType))
+	h := hash.Of(vInfo)
+	wr.artKB.PutHash128(srcKey.Count()+2, h[:hash128Size])
+	return wr.artKB.Build(wr.pool)
+}
+
 // ReplaceConstraintViolation replaces constraint violations that match the
 // given one but have a different commit hash. If no existing violation exists,
-// the given will be inserted. Returns true if a violation was replaced. If an
-// existing violation exists but has a different |meta.VInfo| value then
-// ErrMergeArtifactCollision is a returned.
+// the given will be inserted. A row may carry several violations of the same
+// type, as long as their |meta.VInfo| values differ.
 func (wr *ArtifactsEditor) ReplaceConstraintViolation(ctx context.Context, srcKey val.Tuple, srcRootish hash.Hash, artType ArtifactType, meta ConstraintViolationMeta) error {
-	itr, err := wr.mut.IterRange(ctx, PrefixRange(srcKey, wr.srcKeyDesc))
+	aItr, err := wr.iterSourceKey(ctx, srcKey)
 	if err != nil {
 		return err
 	}
-	aItr := artifactIterImpl{
-		itr:    itr,
-		artKD:  wr.mut.keyDesc,
-		artVD:  wr.mut.valDesc,
-		pool:   wr.pool,
-		tb:     val.NewTupleBuilder(wr.srcKeyDesc),
-		numPks: wr.srcKeyDesc.Count(),
-	}
 
 	var art Artifact
 	var currMeta ConstraintViolationMeta
@@ -399,11 +416,8 @@ func (wr *ArtifactsEditor) ReplaceConstraintViolation(ctx context.Context, srcKe
 			return err
 		}
 
-		if bytes.Compare(currMeta.Value, meta.Value) == 0 {
-			if bytes.Compare(currMeta.VInfo, meta.VInfo) != 0 {
-				return artifactCollisionErr(srcKey, wr.srcKeyDesc, currMeta.VInfo, meta.VInfo)
-			}
-			// Key and Value is the same, so delete this
+		if bytes.Compare(currMeta.Value, meta.Value) == 0 && bytes.Compare(currMeta.VInfo, meta.VInfo) == 0 {
+			// Key, Value and violation info are the same, so delete this
 			err = wr.Delete(ctx, art.ArtKey)
 			if err != nil {
 				return err
@@ -418,17 +432,78 @@ func (wr *ArtifactsEditor) ReplaceConstraintViolation(ctx context.Context, srcKe
 	if err != nil {
 		return err
 	}
-	err = wr.Add(ctx, srcKey, srcRootish, artType, d)
+
+	key := wr.buildConstraintViolationKey(srcKey, srcRootish, artType, meta.VInfo)
+	wr.artVB.PutJSON(0, d)
+	value := wr.artVB.Build(wr.pool)
+
+	return wr.mut.Put(ctx, key, value)
+}
+
+// DeleteConstraintViolations deletes the constraint violations of |artType| recorded from |srcRootish| for the row
+// identified by |srcKey|. If |match| is non-nil, only the violations for which it returns true are deleted. Returns
+// the number of deleted violations.
+func (wr *ArtifactsEditor) DeleteConstraintViolations(ctx context.Context, srcKey val.Tuple, srcRootish hash.Hash, artType ArtifactType, match func(meta ConstraintViolationMeta) (bool, error)) (int, error) {
+	aItr, err := wr.iterSourceKey(ctx, srcKey)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	var toDelete []val.Tuple
+	var art Artifact
+	for art, err = aItr.Next(ctx); err == nil; art, err = aItr.Next(ctx) {
+		// prefix scanning sometimes returns keys not in the range
+		if bytes.Compare(art.SourceKey, srcKey) != 0 {
+			continue
+		}
+		if art.ArtType != artType || art.SourceRootish != srcRootish {
+			continue
+		}
+
+		if match != nil {
+			var meta ConstraintViolationMeta
+			err = json.Unmarshal(art.Metadata, &meta)
+			if err != nil {
+				return 0, err
+			}
+			ok, err := match(meta)
+			if err != nil {
+				return 0, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		toDelete = append(toDelete, art.ArtKey)
+	}
+	if err != io.EOF {
+		return 0, err
 	}
 
-	return nil
+	for _, k := range toDelete {
+		err = wr.Delete(ctx, k)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(toDelete), nil
 }
 
-func artifactCollisionErr(key val.Tuple, desc val.TupleDesc, old, new []byte) error {
-	return fmt.Errorf("error storing constraint violation for primary key (%s): another violation already exists\n"+
-		"new violation: %s old violation: (%s)", desc.Format(key), string(old), string(new))
+// iterSourceKey returns an iterator over the artifacts of the row identified by |srcKey|, including in-flight edits.
+func (wr *ArtifactsEditor) iterSourceKey(ctx context.Context, srcKey val.Tuple) (ArtifactIter, error) {
+	itr, err := wr.mut.IterRange(ctx, PrefixRange(srcKey, wr.srcKeyDesc))
+	if err != nil {
+		return nil, err
+	}
+	return artifactIterImpl{
+		itr:    itr,
+		artKD:  wr.mut.keyDesc,
+		artVD:  wr.mut.valDesc,
+		pool:   wr.pool,
+		tb:     val.NewTupleBuilder(wr.srcKeyDesc),
+		numPks: wr.srcKeyDesc.Count(),
+	}, nil
 }
 
 func (wr *ArtifactsEditor) Delete(ctx context.Context, key val.Tuple) error {
@@ -608,7 +683,7 @@ type Artifact struct {
 
 func mergeArtifactsDescriptorsFromSource(srcKd val.TupleDesc) (kd, vd val.TupleDesc) {
 	// artifact key consists of keys of source schema, followed by target branch
-	// commit hash, and artifact type.
+	// commit hash, artifact type, and a hash of the violation info.
 	keyTypes := srcKd.Types
 
 	// source branch commit hash
@@ -617,6 +692,14 @@ func mergeArtifactsDescriptorsFromSource(srcKd val.TupleDesc) (kd, vd val.TupleD
 	// artifact type
 	keyTypes = append(keyTypes, val.Type{Enc: val.Uint8Enc, Nullable: false})
 
+	// violation info hash, which distinguishes several constraint violations of
+	// the same type for one row. It is NULL for conflicts, so that those keys
+	// are unchanged by this trailing field. Artifacts written before feature
+	// version 9 don't have it, so their violations read it as NULL too. Older
+	// clients can't tell the violations of a row apart, so writing these keys
+	// requires feature version 9.
+	keyTypes = append(keyTypes, val.Type{Enc: val.Hash128Enc, Nullable: true})
+
 	// json blob data
 	valTypes := []val.Type{{Enc: val.JSONEnc, Nullable: false}}
 
@@ -16,7 +16,9 @@ package prolly
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -135,3 +137,151 @@ func TestMergeArtifactMaps(t *testing.T) {
 
 	assert.Equal(t, es, ms)
 }
+
+func TestMultipleConstraintViolationsPerRow(t *testing.T) {
+	var srcKd = val.NewTupleDescriptor(val.Type{Enc: val.Int16Enc})
+	var srcKb = val.NewTupleBuilder(srcKd)
+
+	ctx := context.Background()
+	ns := tree.NewTestNodeStore()
+
+	am, err := NewArtifactMapFromTuples(ctx, ns, srcKd)
+	require.NoError(t, err)
+
+	addr1, err := ns.Write(ctx, tree.NewEmptyTestNode())
+	require.NoError(t, err)
+	addr2 := hash.Of([]byte("other root"))
+
+	srcKb.PutInt16(0, 1)
+	key := srcKb.Build(sharedPool)
+	value := []byte("value")
+	meta1 := ConstraintViolationMeta{VInfo: []byte(`{"Name":"idx1"}`), Value: value}
+	meta2 := ConstraintViolationMeta{VInfo: []byte(`{"Name":"idx2"}`), Value: value}
+
+	edt := am.Editor()
+	require.NoError(t, edt.ReplaceConstraintViolation(ctx, key, addr1, ArtifactTypeUniqueKeyViol, meta1))
+	require.NoError(t, edt.ReplaceConstraintViolation(ctx, key, addr1, ArtifactTypeUniqueKeyViol, meta2))
+	// recording an existing violation from another root replaces it
+	require.NoError(t, edt.ReplaceConstraintViolation(ctx, key, addr2, ArtifactTypeUniqueKeyViol, meta1))
+	// a conflict for the same row is independent of its violations
+	require.NoError(t, edt.Add(ctx, key, addr1, ArtifactTypeConflict, []byte("{}")))
+	am, err = edt.Flush(ctx)
+	require.NoError(t, err)
+
+	cnt, err := am.CountOfType(ctx, ArtifactTypeUniqueKeyViol)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), cnt)
+	cnt, err = am.CountOfType(ctx, ArtifactTypeConflict)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), cnt)
+
+	edt = am.Editor()
+	deleted, err := edt.DeleteConstraintViolations(ctx, key, addr1, ArtifactTypeUniqueKeyViol, func(meta ConstraintViolationMeta) (bool, error) {
+		return string(meta.VInfo) == string(meta2.VInfo), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	am, err = edt.Flush(ctx)
+	require.NoError(t, err)
+
+	itr, err := am.IterAllCVs(ctx)
+	require.NoError(t, err)
+	art, err := itr.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, addr2, art.SourceRootish)
+	_, err = itr.Next(ctx)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReadArtifactsWithoutViolationInfoHash(t *testing.T) {
+	var srcKd = val.NewTupleDescriptor(val.Type{Enc: val.Int16Enc})
+	var srcKb = val.NewTupleBuilder(srcKd)
+
+	// artifact keys written before feature version 9 end with the artifact type
+	oldKd := val.NewTupleDescriptor(
+		val.Type{Enc: val.Int16Enc},
+		val.Type{Enc: val.CommitAddrEnc},
+		val.Type{Enc: val.Uint8Enc},
+	)
+	oldKb := val.NewTupleBuilder(oldKd)
+	vb := val.NewTupleBuilder(val.NewTupleDescriptor(val.Type{Enc: val.JSONEnc}))
+
+	ctx := context.Background()
+	ns := tree.NewTestNodeStore()
+
+	addr1, err := ns.Write(ctx, tree.NewEmptyTestNode())
+	require.NoError(t, err)
+	addr2 := hash.Of([]byte("other root"))
+
+	meta := ConstraintViolationMeta{VInfo: []byte(`{"Name":"idx1"}`), Value: []byte("value")}
+	metaJSON, err := json.Marshal(meta)
+	require.NoError(t, err)
+
+	oldKb.PutInt16(0, 1)
+	oldKb.PutCommitAddr(1, addr1)
+	oldKb.PutUint8(2, uint8(ArtifactTypeUniqueKeyViol))
+	violationKey := oldKb.Build(sharedPool)
+	vb.PutJSON(0, metaJSON)
+	violationValue := vb.Build(sharedPool)
+
+	oldKb.PutInt16(0, 2)
+	oldKb.PutCommitAddr(1, addr1)
+	oldKb.PutUint8(2, uint8(ArtifactTypeConflict))
+	conflictKey := oldKb.Build(sharedPool)
+	vb.PutJSON(0, []byte("{}"))
+	conflictValue := vb.Build(sharedPool)
+
+	am, err := NewArtifactMapFromTuples(ctx, ns, srcKd, violationKey, violationValue, conflictKey, conflictValue)
+	require.NoError(t, err)
+
+	srcKb.PutInt16(0, 1)
+	key1 := srcKb.Build(sharedPool)
+	srcKb.PutInt16(0, 2)
+	key2 := srcKb.Build(sharedPool)
+
+	itr, err := am.IterAllCVs(ctx)
+	require.NoError(t, err)
+	art, err := itr.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, key1, art.SourceKey)
+	assert.Equal(t, addr1, art.SourceRootish)
+	assert.Equal(t, ArtifactTypeUniqueKeyViol, art.ArtType)
+	assert.Equal(t, metaJSON, art.Metadata)
+	_, err = itr.Next(ctx)
+	assert.Equal(t, io.EOF, err)
+
+	// conflict keys are the same in both layouts
+	edt := am.Editor()
+	ok, err := am.Has(ctx, edt.BuildArtifactKey(ctx, key2, addr1, ArtifactTypeConflict))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// recording the same violation from another root replaces the old key
+	require.NoError(t, edt.ReplaceConstraintViolation(ctx, key1, addr2, ArtifactTypeUniqueKeyViol, meta))
+	am, err = edt.Flush(ctx)
+	require.NoError(t, err)
+	cnt, err := am.CountOfType(ctx, ArtifactTypeUniqueKeyViol)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), cnt)
+	itr, err = am.IterAllCVs(ctx)
+	require.NoError(t, err)
+	art, err = itr.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, addr2, art.SourceRootish)
+
+	// and old keys can be deleted
+	oldKb.PutInt16(0, 1)
+	oldKb.PutCommitAddr(1, addr1)
+	oldKb.PutUint8(2, uint8(ArtifactTypeUniqueKeyViol))
+	am, err = NewArtifactMapFromTuples(ctx, ns, srcKd, oldKb.Build(sharedPool), violationValue)
+	require.NoError(t, err)
+	edt = am.Editor()
+	deleted, err := edt.DeleteConstraintViolations(ctx, key1, addr1, ArtifactTypeUniqueKeyViol, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	am, err = edt.Flush(ctx)
+	require.NoError(t, err)
+	n, err := am.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
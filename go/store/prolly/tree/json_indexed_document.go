@@ -172,6 +172,11 @@ func (i IndexedJsonDocument) lookupByLocation(ctx context.Context, path jsonLoca
 	return types.NewLazyJSONDocument(valueBytes), nil
 }
 
+// LookupWithKey returns the value at the location encoded in |key|, or nil if the document has no value there.
+func (i IndexedJsonDocument) LookupWithKey(ctx context.Context, key []byte) (sql.JSONWrapper, error) {
+	return i.lookupByLocation(ctx, jsonPathFromKey(key))
+}
+
 // Insert implements types.MutableJSON
 func (i IndexedJsonDocument) Insert(ctx context.Context, path string, val sql.JSONWrapper) (result types.MutableJSON, changed bool, err error) {
 	err = tryWithFallback(
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/mohae/uvarint"
 )
@@ -211,6 +212,61 @@ func JsonKeysModifySameArray(leftKey, rightKey []byte) bool {
 	return false
 }
 
+// SharedJsonArrayKey returns the key of the outermost array that contains the locations encoded in both |leftKey| and
+// |rightKey|, if there is one.
+func SharedJsonArrayKey(leftKey, rightKey []byte) ([]byte, bool) {
+	left, right := jsonPathFromKey(leftKey), jsonPathFromKey(rightKey)
+	for i := 0; i < left.size() && i < right.size(); i++ {
+		leftElement, rightElement := left.getPathElement(i), right.getPathElement(i)
+		if leftElement.isArrayIndex && rightElement.isArrayIndex {
+			arrayKey := bytes.Clone(leftKey[:left.offsets[i]])
+			arrayKey[0] = byte(startOfValue)
+			return arrayKey, true
+		}
+		if leftElement.isArrayIndex != rightElement.isArrayIndex || !bytes.Equal(leftElement.key, rightElement.key) {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// JsonKeyToPath returns the MySQL JSON path expression of the location encoded in |key|, such as $.a[1]."b c".
+func JsonKeyToPath(key []byte) string {
+	if len(key) == 0 {
+		return "$"
+	}
+	location := jsonPathFromKey(key)
+	sb := strings.Builder{}
+	sb.WriteByte('$')
+	for i := 0; i < location.size(); i++ {
+		element := location.getPathElement(i)
+		if element.isArrayIndex {
+			sb.WriteString(fmt.Sprintf("[%d]", element.getArrayIndex()))
+		} else if isIdentifierJsonPathKey(element.key) {
+			sb.WriteByte('.')
+			sb.Write(element.key)
+		} else {
+			sb.WriteString(`."`)
+			sb.Write(escapeKey(element.key))
+			sb.WriteByte('"')
+		}
+	}
+	return sb.String()
+}
+
+// isIdentifierJsonPathKey returns whether |key| can appear in a JSON path expression without being quoted.
+func isIdentifierJsonPathKey(key []byte) bool {
+	if len(key) == 0 || (key[0] >= '0' && key[0] <= '9') {
+		return false
+	}
+	for _, c := range key {
+		if !(c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
 func jsonPathElementsFromMySQLJsonPath(pathBytes []byte) (jsonLocation, error) {
 	location := newRootLocation()
 	state := lexStatePath
@@ -0,0 +1,73 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJsonKeyToPath(t *testing.T) {
+	paths := []string{
+		"$",
+		"$.a",
+		"$[0]",
+		"$.a[1].b",
+		"$.a[300][2]",
+		`$."b c"`,
+		`$."1a"`,
+		`$.a."x\"y"`,
+	}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			location, err := jsonPathElementsFromMySQLJsonPath([]byte(path))
+			require.NoError(t, err)
+			assert.Equal(t, path, JsonKeyToPath(location.key))
+		})
+	}
+}
+
+func TestSharedJsonArrayKey(t *testing.T) {
+	tests := []struct {
+		left, right string
+		shared      string
+	}{
+		{left: "$.a[0]", right: "$.a[1]", shared: "$.a"},
+		{left: "$.a[0].b", right: "$.a[0].c", shared: "$.a"},
+		{left: "$.a[0][1]", right: "$.a[0][2]", shared: "$.a"},
+		{left: "$[0]", right: "$[3].x", shared: "$"},
+		{left: "$.a.b", right: "$.a.c"},
+		{left: "$.a[0]", right: "$.b[0]"},
+		{left: "$.a", right: "$.a[0]"},
+	}
+	for _, test := range tests {
+		t.Run(test.left+" "+test.right, func(t *testing.T) {
+			left, err := jsonPathElementsFromMySQLJsonPath([]byte(test.left))
+			require.NoError(t, err)
+			right, err := jsonPathElementsFromMySQLJsonPath([]byte(test.right))
+			require.NoError(t, err)
+
+			shared, ok := SharedJsonArrayKey(left.key, right.key)
+			if test.shared == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, test.shared, JsonKeyToPath(shared))
+		})
+	}
+}
@@ -80,10 +80,38 @@ func NewNodeStore(cs chunks.ChunkStore) NodeStore {
 	}
 }
 
+// ReadTracker is told the size of every Node read with a context returned by WithReadTracker, including those read
+// from the cache. It lets callers account for the data read by an operation, such as a query.
+type ReadTracker interface {
+	// NodesRead is called with the bytes of the Nodes that were read.
+	NodesRead(bytes uint64)
+}
+
+type readTrackerKey struct{}
+
+// WithReadTracker returns a context whose Node reads are reported to |rt|.
+func WithReadTracker(ctx context.Context, rt ReadTracker) context.Context {
+	return context.WithValue(ctx, readTrackerKey{}, rt)
+}
+
+// trackReads reports the bytes of |nodes| to the ReadTracker of |ctx|, if it has one.
+func trackReads(ctx context.Context, nodes ...Node) {
+	rt, ok := ctx.Value(readTrackerKey{}).(ReadTracker)
+	if !ok {
+		return
+	}
+	var bytes uint64
+	for _, n := range nodes {
+		bytes += uint64(n.Size())
+	}
+	rt.NodesRead(bytes)
+}
+
 // Read implements NodeStore.
 func (ns nodeStore) Read(ctx context.Context, ref hash.Hash) (Node, error) {
 	n, ok := ns.cache.get(ref)
 	if ok {
+		trackReads(ctx, n)
 		return n, nil
 	}
 
@@ -98,6 +126,7 @@ func (ns nodeStore) Read(ctx context.Context, ref hash.Hash) (Node, error) {
 		return Node{}, err
 	}
 	ns.cache.insert(ref, n)
+	trackReads(ctx, n)
 
 	return n, nil
 }
@@ -142,6 +171,7 @@ func (ns nodeStore) ReadMany(ctx context.Context, addrs hash.HashSlice) ([]Node,
 			ns.cache.insert(addr, nodes[i])
 		}
 	}
+	trackReads(ctx, nodes...)
 	return nodes, nil
 }
 
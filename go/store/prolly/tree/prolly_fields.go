@@ -282,7 +282,16 @@ func PutField(ctx context.Context, ns NodeStore, tb *val.TupleBuilder, i int, v
 				return err
 			}
 		}
-		tb.PutCell(i, ZCell(v.(types.GeometryValue)))
+		if g, ok := v.(types.GeometryValue); ok {
+			tb.PutCell(i, ZCell(g))
+			break
+		}
+		// vector indexes are the only other indexes keyed by Cells
+		vec, err := sql.ConvertToVector(v)
+		if err != nil {
+			return err
+		}
+		tb.PutCell(i, VectorCell(vec))
 	case val.ExtendedEnc:
 		b, err := tb.Desc.Handlers[i].SerializeValue(v)
 		if err != nil {
@@ -0,0 +1,85 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"sync"
+
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// VectorCellBits is the number of bits of a vector Cell.
+const VectorCellBits = 8 * len(val.Cell{})
+
+const vectorHyperplaneWords = (VectorCellBits + 63) / 64
+
+// vectorHyperplanes caches the hyperplanes of each number of dimensions, keyed by the number of dimensions.
+var vectorHyperplanes sync.Map
+
+// VectorCell converts the vector |vec| into the Cell vector indexes are keyed by. Each bit of the Cell records which
+// side of a hyperplane through the origin |vec| lies on (random hyperplane hashing), so the more bits the Cells of two
+// vectors share, the smaller the angle between them. Vectors that are close together therefore tend to share a
+// prefix of their Cell, and are stored close together in the index.
+//
+// The hyperplanes are generated deterministically from the number of dimensions of |vec|, and their components are
+// all either 1 or -1. The side of a hyperplane is computed with additions alone, in a fixed order, so the Cell of a
+// vector is the same on every platform.
+func VectorCell(vec []float64) (cell val.Cell) {
+	planes := vectorHyperplanesFor(len(vec))
+	var sums [VectorCellBits]float64
+	for d, x := range vec {
+		signs := planes[d]
+		for i := range sums {
+			if signs[i/64]&(1<<(i%64)) != 0 {
+				sums[i] += x
+			} else {
+				sums[i] -= x
+			}
+		}
+	}
+	for i, sum := range sums {
+		if sum > 0 {
+			cell[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return cell
+}
+
+// vectorHyperplanesFor returns the hyperplanes vectors of |dims| dimensions are hashed against. Element d holds the
+// signs of dimension d of every hyperplane, as a bit set.
+func vectorHyperplanesFor(dims int) [][vectorHyperplaneWords]uint64 {
+	if planes, ok := vectorHyperplanes.Load(dims); ok {
+		return planes.([][vectorHyperplaneWords]uint64)
+	}
+	planes := make([][vectorHyperplaneWords]uint64, dims)
+	state := uint64(dims)
+	for d := range planes {
+		for w := range planes[d] {
+			state, planes[d][w] = splitMix64(state)
+		}
+	}
+	vectorHyperplanes.Store(dims, planes)
+	return planes
+}
+
+// splitMix64 returns the next state and output of the SplitMix64 generator. It is used rather than math/rand so that
+// the hyperplanes, and so the persisted Cells, are defined by this file alone.
+func splitMix64(state uint64) (uint64, uint64) {
+	state += 0x9e3779b97f4a7c15
+	z := state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return state, z ^ (z >> 31)
+}
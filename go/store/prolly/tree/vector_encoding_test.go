@@ -0,0 +1,52 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"math/bits"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+func TestVectorCell(t *testing.T) {
+	t.Run("cells are persisted, so they must not change", func(t *testing.T) {
+		assert.Equal(t, val.Cell{0x15, 0xb3, 0x55, 0xd3, 0x42, 0x6, 0x11, 0x40, 0x68, 0x9, 0xa1, 0x1b, 0x84, 0xb0, 0x40, 0xc5, 0x40}, VectorCell([]float64{1, 2, 3}))
+		assert.Equal(t, val.Cell{}, VectorCell([]float64{0, 0, 0}))
+		assert.Equal(t, val.Cell{}, VectorCell(nil))
+	})
+
+	t.Run("cells only depend on direction", func(t *testing.T) {
+		assert.Equal(t, VectorCell([]float64{1, 2, 3}), VectorCell([]float64{0.5, 1, 1.5}))
+	})
+
+	t.Run("closer vectors share more bits", func(t *testing.T) {
+		v := VectorCell([]float64{1, 2, 3, 4})
+		near := VectorCell([]float64{1.1, 2, 2.9, 4})
+		far := VectorCell([]float64{-4, 1, -2, 3})
+		opposite := VectorCell([]float64{-1, -2, -3, -4})
+		assert.Less(t, cellDistance(v, near), cellDistance(v, far))
+		assert.Less(t, cellDistance(v, far), cellDistance(v, opposite))
+	})
+}
+
+func cellDistance(l, r val.Cell) (d int) {
+	for i := range l {
+		d += bits.OnesCount8(l[i] ^ r[i])
+	}
+	return d
+}
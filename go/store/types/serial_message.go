@@ -586,6 +586,15 @@ func (sm SerialMessage) WalkAddrs(nbf *NomsBinFormat, cb func(addr hash.Hash) er
 			if err = cb(hash.New(mergeState.FromCommitAddrBytes())); err != nil {
 				return err
 			}
+			if err = walkConcatenatedAddrs(mergeState.RevertedCommitAddrsBytes(), cb); err != nil {
+				return err
+			}
+			if err = walkConcatenatedAddrs(mergeState.PendingRevertCommitAddrsBytes(), cb); err != nil {
+				return err
+			}
+			if err = walkConcatenatedAddrs(mergeState.PendingCherryPickCommitAddrsBytes(), cb); err != nil {
+				return err
+			}
 		}
 	case serial.RootValueFileID:
 		var msg serial.RootValue
@@ -711,6 +720,17 @@ func (sm SerialMessage) WalkAddrs(nbf *NomsBinFormat, cb func(addr hash.Hash) er
 	return nil
 }
 
+// walkConcatenatedAddrs calls |cb| with each of the 20-byte addresses concatenated in |addrs|.
+func walkConcatenatedAddrs(addrs []byte, cb func(addr hash.Hash) error) error {
+	for len(addrs) >= hash.ByteLen {
+		if err := cb(hash.New(addrs[:hash.ByteLen])); err != nil {
+			return err
+		}
+		addrs = addrs[hash.ByteLen:]
+	}
+	return nil
+}
+
 func SerialCommitParentAddrs(nbf *NomsBinFormat, sm SerialMessage) ([]hash.Hash, error) {
 	var msg serial.Commit
 	err := serial.InitCommitRoot(&msg, []byte(sm), serial.MessagePrefixSz)
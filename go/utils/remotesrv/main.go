@@ -16,18 +16,23 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 
+	"github.com/sirupsen/logrus"
+
 	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotesrv"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/libraries/utils/jwtauth"
 	"github.com/dolthub/dolt/go/store/datas"
 )
 
@@ -40,6 +45,15 @@ func main() {
 	grpcPortParam := flag.Int("grpc-port", -1, "the port the grpc server will listen on; default 50051")
 	httpPortParam := flag.Int("http-port", -1, "the port the http server will listen on; default 80; if http-port is equal to grpc-port, both services will serve over the same port")
 	httpHostParam := flag.String("http-host", "", "hostname to use in the host component of the URLs that the server generates; default ''; if '', server will echo the :authority header")
+	tlsCertParam := flag.String("tls-cert", "", "path to a PEM encoded certificate; if set along with tls-key, the server will serve TLS")
+	tlsKeyParam := flag.String("tls-key", "", "path to the PEM encoded private key for tls-cert")
+	tlsClientCAParam := flag.String("tls-client-ca", "", "path to a PEM encoded CA bundle used to verify client certificates; requires tls-cert and client-cert-grants")
+	clientCertGrantsParam := flag.String("client-cert-grants", "", "path to a file mapping client certificate common names to repository scopes, one '<name> <scope>...' entry per line, where a scope is read:<repo> or write:<repo>")
+	jwksURLParam := flag.String("jwks-url", "", "URL of a JWKS used to validate bearer tokens; the token's scope claim lists the repositories it grants, e.g. 'read:* write:ci/nightly'")
+	jwtIssuerParam := flag.String("jwt-issuer", "", "required issuer of bearer tokens; requires jwks-url")
+	jwtAudienceParam := flag.String("jwt-audience", "", "required audience of bearer tokens; requires jwks-url")
+	branchProtectionParam := flag.String("branch-protection", "", "path to a YAML file of protected branch rules enforced on push")
+	preReceiveParam := flag.String("pre-receive", "", "path to an executable run before each push is accepted; it receives the push as JSON on stdin and rejects it by exiting non-zero")
 	flag.Parse()
 
 	if dirParam != nil && len(*dirParam) > 0 {
@@ -85,7 +99,7 @@ func main() {
 		dbCache = NewLocalCSCache(fs)
 	}
 
-	server, err := remotesrv.NewServer(remotesrv.ServerArgs{
+	args := remotesrv.ServerArgs{
 		HttpHost:           *httpHostParam,
 		HttpListenAddr:     fmt.Sprintf(":%d", *httpPortParam),
 		GrpcListenAddr:     fmt.Sprintf(":%d", *grpcPortParam),
@@ -93,7 +107,58 @@ func main() {
 		DBCache:            dbCache,
 		ReadOnly:           *readOnlyParam,
 		ConcurrencyControl: remotesapi.PushConcurrencyControl_PUSH_CONCURRENCY_CONTROL_IGNORE_WORKING_SET,
-	})
+	}
+
+	if *tlsCertParam != "" || *tlsKeyParam != "" {
+		args.TLSConfig, err = loadTLSConfig(*tlsCertParam, *tlsKeyParam, *tlsClientCAParam)
+		if err != nil {
+			log.Fatalf("error loading tls config: %v\n", err)
+		}
+	} else if *tlsClientCAParam != "" {
+		log.Fatalln("'tls-client-ca' requires 'tls-cert' and 'tls-key'")
+	}
+
+	if *jwksURLParam != "" || *clientCertGrantsParam != "" {
+		var ac remotesrv.ScopedAccessControl
+		if *jwksURLParam != "" {
+			ac.Validator, err = jwtauth.NewJWTValidator(jwtauth.JWTProvider{
+				URL:      *jwksURLParam,
+				Issuer:   *jwtIssuerParam,
+				Audience: *jwtAudienceParam,
+			})
+			if err != nil {
+				log.Fatalf("error creating jwt validator: %v\n", err)
+			}
+		}
+		if *clientCertGrantsParam != "" {
+			if *tlsClientCAParam == "" {
+				log.Fatalln("'client-cert-grants' requires 'tls-client-ca'")
+			}
+			ac.CertGrants, err = loadClientCertGrants(*clientCertGrantsParam)
+			if err != nil {
+				log.Fatalf("error loading client certificate grants: %v\n", err)
+			}
+		}
+		si := remotesrv.ServerInterceptor{
+			Lgr:              logrus.NewEntry(logrus.StandardLogger()),
+			AccessController: ac,
+		}
+		args.Options = append(args.Options, si.Options()...)
+	} else if *jwtIssuerParam != "" || *jwtAudienceParam != "" {
+		log.Fatalln("'jwt-issuer' and 'jwt-audience' require 'jwks-url'")
+	}
+
+	if *branchProtectionParam != "" {
+		args.BranchProtection, err = loadBranchProtection(*branchProtectionParam)
+		if err != nil {
+			log.Fatalf("error loading branch protection rules: %v\n", err)
+		}
+	}
+	if *preReceiveParam != "" {
+		args.PreReceiveHooks = []remotesrv.PreReceiveHook{remotesrv.ExecPreReceiveHook{Path: *preReceiveParam}}
+	}
+
+	server, err := remotesrv.NewServer(args)
 	if err != nil {
 		log.Fatalf("error creating remotesrv Server: %v\n", err)
 	}
@@ -108,6 +173,50 @@ func main() {
 	server.GracefulStop()
 }
 
+// loadTLSConfig loads the server certificate at |certPath| and |keyPath|. If |clientCAPath| is set, clients which
+// present a certificate must have it signed by one of its CAs. Clients are not required to present one, since they
+// may instead authenticate with a bearer token.
+func loadTLSConfig(certPath, keyPath, clientCAPath string) (*tls.Config, error) {
+	c, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{c},
+	}
+	if clientCAPath != "" {
+		pem, err := os.ReadFile(clientCAPath)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", clientCAPath)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return cfg, nil
+}
+
+func loadClientCertGrants(path string) (remotesrv.ClientCertGrants, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return remotesrv.ReadClientCertGrants(f)
+}
+
+func loadBranchProtection(path string) (remotesrv.BranchProtection, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return remotesrv.ReadBranchProtection(f)
+}
+
 func waitForSignal() {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, os.Kill)